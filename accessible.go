@@ -0,0 +1,159 @@
+package notelink
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// iconTag matches a Font Awesome icon, with its title when it has one
+	iconTag = regexp.MustCompile(`<i class="(fas? [^"]*)"( title="([^"]*)")?></i>`)
+	// labelableTag matches the opening tag of a form control or button
+	labelableTag = regexp.MustCompile(`<(input|textarea|button)\b[^>]*>`)
+	// placeholderOrTitle matches the placeholder or title a control can be labeled with
+	placeholderOrTitle = regexp.MustCompile(`\s(?:placeholder|title)="([^"]*)"`)
+	// bodyTag matches the opening body tag
+	bodyTag = regexp.MustCompile(`<body[^>]*>`)
+)
+
+// iconLabels name the meaningful icons without a title, by class
+var iconLabels = map[string]string{
+	"lock-icon": "Authentication required",
+}
+
+// accessibleHTML renders the docs page in the accessible mode of
+// Config.Accessible: decorative icons are hidden from screen readers and
+// meaningful ones labeled, controls without a label are labeled with their
+// placeholder or title, try-it results are announced and focused, the
+// summaries report their expanded state, a skip link leads to the endpoints,
+// and the high-contrast, focus-visible and reduced-motion styles apply.
+func accessibleHTML(page string) string {
+	page = iconTag.ReplaceAllStringFunc(page, func(tag string) string {
+		match := iconTag.FindStringSubmatch(tag)
+		label := match[3]
+		if label == "" {
+			for class, name := range iconLabels {
+				if strings.Contains(" "+match[1]+" ", " "+class+" ") {
+					label = name
+				}
+			}
+		}
+		if label == "" {
+			return `<i class="` + match[1] + `" aria-hidden="true"></i>`
+		}
+		return `<i class="` + match[1] + `"` + match[2] + ` role="img" aria-label="` + label + `"></i>`
+	})
+	page = labelableTag.ReplaceAllStringFunc(page, func(tag string) string {
+		if strings.Contains(tag, "aria-label") || strings.Contains(tag, `type="hidden"`) {
+			return tag
+		}
+		label := placeholderOrTitle.FindStringSubmatch(tag)
+		if label == nil || label[1] == "" {
+			return tag
+		}
+		name := labelableTag.FindStringSubmatch(tag)[1]
+		return "<" + name + ` aria-label="` + label[1] + `"` + tag[len(name)+1:]
+	})
+	page = strings.ReplaceAll(page, `<pre id="test-result-`, `<pre role="status" aria-live="polite" tabindex="-1" id="test-result-`)
+	page = strings.Replace(page, `<main class="docs-main">`, `<main class="docs-main" id="main-content" tabindex="-1">`, 1)
+	if location := bodyTag.FindStringIndex(page); location != nil {
+		page = page[:location[1]] + `
+    <a class="skip-link" href="#main-content">Skip to the API endpoints</a>` + page[location[1]:]
+	}
+	page = strings.Replace(page, "</head>", accessibleStyles+"</head>", 1)
+	if index := strings.LastIndex(page, "</body>"); index >= 0 {
+		page = page[:index] + accessibleScript + page[index:]
+	}
+	return page
+}
+
+// accessibleStyles raise the contrast to WCAG AA, show where the focus is
+// and turn animations off
+const accessibleStyles = `    <style>
+        :root {
+            --gray-400: #4b5563;
+            --gray-500: #374151;
+            --gray-600: #1f2937;
+            --gray-700: #111827;
+        }
+
+        .skip-link {
+            position: absolute;
+            left: 1rem;
+            top: -3rem;
+            z-index: 1000;
+            padding: 0.5rem 1rem;
+            background: #111827;
+            color: #ffffff;
+            border-radius: var(--radius);
+        }
+
+        .skip-link:focus {
+            top: 1rem;
+        }
+
+        a:focus-visible, button:focus-visible, summary:focus-visible, input:focus-visible,
+        select:focus-visible, textarea:focus-visible, pre:focus-visible, [tabindex]:focus-visible {
+            outline: 3px solid #1d4ed8;
+            outline-offset: 2px;
+        }
+
+        .endpoint-description {
+            opacity: 1;
+        }
+
+        *, *::before, *::after {
+            animation: none !important;
+            transition: none !important;
+            scroll-behavior: auto !important;
+        }
+
+        @media (prefers-contrast: more) {
+            :root {
+                --gray-200: #6b7280;
+            }
+
+            a {
+                text-decoration: underline;
+            }
+        }
+    </style>
+`
+
+// accessibleScript keeps aria-expanded in step with the summaries and moves
+// the focus to a try-it result once it arrives
+const accessibleScript = `    <script>
+        document.addEventListener('DOMContentLoaded', function() {
+            document.querySelectorAll('details').forEach(function(details, index) {
+                const summary = details.querySelector(':scope > summary');
+                const content = details.querySelector(':scope > :not(summary)');
+                if (!summary) {
+                    return;
+                }
+                if (content) {
+                    content.id = content.id || 'details-content-' + index;
+                    summary.setAttribute('aria-controls', content.id);
+                }
+                summary.setAttribute('aria-expanded', details.open ? 'true' : 'false');
+                details.addEventListener('toggle', function() {
+                    summary.setAttribute('aria-expanded', details.open ? 'true' : 'false');
+                });
+            });
+
+            document.querySelectorAll('pre[id^="test-result-"]').forEach(function(result) {
+                new MutationObserver(function() {
+                    if (result.dataset.focusPending) {
+                        delete result.dataset.focusPending;
+                        result.focus();
+                    }
+                }).observe(result, {childList: true, characterData: true, subtree: true});
+            });
+            document.addEventListener('submit', function(e) {
+                const result = e.target.querySelector('pre[id^="test-result-"]');
+                if (result) {
+                    result.dataset.focusPending = 'true';
+                }
+            }, true);
+        });
+    </script>
+`
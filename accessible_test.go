@@ -0,0 +1,37 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestAccessibleDocs tests the accessible rendering mode of the docs page
+func TestAccessibleDocs(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return nil }
+	authRequired := true
+	snippets := []string{
+		`<a class="skip-link" href="#main-content">`,
+		`<main class="docs-main" id="main-content" tabindex="-1">`,
+		`<pre role="status" aria-live="polite" tabindex="-1" id="test-result-GET-users">`,
+		`<i class="fas fa-lock lock-icon" role="img" aria-label="Authentication required"></i>`,
+		`<i class="fas fa-print" aria-hidden="true"></i>`,
+		`<input aria-label="Enter JWT Bearer Token (e.g., Bearer eyJ...)" type="text" id="auth-token"`,
+		"summary.setAttribute('aria-expanded'",
+		"scroll-behavior: auto !important;",
+	}
+
+	for _, accessible := range []bool{false, true} {
+		api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", Accessible: accessible}, "secret")
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/users", Description: "List users", Handler: handler, AuthRequired: &authRequired}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+		html := api.generateHTML()
+		for _, snippet := range snippets {
+			if strings.Contains(html, snippet) != accessible {
+				t.Errorf("Expected %q in the docs only in accessible mode (accessible %v)", snippet, accessible)
+			}
+		}
+	}
+}
@@ -0,0 +1,480 @@
+package notelink
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// APIKeyAdminScope grants access to the API key admin endpoints
+const APIKeyAdminScope = "api-keys:admin"
+
+// DefaultAPIKeyAdminPath is where the API key admin endpoints are mounted,
+// relative to Config.BasePath
+const DefaultAPIKeyAdminPath = "/api-keys"
+
+// apiKeyPrefix starts every issued key, so leaked keys are easy to scan for
+const apiKeyPrefix = "nlk_"
+
+var (
+	// ErrAPIKeyNotFound is returned by APIKeyStore for unknown keys
+	ErrAPIKeyNotFound = errors.New("notelink: API key not found")
+	// ErrAPIKeyRevoked is returned by APIKeyManager.Verify for revoked keys
+	ErrAPIKeyRevoked = errors.New("notelink: API key revoked")
+	// ErrAPIKeyExpired is returned by APIKeyManager.Verify for expired keys
+	ErrAPIKeyExpired = errors.New("notelink: API key expired")
+	// ErrInvalidAPIKeyRequest is returned by APIKeyManager.Issue for invalid
+	// key requests, wrapped with the reason
+	ErrInvalidAPIKeyRequest = errors.New("notelink: invalid API key request")
+)
+
+// APIKey is a client's API key as stored: only a hash of the key is kept, so
+// it can't be recovered after being issued
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`   // The client, e.g. "billing-service"
+	Prefix    string     `json:"prefix"` // First characters of the key, to recognize it
+	Hash      string     `json:"-"`      // Hex SHA-256 of the key
+	Scopes    []string   `json:"scopes,omitempty"`
+	RateLimit int        `json:"rateLimit,omitempty"` // Requests per minute; 0 is unlimited
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyRequest issues an API key
+type APIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	RateLimit int        `json:"rateLimit,omitempty"` // Requests per minute; 0 is unlimited
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// IssuedAPIKey is a newly issued key, the only time the key itself is returned
+type IssuedAPIKey struct {
+	Key    string `json:"key"`
+	APIKey APIKey `json:"apiKey"`
+}
+
+// APIKeyStore persists API keys, e.g. in a database shared by several
+// instances. Config.APIKeys.Store defaults to an in-memory store.
+type APIKeyStore interface {
+	Save(key *APIKey) error
+	Get(id string) (*APIKey, error)         // ErrAPIKeyNotFound for unknown ids
+	GetByHash(hash string) (*APIKey, error) // ErrAPIKeyNotFound for unknown hashes
+	List() ([]*APIKey, error)
+}
+
+// MemoryAPIKeyStore keeps API keys in memory; keys are lost on restart
+type MemoryAPIKeyStore struct {
+	mu     sync.RWMutex
+	keys   map[string]APIKey
+	hashes map[string]string // Hash to id
+}
+
+// NewMemoryAPIKeyStore creates an empty in-memory API key store
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{keys: make(map[string]APIKey), hashes: make(map[string]string)}
+}
+
+// Save stores a copy of the key
+func (s *MemoryAPIKeyStore) Save(key *APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *key
+	stored.Scopes = append([]string(nil), key.Scopes...)
+	s.keys[key.ID] = stored
+	s.hashes[key.Hash] = key.ID
+	return nil
+}
+
+// Get returns a copy of the key
+func (s *MemoryAPIKeyStore) Get(id string) (*APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+// GetByHash returns a copy of the key with the hash
+func (s *MemoryAPIKeyStore) GetByHash(hash string) (*APIKey, error) {
+	s.mu.RLock()
+	id, ok := s.hashes[hash]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	return s.Get(id)
+}
+
+// List returns copies of the keys ordered by creation
+func (s *MemoryAPIKeyStore) List() ([]*APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]*APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		key := key
+		keys = append(keys, &key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+// stats reports the keys held by the store
+func (s *MemoryAPIKeyStore) stats() RegistryStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return RegistryStats{Name: "api_keys", Entries: len(s.keys)}
+}
+
+// APIKeyConfig enables per-client API keys (see ApiNote.APIKeys): routes with
+// APIKeyScopes, and routes registered after ApiNote.UseAPIKeys, require a key
+// in the Config.APIKeyHeader header. Admin endpoints issuing, listing and
+// revoking keys are mounted at AdminPath and require APIKeyAdminScope; issue
+// the first admin key in code.
+type APIKeyConfig struct {
+	Store     APIKeyStore // Defaults to a MemoryAPIKeyStore
+	AdminPath string      // Defaults to DefaultAPIKeyAdminPath
+	// DisableAdmin skips mounting the admin endpoints, e.g. when keys are
+	// managed by another service sharing the Store
+	DisableAdmin bool
+}
+
+// APIKeyManager issues, revokes and verifies API keys, enforcing their
+// scopes and per-minute rate limits
+type APIKeyManager struct {
+	store  APIKeyStore
+	header string
+
+	mu      sync.Mutex
+	buckets map[string]*rateBucket // Per key id
+}
+
+// rateBucket is a token bucket refilled at a rate limit per minute
+type rateBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills the bucket up to limit and takes a token, returning the
+// remaining tokens or, when empty, the wait until the next one
+func (b *rateBucket) take(limit int, now time.Time) (remaining int, wait time.Duration, ok bool) {
+	perSecond := float64(limit) / 60
+	b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+	if b.tokens < 1 {
+		return 0, time.Duration((1 - b.tokens) / perSecond * float64(time.Second)), false
+	}
+	b.tokens--
+	return int(b.tokens), 0, true
+}
+
+// NewAPIKeyManager creates a manager of the keys in store, read from the
+// header of requests. A nil store keeps keys in memory and an empty header
+// defaults to DefaultAPIKeyHeader.
+func NewAPIKeyManager(store APIKeyStore, header string) *APIKeyManager {
+	if store == nil {
+		store = NewMemoryAPIKeyStore()
+	}
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	return &APIKeyManager{store: store, header: header, buckets: make(map[string]*rateBucket)}
+}
+
+// Issue creates a key, returning it with the only copy of its secret
+func (m *APIKeyManager) Issue(req APIKeyRequest) (*IssuedAPIKey, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidAPIKeyRequest)
+	}
+	if req.RateLimit < 0 {
+		return nil, fmt.Errorf("%w: rateLimit must not be negative", ErrInvalidAPIKeyRequest)
+	}
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	raw := apiKeyPrefix + hex.EncodeToString(secret)
+	key := APIKey{
+		ID:        newRandomID(),
+		Name:      req.Name,
+		Prefix:    raw[:len(apiKeyPrefix)+6],
+		Hash:      hashAPIKey(raw),
+		Scopes:    append([]string(nil), req.Scopes...),
+		RateLimit: req.RateLimit,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := m.store.Save(&key); err != nil {
+		return nil, fmt.Errorf("failed to save API key: %w", err)
+	}
+	return &IssuedAPIKey{Key: raw, APIKey: key}, nil
+}
+
+// Revoke revokes a key by id; revoking a revoked key keeps its revocation time
+func (m *APIKeyManager) Revoke(id string) (*APIKey, error) {
+	key, err := m.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt == nil {
+		now := time.Now().UTC()
+		key.RevokedAt = &now
+		if err := m.store.Save(key); err != nil {
+			return nil, fmt.Errorf("failed to save API key: %w", err)
+		}
+	}
+	m.mu.Lock()
+	delete(m.buckets, id)
+	m.mu.Unlock()
+	return key, nil
+}
+
+// List returns the keys, including revoked and expired ones
+func (m *APIKeyManager) List() ([]*APIKey, error) {
+	return m.store.List()
+}
+
+// Verify returns the key of a raw API key: ErrAPIKeyNotFound for unknown
+// keys, ErrAPIKeyRevoked and ErrAPIKeyExpired for keys no longer valid
+func (m *APIKeyManager) Verify(raw string) (*APIKey, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, ErrAPIKeyNotFound
+	}
+	key, err := m.store.GetByHash(hashAPIKey(raw))
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+	if key.ExpiresAt != nil && !time.Now().Before(*key.ExpiresAt) {
+		return nil, ErrAPIKeyExpired
+	}
+	return key, nil
+}
+
+// allow takes a token from the key's bucket, returning the remaining tokens
+// or, when empty, the wait until the next one
+func (m *APIKeyManager) allow(key *APIKey, now time.Time) (remaining int, wait time.Duration, ok bool) {
+	if key.RateLimit <= 0 {
+		return 0, 0, true
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	bucket, exists := m.buckets[key.ID]
+	if !exists {
+		bucket = &rateBucket{tokens: float64(key.RateLimit), updated: now}
+		m.buckets[key.ID] = bucket
+	}
+	return bucket.take(key.RateLimit, now)
+}
+
+// Middleware returns a Fiber middleware requiring a valid API key granting
+// every scope. It responds 401 for missing or invalid keys, 429 with
+// Retry-After once the key's rate limit is exceeded and 403 for missing
+// scopes, which don't count against the rate limit, and stores the key in
+// c.Locals("api_key"). Rejections are returned as *fiber.Error, rendered by
+// Config.ErrorHandler.
+//
+// It is installed automatically on routes with APIKeyScopes and after
+// ApiNote.UseAPIKeys; use it directly for routes outside the docs.
+func (m *APIKeyManager) Middleware(scopes ...string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		raw := c.Get(m.header)
+		if raw == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, m.header+" header required")
+		}
+		key, err := m.Verify(raw)
+		switch {
+		case errors.Is(err, ErrAPIKeyNotFound), errors.Is(err, ErrAPIKeyRevoked), errors.Is(err, ErrAPIKeyExpired):
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid, revoked or expired API key")
+		case err != nil:
+			return err
+		}
+		// Only authorized calls count against the key's rate limit
+		for _, scope := range scopes {
+			if !key.HasScope(scope) {
+				return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("API key lacks the %q scope", scope))
+			}
+		}
+		remaining, wait, ok := m.allow(key, time.Now())
+		if key.RateLimit > 0 {
+			c.Set("X-RateLimit-Limit", strconv.Itoa(key.RateLimit))
+			c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		}
+		if !ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(1, int(math.Ceil(wait.Seconds())))))
+			return fiber.NewError(fiber.StatusTooManyRequests, "API key rate limit exceeded")
+		}
+		c.Locals("api_key", key)
+		return c.Next()
+	}
+}
+
+// hashAPIKey returns the hex SHA-256 of a raw key. Keys are random, so a fast
+// hash is enough to keep them unrecoverable from the store.
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeys returns the API key manager of Config.APIKeys, or nil, e.g. to
+// issue the first admin key:
+//
+//	issued, err := api.APIKeys().Issue(notelink.APIKeyRequest{
+//	    Name:   "ops",
+//	    Scopes: []string{notelink.APIKeyAdminScope},
+//	})
+func (an *ApiNote) APIKeys() *APIKeyManager {
+	return an.apiKeys
+}
+
+// UseAPIKeys requires an API key on all subsequent routes, like UseJWT. Routes
+// add required scopes with APIKeyScopes. Config.APIKeys must be set.
+func (an *ApiNote) UseAPIKeys() {
+	an.apiKeysRequired = true
+}
+
+// apiKeyAdminPath returns Config.APIKeys.AdminPath, or its default
+func (an *ApiNote) apiKeyAdminPath() string {
+	if an.config.APIKeys.AdminPath == "" {
+		return DefaultAPIKeyAdminPath
+	}
+	return an.config.APIKeys.AdminPath
+}
+
+// documentAPIKeyResponses documents the rejections of an API key protected route
+func documentAPIKeyResponses(endpoint *Endpoint) {
+	if endpoint.Responses == nil {
+		endpoint.Responses = make(map[string]string)
+	}
+	defaults := map[string]string{"401": "Missing, invalid, revoked or expired API key", "429": "API key rate limit exceeded; retry after the Retry-After delay"}
+	if len(endpoint.APIKeyScopes) > 0 {
+		defaults["403"] = "API key lacks a required scope: " + strings.Join(endpoint.APIKeyScopes, ", ")
+	}
+	for status, description := range defaults {
+		if _, ok := endpoint.Responses[status]; !ok {
+			endpoint.Responses[status] = description
+		}
+	}
+}
+
+// apiKeySecurityScheme returns the OpenAPI security scheme of API keys
+func (an *ApiNote) apiKeySecurityScheme() SecurityScheme {
+	return SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        an.apiKeyHeader(),
+		Description: "Per-client API key issued by the API key admin endpoints",
+	}
+}
+
+// mountAPIKeyAdmin registers the documented admin endpoints
+func (an *ApiNote) mountAPIKeyAdmin() {
+	path := an.apiKeyAdminPath()
+	routes := []DocumentedRouteInput{
+		{
+			Method:          http.MethodPost,
+			Path:            path,
+			Description:     "Issue an API key. The key is only returned in this response; store it securely.",
+			Responses:       map[string]string{"201": "API key issued", "400": "Invalid key request"},
+			SchemasRequest:  APIKeyRequest{},
+			SchemasResponse: IssuedAPIKey{},
+			Handler:         an.issueAPIKeyHandler,
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            path,
+			Description:     "List the API keys, including revoked and expired ones",
+			Responses:       map[string]string{"200": "API keys"},
+			SchemasResponse: []APIKey{},
+			Handler:         an.listAPIKeysHandler,
+		},
+		{
+			Method:          http.MethodDelete,
+			Path:            path + "/:id",
+			Description:     "Revoke an API key; requests with it are rejected from then on",
+			Params:          []Parameter{{Name: "id", In: "path", Type: "string", Description: "API key id", Required: true}},
+			Responses:       map[string]string{"200": "API key revoked", "404": "API key not found"},
+			SchemasResponse: APIKey{},
+			Handler:         an.revokeAPIKeyHandler,
+		},
+	}
+	for i := range routes {
+		routes[i].APIKeyScopes = []string{APIKeyAdminScope}
+		routes[i].Tags = []string{"api-keys"}
+		routes[i].builtin = true
+		if err := an.DocumentedRoute(&routes[i]); err != nil {
+			log.Errorf("notelink: registering %s %s: %v", routes[i].Method, routes[i].Path, err)
+		}
+	}
+}
+
+// issueAPIKeyHandler issues a key
+func (an *ApiNote) issueAPIKeyHandler(c fiber.Ctx) error {
+	var req APIKeyRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid key request")
+	}
+	issued, err := an.apiKeys.Issue(req)
+	if errors.Is(err, ErrInvalidAPIKeyRequest) {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid key request: "+strings.TrimPrefix(err.Error(), ErrInvalidAPIKeyRequest.Error()+": "))
+	}
+	// Store errors may describe the store; clients only learn the key wasn't issued
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to issue API key")
+	}
+	return c.Status(fiber.StatusCreated).JSON(issued)
+}
+
+// listAPIKeysHandler lists the keys
+func (an *ApiNote) listAPIKeysHandler(c fiber.Ctx) error {
+	keys, err := an.apiKeys.List()
+	if err != nil {
+		return err
+	}
+	return c.JSON(keys)
+}
+
+// revokeAPIKeyHandler revokes a key
+func (an *ApiNote) revokeAPIKeyHandler(c fiber.Ctx) error {
+	key, err := an.apiKeys.Revoke(c.Params("id"))
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "API key not found")
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(key)
+}
@@ -0,0 +1,312 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestAPIKeys tests issuing, scoping, rate limiting and revoking API keys
+func TestAPIKeys(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", APIKeys: &APIKeyConfig{}}, "secret")
+	api.UseAPIKeys()
+	handler := func(c fiber.Ctx) error { return c.SendString(c.Locals("api_key").(*APIKey).Name) }
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/orders", Description: "List orders", Handler: handler},
+		{Method: "POST", Path: "/orders", Description: "Create an order", APIKeyScopes: []string{"orders:write"}, Handler: handler},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	admin, err := api.APIKeys().Issue(APIKeyRequest{Name: "ops", Scopes: []string{APIKeyAdminScope}})
+	if err != nil {
+		t.Fatalf("Failed to issue admin key: %v", err)
+	}
+	send := func(method, path, key string, body interface{}) (int, []byte, map[string]string) {
+		var payload []byte
+		if body != nil {
+			payload, _ = json.Marshal(body)
+		}
+		req := httptest.NewRequest(method, path, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		defer resp.Body.Close()
+		var buf bytes.Buffer
+		buf.ReadFrom(resp.Body) //nolint:errcheck // test body
+		headers := map[string]string{"Retry-After": resp.Header.Get("Retry-After"), "X-RateLimit-Remaining": resp.Header.Get("X-RateLimit-Remaining")}
+		return resp.StatusCode, buf.Bytes(), headers
+	}
+
+	status, body, _ := send("POST", "/api-keys", admin.Key, APIKeyRequest{Name: "billing", Scopes: []string{"orders:read"}, RateLimit: 2})
+	if status != 201 {
+		t.Fatalf("Expected 201 issuing a key, got %d: %s", status, body)
+	}
+	var issued IssuedAPIKey
+	if err := json.Unmarshal(body, &issued); err != nil || issued.Key == "" || issued.APIKey.Prefix != issued.Key[:10] {
+		t.Fatalf("Expected the issued key, got %s", body)
+	}
+	if bytes.Contains(body, []byte(hashAPIKey(issued.Key))) {
+		t.Error("Expected the key hash kept out of responses")
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		status int
+	}{
+		{"missing key", "GET", "", 401},
+		{"unknown key", "GET", "nlk_0000", 401},
+		{"valid key", "GET", issued.Key, 200},
+		{"missing scope", "POST", issued.Key, 403},
+		// The rejected call didn't spend the key's quota
+		{"valid key within the limit", "GET", issued.Key, 200},
+		{"rate limited", "GET", issued.Key, 429},
+		{"admin key on regular route", "GET", admin.Key, 200},
+	}
+	for _, tt := range tests {
+		status, body, headers := send(tt.method, "/orders", tt.key, nil)
+		if status != tt.status {
+			t.Errorf("%s: expected %d, got %d: %s", tt.name, tt.status, status, body)
+		}
+		if tt.status == 429 && headers["Retry-After"] == "" {
+			t.Errorf("%s: expected a Retry-After header", tt.name)
+		}
+	}
+
+	reader, err := api.APIKeys().Issue(APIKeyRequest{Name: "reader", Scopes: []string{"orders:read"}})
+	if err != nil {
+		t.Fatalf("Failed to issue key: %v", err)
+	}
+	if status, _, _ := send("GET", "/api-keys", reader.Key, nil); status != 403 {
+		t.Errorf("Expected the admin endpoints to require the admin scope, got %d", status)
+	}
+	if status, _, _ := send("DELETE", "/api-keys/"+issued.APIKey.ID, admin.Key, nil); status != 200 {
+		t.Errorf("Expected 200 revoking the key, got %d", status)
+	}
+	if _, err := api.APIKeys().Verify(issued.Key); !errors.Is(err, ErrAPIKeyRevoked) {
+		t.Errorf("Expected the key revoked, got %v", err)
+	}
+	if status, _, _ := send("DELETE", "/api-keys/unknown", admin.Key, nil); status != 404 {
+		t.Errorf("Expected 404 revoking an unknown key, got %d", status)
+	}
+	status, body, _ = send("GET", "/api-keys", admin.Key, nil)
+	var keys []APIKey
+	if err := json.Unmarshal(body, &keys); status != 200 || err != nil || len(keys) != 3 || keys[1].RevokedAt == nil {
+		t.Errorf("Expected both keys listed with the revocation, got %d: %s", status, body)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if scheme := spec.Components.SecuritySchemes["apiKeyAuth"]; scheme.Type != "apiKey" || scheme.Name != "X-API-Key" {
+		t.Errorf("Expected the API key security scheme, got %+v", scheme)
+	}
+	post := spec.Paths["/orders"].Post
+	if scopes := post.Security[0]["apiKeyAuth"]; len(scopes) != 1 || scopes[0] != "orders:write" {
+		t.Errorf("Expected the route's scopes in its security requirement, got %v", post.Security)
+	}
+	for _, status := range []string{"401", "403", "429"} {
+		if _, ok := post.Responses[status]; !ok {
+			t.Errorf("Expected the %s response documented", status)
+		}
+	}
+}
+
+// TestAPIKeyExpiry tests rejecting expired keys
+func TestAPIKeyExpiry(t *testing.T) {
+	manager := NewAPIKeyManager(nil, "")
+	past := time.Now().Add(-time.Minute)
+	issued, err := manager.Issue(APIKeyRequest{Name: "temporary", ExpiresAt: &past})
+	if err != nil {
+		t.Fatalf("Failed to issue key: %v", err)
+	}
+	if _, err := manager.Verify(issued.Key); !errors.Is(err, ErrAPIKeyExpired) {
+		t.Errorf("Expected the key expired, got %v", err)
+	}
+	if _, err := manager.Issue(APIKeyRequest{}); !errors.Is(err, ErrInvalidAPIKeyRequest) {
+		t.Errorf("Expected a name to be required, got %v", err)
+	}
+}
+
+// TestAPIKeysRequireConfig tests that routes can't require keys without Config.APIKeys
+func TestAPIKeysRequireConfig(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:       "GET",
+		Path:         "/orders",
+		APIKeyScopes: []string{"orders:read"},
+		Handler:      func(c fiber.Ctx) error { return nil },
+	})
+	if err == nil {
+		t.Error("Expected an error without Config.APIKeys")
+	}
+}
+
+// TestClientCertBeforeAPIKeys tests rejecting requests without a client
+// certificate before their API key spends rate-limit quota
+func TestClientCertBeforeAPIKeys(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", APIKeys: &APIKeyConfig{}}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:             "GET",
+		Path:               "/partners",
+		ClientCertRequired: true,
+		APIKeyScopes:       []string{"partners:read"},
+		Handler:            func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	issued, err := api.APIKeys().Issue(APIKeyRequest{Name: "partner", Scopes: []string{"partners:read"}, RateLimit: 1})
+	if err != nil {
+		t.Fatalf("Failed to issue key: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/partners", nil)
+		req.Header.Set("X-API-Key", issued.Key)
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != fiber.StatusUnauthorized || resp.Header.Get("X-RateLimit-Remaining") != "" {
+			t.Errorf("Request %d: expected the missing certificate rejected before the API key check, got %d", i, resp.StatusCode)
+		}
+	}
+}
+
+// TestAPIKeyErrorHandler tests rendering API key rejections with Config.ErrorHandler
+func TestAPIKeyErrorHandler(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title:   "Test API",
+		Host:    "localhost:8080",
+		APIKeys: &APIKeyConfig{},
+		ErrorHandler: func(c fiber.Ctx, err error) error {
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				return c.Status(fiberErr.Code).JSON(fiber.Map{"problem": fiberErr.Message})
+			}
+			return c.SendStatus(fiber.StatusInternalServerError)
+		},
+	}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:       "POST",
+		Path:         "/orders",
+		APIKeyScopes: []string{"orders:write"},
+		Handler:      func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	issued, err := api.APIKeys().Issue(APIKeyRequest{Name: "reader", Scopes: []string{"orders:read"}})
+	if err != nil {
+		t.Fatalf("Failed to issue key: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/orders", nil)
+	req.Header.Set("X-API-Key", issued.Key)
+	resp, err := api.Fiber().Test(req)
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || resp.StatusCode != 403 || body["problem"] != `API key lacks the "orders:write" scope` {
+		t.Errorf("Expected the 403 rendered by the ErrorHandler, got %d %v", resp.StatusCode, body)
+	}
+}
+
+// TestClientCertErrorHandler tests rendering missing client certificates with Config.ErrorHandler
+func TestClientCertErrorHandler(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		ErrorHandler: func(c fiber.Ctx, err error) error {
+			return c.Status(fiber.StatusTeapot).SendString("custom: " + err.Error())
+		},
+	}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:             "GET",
+		Path:               "/partners",
+		ClientCertRequired: true,
+		Handler:            func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/partners", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	var body bytes.Buffer
+	body.ReadFrom(resp.Body) //nolint:errcheck // test body
+	if resp.StatusCode != fiber.StatusTeapot || body.String() != "custom: Client certificate required" {
+		t.Errorf("Expected the rejection rendered by the ErrorHandler, got %d %s", resp.StatusCode, body.String())
+	}
+}
+
+// failingAPIKeyStore fails to save keys
+type failingAPIKeyStore struct {
+	*MemoryAPIKeyStore
+}
+
+func (s failingAPIKeyStore) Save(key *APIKey) error {
+	return errors.New("dial tcp 10.0.0.5:5432: connection refused")
+}
+
+// TestIssueAPIKeyErrors tests the statuses and messages of failed key requests
+func TestIssueAPIKeyErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		failing bool
+		request APIKeyRequest
+		status  int
+		message string
+	}{
+		{name: "invalid request", request: APIKeyRequest{Name: "billing", RateLimit: -1}, status: 400, message: "Invalid key request: rateLimit must not be negative"},
+		{name: "store error", failing: true, request: APIKeyRequest{Name: "billing"}, status: 500, message: "Failed to issue API key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memory := NewMemoryAPIKeyStore()
+			admin, err := NewAPIKeyManager(memory, "").Issue(APIKeyRequest{Name: "ops", Scopes: []string{APIKeyAdminScope}})
+			if err != nil {
+				t.Fatalf("Failed to issue admin key: %v", err)
+			}
+			var store APIKeyStore = memory
+			if tt.failing {
+				store = failingAPIKeyStore{memory}
+			}
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", APIKeys: &APIKeyConfig{Store: store}}, "secret")
+
+			payload, _ := json.Marshal(tt.request)
+			req := httptest.NewRequest("POST", "/api-keys", bytes.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-API-Key", admin.Key)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			var body ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || resp.StatusCode != tt.status || body.Error != tt.message {
+				t.Errorf("Expected %d %q, got %d %q", tt.status, tt.message, resp.StatusCode, body.Error)
+			}
+		})
+	}
+}
@@ -24,14 +24,19 @@ package notelink
 
 import (
 	"fmt"
+	"html/template"
+	"net"
 	"net/http"
-	"os"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/goccy/go-json"
-	"github.com/gofiber/contrib/v3/monitor"
 	"github.com/gofiber/fiber/v3"
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/shamaton/msgpack/v2"
 )
 
 // ApiNote is the main structure for API documentation and routing.
@@ -42,9 +47,35 @@ type ApiNote struct {
 	config               *Config
 	app                  *fiber.App
 	jwtSecret            string
-	middlewares          []fiber.Handler
+	middlewares          []namedMiddleware
 	jwtMiddlewares       []fiber.Handler
 	customAuthMiddleware []fiber.Handler
+	componentTypes       map[string]reflect.Type // Types registered per component schema name
+	resources            []resourceType          // Resource structs registered with CRUD
+	channels             []Channel               // Message channels registered with DocumentChannel
+	docPages             []DocPage               // Markdown pages registered with AddDocPage
+	hooks                hooks
+	mockState            MockState       // State shared by mock rules in mock mode
+	jobs                 *MemoryJobStore // Default job store of long-running routes
+	jobsOnce             sync.Once
+	deprecatedFields     deprecatedFieldUsage   // Deprecated request fields received per route
+	deprecatedRoutes     deprecatedRouteUsage   // Callers of deprecated routes
+	validationFailures   validationFailureStats // Validation failures per route, field and client
+	callbackRelay        callbackRelay          // Receive URLs of Config.CallbackRelay
+	deviceAuth           deviceAuthorizations   // Pending sign-ins of Config.DeviceAuth
+	apiKeys              *APIKeyManager         // Set with Config.APIKeys
+	apiKeysRequired      bool                   // Set by UseAPIKeys for subsequent routes
+	sessionsRequired     bool                   // Set by UseSessions for subsequent routes
+	optionsPaths         map[string]bool        // Paths with an OPTIONS responder
+	errorCodes           map[string]ErrorCode   // Error codes registered with RegisterErrorCode
+	assetIntegrity       *assetIntegrity
+	docsTemplates        *template.Template // Slots of Config.DocsTemplates
+	artifacts            artifactCache      // Rendered docs pages and specs
+	ready                atomic.Bool        // Set once WarmUp completes
+	defaultUI            atomic.Value       // Route of the frontend served at the docs path with Config.DefaultUI
+	tryIt                tryItThrottle      // Per-user try-it buckets of Config.TryIt
+	exporters            []Exporter         // Custom artifact generators registered with RegisterExporter
+	unsupportedTypes     []UnsupportedType  // Schema fields let through by Config.AllowUnsupportedTypes
 }
 
 // NewApiNote creates a new ApiNote instance with the provided configuration and JWT secret.
@@ -55,92 +86,97 @@ type ApiNote struct {
 //
 // Returns a pointer to the initialized ApiNote.
 func NewApiNote(config *Config, jwtSecret string) *ApiNote {
-	app := fiber.New(fiber.Config{
-		JSONEncoder: json.Marshal,
-		JSONDecoder: json.Unmarshal,
-	})
+	fiberConfig := fiber.Config{}
+	if config.FiberConfig != nil {
+		fiberConfig = *config.FiberConfig
+	}
+	switch {
+	case config.ErrorHandler != nil:
+		fiberConfig.ErrorHandler = config.ErrorHandler
+	case fiberConfig.ErrorHandler == nil:
+		fiberConfig.ErrorHandler = DefaultErrorHandler
+	}
+	if fiberConfig.JSONEncoder == nil {
+		fiberConfig.JSONEncoder = json.Marshal
+	}
+	switch {
+	case config.JSONDecoder != nil:
+		fiberConfig.JSONDecoder = config.JSONDecoder
+	case fiberConfig.JSONDecoder == nil:
+		fiberConfig.JSONDecoder = json.Unmarshal
+	}
+	if fiberConfig.MsgPackEncoder == nil {
+		fiberConfig.MsgPackEncoder = msgpack.Marshal
+	}
+	if fiberConfig.MsgPackDecoder == nil {
+		fiberConfig.MsgPackDecoder = msgpack.Unmarshal
+	}
+	app := fiber.New(fiberConfig)
 	apiNote := &ApiNote{
 		config:               config,
 		endpoints:            make(map[string]Endpoint),
 		app:                  app,
-		middlewares:          []fiber.Handler{},
+		middlewares:          []namedMiddleware{},
 		jwtMiddlewares:       []fiber.Handler{},
 		customAuthMiddleware: []fiber.Handler{},
+		componentTypes:       make(map[string]reflect.Type),
+		assetIntegrity:       &assetIntegrity{},
 		jwtSecret:            jwtSecret,
 	}
-	app.Get("/api-docs", func(c fiber.Ctx) error {
-		// Default to Scalar if DocsUI is empty or explicitly set to "scalar"
-		if apiNote.config.DocsUI == "" || apiNote.config.DocsUI == "scalar" {
-			return apiNote.ScalarUIHandler()(c)
-		} else if apiNote.config.DocsUI == "swagger" {
-			return apiNote.SwaggerUIHandler()(c)
-		}
-		// Fallback to original HTML handler for any other value
-		return apiNote.Handler()(c)
-	})
-
-	app.Get("/api-docs/metrics", monitor.New(monitor.Config{Title: "Service Metrics Page"}))
-
-	app.Get("/api-docs/indent", func(c fiber.Ctx) error {
-		data, err := json.MarshalIndent(app.GetRoutes(true), "", "  ")
-		if err != nil {
-			return c.Status(http.StatusInternalServerError).SendString("Error marshaling routes")
-		}
-		return c.Status(http.StatusOK).SendString(string(data))
-	})
-
-	// Serve OpenAPI JSON spec at /api-docs/openapi.json
-	app.Get("/api-docs/openapi.json", func(c fiber.Ctx) error {
-		spec := apiNote.GenerateOpenAPISpec()
-		c.Set("Content-Type", "application/json")
-		return c.JSON(spec)
-	})
-
-	// Serve favicon - try multiple possible locations
-	app.Get("/icon.png", func(c fiber.Ctx) error {
-		// Try different possible locations for the icon
-		iconPaths := []string{
-			"./icon.png",     // Current directory
-			"../icon.png",    // Parent directory (for examples folder)
-			"../../icon.png", // Grandparent directory
-		}
-
-		for _, iconPath := range iconPaths {
-			if _, err := os.Stat(iconPath); err == nil {
-				c.Set("Content-Type", "image/png")
-				return c.SendFile(iconPath)
-			}
-		}
-
-		// If no icon found, return 404
-		return c.Status(fiber.StatusNotFound).SendString("Icon not found")
-	})
+	if !config.DisableSecurityHeaders {
+		apiNote.mountSecurityHeaders()
+	}
+	if config.ServerTiming {
+		app.Use(ServerTimingMiddleware())
+	}
 
-	// Also serve favicon.ico for browsers that look for it by default
-	app.Get("/favicon.ico", func(c fiber.Ctx) error {
-		// Try different possible locations for the icon
-		iconPaths := []string{
-			"./icon.png",     // Current directory
-			"../icon.png",    // Parent directory (for examples folder)
-			"../../icon.png", // Grandparent directory
-		}
+	limits := apiNote.limits()
+	apiNote.mockState.setLimits(limits.MaxMockResources, limits.MaxMockBytes)
+	apiNote.artifacts.setLimit(limits.MaxArtifacts)
+	apiNote.warnConfigProblems()
+	apiNote.parseDocsTemplates()
+	if !config.DisableDocsRoutes {
+		apiNote.mountDocsMiddleware()
+		apiNote.mountDocsRoutes()
+	}
 
-		for _, iconPath := range iconPaths {
-			if _, err := os.Stat(iconPath); err == nil {
-				c.Set("Content-Type", "image/x-icon")
-				return c.SendFile(iconPath)
-			}
+	if config.APIKeys != nil {
+		apiNote.apiKeys = NewAPIKeyManager(config.APIKeys.Store, apiNote.apiKeyHeader())
+		if !config.APIKeys.DisableAdmin {
+			apiNote.mountAPIKeyAdmin()
 		}
-
-		// If no icon found, return 404
-		return c.Status(fiber.StatusNotFound).SendString("Icon not found")
-	})
+	}
+	if config.Tokens != nil {
+		apiNote.mountTokenEndpoints()
+	}
+	if config.Sessions != nil {
+		apiNote.mountSessionEndpoints()
+	}
+	if config.DeviceAuth != nil {
+		apiNote.mountDeviceAuth()
+	}
+	if config.Demo != nil {
+		apiNote.mountDemo()
+	}
+	if config.CallbackRelay != nil {
+		apiNote.mountCallbackRelay()
+	}
+	if config.WarmUp {
+		apiNote.mountWarmUp()
+	}
+	if config.AllowUnsupportedTypes {
+		apiNote.mountSchemaReport()
+	}
+	if config.PublishArtifacts != nil || len(config.SpecRegistries) > 0 || config.SpecChangeNotifier != nil || config.SchemaCompatibility != nil {
+		apiNote.mountPublishing()
+	}
 
 	return apiNote
 }
 
 // Use adds one or more middleware handlers to be applied to all subsequent routes.
-// Middleware is executed in the order it is added.
+// Middleware is executed in the order it is added; use UseNamed, UseBefore and
+// UseAfter to position middleware, and WithMiddlewares to scope it to some routes.
 // These middlewares are treated as custom (non-authentication) middleware and will
 // not set the AuthRequired flag in endpoint documentation.
 //
@@ -148,7 +184,9 @@ func NewApiNote(config *Config, jwtSecret string) *ApiNote {
 //
 //	api.Use(RequestLoggerMiddleware()) // Apply custom logging to all following routes
 func (an *ApiNote) Use(middleware ...fiber.Handler) {
-	an.middlewares = append(an.middlewares, middleware...)
+	for _, h := range middleware {
+		an.middlewares = append(an.middlewares, namedMiddleware{handler: h})
+	}
 }
 
 // UseJWT adds JWT authentication middleware to all subsequent routes.
@@ -204,23 +242,87 @@ func (an *ApiNote) UseCustomAuth(middleware ...fiber.Handler) {
 //	    SchemasResponse: UserResponse{},
 //	})
 func (an *ApiNote) DocumentedRoute(input *DocumentedRouteInput) error {
+	return an.documentedRoute(input, nil)
+}
+
+// documentedRoute registers a route, running scoped middleware after the global
+// middleware and right before the handler
+func (an *ApiNote) documentedRoute(input *DocumentedRouteInput, scoped []fiber.Handler) error {
+	route, err := an.prepareRoute(input)
+	if err != nil {
+		return err
+	}
+	return an.mountRoute(route, scoped)
+}
+
+// preparedRoute is a validated route whose endpoint is documented, ready to
+// be mounted
+type preparedRoute struct {
+	input     *DocumentedRouteInput
+	endpoint  Endpoint
+	routePath string
+	ipFilter  fiber.Handler
+}
+
+// prepareRoute validates a route and builds its endpoint. Its only side
+// effects are the schema names and lenient unsupported types it records, so
+// that DocumentedRoutes can roll a table back before mounting any of it.
+func (an *ApiNote) prepareRoute(input *DocumentedRouteInput) (*preparedRoute, error) {
 	// Validate required fields
 	if input.Method == "" || input.Path == "" {
-		return fmt.Errorf("method and path are required")
+		return nil, fmt.Errorf("method and path are required")
 	}
-	if input.Handler == nil {
-		return fmt.Errorf("handler is required")
+	if input.Handler == nil && !an.config.Mock {
+		return nil, fmt.Errorf("handler is required")
+	}
+	switch strings.ToUpper(input.Method) {
+	case "GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "CONNECT", "OPTIONS", "TRACE":
+	default:
+		return nil, fmt.Errorf("unsupported HTTP method: %s", input.Method)
 	}
 
-	key := input.Method + " " + input.Path
+	routePath := an.tenantRoutePath(input)
 	endpoint := Endpoint{
-		Method:      input.Method,
-		Path:        an.config.BasePath + input.Path,
-		Description: input.Description,
-		Responses:   input.Responses,
-		Parameters:  input.Params,
+		Method:             input.Method,
+		Path:               an.config.BasePath + routePath,
+		RoutePath:          routePath,
+		Description:        input.Description,
+		Responses:          copyResponses(input.Responses),
+		Parameters:         mergeParams(append([]Parameter(nil), input.Params...), ParamsFromStruct(input.ParamsSchema)),
+		ClientCertRequired: input.ClientCertRequired,
+		Produces:           input.Produces,
+		Consumes:           input.Consumes,
+		Links:              append([]Link(nil), input.Links...),
+		Tags:               append([]string(nil), input.Tags...),
+		Weight:             input.Weight,
+		Extensions:         copyExtensions(input.Extensions),
+		ValidationRules:    append([]ValidationRule(nil), input.ValidationRules...),
+		ChangeLog:          append([]ChangeLogEntry(nil), input.ChangeLog...),
+		Status:             input.Status,
+		CallbackURLField:   input.CallbackURLField,
+
+		LocalizedDescriptions: copyResponses(input.LocalizedDescriptions),
+		LocalizedResponses:    copyLocalizedResponses(input.LocalizedResponses),
 	}
 
+	if err := validateStatus(input.Status); err != nil {
+		return nil, fmt.Errorf("status of %s %s: %w", input.Method, input.Path, err)
+	}
+	if err := validateChangeLog(input.ChangeLog); err != nil {
+		return nil, fmt.Errorf("change log of %s %s: %w", input.Method, input.Path, err)
+	}
+	if err := validateParameterStyles(endpoint.Parameters); err != nil {
+		return nil, fmt.Errorf("%s %s: %w", input.Method, input.Path, err)
+	}
+	if input.Ownership != nil {
+		ownership := *input.Ownership
+		endpoint.Ownership = &ownership
+	}
+
+	an.resolveErrorCodes(&endpoint)
+	an.addTenantParameter(&endpoint, input)
+	derivePathParameters(&endpoint)
+
 	// Set AuthRequired based on explicit input or JWT middleware presence
 	if input.AuthRequired != nil {
 		endpoint.AuthRequired = *input.AuthRequired
@@ -229,17 +331,227 @@ func (an *ApiNote) DocumentedRoute(input *DocumentedRouteInput) error {
 		endpoint.AuthRequired = len(an.jwtMiddlewares) > 0 || len(an.customAuthMiddleware) > 0
 	}
 
+	if err := an.checkSchemaNames(input.SchemasRequest, input.SchemasResponse); err != nil {
+		return nil, err
+	}
+	if err := an.checkSchemaTypes(input.Method+" "+input.Path, input.SchemasRequest, input.SchemasResponse, input.TransformResponse); err != nil {
+		return nil, err
+	}
 	if input.SchemasRequest != nil {
 		endpoint.RequestSchema = input.SchemasRequest
 	}
 	if input.SchemasResponse != nil {
 		endpoint.ResponseSchema = input.SchemasResponse
 	}
+	if input.TransformResponse != nil {
+		if input.SchemasResponse != nil {
+			return nil, fmt.Errorf("%s %s: TransformResponse replaces SchemasResponse, set only one", input.Method, input.Path)
+		}
+		if err := an.checkSchemaNames(input.TransformResponse); err != nil {
+			return nil, err
+		}
+		endpoint.ResponseSchema = input.TransformResponse
+	}
+	if input.SparseFields {
+		endpoint.SparseFields = filterableFields(endpoint.ResponseSchema)
+		if len(endpoint.SparseFields) == 0 {
+			return nil, fmt.Errorf("%s %s: SparseFields requires a struct response schema", input.Method, input.Path)
+		}
+		endpoint.Parameters = mergeParams(endpoint.Parameters, []Parameter{sparseFieldsParameter(endpoint.SparseFields)})
+	}
+	if len(input.ExampleFiles) > 0 {
+		examples, err := loadExampleFiles(an.config.ExampleFS, input.ExampleFiles)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.RequestExamples = redactExamples(examples, input.SchemasRequest)
+	}
+	if err := validatePresets(input.Presets, &endpoint); err != nil {
+		return nil, fmt.Errorf("presets of %s %s: %w", input.Method, input.Path, err)
+	}
+	endpoint.Presets = append([]RequestPreset(nil), input.Presets...)
+	if input.WebhookSignature != nil {
+		endpoint.WebhookSignature = input.WebhookSignature
+		documentWebhookSignature(&endpoint, input.WebhookSignature)
+	}
+	if input.RequestSigning != nil {
+		endpoint.RequestSigning = input.RequestSigning
+		if endpoint.Responses == nil {
+			endpoint.Responses = make(map[string]string)
+		}
+		if _, ok := endpoint.Responses["401"]; !ok {
+			endpoint.Responses["401"] = "Missing or invalid request signature"
+		}
+	}
+
+	if input.Proxy != nil {
+		service, err := proxiedService(input, routePath)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.Proxy = service
+	}
+
+	endpoint.Compressed = an.compressed(input)
+	if input.Method == "POST" || input.Method == "PUT" || input.Method == "PATCH" {
+		endpoint.RequestDecompression = an.requestDecompression(input)
+	}
+	if endpoint.RequestDecompression != nil {
+		documentRequestDecompression(&endpoint)
+	}
+	if input.EncryptedRequest {
+		if an.config.RequestEncryption == nil {
+			return nil, fmt.Errorf("%s %s: encrypted requests require Config.RequestEncryption", input.Method, input.Path)
+		}
+		if err := an.config.RequestEncryption.check(); err != nil {
+			return nil, fmt.Errorf("request encryption of %s %s: %w", input.Method, input.Path, err)
+		}
+		endpoint.EncryptedRequest = true
+		documentRequestEncryption(&endpoint)
+	}
+	endpoint.Thresholds = an.thresholds(input)
+	if input.SLO != nil {
+		if input.SLO.LatencyP99 <= 0 && input.SLO.Availability <= 0 {
+			return nil, fmt.Errorf("SLO of %s %s: LatencyP99 or Availability is required", input.Method, input.Path)
+		}
+		if input.SLO.Availability < 0 || input.SLO.Availability >= 1 {
+			return nil, fmt.Errorf("SLO of %s %s: Availability must be between 0 and 1", input.Method, input.Path)
+		}
+		endpoint.SLO = NewSLOTracker(*input.SLO)
+	}
+	if an.config.PayloadSizes != nil {
+		endpoint.PayloadSizes = an.newPayloadSizeTracker(&endpoint)
+	}
+	if input.ConcurrencyLimit != nil {
+		if input.ConcurrencyLimit.MaxInFlight <= 0 {
+			return nil, fmt.Errorf("concurrency limit of %s %s: MaxInFlight must be positive", input.Method, input.Path)
+		}
+		limit := *input.ConcurrencyLimit
+		endpoint.ConcurrencyLimit = &limit
+		if endpoint.Responses == nil {
+			endpoint.Responses = make(map[string]string)
+		}
+		if status := strconv.Itoa(limit.status()); endpoint.Responses[status] == "" {
+			endpoint.Responses[status] = "Too many concurrent requests, retry after the Retry-After delay"
+		}
+	}
+	var ipFilter fiber.Handler
+	if filter := an.routeIPFilter(input); filter != nil && filter.restricts() {
+		handler, err := IPFilterMiddleware(*filter)
+		if err != nil {
+			return nil, fmt.Errorf("IP filter of %s %s: %w", input.Method, input.Path, err)
+		}
+		ipFilter = handler
+		endpoint.IPFilter = &IPFilter{
+			Allow:          append([]string(nil), filter.Allow...),
+			Deny:           append([]string(nil), filter.Deny...),
+			TrustedProxies: append([]string(nil), filter.TrustedProxies...),
+		}
+		if endpoint.Responses == nil {
+			endpoint.Responses = make(map[string]string)
+		}
+		if endpoint.Responses["403"] == "" {
+			endpoint.Responses["403"] = ipFilterDescription
+		}
+	}
+	if input.CircuitBreaker != nil {
+		endpoint.CircuitBreaker = NewCircuitBreaker(*input.CircuitBreaker)
+		if endpoint.Responses == nil {
+			endpoint.Responses = make(map[string]string)
+		}
+		if _, ok := endpoint.Responses["503"]; !ok {
+			endpoint.Responses["503"] = circuitOpenDescription
+		}
+	}
+	if len(input.Variants) > 0 {
+		if err := checkVariants(input.Variants); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", input.Method, input.Path, err)
+		}
+		endpoint.Variants = append([]RouteVariant(nil), input.Variants...)
+	}
+	endpoint.Deprecated = input.Deprecated
+	if input.Migration != nil {
+		if err := validateMigrationGuide(input.Migration, input.Deprecated); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", input.Method, input.Path, err)
+		}
+		guide := *input.Migration
+		endpoint.Migration = &guide
+	}
+	if an.apiKeysRequired || len(input.APIKeyScopes) > 0 {
+		if an.apiKeys == nil {
+			return nil, fmt.Errorf("%s %s: API keys require Config.APIKeys", input.Method, input.Path)
+		}
+		endpoint.APIKeyRequired = true
+		endpoint.APIKeyScopes = append([]string(nil), input.APIKeyScopes...)
+		documentAPIKeyResponses(&endpoint)
+	}
+	if an.sessionsRequired {
+		if an.config.Sessions == nil {
+			return nil, fmt.Errorf("%s %s: sessions require Config.Sessions", input.Method, input.Path)
+		}
+		endpoint.SessionRequired = true
+		if endpoint.Responses == nil {
+			endpoint.Responses = make(map[string]string)
+		}
+		if _, ok := endpoint.Responses["401"]; !ok {
+			endpoint.Responses["401"] = "Sign in required"
+		}
+	}
+	if input.FeatureFlag != "" {
+		if err := an.featureFlagged(&endpoint, input.FeatureFlag); err != nil {
+			return nil, err
+		}
+	}
+	return &preparedRoute{input: input, endpoint: endpoint, routePath: routePath, ipFilter: ipFilter}, nil
+}
 
+// mountRoute records a prepared route's endpoint and mounts its handler chain
+func (an *ApiNote) mountRoute(route *preparedRoute, scoped []fiber.Handler) error {
+	input, endpoint, routePath, ipFilter := route.input, route.endpoint, route.routePath, route.ipFilter
+	key := input.Method + " " + input.Path
+	an.hooks.runRouteRegistered(&endpoint)
 	an.endpoints[key] = endpoint
+	an.documentMethodNotAllowed(endpoint.Path)
+	an.mountOptions(endpoint.Path)
+	an.artifacts.invalidate()
 
 	// Combine authentication middlewares (JWT or custom), custom middlewares, then add the handler
 	handlers := []any{}
+	// Tag the request first so every later log line carries its id
+	if an.config.RequestLogging {
+		handlers = append(handlers, RequestIDMiddleware(), RequestLoggerMiddleware())
+	}
+	// Disabled routes respond as if they didn't exist, before any other check
+	if endpoint.FeatureFlag != "" {
+		handlers = append(handlers, FeatureFlagMiddleware(an.config.FeatureFlags, endpoint.FeatureFlag, an.featureFlagStatus()))
+	}
+	// Reject filtered clients before spending anything else on them
+	if ipFilter != nil {
+		handlers = append(handlers, ipFilter)
+	}
+	// Throttle the docs' try-it traffic before the route does any work
+	if an.config.TryIt != nil && (an.config.TryIt.RateLimit > 0 || an.config.TryIt.IdempotentOnly) {
+		handlers = append(handlers, an.tryItMiddleware())
+	}
+	// Measure the whole chain against the route's limits
+	if endpoint.Thresholds != nil {
+		handlers = append(handlers, an.thresholdsMiddleware(&endpoint))
+	}
+	if endpoint.SLO != nil {
+		handlers = append(handlers, SLOMiddleware(endpoint.SLO))
+	}
+	if an.config.CORS != nil {
+		handlers = append(handlers, CORSMiddleware(*an.config.CORS))
+	}
+	// Compress first so every response of the route is compressed
+	if endpoint.Compressed {
+		handlers = append(handlers, CompressionMiddleware(an.compressionConfig()))
+	}
+	// Require a verified client certificate before authentication parses
+	// credentials or spends rate-limit quota on the request
+	if endpoint.ClientCertRequired {
+		handlers = append(handlers, ClientCertMiddleware())
+	}
 	if endpoint.AuthRequired {
 		// Add JWT middlewares if present
 		for _, h := range an.jwtMiddlewares {
@@ -251,43 +563,133 @@ func (an *ApiNote) DocumentedRoute(input *DocumentedRouteInput) error {
 		}
 	}
 
+	if endpoint.APIKeyRequired {
+		handlers = append(handlers, an.apiKeys.Middleware(endpoint.APIKeyScopes...))
+	}
+	if endpoint.SessionRequired {
+		handlers = append(handlers, an.SessionMiddleware())
+	}
+
+	// Record the callers of deprecated routes once authentication identified them
+	if endpoint.Deprecated {
+		handlers = append(handlers, an.deprecatedRouteMiddleware(&endpoint))
+	}
+
+	// Decompress the body before anything reads it
+	if endpoint.RequestDecompression != nil {
+		handlers = append(handlers, RequestDecompressionMiddleware(*endpoint.RequestDecompression))
+	}
+
+	// Verify webhook signatures over the decompressed body, before it is validated
+	if endpoint.WebhookSignature != nil {
+		handlers = append(handlers, WebhookSignatureMiddleware(*endpoint.WebhookSignature))
+	}
+	if endpoint.RequestSigning != nil {
+		handlers = append(handlers, RequestSigningMiddleware(*endpoint.RequestSigning))
+	}
+	// Decrypt after signatures, which cover the encrypted body, and before validation
+	if endpoint.EncryptedRequest {
+		handlers = append(handlers, RequestEncryptionMiddleware(*an.config.RequestEncryption))
+	}
+	// Size the bodies as the handler reads and writes them
+	if endpoint.PayloadSizes != nil {
+		handlers = append(handlers, an.payloadSizesMiddleware(&endpoint))
+	}
+
 	// Add validation middleware if validation is needed
 	// Validation is enabled by default when parameters or request schema are defined
-	if len(endpoint.Parameters) > 0 || endpoint.RequestSchema != nil {
-		validationHandler := func(c fiber.Ctx) error {
+	if len(endpoint.Parameters) > 0 || endpoint.RequestSchema != nil || len(endpoint.ValidationRules) > 0 {
+		parameters := NewParameterValidator(endpoint.Parameters)
+		validate := func(c fiber.Ctx) error {
 			// Validate parameters
 			if len(endpoint.Parameters) > 0 {
-				if err := ValidateParameters(c, endpoint.Parameters); err != nil {
-					return c.Status(fiber.StatusBadRequest).JSON(err)
+				if err := parameters.Validate(c); err != nil {
+					return err
 				}
 			}
 
 			// Validate request body for POST/PUT/PATCH
 			if endpoint.RequestSchema != nil &&
 				(endpoint.Method == "POST" || endpoint.Method == "PUT" || endpoint.Method == "PATCH") {
-				if err := ValidateRequestBody(c, endpoint.RequestSchema); err != nil {
-					return c.Status(fiber.StatusBadRequest).JSON(err)
+				if err := validateRequestBody(c, endpoint.RequestSchema, an.config.TimeFormat, an.streamValidationThreshold()); err != nil {
+					return err
+				}
+			}
+
+			// Check the route's rules against the caller once the request is well-formed
+			if len(endpoint.ValidationRules) > 0 {
+				return validateRules(c, &endpoint)
+			}
+
+			return nil
+		}
+
+		timing := an.config.ServerTiming
+		validationHandler := func(c fiber.Ctx) error {
+			start := time.Now()
+			err := validate(c)
+			if timing {
+				RecordServerTiming(c, "validation", time.Since(start))
+			}
+			if err != nil {
+				if an.config.ValidationFailures != nil {
+					an.recordValidationFailure(c, &endpoint, err)
 				}
+				// Returned to the app's ErrorHandler, which renders the 400 envelope
+				return err
 			}
 
 			return c.Next()
 		}
 		handlers = append(handlers, validationHandler)
 	}
+	if endpoint.RequestSchema != nil && (endpoint.Method == "POST" || endpoint.Method == "PUT" || endpoint.Method == "PATCH") &&
+		hasDeprecatedFields(reflect.TypeOf(endpoint.RequestSchema), make(map[reflect.Type]bool)) {
+		handlers = append(handlers, an.deprecatedFieldsMiddleware(&endpoint))
+	}
 
-	// Add custom non-auth middlewares
-	for _, h := range an.middlewares {
+	// Add custom non-auth middlewares, then the route scope's
+	for _, middleware := range an.middlewares {
+		handlers = append(handlers, middleware.handler)
+	}
+	for _, h := range scoped {
 		handlers = append(handlers, h)
 	}
-	// Add the route handler
-	handlers = append(handlers, input.Handler)
+	if endpoint.ConcurrencyLimit != nil {
+		handlers = append(handlers, ConcurrencyLimitMiddleware(*endpoint.ConcurrencyLimit))
+	}
+	// Guard the handler alone, so rejected and invalid requests don't count as failures
+	if endpoint.CircuitBreaker != nil {
+		handlers = append(handlers, CircuitBreakerMiddleware(endpoint.CircuitBreaker))
+	}
+	if len(endpoint.SparseFields) > 0 {
+		handlers = append(handlers, SparseFieldsMiddleware(endpoint.SparseFields))
+	}
+	// Add the route handler, or the mock handler in mock mode, timed when Server-Timing is enabled
+	handler := input.Handler
+	if an.config.Mock && !input.builtin {
+		handler = an.mockHandler(endpoint, input.MockRules)
+	} else {
+		if len(endpoint.Variants) > 0 {
+			handler = VariantHandler(handler, endpoint.Variants)
+		}
+		// Reshape the handler's output into the declared schema; mock responses already match it
+		if input.TransformResponse != nil {
+			handlers = append(handlers, ResponseTransformMiddleware(input.TransformResponse, an.config.TimeFormat))
+		}
+	}
+	if an.config.ServerTiming {
+		handlers = append(handlers, timedHandler(handler))
+	} else {
+		handlers = append(handlers, handler)
+	}
 
 	// Ensure we have at least one handler
 	if len(handlers) == 0 {
 		return fmt.Errorf("at least one handler is required")
 	}
 
-	path := an.config.BasePath + input.Path
+	path := an.config.BasePath + routePath
 	// Get first handler and rest as varargs for v3 API
 	firstHandler := handlers[0]
 	restHandlers := []any{}
@@ -321,6 +723,19 @@ func (an *ApiNote) DocumentedRoute(input *DocumentedRouteInput) error {
 	return nil
 }
 
+// copyResponses returns a copy of the responses map so registration-time
+// additions never mutate the caller's map
+func copyResponses(responses map[string]string) map[string]string {
+	if responses == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(responses))
+	for code, description := range responses {
+		copied[code] = description
+	}
+	return copied
+}
+
 // Fiber returns the underlying *fiber.App instance used by the ApiNote.
 //
 // This allows external packages or components to directly access and
@@ -335,7 +750,7 @@ func (an *ApiNote) Fiber() *fiber.App {
 // The returned handler sets the Content-Type to "text/html" and responds with status 200.
 func (an *ApiNote) Handler() fiber.Handler {
 	return func(c fiber.Ctx) error {
-		html := an.generateHTML()
+		html := an.cachedDocsHTML(false, an.baseURL(c), an.localeOf(c))
 		c.Set("Content-Type", "text/html")
 		return c.Status(http.StatusOK).SendString(html)
 	}
@@ -345,7 +760,8 @@ func (an *ApiNote) Handler() fiber.Handler {
 // It checks the "Authorization" header for a "Bearer" token and verifies it
 // using the configured jwtSecret.
 //
-// If the token is valid, it sets the "user_id" in the context from the token's "sub" claim.
+// If the token is valid, it sets the "user_id" in the context from the token's "sub" claim
+// and "claims" to the token's claims.
 // If invalid or missing, it returns a 401 Unauthorized response.
 //
 // Example usage:
@@ -363,21 +779,14 @@ func (an *ApiNote) JWTMiddleware() fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid Authorization header format"})
 		}
 
-		tokenStr := parts[1]
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(an.jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		// Refresh tokens of the token endpoints only grant new tokens
+		claims, err := an.parseToken(parts[1])
+		if err != nil || claims[tokenUseClaim] == "refresh" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid or expired token"})
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Locals("user_id", claims["sub"])
-		}
+		c.Locals("user_id", claims["sub"])
+		c.Locals("claims", map[string]interface{}(claims))
 
 		return c.Next()
 	}
@@ -385,14 +794,47 @@ func (an *ApiNote) JWTMiddleware() fiber.Handler {
 
 // Listen starts the Fiber server on the port specified in Config.Host.
 // The Host field should be in the format "host:port" (e.g., "localhost:8080").
-// If no port is specified, it defaults to ":8080".
+// If no port is specified, it defaults to ":8080". Config.ListenConfig is applied
+// when set (e.g., EnablePrefork).
 //
 // Returns an error if the server fails to start.
 func (an *ApiNote) Listen() error {
-	hostParts := strings.Split(an.config.Host, ":")
-	port := ":8080" // Default port
-	if len(hostParts) > 1 {
-		port = ":" + hostParts[1]
+	return an.app.Listen(an.listenAddr(), an.listenConfig())
+}
+
+// listenConfig returns a copy of Config.ListenConfig, or the zero value when unset
+func (an *ApiNote) listenConfig() fiber.ListenConfig {
+	if an.config.ListenConfig == nil {
+		return fiber.ListenConfig{}
 	}
-	return an.app.Listen(port)
+	return *an.config.ListenConfig
+}
+
+// listenAddr derives the listen address from Config.Host, defaulting to ":8080"
+func (an *ApiNote) listenAddr() string {
+	_, port, err := net.SplitHostPort(an.config.Host)
+	if err != nil || port == "" {
+		return ":8080" // Default port
+	}
+	return ":" + port
+}
+
+// Listener serves the application on an existing net.Listener instead of
+// deriving an address from Config.Host. This is useful for sockets handed over
+// by a process manager or listeners wrapped by other libraries.
+//
+// Returns an error if the server fails to serve on the listener.
+func (an *ApiNote) Listener(ln net.Listener) error {
+	return an.app.Listener(ln, an.listenConfig())
+}
+
+// ListenUnix starts the Fiber server on a Unix domain socket at the given path,
+// e.g. for running behind a local reverse proxy. A stale socket file at path
+// is removed before listening.
+//
+// Returns an error if the socket cannot be created or the server fails to start.
+func (an *ApiNote) ListenUnix(path string) error {
+	listenConfig := an.listenConfig()
+	listenConfig.ListenerNetwork = fiber.NetworkUnix
+	return an.app.Listen(path, listenConfig)
 }
@@ -0,0 +1,171 @@
+package notelink
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Kinds of rendered docs artifacts
+const (
+	artifactDocsHTML    = "docs.html"
+	artifactPrintHTML   = "print.html"
+	artifactOpenAPIJSON = "openapi.json"
+	artifactOpenAPIYAML = "openapi.yaml"
+	artifactTypeScript  = "types.ts"
+)
+
+// artifactKey identifies a rendered artifact: the same kind is rendered per
+// base URL and locale
+type artifactKey struct {
+	kind    string
+	baseURL string
+	locale  string
+}
+
+// String returns the key as tracked by the cache's LRU
+func (k artifactKey) String() string {
+	return k.kind + " " + k.baseURL + " " + k.locale
+}
+
+// artifact is rendered once; concurrent requests for it wait for that render
+// instead of rendering it again
+type artifact struct {
+	once    sync.Once
+	version uint64
+	data    []byte
+	err     error
+}
+
+// artifactCache holds the rendered docs pages and specs. Registering routes,
+// channels, pages, error codes or hooks invalidates it, so artifacts rendered
+// before are never served after. Base URLs come from the request's host, so
+// the least recently used artifacts are evicted beyond
+// MemoryLimits.MaxArtifacts, keeping clients cycling hosts from growing it.
+type artifactCache struct {
+	mu         sync.Mutex
+	entries    map[string]*artifact
+	version    uint64 // Version of the artifacts in entries
+	lru        *lruTracker
+	generation atomic.Uint64
+}
+
+// invalidate drops the rendered artifacts
+func (ac *artifactCache) invalidate() {
+	ac.generation.Add(1)
+}
+
+// setLimit caps the rendered artifacts, DefaultMaxArtifacts when zero and
+// none when negative
+func (ac *artifactCache) setLimit(maxArtifacts int) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.lru = newLRUTracker(limit(maxArtifacts, DefaultMaxArtifacts), 0)
+	ac.entries = nil
+}
+
+// tracker returns the cache's LRU, with the default cap unless setLimit ran.
+// The caller holds mu.
+func (ac *artifactCache) tracker() *lruTracker {
+	if ac.lru == nil {
+		ac.lru = newLRUTracker(DefaultMaxArtifacts, 0)
+	}
+	return ac.lru
+}
+
+// stats reports the rendered artifacts as a registry
+func (ac *artifactCache) stats() RegistryStats {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	return ac.tracker().stats("rendered_artifacts")
+}
+
+// artifactVersion changes whenever the artifacts must be rendered again: on
+// registrations and when an asset's integrity couldn't be computed, so the
+// next render retries it
+func (an *ApiNote) artifactVersion() uint64 {
+	return an.artifacts.generation.Load() + an.assetIntegrity.failures.Load()
+}
+
+// cachedArtifact returns an artifact, rendering it on first use of the current
+// version. Render errors aren't cached.
+func (an *ApiNote) cachedArtifact(kind, baseURL, locale string, render func() ([]byte, error)) ([]byte, error) {
+	key := artifactKey{kind: kind, baseURL: baseURL, locale: locale}.String()
+	version := an.artifactVersion()
+	ac := &an.artifacts
+
+	ac.mu.Lock()
+	// Artifacts of an older version are never served again
+	if ac.entries == nil || ac.version != version {
+		for stale := range ac.entries {
+			ac.tracker().remove(stale)
+		}
+		ac.entries = make(map[string]*artifact)
+		ac.version = version
+	}
+	entry := ac.entries[key]
+	if entry == nil {
+		entry = &artifact{version: version}
+		ac.entries[key] = entry
+		for _, evicted := range ac.tracker().touch(key, 0) {
+			delete(ac.entries, evicted)
+		}
+	} else {
+		ac.tracker().use(key)
+	}
+	ac.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.data, entry.err = render()
+	})
+	if entry.err != nil {
+		ac.mu.Lock()
+		if ac.entries[key] == entry {
+			delete(ac.entries, key)
+			ac.tracker().remove(key)
+		}
+		ac.mu.Unlock()
+	}
+	return entry.data, entry.err
+}
+
+// cachedDocsHTML returns the docs page or print view. Pages badging routes
+// breaching their SLO reflect live state and are rendered on every request.
+func (an *ApiNote) cachedDocsHTML(printView bool, baseURL, locale string) string {
+	render := func() ([]byte, error) {
+		if printView {
+			return []byte(an.generatePrintHTML(baseURL, locale)), nil
+		}
+		return []byte(an.renderDocsHTML(false, baseURL, locale)), nil
+	}
+	if an.hasSLOs() {
+		html, _ := render()
+		return string(html)
+	}
+
+	kind := artifactDocsHTML
+	if printView {
+		kind = artifactPrintHTML
+	}
+	html, _ := an.cachedArtifact(kind, baseURL, locale, render)
+	return string(html)
+}
+
+// cachedOpenAPISpec returns the spec encoded as JSON, or as YAML with yaml set
+func (an *ApiNote) cachedOpenAPISpec(baseURL, locale string, yaml bool) ([]byte, error) {
+	if yaml {
+		return an.cachedArtifact(artifactOpenAPIYAML, baseURL, locale, func() ([]byte, error) {
+			return marshalYAML(an.generateOpenAPISpec(baseURL, locale))
+		})
+	}
+	return an.cachedArtifact(artifactOpenAPIJSON, baseURL, locale, func() ([]byte, error) {
+		return an.app.Config().JSONEncoder(an.generateOpenAPISpec(baseURL, locale))
+	})
+}
+
+// cachedTypeScriptTypes returns the TypeScript types of the schemas
+func (an *ApiNote) cachedTypeScriptTypes() string {
+	types, _ := an.cachedArtifact(artifactTypeScript, "", "", func() ([]byte, error) {
+		return []byte(an.generateTypeScriptTypes()), nil
+	})
+	return string(types)
+}
@@ -0,0 +1,102 @@
+package notelink
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestCachedArtifacts tests rendering the docs and spec once for concurrent requests
+func TestCachedArtifacts(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/orders", Description: "List orders", Handler: handler}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	var specs, pages atomic.Int64
+	api.OnSpecGenerated(func(spec *OpenAPISpec) { specs.Add(1) })
+	api.OnDocsRendered(func(html string) string {
+		pages.Add(1)
+		return html
+	})
+
+	fetch := func(path string) string {
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Errorf("Failed to fetch %s: %v", path, err)
+			return ""
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return string(body)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fetch("/api-docs/openapi.json")
+			fetch("/api-docs/print")
+		}()
+	}
+	wg.Wait()
+	if specs.Load() != 1 || pages.Load() != 1 {
+		t.Errorf("Expected the spec and print view rendered once, got %d and %d", specs.Load(), pages.Load())
+	}
+
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/invoices", Description: "List invoices", Handler: handler}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if spec := fetch("/api-docs/openapi.json"); !strings.Contains(spec, `"/invoices"`) || specs.Load() != 2 {
+		t.Errorf("Expected registering a route to render the spec again, got %d renders", specs.Load())
+	}
+	if yaml := fetch("/api-docs/openapi.yaml"); !strings.Contains(yaml, "/invoices:") {
+		t.Error("Expected the YAML spec to list the new route")
+	}
+
+	// Pages badging SLO breaches reflect live state
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/health", Description: "Health", Handler: handler, SLO: &SLO{Availability: 0.99}}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	rendered := pages.Load()
+	fetch("/api-docs/print")
+	fetch("/api-docs/print")
+	if pages.Load() != rendered+2 {
+		t.Errorf("Expected the print view rendered per request with SLOs, got %d renders", pages.Load()-rendered)
+	}
+}
+
+// TestCachedArtifactsCap tests evicting the artifacts of the least recently
+// requested hosts beyond MemoryLimits.MaxArtifacts
+func TestCachedArtifactsCap(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", MemoryLimits: &MemoryLimits{MaxArtifacts: 4}}, "secret")
+	for i := 0; i < 20; i++ {
+		req := httptest.NewRequest("GET", "/api-docs/openapi.json", nil)
+		req.Host = fmt.Sprintf("host%d.example.com", i)
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to fetch the spec: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(body), req.Host) {
+			t.Errorf("Expected the spec served for %s to use its host", req.Host)
+		}
+	}
+
+	api.artifacts.mu.Lock()
+	cached := len(api.artifacts.entries)
+	api.artifacts.mu.Unlock()
+	if cached > 4 {
+		t.Errorf("Expected at most 4 cached artifacts, got %d", cached)
+	}
+	if stats := api.artifacts.stats(); stats.Entries > 4 || stats.Evictions == 0 {
+		t.Errorf("Expected capped artifacts with evictions, got %+v", stats)
+	}
+}
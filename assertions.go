@@ -0,0 +1,415 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// assertionPattern matches a try-it assertion: a subject (status,
+// header.<name> or body[.<path>]), an operator and, except for exists, a
+// JSON literal
+var assertionPattern = regexp.MustCompile(`^(status|header\.[A-Za-z0-9-]+|body(?:\.\S+)?)\s+(exists|==|!=|<=|>=|<|>|contains)(?:\s+(.+))?$`)
+
+// assertion is a check of a try-it response, e.g. "status == 201" or
+// "body.id exists". Body paths separate keys and array indexes with dots;
+// "length" is the length of an array or string.
+type assertion struct {
+	text     string
+	subject  string // "status", "header" or "body"
+	name     string // Header name or body path
+	operator string
+	value    string // JSON literal, empty for exists
+}
+
+// parseAssertion parses an assertion of the try-it panel
+func parseAssertion(text string) (assertion, error) {
+	text = strings.TrimSpace(text)
+	match := assertionPattern.FindStringSubmatch(text)
+	if match == nil {
+		return assertion{}, fmt.Errorf("%q: expected <status|header.Name|body.path> <exists|==|!=|<|<=|>|>=|contains> [value]", text)
+	}
+	a := assertion{text: text, operator: match[2], value: strings.TrimSpace(match[3])}
+	a.subject, a.name, _ = strings.Cut(match[1], ".")
+
+	if a.operator == "exists" {
+		if a.value != "" || a.subject == "status" {
+			return assertion{}, fmt.Errorf("%q: exists takes a header or body path and no value", text)
+		}
+		return a, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(a.value), &value); err != nil {
+		return assertion{}, fmt.Errorf("%q: the value must be a JSON literal, e.g. 201 or \"text\"", text)
+	}
+	number, isNumber := value.(float64)
+	_, isString := value.(string)
+	ordered := a.operator != "==" && a.operator != "!=" && a.operator != "contains"
+	switch {
+	case a.subject == "status" && (!isNumber || number != math.Trunc(number) || a.operator == "contains"):
+		return assertion{}, fmt.Errorf("%q: status compares with a whole number", text)
+	case a.subject == "header" && (!isString || ordered):
+		return assertion{}, fmt.Errorf("%q: headers take ==, != or contains and a string", text)
+	case ordered && !isNumber:
+		return assertion{}, fmt.Errorf("%q: %s compares with a number", text, a.operator)
+	}
+	return a, nil
+}
+
+// goCheck renders the Go statements checking an assertion in a test skeleton
+func (a assertion) goCheck() string {
+	failure := strconv.Quote("Expected " + strings.ReplaceAll(a.text, "%", "%%") + ", got %v")
+	path := ""
+	if a.name != "" {
+		for _, key := range strings.Split(a.name, ".") {
+			path += ", " + strconv.Quote(key)
+		}
+	}
+
+	var check string
+	switch {
+	case a.subject == "status":
+		check = "if got := resp.StatusCode; !(got " + a.operator + " " + a.value + ")"
+	case a.subject == "header" && a.operator == "exists":
+		check = "if got := resp.Header.Get(" + strconv.Quote(a.name) + `); got == ""`
+	case a.subject == "header":
+		var value string
+		_ = json.Unmarshal([]byte(a.value), &value)
+		if a.operator == "contains" {
+			check = "if got := resp.Header.Get(" + strconv.Quote(a.name) + "); !strings.Contains(got, " + strconv.Quote(value) + ")"
+		} else {
+			check = "if got := resp.Header.Get(" + strconv.Quote(a.name) + "); !(got " + a.operator + " " + strconv.Quote(value) + ")"
+		}
+	case a.operator == "exists":
+		check = "if got, ok := lookup(body" + path + "); !ok"
+	case a.operator == "==":
+		check = "if got, _ := lookup(body" + path + "); !equal(got, " + goStringLiteral(a.value) + ")"
+	case a.operator == "!=":
+		check = "if got, _ := lookup(body" + path + "); equal(got, " + goStringLiteral(a.value) + ")"
+	case a.operator == "contains":
+		check = "if got, _ := lookup(body" + path + "); !contains(got, " + goStringLiteral(a.value) + ")"
+	default:
+		check = "if got, _ := lookup(body" + path + "); !(number(got) " + a.operator + " " + a.value + ")"
+	}
+	return "\t// " + goComment(a.text) + "\n\t" + check + " {\n\t\tt.Errorf(" + failure + ", got)\n\t}\n"
+}
+
+// goComment returns text for a line comment of Go source, quoted when it
+// spans several lines
+func goComment(text string) string {
+	if strings.ContainsAny(text, "\r\n") {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+// goStringLiteral quotes a string for Go source, raw when it can be
+func goStringLiteral(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
+
+// goTestSkeleton renders a Go test sending an endpoint's request to target
+// and checking the assertions of its try-it panel, to paste into the API's
+// test suite
+func (an *ApiNote) goTestSkeleton(endpoint *Endpoint, target, body string, assertions []assertion) ([]byte, error) {
+	operation := generateOperationID(endpoint.Method, endpoint.routePath())
+	var src strings.Builder
+	src.WriteString("// Generated by notelink from the " + goComment(an.config.Title) + " docs: a starting point for\n")
+	src.WriteString("// a test of " + endpoint.Method + " " + endpoint.routePath() + ".\n\n")
+	src.WriteString(`package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/canvas-tech-horizon/notelink"
+)
+
+`)
+	src.WriteString("func Test" + strings.ToUpper(operation[:1]) + operation[1:] + "(t *testing.T) {\n")
+	src.WriteString("\tapi := newTestAPI(t)\n")
+	if body != "" {
+		src.WriteString("\treq := httptest.NewRequest(" + strconv.Quote(endpoint.Method) + ", " + strconv.Quote(target) + ", strings.NewReader(" + goStringLiteral(body) + "))\n")
+		src.WriteString("\treq.Header.Set(\"Content-Type\", \"application/json\")\n")
+	} else {
+		src.WriteString("\treq := httptest.NewRequest(" + strconv.Quote(endpoint.Method) + ", " + strconv.Quote(target) + ", nil)\n")
+	}
+	if endpoint.AuthRequired {
+		src.WriteString("\treq.Header.Set(\"Authorization\", \"Bearer <token>\") // TODO: a valid token\n")
+	}
+	if endpoint.APIKeyRequired {
+		src.WriteString("\treq.Header.Set(" + strconv.Quote(an.apiKeyHeader()) + ", \"<api-key>\") // TODO: a valid key\n")
+	}
+	src.WriteString(`	resp, err := api.Fiber().Test(req)
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read the response: %v", err)
+	}
+	var body interface{}
+	_ = json.Unmarshal(data, &body)
+`)
+	for _, a := range assertions {
+		src.WriteString("\n" + a.goCheck())
+	}
+	src.WriteString("}\n\n")
+	src.WriteString(`// newTestAPI builds the API under test
+func newTestAPI(t *testing.T) *notelink.ApiNote {
+	t.Helper()
+	// TODO: register the routes of the API
+	return notelink.NewApiNote(&notelink.Config{Title: ` + strconv.Quote(an.config.Title) + `}, "secret")
+}
+` + goTestHelpers)
+	return format.Source([]byte(src.String()))
+}
+
+// goTestHelpers evaluate body assertions in the generated tests
+const goTestHelpers = `
+// lookup returns the value at a path of a decoded JSON body; "length" is the
+// length of an array or string
+func lookup(value interface{}, path ...string) (interface{}, bool) {
+	for _, key := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			if key == "length" {
+				value = float64(len(v))
+				continue
+			}
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			value = v[index]
+		case string:
+			if key != "length" {
+				return nil, false
+			}
+			value = float64(len([]rune(v)))
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// equal reports whether a value equals a JSON literal
+func equal(value interface{}, literal string) bool {
+	var want interface{}
+	return json.Unmarshal([]byte(literal), &want) == nil && reflect.DeepEqual(value, want)
+}
+
+// number returns a JSON number, or NaN for other values
+func number(value interface{}) float64 {
+	if n, ok := value.(float64); ok {
+		return n
+	}
+	return math.NaN()
+}
+
+// contains reports whether a string contains a JSON string literal, or an
+// array an element equal to a JSON literal
+func contains(value interface{}, literal string) bool {
+	var want interface{}
+	if json.Unmarshal([]byte(literal), &want) != nil {
+		return false
+	}
+	switch v := value.(type) {
+	case string:
+		s, ok := want.(string)
+		return ok && strings.Contains(v, s)
+	case []interface{}:
+		for _, element := range v {
+			if reflect.DeepEqual(element, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+`
+
+// goTestHandler serves the Go test skeleton of a try-it form's assertions,
+// e.g. /api-docs/go-test?method=POST&path=/users&assert=status+==+201. The
+// optional target and body are the path, query and body of the last request.
+func (an *ApiNote) goTestHandler(c fiber.Ctx) error {
+	var endpoint *Endpoint
+	for _, candidate := range an.sortedEndpoints() {
+		if candidate.Method == c.Query("method") && candidate.Path == c.Query("path") && !an.hiddenByFlag(&candidate) && !an.hiddenAsDraft(&candidate) {
+			endpoint = &candidate
+			break
+		}
+	}
+	if endpoint == nil {
+		return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "Unknown operation"})
+	}
+
+	var assertions []assertion
+	for _, raw := range c.Request().URI().QueryArgs().PeekMulti("assert") {
+		if strings.TrimSpace(string(raw)) == "" {
+			continue
+		}
+		a, err := parseAssertion(string(raw))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: err.Error()})
+		}
+		assertions = append(assertions, a)
+	}
+	target := c.Query("target", endpoint.Path)
+	if !strings.HasPrefix(target, "/") || strings.ContainsAny(target, " \t\r\n") {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: "target must be a path"})
+	}
+	body := c.Query("body")
+	if body == "" && endpoint.RequestSchema != nil {
+		body = an.exampleRequestBody(endpoint)
+	}
+
+	src, err := an.goTestSkeleton(endpoint, target, body, assertions)
+	if err != nil {
+		return err
+	}
+	operation := generateOperationID(endpoint.Method, endpoint.routePath())
+	c.Set(fiber.HeaderContentType, "text/x-go; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+operation+`_test.go"`)
+	return c.Send(src)
+}
+
+// assertionsScript evaluates the assertions of the try-it panels on their
+// responses and builds the links exporting them as Go tests
+func (an *ApiNote) assertionsScript() string {
+	return `
+            const assertionPattern = /^(status|header\.[A-Za-z0-9-]+|body(?:\.\S+)?)\s+(exists|==|!=|<=|>=|<|>|contains)(?:\s+(.+))?$/;
+            // evaluateAssertion checks a try-it response, returning whether it
+            // passed and the value found
+            function evaluateAssertion(text, result) {
+                const match = text.trim().match(assertionPattern);
+                if (!match) {
+                    return { pass: false, actual: 'invalid assertion' };
+                }
+                const [, subject, operator, literal] = match;
+                let expected;
+                if (operator !== 'exists') {
+                    try {
+                        expected = JSON.parse(literal);
+                    } catch (e) {
+                        return { pass: false, actual: 'invalid value' };
+                    }
+                }
+                let actual;
+                let found = true;
+                if (subject === 'status') {
+                    actual = result.status;
+                } else if (subject.startsWith('header.')) {
+                    actual = (result.headers || {})[subject.slice(7).toLowerCase()];
+                    found = actual !== undefined;
+                } else {
+                    try {
+                        actual = typeof result.body === 'string' ? JSON.parse(result.body) : undefined;
+                    } catch (e) {
+                        actual = undefined;
+                    }
+                    found = actual !== undefined;
+                    for (const key of subject.split('.').slice(1)) {
+                        if (!found) {
+                            break;
+                        }
+                        if (key === 'length' && (Array.isArray(actual) || typeof actual === 'string')) {
+                            actual = actual.length;
+                        } else if (actual !== null && typeof actual === 'object' && Object.prototype.hasOwnProperty.call(actual, key)) {
+                            actual = actual[key];
+                        } else {
+                            found = false;
+                            actual = undefined;
+                        }
+                    }
+                }
+                const equal = (a, b) => JSON.stringify(a) === JSON.stringify(b);
+                let pass;
+                switch (operator) {
+                    case 'exists': pass = found; break;
+                    case '==': pass = found && equal(actual, expected); break;
+                    case '!=': pass = !found || !equal(actual, expected); break;
+                    case '<': pass = typeof actual === 'number' && actual < expected; break;
+                    case '<=': pass = typeof actual === 'number' && actual <= expected; break;
+                    case '>': pass = typeof actual === 'number' && actual > expected; break;
+                    case '>=': pass = typeof actual === 'number' && actual >= expected; break;
+                    case 'contains':
+                        pass = typeof actual === 'string' ? typeof expected === 'string' && actual.includes(expected)
+                            : Array.isArray(actual) && actual.some(element => equal(element, expected));
+                        break;
+                }
+                return { pass: pass, actual: found ? JSON.stringify(actual) : 'missing' };
+            }
+
+            function assertionLines(form) {
+                const input = form.querySelector('.assertions-input');
+                return input ? input.value.split('\n').map(line => line.trim()).filter(line => line) : [];
+            }
+
+            // renderAssertions shows a pass/fail badge per assertion of a form
+            function renderAssertions(form, result) {
+                const list = form.querySelector('.assertion-results');
+                if (!list) {
+                    return;
+                }
+                list.replaceChildren();
+                assertionLines(form).forEach(text => {
+                    const outcome = evaluateAssertion(text, result);
+                    const item = document.createElement('li');
+                    const badge = document.createElement('span');
+                    badge.className = 'assertion-badge ' + (outcome.pass ? 'pass' : 'fail');
+                    badge.textContent = outcome.pass ? 'PASS' : 'FAIL';
+                    item.append(badge, ' ' + text + (outcome.pass ? '' : ' (got ' + outcome.actual + ')'));
+                    list.appendChild(item);
+                });
+            }
+
+            // exportGoTest downloads the form's assertions as a Go test skeleton
+            function exportGoTest(link, method, path) {
+                const form = link.closest('form');
+                const params = new URLSearchParams({ method: method, path: path });
+                if (form.dataset.target) {
+                    params.set('target', form.dataset.target);
+                }
+                const bodyInput = form.querySelector('textarea[name="requestBody"]');
+                if (bodyInput && bodyInput.value.trim()) {
+                    params.set('body', bodyInput.value.trim());
+                }
+                assertionLines(form).forEach(line => params.append('assert', line));
+                link.href = '` + escapeJavaScript(an.docsPath()) + `/go-test?' + params.toString();
+            }`
+}
+
+// renderAssertionsPanel renders the assertions of an endpoint's try-it form
+func renderAssertionsPanel(endpoint *Endpoint) string {
+	return `
+                            <details class="try-it-assertions">
+                                <summary>Assertions</summary>
+                                <textarea class="assertions-input" rows="3" placeholder="One per line, e.g. status == 201 or body.id exists"></textarea>
+                                <a class="json-editor-btn" href="#" download onclick="exportGoTest(this, '` + endpoint.Method + `', '` + escapeJavaScript(endpoint.Path) + `')"><i class="fas fa-file-code"></i> Export Go test</a>
+                                <ul class="assertion-results"></ul>
+                            </details>`
+}
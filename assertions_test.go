@@ -0,0 +1,139 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestParseAssertion tests parsing try-it assertions
+func TestParseAssertion(t *testing.T) {
+	tests := []struct {
+		text  string
+		valid bool
+	}{
+		{"status == 201", true},
+		{"body.id exists", true},
+		{"body.items.length > 0", true},
+		{`body.tags contains "admin"`, true},
+		{`header.Content-Type contains "json"`, true},
+		{`body.user.name == "Ada"`, true},
+		{"body == null", true},
+		{"status exists", false},
+		{"status == 20.5", false},
+		{`status == "201"`, false},
+		{"body.total > \"3\"", false},
+		{"header.ETag > 3", false},
+		{"body.id exists 1", false},
+		{"body.id is set", false},
+		{"body.id == Ada", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := parseAssertion(tt.text)
+			if tt.valid && err != nil {
+				t.Errorf("Expected %q to parse, got %v", tt.text, err)
+			} else if !tt.valid && err == nil {
+				t.Errorf("Expected %q to be rejected", tt.text)
+			}
+		})
+	}
+}
+
+// TestGoTestExport tests exporting try-it assertions as a Go test
+func TestGoTestExport(t *testing.T) {
+	type user struct {
+		Name string `json:"name" example:"Ada"`
+	}
+	api := NewApiNote(&Config{Title: "Users API"}, "secret")
+	authRequired := true
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "POST", Path: "/users", Description: "Create a user", Handler: func(c fiber.Ctx) error { return nil }, SchemasRequest: user{}, AuthRequired: &authRequired}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		status   int
+		contains []string
+	}{
+		{
+			name:   "skeleton",
+			query:  url.Values{"method": {"POST"}, "path": {"/users"}, "target": {"/users?dry_run=true"}, "assert": {"status == 201", "body.id exists", `body.roles contains "admin"`, `header.Location contains "/users/"`}},
+			status: fiber.StatusOK,
+			contains: []string{
+				"func TestPostUsers(t *testing.T) {",
+				`req := httptest.NewRequest("POST", "/users?dry_run=true", strings.NewReader(`,
+				`req.Header.Set("Authorization", "Bearer <token>") // TODO: a valid token`,
+				"if got := resp.StatusCode; !(got == 201) {",
+				`if got, ok := lookup(body, "id"); !ok {`,
+				"if got, _ := lookup(body, \"roles\"); !contains(got, `\"admin\"`) {",
+				`if got := resp.Header.Get("Location"); !strings.Contains(got, "/users/") {`,
+				"func lookup(value interface{}, path ...string) (interface{}, bool) {",
+			},
+		},
+		{
+			name:     "body of the form",
+			query:    url.Values{"method": {"POST"}, "path": {"/users"}, "body": {`{"name": "Grace"}`}},
+			status:   fiber.StatusOK,
+			contains: []string{"strings.NewReader(`{\"name\": \"Grace\"}`)"},
+		},
+		{name: "invalid assertion", query: url.Values{"method": {"POST"}, "path": {"/users"}, "assert": {"status is 201"}}, status: fiber.StatusBadRequest},
+		{name: "unknown operation", query: url.Values{"method": {"GET"}, "path": {"/users"}}, status: fiber.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/go-test?"+tt.query.Encode(), nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected %d, got %d: %s", tt.status, resp.StatusCode, body)
+			}
+			if tt.status != fiber.StatusOK {
+				return
+			}
+			if disposition := resp.Header.Get("Content-Disposition"); disposition != `attachment; filename="postUsers_test.go"` {
+				t.Errorf("Unexpected Content-Disposition %q", disposition)
+			}
+			for _, snippet := range tt.contains {
+				if !strings.Contains(string(body), snippet) {
+					t.Errorf("Expected the test to contain %q, got:\n%s", snippet, body)
+				}
+			}
+		})
+	}
+}
+
+// TestGoTestExportMultiline tests exporting a Go test for an API whose title,
+// description and assertions span several lines
+func TestGoTestExportMultiline(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Users API\nInternal", Description: "Manages users.\n\nSee the wiki."}, "secret")
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/users", Description: "List users\npaginated", Handler: func(c fiber.Ctx) error { return nil }}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	query := url.Values{"method": {"GET"}, "path": {"/users"}, "assert": {"status ==\n200"}}
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/go-test?"+query.Encode(), nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	for _, snippet := range []string{`// Generated by notelink from the "Users API\nInternal" docs`, `// "status ==\n200"`, `Title: "Users API\nInternal"`} {
+		if !strings.Contains(string(body), snippet) {
+			t.Errorf("Expected the test to contain %q, got:\n%s", snippet, body)
+		}
+	}
+}
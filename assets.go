@@ -0,0 +1,158 @@
+package notelink
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// Names of the external assets loaded by the docs, Swagger UI, Scalar and ReDoc pages,
+// used as keys of Config.Assets
+const (
+	AssetFonts                     = "fonts"
+	AssetFontAwesome               = "font-awesome"
+	AssetCodeMirrorCSS             = "codemirror.css"
+	AssetCodeMirrorThemeCSS        = "codemirror-theme.css"
+	AssetCodeMirror                = "codemirror"
+	AssetCodeMirrorJavaScript      = "codemirror-javascript"
+	AssetCodeMirrorLint            = "codemirror-lint"
+	AssetCodeMirrorJSONLint        = "codemirror-json-lint"
+	AssetCodeMirrorCloseBrackets   = "codemirror-closebrackets"
+	AssetCodeMirrorMatchBrackets   = "codemirror-matchbrackets"
+	AssetCodeMirrorFoldCode        = "codemirror-foldcode"
+	AssetCodeMirrorFoldGutter      = "codemirror-foldgutter"
+	AssetCodeMirrorBraceFold       = "codemirror-bracefold"
+	AssetJSONLint                  = "jsonlint"
+	AssetSwaggerUICSS              = "swagger-ui.css"
+	AssetSwaggerUIBundle           = "swagger-ui-bundle"
+	AssetSwaggerUIStandalonePreset = "swagger-ui-standalone-preset"
+	AssetScalar                    = "scalar"
+	AssetRedoc                     = "redoc"
+)
+
+// Asset is an external script or stylesheet. Set URL to self-host or pin a
+// version, and Integrity to a Subresource Integrity hash such as "sha384-...".
+type Asset struct {
+	URL       string
+	Integrity string
+}
+
+// defaultAsset is the built-in location of an asset
+type defaultAsset struct {
+	url        string
+	stylesheet bool
+	dynamic    bool // Content varies per browser, so it can't be pinned with a hash
+}
+
+const codeMirrorCDN = "https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/"
+
+var defaultAssets = map[string]defaultAsset{
+	AssetFonts:                     {url: "https://fonts.googleapis.com/css2?family=Inter:wght@300;400;500;600;700&family=JetBrains+Mono:wght@400;500&display=swap", stylesheet: true, dynamic: true},
+	AssetFontAwesome:               {url: "https://cdnjs.cloudflare.com/ajax/libs/font-awesome/6.4.0/css/all.min.css", stylesheet: true},
+	AssetCodeMirrorCSS:             {url: codeMirrorCDN + "codemirror.min.css", stylesheet: true},
+	AssetCodeMirrorThemeCSS:        {url: codeMirrorCDN + "theme/default.min.css", stylesheet: true},
+	AssetCodeMirror:                {url: codeMirrorCDN + "codemirror.min.js"},
+	AssetCodeMirrorJavaScript:      {url: codeMirrorCDN + "mode/javascript/javascript.min.js"},
+	AssetCodeMirrorLint:            {url: codeMirrorCDN + "addon/lint/lint.min.js"},
+	AssetCodeMirrorJSONLint:        {url: codeMirrorCDN + "addon/lint/json-lint.min.js"},
+	AssetCodeMirrorCloseBrackets:   {url: codeMirrorCDN + "addon/edit/closebrackets.min.js"},
+	AssetCodeMirrorMatchBrackets:   {url: codeMirrorCDN + "addon/edit/matchbrackets.min.js"},
+	AssetCodeMirrorFoldCode:        {url: codeMirrorCDN + "addon/fold/foldcode.min.js"},
+	AssetCodeMirrorFoldGutter:      {url: codeMirrorCDN + "addon/fold/foldgutter.min.js"},
+	AssetCodeMirrorBraceFold:       {url: codeMirrorCDN + "addon/fold/brace-fold.min.js"},
+	AssetJSONLint:                  {url: "https://cdnjs.cloudflare.com/ajax/libs/jsonlint/1.6.0/jsonlint.min.js"},
+	AssetSwaggerUICSS:              {url: "https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui.css", stylesheet: true},
+	AssetSwaggerUIBundle:           {url: "https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui-bundle.js"},
+	AssetSwaggerUIStandalonePreset: {url: "https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui-standalone-preset.js"},
+	AssetScalar:                    {url: "https://cdn.jsdelivr.net/npm/@scalar/api-reference"},
+	AssetRedoc:                     {url: "https://cdn.redoc.ly/redoc/v2.1.3/bundles/redoc.standalone.js"},
+}
+
+// assetIntegrity caches Subresource Integrity hashes computed from asset URLs
+type assetIntegrity struct {
+	mu       sync.Mutex
+	hashes   map[string]string
+	client   *http.Client
+	failures atomic.Uint64 // Failed fetches, so rendered docs missing a hash are rendered again
+}
+
+// hash returns the sha384 integrity of the resource at url, fetching it once.
+// Failures are logged and not cached, so the next render retries.
+func (ai *assetIntegrity) hash(url string) string {
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+	if hash, ok := ai.hashes[url]; ok {
+		return hash
+	}
+
+	hash, err := ai.fetch(url)
+	if err != nil {
+		log.Warnf("notelink: computing integrity of %s: %v", url, err)
+		ai.failures.Add(1)
+		return ""
+	}
+	if ai.hashes == nil {
+		ai.hashes = make(map[string]string)
+	}
+	ai.hashes[url] = hash
+	return hash
+}
+
+// fetch downloads url and returns its sha384 integrity
+func (ai *assetIntegrity) fetch(url string) (string, error) {
+	client := ai.client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	digest := sha512.New384()
+	if _, err := io.Copy(digest, resp.Body); err != nil {
+		return "", err
+	}
+	return "sha384-" + base64.StdEncoding.EncodeToString(digest.Sum(nil)), nil
+}
+
+// asset resolves an asset's URL and integrity from Config.Assets and the
+// defaults. With Config.AssetIntegrity, missing hashes are computed by fetching
+// the asset, except for the browser-dependent Google Fonts stylesheet.
+func (an *ApiNote) asset(name string) Asset {
+	def := defaultAssets[name]
+	asset := an.config.Assets[name]
+	if asset.URL == "" {
+		asset.URL = def.url
+	} else {
+		def.dynamic = false
+	}
+	if asset.Integrity == "" && an.config.AssetIntegrity && !def.dynamic {
+		asset.Integrity = an.assetIntegrity.hash(asset.URL)
+	}
+	return asset
+}
+
+// assetTag renders the link or script tag that loads an asset
+func (an *ApiNote) assetTag(name string) string {
+	asset := an.asset(name)
+	attrs := ""
+	if asset.Integrity != "" {
+		attrs = ` integrity="` + escapeHTML(asset.Integrity) + `" crossorigin="anonymous"`
+	}
+	if defaultAssets[name].stylesheet {
+		return `<link rel="stylesheet" href="` + escapeHTML(asset.URL) + `"` + attrs + `>`
+	}
+	return `<script src="` + escapeHTML(asset.URL) + `"` + attrs + `></script>`
+}
@@ -0,0 +1,76 @@
+package notelink
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestAssets tests asset URL overrides and Subresource Integrity attributes
+func TestAssets(t *testing.T) {
+	var fetches atomic.Int32
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if r.URL.Path == "/missing.js" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("/* " + r.URL.Path + " */"))
+	}))
+	defer cdn.Close()
+
+	integrity := func(body string) string {
+		digest := sha512.Sum384([]byte(body))
+		return "sha384-" + base64.StdEncoding.EncodeToString(digest[:])
+	}
+
+	api := NewApiNote(&Config{
+		Title:          "Test API",
+		Host:           "localhost:8080",
+		AssetIntegrity: true,
+		Assets: map[string]Asset{
+			AssetSwaggerUICSS:              {URL: cdn.URL + "/swagger-ui.css"},
+			AssetSwaggerUIBundle:           {URL: cdn.URL + "/bundle.js"},
+			AssetSwaggerUIStandalonePreset: {URL: cdn.URL + "/missing.js"},
+			AssetScalar:                    {URL: "/static/scalar.js", Integrity: "sha384-pinned"},
+		},
+	}, "secret")
+
+	swagger := api.generateSwaggerHTML()
+	tests := []struct {
+		name    string
+		html    string
+		snippet string
+	}{
+		{name: "Computed stylesheet hash", html: swagger, snippet: `<link rel="stylesheet" href="` + cdn.URL + `/swagger-ui.css" integrity="` + integrity("/* /swagger-ui.css */") + `" crossorigin="anonymous">`},
+		{name: "Computed script hash", html: swagger, snippet: `<script src="` + cdn.URL + `/bundle.js" integrity="` + integrity("/* /bundle.js */") + `" crossorigin="anonymous"></script>`},
+		{name: "Failed fetch omits integrity", html: swagger, snippet: `<script src="` + cdn.URL + `/missing.js"></script>`},
+		{name: "Configured hash", html: api.generateScalarHTML(), snippet: `<script src="/static/scalar.js" integrity="sha384-pinned" crossorigin="anonymous"></script>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(tt.html, tt.snippet) {
+				t.Errorf("Expected page to contain %s", tt.snippet)
+			}
+		})
+	}
+
+	before := fetches.Load()
+	api.generateSwaggerHTML()
+	if fetched := fetches.Load() - before; fetched != 1 {
+		t.Errorf("Expected only the failed asset to be fetched again, got %d fetches", fetched)
+	}
+
+	defaults := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	html := defaults.generateHTML()
+	if !strings.Contains(html, `<script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/codemirror.min.js"></script>`) {
+		t.Error("Expected default CDN assets without integrity")
+	}
+	if !strings.Contains(html, `href="https://fonts.googleapis.com/css2?family=Inter:wght@300;400;500;600;700&amp;family=JetBrains+Mono:wght@400;500&amp;display=swap"`) {
+		t.Error("Expected the escaped fonts stylesheet URL")
+	}
+}
@@ -0,0 +1,214 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ChannelDirection tells whether the service sends or receives a channel's messages
+type ChannelDirection string
+
+const (
+	ChannelPublish   ChannelDirection = "publish"   // The service publishes messages to the channel
+	ChannelSubscribe ChannelDirection = "subscribe" // The service consumes messages from the channel
+)
+
+// Channel is a documented message channel, such as a Kafka topic or NATS subject
+type Channel struct {
+	Topic     string
+	Payload   interface{}
+	Direction ChannelDirection
+}
+
+// AsyncAPI 3.0 document structures
+type AsyncAPISpec struct {
+	AsyncAPI   string                       `json:"asyncapi"`
+	Info       OpenAPIInfo                  `json:"info"`
+	Channels   map[string]AsyncAPIChannel   `json:"channels"`
+	Operations map[string]AsyncAPIOperation `json:"operations"`
+	Components *AsyncAPIComponents          `json:"components,omitempty"`
+}
+
+type AsyncAPIChannel struct {
+	Address  string                     `json:"address"`
+	Messages map[string]AsyncAPIMessage `json:"messages"`
+}
+
+type AsyncAPIMessage struct {
+	Name        string      `json:"name,omitempty"`
+	ContentType string      `json:"contentType,omitempty"`
+	Payload     *JSONSchema `json:"payload"`
+}
+
+type AsyncAPIOperation struct {
+	Action   string        `json:"action"` // "send" or "receive"
+	Channel  AsyncAPIRef   `json:"channel"`
+	Messages []AsyncAPIRef `json:"messages"`
+}
+
+type AsyncAPIRef struct {
+	Ref string `json:"$ref"`
+}
+
+type AsyncAPIComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas,omitempty"`
+}
+
+// DocumentChannel documents a message channel: the topic or subject, the Go type
+// of its payload and whether the service publishes or subscribes to it. Channels
+// are listed in the HTML docs and described by GenerateAsyncAPISpec.
+func (an *ApiNote) DocumentChannel(topic string, payloadSchema interface{}, direction ChannelDirection) error {
+	if topic == "" {
+		return fmt.Errorf("topic is required")
+	}
+	if direction != ChannelPublish && direction != ChannelSubscribe {
+		return fmt.Errorf("invalid channel direction %q: use ChannelPublish or ChannelSubscribe", direction)
+	}
+	if err := an.checkSchemaNames(payloadSchema); err != nil {
+		return err
+	}
+	if err := an.checkSchemaTypes("channel "+topic, payloadSchema); err != nil {
+		return err
+	}
+
+	channel := Channel{Topic: topic, Payload: payloadSchema, Direction: direction}
+	for i := range an.channels {
+		if an.channels[i].Topic == topic && an.channels[i].Direction == direction {
+			an.channels[i] = channel
+			an.artifacts.invalidate()
+			return nil
+		}
+	}
+	an.channels = append(an.channels, channel)
+	an.artifacts.invalidate()
+	return nil
+}
+
+// channelID returns the AsyncAPI channel id of a topic
+// Example: orders.created -> ordersCreated
+func channelID(topic string) string {
+	id := identifierName(topic)
+	if id == "" {
+		return "channel"
+	}
+	return strings.ToLower(id[:1]) + id[1:]
+}
+
+// action returns the AsyncAPI operation action of the direction
+func (d ChannelDirection) action() string {
+	if d == ChannelPublish {
+		return "send"
+	}
+	return "receive"
+}
+
+// messageName returns the name of a channel's message: the payload's type name
+// or, for anonymous payloads, the channel id
+func (an *ApiNote) messageName(channel *Channel) string {
+	typ := reflect.TypeOf(channel.Payload)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) {
+		typ = typ.Elem()
+	}
+	if typ != nil && typ.Kind() == reflect.Struct && typ.Name() != "" {
+		return an.schemaNames().name(typ)
+	}
+	return toTitle(channelID(channel.Topic)) + "Message"
+}
+
+// GenerateAsyncAPISpec creates an AsyncAPI 3.0 document from the documented channels
+func (an *ApiNote) GenerateAsyncAPISpec() *AsyncAPISpec {
+	spec := &AsyncAPISpec{
+		AsyncAPI: "3.0.0",
+		Info: OpenAPIInfo{
+			Title:       an.config.Title,
+			Description: an.config.Description,
+			Version:     an.config.Version,
+		},
+		Channels:   make(map[string]AsyncAPIChannel),
+		Operations: make(map[string]AsyncAPIOperation),
+		Components: &AsyncAPIComponents{Schemas: make(map[string]*JSONSchema)},
+	}
+
+	for i := range an.channels {
+		channel := &an.channels[i]
+		id := channelID(channel.Topic)
+		message := an.messageName(channel)
+
+		specChannel, ok := spec.Channels[id]
+		if !ok {
+			specChannel = AsyncAPIChannel{Address: channel.Topic, Messages: make(map[string]AsyncAPIMessage)}
+		}
+		specChannel.Messages[message] = AsyncAPIMessage{
+			Name:        message,
+			ContentType: "application/json",
+			Payload:     an.bodySchema(message, channel.Payload, spec.Components.Schemas),
+		}
+		spec.Channels[id] = specChannel
+
+		action := channel.Direction.action()
+		spec.Operations[action+toTitle(id)] = AsyncAPIOperation{
+			Action:   action,
+			Channel:  AsyncAPIRef{Ref: "#/channels/" + id},
+			Messages: []AsyncAPIRef{{Ref: "#/channels/" + id + "/messages/" + message}},
+		}
+	}
+
+	if len(spec.Components.Schemas) == 0 {
+		spec.Components = nil
+	}
+	return spec
+}
+
+// ExportAsyncAPIToFile exports the AsyncAPI document to a JSON file
+func (an *ApiNote) ExportAsyncAPIToFile(filepath string) error {
+	data, err := json.MarshalIndent(an.GenerateAsyncAPISpec(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal AsyncAPI spec: %w", err)
+	}
+
+	if err := os.WriteFile(filepath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// renderChannels renders the Events section of the docs and its table of contents entries
+func (an *ApiNote) renderChannels() (string, string) {
+	if len(an.channels) == 0 {
+		return "", ""
+	}
+
+	var html, toc strings.Builder
+	html.WriteString(`
+    <details class="top-segment-group" id="events">
+        <summary>Events</summary>`)
+	toc.WriteString(`
+                <li><a href="#events">Events</a><ul>`)
+	for i := range an.channels {
+		channel := &an.channels[i]
+		anchor := "event-" + string(channel.Direction) + "-" + channelID(channel.Topic)
+		label := strings.ToUpper(string(channel.Direction))
+		toc.WriteString(`
+                    <li><a class="toc-endpoint" href="#` + anchor + `"><span class="toc-method ` + label + `">` + label + `</span>` + escapeHTML(channel.Topic) + `</a></li>`)
+		html.WriteString(`
+        <details class="method-group" id="` + anchor + `">
+            <summary>
+                <span class="method ` + label + `">` + label + `</span>
+                <span class="endpoint-path">` + escapeHTML(channel.Topic) + `</span>
+            </summary>
+            <div>
+                <div class="schemas">
+                    <h4>Message:</h4>` +
+			renderSchemaViews("Payload", an.messageName(channel), channel.Payload, an.exampleOptions(), an.schemaNames()) + `
+                </div>
+            </div>
+        </details>`)
+	}
+	html.WriteString(`
+    </details>`)
+	toc.WriteString(`</ul></li>`)
+	return html.String(), toc.String()
+}
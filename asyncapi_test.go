@@ -0,0 +1,75 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+)
+
+type orderCreated struct {
+	OrderID string        `json:"orderId"`
+	Address exportAddress `json:"address"`
+}
+
+// TestAsyncAPI tests documenting message channels
+func TestAsyncAPI(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Orders API", Version: "1.0.0"}, "secret")
+	if err := api.DocumentChannel("orders.created", orderCreated{}, ChannelPublish); err != nil {
+		t.Fatalf("Failed to document channel: %v", err)
+	}
+	if err := api.DocumentChannel("payments.settled", struct {
+		Amount int `json:"amount"`
+	}{}, ChannelSubscribe); err != nil {
+		t.Fatalf("Failed to document channel: %v", err)
+	}
+	if err := api.DocumentChannel("orders.created", orderCreated{}, "broadcast"); err == nil {
+		t.Error("Expected an error for an invalid direction")
+	}
+
+	spec := api.GenerateAsyncAPISpec()
+	if spec.AsyncAPI != "3.0.0" || spec.Info.Version != "1.0.0" {
+		t.Errorf("Unexpected document header: %s %+v", spec.AsyncAPI, spec.Info)
+	}
+
+	tests := []struct {
+		operation string
+		action    string
+		channel   string
+		address   string
+		message   string
+	}{
+		{operation: "sendOrdersCreated", action: "send", channel: "ordersCreated", address: "orders.created", message: "orderCreated"},
+		{operation: "receivePaymentsSettled", action: "receive", channel: "paymentsSettled", address: "payments.settled", message: "PaymentsSettledMessage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			operation, ok := spec.Operations[tt.operation]
+			if !ok {
+				t.Fatalf("Expected operation %s, got %v", tt.operation, spec.Operations)
+			}
+			if operation.Action != tt.action || operation.Channel.Ref != "#/channels/"+tt.channel {
+				t.Errorf("Unexpected operation: %+v", operation)
+			}
+			channel := spec.Channels[tt.channel]
+			if channel.Address != tt.address {
+				t.Errorf("Expected address %s, got %s", tt.address, channel.Address)
+			}
+			if channel.Messages[tt.message].Payload == nil {
+				t.Errorf("Expected message %s with a payload, got %v", tt.message, channel.Messages)
+			}
+		})
+	}
+
+	if payload := spec.Channels["ordersCreated"].Messages["orderCreated"].Payload; payload.Ref != "#/components/schemas/orderCreated" {
+		t.Errorf("Expected named payloads to reference a component, got %+v", payload)
+	}
+	if _, ok := spec.Components.Schemas["exportAddress"]; !ok {
+		t.Error("Expected nested payload structs in components")
+	}
+
+	html := api.generateHTML()
+	for _, snippet := range []string{`id="events"`, `id="event-publish-ordersCreated"`, `<span class="method SUBSCRIBE">SUBSCRIBE</span>`, "payments.settled"} {
+		if !strings.Contains(html, snippet) {
+			t.Errorf("Expected docs to contain %q", snippet)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package notelink
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DefaultAccessTokenTTL is the lifetime of issued access tokens
+	DefaultAccessTokenTTL = 15 * time.Minute
+	// DefaultRefreshTokenTTL is the lifetime of issued refresh tokens
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+	// DefaultTokenPath is where the token endpoints are mounted, relative to
+	// Config.BasePath
+	DefaultTokenPath = "/auth"
+)
+
+// tokenUseClaim tells refresh tokens apart, so they aren't accepted as access tokens
+const tokenUseClaim = "token_use"
+
+// ErrInvalidCredentials is returned by a CredentialVerifier for wrong credentials
+var ErrInvalidCredentials = errors.New("notelink: invalid credentials")
+
+// CredentialVerifier checks the credentials of a token request and returns
+// the token's subject and extra claims, e.g. {"role": "admin"}. Return
+// ErrInvalidCredentials for wrong credentials; other errors respond 500.
+type CredentialVerifier func(c fiber.Ctx, username, password string) (subject string, claims map[string]interface{}, err error)
+
+// TokenConfig mounts documented endpoints issuing the JWTs JWTMiddleware
+// accepts, signed with the ApiNote's secret: POST {Path}/token exchanges
+// credentials for an access and a refresh token, POST {Path}/refresh exchanges
+// a refresh token for new ones. Refreshed tokens keep the claims of the
+// credentials check unless Refresh is set.
+type TokenConfig struct {
+	Verify     CredentialVerifier // Required
+	AccessTTL  time.Duration      // Defaults to DefaultAccessTokenTTL
+	RefreshTTL time.Duration      // Defaults to DefaultRefreshTokenTTL
+	Issuer     string             // iss claim, omitted when empty
+	Path       string             // Defaults to DefaultTokenPath
+	// Refresh reloads the claims of a subject on refresh, e.g. to drop removed
+	// roles, or rejects it with ErrInvalidCredentials, e.g. for disabled users
+	Refresh func(c fiber.Ctx, subject string) (claims map[string]interface{}, err error)
+}
+
+// TokenRequest exchanges credentials for tokens
+type TokenRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// RefreshRequest exchanges a refresh token for new tokens
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponse carries issued tokens, as in OAuth 2.0 token responses
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // Seconds until the access token expires
+	RefreshToken string `json:"refresh_token"`
+}
+
+// accessTTL returns the access token lifetime
+func (t *TokenConfig) accessTTL() time.Duration {
+	if t.AccessTTL <= 0 {
+		return DefaultAccessTokenTTL
+	}
+	return t.AccessTTL
+}
+
+// refreshTTL returns the refresh token lifetime
+func (t *TokenConfig) refreshTTL() time.Duration {
+	if t.RefreshTTL <= 0 {
+		return DefaultRefreshTokenTTL
+	}
+	return t.RefreshTTL
+}
+
+// path returns the mount path of the token endpoints
+func (t *TokenConfig) path() string {
+	if t.Path == "" {
+		return DefaultTokenPath
+	}
+	return t.Path
+}
+
+// IssueTokens signs an access and a refresh token for subject with the extra
+// claims, as the token endpoints do. Config.Tokens sets the lifetimes and issuer.
+func (an *ApiNote) IssueTokens(subject string, claims map[string]interface{}) (*TokenResponse, error) {
+	config := an.config.Tokens
+	if config == nil {
+		config = &TokenConfig{}
+	}
+	now := time.Now()
+	sign := func(ttl time.Duration, use string) (string, error) {
+		token := jwt.MapClaims{}
+		for name, value := range claims {
+			token[name] = value
+		}
+		token["sub"] = subject
+		token["iat"] = now.Unix()
+		token["exp"] = now.Add(ttl).Unix()
+		if config.Issuer != "" {
+			token["iss"] = config.Issuer
+		}
+		if use != "" {
+			token[tokenUseClaim] = use
+		}
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, token).SignedString([]byte(an.jwtSecret))
+	}
+
+	access, err := sign(config.accessTTL(), "")
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := sign(config.refreshTTL(), "refresh")
+	if err != nil {
+		return nil, err
+	}
+	return &TokenResponse{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(config.accessTTL().Seconds()),
+		RefreshToken: refresh,
+	}, nil
+}
+
+// mountTokenEndpoints registers the documented token endpoints
+func (an *ApiNote) mountTokenEndpoints() {
+	if an.config.Tokens.Verify == nil {
+		log.Errorf("notelink: Config.Tokens.Verify is required, the token endpoints aren't mounted")
+		return
+	}
+	noAuth := false
+	path := an.config.Tokens.path()
+	routes := []DocumentedRouteInput{
+		{
+			Method:          http.MethodPost,
+			Path:            path + "/token",
+			Description:     fmt.Sprintf("Exchange credentials for a bearer access token, valid for %s, and a refresh token", an.config.Tokens.accessTTL()),
+			Responses:       map[string]string{"200": "Tokens issued", "401": "Invalid credentials"},
+			SchemasRequest:  TokenRequest{},
+			SchemasResponse: TokenResponse{},
+			Handler:         an.tokenHandler,
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            path + "/refresh",
+			Description:     fmt.Sprintf("Exchange a refresh token, valid for %s, for new tokens", an.config.Tokens.refreshTTL()),
+			Responses:       map[string]string{"200": "Tokens issued", "401": "Invalid or expired refresh token"},
+			SchemasRequest:  RefreshRequest{},
+			SchemasResponse: TokenResponse{},
+			Handler:         an.refreshTokenHandler,
+		},
+	}
+	for i := range routes {
+		routes[i].AuthRequired = &noAuth
+		routes[i].Tags = []string{"auth"}
+		routes[i].builtin = true
+		if err := an.DocumentedRoute(&routes[i]); err != nil {
+			log.Errorf("notelink: registering %s %s: %v", routes[i].Method, routes[i].Path, err)
+		}
+	}
+}
+
+// tokenHandler issues tokens for valid credentials
+func (an *ApiNote) tokenHandler(c fiber.Ctx) error {
+	var req TokenRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid token request")
+	}
+	subject, claims, err := an.config.Tokens.Verify(c, req.Username, req.Password)
+	if errors.Is(err, ErrInvalidCredentials) {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
+	}
+	if err != nil {
+		return err
+	}
+	tokens, err := an.IssueTokens(subject, claims)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(tokens)
+}
+
+// refreshTokenHandler issues new tokens for a valid refresh token
+func (an *ApiNote) refreshTokenHandler(c fiber.Ctx) error {
+	var req RefreshRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid refresh request")
+	}
+	claims, err := an.parseToken(req.RefreshToken)
+	if err != nil || claims[tokenUseClaim] != "refresh" {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired refresh token")
+	}
+	subject, _ := claims.GetSubject()
+
+	extra := map[string]interface{}{}
+	if refresh := an.config.Tokens.Refresh; refresh != nil {
+		reloaded, err := refresh(c, subject)
+		if errors.Is(err, ErrInvalidCredentials) {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired refresh token")
+		}
+		if err != nil {
+			return err
+		}
+		extra = reloaded
+	} else {
+		for name, value := range claims {
+			switch name {
+			case "sub", "iat", "exp", "nbf", "iss", tokenUseClaim:
+			default:
+				extra[name] = value
+			}
+		}
+	}
+
+	tokens, err := an.IssueTokens(subject, extra)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(tokens)
+}
+
+// parseToken verifies a token signed with the ApiNote's secret and returns its claims
+func (an *ApiNote) parseToken(tokenStr string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(an.jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	return claims, nil
+}
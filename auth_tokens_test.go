@@ -0,0 +1,119 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TestTokenEndpoints tests issuing and refreshing JWTs accepted by UseJWT
+func TestTokenEndpoints(t *testing.T) {
+	disabled := false
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		Tokens: &TokenConfig{
+			AccessTTL: time.Minute,
+			Issuer:    "orders-api",
+			Verify: func(c fiber.Ctx, username, password string) (string, map[string]interface{}, error) {
+				if username != "ada" || password != "s3cret" {
+					return "", nil, ErrInvalidCredentials
+				}
+				return "user-1", map[string]interface{}{"role": "admin"}, nil
+			},
+			Refresh: func(c fiber.Ctx, subject string) (map[string]interface{}, error) {
+				if disabled {
+					return nil, ErrInvalidCredentials
+				}
+				return map[string]interface{}{"role": "viewer"}, nil
+			},
+		},
+	}, "secret")
+	api.UseJWT()
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/me",
+		Description: "Current user",
+		Handler:     func(c fiber.Ctx) error { return c.SendString(c.Locals("user_id").(string)) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	post := func(path string, body interface{}) (int, TokenResponse) {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", path, bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		defer resp.Body.Close()
+		var tokens TokenResponse
+		json.NewDecoder(resp.Body).Decode(&tokens) //nolint:errcheck // empty on errors
+		return resp.StatusCode, tokens
+	}
+	me := func(token string) int {
+		req := httptest.NewRequest("GET", "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status, _ := post("/auth/token", TokenRequest{Username: "ada", Password: "wrong"}); status != 401 {
+		t.Errorf("Expected 401 for wrong credentials, got %d", status)
+	}
+	status, tokens := post("/auth/token", TokenRequest{Username: "ada", Password: "s3cret"})
+	if status != 200 || tokens.TokenType != "Bearer" || tokens.ExpiresIn != 60 {
+		t.Fatalf("Expected tokens, got %d %+v", status, tokens)
+	}
+	claims, err := api.parseToken(tokens.AccessToken)
+	if err != nil || claims["sub"] != "user-1" || claims["role"] != "admin" || claims["iss"] != "orders-api" {
+		t.Errorf("Expected the verifier's claims in the access token, got %v %v", claims, err)
+	}
+	if got := me(tokens.AccessToken); got != 200 {
+		t.Errorf("Expected the access token accepted, got %d", got)
+	}
+	if got := me(tokens.RefreshToken); got != 401 {
+		t.Errorf("Expected the refresh token rejected as an access token, got %d", got)
+	}
+	// Access tokens of other issuers may carry their own token_use
+	external, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user-2", tokenUseClaim: "access", "exp": time.Now().Add(time.Minute).Unix()}).SignedString([]byte("secret"))
+	if got := me(external); got != 200 {
+		t.Errorf("Expected an access token with token_use access accepted, got %d", got)
+	}
+
+	if status, _ := post("/auth/refresh", RefreshRequest{RefreshToken: tokens.AccessToken}); status != 401 {
+		t.Errorf("Expected an access token rejected for refresh, got %d", status)
+	}
+	status, refreshed := post("/auth/refresh", RefreshRequest{RefreshToken: tokens.RefreshToken})
+	if status != 200 {
+		t.Fatalf("Expected refreshed tokens, got %d", status)
+	}
+	if claims, _ := api.parseToken(refreshed.AccessToken); claims["role"] != "viewer" || claims["sub"] != "user-1" {
+		t.Errorf("Expected the reloaded claims, got %v", claims)
+	}
+	disabled = true
+	if status, _ := post("/auth/refresh", RefreshRequest{RefreshToken: tokens.RefreshToken}); status != 401 {
+		t.Errorf("Expected refresh rejected for a disabled user, got %d", status)
+	}
+
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user-1", tokenUseClaim: "refresh", "exp": time.Now().Add(-time.Minute).Unix()}).SignedString([]byte("secret"))
+	if status, _ := post("/auth/refresh", RefreshRequest{RefreshToken: expired}); status != 401 {
+		t.Errorf("Expected an expired refresh token rejected, got %d", status)
+	}
+
+	operation := api.GenerateOpenAPISpec().Paths["/auth/token"].Post
+	if operation == nil || len(operation.Security) != 0 || operation.Responses["401"].Description == "" {
+		t.Errorf("Expected the token endpoint documented without auth, got %+v", operation)
+	}
+}
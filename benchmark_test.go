@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
 )
 
 // Benchmark structures
@@ -98,6 +99,86 @@ func BenchmarkValidateParametersLarge(b *testing.B) {
 	}
 }
 
+// benchParameters are the parameters of the validation fast path benchmarks
+var benchParameters = []Parameter{
+	{Name: "id", In: "query", Type: "integer", Required: true},
+	{Name: "name", In: "query", Type: "string", Required: true},
+	{Name: "age", In: "query", Type: "Integer", Required: false},
+	{Name: "active", In: "query", Type: "boolean", Required: false},
+	{Name: "score", In: "query", Type: "number", Required: false},
+	{Name: "token", In: "header", Type: "string", Required: false},
+}
+
+// benchParametersCtx returns a context with valid values for benchParameters
+func benchParametersCtx(app *fiber.App) fiber.Ctx {
+	fctx := &fasthttp.RequestCtx{}
+	fctx.Request.SetRequestURI("/test?id=123456&name=john&age=25&active=true&score=95.5")
+	fctx.Request.Header.Set("token", "abc123")
+	return app.AcquireCtx(fctx)
+}
+
+// BenchmarkValidateParametersFastPath benchmarks validating valid parameters
+// without the HTTP round trip, where any allocation shows
+func BenchmarkValidateParametersFastPath(b *testing.B) {
+	app := fiber.New()
+	c := benchParametersCtx(app)
+	defer app.ReleaseCtx(c)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ValidateParameters(c, benchParameters); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParameterValidator benchmarks validating valid parameters with
+// descriptors parsed once, as routes do
+func BenchmarkParameterValidator(b *testing.B) {
+	app := fiber.New()
+	c := benchParametersCtx(app)
+	defer app.ReleaseCtx(c)
+	validator := NewParameterValidator(benchParameters)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := validator.Validate(c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestValidateParametersAllocations tests that valid parameters validate without allocating
+func TestValidateParametersAllocations(t *testing.T) {
+	app := fiber.New()
+	c := benchParametersCtx(app)
+	defer app.ReleaseCtx(c)
+	validator := NewParameterValidator(benchParameters)
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = ValidateParameters(c, benchParameters) }); allocs != 0 {
+		t.Errorf("Expected ValidateParameters not to allocate, got %.0f allocations", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _ = validator.Validate(c) }); allocs != 0 {
+		t.Errorf("Expected ParameterValidator not to allocate, got %.0f allocations", allocs)
+	}
+
+	// Errors are copied out of the pooled slice, so later requests don't overwrite them
+	invalid := &fasthttp.RequestCtx{}
+	invalid.Request.SetRequestURI("/test?id=abc&age=x")
+	ic := app.AcquireCtx(invalid)
+	defer app.ReleaseCtx(ic)
+	first := validator.Validate(ic).(*ValidationErrorResponse)
+	second := ValidateParameters(ic, benchParameters[:1]).(*ValidationErrorResponse)
+	if len(first.Errors) != 3 || first.Errors[0].Field != "id" || first.Errors[1].Field != "name" || first.Errors[2].Field != "age" {
+		t.Errorf("Expected errors for id, name and age, got %+v", first.Errors)
+	}
+	if len(second.Errors) != 1 || second.Errors[0].Type != "type_error" {
+		t.Errorf("Expected a type error for id, got %+v", second.Errors)
+	}
+}
+
 // BenchmarkValidateRequestBodySimple benchmarks simple struct validation
 func BenchmarkValidateRequestBodySimple(b *testing.B) {
 	app := fiber.New()
@@ -164,11 +245,64 @@ func BenchmarkValidateRequestBodyNested(b *testing.B) {
 	}
 }
 
+// BenchmarkValidateJSON benchmarks validating raw JSON bytes without a request
+func BenchmarkValidateJSON(b *testing.B) {
+	body := []byte(`{"id":1,"name":"John","email":"john@example.com","age":25,"is_active":true,"salary":50000.50}`)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ValidateJSON(body, BenchUser{}, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchLargeOrder returns a multi-MB order body
+func benchLargeOrder() []byte {
+	var body bytes.Buffer
+	body.WriteString(`{"id":1,"user_id":123,"total":299.97,"customer":{"id":123,"name":"John Doe","email":"john@example.com","age":30,"is_active":true,"salary":75000.00},"items":[`)
+	for i := 0; i < 30000; i++ {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.WriteString(`{"id":1,"name":"Item","price":99.99,"quantity":1}`)
+	}
+	body.WriteString(`]}`)
+	return body.Bytes()
+}
+
+// BenchmarkValidateJSONLarge benchmarks validating a multi-MB body decoded into a map
+func BenchmarkValidateJSONLarge(b *testing.B) {
+	body := benchLargeOrder()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ValidateJSON(body, BenchOrder{}, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkValidateJSONStreamLarge benchmarks validating a multi-MB body while tokenizing it
+func BenchmarkValidateJSONStreamLarge(b *testing.B) {
+	body := benchLargeOrder()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(body)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := ValidateJSONStream(bytes.NewReader(body), BenchOrder{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // BenchmarkGenerateTypeScriptSchemaSimple benchmarks simple TypeScript generation
 func BenchmarkGenerateTypeScriptSchemaSimple(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = generateTypeScriptSchema("User", BenchUser{})
+		_ = generateTypeScriptSchema("User", BenchUser{}, "", nil)
 	}
 }
 
@@ -176,7 +310,7 @@ func BenchmarkGenerateTypeScriptSchemaSimple(b *testing.B) {
 func BenchmarkGenerateTypeScriptSchemaNested(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = generateTypeScriptSchema("Order", BenchOrder{})
+		_ = generateTypeScriptSchema("Order", BenchOrder{}, "", nil)
 	}
 }
 
@@ -184,7 +318,7 @@ func BenchmarkGenerateTypeScriptSchemaNested(b *testing.B) {
 func BenchmarkGenerateJSONTemplateSimple(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := generateJSONTemplate(BenchUser{})
+		_, err := generateJSONTemplate(BenchUser{}, exampleOptions{})
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -195,7 +329,7 @@ func BenchmarkGenerateJSONTemplateSimple(b *testing.B) {
 func BenchmarkGenerateJSONTemplateNested(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := generateJSONTemplate(BenchOrder{})
+		_, err := generateJSONTemplate(BenchOrder{}, exampleOptions{})
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -0,0 +1,286 @@
+package notelink
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	// DefaultCallbackRelayPath is where the relay receives callbacks unless
+	// CallbackRelayConfig.Path says otherwise
+	DefaultCallbackRelayPath = "/callback-relay"
+	// DefaultCallbackRelayTTL is how long a receive URL stays open
+	DefaultCallbackRelayTTL = 15 * time.Minute
+	// DefaultCallbackRelayMaxBodySize is the size callback bodies are
+	// truncated to before they are relayed
+	DefaultCallbackRelayMaxBodySize = 64 << 10
+)
+
+// maxCallbackRelayChannels caps the receive URLs open at once
+const maxCallbackRelayChannels = 100
+
+// CallbackRelayConfig configures the callback relay of the try-it forms (see
+// Config.CallbackRelay). Routes set DocumentedRouteInput.CallbackURLField to
+// name the body field taking the URL they call back; their try-it form opens
+// a temporary receive URL over a WebSocket, fills it in and shows the
+// callbacks it receives live, so async flows can be tried without a tunnel.
+// Anyone knowing a receive URL can post to it while it is open, so leave the
+// relay off in production.
+type CallbackRelayConfig struct {
+	// Path serves the receive URLs, <Path>/<channel id>, outside the docs path
+	// so the services calling back need no docs credentials.
+	// Defaults to DefaultCallbackRelayPath.
+	Path string
+	// TTL closes receive URLs after this long (default DefaultCallbackRelayTTL)
+	TTL time.Duration
+	// MaxBodySize truncates relayed callback bodies (default DefaultCallbackRelayMaxBodySize)
+	MaxBodySize int
+}
+
+// path returns the configured receive path or the default
+func (r *CallbackRelayConfig) path() string {
+	if r.Path == "" {
+		return DefaultCallbackRelayPath
+	}
+	return "/" + strings.Trim(r.Path, "/")
+}
+
+// ttl returns the configured TTL or the default
+func (r *CallbackRelayConfig) ttl() time.Duration {
+	if r.TTL <= 0 {
+		return DefaultCallbackRelayTTL
+	}
+	return r.TTL
+}
+
+// maxBodySize returns the configured body size limit or the default
+func (r *CallbackRelayConfig) maxBodySize() int {
+	if r.MaxBodySize <= 0 {
+		return DefaultCallbackRelayMaxBodySize
+	}
+	return r.MaxBodySize
+}
+
+// relayedCallback is a callback request relayed to the docs
+type relayedCallback struct {
+	Type       string            `json:"type"` // "callback"
+	Method     string            `json:"method"`
+	Query      string            `json:"query,omitempty"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+	Truncated  bool              `json:"truncated,omitempty"`
+	ReceivedAt time.Time         `json:"receivedAt"`
+}
+
+// callbackRelay holds the open receive URLs and the sockets they relay to
+type callbackRelay struct {
+	mu       sync.Mutex
+	channels map[string]chan relayedCallback
+}
+
+// open registers a receive URL, returning its channel id, or false when too
+// many are open
+func (r *callbackRelay) open() (string, chan relayedCallback, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.channels) >= maxCallbackRelayChannels {
+		return "", nil, false
+	}
+	if r.channels == nil {
+		r.channels = make(map[string]chan relayedCallback)
+	}
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	channel := make(chan relayedCallback, 16)
+	r.channels[hex.EncodeToString(id)] = channel
+	return hex.EncodeToString(id), channel, true
+}
+
+// close removes a receive URL
+func (r *callbackRelay) close(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.channels, id)
+}
+
+// channel returns the channel of an open receive URL
+func (r *callbackRelay) channel(id string) (chan relayedCallback, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	channel, ok := r.channels[id]
+	return channel, ok
+}
+
+// mountCallbackRelay serves the receive URLs of the callback relay
+func (an *ApiNote) mountCallbackRelay() {
+	maxBodySize := an.config.CallbackRelay.maxBodySize()
+	an.app.All(an.config.CallbackRelay.path()+"/:id", func(c fiber.Ctx) error {
+		channel, ok := an.callbackRelay.channel(c.Params("id"))
+		if !ok {
+			return c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "Unknown or expired callback relay URL"})
+		}
+
+		// The request's buffers are reused after it, so everything is copied
+		callback := relayedCallback{
+			Type:       "callback",
+			Method:     c.Method(),
+			Query:      string(c.Request().URI().QueryString()),
+			Headers:    make(map[string]string),
+			ReceivedAt: time.Now().UTC(),
+		}
+		for name, values := range c.GetReqHeaders() {
+			callback.Headers[name] = strings.Join(values, ", ")
+		}
+		body := c.Body()
+		if len(body) > maxBodySize {
+			body, callback.Truncated = body[:maxBodySize], true
+		}
+		callback.Body = string(body)
+
+		select {
+		case channel <- callback:
+			return c.JSON(fiber.Map{"relayed": true})
+		default:
+			return c.Status(fiber.StatusTooManyRequests).JSON(ErrorResponse{Error: "Callback relay is busy"})
+		}
+	})
+}
+
+// callbackRelayHandler opens a receive URL for a docs WebSocket and relays
+// the callbacks it receives until the socket closes or the URL expires
+func (an *ApiNote) callbackRelayHandler(c fiber.Ctx) error {
+	if !websocket.FastHTTPIsWebSocketUpgrade(c.RequestCtx()) {
+		return c.Status(fiber.StatusUpgradeRequired).JSON(ErrorResponse{Error: "WebSocket upgrade required"})
+	}
+	id, channel, ok := an.callbackRelay.open()
+	if !ok {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(ErrorResponse{Error: "Too many callback relays are open"})
+	}
+	ttl := an.config.CallbackRelay.ttl()
+	receiveURL := an.baseURL(c) + an.config.CallbackRelay.path() + "/" + id
+
+	upgrader := websocket.FastHTTPUpgrader{}
+	err := upgrader.Upgrade(c.RequestCtx(), func(conn *websocket.Conn) {
+		defer conn.Close()
+		defer an.callbackRelay.close(id)
+
+		// The docs only listen; reading detects the socket closing
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		expires := time.Now().Add(ttl)
+		if conn.WriteJSON(fiber.Map{"type": "url", "url": receiveURL, "expiresAt": expires.UTC()}) != nil {
+			return
+		}
+		timer := time.NewTimer(ttl)
+		defer timer.Stop()
+		for {
+			select {
+			case callback := <-channel:
+				if conn.WriteJSON(callback) != nil {
+					return
+				}
+			case <-timer.C:
+				_ = conn.WriteJSON(fiber.Map{"type": "expired"})
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "expired"))
+				return
+			case <-closed:
+				return
+			}
+		}
+	})
+	if err != nil {
+		an.callbackRelay.close(id)
+	}
+	return err
+}
+
+// renderCallbackRelay renders the callback receiver of a try-it form
+func (an *ApiNote) renderCallbackRelay(endpoint *Endpoint) string {
+	if an.config.CallbackRelay == nil || endpoint.CallbackURLField == "" {
+		return ""
+	}
+	return `
+                            <div class="callback-relay" data-socket="` + an.docsPath() + `/callback-relay" data-field="` + escapeHTML(endpoint.CallbackURLField) + `">
+                                <h5><i class="fas fa-satellite-dish"></i> Callbacks</h5>
+                                <button type="button" class="json-editor-btn" onclick="toggleCallbackRelay(this)"><i class="fas fa-plug"></i> Receive callbacks</button>
+                                <code class="callback-relay-url"></code>
+                                <div class="callback-relay-log"></div>
+                            </div>`
+}
+
+// callbackRelayScript opens the receive URLs of the try-it forms and lists
+// the callbacks relayed over the WebSocket
+const callbackRelayScript = `
+            // Open a temporary receive URL, fill it into the callback field of
+            // the request body and list the callbacks it receives
+            function toggleCallbackRelay(button) {
+                const relay = button.closest('.callback-relay');
+                if (relay.socket) {
+                    relay.socket.close();
+                    return;
+                }
+                const scheme = window.location.protocol === 'https:' ? 'wss://' : 'ws://';
+                const socket = new WebSocket(scheme + window.location.host + relay.dataset.socket);
+                const url = relay.querySelector('.callback-relay-url');
+                const log = relay.querySelector('.callback-relay-log');
+                relay.socket = socket;
+                button.innerHTML = '<i class="fas fa-stop"></i> Stop receiving';
+                url.textContent = 'Connecting...';
+
+                socket.onmessage = event => {
+                    const message = JSON.parse(event.data);
+                    if (message.type === 'url') {
+                        url.textContent = message.url + ' (open until ' + new Date(message.expiresAt).toLocaleTimeString() + ')';
+                        const editor = relay.closest('form').querySelector('textarea[name="requestBody"]');
+                        if (editor) {
+                            try {
+                                const body = editor.value.trim() ? JSON.parse(editor.value) : {};
+                                body[relay.dataset.field] = message.url;
+                                editor.value = JSON.stringify(body, null, 2);
+                            } catch (e) {
+                                // Leave bodies that aren't JSON objects to the user
+                            }
+                        }
+                    } else if (message.type === 'callback') {
+                        let body = message.body;
+                        try {
+                            body = JSON.stringify(JSON.parse(body), null, 2);
+                        } catch (e) {
+                            // Not JSON, shown as received
+                        }
+                        const headers = Object.keys(message.headers).sort().map(name => name + ': ' + message.headers[name]).join('\n');
+                        const entry = document.createElement('details');
+                        entry.className = 'callback-relay-entry';
+                        entry.open = true;
+                        entry.innerHTML = '<summary>' + escapeHtml(message.method) + ' at ' + escapeHtml(new Date(message.receivedAt).toLocaleTimeString()) +
+                            (message.truncated ? ' (body truncated)' : '') + '</summary><pre>' + escapeHtml(headers) + '\n\n' + escapeHtml(body) + '</pre>';
+                        log.prepend(entry);
+                    } else if (message.type === 'expired') {
+                        url.textContent = 'The receive URL expired';
+                    }
+                };
+                socket.onclose = () => {
+                    relay.socket = null;
+                    button.innerHTML = '<i class="fas fa-plug"></i> Receive callbacks';
+                    if (url.textContent === 'Connecting...') {
+                        url.textContent = 'The callback relay is unavailable';
+                    } else if (!url.textContent.startsWith('The receive URL')) {
+                        url.textContent = 'Stopped receiving callbacks';
+                    }
+                };
+            }
+`
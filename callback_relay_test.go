@@ -0,0 +1,97 @@
+package notelink
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+)
+
+type relaySubscription struct {
+	Event       string `json:"event"`
+	CallbackURL string `json:"callbackUrl"`
+}
+
+// TestCallbackRelay tests relaying callbacks to a docs WebSocket
+func TestCallbackRelay(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	api := NewApiNote(&Config{Title: "Test API", Host: ln.Addr().String(), CallbackRelay: &CallbackRelayConfig{MaxBodySize: 16}}, "secret")
+	err = api.DocumentedRoute(&DocumentedRouteInput{
+		Method:           "POST",
+		Path:             "/v1/subscriptions",
+		SchemasRequest:   relaySubscription{},
+		CallbackURLField: "callbackUrl",
+		Handler:          func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusAccepted) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if html := api.generateHTML(); !strings.Contains(html, `data-socket="/api-docs/callback-relay" data-field="callbackUrl"`) {
+		t.Error("Expected a callback receiver in the try-it form")
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/callback-relay", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusUpgradeRequired {
+		t.Errorf("Expected 426 without a WebSocket upgrade, got %d", resp.StatusCode)
+	}
+
+	go api.Fiber().Listener(ln, fiber.ListenConfig{DisableStartupMessage: true}) //nolint:errcheck // stopped by Shutdown
+	defer api.Fiber().Shutdown()                                                 //nolint:errcheck // best-effort cleanup
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+ln.Addr().String()+"/api-docs/callback-relay", nil)
+	if err != nil {
+		t.Fatalf("Failed to open the relay: %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var opened struct {
+		Type, URL string
+	}
+	if err := conn.ReadJSON(&opened); err != nil || opened.Type != "url" || !strings.HasPrefix(opened.URL, "http://"+ln.Addr().String()+"/callback-relay/") {
+		t.Fatalf("Expected a receive URL, got %+v (%v)", opened, err)
+	}
+
+	callbackResp, err := http.Post(opened.URL+"?attempt=1", "application/json", strings.NewReader(`{"event":"invoice.paid","id":"in_1"}`))
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	callbackResp.Body.Close()
+	if callbackResp.StatusCode != fiber.StatusOK {
+		t.Errorf("Expected the callback to be relayed, got %d", callbackResp.StatusCode)
+	}
+	var callback relayedCallback
+	if err := conn.ReadJSON(&callback); err != nil {
+		t.Fatalf("Expected a relayed callback: %v", err)
+	}
+	if callback.Type != "callback" || callback.Method != "POST" || callback.Query != "attempt=1" ||
+		callback.Body != `{"event":"invoic` || !callback.Truncated || callback.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Unexpected relayed callback %+v", callback)
+	}
+
+	conn.Close()
+	for i := 0; i < 50; i++ {
+		if _, ok := api.callbackRelay.channel(strings.TrimPrefix(opened.URL, "http://"+ln.Addr().String()+"/callback-relay/")); !ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	callbackResp, err = http.Post(opened.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	callbackResp.Body.Close()
+	if callbackResp.StatusCode != fiber.StatusNotFound {
+		t.Errorf("Expected 404 once the relay closed, got %d", callbackResp.StatusCode)
+	}
+}
@@ -0,0 +1,120 @@
+package notelink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CatalogInfo describes the API entity registered in a Backstage software catalog
+type CatalogInfo struct {
+	Name      string   // Entity name; defaults to Config.Title in kebab case
+	Owner     string   // Owning group or user, e.g. "group:payments"; defaults to the team shared by every endpoint's Ownership
+	Lifecycle string   // e.g. "experimental" or "deprecated"; defaults to "production"
+	System    string   // System the API belongs to, if any
+	Tags      []string // Catalog tags
+}
+
+// catalogEntity is a Backstage catalog-info.yaml document
+type catalogEntity struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Metadata   catalogMetadata `json:"metadata"`
+	Spec       catalogSpec     `json:"spec"`
+}
+
+type catalogMetadata struct {
+	Name        string        `json:"name"`
+	Title       string        `json:"title,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Tags        []string      `json:"tags,omitempty"`
+	Links       []catalogLink `json:"links,omitempty"`
+}
+
+type catalogLink struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+type catalogSpec struct {
+	Type       string            `json:"type"`
+	Lifecycle  string            `json:"lifecycle"`
+	Owner      string            `json:"owner"`
+	System     string            `json:"system,omitempty"`
+	Definition map[string]string `json:"definition"`
+}
+
+// GenerateCatalogInfo creates a Backstage catalog-info.yaml API entity from
+// Config.Catalog, whose definition references the served OpenAPI spec
+func (an *ApiNote) GenerateCatalogInfo() ([]byte, error) {
+	return an.generateCatalogInfo(an.baseURL(nil))
+}
+
+// generateCatalogInfo creates the catalog entity with baseURL (scheme and host)
+// as the location of the docs and spec
+func (an *ApiNote) generateCatalogInfo(baseURL string) ([]byte, error) {
+	var info CatalogInfo
+	if an.config.Catalog != nil {
+		info = *an.config.Catalog
+	}
+	if info.Name == "" {
+		info.Name = kebabCase(an.config.Title)
+	}
+	if info.Lifecycle == "" {
+		info.Lifecycle = "production"
+	}
+	if info.Owner == "" {
+		info.Owner = an.sharedTeam()
+	}
+	if info.Owner == "" {
+		return nil, errors.New("catalog owner is required: set Config.Catalog.Owner or the same Ownership.Team on every route")
+	}
+
+	entity := catalogEntity{
+		APIVersion: "backstage.io/v1alpha1",
+		Kind:       "API",
+		Metadata: catalogMetadata{
+			Name:        info.Name,
+			Title:       an.config.Title,
+			Description: an.config.Description,
+			Tags:        info.Tags,
+			Links:       []catalogLink{{URL: baseURL + an.docsPath(), Title: "API documentation"}},
+		},
+		Spec: catalogSpec{
+			Type:       "openapi",
+			Lifecycle:  info.Lifecycle,
+			Owner:      info.Owner,
+			System:     info.System,
+			Definition: map[string]string{"$text": baseURL + an.docsPath() + "/openapi.json"},
+		},
+	}
+	return marshalYAML(entity)
+}
+
+// sharedTeam returns the Ownership.Team of every endpoint when they all agree
+func (an *ApiNote) sharedTeam() string {
+	team := ""
+	for _, endpoint := range an.endpoints {
+		if endpoint.Ownership == nil || endpoint.Ownership.Team == "" {
+			return ""
+		}
+		if team != "" && endpoint.Ownership.Team != team {
+			return ""
+		}
+		team = endpoint.Ownership.Team
+	}
+	return team
+}
+
+// ExportCatalogInfoToFile exports the Backstage catalog entity to a YAML file
+func (an *ApiNote) ExportCatalogInfoToFile(filepath string) error {
+	data, err := an.GenerateCatalogInfo()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
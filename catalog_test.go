@@ -0,0 +1,85 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestCatalogInfo tests the Backstage catalog entity
+func TestCatalogInfo(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return nil }
+	tests := []struct {
+		name     string
+		catalog  *CatalogInfo
+		teams    []string
+		expected []string
+		err      bool
+	}{
+		{
+			name:    "Configured",
+			catalog: &CatalogInfo{Owner: "group:payments", Lifecycle: "experimental", System: "billing", Tags: []string{"rest"}},
+			expected: []string{
+				"apiVersion: backstage.io/v1alpha1\nkind: API\n",
+				"name: orders-api",
+				"lifecycle: experimental",
+				"owner: group:payments",
+				"system: billing",
+				"$text: http://localhost:8080/api-docs/openapi.json",
+			},
+		},
+		{
+			name:     "Owner from routes",
+			catalog:  &CatalogInfo{},
+			teams:    []string{"orders", "orders"},
+			expected: []string{"owner: orders", "lifecycle: production"},
+		},
+		{
+			name:    "Ambiguous owner",
+			catalog: &CatalogInfo{},
+			teams:   []string{"orders", "billing"},
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Orders API", Host: "localhost:8080", Catalog: tt.catalog}, "secret")
+			for i, team := range tt.teams {
+				err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/orders/" + strings.Repeat("x", i+1), Handler: handler, Ownership: &Ownership{Team: team}})
+				if err != nil {
+					t.Fatalf("Failed to register route: %v", err)
+				}
+			}
+
+			data, err := api.GenerateCatalogInfo()
+			if tt.err {
+				if err == nil {
+					t.Fatal("Expected an error without an owner")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to generate catalog info: %v", err)
+			}
+			for _, snippet := range tt.expected {
+				if !strings.Contains(string(data), snippet) {
+					t.Errorf("Expected catalog info to contain %q, got:\n%s", snippet, data)
+				}
+			}
+
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/catalog-info.yaml", nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != 200 || string(body) != string(data) {
+				t.Errorf("Expected the served entity to match, got %d:\n%s", resp.StatusCode, body)
+			}
+		})
+	}
+}
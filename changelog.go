@@ -0,0 +1,59 @@
+package notelink
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChangeLogEntry records a change of a route, e.g.
+//
+//	{Version: "1.4.0", Date: "2026-03-02", Note: "Added the currency field"}
+type ChangeLogEntry struct {
+	Version string `json:"version,omitempty"`
+	Date    string `json:"date,omitempty"` // YYYY-MM-DD
+	Note    string `json:"note"`
+}
+
+// validateChangeLog checks that every entry has a note and a well-formed date
+func validateChangeLog(entries []ChangeLogEntry) error {
+	for i, entry := range entries {
+		if strings.TrimSpace(entry.Note) == "" {
+			return fmt.Errorf("entry %d: Note is required", i)
+		}
+		if entry.Date != "" {
+			if _, err := time.Parse(time.DateOnly, entry.Date); err != nil {
+				return fmt.Errorf("entry %d: Date %q is not YYYY-MM-DD", i, entry.Date)
+			}
+		}
+	}
+	return nil
+}
+
+// renderChangeLog renders the history accordion of an endpoint card
+func renderChangeLog(entries []ChangeLogEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	var html strings.Builder
+	html.WriteString(`
+                    <details class="changelog">
+                        <summary>History (` + fmt.Sprint(len(entries)) + `)</summary>
+                        <ul>`)
+	for _, entry := range entries {
+		html.WriteString(`
+                            <li>`)
+		if entry.Version != "" {
+			html.WriteString(`<span class="changelog-version">` + escapeHTML(entry.Version) + `</span> `)
+		}
+		if entry.Date != "" {
+			html.WriteString(`<time datetime="` + escapeHTML(entry.Date) + `">` + escapeHTML(entry.Date) + `</time> `)
+		}
+		html.WriteString(escapeHTML(entry.Note) + `</li>`)
+	}
+	html.WriteString(`
+                        </ul>
+                    </details>`)
+	return html.String()
+}
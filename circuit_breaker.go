@@ -0,0 +1,221 @@
+package notelink
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// CircuitState is the state of a circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // Requests reach the handler
+	CircuitOpen     CircuitState = "open"      // Requests are rejected with 503
+	CircuitHalfOpen CircuitState = "half-open" // Probe requests test whether the handler recovered
+)
+
+// Circuit breaker defaults
+const (
+	DefaultCircuitFailureThreshold = 5
+	DefaultCircuitOpenTimeout      = 30 * time.Second
+	DefaultCircuitHalfOpenProbes   = 1
+)
+
+// circuitOpenDescription documents the 503 response of routes with a breaker
+const circuitOpenDescription = "Service unavailable: the circuit breaker is open, retry after the Retry-After delay"
+
+// CircuitBreakerConfig configures a route's circuit breaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// circuit (default DefaultCircuitFailureThreshold)
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before letting probes
+	// through (default DefaultCircuitOpenTimeout)
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of probe requests let through when half-open;
+	// the circuit closes once they all succeed (default DefaultCircuitHalfOpenProbes)
+	HalfOpenProbes int
+	// IsFailure classifies a handler outcome. By default errors and 5xx
+	// responses are failures, except *fiber.Error values below 500.
+	IsFailure func(c fiber.Ctx, err error) bool
+}
+
+// CircuitBreakerStats is a snapshot of a circuit breaker, served at /api-docs/breakers
+type CircuitBreakerStats struct {
+	Route               string       `json:"route"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	Rejected            int64        `json:"rejected"`
+	OpenedAt            *time.Time   `json:"openedAt,omitempty"`
+}
+
+// CircuitBreaker stops calling a failing handler: after FailureThreshold
+// consecutive failures it opens and rejects requests with 503 for OpenTimeout,
+// then lets HalfOpenProbes requests through, closing if they succeed and
+// opening again on the first failure.
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probes    int // Probes let through while half-open
+	succeeded int // Probes succeeded while half-open
+	rejected  int64
+}
+
+// NewCircuitBreaker creates a closed circuit breaker, applying the defaults
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultCircuitFailureThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = DefaultCircuitOpenTimeout
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = DefaultCircuitHalfOpenProbes
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = isHandlerFailure
+	}
+	return &CircuitBreaker{cfg: cfg, state: CircuitClosed}
+}
+
+// State returns the breaker's current state
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(time.Now())
+	return b.state
+}
+
+// refresh moves an open circuit to half-open once OpenTimeout has elapsed
+func (b *CircuitBreaker) refresh(now time.Time) {
+	if b.state == CircuitOpen && now.Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = CircuitHalfOpen
+		b.probes = 0
+		b.succeeded = 0
+	}
+}
+
+// allow reports whether a request may reach the handler and, when it may not,
+// how long until the circuit lets probes through
+func (b *CircuitBreaker) allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.refresh(now)
+	switch b.state {
+	case CircuitOpen:
+		b.rejected++
+		return false, b.cfg.OpenTimeout - now.Sub(b.openedAt)
+	case CircuitHalfOpen:
+		if b.probes >= b.cfg.HalfOpenProbes {
+			b.rejected++
+			return false, 0
+		}
+		b.probes++
+	}
+	return true, 0
+}
+
+// record updates the breaker with a handler outcome
+func (b *CircuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case failed && b.state == CircuitHalfOpen:
+		b.open()
+	case failed:
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	case b.state == CircuitHalfOpen:
+		b.succeeded++
+		if b.succeeded >= b.cfg.HalfOpenProbes {
+			b.state = CircuitClosed
+			b.failures = 0
+		}
+	default:
+		b.failures = 0
+	}
+}
+
+// open opens the circuit
+func (b *CircuitBreaker) open() {
+	b.state = CircuitOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+}
+
+// stats returns a snapshot of the breaker
+func (b *CircuitBreaker) stats(route string) CircuitBreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refresh(time.Now())
+
+	stats := CircuitBreakerStats{
+		Route:               route,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Rejected:            b.rejected,
+	}
+	if b.state != CircuitClosed {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	return stats
+}
+
+// isHandlerFailure is the default CircuitBreakerConfig.IsFailure
+func isHandlerFailure(c fiber.Ctx, err error) bool {
+	if err != nil {
+		var fiberErr *fiber.Error
+		return !errors.As(err, &fiberErr) || fiberErr.Code >= fiber.StatusInternalServerError
+	}
+	return c.Response().StatusCode() >= fiber.StatusInternalServerError
+}
+
+// CircuitBreakerMiddleware returns a Fiber middleware guarding the handlers
+// after it with a circuit breaker. While the circuit is open requests get a
+// 503 Service Unavailable error, rendered by the app's ErrorHandler, with a
+// Retry-After header.
+//
+// It is installed automatically, right before the handler, on routes
+// registered with DocumentedRouteInput.CircuitBreaker.
+func CircuitBreakerMiddleware(b *CircuitBreaker) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		allowed, retryAfter := b.allow()
+		if !allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(1, int(math.Ceil(retryAfter.Seconds())))))
+			return fiber.NewError(fiber.StatusServiceUnavailable, "Circuit breaker is open")
+		}
+
+		err := c.Next()
+		b.record(b.cfg.IsFailure(c, err))
+		return err
+	}
+}
+
+// CircuitBreakers returns a snapshot of the circuit breakers of every route,
+// ordered by route
+func (an *ApiNote) CircuitBreakers() []CircuitBreakerStats {
+	stats := []CircuitBreakerStats{}
+	for _, endpoint := range an.endpoints {
+		if endpoint.CircuitBreaker != nil {
+			stats = append(stats, endpoint.CircuitBreaker.stats(endpoint.Method+" "+endpoint.Path))
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Route < stats[j].Route })
+	return stats
+}
@@ -0,0 +1,103 @@
+package notelink
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestCircuitBreaker tests opening, half-open probes and recovery of a route's breaker
+func TestCircuitBreaker(t *testing.T) {
+	// Count the rejections reaching the ErrorHandler
+	rejected := 0
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", ErrorHandler: func(c fiber.Ctx, err error) error {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusServiceUnavailable {
+			rejected++
+		}
+		return DefaultErrorHandler(c, err)
+	}}, "secret")
+
+	failing := true
+	calls := 0
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "GET",
+		Path:           "/reports",
+		Responses:      map[string]string{"200": "Report"},
+		CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond},
+		Handler: func(c fiber.Ctx) error {
+			calls++
+			if failing {
+				return c.SendStatus(fiber.StatusBadGateway)
+			}
+			return c.SendString("ok")
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	send := func(expected int) {
+		t.Helper()
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/reports", nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		if resp.StatusCode != expected {
+			t.Fatalf("Expected status %d, got %d", expected, resp.StatusCode)
+		}
+		if expected == fiber.StatusServiceUnavailable && resp.Header.Get("Retry-After") == "" {
+			t.Error("Expected a Retry-After header on rejected requests")
+		}
+	}
+	breaker := api.endpoints["GET /reports"].CircuitBreaker
+
+	// Two failures open the circuit, which then rejects without calling the handler
+	send(fiber.StatusBadGateway)
+	send(fiber.StatusBadGateway)
+	send(fiber.StatusServiceUnavailable)
+	if calls != 2 || breaker.State() != CircuitOpen {
+		t.Fatalf("Expected an open circuit after 2 calls, got %s after %d calls", breaker.State(), calls)
+	}
+
+	// A failed probe opens it again
+	time.Sleep(60 * time.Millisecond)
+	if breaker.State() != CircuitHalfOpen {
+		t.Fatalf("Expected a half-open circuit after the timeout, got %s", breaker.State())
+	}
+	send(fiber.StatusBadGateway)
+	send(fiber.StatusServiceUnavailable)
+
+	// A successful probe closes it
+	time.Sleep(60 * time.Millisecond)
+	failing = false
+	send(fiber.StatusOK)
+	send(fiber.StatusOK)
+	if breaker.State() != CircuitClosed {
+		t.Errorf("Expected a closed circuit after a successful probe, got %s", breaker.State())
+	}
+	if rejected != 2 {
+		t.Errorf("Expected the 2 rejections returned to the ErrorHandler, got %d", rejected)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if response, ok := spec.Paths["/reports"].Get.Responses["503"]; !ok || response.Description != circuitOpenDescription {
+		t.Errorf("Expected a documented 503 response, got %+v", spec.Paths["/reports"].Get.Responses)
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/breakers", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	var stats []CircuitBreakerStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode breaker stats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Route != "GET /reports" || stats[0].State != CircuitClosed || stats[0].Rejected != 2 {
+		t.Errorf("Unexpected breaker stats: %+v", stats)
+	}
+}
@@ -0,0 +1,53 @@
+package notelink
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/compress"
+)
+
+// compressionDescription documents compressed operations in the spec
+const compressionDescription = "Responses are compressed with br or gzip when the Accept-Encoding request header allows it, preferring br. " +
+	"The Content-Encoding response header names the encoding used and is absent for uncompressed responses, " +
+	"e.g. without Accept-Encoding, for empty bodies or with Cache-Control: no-transform. Responses carry Vary: Accept-Encoding."
+
+// CompressionConfig configures response compression for documented routes
+type CompressionConfig struct {
+	// Level trades speed for size: compress.LevelDefault, compress.LevelBestSpeed
+	// or compress.LevelBestCompression
+	Level compress.Level
+}
+
+// CompressionMiddleware returns a Fiber middleware compressing responses with
+// br or gzip according to the request's Accept-Encoding header.
+//
+// It is installed automatically on documented routes when Config.Compression
+// is set or DocumentedRouteInput.Compress is true.
+func CompressionMiddleware(cfg CompressionConfig) fiber.Handler {
+	return compress.New(compress.Config{Level: cfg.Level})
+}
+
+// compressed reports whether a route's responses are compressed: the route's
+// Compress override when set, otherwise whether Config.Compression is set
+func (an *ApiNote) compressed(input *DocumentedRouteInput) bool {
+	if input.Compress != nil {
+		return *input.Compress
+	}
+	return an.config.Compression != nil
+}
+
+// compressionConfig returns the configured compression settings, or the defaults
+// for routes enabling compression on their own
+func (an *ApiNote) compressionConfig() CompressionConfig {
+	if an.config.Compression != nil {
+		return *an.config.Compression
+	}
+	return CompressionConfig{}
+}
+
+// appendDescription appends a paragraph to an operation description
+func appendDescription(description, paragraph string) string {
+	if description == "" {
+		return paragraph
+	}
+	return description + "\n\n" + paragraph
+}
@@ -0,0 +1,67 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestCompression tests opt-in response compression and its per-route override
+func TestCompression(t *testing.T) {
+	enabled, disabled := true, false
+	body := strings.Repeat(`{"name":"Ada Lovelace"},`, 100)
+
+	tests := []struct {
+		name        string
+		compression *CompressionConfig
+		compress    *bool
+		encoding    string // Accept-Encoding sent
+		expected    string // Content-Encoding expected
+		documented  bool
+	}{
+		{name: "disabled by default", encoding: "gzip", expected: ""},
+		{name: "enabled in config", compression: &CompressionConfig{}, encoding: "gzip", expected: "gzip", documented: true},
+		{name: "brotli preferred", compression: &CompressionConfig{}, encoding: "gzip, br", expected: "br", documented: true},
+		{name: "no Accept-Encoding", compression: &CompressionConfig{}, expected: "", documented: true},
+		{name: "disabled per route", compression: &CompressionConfig{}, compress: &disabled, encoding: "gzip", expected: ""},
+		{name: "enabled per route", compress: &enabled, encoding: "gzip", expected: "gzip", documented: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", Compression: tt.compression}, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:      "GET",
+				Path:        "/users",
+				Description: "List users",
+				Compress:    tt.compress,
+				Handler:     func(c fiber.Ctx) error { return c.SendString(body) },
+			})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			req := httptest.NewRequest("GET", "/users", nil)
+			if tt.encoding != "" {
+				req.Header.Set("Accept-Encoding", tt.encoding)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			if got := resp.Header.Get("Content-Encoding"); got != tt.expected {
+				t.Errorf("Expected Content-Encoding %q, got %q", tt.expected, got)
+			}
+
+			description := api.GenerateOpenAPISpec().Paths["/users"].Get.Description
+			if strings.Contains(description, "Accept-Encoding") != tt.documented {
+				t.Errorf("Expected compression documented = %v, got description %q", tt.documented, description)
+			}
+			if !strings.HasPrefix(description, "List users") {
+				t.Errorf("Expected the route description first, got %q", description)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package notelink
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// DefaultConcurrencyRetryAfter is the Retry-After delay of rejected requests
+const DefaultConcurrencyRetryAfter = time.Second
+
+// ConcurrencyLimit caps the requests a route processes at once. Requests over
+// the limit wait in a queue of MaxQueue for up to QueueTimeout and are
+// rejected with Status and a Retry-After header when the queue is full or the
+// wait times out.
+type ConcurrencyLimit struct {
+	MaxInFlight  int           // Requests processed at once (required)
+	MaxQueue     int           // Requests waiting for a slot; 0 rejects immediately
+	QueueTimeout time.Duration // Longest wait in the queue; 0 waits until a slot frees up
+	Status       int           // Rejection status: 503 (default) or 429
+	RetryAfter   time.Duration // Retry-After of rejected requests (default DefaultConcurrencyRetryAfter)
+}
+
+// status returns the rejection status
+func (l *ConcurrencyLimit) status() int {
+	if l.Status == 0 {
+		return fiber.StatusServiceUnavailable
+	}
+	return l.Status
+}
+
+// retryAfter returns the Retry-After header value in seconds
+func (l *ConcurrencyLimit) retryAfter() string {
+	delay := l.RetryAfter
+	if delay <= 0 {
+		delay = DefaultConcurrencyRetryAfter
+	}
+	return strconv.Itoa(max(1, int(math.Ceil(delay.Seconds()))))
+}
+
+// describe documents the limit on the operation
+func (l *ConcurrencyLimit) describe() string {
+	description := fmt.Sprintf("At most %d requests are processed at once.", l.MaxInFlight)
+	switch {
+	case l.MaxQueue > 0 && l.QueueTimeout > 0:
+		description += fmt.Sprintf(" Up to %d more wait for up to %s.", l.MaxQueue, l.QueueTimeout)
+	case l.MaxQueue > 0:
+		description += fmt.Sprintf(" Up to %d more wait for a free slot.", l.MaxQueue)
+	}
+	return description + fmt.Sprintf(" Other requests are rejected with %d and a Retry-After header.", l.status())
+}
+
+// ConcurrencyLimitMiddleware returns a Fiber middleware capping the requests
+// processed at once by the handlers after it.
+//
+// Rejections are returned as *fiber.Error, rendered by the app's
+// ErrorHandler. It is installed automatically on routes registered with
+// DocumentedRouteInput.ConcurrencyLimit.
+func ConcurrencyLimitMiddleware(limit ConcurrencyLimit) fiber.Handler {
+	slots := make(chan struct{}, max(1, limit.MaxInFlight))
+	var queued atomic.Int64
+
+	reject := func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderRetryAfter, limit.retryAfter())
+		return fiber.NewError(limit.status(), "Too many concurrent requests")
+	}
+
+	return func(c fiber.Ctx) error {
+		select {
+		case slots <- struct{}{}:
+		default:
+			if queued.Add(1) > int64(limit.MaxQueue) {
+				queued.Add(-1)
+				return reject(c)
+			}
+			acquired := waitForSlot(slots, limit.QueueTimeout)
+			queued.Add(-1)
+			if !acquired {
+				return reject(c)
+			}
+		}
+		defer func() { <-slots }()
+		return c.Next()
+	}
+}
+
+// waitForSlot blocks until a slot frees up or timeout elapses (0 waits indefinitely)
+func waitForSlot(slots chan struct{}, timeout time.Duration) bool {
+	if timeout <= 0 {
+		slots <- struct{}{}
+		return true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
@@ -0,0 +1,109 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestConcurrencyLimit tests rejecting and queueing requests over a route's limit
+func TestConcurrencyLimit(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       ConcurrencyLimit
+		release     time.Duration // When the in-flight request completes
+		expected    int           // Status of the concurrent request
+		description string
+	}{
+		{
+			name:        "rejected with 429",
+			limit:       ConcurrencyLimit{MaxInFlight: 1, Status: fiber.StatusTooManyRequests, RetryAfter: 2 * time.Second},
+			release:     100 * time.Millisecond,
+			expected:    fiber.StatusTooManyRequests,
+			description: "At most 1 requests are processed at once. Other requests are rejected with 429",
+		},
+		{
+			name:        "queue timeout",
+			limit:       ConcurrencyLimit{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: 20 * time.Millisecond},
+			release:     100 * time.Millisecond,
+			expected:    fiber.StatusServiceUnavailable,
+			description: "Up to 1 more wait for up to 20ms.",
+		},
+		{
+			name:        "queued until a slot frees up",
+			limit:       ConcurrencyLimit{MaxInFlight: 1, MaxQueue: 1, QueueTimeout: time.Second},
+			release:     20 * time.Millisecond,
+			expected:    fiber.StatusOK,
+			description: "Up to 1 more wait for up to 1s.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", ErrorHandler: func(c fiber.Ctx, err error) error {
+				c.Set("X-Error-Handler", "called")
+				return DefaultErrorHandler(c, err)
+			}}, "secret")
+			entered := make(chan struct{}, 2)
+			limit := tt.limit
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:           "POST",
+				Path:             "/exports",
+				Description:      "Start an export",
+				ConcurrencyLimit: &limit,
+				Handler: func(c fiber.Ctx) error {
+					entered <- struct{}{}
+					time.Sleep(tt.release)
+					return c.SendString("done")
+				},
+			})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			first := make(chan error, 1)
+			go func() {
+				_, err := api.Fiber().Test(httptest.NewRequest("POST", "/exports", nil))
+				first <- err
+			}()
+			<-entered
+
+			resp, err := api.Fiber().Test(httptest.NewRequest("POST", "/exports", nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			if resp.StatusCode != tt.expected {
+				t.Errorf("Expected status %d, got %d", tt.expected, resp.StatusCode)
+			}
+			if tt.expected != fiber.StatusOK && (resp.Header.Get("Retry-After") == "" || resp.Header.Get("X-Error-Handler") == "") {
+				t.Error("Expected a Retry-After header on the rejection rendered by the ErrorHandler")
+			}
+			if err := <-first; err != nil {
+				t.Fatalf("Failed to send first request: %v", err)
+			}
+
+			operation := api.GenerateOpenAPISpec().Paths["/exports"].Post
+			if !strings.Contains(operation.Description, tt.description) {
+				t.Errorf("Expected the description to contain %q, got %q", tt.description, operation.Description)
+			}
+			if _, ok := operation.Responses[strconv.Itoa(limit.status())]; !ok {
+				t.Errorf("Expected a documented %d response, got %v", limit.status(), operation.Responses)
+			}
+		})
+	}
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:           "GET",
+		Path:             "/exports",
+		ConcurrencyLimit: &ConcurrencyLimit{},
+		Handler:          func(c fiber.Ctx) error { return nil },
+	})
+	if err == nil {
+		t.Error("Expected an error for a limit without MaxInFlight")
+	}
+}
@@ -0,0 +1,125 @@
+package notelink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// Validate checks the configuration for settings that would produce broken
+// links or routes at runtime, returning all problems joined:
+//   - Host is a bare host and port, set whenever the specs and docs are
+//     rendered outside a request (WarmUp and the publishing options) unless
+//     Servers name the hosts
+//   - BasePath starts with a slash and doesn't end with one
+//   - DocsPath is neither the site root nor BasePath itself
+//   - Servers have absolute http(s) URLs whose variables have valid defaults
+//
+// NewApiNote logs each problem as a warning; call Validate first to fail
+// startup on them instead.
+func (c *Config) Validate() error {
+	return errors.Join(c.problems()...)
+}
+
+// warnConfigProblems logs the problems of Validate as warnings
+func (an *ApiNote) warnConfigProblems() {
+	for _, problem := range an.config.problems() {
+		log.Warnf("notelink: config: %v", problem)
+	}
+}
+
+// problems lists the configuration problems reported by Validate
+func (c *Config) problems() []error {
+	var problems []error
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	rendersOffline := c.WarmUp || c.PublishArtifacts != nil || len(c.SpecRegistries) > 0 || c.SpecChangeNotifier != nil || c.SchemaCompatibility != nil
+	switch {
+	case c.Host == "" && rendersOffline && len(c.Servers) == 0:
+		add("Host is empty: specs rendered outside a request (WarmUp, publishing) would link to http://; set Host or Servers")
+	case strings.Contains(c.Host, "://"):
+		add("Host %q includes a scheme: set the bare host, e.g. api.example.com, and the scheme with Servers", c.Host)
+	case strings.ContainsAny(c.Host, "/?# "):
+		add("Host %q is not a host and port: paths belong in BasePath", c.Host)
+	}
+
+	if c.BasePath != "" {
+		if !strings.HasPrefix(c.BasePath, "/") {
+			add("BasePath %q must start with a slash, e.g. /%s", c.BasePath, strings.TrimLeft(c.BasePath, "/"))
+		} else if c.BasePath != "/" && strings.HasSuffix(c.BasePath, "/") {
+			add("BasePath %q must not end with a slash, e.g. %s", c.BasePath, strings.TrimRight(c.BasePath, "/"))
+		}
+		if strings.ContainsAny(c.BasePath, "?# ") {
+			add("BasePath %q must be a plain path", c.BasePath)
+		}
+	}
+
+	if c.DocsPath != "" {
+		docsPath := "/" + strings.Trim(c.DocsPath, "/")
+		switch {
+		case docsPath == "/":
+			add("DocsPath %q serves the docs at the site root, where they would shadow the API's routes; use e.g. %s", c.DocsPath, DefaultDocsPath)
+		case c.BasePath != "" && docsPath == "/"+strings.Trim(c.BasePath, "/"):
+			add("DocsPath %q is BasePath: the docs routes would collide with the API's routes; use e.g. %s", c.DocsPath, DefaultDocsPath)
+		}
+	}
+
+	for i, server := range c.Servers {
+		problems = append(problems, serverProblems(i, &server)...)
+	}
+	return problems
+}
+
+// serverProblems checks a server's URL and variables
+func serverProblems(index int, server *OpenAPIServer) []error {
+	var problems []error
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf("Servers[%d] %s: "+format, append([]interface{}{index, server.URL}, args...)...))
+	}
+
+	// The URL is checked with the defaults substituted
+	resolved, unresolved := server.URL, false
+	for _, match := range serverVariablePattern.FindAllStringSubmatch(server.URL, -1) {
+		variable, ok := server.Variables[match[1]]
+		if !ok {
+			add("variable %q has no default", match[1])
+			unresolved = true
+			continue
+		}
+		resolved = strings.ReplaceAll(resolved, match[0], variable.Default)
+	}
+	names := make([]string, 0, len(server.Variables))
+	for name := range server.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		variable := server.Variables[name]
+		if len(variable.Enum) > 0 && !slices.Contains(variable.Enum, variable.Default) {
+			add("default %q of variable %q is not one of its values", variable.Default, name)
+		}
+	}
+
+	if unresolved {
+		return problems
+	}
+	parsed, err := url.Parse(resolved)
+	switch {
+	case err != nil:
+		add("invalid URL: %v", err)
+	case parsed.Scheme != "http" && parsed.Scheme != "https":
+		add("the URL needs an http or https scheme, e.g. https://api.example.com")
+	case parsed.Host == "":
+		add("the URL has no host")
+	case parsed.RawQuery != "" || parsed.Fragment != "":
+		add("the URL must not have a query or fragment")
+	}
+	return problems
+}
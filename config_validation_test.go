@@ -0,0 +1,72 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestConfigValidate tests catching configurations producing broken links
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		problems []string
+	}{
+		{name: "minimal", config: Config{Title: "Test API"}},
+		{name: "complete", config: Config{Host: "api.example.com:8443", BasePath: "/v1", DocsPath: "/v1/docs", WarmUp: true}},
+		{name: "empty host rendered offline", config: Config{WarmUp: true}, problems: []string{"Host is empty"}},
+		{name: "empty host with servers", config: Config{WarmUp: true, Servers: []OpenAPIServer{{URL: "https://api.example.com"}}}},
+		{name: "host with scheme", config: Config{Host: "https://api.example.com"}, problems: []string{`Host "https://api.example.com" includes a scheme`}},
+		{name: "host with path", config: Config{Host: "api.example.com/v1"}, problems: []string{"paths belong in BasePath"}},
+		{name: "base path without slash", config: Config{BasePath: "v1"}, problems: []string{`BasePath "v1" must start with a slash, e.g. /v1`}},
+		{name: "base path with trailing slash", config: Config{BasePath: "/v1/"}, problems: []string{`BasePath "/v1/" must not end with a slash, e.g. /v1`}},
+		{name: "docs at the root", config: Config{DocsPath: "/"}, problems: []string{"serves the docs at the site root"}},
+		{name: "docs at the base path", config: Config{BasePath: "/v1", DocsPath: "v1/"}, problems: []string{`DocsPath "v1/" is BasePath`}},
+		{
+			name: "templated server",
+			config: Config{Servers: []OpenAPIServer{{
+				URL:       "https://{tenant}.api.example.com",
+				Variables: map[string]ServerVariable{"tenant": {Default: "acme", Enum: []string{"acme", "globex"}}},
+			}}},
+		},
+		{name: "server without scheme", config: Config{Servers: []OpenAPIServer{{URL: "api.example.com"}}}, problems: []string{"Servers[0] api.example.com: the URL needs an http or https scheme"}},
+		{name: "server without host", config: Config{Servers: []OpenAPIServer{{URL: "https:///v1"}}}, problems: []string{"the URL has no host"}},
+		{name: "server with query", config: Config{Servers: []OpenAPIServer{{URL: "https://api.example.com?debug=1"}}}, problems: []string{"must not have a query"}},
+		{
+			name: "server variables",
+			config: Config{Servers: []OpenAPIServer{{
+				URL:       "https://{tenant}.{region}.example.com",
+				Variables: map[string]ServerVariable{"tenant": {Default: "initech", Enum: []string{"acme", "globex"}}},
+			}}},
+			problems: []string{`variable "region" has no default`, `default "initech" of variable "tenant" is not one of its values`},
+		},
+		{
+			name:     "aggregated",
+			config:   Config{Host: "http://localhost", BasePath: "v1", Servers: []OpenAPIServer{{URL: "ftp://files.example.com"}}},
+			problems: []string{"includes a scheme", "must start with a slash", "Servers[0] ftp://files.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Errorf("Expected a valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected problems %v, got none", tt.problems)
+			}
+			if lines := strings.Split(err.Error(), "\n"); len(lines) != len(tt.problems) {
+				t.Errorf("Expected %d problems, got %q", len(tt.problems), lines)
+			}
+			for _, problem := range tt.problems {
+				if !strings.Contains(err.Error(), problem) {
+					t.Errorf("Expected a problem containing %q, got %q", problem, err)
+				}
+			}
+		})
+	}
+}
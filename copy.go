@@ -0,0 +1,81 @@
+package notelink
+
+// copyButtonMarkup is the copy-to-clipboard button placed in every .copyable
+// container; it copies the container's visible pre block
+const copyButtonMarkup = `<button type="button" class="copy-button" onclick="copyToClipboard(event, this)" title="Copy to clipboard" aria-label="Copy to clipboard"><i class="far fa-copy"></i></button>`
+
+// renderCopyablePre renders a pre block with a copy button. attrs is inserted
+// verbatim into the pre tag and content must already be escaped.
+func renderCopyablePre(attrs, content string) string {
+	return `<div class="copyable">` + copyButtonMarkup + `<pre` + attrs + `>` + content + `</pre></div>`
+}
+
+// renderCopyTextButton renders a copy button for a fixed text, such as an endpoint URL
+func renderCopyTextButton(text, title string) string {
+	return `<button type="button" class="copy-button" data-copy="` + escapeHTML(text) + `" onclick="copyToClipboard(event, this)" title="` + escapeHTML(title) + `" aria-label="` + escapeHTML(title) + `"><i class="far fa-copy"></i></button>`
+}
+
+// renderCopyURLButton renders a copy button for an endpoint URL. With an empty
+// baseURL the path is copied relative to the page's origin.
+func renderCopyURLButton(baseURL, path string) string {
+	if baseURL != "" {
+		return renderCopyTextButton(baseURL+path, "Copy URL")
+	}
+	return `<button type="button" class="copy-button" data-copy-origin data-copy="` + escapeHTML(path) + `" onclick="copyToClipboard(event, this)" title="Copy URL" aria-label="Copy URL"><i class="far fa-copy"></i></button>`
+}
+
+// copyScript implements the copy buttons, including those added to try-it results
+const copyScript = `
+            // Copy to clipboard: a fixed data-copy text or the visible pre in the .copyable container
+            function copyToClipboard(event, button) {
+                event.preventDefault();
+                event.stopPropagation();
+
+                let text = button.dataset.copy;
+                if (button.dataset.copyOrigin !== undefined) {
+                    text = window.location.origin + text;
+                } else if (text === undefined) {
+                    const pre = button.closest('.copyable').querySelector('pre:not([hidden])');
+                    text = pre ? pre.textContent : '';
+                }
+
+                const done = () => {
+                    const icon = button.querySelector('i');
+                    icon.className = 'fas fa-check';
+                    button.classList.add('copied');
+                    setTimeout(() => {
+                        icon.className = 'far fa-copy';
+                        button.classList.remove('copied');
+                    }, 1500);
+                };
+
+                if (navigator.clipboard && window.isSecureContext) {
+                    navigator.clipboard.writeText(text).then(done).catch(() => fallbackCopy(text, done));
+                } else {
+                    fallbackCopy(text, done);
+                }
+            }
+
+            // execCommand fallback for pages served over plain HTTP
+            function fallbackCopy(text, done) {
+                const textarea = document.createElement('textarea');
+                textarea.value = text;
+                textarea.setAttribute('readonly', '');
+                textarea.style.position = 'fixed';
+                textarea.style.opacity = '0';
+                document.body.appendChild(textarea);
+                textarea.select();
+                try {
+                    if (document.execCommand('copy')) {
+                        done();
+                    }
+                } finally {
+                    document.body.removeChild(textarea);
+                }
+            }
+
+            // Client-side counterpart of renderCopyablePre for try-it results
+            function copyablePre(content, attrs = '') {
+                return '<div class="copyable">` + copyButtonMarkup + `<pre' + attrs + '>' + content + '</pre></div>';
+            }
+`
@@ -0,0 +1,191 @@
+package notelink
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// CORSConfig lets browser clients on other origins call the documented routes.
+// Preflight requests are answered for every documented path with the path's
+// methods and headers, and documented as the path's OPTIONS operation.
+type CORSConfig struct {
+	// AllowOrigins lists the origins allowed to call the API, e.g.
+	// "https://app.example.com", or "*" for any origin
+	AllowOrigins []string
+	// AllowHeaders adds request headers to those derived from the routes:
+	// Content-Type, Authorization for authenticated routes, header parameters
+	// and X-Request-ID
+	AllowHeaders []string
+	// ExposeHeaders lists response headers readable by browser clients
+	ExposeHeaders []string
+	// AllowCredentials lets clients send cookies and credentials; the origin
+	// is then echoed even when AllowOrigins is "*"
+	AllowCredentials bool
+	// MaxAge lets browsers cache preflight responses for the duration
+	MaxAge time.Duration
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for an origin, or
+// "" when the origin isn't allowed
+func (cfg *CORSConfig) allowOrigin(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	if slices.Contains(cfg.AllowOrigins, "*") {
+		if cfg.AllowCredentials {
+			return origin
+		}
+		return "*"
+	}
+	if slices.Contains(cfg.AllowOrigins, origin) {
+		return origin
+	}
+	return ""
+}
+
+// setCORSHeaders sets the headers of responses to allowed cross-origin requests,
+// reporting whether the origin is allowed
+func (cfg *CORSConfig) setCORSHeaders(c fiber.Ctx) bool {
+	c.Vary(fiber.HeaderOrigin)
+	allowed := cfg.allowOrigin(c.Get(fiber.HeaderOrigin))
+	if allowed == "" {
+		return false
+	}
+	c.Set(fiber.HeaderAccessControlAllowOrigin, allowed)
+	if cfg.AllowCredentials {
+		c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+	}
+	return true
+}
+
+// CORSMiddleware sets the CORS headers of responses to allowed origins
+func CORSMiddleware(cfg CORSConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if cfg.setCORSHeaders(c) && len(cfg.ExposeHeaders) > 0 {
+			c.Set(fiber.HeaderAccessControlExposeHeaders, strings.Join(cfg.ExposeHeaders, ", "))
+		}
+		return c.Next()
+	}
+}
+
+// mountOptions answers OPTIONS requests for a documented path, once per path.
+// A documented OPTIONS route on the path takes precedence.
+func (an *ApiNote) mountOptions(path string) {
+	if an.optionsPaths[path] {
+		return
+	}
+	if an.optionsPaths == nil {
+		an.optionsPaths = make(map[string]bool)
+	}
+	an.optionsPaths[path] = true
+	an.app.Options(path, func(c fiber.Ctx) error {
+		return an.optionsHandler(c, path)
+	})
+}
+
+// optionsHandler answers OPTIONS with the path's methods in the Allow header
+// and, for CORS preflight requests from allowed origins, the path's CORS headers
+func (an *ApiNote) optionsHandler(c fiber.Ctx, path string) error {
+	if an.hasEndpoint(fiber.MethodOptions, path) {
+		return c.Next()
+	}
+
+	c.Set(fiber.HeaderAllow, an.allowedMethods(path))
+	cors := an.config.CORS
+	if cors != nil && c.Get(fiber.HeaderAccessControlRequestMethod) != "" && cors.setCORSHeaders(c) {
+		c.Set(fiber.HeaderAccessControlAllowMethods, an.allowedMethods(path))
+		c.Set(fiber.HeaderAccessControlAllowHeaders, strings.Join(an.corsAllowHeaders(path), ", "))
+		if cors.MaxAge > 0 {
+			c.Set(fiber.HeaderAccessControlMaxAge, strconv.Itoa(int(cors.MaxAge.Seconds())))
+		}
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// hasEndpoint reports whether a route is documented for a method and full path
+func (an *ApiNote) hasEndpoint(method, path string) bool {
+	for _, endpoint := range an.endpoints {
+		if endpoint.Method == method && endpoint.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// corsAllowHeaders returns the request headers browser clients may send to a
+// path: Content-Type, Authorization when a route requires authentication, the
+// routes' header parameters, X-Request-ID of the docs' try-it client (and
+// X-Try-It with Config.TryIt, Accept-Language and X-Timezone with
+// Config.TryItLocale) and CORSConfig.AllowHeaders
+func (an *ApiNote) corsAllowHeaders(path string) []string {
+	headers := []string{fiber.HeaderContentType}
+	add := func(header string) {
+		for _, existing := range headers {
+			if strings.EqualFold(existing, header) {
+				return
+			}
+		}
+		headers = append(headers, header)
+	}
+	for _, endpoint := range an.sortedEndpoints() {
+		if endpoint.Path != path {
+			continue
+		}
+		if endpoint.AuthRequired {
+			add(fiber.HeaderAuthorization)
+		}
+		for _, param := range endpoint.Parameters {
+			if param.In == "header" {
+				add(param.Name)
+			}
+		}
+	}
+	add(RequestIDHeader)
+	if an.config.TryIt != nil {
+		add(TryItHeader)
+	}
+	if an.config.TryItLocale != nil {
+		add(fiber.HeaderAcceptLanguage)
+		add(TimezoneHeader)
+	}
+	for _, header := range an.config.CORS.AllowHeaders {
+		add(header)
+	}
+	return headers
+}
+
+// preflightOperation documents the CORS preflight of a path
+func (an *ApiNote) preflightOperation(endpoint *Endpoint) *Operation {
+	cors := an.config.CORS
+	origins := strings.Join(cors.AllowOrigins, ", ")
+	if origins == "" {
+		origins = "none"
+	}
+	headers := map[string]HeaderObject{
+		fiber.HeaderAllow:                     {Description: "Methods of the path: " + an.allowedMethods(endpoint.Path), Schema: &JSONSchema{Type: "string"}},
+		fiber.HeaderAccessControlAllowOrigin:  {Description: "The request's Origin when allowed (" + origins + ")", Schema: &JSONSchema{Type: "string"}},
+		fiber.HeaderAccessControlAllowMethods: {Description: an.allowedMethods(endpoint.Path), Schema: &JSONSchema{Type: "string"}},
+		fiber.HeaderAccessControlAllowHeaders: {Description: strings.Join(an.corsAllowHeaders(endpoint.Path), ", "), Schema: &JSONSchema{Type: "string"}},
+	}
+	if cors.AllowCredentials {
+		headers[fiber.HeaderAccessControlAllowCredentials] = HeaderObject{Description: "true", Schema: &JSONSchema{Type: "string"}}
+	}
+	if cors.MaxAge > 0 {
+		headers[fiber.HeaderAccessControlMaxAge] = HeaderObject{Description: "Seconds browsers may cache the preflight", Schema: &JSONSchema{Type: "integer"}}
+	}
+
+	return &Operation{
+		OperationID: generateOperationID(fiber.MethodOptions, endpoint.routePath()),
+		Summary:     "CORS preflight",
+		Description: "Answered automatically for browser clients on allowed origins (" + origins + "). " +
+			"Requests without an allowed Origin and Access-Control-Request-Method only get the Allow header.",
+		Responses: map[string]Response{
+			"204": {Description: "Preflight accepted", Headers: headers},
+		},
+		Tags: endpointTags(endpoint),
+	}
+}
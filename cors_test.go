@@ -0,0 +1,96 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestOptionsAndCORS tests the OPTIONS responder, CORS headers and the documented preflight
+func TestOptionsAndCORS(t *testing.T) {
+	newAPI := func(cors *CORSConfig) *ApiNote {
+		api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", CORS: cors}, "secret")
+		handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+		authRequired := true
+		routes := []DocumentedRouteInput{
+			{Method: "GET", Path: "/v1/users", Handler: handler},
+			{Method: "POST", Path: "/v1/users", AuthRequired: &authRequired, Params: []Parameter{{Name: "X-Request-ID", In: "header", Type: "string"}}, Handler: handler},
+			{Method: "OPTIONS", Path: "/v1/custom", Handler: func(c fiber.Ctx) error { return c.SendString("custom") }},
+			{Method: "GET", Path: "/v1/custom", Handler: handler},
+		}
+		for i := range routes {
+			if err := api.DocumentedRoute(&routes[i]); err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+		}
+		return api
+	}
+
+	cors := &CORSConfig{AllowOrigins: []string{"https://app.example.com"}, ExposeHeaders: []string{"X-Total-Count"}, MaxAge: 10 * time.Minute}
+	tests := []struct {
+		name    string
+		cors    *CORSConfig
+		method  string
+		path    string
+		origin  string
+		status  int
+		headers map[string]string
+	}{
+		{"options without CORS", nil, "OPTIONS", "/v1/users", "", fiber.StatusNoContent, map[string]string{
+			"Allow": "GET, HEAD, OPTIONS, POST", "Access-Control-Allow-Origin": "",
+		}},
+		{"preflight", cors, "OPTIONS", "/v1/users", "https://app.example.com", fiber.StatusNoContent, map[string]string{
+			"Access-Control-Allow-Origin":  "https://app.example.com",
+			"Access-Control-Allow-Methods": "GET, HEAD, OPTIONS, POST",
+			"Access-Control-Allow-Headers": "Content-Type, Authorization, X-Request-ID",
+			"Access-Control-Max-Age":       "600",
+		}},
+		{"preflight from other origin", cors, "OPTIONS", "/v1/users", "https://evil.example.com", fiber.StatusNoContent, map[string]string{
+			"Allow": "GET, HEAD, OPTIONS, POST", "Access-Control-Allow-Origin": "",
+		}},
+		{"cross-origin request", cors, "GET", "/v1/users", "https://app.example.com", fiber.StatusOK, map[string]string{
+			"Access-Control-Allow-Origin": "https://app.example.com", "Access-Control-Expose-Headers": "X-Total-Count", "Vary": "Origin",
+		}},
+		{"documented OPTIONS route", cors, "OPTIONS", "/v1/custom", "", fiber.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPI(tt.cors)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+				req.Header.Set("Access-Control-Request-Method", "POST")
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			for name, value := range tt.headers {
+				if got := resp.Header.Get(name); got != value {
+					t.Errorf("Expected %s %q, got %q", name, value, got)
+				}
+			}
+		})
+	}
+
+	spec := newAPI(cors).GenerateOpenAPISpec()
+	preflight := spec.Paths["/v1/users"].Options
+	if preflight == nil || preflight.Summary != "CORS preflight" {
+		t.Fatalf("Expected a documented preflight, got %+v", preflight)
+	}
+	if headers := preflight.Responses["204"].Headers; headers["Access-Control-Allow-Headers"].Description != "Content-Type, Authorization, X-Request-ID" {
+		t.Errorf("Expected the documented preflight headers, got %+v", headers)
+	}
+	if custom := spec.Paths["/v1/custom"].Options; custom == nil || custom.Summary == "CORS preflight" {
+		t.Errorf("Expected the documented OPTIONS route to be kept, got %+v", custom)
+	}
+	if newAPI(nil).GenerateOpenAPISpec().Paths["/v1/users"].Options != nil {
+		t.Error("Expected no documented preflight without Config.CORS")
+	}
+}
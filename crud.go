@@ -0,0 +1,297 @@
+package notelink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	// DefaultCRUDPageLimit is the page size of CRUD list routes without a limit
+	DefaultCRUDPageLimit = 20
+	// DefaultCRUDMaxPageLimit caps the limit of CRUD list routes
+	DefaultCRUDMaxPageLimit = 100
+)
+
+// ErrResourceNotFound is returned by Repository methods for unknown ids; CRUD
+// routes respond 404 to it
+var ErrResourceNotFound = errors.New("notelink: resource not found")
+
+// Repository stores the resources served by CRUD routes
+type Repository[T any] interface {
+	List(ctx context.Context, page PageRequest) (items []T, total int, err error)
+	Get(ctx context.Context, id string) (T, error)
+	Create(ctx context.Context, resource T) (T, error)
+	Update(ctx context.Context, id string, resource T) (T, error)
+	// Delete removes a resource, or marks it deleted for soft deletes
+	Delete(ctx context.Context, id string) error
+}
+
+// Restorer is implemented by repositories that soft-delete resources; CRUD
+// then also registers a POST {path}/{id}/restore route
+type Restorer[T any] interface {
+	Restore(ctx context.Context, id string) (T, error)
+}
+
+// PageRequest is the page requested from a CRUD list route
+type PageRequest struct {
+	Limit  int
+	Offset int
+}
+
+// ResourcePage is the response of CRUD list routes
+type ResourcePage[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// AuditEvent describes a change made through a CRUD route
+type AuditEvent struct {
+	Action   string      // "create", "update", "delete" or "restore"
+	Resource string      // CRUDOptions.Name
+	ID       string      // Empty for creates; see After
+	After    interface{} // The resource after the change, nil for deletes
+}
+
+// CRUDOptions customizes the routes registered by CRUD
+type CRUDOptions struct {
+	// Name is the resource name used in descriptions, e.g. "user". Defaults to
+	// the resource type's name.
+	Name string
+	// Tags sets the operation tags of the routes
+	Tags []string
+	// AuthRequired protects the routes like DocumentedRouteInput.AuthRequired
+	AuthRequired *bool
+	// DefaultLimit and MaxLimit bound the page size of the list route
+	// (default DefaultCRUDPageLimit and DefaultCRUDMaxPageLimit)
+	DefaultLimit int
+	MaxLimit     int
+	// Audit is called after each successful change, e.g. to write an audit log
+	Audit func(c fiber.Ctx, event AuditEvent)
+}
+
+// CRUD registers documented list, get, create, update and delete routes for
+// the resources of repo under path, with pagination and the standard error
+// responses:
+//
+//	GET    {path}?limit=&offset=  list a page of resources
+//	POST   {path}                 create a resource (201)
+//	GET    {path}/:id             get a resource
+//	PUT    {path}/:id             replace a resource
+//	DELETE {path}/:id             delete a resource (204)
+//	POST   {path}/:id/restore     restore a soft-deleted resource, when repo is a Restorer
+//
+// Request bodies are validated against T, so server-assigned fields such as the
+// id should be tagged omitempty.
+//
+// Example:
+//
+//	err := notelink.CRUD(api, "/v1/users", usersRepo, notelink.CRUDOptions{Name: "user"})
+func CRUD[T any](an *ApiNote, path string, repo Repository[T], opts CRUDOptions) error {
+	if repo == nil {
+		return fmt.Errorf("repository is required")
+	}
+	var resource T
+	if opts.Name == "" {
+		opts.Name = strings.ToLower(reflect.TypeOf(&resource).Elem().Name())
+	}
+	if opts.DefaultLimit <= 0 {
+		opts.DefaultLimit = DefaultCRUDPageLimit
+	}
+	if opts.MaxLimit <= 0 {
+		opts.MaxLimit = DefaultCRUDMaxPageLimit
+	}
+	path = strings.TrimSuffix(path, "/")
+	item := path + "/:id"
+	idParam := Parameter{Name: "id", In: "path", Type: "string", Description: "ID of the " + opts.Name, Required: true}
+	crud := crudHandlers[T]{repo: repo, opts: opts}
+
+	routes := []DocumentedRouteInput{
+		{
+			Method:      fiber.MethodGet,
+			Path:        path,
+			Description: "List " + opts.Name + " resources",
+			Params: []Parameter{
+				{Name: "limit", In: "query", Type: "integer", Description: fmt.Sprintf("Page size, at most %d (default %d)", opts.MaxLimit, opts.DefaultLimit)},
+				{Name: "offset", In: "query", Type: "integer", Description: "Number of resources to skip (default 0)"},
+			},
+			Responses:       map[string]string{"200": "A page of " + opts.Name + " resources", "400": "Invalid page"},
+			SchemasResponse: ResourcePage[T]{},
+			Handler:         crud.list,
+		},
+		{
+			Method:          fiber.MethodPost,
+			Path:            path,
+			Description:     "Create a " + opts.Name,
+			Responses:       map[string]string{"201": "Created " + opts.Name, "400": "Invalid " + opts.Name},
+			SchemasRequest:  resource,
+			SchemasResponse: resource,
+			Handler:         crud.create,
+		},
+		{
+			Method:          fiber.MethodGet,
+			Path:            item,
+			Description:     "Get a " + opts.Name,
+			Params:          []Parameter{idParam},
+			Responses:       map[string]string{"200": "The " + opts.Name, "404": opts.Name + " not found"},
+			SchemasResponse: resource,
+			Handler:         crud.get,
+		},
+		{
+			Method:          fiber.MethodPut,
+			Path:            item,
+			Description:     "Replace a " + opts.Name,
+			Params:          []Parameter{idParam},
+			Responses:       map[string]string{"200": "Updated " + opts.Name, "400": "Invalid " + opts.Name, "404": opts.Name + " not found"},
+			SchemasRequest:  resource,
+			SchemasResponse: resource,
+			Handler:         crud.update,
+		},
+		{
+			Method:      fiber.MethodDelete,
+			Path:        item,
+			Description: "Delete a " + opts.Name,
+			Params:      []Parameter{idParam},
+			Responses:   map[string]string{"204": opts.Name + " deleted", "404": opts.Name + " not found"},
+			Handler:     crud.delete,
+		},
+	}
+	if restorer, ok := repo.(Restorer[T]); ok {
+		routes = append(routes, DocumentedRouteInput{
+			Method:          fiber.MethodPost,
+			Path:            item + "/restore",
+			Description:     "Restore a deleted " + opts.Name,
+			Params:          []Parameter{idParam},
+			Responses:       map[string]string{"200": "Restored " + opts.Name, "404": opts.Name + " not found"},
+			SchemasResponse: resource,
+			Handler:         crud.restore(restorer),
+		})
+	}
+
+	for i := range routes {
+		routes[i].Tags = opts.Tags
+		routes[i].AuthRequired = opts.AuthRequired
+		if err := an.DocumentedRoute(&routes[i]); err != nil {
+			return err
+		}
+	}
+	an.resources = append(an.resources, resourceType{name: opts.Name, typ: reflect.TypeOf(&resource).Elem()})
+	return nil
+}
+
+// crudHandlers serves the CRUD routes of a repository
+type crudHandlers[T any] struct {
+	repo Repository[T]
+	opts CRUDOptions
+}
+
+// list serves a page of resources
+func (h crudHandlers[T]) list(c fiber.Ctx) error {
+	page := PageRequest{Limit: h.opts.DefaultLimit}
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > h.opts.MaxLimit {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", h.opts.MaxLimit))
+		}
+		page.Limit = value
+	}
+	if offset := c.Query("offset"); offset != "" {
+		value, err := strconv.Atoi(offset)
+		if err != nil || value < 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "offset must not be negative")
+		}
+		page.Offset = value
+	}
+
+	items, total, err := h.repo.List(c.Context(), page)
+	if err != nil {
+		return err
+	}
+	if items == nil {
+		items = []T{}
+	}
+	return c.JSON(ResourcePage[T]{Items: items, Total: total, Limit: page.Limit, Offset: page.Offset})
+}
+
+// get serves a resource
+func (h crudHandlers[T]) get(c fiber.Ctx) error {
+	resource, err := h.repo.Get(c.Context(), c.Params("id"))
+	if err != nil {
+		return h.error(err)
+	}
+	return c.JSON(resource)
+}
+
+// create stores a new resource
+func (h crudHandlers[T]) create(c fiber.Ctx) error {
+	var resource T
+	if err := c.Bind().Body(&resource); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid "+h.opts.Name)
+	}
+	created, err := h.repo.Create(c.Context(), resource)
+	if err != nil {
+		return h.error(err)
+	}
+	h.audit(c, "create", "", created)
+	return c.Status(fiber.StatusCreated).JSON(created)
+}
+
+// update replaces a resource
+func (h crudHandlers[T]) update(c fiber.Ctx) error {
+	var resource T
+	if err := c.Bind().Body(&resource); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid "+h.opts.Name)
+	}
+	id := c.Params("id")
+	updated, err := h.repo.Update(c.Context(), id, resource)
+	if err != nil {
+		return h.error(err)
+	}
+	h.audit(c, "update", id, updated)
+	return c.JSON(updated)
+}
+
+// delete removes a resource
+func (h crudHandlers[T]) delete(c fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.repo.Delete(c.Context(), id); err != nil {
+		return h.error(err)
+	}
+	h.audit(c, "delete", id, nil)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// restore serves restoring a soft-deleted resource
+func (h crudHandlers[T]) restore(restorer Restorer[T]) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		id := c.Params("id")
+		restored, err := restorer.Restore(c.Context(), id)
+		if err != nil {
+			return h.error(err)
+		}
+		h.audit(c, "restore", id, restored)
+		return c.JSON(restored)
+	}
+}
+
+// error converts ErrResourceNotFound into a 404
+func (h crudHandlers[T]) error(err error) error {
+	if errors.Is(err, ErrResourceNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, h.opts.Name+" not found")
+	}
+	return err
+}
+
+// audit reports a change to CRUDOptions.Audit
+func (h crudHandlers[T]) audit(c fiber.Ctx, action, id string, after interface{}) {
+	if h.opts.Audit != nil {
+		h.opts.Audit(c, AuditEvent{Action: action, Resource: h.opts.Name, ID: id, After: after})
+	}
+}
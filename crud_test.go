@@ -0,0 +1,173 @@
+package notelink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type crudUser struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+// softDeleteUsers is a soft-deleting in-memory user repository
+type softDeleteUsers struct {
+	mu      sync.Mutex
+	users   []crudUser
+	deleted map[string]bool
+}
+
+func (r *softDeleteUsers) find(id string) int {
+	for i, user := range r.users {
+		if user.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *softDeleteUsers) List(ctx context.Context, page PageRequest) ([]crudUser, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var live []crudUser
+	for _, user := range r.users {
+		if !r.deleted[user.ID] {
+			live = append(live, user)
+		}
+	}
+	total := len(live)
+	live = live[min(page.Offset, total):min(page.Offset+page.Limit, total)]
+	return live, total, nil
+}
+
+func (r *softDeleteUsers) Get(ctx context.Context, id string) (crudUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.find(id); i >= 0 && !r.deleted[id] {
+		return r.users[i], nil
+	}
+	return crudUser{}, ErrResourceNotFound
+}
+
+func (r *softDeleteUsers) Create(ctx context.Context, user crudUser) (crudUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.ID = strconv.Itoa(len(r.users) + 1)
+	r.users = append(r.users, user)
+	return user, nil
+}
+
+func (r *softDeleteUsers) Update(ctx context.Context, id string, user crudUser) (crudUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || r.deleted[id] {
+		return crudUser{}, ErrResourceNotFound
+	}
+	user.ID = id
+	r.users[i] = user
+	return user, nil
+}
+
+func (r *softDeleteUsers) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.find(id); i < 0 || r.deleted[id] {
+		return ErrResourceNotFound
+	}
+	r.deleted[id] = true
+	return nil
+}
+
+func (r *softDeleteUsers) Restore(ctx context.Context, id string) (crudUser, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.find(id)
+	if i < 0 || !r.deleted[id] {
+		return crudUser{}, ErrResourceNotFound
+	}
+	delete(r.deleted, id)
+	return r.users[i], nil
+}
+
+// TestCRUD tests the scaffolded CRUD routes, soft deletes and audit events
+func TestCRUD(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	var audit []string
+	err := CRUD[crudUser](api, "/v1/users", &softDeleteUsers{deleted: make(map[string]bool)}, CRUDOptions{
+		Name: "user",
+		Tags: []string{"users"},
+		Audit: func(c fiber.Ctx, event AuditEvent) {
+			audit = append(audit, event.Action+" "+event.Resource+" "+event.ID)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register CRUD routes: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"create", "POST", "/v1/users", `{"name":"Ada"}`, fiber.StatusCreated, `{"id":"1","name":"Ada"}`},
+		{"create another", "POST", "/v1/users", `{"name":"Grace"}`, fiber.StatusCreated, `{"id":"2","name":"Grace"}`},
+		{"create invalid", "POST", "/v1/users", `{}`, fiber.StatusBadRequest, ""},
+		{"list", "GET", "/v1/users?limit=1&offset=1", "", fiber.StatusOK, `{"items":[{"id":"2","name":"Grace"}],"total":2,"limit":1,"offset":1}`},
+		{"list over max", "GET", "/v1/users?limit=1000", "", fiber.StatusBadRequest, ""},
+		{"get", "GET", "/v1/users/1", "", fiber.StatusOK, `{"id":"1","name":"Ada"}`},
+		{"update", "PUT", "/v1/users/1", `{"name":"Ada L."}`, fiber.StatusOK, `{"id":"1","name":"Ada L."}`},
+		{"delete", "DELETE", "/v1/users/1", "", fiber.StatusNoContent, ""},
+		{"get deleted", "GET", "/v1/users/1", "", fiber.StatusNotFound, `{"error":"user not found"}`},
+		{"restore", "POST", "/v1/users/1/restore", "", fiber.StatusOK, `{"id":"1","name":"Ada L."}`},
+		{"delete unknown", "DELETE", "/v1/users/9", "", fiber.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("%s: failed to send test request: %v", tt.name, err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.wantStatus, resp.StatusCode)
+		}
+		if tt.wantBody != "" {
+			var got, want interface{}
+			_ = json.NewDecoder(resp.Body).Decode(&got)
+			_ = json.Unmarshal([]byte(tt.wantBody), &want)
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("%s: expected body %s, got %s", tt.name, wantJSON, gotJSON)
+			}
+		}
+	}
+
+	wantAudit := []string{"create user ", "create user ", "update user 1", "delete user 1", "restore user 1"}
+	if strings.Join(audit, ",") != strings.Join(wantAudit, ",") {
+		t.Errorf("Expected audit events %q, got %q", wantAudit, audit)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if _, ok := spec.Components.Schemas["ResourcePageCrudUser"]; !ok {
+		t.Errorf("Expected a page schema, got %v", spec.Components.Schemas)
+	}
+	list := spec.Paths["/v1/users"].Get
+	if list == nil || len(list.Parameters) != 2 || list.Tags[0] != "users" {
+		t.Errorf("Expected a tagged list operation with page parameters, got %+v", list)
+	}
+	if restore := spec.Paths["/v1/users/{id}/restore"].Post; restore == nil {
+		t.Error("Expected a restore operation for a soft-deleting repository")
+	}
+}
@@ -0,0 +1,364 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v3"
+)
+
+// MIMETextCSV is the media type of CSV bodies
+const MIMETextCSV = "text/csv"
+
+// csvDescription documents CSV operations in the spec
+const csvDescription = "Bodies in " + MIMETextCSV + " are comma-separated values with a header row naming the columns after the fields of the documented schema, one row per array element. " +
+	"Empty cells are absent values; nested values are JSON-encoded."
+
+// csvColumn describes a CSV column derived from a struct field
+type csvColumn struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+	field       reflect.StructField
+}
+
+// isCSVContentType reports whether a media type is CSV
+func isCSVContentType(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(contentType)), MIMETextCSV)
+}
+
+// hasCSV reports whether an endpoint accepts or produces CSV bodies
+func hasCSV(endpoint *Endpoint) bool {
+	return hasMediaType(requestMediaTypes(endpoint), isCSVContentType) || hasMediaType(responseMediaTypes(endpoint), isCSVContentType)
+}
+
+// consumesCSV reports whether an endpoint's first request media type is CSV,
+// which the try-it form then sends
+func consumesCSV(endpoint *Endpoint) bool {
+	return len(endpoint.Consumes) > 0 && isCSVContentType(endpoint.Consumes[0])
+}
+
+// hasMediaType reports whether any of mediaTypes matches
+func hasMediaType(mediaTypes []string, match func(string) bool) bool {
+	for _, mediaType := range mediaTypes {
+		if match(mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// csvRowType returns the struct type of a CSV row: the element type of a
+// slice schema, or the struct itself; nil for other schemas
+func csvRowType(schema interface{}) reflect.Type {
+	typ := reflect.TypeOf(schema)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct || validation.IsTimeType(typ) {
+		return nil
+	}
+	return typ
+}
+
+// csvColumns returns the columns of a struct type, named after its JSON fields
+func csvColumns(typ reflect.Type) []csvColumn {
+	var columns []csvColumn
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := validation.JSONFieldName(&field)
+		if name == "-" {
+			continue
+		}
+		columnType := paramType(field.Type)
+		switch valueType := elemType(field.Type); {
+		case validation.IsTimeType(valueType):
+			columnType = "date-time"
+		case valueType.Kind() == reflect.Slice || valueType.Kind() == reflect.Array:
+			columnType = "array"
+		case valueType.Kind() == reflect.Map || (valueType.Kind() == reflect.Struct && !validation.IsNullable(valueType)):
+			columnType = "object"
+		}
+		columns = append(columns, csvColumn{
+			Name:        name,
+			Type:        columnType,
+			Required:    !strings.Contains(field.Tag.Get("json"), "omitempty") && !validation.IsNullable(field.Type),
+			Description: field.Tag.Get("doc"),
+			field:       field,
+		})
+	}
+	return columns
+}
+
+// elemType dereferences pointer types
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// MarshalCSV encodes a slice of structs as CSV: a header row with the JSON
+// names of the fields, then one row per element. Nil pointers are empty
+// cells, time.Time fields use their timeformat tag or RFC 3339, and nested
+// values such as slices or structs are JSON-encoded.
+func MarshalCSV(rows interface{}) ([]byte, error) {
+	value := reflect.ValueOf(rows)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	typ := csvRowType(rows)
+	if typ == nil || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("notelink: MarshalCSV needs a slice of structs, got %T", rows)
+	}
+	columns := csvColumns(typ)
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	for i := 0; i < value.Len(); i++ {
+		row := value.Index(i)
+		for row.Kind() == reflect.Ptr && !row.IsNil() {
+			row = row.Elem()
+		}
+		record := make([]string, len(columns))
+		if row.Kind() == reflect.Struct {
+			for j, column := range columns {
+				cell, err := csvCell(row.FieldByIndex(column.field.Index), &column.field)
+				if err != nil {
+					return nil, err
+				}
+				record[j] = cell
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+// csvCell formats a field value as a CSV cell
+func csvCell(value reflect.Value, field *reflect.StructField) (string, error) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+	if t, ok := value.Interface().(time.Time); ok {
+		return t.Format(validation.FieldTimeFormat(field, time.RFC3339)), nil
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), nil
+	}
+
+	data, err := json.Marshal(value.Interface())
+	if err != nil {
+		return "", err
+	}
+	var text string
+	switch {
+	case string(data) == "null":
+		return "", nil
+	case json.Unmarshal(data, &text) == nil:
+		return text, nil
+	default:
+		return string(data), nil
+	}
+}
+
+// SendCSV responds with rows, a slice of structs, encoded by MarshalCSV as
+// text/csv. Declare the route with Produces: []string{notelink.MIMETextCSV} and
+// the slice type as SchemasResponse to document the columns.
+//
+// Example usage:
+//
+//	api.DocumentedRoute(&notelink.DocumentedRouteInput{
+//	    Method:          "GET",
+//	    Path:            "/v1/reports/sales",
+//	    Produces:        []string{notelink.MIMETextCSV},
+//	    SchemasResponse: []SalesRow{},
+//	    Handler: func(c fiber.Ctx) error {
+//	        return notelink.SendCSV(c, rows)
+//	    },
+//	})
+func SendCSV(c fiber.Ctx, rows interface{}) error {
+	data, err := MarshalCSV(rows)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, MIMETextCSV+"; charset=utf-8")
+	return c.Send(data)
+}
+
+// validateCSV validates the rows of a CSV request body against the row type
+// of schema, converting cells to the column types
+func validateCSV(body []byte, schema interface{}, timeFormat string) error {
+	typ := csvRowType(schema)
+	if typ == nil {
+		return nil
+	}
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return &ValidationErrorResponse{
+			ErrorMessage: "Invalid CSV body",
+			Errors:       []ValidationError{{Field: "body", Message: err.Error(), Type: "parse_error", Code: validation.CodeParse}},
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	types := make(map[string]string)
+	for _, column := range csvColumns(typ) {
+		types[column.Name] = column.Type
+	}
+	header := records[0]
+	var errs []ValidationError
+	for i, record := range records[1:] {
+		row := make(map[string]interface{}, len(record))
+		for j, cell := range record {
+			if j >= len(header) || cell == "" {
+				continue
+			}
+			name := strings.TrimSpace(header[j])
+			row[name] = csvValue(cell, types[name])
+		}
+		for _, err := range validation.Struct(row, typ, timeFormat) {
+			err.Field = fmt.Sprintf("row %d: %s", i+1, err.Field)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationErrorResponse{
+			ErrorMessage: "Request body validation failed",
+			Errors:       errs,
+		}
+	}
+	return nil
+}
+
+// csvValue converts a CSV cell to the JSON value of its column type, leaving
+// cells that don't parse as strings for validation to report
+func csvValue(cell, columnType string) interface{} {
+	switch columnType {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(cell, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(cell); err == nil {
+			return b
+		}
+	case "string", "date-time":
+		return cell
+	default:
+		var value interface{}
+		if err := json.Unmarshal([]byte(cell), &value); err == nil {
+			return value
+		}
+	}
+	return cell
+}
+
+// csvExample renders the generated example of a slice schema as CSV
+func csvExample(schema interface{}, opts exampleOptions) string {
+	typ := csvRowType(schema)
+	if typ == nil {
+		return ""
+	}
+	template, err := generateJSONTemplate(schema, opts)
+	if err != nil {
+		return ""
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal([]byte(template), &rows); err != nil {
+		var row map[string]interface{}
+		if err := json.Unmarshal([]byte(template), &row); err != nil {
+			return ""
+		}
+		rows = []map[string]interface{}{row}
+	}
+
+	columns := csvColumns(typ)
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	writer.Write(header)
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			switch value := row[column.Name].(type) {
+			case nil:
+			case string:
+				record[i] = value
+			default:
+				data, _ := json.Marshal(value)
+				record[i] = string(data)
+			}
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return buf.String()
+}
+
+// renderCSVColumns renders the column table of a CSV request or response body
+func renderCSVColumns(title string, schema interface{}) string {
+	typ := csvRowType(schema)
+	if typ == nil {
+		return ""
+	}
+	var html strings.Builder
+	html.WriteString(`
+                        <div class="csv-columns">
+                            <h5>` + escapeHTML(title) + ` (CSV columns):</h5>
+                            <table>
+                                <thead><tr><th>Column</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>
+                                <tbody>`)
+	for _, column := range csvColumns(typ) {
+		required := "no"
+		if column.Required {
+			required = "yes"
+		}
+		html.WriteString(`
+                                    <tr><td><code>` + escapeHTML(column.Name) + `</code></td><td>` + escapeHTML(column.Type) + `</td><td>` +
+			required + `</td><td>` + escapeHTML(column.Description) + `</td></tr>`)
+	}
+	html.WriteString(`
+                                </tbody>
+                            </table>
+                        </div>`)
+	return html.String()
+}
@@ -0,0 +1,115 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type csvSale struct {
+	Region  string    `json:"region" doc:"Sales region"`
+	Units   int       `json:"units"`
+	Revenue float64   `json:"revenue"`
+	Note    *string   `json:"note"`
+	Tags    []string  `json:"tags,omitempty"`
+	Day     time.Time `json:"day" timeformat:"2006-01-02"`
+	Secret  string    `json:"-"`
+}
+
+// TestMarshalCSV tests encoding struct slices as CSV
+func TestMarshalCSV(t *testing.T) {
+	note := `with "quotes", and commas`
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	data, err := MarshalCSV([]csvSale{
+		{Region: "EU", Units: 3, Revenue: 12.5, Note: &note, Tags: []string{"a", "b"}, Day: day, Secret: "x"},
+		{Region: "US", Units: 1, Revenue: 2, Day: day},
+	})
+	if err != nil {
+		t.Fatalf("MarshalCSV failed: %v", err)
+	}
+	want := "region,units,revenue,note,tags,day\n" +
+		"EU,3,12.5,\"with \"\"quotes\"\", and commas\",\"[\"\"a\"\",\"\"b\"\"]\",2026-03-01\n" +
+		"US,1,2,,,2026-03-01\n"
+	if string(data) != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, data)
+	}
+
+	if _, err := MarshalCSV(csvSale{}); err == nil {
+		t.Error("Expected an error for a non-slice value")
+	}
+}
+
+// TestCSVRoutes tests serving, validating and documenting CSV bodies
+func TestCSVRoutes(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	routes := []DocumentedRouteInput{
+		{Method: "GET", Path: "/v1/sales", Produces: []string{MIMETextCSV}, Responses: map[string]string{"200": "Sales"},
+			SchemasResponse: []csvSale{}, Handler: func(c fiber.Ctx) error {
+				return SendCSV(c, []csvSale{{Region: "EU", Units: 3}})
+			}},
+		{Method: "POST", Path: "/v1/sales", Consumes: []string{MIMETextCSV}, SchemasRequest: []csvSale{},
+			Handler: func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) }},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/v1/sales", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get(fiber.HeaderContentType), MIMETextCSV) || !strings.HasPrefix(string(body), "region,units,revenue,note,tags,day\nEU,3,0,,,") {
+		t.Errorf("Expected a CSV response, got %s %q", resp.Header.Get(fiber.HeaderContentType), body)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"valid rows", "region,units,revenue,note,day\nEU,3,12.5,,2026-03-01\nUS,1,2,hi,2026-03-02\n", 204, ""},
+		{"invalid cell", "region,units,revenue,note,day\nEU,many,12.5,,2026-03-01\n", 400, `"field":"row 1: units"`},
+		{"missing column", "region,revenue,note,day\nEU,12.5,,2026-03-01\nUS,2,,2026-03-02\n", 400, `"field":"row 2: units"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/sales", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, MIMETextCSV)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.want) {
+				t.Errorf("Expected %d containing %s, got %d %s", tt.status, tt.want, resp.StatusCode, body)
+			}
+		})
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	content := spec.Paths["/v1/sales"].Get.Responses["200"].Content[MIMETextCSV]
+	example, _ := content.Example.(string)
+	if content.Schema == nil || content.Schema.Type != "array" || !strings.HasPrefix(example, "region,units,revenue,note,tags,day\n") {
+		t.Errorf("Expected the CSV response to be documented with a CSV example, got %+v", content)
+	}
+	if request := spec.Paths["/v1/sales"].Post.RequestBody.Content[MIMETextCSV]; request.Schema == nil {
+		t.Error("Expected the CSV request body to be documented")
+	}
+
+	html := api.generateHTML()
+	for _, want := range []string{`class="csv-columns"`, `<td><code>region</code></td><td>string</td><td>yes</td><td>Sales region</td>`, `data-csv="true"`, "function renderCSVPreview"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the docs to contain %s", want)
+		}
+	}
+}
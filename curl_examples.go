@@ -0,0 +1,101 @@
+package notelink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CurlExamples renders a shell script per operation with a ready-to-run curl
+// command, for onboarding material of new integrators: the request goes to
+// $BASE_URL (defaulting to the API's address and base path), authenticated
+// routes send $TOKEN or $API_KEY, and JSON bodies are filled with the first
+// request example or a generated one. Path, required query and header
+// parameters get <name> placeholders. Keys are <operationId>.sh; flagged and
+// draft routes hidden from the spec are left out.
+func (an *ApiNote) CurlExamples() map[string][]byte {
+	files := make(map[string][]byte)
+	for _, endpoint := range an.sortedEndpoints() {
+		if an.hiddenByFlag(&endpoint) || an.hiddenAsDraft(&endpoint) {
+			continue
+		}
+		operation := generateOperationID(endpoint.Method, endpoint.routePath())
+		files[operation+".sh"] = []byte(an.curlScript(&endpoint))
+	}
+	return files
+}
+
+// ExportCurlExamples writes the scripts of CurlExamples to dir, executable
+func (an *ApiNote) ExportCurlExamples(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, data := range an.CurlExamples() {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// curlScript renders the curl script of an endpoint
+func (an *ApiNote) curlScript(endpoint *Endpoint) string {
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	if endpoint.Description != "" {
+		script.WriteString("# " + strings.ReplaceAll(endpoint.Description, "\n", "\n# ") + "\n")
+	}
+	script.WriteString("# " + endpoint.Method + " " + endpoint.routePath() + "\n")
+	script.WriteString("#\n# Generated by notelink from the " + an.config.Title + " docs\n\n")
+	script.WriteString(`BASE_URL="${BASE_URL:-` + an.baseURL(nil) + an.config.BasePath + `}"` + "\n\n")
+
+	url := "$BASE_URL" + openAPIPath(endpoint.routePath())
+	var query []string
+	for _, param := range endpoint.Parameters {
+		if param.In == "path" {
+			url = strings.ReplaceAll(url, "{"+param.Name+"}", "<"+param.Name+">")
+		}
+		if param.In == "query" && param.Required {
+			query = append(query, param.Name+"=<"+param.Name+">")
+		}
+	}
+	if len(query) > 0 {
+		url += "?" + strings.Join(query, "&")
+	}
+
+	lines := []string{"curl -X " + strings.ToUpper(endpoint.Method) + ` "` + url + `"`}
+	if endpoint.AuthRequired {
+		lines = append(lines, `-H "Authorization: Bearer ${TOKEN:-<token>}"`)
+	}
+	if endpoint.APIKeyRequired {
+		lines = append(lines, `-H "`+an.apiKeyHeader()+`: ${API_KEY:-<api-key>}"`)
+	}
+	for _, param := range endpoint.Parameters {
+		if param.In == "header" && param.Required {
+			lines = append(lines, "-H "+shellQuote(param.Name+": <"+param.Name+">"))
+		}
+	}
+	if endpoint.RequestSchema != nil {
+		lines = append(lines, "-H 'Content-Type: application/json'", "-d "+shellQuote(an.exampleRequestBody(endpoint)))
+	}
+	script.WriteString(strings.Join(lines, " \\\n  ") + "\n")
+	return script.String()
+}
+
+// exampleRequestBody returns the JSON body of an endpoint's generated
+// requests: its first request example, or one generated from the schema
+func (an *ApiNote) exampleRequestBody(endpoint *Endpoint) string {
+	if len(endpoint.RequestExamples) > 0 {
+		return exampleJSON(endpoint.RequestExamples[0].Value)
+	}
+	template, err := generateJSONTemplate(endpoint.RequestSchema, an.exampleOptions())
+	if err != nil {
+		return ""
+	}
+	return template
+}
+
+// shellQuote quotes a string for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,82 @@
+package notelink
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestExportCurlExamples tests writing a curl script per operation
+func TestExportCurlExamples(t *testing.T) {
+	type note struct {
+		Text string `json:"text" example:"It's done"`
+	}
+	api := NewApiNote(&Config{Title: "Notes API", Host: "api.example.com", BasePath: "/api", HideDraftRoutes: true}, "secret")
+	handler := func(c fiber.Ctx) error { return nil }
+	authRequired := true
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/v1/notes/:id", Description: "Get a note", Handler: handler,
+			Params: []Parameter{{Name: "id", In: "path", Type: "string", Required: true}, {Name: "fields", In: "query", Type: "string"}}},
+		{Method: "POST", Path: "/v1/notes", Description: "Create a note", Handler: handler, AuthRequired: &authRequired, SchemasRequest: note{},
+			Params: []Parameter{{Name: "notebook", In: "query", Type: "string", Required: true}, {Name: "X-Tenant", In: "header", Type: "string", Required: true}}},
+		{Method: "DELETE", Path: "/v1/notes/:id", Description: "Delete a note", Handler: handler, Status: StatusDraft},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := api.ExportCurlExamples(dir); err != nil {
+		t.Fatalf("Failed to export curl examples: %v", err)
+	}
+
+	tests := []struct {
+		file     string
+		contains []string
+	}{
+		{"getNotesById.sh", []string{
+			"#!/bin/sh\n# Get a note\n# GET /v1/notes/:id\n",
+			`BASE_URL="${BASE_URL:-http://api.example.com/api}"`,
+			`curl -X GET "$BASE_URL/v1/notes/<id>"` + "\n",
+		}},
+		{"postNotes.sh", []string{
+			`curl -X POST "$BASE_URL/v1/notes?notebook=<notebook>" \`,
+			`  -H "Authorization: Bearer ${TOKEN:-<token>}" \`,
+			`  -H 'X-Tenant: <X-Tenant>' \`,
+			`  -H 'Content-Type: application/json' \`,
+			`"text": "It'\''s done"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Expected %s to be written: %v", tt.file, err)
+			}
+			for _, snippet := range tt.contains {
+				if !strings.Contains(string(data), snippet) {
+					t.Errorf("Expected %s to contain %q, got:\n%s", tt.file, snippet, data)
+				}
+			}
+			if info, err := os.Stat(path); err == nil && info.Mode()&0o100 == 0 {
+				t.Errorf("Expected %s to be executable", tt.file)
+			}
+			if sh, err := exec.LookPath("sh"); err == nil {
+				if out, err := exec.Command(sh, "-n", path).CombinedOutput(); err != nil {
+					t.Errorf("Expected %s to be valid shell: %v\n%s", tt.file, err, out)
+				}
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "deleteNotesById.sh")); err == nil {
+		t.Error("Expected no script for the hidden draft route")
+	}
+}
@@ -0,0 +1,143 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// DemoConfig enables endpoints creating and resetting sample data, so the try-it
+// forms of shared dev environments always have data to return. Never set it in
+// production.
+type DemoConfig struct {
+	// Datasets are generated into MockState collections on each seed
+	Datasets []DemoDataset
+	// Seed inserts custom sample data, e.g. into the dev database, after the
+	// datasets. Seeding runs repeatedly, so it must be idempotent.
+	Seed func(c fiber.Ctx, state *MockState) error
+	// Reset removes the custom sample data
+	Reset func(c fiber.Ctx, state *MockState) error
+	// AuthRequired protects the demo endpoints like authenticated routes
+	AuthRequired bool
+}
+
+// DemoDataset generates Count resources of Schema's example into a MockState
+// collection, with ids "1" to Count, e.g. to page through in the try-it forms
+type DemoDataset struct {
+	Collection string
+	Count      int
+	Schema     interface{}
+}
+
+// DemoSeedResult reports the resources per collection after seeding
+type DemoSeedResult struct {
+	Collections map[string]int `json:"collections"`
+}
+
+// mountDemo registers the documented demo endpoints under Config.BasePath
+func (an *ApiNote) mountDemo() {
+	demo := an.config.Demo
+	authRequired := demo.AuthRequired
+	routes := []DocumentedRouteInput{
+		{
+			Method:          http.MethodPost,
+			Path:            "/demo/seed",
+			Description:     "Replace the sample data with a fresh copy. Seeding is idempotent: repeated calls leave the same data.",
+			Responses:       map[string]string{"200": "Sample data seeded"},
+			SchemasResponse: DemoSeedResult{},
+			AuthRequired:    &authRequired,
+			Tags:            []string{"demo"},
+			Handler:         an.seedDemoHandler,
+			builtin:         true,
+		},
+		{
+			Method:       http.MethodPost,
+			Path:         "/demo/reset",
+			Description:  "Remove the sample data",
+			Responses:    map[string]string{"204": "Sample data removed"},
+			AuthRequired: &authRequired,
+			Tags:         []string{"demo"},
+			Handler:      an.resetDemoHandler,
+			builtin:      true,
+		},
+	}
+	for i := range routes {
+		if err := an.DocumentedRoute(&routes[i]); err != nil {
+			log.Errorf("notelink: registering %s %s: %v", routes[i].Method, routes[i].Path, err)
+		}
+	}
+}
+
+// seedDemoHandler replaces the sample data
+func (an *ApiNote) seedDemoHandler(c fiber.Ctx) error {
+	result, err := an.SeedDemo(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(result)
+}
+
+// resetDemoHandler removes the sample data
+func (an *ApiNote) resetDemoHandler(c fiber.Ctx) error {
+	if err := an.resetDemo(c); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SeedDemo replaces the sample data of Config.Demo, e.g. on startup; c is
+// passed to DemoConfig.Seed and may be nil outside a request
+func (an *ApiNote) SeedDemo(c fiber.Ctx) (*DemoSeedResult, error) {
+	demo := an.config.Demo
+	if demo == nil {
+		return nil, fmt.Errorf("demo data is not configured")
+	}
+	if err := an.resetDemo(c); err != nil {
+		return nil, err
+	}
+
+	state := an.MockState()
+	result := &DemoSeedResult{Collections: make(map[string]int)}
+	for _, dataset := range demo.Datasets {
+		template, err := generateJSONTemplate(dataset.Schema, an.exampleOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s sample data: %w", dataset.Collection, err)
+		}
+		for i := 1; i <= dataset.Count; i++ {
+			var resource map[string]interface{}
+			if err := json.Unmarshal([]byte(template), &resource); err != nil {
+				return nil, fmt.Errorf("%s sample data must be an object: %w", dataset.Collection, err)
+			}
+			resource["id"] = strconv.Itoa(i)
+			state.Create(dataset.Collection, resource)
+		}
+	}
+	if demo.Seed != nil {
+		if err := demo.Seed(c, state); err != nil {
+			return nil, fmt.Errorf("failed to seed demo data: %w", err)
+		}
+	}
+
+	for _, dataset := range demo.Datasets {
+		result.Collections[dataset.Collection] = len(state.List(dataset.Collection))
+	}
+	return result, nil
+}
+
+// resetDemo removes the datasets and the custom sample data
+func (an *ApiNote) resetDemo(c fiber.Ctx) error {
+	state := an.MockState()
+	for _, dataset := range an.config.Demo.Datasets {
+		state.Clear(dataset.Collection)
+	}
+	if an.config.Demo.Reset != nil {
+		if err := an.config.Demo.Reset(c, state); err != nil {
+			return fmt.Errorf("failed to reset demo data: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package notelink
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type demoUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TestDemoData tests seeding and resetting sample data through the demo endpoints
+func TestDemoData(t *testing.T) {
+	seeded := 0
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		Mock:  true,
+		Demo: &DemoConfig{
+			Datasets: []DemoDataset{{Collection: "users", Count: 25, Schema: demoUser{}}},
+			Seed: func(c fiber.Ctx, state *MockState) error {
+				seeded++
+				state.Create("orders", map[string]interface{}{"id": "1", "user": "1"})
+				return nil
+			},
+			Reset: func(c fiber.Ctx, state *MockState) error {
+				state.Clear("orders")
+				return nil
+			},
+		},
+	}, "secret")
+
+	post := func(path string, expected int) *DemoSeedResult {
+		t.Helper()
+		resp, err := api.Fiber().Test(httptest.NewRequest("POST", path, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		if resp.StatusCode != expected {
+			t.Fatalf("Expected status %d from %s, got %d", expected, path, resp.StatusCode)
+		}
+		var result DemoSeedResult
+		_ = json.NewDecoder(resp.Body).Decode(&result)
+		return &result
+	}
+
+	// Seeding twice leaves the same data
+	post("/demo/seed", fiber.StatusOK)
+	result := post("/demo/seed", fiber.StatusOK)
+	state := api.MockState()
+	if result.Collections["users"] != 25 || len(state.List("users")) != 25 || len(state.List("orders")) != 1 {
+		t.Fatalf("Expected 25 users and 1 order after seeding twice, got %v and %d orders", result.Collections, len(state.List("orders")))
+	}
+	if user, ok := state.Get("users", "25"); !ok || user["name"] == nil {
+		t.Errorf("Expected generated users with ids 1 to 25, got %v", user)
+	}
+	if seeded != 2 {
+		t.Errorf("Expected the custom seed to run on each seed, ran %d times", seeded)
+	}
+
+	post("/demo/reset", fiber.StatusNoContent)
+	if len(state.List("users")) != 0 || len(state.List("orders")) != 0 {
+		t.Error("Expected reset to remove the sample data")
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if operation := spec.Paths["/demo/seed"].Post; operation == nil || operation.Tags[0] != "demo" {
+		t.Errorf("Expected a documented seed endpoint, got %+v", operation)
+	}
+
+	// Without Config.Demo the endpoints don't exist
+	api = NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	post("/demo/seed", fiber.StatusNotFound)
+}
@@ -0,0 +1,319 @@
+package notelink
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// isDeprecatedField reports whether a struct field is tagged `deprecated:"true"`
+func isDeprecatedField(field *reflect.StructField) bool {
+	return field.Tag.Get("deprecated") == "true"
+}
+
+// hasDeprecatedFields reports whether a type has deprecated fields at any depth
+func hasDeprecatedFields(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType || visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || validation.JSONFieldName(&field) == "-" {
+			continue
+		}
+		if isDeprecatedField(&field) || hasDeprecatedFields(field.Type, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// receivedDeprecatedFields returns the dotted paths of the deprecated fields of
+// t set in a decoded body, e.g. "address.zip" or "items[].sku"
+func receivedDeprecatedFields(value interface{}, t reflect.Type, prefix string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var fields []string
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		items, _ := value.([]interface{})
+		seen := make(map[string]bool)
+		for _, item := range items {
+			for _, field := range receivedDeprecatedFields(item, t.Elem(), prefix+"[]") {
+				if !seen[field] {
+					seen[field] = true
+					fields = append(fields, field)
+				}
+			}
+		}
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok || t == timeType {
+			return nil
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := validation.JSONFieldName(&field)
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			fieldValue, exists := object[name]
+			if !exists || fieldValue == nil {
+				continue
+			}
+			path := name
+			if prefix != "" {
+				path = prefix + "." + name
+			}
+			if isDeprecatedField(&field) {
+				fields = append(fields, path)
+			}
+			fields = append(fields, receivedDeprecatedFields(fieldValue, field.Type, path)...)
+		}
+	}
+	return fields
+}
+
+// DeprecatedFieldUsage counts the requests to a route that set a deprecated field
+type DeprecatedFieldUsage struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Field  string `json:"field"`
+	Count  int64  `json:"count"`
+}
+
+// deprecatedFieldUsage records the deprecated fields received per route
+type deprecatedFieldUsage struct {
+	mu     sync.Mutex
+	counts map[DeprecatedFieldUsage]int64 // Keyed without Count
+}
+
+// record counts a received deprecated field, warning the first time a route receives it
+func (u *deprecatedFieldUsage) record(method, path, field string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.counts == nil {
+		u.counts = make(map[DeprecatedFieldUsage]int64)
+	}
+	key := DeprecatedFieldUsage{Method: method, Path: path, Field: field}
+	if u.counts[key] == 0 {
+		log.Warnf("notelink: %s %s received deprecated field %q", method, path, field)
+	}
+	u.counts[key]++
+}
+
+// DeprecatedFieldUsage returns how often each route received each deprecated
+// request field, ordered by route and field, e.g. to check whether clients
+// still send a field before removing it
+func (an *ApiNote) DeprecatedFieldUsage() []DeprecatedFieldUsage {
+	an.deprecatedFields.mu.Lock()
+	defer an.deprecatedFields.mu.Unlock()
+	usage := make([]DeprecatedFieldUsage, 0, len(an.deprecatedFields.counts))
+	for key, count := range an.deprecatedFields.counts {
+		key.Count = count
+		usage = append(usage, key)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Path != usage[j].Path {
+			return usage[i].Path < usage[j].Path
+		}
+		if usage[i].Method != usage[j].Method {
+			return usage[i].Method < usage[j].Method
+		}
+		return usage[i].Field < usage[j].Field
+	})
+	return usage
+}
+
+// deprecatedFieldsMiddleware records the deprecated fields set in valid request
+// bodies of an endpoint; it runs after validation
+func (an *ApiNote) deprecatedFieldsMiddleware(endpoint *Endpoint) fiber.Handler {
+	schemaType := reflect.TypeOf(endpoint.RequestSchema)
+	method, path := endpoint.Method, endpoint.Path
+	return func(c fiber.Ctx) error {
+		if len(c.Body()) > 0 {
+			if body, err := decodeRequestBody(c); err == nil {
+				for _, field := range receivedDeprecatedFields(body, schemaType, "") {
+					an.deprecatedFields.record(method, path, field)
+				}
+			}
+		}
+		return c.Next()
+	}
+}
+
+// DefaultAPIKeyHeader is the header callers of deprecated routes are
+// identified by when Config.APIKeyHeader is unset
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// maxDeprecatedCallers caps the callers tracked per deprecated route; further
+// callers are counted under an empty identity
+const maxDeprecatedCallers = 1000
+
+// DeprecatedRouteCaller counts the requests of a caller to a deprecated route
+type DeprecatedRouteCaller struct {
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Subject   string    `json:"subject,omitempty"` // JWT sub claim
+	APIKey    string    `json:"api_key,omitempty"` // SHA-256 fingerprint of the API key, never the key
+	UserAgent string    `json:"user_agent,omitempty"`
+	Count     int64     `json:"count"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// deprecatedCaller identifies a caller of a deprecated route
+type deprecatedCaller struct {
+	method, path, subject, apiKey, userAgent string
+}
+
+// deprecatedRouteUsage records the callers of deprecated routes
+type deprecatedRouteUsage struct {
+	mu      sync.Mutex
+	callers map[deprecatedCaller]*DeprecatedRouteCaller
+	routes  map[[2]string]int // Callers tracked per method and path
+}
+
+// record counts a request, warning the first time a route is called
+func (u *deprecatedRouteUsage) record(caller deprecatedCaller, now time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.callers == nil {
+		u.callers = make(map[deprecatedCaller]*DeprecatedRouteCaller)
+		u.routes = make(map[[2]string]int)
+	}
+	usage, ok := u.callers[caller]
+	if !ok {
+		route := [2]string{caller.method, caller.path}
+		if u.routes[route] == 0 {
+			log.Warnf("notelink: deprecated route %s %s was called", caller.method, caller.path)
+		}
+		if u.routes[route] >= maxDeprecatedCallers {
+			caller = deprecatedCaller{method: caller.method, path: caller.path}
+			usage, ok = u.callers[caller]
+		}
+		if !ok {
+			// The identity may reference the request's buffers, reused after it
+			caller = deprecatedCaller{method: caller.method, path: caller.path, subject: strings.Clone(caller.subject), apiKey: caller.apiKey, userAgent: strings.Clone(caller.userAgent)}
+			usage = &DeprecatedRouteCaller{Method: caller.method, Path: caller.path, Subject: caller.subject, APIKey: caller.apiKey, UserAgent: caller.userAgent}
+			u.callers[caller] = usage
+			u.routes[route]++
+		}
+	}
+	usage.Count++
+	usage.LastSeen = now
+}
+
+// DeprecatedRouteUsage returns who still calls the deprecated routes, by JWT
+// subject, API key fingerprint and User-Agent, ordered by route and then by
+// the most frequent callers, e.g. to plan and announce removals
+func (an *ApiNote) DeprecatedRouteUsage() []DeprecatedRouteCaller {
+	an.deprecatedRoutes.mu.Lock()
+	defer an.deprecatedRoutes.mu.Unlock()
+	usage := make([]DeprecatedRouteCaller, 0, len(an.deprecatedRoutes.callers))
+	for _, caller := range an.deprecatedRoutes.callers {
+		usage = append(usage, *caller)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Path != usage[j].Path {
+			return usage[i].Path < usage[j].Path
+		}
+		if usage[i].Method != usage[j].Method {
+			return usage[i].Method < usage[j].Method
+		}
+		if usage[i].Count != usage[j].Count {
+			return usage[i].Count > usage[j].Count
+		}
+		return usage[i].LastSeen.After(usage[j].LastSeen)
+	})
+	return usage
+}
+
+// apiKeyHeader returns Config.APIKeyHeader, or its default
+func (an *ApiNote) apiKeyHeader() string {
+	if an.config.APIKeyHeader == "" {
+		return DefaultAPIKeyHeader
+	}
+	return an.config.APIKeyHeader
+}
+
+// deprecatedRouteMiddleware announces the deprecation with the Deprecation
+// header, and the MigrationGuide with the Sunset and successor Link headers,
+// and records the caller; it runs after authentication
+func (an *ApiNote) deprecatedRouteMiddleware(endpoint *Endpoint) fiber.Handler {
+	method, path := endpoint.Method, endpoint.Path
+	apiKeyHeader := an.apiKeyHeader()
+	guide := endpoint.Migration
+	// The replacement may be registered after the route
+	var successorOnce sync.Once
+	var successor string
+	return func(c fiber.Ctx) error {
+		c.Set("Deprecation", "true")
+		if guide != nil {
+			if sunset := guide.sunsetHeader(); sunset != "" {
+				c.Set("Sunset", sunset)
+			}
+			successorOnce.Do(func() { successor = an.successorLink(guide) })
+			if successor != "" {
+				c.Append(fiber.HeaderLink, successor)
+			}
+		}
+		caller := deprecatedCaller{method: method, path: path, subject: an.callerSubject(c), userAgent: c.Get(fiber.HeaderUserAgent)}
+		if key := c.Get(apiKeyHeader); key != "" {
+			sum := sha256.Sum256([]byte(key))
+			caller.apiKey = hex.EncodeToString(sum[:6])
+		}
+		an.deprecatedRoutes.record(caller, time.Now())
+		return c.Next()
+	}
+}
+
+// callerSubject returns the verified JWT subject of the request: the
+// "user_id" set by JWTMiddleware or, on routes without authentication, the sub
+// claim of a bearer token signed with the API's secret. Unverified tokens
+// identify no one, so clients can't report arbitrary subjects.
+func (an *ApiNote) callerSubject(c fiber.Ctx) string {
+	if subject, ok := c.Locals("user_id").(string); ok {
+		return subject
+	}
+	token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+	if !ok {
+		return ""
+	}
+	claims, err := an.parseToken(token)
+	if err != nil {
+		return ""
+	}
+	subject, _ := claims.GetSubject()
+	return subject
+}
+
+// deprecationsHandler serves the usage of deprecated routes and fields
+func (an *ApiNote) deprecationsHandler(c fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"routes": an.DeprecatedRouteUsage(),
+		"fields": an.DeprecatedFieldUsage(),
+	})
+}
+
+// renderDeprecatedBadge renders the badge of a deprecated endpoint
+func renderDeprecatedBadge(endpoint *Endpoint) string {
+	if !endpoint.Deprecated {
+		return ""
+	}
+	return `<span class="deprecated-badge" title="Deprecated; see the deprecation report for its remaining callers"><i class="fas fa-ban"></i> deprecated</span>`
+}
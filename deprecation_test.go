@@ -0,0 +1,224 @@
+package notelink
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type deprecationLine struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity,omitempty" deprecated:"true"`
+}
+
+type deprecationOrder struct {
+	Customer     string            `json:"customer"`
+	CustomerName string            `json:"customerName,omitempty" deprecated:"true"`
+	Lines        []deprecationLine `json:"lines,omitempty"`
+}
+
+// TestDeprecatedFields tests documenting deprecated fields and counting their use
+func TestDeprecatedFields(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "POST",
+		Path:            "/v1/orders",
+		SchemasRequest:  deprecationOrder{},
+		SchemasResponse: deprecationOrder{},
+		Handler:         func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	schemas := api.GenerateOpenAPISpec().Components.Schemas
+	if !schemas["deprecationOrder"].Properties["customerName"].Deprecated || schemas["deprecationOrder"].Properties["customer"].Deprecated {
+		t.Errorf("Expected only customerName to be deprecated, got %+v", schemas["deprecationOrder"].Properties)
+	}
+	if !schemas["deprecationLine"].Properties["quantity"].Deprecated {
+		t.Error("Expected nested deprecated fields in their component schema")
+	}
+	if ts := generateTypeScriptSchema("Order", deprecationOrder{}, "", nil); !strings.Contains(ts, "  /** @deprecated */\n  customerName?: string;") {
+		t.Errorf("Expected a @deprecated JSDoc tag, got %s", ts)
+	}
+
+	bodies := []string{
+		`{"customer":"c1"}`,
+		`{"customer":"c1","customerName":"Ada","lines":[{"sku":"a","quantity":1},{"sku":"b","quantity":2}]}`,
+		`{"customer":"c1","customerName":"Ada"}`,
+	}
+	for _, body := range bodies {
+		req := httptest.NewRequest("POST", "/v1/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusCreated {
+			t.Errorf("Expected deprecated fields to be accepted, got %d", resp.StatusCode)
+		}
+	}
+
+	expected := []DeprecatedFieldUsage{
+		{Method: "POST", Path: "/v1/orders", Field: "customerName", Count: 2},
+		{Method: "POST", Path: "/v1/orders", Field: "lines[].quantity", Count: 1},
+	}
+	usage := api.DeprecatedFieldUsage()
+	if len(usage) != len(expected) {
+		t.Fatalf("Expected usage %+v, got %+v", expected, usage)
+	}
+	for i := range expected {
+		if usage[i] != expected[i] {
+			t.Errorf("Expected usage %+v, got %+v", expected[i], usage[i])
+		}
+	}
+}
+
+// TestDeprecatedRoutes tests marking routes deprecated and reporting their callers
+func TestDeprecatedRoutes(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", APIKeyHeader: "X-Partner-Key", PublicDiagnostics: true}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/v1/orders",
+		Description: "List orders",
+		Deprecated:  true,
+		Handler:     func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	if !api.GenerateOpenAPISpec().Paths["/v1/orders"].Get.Deprecated {
+		t.Error("Expected the operation deprecated in the spec")
+	}
+	if !strings.Contains(api.generateHTML(), `class="deprecated-badge"`) {
+		t.Error("Expected the deprecated badge in the docs")
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "partner-a"}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	requests := []map[string]string{
+		{"Authorization": "Bearer " + token, "User-Agent": "partner-sdk/1.2"},
+		{"Authorization": "Bearer " + token, "User-Agent": "partner-sdk/1.2"},
+		{"X-Partner-Key": "key-123", "User-Agent": "curl/8.0"},
+	}
+	for _, headers := range requests {
+		req := httptest.NewRequest("GET", "/v1/orders", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.Header.Get("Deprecation") != "true" {
+			t.Errorf("Expected the Deprecation header, got %q", resp.Header.Get("Deprecation"))
+		}
+	}
+
+	usage := api.DeprecatedRouteUsage()
+	if len(usage) != 2 {
+		t.Fatalf("Expected 2 callers, got %+v", usage)
+	}
+	if usage[0].Subject != "partner-a" || usage[0].UserAgent != "partner-sdk/1.2" || usage[0].Count != 2 || usage[0].LastSeen.IsZero() {
+		t.Errorf("Expected the JWT caller first, got %+v", usage[0])
+	}
+	sum := sha256.Sum256([]byte("key-123"))
+	if usage[1].APIKey != hex.EncodeToString(sum[:6]) || usage[1].Count != 1 {
+		t.Errorf("Expected the API key fingerprint, got %+v", usage[1])
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/deprecations", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	var report struct {
+		Routes []DeprecatedRouteCaller `json:"routes"`
+		Fields []DeprecatedFieldUsage  `json:"fields"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if len(report.Routes) != 2 || resp.Header.Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected the callers in an uncached report, got %+v (%s)", report, resp.Header.Get("Cache-Control"))
+	}
+
+	// Tokens the API didn't sign identify no one
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "partner-b"}).SignedString([]byte("other-secret"))
+	req := httptest.NewRequest("GET", "/v1/orders", nil)
+	req.Header.Set("Authorization", "Bearer "+forged)
+	if _, err := api.Fiber().Test(req); err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	for _, caller := range api.DeprecatedRouteUsage() {
+		if caller.Subject == "partner-b" {
+			t.Errorf("Expected the forged subject not recorded, got %+v", caller)
+		}
+	}
+}
+
+// TestDeprecationsReportAccess tests serving the callers report only behind
+// DocsAuth or with PublicDiagnostics
+func TestDeprecationsReportAccess(t *testing.T) {
+	docsAuth := func(c fiber.Ctx) error {
+		if c.Get("Authorization") != "Bearer staff" {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return c.Next()
+	}
+	tests := []struct {
+		name   string
+		config Config
+		token  string
+		status int
+	}{
+		{name: "public docs", config: Config{Title: "Test API"}, status: fiber.StatusNotFound},
+		{name: "opt-in", config: Config{Title: "Test API", PublicDiagnostics: true}, status: fiber.StatusOK},
+		{name: "anonymous behind DocsAuth", config: Config{Title: "Test API", DocsAuth: docsAuth}, status: fiber.StatusUnauthorized},
+		{name: "staff behind DocsAuth", config: Config{Title: "Test API", DocsAuth: docsAuth}, token: "staff", status: fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&tt.config, "secret")
+			req := httptest.NewRequest("GET", "/api-docs/deprecations", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
+
+// TestDeprecatedRouteCallerCap tests bounding the callers tracked per route
+func TestDeprecatedRouteCallerCap(t *testing.T) {
+	var usage deprecatedRouteUsage
+	now := time.Now()
+	for i := 0; i < maxDeprecatedCallers+5; i++ {
+		usage.record(deprecatedCaller{method: "GET", path: "/v1/orders", userAgent: strconv.Itoa(i)}, now)
+	}
+	if len(usage.callers) != maxDeprecatedCallers+1 {
+		t.Errorf("Expected %d callers, got %d", maxDeprecatedCallers+1, len(usage.callers))
+	}
+	if other := usage.callers[deprecatedCaller{method: "GET", path: "/v1/orders"}]; other == nil || other.Count != 5 {
+		t.Errorf("Expected the overflow counted anonymously, got %+v", other)
+	}
+}
@@ -0,0 +1,431 @@
+package notelink
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+const (
+	// DefaultDeviceAuthPath is where the device authorization endpoints are
+	// mounted, relative to Config.BasePath
+	DefaultDeviceAuthPath = "/auth/device"
+	// DefaultDeviceCodeTTL is how long a device code can be approved
+	DefaultDeviceCodeTTL = 10 * time.Minute
+	// DefaultDevicePollInterval is the minimum time between token polls
+	DefaultDevicePollInterval = 5 * time.Second
+)
+
+// maxDeviceAuthorizations caps the device codes pending at once
+const maxDeviceAuthorizations = 1000
+
+// userCodeAlphabet has no vowels or look-alike characters, as RFC 8628
+// recommends for codes typed by hand
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// Device token errors of RFC 8628, section 3.5
+const (
+	deviceAuthorizationPending = "authorization_pending"
+	deviceSlowDown             = "slow_down"
+	deviceExpiredToken         = "expired_token"
+)
+
+// DeviceAuthConfig mounts a device authorization flow (RFC 8628) issuing the
+// JWTs JWTMiddleware accepts, for docs opened where sign-in redirects don't
+// work, e.g. kiosks or embedded browsers: POST {Path}/code starts a sign-in
+// and returns a user code, the user approves it with their credentials on
+// the page at {Path}, from any device, while POST {Path}/token is polled for
+// the tokens. The docs page runs the flow from its Authorize section and the
+// spec documents it as the deviceAuth security scheme. Config.Tokens sets the
+// lifetimes and issuer of the tokens.
+type DeviceAuthConfig struct {
+	Verify       CredentialVerifier // Required
+	Path         string             // Defaults to DefaultDeviceAuthPath
+	CodeTTL      time.Duration      // Defaults to DefaultDeviceCodeTTL
+	PollInterval time.Duration      // Defaults to DefaultDevicePollInterval
+}
+
+// DeviceCodeResponse starts a device sign-in, as in RFC 8628 device
+// authorization responses
+type DeviceCodeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"` // Seconds until the codes expire
+	Interval                int    `json:"interval"`   // Seconds to wait between token polls
+}
+
+// DeviceTokenRequest polls for the tokens of a device sign-in
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code"`
+}
+
+// path returns the mount path of the device authorization endpoints
+func (d *DeviceAuthConfig) path() string {
+	if d.Path == "" {
+		return DefaultDeviceAuthPath
+	}
+	return "/" + strings.Trim(d.Path, "/")
+}
+
+// codeTTL returns the device code lifetime
+func (d *DeviceAuthConfig) codeTTL() time.Duration {
+	if d.CodeTTL <= 0 {
+		return DefaultDeviceCodeTTL
+	}
+	return d.CodeTTL
+}
+
+// pollInterval returns the minimum time between token polls
+func (d *DeviceAuthConfig) pollInterval() time.Duration {
+	if d.PollInterval <= 0 {
+		return DefaultDevicePollInterval
+	}
+	return d.PollInterval
+}
+
+// deviceGrant is a pending device sign-in
+type deviceGrant struct {
+	userCode string
+	expires  time.Time
+	lastPoll time.Time
+	interval time.Duration // Grows with every poll that comes too early
+	approved bool
+	subject  string
+	claims   map[string]interface{}
+}
+
+// deviceAuthorizations holds the pending device sign-ins by device code
+type deviceAuthorizations struct {
+	mu        sync.Mutex
+	grants    map[string]*deviceGrant
+	userCodes map[string]string // Device codes by user code
+}
+
+// start registers a device sign-in, returning its device and user codes, or
+// false when too many are pending
+func (d *deviceAuthorizations) start(ttl, interval time.Duration, now time.Time) (string, string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.grants == nil {
+		d.grants = make(map[string]*deviceGrant)
+		d.userCodes = make(map[string]string)
+	}
+	for deviceCode, grant := range d.grants {
+		if now.After(grant.expires) {
+			d.remove(deviceCode)
+		}
+	}
+	if len(d.grants) >= maxDeviceAuthorizations {
+		return "", "", false
+	}
+
+	id := make([]byte, 32)
+	_, _ = rand.Read(id)
+	deviceCode := hex.EncodeToString(id)
+	userCode := newUserCode()
+	for _, taken := d.userCodes[userCode]; taken; _, taken = d.userCodes[userCode] {
+		userCode = newUserCode()
+	}
+	d.grants[deviceCode] = &deviceGrant{userCode: userCode, expires: now.Add(ttl), interval: interval}
+	d.userCodes[userCode] = deviceCode
+	return deviceCode, userCode, true
+}
+
+// approve signs in the pending device sign-in of a user code
+func (d *deviceAuthorizations) approve(userCode, subject string, claims map[string]interface{}, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	grant, ok := d.grants[d.userCodes[normalizeUserCode(userCode)]]
+	if !ok || grant.approved || now.After(grant.expires) {
+		return false
+	}
+	grant.approved, grant.subject, grant.claims = true, subject, claims
+	return true
+}
+
+// poll returns the subject and claims of an approved device sign-in, removing
+// it, or the RFC 8628 error code telling the client how to go on
+func (d *deviceAuthorizations) poll(deviceCode string, now time.Time) (*deviceGrant, string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	grant, ok := d.grants[deviceCode]
+	if !ok || now.After(grant.expires) {
+		d.remove(deviceCode)
+		return nil, deviceExpiredToken
+	}
+	if !grant.lastPoll.IsZero() && now.Sub(grant.lastPoll) < grant.interval {
+		grant.lastPoll = now
+		grant.interval += 5 * time.Second
+		return nil, deviceSlowDown
+	}
+	grant.lastPoll = now
+	if !grant.approved {
+		return nil, deviceAuthorizationPending
+	}
+	d.remove(deviceCode)
+	return grant, ""
+}
+
+// remove drops a device sign-in; the caller holds the lock
+func (d *deviceAuthorizations) remove(deviceCode string) {
+	if grant, ok := d.grants[deviceCode]; ok {
+		delete(d.userCodes, grant.userCode)
+		delete(d.grants, deviceCode)
+	}
+}
+
+// newUserCode returns a random user code, e.g. "WDJB-MJHT"
+func newUserCode() string {
+	raw := make([]byte, 8)
+	_, _ = rand.Read(raw)
+	code := make([]byte, 0, 9)
+	for i, b := range raw {
+		if i == 4 {
+			code = append(code, '-')
+		}
+		code = append(code, userCodeAlphabet[int(b)%len(userCodeAlphabet)])
+	}
+	return string(code)
+}
+
+// normalizeUserCode uppercases a typed user code and restores its dash
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	if len(code) != 8 {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
+
+// mountDeviceAuth registers the documented device authorization endpoints
+// and the verification page
+func (an *ApiNote) mountDeviceAuth() {
+	if an.config.DeviceAuth.Verify == nil {
+		log.Errorf("notelink: Config.DeviceAuth.Verify is required, the device authorization endpoints aren't mounted")
+		return
+	}
+	noAuth := false
+	path := an.config.DeviceAuth.path()
+	routes := []DocumentedRouteInput{
+		{
+			Method:          http.MethodPost,
+			Path:            path + "/code",
+			Description:     fmt.Sprintf("Start a device sign-in; the user approves the returned user code at verification_uri within %s", an.config.DeviceAuth.codeTTL()),
+			Responses:       map[string]string{"200": "Device sign-in started", "503": "Too many device sign-ins pending"},
+			SchemasResponse: DeviceCodeResponse{},
+			Handler:         an.deviceCodeHandler,
+		},
+		{
+			Method:          http.MethodPost,
+			Path:            path + "/token",
+			Description:     "Poll for the tokens of an approved device sign-in; until then responds 400 with error authorization_pending, slow_down when polled faster than the interval, or expired_token",
+			Responses:       map[string]string{"200": "Tokens issued", "400": "Not approved yet, polled too fast or expired"},
+			SchemasRequest:  DeviceTokenRequest{},
+			SchemasResponse: TokenResponse{},
+			Handler:         an.deviceTokenHandler,
+		},
+	}
+	for i := range routes {
+		routes[i].AuthRequired = &noAuth
+		routes[i].Tags = []string{"auth"}
+		routes[i].builtin = true
+		if err := an.DocumentedRoute(&routes[i]); err != nil {
+			log.Errorf("notelink: registering %s %s: %v", routes[i].Method, routes[i].Path, err)
+		}
+	}
+
+	// The verification page is for people, so it stays out of the spec
+	an.app.Get(an.config.BasePath+path, an.deviceVerificationPage)
+	an.app.Post(an.config.BasePath+path, an.deviceApproveHandler)
+}
+
+// deviceCodeHandler starts a device sign-in
+func (an *ApiNote) deviceCodeHandler(c fiber.Ctx) error {
+	config := an.config.DeviceAuth
+	deviceCode, userCode, ok := an.deviceAuth.start(config.codeTTL(), config.pollInterval(), time.Now())
+	if !ok {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Too many device sign-ins pending")
+	}
+	verificationURI := an.baseURL(c) + an.config.BasePath + config.path()
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(DeviceCodeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?user_code=" + userCode,
+		ExpiresIn:               int(config.codeTTL().Seconds()),
+		Interval:                int(config.pollInterval().Seconds()),
+	})
+}
+
+// deviceTokenHandler issues tokens for an approved device code
+func (an *ApiNote) deviceTokenHandler(c fiber.Ctx) error {
+	var req DeviceTokenRequest
+	if err := c.Bind().JSON(&req); err != nil || req.DeviceCode == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid device token request")
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	grant, pending := an.deviceAuth.poll(req.DeviceCode, time.Now())
+	if grant == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{Error: pending})
+	}
+	tokens, err := an.IssueTokens(grant.subject, grant.claims)
+	if err != nil {
+		return err
+	}
+	return c.JSON(tokens)
+}
+
+// deviceVerificationPage serves the form approving a user code
+func (an *ApiNote) deviceVerificationPage(c fiber.Ctx) error {
+	return an.sendDeviceVerificationPage(c, fiber.StatusOK, c.Query("user_code"), "")
+}
+
+// deviceApproveHandler approves a user code for the submitted credentials
+func (an *ApiNote) deviceApproveHandler(c fiber.Ctx) error {
+	userCode := c.FormValue("user_code")
+	subject, claims, err := an.config.DeviceAuth.Verify(c, c.FormValue("username"), c.FormValue("password"))
+	if errors.Is(err, ErrInvalidCredentials) {
+		return an.sendDeviceVerificationPage(c, fiber.StatusUnauthorized, userCode, "Invalid credentials")
+	}
+	if err != nil {
+		return err
+	}
+	if !an.deviceAuth.approve(userCode, subject, claims, time.Now()) {
+		return an.sendDeviceVerificationPage(c, fiber.StatusBadRequest, userCode, "Unknown or expired code")
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="UTF-8"><title>` + escapeHTML(an.config.Title) + ` - Device sign-in</title></head>
+<body>
+<h1>Device signed in</h1>
+<p>You can close this page and return to your device.</p>
+</body>
+</html>`)
+}
+
+// sendDeviceVerificationPage renders the form approving a user code, with an
+// error message of a failed attempt
+func (an *ApiNote) sendDeviceVerificationPage(c fiber.Ctx, status int, userCode, message string) error {
+	if message != "" {
+		message = `
+<p role="alert">` + escapeHTML(message) + `</p>`
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(status).SendString(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="UTF-8"><meta name="viewport" content="width=device-width, initial-scale=1.0"><title>` + escapeHTML(an.config.Title) + ` - Device sign-in</title></head>
+<body>
+<h1>Sign in a device</h1>
+<p>Enter the code shown on your device and your credentials.</p>` + message + `
+<form method="post">
+<p><label>Code <input type="text" name="user_code" value="` + escapeHTML(userCode) + `" autocomplete="off" required></label></p>
+<p><label>Username <input type="text" name="username" autocomplete="username" required></label></p>
+<p><label>Password <input type="password" name="password" autocomplete="current-password" required></label></p>
+<p><button type="submit">Approve</button></p>
+</form>
+</body>
+</html>`)
+}
+
+// deviceAuthSecurityScheme documents the device authorization flow; OpenAPI
+// 3.1 has no device flow, so it is an extension of the oauth2 flows
+func (an *ApiNote) deviceAuthSecurityScheme() SecurityScheme {
+	path := an.config.BasePath + an.config.DeviceAuth.path()
+	flow := &OAuthFlow{
+		DeviceAuthorizationURL: path + "/code",
+		TokenURL:               path + "/token",
+		Scopes:                 map[string]string{},
+	}
+	if an.config.Tokens != nil {
+		flow.RefreshURL = an.config.BasePath + an.config.Tokens.path() + "/refresh"
+	}
+	return SecurityScheme{
+		Type:        "oauth2",
+		Description: "Bearer JWTs of a device sign-in: start it at " + flow.DeviceAuthorizationURL + ", approve the user code at " + path + " and poll " + flow.TokenURL + " for the tokens (RFC 8628)",
+		Flows:       &OAuthFlows{DeviceAuthorization: flow},
+	}
+}
+
+// renderDeviceAuth renders the device sign-in of the Authorize section
+func (an *ApiNote) renderDeviceAuth() string {
+	if an.config.DeviceAuth == nil || an.config.DeviceAuth.Verify == nil {
+		return ""
+	}
+	return `
+            <div class="auth-input-group device-auth">
+                <button type="button" onclick="startDeviceSignIn(this)"><i class="fas fa-mobile-screen"></i> Sign in on another device</button>
+                <p class="device-auth-status" id="device-auth-status"></p>
+            </div>`
+}
+
+// deviceAuthScript runs the device sign-in of the docs page and sets the
+// issued access token for the try-it forms
+func (an *ApiNote) deviceAuthScript() string {
+	if an.config.DeviceAuth == nil || an.config.DeviceAuth.Verify == nil {
+		return ""
+	}
+	return `
+            const deviceAuthURL = '` + escapeJavaScript(an.config.BasePath+an.config.DeviceAuth.path()) + `';
+            async function startDeviceSignIn(button) {
+                const status = document.getElementById('device-auth-status');
+                button.disabled = true;
+                try {
+                    const response = await fetch(deviceAuthURL + '/code', { method: 'POST' });
+                    if (!response.ok) {
+                        throw new Error('status ' + response.status);
+                    }
+                    const device = await response.json();
+                    const link = document.createElement('a');
+                    link.href = device.verification_uri_complete;
+                    link.target = '_blank';
+                    link.rel = 'noopener';
+                    link.textContent = device.verification_uri;
+                    const code = document.createElement('strong');
+                    code.textContent = device.user_code;
+                    status.replaceChildren('Open ', link, ' on any device and enter ', code, '.');
+
+                    let interval = device.interval * 1000;
+                    const expires = Date.now() + device.expires_in * 1000;
+                    while (Date.now() < expires) {
+                        await new Promise(resolve => setTimeout(resolve, interval));
+                        const poll = await fetch(deviceAuthURL + '/token', {
+                            method: 'POST',
+                            headers: { 'Content-Type': 'application/json' },
+                            body: JSON.stringify({ device_code: device.device_code }),
+                        });
+                        const result = await poll.json();
+                        if (poll.ok) {
+                            authToken = result.access_token;
+                            localStorage.setItem('authToken', authToken);
+                            document.getElementById('auth-token').value = authToken;
+                            status.textContent = 'Signed in; the token is set for the try-it forms.';
+                            return;
+                        }
+                        if (result.error === 'slow_down') {
+                            interval += 5000;
+                        } else if (result.error !== 'authorization_pending') {
+                            break;
+                        }
+                    }
+                    status.textContent = 'The code expired, start again.';
+                } catch (e) {
+                    status.textContent = 'Device sign-in failed: ' + e.message;
+                } finally {
+                    button.disabled = false;
+                }
+            }`
+}
@@ -0,0 +1,150 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestDeviceAuth tests signing in a device with a user code approved elsewhere
+func TestDeviceAuth(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		DeviceAuth: &DeviceAuthConfig{
+			PollInterval: time.Millisecond,
+			Verify: func(c fiber.Ctx, username, password string) (string, map[string]interface{}, error) {
+				if username != "ada" || password != "secret" {
+					return "", nil, ErrInvalidCredentials
+				}
+				return "user-1", map[string]interface{}{"role": "admin"}, nil
+			},
+		},
+	}, "jwt-secret")
+	api.UseJWT()
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method: "GET",
+		Path:   "/v1/me",
+		Handler: func(c fiber.Ctx) error {
+			return c.JSON(fiber.Map{"role": c.Locals("claims").(map[string]interface{})["role"]})
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	send := func(method, path, contentType, body string) (int, string) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := send("POST", "/auth/device/code", "", "")
+	var device DeviceCodeResponse
+	if status != fiber.StatusOK || json.Unmarshal([]byte(body), &device) != nil {
+		t.Fatalf("Expected a device code, got %d %s", status, body)
+	}
+	if len(device.UserCode) != 9 || device.VerificationURI != "http://localhost:8080/auth/device" || device.ExpiresIn != 600 {
+		t.Errorf("Unexpected device code %+v", device)
+	}
+
+	poll := `{"device_code":"` + device.DeviceCode + `"}`
+	if status, body := send("POST", "/auth/device/token", "application/json", poll); status != fiber.StatusBadRequest || !strings.Contains(body, `"authorization_pending"`) {
+		t.Errorf("Expected authorization_pending before approval, got %d %s", status, body)
+	}
+	if status, body := send("GET", "/auth/device?user_code="+device.UserCode, "", ""); status != fiber.StatusOK || !strings.Contains(body, `value="`+device.UserCode+`"`) {
+		t.Errorf("Expected the verification page with the code filled in, got %d %s", status, body)
+	}
+
+	approvals := []struct {
+		name     string
+		userCode string
+		password string
+		status   int
+	}{
+		{"wrong password", device.UserCode, "wrong", fiber.StatusUnauthorized},
+		{"unknown code", "BBBB-BBBB", "secret", fiber.StatusBadRequest},
+		{"typed code", strings.ToLower(strings.ReplaceAll(device.UserCode, "-", "")), "secret", fiber.StatusOK},
+		{"approved twice", device.UserCode, "secret", fiber.StatusBadRequest},
+	}
+	for _, tt := range approvals {
+		form := url.Values{"user_code": {tt.userCode}, "username": {"ada"}, "password": {tt.password}}
+		if status, body := send("POST", "/auth/device", "application/x-www-form-urlencoded", form.Encode()); status != tt.status {
+			t.Errorf("%s: expected %d, got %d %s", tt.name, tt.status, status, body)
+		}
+	}
+
+	time.Sleep(2 * time.Millisecond)
+	status, body = send("POST", "/auth/device/token", "application/json", poll)
+	var tokens TokenResponse
+	if status != fiber.StatusOK || json.Unmarshal([]byte(body), &tokens) != nil || tokens.AccessToken == "" {
+		t.Fatalf("Expected tokens once approved, got %d %s", status, body)
+	}
+	if status, body := send("POST", "/auth/device/token", "application/json", poll); status != fiber.StatusBadRequest || !strings.Contains(body, `"expired_token"`) {
+		t.Errorf("Expected the device code to be used up, got %d %s", status, body)
+	}
+
+	req := httptest.NewRequest("GET", "/v1/me", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	resp, err := api.Fiber().Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK || !strings.Contains(string(data), `"admin"`) {
+		t.Errorf("Expected the device token to authorize with its claims, got %d %s", resp.StatusCode, data)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	scheme := spec.Components.SecuritySchemes["deviceAuth"]
+	if scheme.Type != "oauth2" || scheme.Flows == nil || scheme.Flows.DeviceAuthorization.TokenURL != "/auth/device/token" {
+		t.Errorf("Expected the device flow as a security scheme, got %+v", scheme)
+	}
+	if security := spec.Paths["/v1/me"].Get.Security; len(security) != 2 || security[1]["deviceAuth"] == nil {
+		t.Errorf("Expected deviceAuth as an alternative to bearerAuth, got %v", security)
+	}
+	if html := api.generateHTML(); !strings.Contains(html, "startDeviceSignIn(this)") || !strings.Contains(html, `const deviceAuthURL = '/auth/device'`) {
+		t.Error("Expected the device sign-in on the docs page")
+	}
+}
+
+// TestDeviceAuthSlowDown tests that polling faster than the interval slows clients down
+func TestDeviceAuthSlowDown(t *testing.T) {
+	var grants deviceAuthorizations
+	now := time.Now()
+	deviceCode, _, ok := grants.start(time.Minute, 5*time.Second, now)
+	if !ok {
+		t.Fatal("Expected a device sign-in to start")
+	}
+	polls := []struct {
+		after time.Duration
+		want  string
+	}{
+		{0, deviceAuthorizationPending},
+		{time.Second, deviceSlowDown},
+		{10 * time.Second, deviceSlowDown}, // The interval grew to 10s
+		{25 * time.Second, deviceAuthorizationPending},
+		{2 * time.Minute, deviceExpiredToken},
+	}
+	for _, tt := range polls {
+		if _, got := grants.poll(deviceCode, now.Add(tt.after)); got != tt.want {
+			t.Errorf("Poll after %s: expected %s, got %s", tt.after, tt.want, got)
+		}
+	}
+}
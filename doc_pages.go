@@ -0,0 +1,205 @@
+package notelink
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DocPage is a free-form Markdown page of the docs, e.g. a glossary or a
+// getting-started guide
+type DocPage struct {
+	Slug     string
+	Title    string
+	Markdown string
+}
+
+// docPageSlug matches valid page slugs, e.g. "getting-started"
+var docPageSlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// AddDocPage registers a Markdown page shown in the docs navigation next to the
+// endpoints, under Guides, in registration order. Adding a page with an
+// existing slug replaces it. The Markdown supports headings, paragraphs,
+// lists, block quotes, fenced code, rules, and inline code, emphasis and links.
+func (an *ApiNote) AddDocPage(slug, title, markdown string) error {
+	if !docPageSlug.MatchString(slug) {
+		return fmt.Errorf("invalid doc page slug %q: use lowercase letters, digits and dashes", slug)
+	}
+	if title == "" {
+		return fmt.Errorf("doc page %s: title is required", slug)
+	}
+
+	page := DocPage{Slug: slug, Title: title, Markdown: markdown}
+	for i := range an.docPages {
+		if an.docPages[i].Slug == slug {
+			an.docPages[i] = page
+			an.artifacts.invalidate()
+			return nil
+		}
+	}
+	an.docPages = append(an.docPages, page)
+	an.artifacts.invalidate()
+	return nil
+}
+
+// renderDocPages renders the Guides section of the docs and its table of
+// contents entry
+func (an *ApiNote) renderDocPages() (string, string) {
+	if len(an.docPages) == 0 {
+		return "", ""
+	}
+
+	var html, toc strings.Builder
+	html.WriteString(`
+    <details class="top-segment-group" id="guides" open>
+        <summary>Guides</summary>`)
+	toc.WriteString(`
+                <li><a href="#guides">Guides</a><ul>`)
+	for _, page := range an.docPages {
+		anchor := "guide-" + page.Slug
+		toc.WriteString(`
+                    <li><a href="#` + anchor + `">` + escapeHTML(page.Title) + `</a></li>`)
+		html.WriteString(`
+        <details class="method-group doc-page" id="` + anchor + `">
+            <summary><span class="endpoint-path">` + escapeHTML(page.Title) + `</span></summary>
+            <div class="doc-page-content">` + renderMarkdown(page.Markdown) + `
+            </div>
+        </details>`)
+	}
+	html.WriteString(`
+    </details>`)
+	toc.WriteString(`</ul></li>`)
+	return html.String(), toc.String()
+}
+
+// markdownOrderedItem matches an ordered list item, e.g. "1. Step"
+var markdownOrderedItem = regexp.MustCompile(`^\d+[.)]\s+`)
+
+// renderMarkdown converts a Markdown subset to HTML. Raw HTML is escaped.
+func renderMarkdown(markdown string) string {
+	var html strings.Builder
+	var paragraph []string
+	listTag := ""
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			html.WriteString("\n<p>" + renderInlineMarkdown(strings.Join(paragraph, " ")) + "</p>")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if listTag != "" {
+			html.WriteString("\n</" + listTag + ">")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		flushParagraph()
+		if listTag != tag {
+			closeList()
+			html.WriteString("\n<" + tag + ">")
+			listTag = tag
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			closeList()
+			language := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			class := ""
+			if language != "" {
+				class = ` class="language-` + escapeHTML(language) + `"`
+			}
+			html.WriteString("\n<pre><code" + class + ">" + escapeHTML(strings.Join(code, "\n")) + "</code></pre>")
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			if level > 6 || !strings.HasPrefix(trimmed[level:], " ") {
+				paragraph = append(paragraph, trimmed)
+				continue
+			}
+			flushParagraph()
+			closeList()
+			// Page titles are the summary, so headings start at h3
+			tag := "h" + strconv.Itoa(min(level+2, 6))
+			html.WriteString("\n<" + tag + ">" + renderInlineMarkdown(strings.TrimSpace(trimmed[level:])) + "</" + tag + ">")
+		case trimmed == "---" || trimmed == "***" || trimmed == "___":
+			flushParagraph()
+			closeList()
+			html.WriteString("\n<hr>")
+		case strings.HasPrefix(trimmed, "> ") || trimmed == ">":
+			flushParagraph()
+			closeList()
+			html.WriteString("\n<blockquote>" + renderInlineMarkdown(strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))) + "</blockquote>")
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || strings.HasPrefix(trimmed, "+ "):
+			openList("ul")
+			html.WriteString("\n<li>" + renderInlineMarkdown(strings.TrimSpace(trimmed[2:])) + "</li>")
+		case markdownOrderedItem.MatchString(trimmed):
+			openList("ol")
+			html.WriteString("\n<li>" + renderInlineMarkdown(markdownOrderedItem.ReplaceAllString(trimmed, "")) + "</li>")
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	closeList()
+	return html.String()
+}
+
+var (
+	markdownCode   = regexp.MustCompile("`([^`]+)`")
+	markdownLink   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	markdownBold   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownItalic = regexp.MustCompile(`\*([^*]+)\*|\b_([^_]+)_\b`)
+)
+
+// renderInlineMarkdown converts inline code, links, bold and italic text to
+// HTML. Code spans are kept verbatim and links limited to http(s), mailto,
+// relative and fragment URLs.
+func renderInlineMarkdown(text string) string {
+	// Set code spans aside so their content isn't formatted
+	var spans []string
+	text = markdownCode.ReplaceAllStringFunc(text, func(match string) string {
+		spans = append(spans, "<code>"+escapeHTML(match[1:len(match)-1])+"</code>")
+		return "\x00" + strconv.Itoa(len(spans)-1) + "\x00"
+	})
+
+	text = escapeHTML(text)
+	text = markdownLink.ReplaceAllStringFunc(text, func(match string) string {
+		parts := markdownLink.FindStringSubmatch(match)
+		if !safeMarkdownURL(parts[2]) {
+			return parts[1]
+		}
+		return `<a href="` + parts[2] + `">` + parts[1] + `</a>`
+	})
+	text = markdownBold.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = markdownItalic.ReplaceAllString(text, "<em>$1$2</em>")
+
+	for i, span := range spans {
+		text = strings.Replace(text, "\x00"+strconv.Itoa(i)+"\x00", span, 1)
+	}
+	return text
+}
+
+// safeMarkdownURL reports whether a link target can't run script
+func safeMarkdownURL(url string) bool {
+	lower := strings.ToLower(url)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:") {
+		return true
+	}
+	return !strings.Contains(strings.SplitN(lower, "/", 2)[0], ":")
+}
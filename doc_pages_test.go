@@ -0,0 +1,72 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRenderMarkdown tests the Markdown subset of doc pages
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{"heading and paragraph", "# Overview\nFirst line\nsecond line", "\n<h3>Overview</h3>\n<p>First line second line</p>"},
+		{"inline formatting", "Use **bold**, *italic* and `a*b*c`", "\n<p>Use <strong>bold</strong>, <em>italic</em> and <code>a*b*c</code></p>"},
+		{"links", "[Docs](https://example.com/?a=1&b=2) and [bad](javascript:alert)", "\n<p><a href=\"https://example.com/?a=1&amp;b=2\">Docs</a> and bad</p>"},
+		{"lists", "- one\n- two\n1. first\n2. second", "\n<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<ol>\n<li>first</li>\n<li>second</li>\n</ol>"},
+		{"fenced code", "```bash\ncurl <host>\n```", "\n<pre><code class=\"language-bash\">curl &lt;host&gt;</code></pre>"},
+		{"raw html escaped", "<script>alert(1)</script>", "\n<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"quote and rule", "> Note\n\n---", "\n<blockquote>Note</blockquote>\n<hr>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderMarkdown(tt.markdown); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestAddDocPage tests registering doc pages and rendering them in the docs
+func TestAddDocPage(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	for _, slug := range []string{"", "Getting Started", "-intro"} {
+		if err := api.AddDocPage(slug, "Title", ""); err == nil {
+			t.Errorf("Expected an error for slug %q", slug)
+		}
+	}
+	if err := api.AddDocPage("getting-started", "Getting started", "Draft"); err != nil {
+		t.Fatalf("Failed to add doc page: %v", err)
+	}
+	if err := api.AddDocPage("glossary", "Glossary", "**Tenant**: an isolated customer"); err != nil {
+		t.Fatalf("Failed to add doc page: %v", err)
+	}
+	if err := api.AddDocPage("getting-started", "Getting started", "Call `GET /v1/users`"); err != nil {
+		t.Fatalf("Failed to replace doc page: %v", err)
+	}
+	if len(api.docPages) != 2 {
+		t.Fatalf("Expected 2 doc pages, got %d", len(api.docPages))
+	}
+
+	html := api.generateHTML()
+	for _, want := range []string{
+		`<a href="#guides">Guides</a>`,
+		`<a href="#guide-getting-started">Getting started</a>`,
+		`id="guide-glossary"`,
+		`<code>GET /v1/users</code>`,
+		`<strong>Tenant</strong>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the docs to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "Draft") {
+		t.Error("Expected the replaced page content to be gone")
+	}
+	if strings.Index(html, `id="guide-getting-started"`) > strings.Index(html, `id="guide-glossary"`) {
+		t.Error("Expected pages in registration order")
+	}
+}
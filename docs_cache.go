@@ -0,0 +1,72 @@
+package notelink
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// docsVaryHeaders are the request headers protected docs responses depend on
+var docsVaryHeaders = []string{fiber.HeaderAuthorization, fiber.HeaderCookie}
+
+// docsForwardedHeaders are the request headers docs responses depend on with
+// Config.TrustProxyHeaders
+var docsForwardedHeaders = []string{fiber.HeaderXForwardedHost, fiber.HeaderXForwardedProto}
+
+// mountDocsMiddleware protects every docs page with Config.DocsAuth and
+// sets its caching headers
+func (an *ApiNote) mountDocsMiddleware() {
+	// Registered first so responses rejected by DocsAuth get headers too
+	an.app.Use(an.docsPath(), func(c fiber.Ctx) error {
+		err := c.Next()
+		an.setDocsCacheHeaders(c, err)
+		return err
+	})
+	if an.config.DocsAuth != nil {
+		an.app.Use(an.docsPath(), an.config.DocsAuth)
+	}
+}
+
+// setDocsCacheHeaders sets Cache-Control and Vary on a docs response. Pages
+// are cached for Config.DocsCacheTTL, privately and per credentials when
+// Config.DocsAuth protects them. With Config.TrustProxyHeaders they embed the
+// forwarded base URL, so caches key them on the forwarded headers too. Errors,
+// rejected requests and the live metrics, SLO, debug, deprecation and
+// validation failure pages are never stored.
+func (an *ApiNote) setDocsCacheHeaders(c fiber.Ctx, err error) {
+	status := c.Response().StatusCode()
+	if err != nil || status < 200 || status >= 300 || strings.HasPrefix(c.Path(), an.docsPath()+"/metrics") || strings.HasPrefix(c.Path(), an.docsPath()+"/slo") || strings.HasPrefix(c.Path(), an.docsPath()+"/payload-sizes") || c.Path() == an.docsPath()+"/debug" || c.Path() == an.docsPath()+"/deprecations" || strings.HasPrefix(c.Path(), an.docsPath()+"/validation-failures") {
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return
+	}
+
+	directive := "no-cache"
+	if seconds := int(an.config.DocsCacheTTL.Seconds()); seconds > 0 {
+		directive = "max-age=" + strconv.Itoa(seconds)
+	}
+	if an.config.TrustProxyHeaders {
+		addVary(c, docsForwardedHeaders)
+	}
+	if an.config.DocsAuth == nil {
+		c.Set(fiber.HeaderCacheControl, "public, "+directive)
+		return
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, "+directive)
+	addVary(c, docsVaryHeaders)
+}
+
+// addVary appends the headers missing from the response's Vary header
+func addVary(c fiber.Ctx, headers []string) {
+	vary := c.GetRespHeader(fiber.HeaderVary)
+	for _, header := range headers {
+		if !strings.Contains(strings.ToLower(vary), strings.ToLower(header)) {
+			if vary != "" {
+				vary += ", "
+			}
+			vary += header
+		}
+	}
+	c.Set(fiber.HeaderVary, vary)
+}
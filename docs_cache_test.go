@@ -0,0 +1,64 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestDocsCacheHeaders tests the caching headers of docs pages with and without docs auth
+func TestDocsCacheHeaders(t *testing.T) {
+	docsAuth := func(c fiber.Ctx) error {
+		if c.Get("Authorization") != "Bearer docs-token" {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return c.Next()
+	}
+
+	tests := []struct {
+		name          string
+		auth          fiber.Handler
+		ttl           time.Duration
+		trustProxy    bool
+		path          string
+		authorization string
+		status        int
+		cacheControl  string
+		vary          string
+	}{
+		{name: "public without TTL", path: "/api-docs/openapi.json", status: 200, cacheControl: "public, no-cache"},
+		{name: "public with TTL", ttl: 10 * time.Minute, path: "/api-docs", status: 200, cacheControl: "public, max-age=600"},
+		{name: "protected", auth: docsAuth, ttl: time.Minute, path: "/api-docs/openapi.yaml", authorization: "Bearer docs-token", status: 200, cacheControl: "private, max-age=60", vary: "Authorization, Cookie"},
+		{name: "behind a proxy", trustProxy: true, path: "/api-docs/openapi.json", status: 200, cacheControl: "public, no-cache", vary: "X-Forwarded-Host, X-Forwarded-Proto"},
+		{name: "protected behind a proxy", auth: docsAuth, trustProxy: true, path: "/api-docs/openapi.json", authorization: "Bearer docs-token", status: 200, cacheControl: "private, no-cache", vary: "X-Forwarded-Host, X-Forwarded-Proto, Authorization, Cookie"},
+		{name: "rejected", auth: docsAuth, ttl: time.Minute, path: "/api-docs/openapi.json", status: 401, cacheControl: "no-store"},
+		{name: "metrics", ttl: time.Minute, path: "/api-docs/metrics", status: 200, cacheControl: "no-store"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", DocsAuth: tt.auth, DocsCacheTTL: tt.ttl, TrustProxyHeaders: tt.trustProxy}, "secret")
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Expected Cache-Control %q, got %q", tt.cacheControl, got)
+			}
+			if got := resp.Header.Get("Vary"); !strings.Contains(got, tt.vary) {
+				t.Errorf("Expected Vary to contain %q, got %q", tt.vary, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package notelink
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// Documentation checks of DocsCoverage
+const (
+	CoverageResponseSchema       = "response schema"        // The endpoint has a ResponseSchema, or only answers 204
+	CoverageErrorResponses       = "error responses"        // Responses documents a non-2xx status
+	CoverageParameterDescription = "parameter descriptions" // Every parameter has a Description
+)
+
+// coverageChecks are the checks run on every endpoint
+var coverageChecks = []string{CoverageResponseSchema, CoverageErrorResponses, CoverageParameterDescription}
+
+// DocsCoverage reports how thoroughly the endpoints are documented
+type DocsCoverage struct {
+	Checks  int     `json:"checks"`  // Checks run, three per endpoint
+	Passed  int     `json:"passed"`  // Checks passed
+	Percent float64 `json:"percent"` // Passed checks, 100 without endpoints
+	// Gaps lists the endpoints failing a check, in docs order
+	Gaps []DocsCoverageGap `json:"gaps,omitempty"`
+}
+
+// DocsCoverageGap is an endpoint failing documentation checks
+type DocsCoverageGap struct {
+	Method  string   `json:"method"`
+	Path    string   `json:"path"`
+	Missing []string `json:"missing"` // The failed checks
+	// UndescribedParameters names the parameters without a description
+	UndescribedParameters []string `json:"undescribedParameters,omitempty"`
+}
+
+// DocsCoverage checks every endpoint for a response schema, documented error
+// responses and parameter descriptions, e.g. to enforce a minimum from a test
+// with RequireDocsCoverage
+func (an *ApiNote) DocsCoverage() DocsCoverage {
+	coverage := DocsCoverage{Percent: 100}
+	for _, endpoint := range an.sortedEndpoints() {
+		gap := DocsCoverageGap{Method: endpoint.Method, Path: endpoint.Path}
+		if endpoint.ResponseSchema == nil && !onlyNoContent(endpoint.Responses) {
+			gap.Missing = append(gap.Missing, CoverageResponseSchema)
+		}
+		if !hasErrorResponse(endpoint.Responses) {
+			gap.Missing = append(gap.Missing, CoverageErrorResponses)
+		}
+		for _, param := range endpoint.Parameters {
+			if strings.TrimSpace(param.Description) == "" {
+				gap.UndescribedParameters = append(gap.UndescribedParameters, param.Name)
+			}
+		}
+		if len(gap.UndescribedParameters) > 0 {
+			gap.Missing = append(gap.Missing, CoverageParameterDescription)
+		}
+
+		coverage.Checks += len(coverageChecks)
+		coverage.Passed += len(coverageChecks) - len(gap.Missing)
+		if len(gap.Missing) > 0 {
+			coverage.Gaps = append(coverage.Gaps, gap)
+		}
+	}
+	if coverage.Checks > 0 {
+		coverage.Percent = math.Floor(float64(coverage.Passed)*1000/float64(coverage.Checks)) / 10
+	}
+	return coverage
+}
+
+// onlyNoContent reports whether the only documented success is 204 No Content
+func onlyNoContent(responses map[string]string) bool {
+	found := false
+	for status := range responses {
+		if strings.HasPrefix(status, "2") {
+			if status != "204" {
+				return false
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// hasErrorResponse reports whether a non-2xx status is documented, besides the
+// 405 response documented for every path
+func hasErrorResponse(responses map[string]string) bool {
+	for status, description := range responses {
+		if status == "405" && description == methodNotAllowedDescription {
+			continue
+		}
+		if !strings.HasPrefix(status, "1") && !strings.HasPrefix(status, "2") && !strings.HasPrefix(status, "3") {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary describes the coverage for people, one gap per line
+func (c DocsCoverage) Summary() string {
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "docs coverage %.1f%% (%d/%d checks)", c.Percent, c.Passed, c.Checks)
+	for _, gap := range c.Gaps {
+		fmt.Fprintf(&summary, "\n%s %s: missing %s", gap.Method, gap.Path, strings.Join(gap.Missing, ", "))
+		if len(gap.UndescribedParameters) > 0 {
+			fmt.Fprintf(&summary, " (%s)", strings.Join(gap.UndescribedParameters, ", "))
+		}
+	}
+	return summary.String()
+}
+
+// RequireDocsCoverage fails the test when the docs coverage is below minPercent,
+// listing the gaps
+//
+//	func TestDocsCoverage(t *testing.T) {
+//	    newAPI().RequireDocsCoverage(t, 90)
+//	}
+func (an *ApiNote) RequireDocsCoverage(t *testing.T, minPercent float64) {
+	t.Helper()
+	coverage := an.DocsCoverage()
+	if coverage.Percent < minPercent {
+		t.Errorf("%s\nwant at least %.1f%%", coverage.Summary(), minPercent)
+		return
+	}
+	t.Log(coverage.Summary())
+}
@@ -0,0 +1,81 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestDocsCoverage tests finding endpoints with documentation gaps
+func TestDocsCoverage(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	handler := func(c fiber.Ctx) error { return nil }
+	tests := []struct {
+		name    string
+		route   *DocumentedRouteInput
+		missing []string
+		params  []string
+	}{
+		{
+			name: "fully documented",
+			route: &DocumentedRouteInput{Method: "GET", Path: "/users/:id", Handler: handler, SchemasResponse: user{},
+				Responses: map[string]string{"200": "The user", "404": "No such user"},
+				Params:    []Parameter{{Name: "id", In: "path", Type: "string", Required: true, Description: "User id"}}},
+		},
+		{
+			name:  "no content",
+			route: &DocumentedRouteInput{Method: "DELETE", Path: "/users/:id", Handler: handler, Responses: map[string]string{"204": "Deleted", "default": "Error"}},
+			// The derived path parameter has no description
+			missing: []string{CoverageParameterDescription},
+			params:  []string{"id"},
+		},
+		{
+			name: "undocumented",
+			route: &DocumentedRouteInput{Method: "GET", Path: "/users", Handler: handler,
+				Params: []Parameter{{Name: "page", In: "query", Type: "integer"}, {Name: "q", In: "query", Type: "string", Description: "Search"}}},
+			missing: []string{CoverageResponseSchema, CoverageErrorResponses, CoverageParameterDescription},
+			params:  []string{"page"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API"}, "secret")
+			if err := api.DocumentedRoute(tt.route); err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+			coverage := api.DocsCoverage()
+			if len(tt.missing) == 0 {
+				if len(coverage.Gaps) != 0 || coverage.Percent != 100 {
+					t.Errorf("Expected full coverage, got %+v", coverage)
+				}
+				return
+			}
+			if len(coverage.Gaps) != 1 {
+				t.Fatalf("Expected a gap, got %+v", coverage)
+			}
+			gap := coverage.Gaps[0]
+			if strings.Join(gap.Missing, ",") != strings.Join(tt.missing, ",") || strings.Join(gap.UndescribedParameters, ",") != strings.Join(tt.params, ",") {
+				t.Errorf("Expected missing %v (%v), got %+v", tt.missing, tt.params, gap)
+			}
+		})
+	}
+
+	api := NewApiNote(&Config{Title: "Test API"}, "secret")
+	for _, tt := range tests {
+		if err := api.DocumentedRoute(tt.route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+	coverage := api.DocsCoverage()
+	if coverage.Checks != 9 || coverage.Passed != 5 || coverage.Percent != 55.5 {
+		t.Errorf("Expected 5 of 9 checks passed, got %+v", coverage)
+	}
+	if summary := coverage.Summary(); !strings.Contains(summary, "GET /users: missing response schema, error responses, parameter descriptions (page)") {
+		t.Errorf("Unexpected summary:\n%s", summary)
+	}
+	api.RequireDocsCoverage(t, 50)
+}
@@ -0,0 +1,256 @@
+package notelink
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/contrib/v3/monitor"
+	"github.com/gofiber/fiber/v3"
+)
+
+// DefaultDocsPath is where the docs surface is served unless Config.DocsPath says otherwise
+const DefaultDocsPath = "/api-docs"
+
+// iconPaths are the locations the docs icon is looked up at
+var iconPaths = []string{
+	"./icon.png",     // Current directory
+	"../icon.png",    // Parent directory (for examples folder)
+	"../../icon.png", // Grandparent directory
+}
+
+// docsRoute is a page, spec, export or asset of the docs surface, at a path
+// relative to the docs path ("" for the docs path itself)
+type docsRoute struct {
+	path    string
+	handler fiber.Handler
+}
+
+// docsPath returns the path the docs surface is served and linked at
+func (an *ApiNote) docsPath() string {
+	if an.config.DocsPath == "" {
+		return DefaultDocsPath
+	}
+	return "/" + strings.Trim(an.config.DocsPath, "/")
+}
+
+// docsRoutes lists the docs surface: the configured frontends, the print
+// view, landing page, specs, exports, route health pages and the icon
+func (an *ApiNote) docsRoutes() []docsRoute {
+	var routes []docsRoute
+	if an.config.DefaultUI != "" {
+		handlers := make(map[string]fiber.Handler)
+		for _, ui := range an.frontends() {
+			handler := ui.Handler(an)
+			handlers[uiRoute(ui)] = handler
+			routes = append(routes, docsRoute{"/" + uiRoute(ui), handler})
+		}
+		routes = append(routes,
+			docsRoute{"", func(c fiber.Ctx) error { return handlers[an.currentDefaultUI()](c) }},
+			docsRoute{"/links", an.landingHandler()},
+		)
+	} else if len(an.config.UIs) == 0 {
+		routes = append(routes, docsRoute{"", legacyDocsUI(an.config.DocsUI).Handler(an)})
+	} else {
+		for _, ui := range an.config.UIs {
+			routes = append(routes, docsRoute{"/" + strings.Trim(ui.Route(), "/"), ui.Handler(an)})
+		}
+		routes = append(routes, docsRoute{"", an.landingHandler()})
+	}
+
+	routes = append(routes,
+		// Serve the print-friendly single-page view of the built-in docs
+		docsRoute{"/print", func(c fiber.Ctx) error {
+			c.Set("Content-Type", "text/html")
+			return c.Status(http.StatusOK).SendString(an.cachedDocsHTML(true, an.baseURL(c), an.localeOf(c)))
+		}},
+		docsRoute{"/metrics", monitor.New(monitor.Config{Title: "Service Metrics Page"})},
+		docsRoute{"/indent", func(c fiber.Ctx) error {
+			data, err := json.MarshalIndent(an.app.GetRoutes(true), "", "  ")
+			if err != nil {
+				return c.Status(http.StatusInternalServerError).SendString("Error marshaling routes")
+			}
+			return c.Status(http.StatusOK).SendString(string(data))
+		}},
+		docsRoute{"/openapi.json", func(c fiber.Ctx) error {
+			data, err := an.cachedOpenAPISpec(an.baseURL(c), an.localeOf(c), false)
+			if err != nil {
+				return c.Status(http.StatusInternalServerError).SendString("Error marshaling OpenAPI spec")
+			}
+			c.Set("Content-Type", "application/json")
+			return c.Status(http.StatusOK).Send(data)
+		}},
+		docsRoute{"/asyncapi.json", func(c fiber.Ctx) error {
+			return c.JSON(an.GenerateAsyncAPISpec())
+		}},
+		docsRoute{"/openapi.yaml", func(c fiber.Ctx) error {
+			data, err := an.cachedOpenAPISpec(an.baseURL(c), an.localeOf(c), true)
+			if err != nil {
+				return c.Status(http.StatusInternalServerError).SendString("Error marshaling OpenAPI spec")
+			}
+			c.Set("Content-Type", "application/yaml")
+			return c.Status(http.StatusOK).Send(data)
+		}},
+		// Serve a Postman collection and TypeScript types for client developers
+		docsRoute{"/postman.json", func(c fiber.Ctx) error {
+			c.Set("Content-Disposition", `attachment; filename="postman_collection.json"`)
+			return c.JSON(an.generatePostmanCollection(an.baseURL(c)))
+		}},
+		docsRoute{"/types.ts", func(c fiber.Ctx) error {
+			c.Set("Content-Type", "application/typescript")
+			return c.Status(http.StatusOK).SendString(an.cachedTypeScriptTypes())
+		}},
+		// Serve each component schema as a standalone JSON Schema
+		docsRoute{"/schemas/:name.json", an.schemaHandler},
+		// Serve the assertions of a try-it form as a Go test skeleton
+		docsRoute{"/go-test", an.goTestHandler},
+	)
+
+	// Serve the Backstage catalog entity so the API can register itself
+	if an.config.Catalog != nil {
+		routes = append(routes, docsRoute{"/catalog-info.yaml", func(c fiber.Ctx) error {
+			data, err := an.generateCatalogInfo(an.baseURL(c))
+			if err != nil {
+				return c.Status(http.StatusInternalServerError).SendString(err.Error())
+			}
+			c.Set("Content-Type", "application/yaml")
+			return c.Status(http.StatusOK).Send(data)
+		}})
+	}
+
+	routes = append(routes,
+		// Serve the circuit breaker states of the routes
+		docsRoute{"/breakers", func(c fiber.Ctx) error {
+			return c.JSON(an.CircuitBreakers())
+		}},
+		// Serve the SLO compliance of the routes, as JSON and for Prometheus
+		docsRoute{"/slo", func(c fiber.Ctx) error {
+			return c.JSON(an.SLOs())
+		}},
+		docsRoute{"/slo/metrics", func(c fiber.Ctx) error {
+			c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+			return c.SendString(an.sloPrometheus())
+		}},
+		// Serve the body sizes of the routes, as JSON and for Prometheus
+		docsRoute{"/payload-sizes", func(c fiber.Ctx) error {
+			return c.JSON(an.PayloadSizes())
+		}},
+		docsRoute{"/payload-sizes/metrics", func(c fiber.Ctx) error {
+			c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+			return c.SendString(an.payloadSizesPrometheus())
+		}},
+		// Serve the landing page linking every docs surface
+		docsRoute{"/index", an.landingHandler()},
+		docsRoute{"/icon.png", iconHandler("image/png")},
+	)
+	if an.diagnosticsServed() {
+		// Serve who still calls the deprecated routes and sends deprecated fields
+		routes = append(routes,
+			docsRoute{"/deprecations", an.deprecationsHandler},
+			// Serve the validation failures by field and client, as JSON and for Prometheus
+			docsRoute{"/validation-failures", an.validationFailuresHandler},
+			docsRoute{"/validation-failures/metrics", func(c fiber.Ctx) error {
+				c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
+				return c.SendString(an.validationFailuresPrometheus())
+			}},
+			// Serve the sizes of the in-memory registries
+			docsRoute{"/debug", an.debugHandler},
+		)
+	}
+	if an.config.CallbackRelay != nil {
+		// Relay callbacks to the try-it forms over a WebSocket
+		routes = append(routes, docsRoute{"/callback-relay", an.callbackRelayHandler})
+	}
+
+	// Serve the pages and specs rendered at build time when there's a snapshot
+	if an.config.DocsSnapshot != nil {
+		for i, route := range routes {
+			if liveDocsRoutes[route.path] {
+				continue
+			}
+			if handler := snapshotHandler(an.config.DocsSnapshot, route.path); handler != nil {
+				routes[i].handler = handler
+			}
+		}
+	}
+	return routes
+}
+
+// diagnosticsServed reports whether the docs pages identifying clients or
+// describing the service's internals are served: behind Config.DocsAuth, or with Config.PublicDiagnostics
+func (an *ApiNote) diagnosticsServed() bool {
+	return an.config.DocsAuth != nil || an.config.PublicDiagnostics
+}
+
+// mountDocsRoutes serves the docs surface under the docs path, and the icon
+// at the site root for browsers looking for it there
+func (an *ApiNote) mountDocsRoutes() {
+	for _, route := range an.docsRoutes() {
+		an.app.Get(an.docsPath()+route.path, route.handler)
+	}
+	an.app.Get("/icon.png", iconHandler("image/png"))
+	// Also serve favicon.ico for browsers that look for it by default
+	an.app.Get("/favicon.ico", iconHandler("image/x-icon"))
+}
+
+// DocsHandlers returns the docs surface as handlers keyed by their path
+// relative to Config.DocsPath: "/" for the docs frontend (or the landing page
+// with Config.UIs), "/print", "/openapi.json", "/openapi.yaml",
+// "/asyncapi.json", "/postman.json", "/types.ts", "/schemas/:name.json", "/go-test",
+// "/index", "/icon.png", the route health and debug pages and the frontends
+// of Config.UIs. Mount them into an existing router with your own
+// middleware, e.g.
+//
+//	docs := app.Group("/internal/docs", requireStaff)
+//	for path, handler := range api.DocsHandlers() {
+//	    docs.Get(path, handler)
+//	}
+//
+// with Config.DocsPath set to "/internal/docs" so the pages link each other
+// there, and Config.DisableDocsRoutes set so NewApiNote doesn't serve them
+// too. The handlers set the docs caching headers; Config.DocsAuth isn't
+// applied to them.
+//
+// The reports identifying clients, "/deprecations" and
+// "/validation-failures", and the registry sizes at "/debug" are included
+// when Config.DocsAuth or Config.PublicDiagnostics is set. They list JWT
+// subjects, API key fingerprints, IP addresses and operational internals:
+// mount them only behind your own authentication.
+func (an *ApiNote) DocsHandlers() map[string]fiber.Handler {
+	handlers := make(map[string]fiber.Handler)
+	for _, route := range an.docsRoutes() {
+		path := route.path
+		if path == "" {
+			path = "/"
+		}
+		handler := route.handler
+		handlers[path] = func(c fiber.Ctx) error {
+			err := handler(c)
+			an.setDocsCacheHeaders(c, err)
+			return err
+		}
+	}
+	return handlers
+}
+
+// landingHandler serves the landing page
+func (an *ApiNote) landingHandler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		c.Set("Content-Type", "text/html")
+		return c.Status(http.StatusOK).SendString(an.generateLandingHTML())
+	}
+}
+
+// iconHandler serves the first icon.png found, or 404
+func iconHandler(contentType string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		for _, iconPath := range iconPaths {
+			if _, err := os.Stat(iconPath); err == nil {
+				c.Set("Content-Type", contentType)
+				return c.SendFile(iconPath)
+			}
+		}
+		return c.Status(fiber.StatusNotFound).SendString("Icon not found")
+	}
+}
@@ -0,0 +1,83 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestDocsHandlers tests mounting the docs surface into an existing router
+func TestDocsHandlers(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", DocsPath: "/internal/docs/", DisableDocsRoutes: true}, "secret")
+	handlers := api.DocsHandlers()
+	for _, path := range []string{"/", "/print", "/openapi.json", "/openapi.yaml", "/postman.json", "/types.ts", "/index", "/icon.png"} {
+		if handlers[path] == nil {
+			t.Errorf("Expected a handler for %s", path)
+		}
+	}
+
+	requireStaff := func(c fiber.Ctx) error {
+		if c.Get("X-Staff") != "yes" {
+			return c.SendStatus(fiber.StatusUnauthorized)
+		}
+		return c.Next()
+	}
+	docs := api.Fiber().Group("/internal/docs", requireStaff)
+	for path, handler := range handlers {
+		docs.Get(path, handler)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		staff  bool
+		status int
+		want   string
+	}{
+		{"default routes disabled", "/api-docs/openapi.json", true, 404, ""},
+		{"own auth", "/internal/docs/openapi.json", false, 401, ""},
+		{"spec", "/internal/docs/openapi.json", true, 200, `"openapi":"3.1.0"`},
+		{"frontend links the mounted spec", "/internal/docs/", true, 200, `/internal/docs/openapi.json`},
+		{"landing links the mounted pages", "/internal/docs/index", true, 200, `href="/internal/docs/types.ts"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.staff {
+				req.Header.Set("X-Staff", "yes")
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.want) {
+				t.Errorf("Expected %d containing %s, got %d %.200s", tt.status, tt.want, resp.StatusCode, body)
+			}
+			if tt.status == 200 && resp.Header.Get(fiber.HeaderCacheControl) == "" {
+				t.Error("Expected the docs caching headers")
+			}
+		})
+	}
+}
+
+// TestDocsPath tests serving the docs surface at a custom path
+func TestDocsPath(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", DocsPath: "/docs", DocsUI: "notelink"}, "secret")
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/docs", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != 200 || !strings.Contains(string(body), `href="/docs/print"`) {
+		t.Errorf("Expected the docs at /docs linking the print view there, got %d", resp.StatusCode)
+	}
+	if resp, _ := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/openapi.json", nil)); resp.StatusCode != 404 {
+		t.Errorf("Expected nothing at the default docs path, got %d", resp.StatusCode)
+	}
+}
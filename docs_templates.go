@@ -0,0 +1,105 @@
+package notelink
+
+import (
+	"html/template"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// Well-known Config.ExtraTemplateData keys rendered by the built-in docs
+// slots when DocsTemplates doesn't replace them
+const (
+	// TemplateDataEnvironment is an environment banner above the docs, e.g.
+	// "STAGING — data resets nightly"
+	TemplateDataEnvironment = "environment"
+	// TemplateDataAnnouncement is an announcement bar below the environment banner
+	TemplateDataAnnouncement = "announcement"
+	// TemplateDataLegalNotice is a legal notice at the bottom of the docs
+	TemplateDataLegalNotice = "legal_notice"
+)
+
+// DocsTemplates are html/template sources rendered into the built-in docs page
+// and print view with DocsTemplateData, e.g.
+//
+//	Banner: `{{with env "DOCS_BANNER"}}<div class="docs-environment-banner">{{.}}</div>{{end}}`
+//
+// The env function reads environment variables, so deployments can change
+// the injected content without code changes. A slot left empty renders its
+// built-in content from Config.ExtraTemplateData.
+type DocsTemplates struct {
+	// Head is rendered at the end of <head>, e.g. for styles or meta tags
+	Head string
+	// Banner is rendered at the top of the page, replacing the environment
+	// banner and announcement bar
+	Banner string
+	// Footer is rendered at the end of the page, replacing the legal notice
+	Footer string
+}
+
+// DocsTemplateData is the data DocsTemplates are rendered with
+type DocsTemplateData struct {
+	Title     string
+	Version   string
+	Locale    string
+	PrintView bool
+	// Data is Config.ExtraTemplateData
+	Data map[string]interface{}
+}
+
+// docsTemplateSlots names the slots of DocsTemplates
+var docsTemplateSlots = []string{"head", "banner", "footer"}
+
+// defaultDocsTemplates render the well-known ExtraTemplateData keys
+var defaultDocsTemplates = map[string]string{
+	"banner": `{{with index .Data "` + TemplateDataEnvironment + `"}}<div class="docs-environment-banner" role="status">{{.}}</div>{{end}}` +
+		`{{with index .Data "` + TemplateDataAnnouncement + `"}}<div class="docs-announcement" role="note">{{.}}</div>{{end}}`,
+	"footer": `{{with index .Data "` + TemplateDataLegalNotice + `"}}<footer class="docs-legal-notice">{{.}}</footer>{{end}}`,
+}
+
+// parseDocsTemplates parses Config.DocsTemplates, falling back to the
+// built-in slot for templates that don't parse
+func (an *ApiNote) parseDocsTemplates() {
+	sources := map[string]string{}
+	if custom := an.config.DocsTemplates; custom != nil {
+		sources["head"], sources["banner"], sources["footer"] = custom.Head, custom.Banner, custom.Footer
+	}
+
+	funcs := template.FuncMap{"env": os.Getenv}
+	an.docsTemplates = template.New("docs").Funcs(funcs)
+	for _, slot := range docsTemplateSlots {
+		source := sources[slot]
+		if source != "" {
+			if _, err := template.New(slot).Funcs(funcs).Parse(source); err != nil {
+				log.Warnf("notelink: docs template %s: %v", slot, err)
+				source = ""
+			}
+		}
+		if source == "" {
+			source = defaultDocsTemplates[slot]
+		}
+		template.Must(an.docsTemplates.New(slot).Parse(source))
+	}
+}
+
+// renderDocsTemplate renders a docs template slot; errors are logged and
+// rendered as nothing, so a broken template doesn't take the docs down
+func (an *ApiNote) renderDocsTemplate(slot string, printView bool, locale string) string {
+	if an.docsTemplates == nil {
+		return ""
+	}
+	var out strings.Builder
+	err := an.docsTemplates.ExecuteTemplate(&out, slot, DocsTemplateData{
+		Title:     an.config.Title,
+		Version:   an.config.Version,
+		Locale:    locale,
+		PrintView: printView,
+		Data:      an.config.ExtraTemplateData,
+	})
+	if err != nil {
+		log.Warnf("notelink: rendering docs template %s: %v", slot, err)
+		return ""
+	}
+	return out.String()
+}
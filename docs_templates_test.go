@@ -0,0 +1,82 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestDocsTemplates tests injecting template data and templates into the docs
+func TestDocsTemplates(t *testing.T) {
+	t.Setenv("NOTELINK_TEST_BANNER", "Maintenance tonight")
+	data := map[string]interface{}{
+		TemplateDataEnvironment:  "STAGING — data resets nightly",
+		TemplateDataAnnouncement: "v2 is <b>out</b>",
+		TemplateDataLegalNotice:  "© Example Corp",
+		"theme":                  "dark",
+	}
+
+	tests := []struct {
+		name      string
+		templates *DocsTemplates
+		want      []string
+		unwanted  []string
+	}{
+		{
+			name: "built-in slots",
+			want: []string{
+				`<div class="docs-environment-banner" role="status">STAGING — data resets nightly</div>`,
+				`<div class="docs-announcement" role="note">v2 is &lt;b&gt;out&lt;/b&gt;</div>`,
+				`<footer class="docs-legal-notice">© Example Corp</footer>`,
+			},
+		},
+		{
+			name: "custom templates",
+			templates: &DocsTemplates{
+				Head:   `<meta name="theme" content="{{.Data.theme}}">`,
+				Banner: `{{with env "NOTELINK_TEST_BANNER"}}<div class="maintenance">{{.}}</div>{{end}}`,
+				Footer: `<footer>{{.Title}} {{.Version}}</footer>`,
+			},
+			want:     []string{`<meta name="theme" content="dark">`, `<div class="maintenance">Maintenance tonight</div>`, `<footer>Test API 1.0.0</footer>`},
+			unwanted: []string{`class="docs-environment-banner"`, `class="docs-legal-notice"`},
+		},
+		{
+			name:      "invalid template falls back",
+			templates: &DocsTemplates{Banner: `{{if}}`},
+			want:      []string{`<div class="docs-environment-banner" role="status">`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Version: "1.0.0", Host: "localhost:8080", ExtraTemplateData: data, DocsTemplates: tt.templates}, "secret")
+			html := api.generateHTML()
+			for _, want := range tt.want {
+				if !strings.Contains(html, want) {
+					t.Errorf("Expected the docs to contain %s", want)
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(html, unwanted) {
+					t.Errorf("Expected the docs not to contain %s", unwanted)
+				}
+			}
+		})
+	}
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", ExtraTemplateData: data}, "secret")
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/print", nil))
+	if err != nil {
+		t.Fatalf("Failed to fetch the print view: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "© Example Corp") {
+		t.Error("Expected the legal notice on the print view")
+	}
+
+	plain := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	if html := plain.generateHTML(); strings.Contains(html, "docs-environment-banner\"") || strings.Contains(html, "<footer") {
+		t.Error("Expected no injected content without template data")
+	}
+}
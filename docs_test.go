@@ -0,0 +1,350 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestSidebarNavigation tests the table of contents generated from the docs tree
+func TestSidebarNavigation(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+	for _, path := range []string{"/v1/users", "/v1/users/:id", "/health"} {
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: path, Handler: handler}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	html := api.generateHTML()
+	sidebarStart := strings.Index(html, `<nav class="docs-sidebar"`)
+	if sidebarStart < 0 {
+		t.Fatal("Expected sidebar navigation")
+	}
+	sidebar := html[sidebarStart : sidebarStart+strings.Index(html[sidebarStart:], "</nav>")]
+
+	tests := []struct {
+		name   string
+		anchor string
+	}{
+		{name: "Version group", anchor: "group-v1"},
+		{name: "Nested group", anchor: "group-v1-users"},
+		{name: "Top-level group", anchor: "group-health"},
+		{name: "Endpoint", anchor: "op-getUsersById"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(sidebar, `href="#`+tt.anchor+`"`) {
+				t.Errorf("Expected sidebar link to %s", tt.anchor)
+			}
+			if !strings.Contains(html, `id="`+tt.anchor+`"`) {
+				t.Errorf("Expected docs element with id %s", tt.anchor)
+			}
+		})
+	}
+
+	if strings.Count(sidebar, "<ul>") != strings.Count(sidebar, "</ul>")-1 {
+		t.Error("Expected balanced nested lists in the sidebar")
+	}
+}
+
+// TestCommandPalette tests that the command palette and shortcuts are part of the docs
+func TestCommandPalette(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	html := api.generateHTML()
+
+	for _, marker := range []string{`id="command-palette"`, "function openCommandPalette()", `onclick="openCommandPalette()"`, "e.key.toLowerCase() === 'k'"} {
+		if !strings.Contains(html, marker) {
+			t.Errorf("Expected %s in the docs", marker)
+		}
+	}
+}
+
+// TestGroupToggleControls tests expand-all / collapse-all controls on top-level groups
+func TestGroupToggleControls(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+	for _, path := range []string{"/v1/users/:id", "/v2/users/:id", "/health/live"} {
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: path, Handler: handler}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	html := api.generateHTML()
+	// One pair per version group and top-level group, none on nested groups
+	if count := strings.Count(html, "toggleGroup(event, this, true)"); count != 3 {
+		t.Errorf("Expected 3 expand-all controls, got %d", count)
+	}
+	if !strings.Contains(html, `onclick="setAllDetails(false)"`) {
+		t.Error("Expected a global collapse-all control in the section header")
+	}
+}
+
+// TestPrintView tests the single-page print view of the docs
+func TestPrintView(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+	for _, path := range []string{"/v1/users", "/v1/users/:id"} {
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: path, Handler: handler}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	if !strings.Contains(api.generateHTML(), `href="/api-docs/print"`) {
+		t.Error("Expected a link to the print view")
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/print", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	html := string(body)
+
+	checks := []struct {
+		name     string
+		snippet  string
+		expected bool
+	}{
+		{name: "Print body class", snippet: `<body class="print-view">`, expected: true},
+		{name: "Print media styles", snippet: "@media print", expected: true},
+		{name: "Expanded groups", snippet: `<details open class="version-group"`, expected: true},
+		{name: "Endpoint documented", snippet: "/v1/users/:id", expected: true},
+		{name: "No collapsed details", snippet: "<details class=", expected: false},
+		{name: "No try-it forms", snippet: `<div class="api-test">`, expected: false},
+	}
+	for _, tt := range checks {
+		t.Run(tt.name, func(t *testing.T) {
+			if strings.Contains(html, tt.snippet) != tt.expected {
+				t.Errorf("Expected contains(%q) to be %v", tt.snippet, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFavorites tests the favorite toggles and quick access lists
+func TestFavorites(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+	for _, path := range []string{"/v1/users", "/v1/users/:id"} {
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: path, Handler: handler}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	html := api.generateHTML()
+	if count := strings.Count(html, `class="favorite-toggle"`); count != 2 {
+		t.Errorf("Expected a favorite toggle per endpoint, got %d", count)
+	}
+	for _, snippet := range []string{`id="favorites-list"`, `id="recent-list"`, "function toggleFavorite(", "function recordRecentlyViewed(", "notelink.favorites"} {
+		if !strings.Contains(html, snippet) {
+			t.Errorf("Expected docs to contain %q", snippet)
+		}
+	}
+}
+
+// TestSchemaViews tests the TypeScript / JSON Schema / example toggle
+func TestSchemaViews(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+	}
+	type Customer struct {
+		Name    string  `json:"name"`
+		Address Address `json:"address"`
+	}
+
+	views := renderSchemaViews("Request Body", "CustomerRequest", Customer{}, exampleOptions{}, nil)
+	tests := []struct {
+		name    string
+		snippet string
+	}{
+		{name: "TypeScript tab active", snippet: `class="schema-view-tab active" data-view="typescript"`},
+		{name: "TypeScript view", snippet: "interface CustomerRequest"},
+		{name: "JSON Schema view hidden", snippet: `data-view="json-schema" hidden>`},
+		{name: "JSON Schema ref", snippet: "#/components/schemas/Address"},
+		{name: "Embedded components", snippet: "&quot;components&quot;"},
+		{name: "Example view", snippet: "&quot;city&quot;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(views, tt.snippet) {
+				t.Errorf("Expected schema views to contain %q", tt.snippet)
+			}
+		})
+	}
+
+	if renderSchemaViews("Request Body", "None", nil, exampleOptions{}, nil) != "" {
+		t.Error("Expected no schema views without a schema")
+	}
+}
+
+// TestCopyButtons tests the copy-to-clipboard buttons on code blocks and endpoint URLs
+func TestCopyButtons(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/api"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "POST",
+		Path:            "/users",
+		Handler:         func(c fiber.Ctx) error { return c.SendStatus(201) },
+		SchemasRequest:  TestUser{},
+		SchemasResponse: TestUser{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	html := api.generateHTML()
+	tests := []struct {
+		name    string
+		snippet string
+	}{
+		{name: "Endpoint URL", snippet: `data-copy="http://localhost:8080/api/users"`},
+		{name: "Schema views", snippet: `<div class="schema-views copyable">`},
+		{name: "Copy script", snippet: "function copyToClipboard(event, button)"},
+		{name: "Result blocks", snippet: "copyablePre(escapeHtml(result.body))"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(html, tt.snippet) {
+				t.Errorf("Expected docs to contain %q", tt.snippet)
+			}
+		})
+	}
+
+	if got := renderCopyablePre(` id="x"`, "a &lt; b"); got != `<div class="copyable">`+copyButtonMarkup+`<pre id="x">a &lt; b</pre></div>` {
+		t.Errorf("Unexpected copyable pre: %s", got)
+	}
+}
+
+// TestTryItRelativeURL tests the try-it client calling the docs page's origin
+func TestTryItRelativeURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		relative bool
+		present  []string
+		absent   []string
+	}{
+		{
+			name:    "Configured host",
+			present: []string{"const baseUrl = 'http://localhost:8080';", `data-copy="http://localhost:8080/api/users"`},
+			absent:  []string{"data-copy-origin"},
+		},
+		{
+			name:     "Relative URLs",
+			relative: true,
+			present:  []string{"const baseUrl = window.location.origin;", `data-copy-origin data-copy="/api/users"`},
+			absent:   []string{"http://localhost:8080"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/api", TryItRelativeURL: tt.relative}, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/users", Handler: func(c fiber.Ctx) error { return nil }})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			html := api.generateHTML()
+			for _, snippet := range tt.present {
+				if !strings.Contains(html, snippet) {
+					t.Errorf("Expected docs to contain %q", snippet)
+				}
+			}
+			for _, snippet := range tt.absent {
+				if strings.Contains(html, snippet) {
+					t.Errorf("Expected docs not to contain %q", snippet)
+				}
+			}
+		})
+	}
+}
+
+// TestOwnership tests endpoint ownership on the docs card and in the spec
+func TestOwnership(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return nil }
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/payments", Handler: handler, Ownership: &Ownership{Team: "payments", Owner: "jane@acme.io", SupportChannel: "https://acme.slack.com/payments"}},
+		{Method: "GET", Path: "/refunds", Handler: handler, Ownership: &Ownership{SupportChannel: "#refunds"}},
+		{Method: "GET", Path: "/health", Handler: handler},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	html := api.generateHTML()
+	for _, snippet := range []string{
+		"<strong>Team:</strong> payments",
+		"<strong>Owner:</strong> jane@acme.io",
+		`<a href="https://acme.slack.com/payments" target="_blank" rel="noopener">`,
+		"<strong>Support:</strong> #refunds",
+	} {
+		if !strings.Contains(html, snippet) {
+			t.Errorf("Expected docs to contain %q", snippet)
+		}
+	}
+	if strings.Count(html, `<div class="ownership">`) != 2 {
+		t.Error("Expected an ownership section only on endpoints with ownership")
+	}
+
+	spec := api.generateOpenAPISpec("http://localhost:8080", api.docsLocale())
+	if owner := spec.Paths["/payments"].Get.Owner; owner == nil || owner.Team != "payments" {
+		t.Errorf("Expected x-owner on /payments, got %+v", owner)
+	}
+	if owner := spec.Paths["/health"].Get.Owner; owner != nil {
+		t.Errorf("Expected no x-owner on /health, got %+v", owner)
+	}
+}
+
+// TestChangeLog tests the endpoint history on the docs card and in the spec
+func TestChangeLog(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return nil }
+	changeLog := []ChangeLogEntry{
+		{Version: "1.4.0", Date: "2026-03-02", Note: "Added the <currency> field"},
+		{Version: "1.0.0", Note: "Introduced"},
+	}
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/payments", Handler: handler, ChangeLog: changeLog}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/health", Handler: handler}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	html := api.generateHTML()
+	for _, snippet := range []string{
+		"<summary>History (2)</summary>",
+		`<span class="changelog-version">1.4.0</span> <time datetime="2026-03-02">2026-03-02</time> Added the &lt;currency&gt; field`,
+		`<span class="changelog-version">1.0.0</span> Introduced`,
+	} {
+		if !strings.Contains(html, snippet) {
+			t.Errorf("Expected docs to contain %q", snippet)
+		}
+	}
+	if strings.Count(html, `<details class="changelog">`) != 1 {
+		t.Error("Expected a history only on endpoints with a change log")
+	}
+
+	spec := api.generateOpenAPISpec("http://localhost:8080", api.docsLocale())
+	if history := spec.Paths["/payments"].Get.ChangeLog; len(history) != 2 || history[0].Version != "1.4.0" {
+		t.Errorf("Expected x-changelog on /payments, got %+v", history)
+	}
+	if history := spec.Paths["/health"].Get.ChangeLog; history != nil {
+		t.Errorf("Expected no x-changelog on /health, got %+v", history)
+	}
+
+	for _, invalid := range [][]ChangeLogEntry{{{Version: "2.0.0"}}, {{Date: "March 2", Note: "Renamed"}}} {
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/refunds", Handler: handler, ChangeLog: invalid}); err == nil {
+			t.Errorf("Expected %+v to be rejected", invalid)
+		}
+	}
+}
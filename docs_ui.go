@@ -0,0 +1,240 @@
+package notelink
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// DocsUI is a documentation frontend. Frontends listed in Config.UIs are
+// mounted at <Config.DocsPath>/<Route> and linked from a landing page at
+// Config.DocsPath (/api-docs by default).
+type DocsUI interface {
+	Name() string                       // Label on the landing page, e.g. "Swagger UI"
+	Route() string                      // Path segment under the docs path, e.g. "swagger"
+	Handler(api *ApiNote) fiber.Handler // Serves the frontend, typically pointing it at <docs path>/openapi.json
+}
+
+// docsUI is a built-in DocsUI
+type docsUI struct {
+	name    string
+	route   string
+	handler func(api *ApiNote) fiber.Handler
+}
+
+func (u docsUI) Name() string                       { return u.name }
+func (u docsUI) Route() string                      { return u.route }
+func (u docsUI) Handler(api *ApiNote) fiber.Handler { return u.handler(api) }
+
+// NotelinkUI is the built-in HTML documentation with the try-it client
+func NotelinkUI() DocsUI {
+	return docsUI{name: "Notelink", route: "notelink", handler: (*ApiNote).Handler}
+}
+
+// SwaggerUI serves Swagger UI
+func SwaggerUI() DocsUI {
+	return docsUI{name: "Swagger UI", route: "swagger", handler: (*ApiNote).SwaggerUIHandler}
+}
+
+// ScalarUI serves the Scalar API reference
+func ScalarUI() DocsUI {
+	return docsUI{name: "Scalar", route: "scalar", handler: (*ApiNote).ScalarUIHandler}
+}
+
+// RedocUI serves ReDoc
+func RedocUI() DocsUI {
+	return docsUI{name: "ReDoc", route: "redoc", handler: (*ApiNote).RedocUIHandler}
+}
+
+// frontends returns the frontends served under the docs path with
+// Config.DefaultUI: Config.UIs, or the built-in ones
+func (an *ApiNote) frontends() []DocsUI {
+	if len(an.config.UIs) > 0 {
+		return an.config.UIs
+	}
+	return []DocsUI{NotelinkUI(), SwaggerUI(), ScalarUI(), RedocUI()}
+}
+
+// uiRoute returns the path segment of a frontend
+func uiRoute(ui DocsUI) string {
+	return strings.Trim(ui.Route(), "/")
+}
+
+// findFrontend returns the route of the frontend named route, accepting
+// surrounding slashes
+func (an *ApiNote) findFrontend(route string) (string, bool) {
+	route = strings.Trim(route, "/")
+	for _, ui := range an.frontends() {
+		if uiRoute(ui) == route {
+			return route, true
+		}
+	}
+	return "", false
+}
+
+// currentDefaultUI returns the route of the frontend served at the docs path:
+// the one SetDefaultUI chose, else Config.DefaultUI, else the first frontend
+// when Config.DefaultUI names none of them
+func (an *ApiNote) currentDefaultUI() string {
+	if route, ok := an.defaultUI.Load().(string); ok {
+		return route
+	}
+	if route, ok := an.findFrontend(an.config.DefaultUI); ok {
+		return route
+	}
+	return uiRoute(an.frontends()[0])
+}
+
+// SetDefaultUI switches the frontend served at the docs path while the app
+// runs, e.g. from an admin endpoint; route is "notelink", "swagger",
+// "scalar", "redoc" or the Route of a custom frontend. It requires
+// Config.DefaultUI, which mounts the frontends it switches between.
+func (an *ApiNote) SetDefaultUI(route string) error {
+	if an.config.DefaultUI == "" {
+		return fmt.Errorf("SetDefaultUI requires Config.DefaultUI")
+	}
+	found, ok := an.findFrontend(route)
+	if !ok {
+		return fmt.Errorf("unknown docs frontend %q", route)
+	}
+	an.defaultUI.Store(found)
+	return nil
+}
+
+// legacyDocsUI returns the frontend selected by Config.DocsUI: Scalar by
+// default, "swagger" or "redoc", and the built-in HTML for any other value
+func legacyDocsUI(name string) DocsUI {
+	switch name {
+	case "", "scalar":
+		return ScalarUI()
+	case "swagger":
+		return SwaggerUI()
+	case "redoc":
+		return RedocUI()
+	default:
+		return NotelinkUI()
+	}
+}
+
+// landingLink is an entry of the docs landing page
+type landingLink struct {
+	href  string
+	label string
+}
+
+// landingLinks lists the docs frontends followed by the spec downloads,
+// client exports, circuit breaker states, SLO compliance and metrics
+func (an *ApiNote) landingLinks() []landingLink {
+	docs := an.docsPath()
+	var links []landingLink
+	if an.config.DefaultUI != "" {
+		links = append(links, landingLink{docs, "Docs home"})
+		for _, ui := range an.frontends() {
+			links = append(links, landingLink{docs + "/" + uiRoute(ui), ui.Name()})
+			if uiRoute(ui) == "notelink" {
+				links = append(links, landingLink{docs + "/print", "Print view"})
+			}
+		}
+	} else if len(an.config.UIs) == 0 {
+		ui := legacyDocsUI(an.config.DocsUI)
+		links = append(links, landingLink{docs, ui.Name()}, landingLink{docs + "/print", "Print view"})
+	} else {
+		for _, ui := range an.config.UIs {
+			links = append(links, landingLink{docs + "/" + uiRoute(ui), ui.Name()})
+		}
+	}
+	links = append(links,
+		landingLink{docs + "/openapi.json", "OpenAPI specification (JSON)"},
+		landingLink{docs + "/openapi.yaml", "OpenAPI specification (YAML)"},
+		landingLink{docs + "/postman.json", "Postman collection"},
+		landingLink{docs + "/types.ts", "TypeScript types"},
+	)
+	if len(an.channels) > 0 {
+		links = append(links, landingLink{docs + "/asyncapi.json", "AsyncAPI specification"})
+	}
+	if an.config.Catalog != nil {
+		links = append(links, landingLink{docs + "/catalog-info.yaml", "Backstage catalog entity"})
+	}
+	if len(an.CircuitBreakers()) > 0 {
+		links = append(links, landingLink{docs + "/breakers", "Circuit breakers"})
+	}
+	if len(an.SLOs()) > 0 {
+		links = append(links, landingLink{docs + "/slo", "SLO compliance"}, landingLink{docs + "/slo/metrics", "SLO metrics (Prometheus)"})
+	}
+	if an.config.PayloadSizes != nil {
+		links = append(links, landingLink{docs + "/payload-sizes", "Payload sizes"}, landingLink{docs + "/payload-sizes/metrics", "Payload size metrics (Prometheus)"})
+	}
+	for _, endpoint := range an.endpoints {
+		if endpoint.Deprecated && an.diagnosticsServed() {
+			links = append(links, landingLink{docs + "/deprecations", "Deprecated route usage"})
+			break
+		}
+	}
+	if an.config.ValidationFailures != nil && an.diagnosticsServed() {
+		links = append(links, landingLink{docs + "/validation-failures", "Validation failures"}, landingLink{docs + "/validation-failures/metrics", "Validation failure metrics (Prometheus)"})
+	}
+	links = append(links, landingLink{docs + "/metrics", "Metrics"})
+	if an.diagnosticsServed() {
+		links = append(links, landingLink{docs + "/debug", "Registry sizes"})
+	}
+	return links
+}
+
+// generateLandingHTML creates the page linking to every docs frontend and
+// export, served at <docs path>/index and, with Config.UIs, at the docs path
+func (an *ApiNote) generateLandingHTML() string {
+	var links strings.Builder
+	for _, link := range an.landingLinks() {
+		links.WriteString(`
+        <li><a href="` + escapeHTML(link.href) + `">` + escapeHTML(link.label) + `</a></li>`)
+	}
+
+	return `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>` + escapeHTML(an.config.Title) + ` - API Documentation</title>
+    <link rel="icon" type="image/png" sizes="32x32" href="` + an.docsPath() + `/icon.png">
+    <style>
+        body {
+            margin: 0;
+            padding: 48px 24px;
+            font-family: ui-sans-serif, system-ui, sans-serif;
+            color: #1e293b;
+            background: #f8fafc;
+        }
+        main {
+            max-width: 480px;
+            margin: 0 auto;
+        }
+        ul {
+            list-style: none;
+            padding: 0;
+        }
+        li a {
+            display: block;
+            margin-bottom: 12px;
+            padding: 16px 20px;
+            border: 1px solid #e2e8f0;
+            border-radius: 10px;
+            background: #fff;
+            color: inherit;
+            font-weight: 600;
+            text-decoration: none;
+        }
+        li a:hover {
+            border-color: #6366f1;
+        }
+    </style>
+</head>
+<body>
+    <main>
+        <h1>` + escapeHTML(an.config.Title) + `</h1>
+        <ul>` + links.String() + `
+        </ul>
+    </main>
+</body>
+</html>`
+}
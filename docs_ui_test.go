@@ -0,0 +1,158 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// rapiDocUI is a custom frontend used to test DocsUI registration
+type rapiDocUI struct{}
+
+func (rapiDocUI) Name() string  { return "RapiDoc" }
+func (rapiDocUI) Route() string { return "/rapidoc/" }
+func (rapiDocUI) Handler(api *ApiNote) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		return c.SendString(`<rapi-doc spec-url="/api-docs/openapi.json"></rapi-doc>`)
+	}
+}
+
+// TestDocsUIs tests mounting several documentation frontends
+func TestDocsUIs(t *testing.T) {
+	get := func(api *ApiNote, path string) (int, string) {
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		UIs:   []DocsUI{NotelinkUI(), SwaggerUI(), ScalarUI(), RedocUI(), rapiDocUI{}},
+	}, "secret")
+
+	tests := []struct {
+		path    string
+		snippet string
+	}{
+		{path: "/api-docs", snippet: `<a href="/api-docs/swagger">Swagger UI</a>`},
+		{path: "/api-docs/notelink", snippet: "const baseUrl ="},
+		{path: "/api-docs/swagger", snippet: "SwaggerUIBundle("},
+		{path: "/api-docs/scalar", snippet: `id="api-reference"`},
+		{path: "/api-docs/redoc", snippet: `<redoc spec-url="/api-docs/openapi.json">`},
+		{path: "/api-docs/rapidoc", snippet: "<rapi-doc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			status, body := get(api, tt.path)
+			if status != 200 || !strings.Contains(body, tt.snippet) {
+				t.Errorf("Expected %s to serve %q, got %d", tt.path, tt.snippet, status)
+			}
+		})
+	}
+	if _, landing := get(api, "/api-docs"); !strings.Contains(landing, `<a href="/api-docs/rapidoc">RapiDoc</a>`) {
+		t.Error("Expected the landing page to link custom frontends")
+	}
+
+	legacy := []struct {
+		docsUI  string
+		snippet string
+	}{
+		{docsUI: "", snippet: `id="api-reference"`},
+		{docsUI: "swagger", snippet: "SwaggerUIBundle("},
+		{docsUI: "redoc", snippet: "<redoc "},
+		{docsUI: "html", snippet: "const baseUrl ="},
+	}
+	for _, tt := range legacy {
+		t.Run("DocsUI "+tt.docsUI, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", DocsUI: tt.docsUI}, "secret")
+			if _, body := get(api, "/api-docs"); !strings.Contains(body, tt.snippet) {
+				t.Errorf("Expected /api-docs to serve %q", tt.snippet)
+			}
+		})
+	}
+}
+
+// TestDefaultUI tests choosing and switching the frontend served at the docs path
+func TestDefaultUI(t *testing.T) {
+	get := func(api *ApiNote, path string) string {
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	tests := []struct {
+		name      string
+		config    Config
+		switchTo  string
+		root      string
+		subpaths  map[string]string
+		switchErr bool
+	}{
+		{
+			name:   "built-in frontends",
+			config: Config{DefaultUI: "swagger"},
+			root:   "SwaggerUIBundle(",
+			subpaths: map[string]string{
+				"/api-docs/notelink": "const baseUrl =",
+				"/api-docs/scalar":   `id="api-reference"`,
+				"/api-docs/redoc":    "<redoc ",
+				"/api-docs/links":    `<a href="/api-docs/notelink">Notelink</a>`,
+			},
+		},
+		{
+			name:     "switched at runtime",
+			config:   Config{DefaultUI: "notelink"},
+			switchTo: "redoc",
+			root:     "<redoc ",
+		},
+		{
+			name:      "unknown frontend",
+			config:    Config{DefaultUI: "scalar"},
+			switchTo:  "rapidoc",
+			root:      `id="api-reference"`,
+			switchErr: true,
+		},
+		{
+			name:     "custom frontends",
+			config:   Config{DefaultUI: "/rapidoc/", UIs: []DocsUI{SwaggerUI(), rapiDocUI{}}},
+			root:     "<rapi-doc",
+			subpaths: map[string]string{"/api-docs/swagger": "SwaggerUIBundle(", "/api-docs/links": `<a href="/api-docs/rapidoc">RapiDoc</a>`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.Title, config.Host = "Test API", "localhost:8080"
+			api := NewApiNote(&config, "secret")
+			if tt.switchTo != "" {
+				if err := api.SetDefaultUI(tt.switchTo); (err != nil) != tt.switchErr {
+					t.Fatalf("Unexpected SetDefaultUI error: %v", err)
+				}
+			}
+			if body := get(api, "/api-docs"); !strings.Contains(body, tt.root) {
+				t.Errorf("Expected /api-docs to serve %q", tt.root)
+			}
+			for path, snippet := range tt.subpaths {
+				if body := get(api, path); !strings.Contains(body, snippet) {
+					t.Errorf("Expected %s to serve %q", path, snippet)
+				}
+			}
+		})
+	}
+
+	if err := NewApiNote(&Config{Title: "Test API"}, "secret").SetDefaultUI("swagger"); err == nil {
+		t.Error("Expected SetDefaultUI to require Config.DefaultUI")
+	}
+}
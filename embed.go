@@ -0,0 +1,79 @@
+package notelink
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// EmbedConfig renders the built-in docs page as a widget for iframes, e.g. in a
+// developer portal: the header and the authorization chrome are left out, and
+// the page posts its height to the parent window whenever it changes:
+//
+//	{"type": "notelink:resize", "height": 1234}
+//
+// The parent may in turn post {"type": "notelink:auth", "token": "..."} to
+// authorize the try-it client with its own session.
+type EmbedConfig struct {
+	// ParentOrigins are the origins allowed to frame the docs pages, to
+	// receive their height and to send tokens, e.g.
+	// "https://developer.example.com". Without them only the docs' own
+	// origin may embed them.
+	ParentOrigins []string
+}
+
+// embedded reports whether the docs page renders in embed mode
+func (an *ApiNote) embedded() bool {
+	return an.config.EmbedMode != nil
+}
+
+// frameAncestors returns the CSP frame-ancestors source of the docs
+// pages in embed mode
+func (e *EmbedConfig) frameAncestors() string {
+	return strings.Join(append([]string{"'self'"}, e.ParentOrigins...), " ")
+}
+
+// embedScript reports the page height to the parent window and accepts its
+// tokens, in embed mode
+func (an *ApiNote) embedScript(printView bool) string {
+	if !an.embedded() || printView {
+		return ""
+	}
+	origins, err := json.Marshal(an.config.EmbedMode.ParentOrigins)
+	if err != nil || len(an.config.EmbedMode.ParentOrigins) == 0 {
+		origins = []byte("[]")
+	}
+	return `
+
+            // Embed mode: report the height to the parent window and accept its tokens
+            const embedOrigins = ` + string(origins) + `;
+            function embedTargets() {
+                return embedOrigins.length > 0 ? embedOrigins : [window.location.origin];
+            }
+            let embedHeight = 0;
+            function reportEmbedHeight() {
+                const height = document.documentElement.scrollHeight;
+                if (window.parent === window || height === embedHeight) {
+                    return;
+                }
+                embedHeight = height;
+                embedTargets().forEach(origin => {
+                    window.parent.postMessage({type: 'notelink:resize', height: height}, origin);
+                });
+            }
+            window.addEventListener('message', function(e) {
+                if (e.source !== window.parent || !embedTargets().includes(e.origin)) {
+                    return;
+                }
+                if (e.data && e.data.type === 'notelink:auth' && typeof e.data.token === 'string') {
+                    authToken = e.data.token;
+                }
+            });
+            document.addEventListener('DOMContentLoaded', function() {
+                if (window.ResizeObserver) {
+                    new ResizeObserver(reportEmbedHeight).observe(document.body);
+                }
+                document.addEventListener('toggle', reportEmbedHeight, true);
+                window.addEventListener('resize', reportEmbedHeight);
+                reportEmbedHeight();
+            });`
+}
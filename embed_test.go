@@ -0,0 +1,69 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestEmbedMode tests rendering the docs as an iframe widget
+func TestEmbedMode(t *testing.T) {
+	tests := []struct {
+		name           string
+		embed          *EmbedConfig
+		frameOptions   string
+		frameAncestors string
+	}{
+		{name: "standalone", frameOptions: "DENY", frameAncestors: "frame-ancestors 'none'"},
+		{name: "same origin", embed: &EmbedConfig{}, frameOptions: "SAMEORIGIN", frameAncestors: "frame-ancestors 'self';"},
+		{
+			name:           "portal",
+			embed:          &EmbedConfig{ParentOrigins: []string{"https://developer.example.com"}},
+			frameAncestors: "frame-ancestors 'self' https://developer.example.com;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", DocsUI: "notelink", EmbedMode: tt.embed}, "secret")
+			if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/users", Description: "List users", Handler: func(c fiber.Ctx) error { return nil }}); err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", api.docsPath(), nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			html := string(body)
+
+			embedded := tt.embed != nil
+			if strings.Contains(html, `id="auth-token"`) == embedded || strings.Contains(html, `<div class="header">`) == embedded {
+				t.Errorf("Expected the header and auth chrome only outside embed mode (embedded %v)", embedded)
+			}
+			if strings.Contains(html, "type: 'notelink:resize'") != embedded || strings.Contains(html, `<body class="embed-mode">`) != embedded {
+				t.Errorf("Expected height reporting only in embed mode (embedded %v)", embedded)
+			}
+			if !strings.Contains(html, `id="test-result-GET-users"`) {
+				t.Error("Expected the endpoints to render")
+			}
+			if got := resp.Header.Get("X-Frame-Options"); got != tt.frameOptions {
+				t.Errorf("Expected X-Frame-Options %q, got %q", tt.frameOptions, got)
+			}
+			if csp := resp.Header.Get("Content-Security-Policy"); !strings.Contains(csp, tt.frameAncestors) {
+				t.Errorf("Expected the docs policy to contain %q, got %q", tt.frameAncestors, csp)
+			}
+		})
+	}
+
+	api := NewApiNote(&Config{Title: "Test API", EmbedMode: &EmbedConfig{ParentOrigins: []string{"https://developer.example.com"}}}, "secret")
+	if html := api.generateHTML(); !strings.Contains(html, `const embedOrigins = ["https://developer.example.com"];`) {
+		t.Error("Expected the parent origins in the embed script")
+	}
+	if html := api.generatePrintHTML("", "en"); !strings.Contains(html, `id="auth-token"`) || strings.Contains(html, "notelink:resize") {
+		t.Error("Expected the print view to keep its chrome")
+	}
+}
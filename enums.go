@@ -0,0 +1,73 @@
+package notelink
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+)
+
+// TypeScript declarations of enum types, set with Config.TypeScriptEnums
+const (
+	TypeScriptEnumUnion = "union" // export type Status = "active" | "closed"; (default)
+	TypeScriptEnumEnum  = "enum"  // export enum Status { Active = "active", Closed = "closed" }
+)
+
+// SchemaEnum is implemented by named types with a fixed set of values, e.g.
+//
+//	type Status string
+//
+//	func (Status) EnumValues() []string { return []string{"active", "closed"} }
+//
+// Fields of the type are documented with the values as their enum, validated
+// against them and declared once as a TypeScript union or enum named after the
+// type. An `enum:"a,b"` tag on a field overrides the type's values.
+type SchemaEnum = validation.Enum
+
+// tsUnion renders enum values as a TypeScript string-literal union
+func tsUnion(values []string) string {
+	literals := make([]string, len(values))
+	for i, value := range values {
+		literals[i] = strconv.Quote(value)
+	}
+	return strings.Join(literals, " | ")
+}
+
+// tsEnumFieldType renders a field with enum tag values as a union, keeping the
+// field's pointers and slices
+func tsEnumFieldType(t reflect.Type, values []string) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return tsEnumFieldType(t.Elem(), values) + " | null"
+	case reflect.Slice:
+		return "(" + tsEnumFieldType(t.Elem(), values) + ")[]"
+	default:
+		return tsUnion(values)
+	}
+}
+
+// tsEnumDeclaration declares a SchemaEnum type as a union type or, with
+// TypeScriptEnumEnum, an enum whose members are the values in PascalCase
+func tsEnumDeclaration(name string, t reflect.Type, names *schemaNames) string {
+	values := validation.TypeEnumValues(t)
+	var lines []string
+	if description := typeDescription(t); description != "" {
+		lines = append(lines, description)
+	}
+	if names == nil || names.enums != TypeScriptEnumEnum {
+		return jsDoc("", lines) + "export type " + name + " = " + tsUnion(values) + ";"
+	}
+
+	var ts strings.Builder
+	ts.WriteString(jsDoc("", lines) + "export enum " + name + " {\n")
+	for _, value := range values {
+		member := identifierName(value)
+		if member == "" || (member[0] >= '0' && member[0] <= '9') {
+			member = "Value" + member
+		}
+		ts.WriteString("  " + member + " = " + strconv.Quote(value) + ",\n")
+	}
+	ts.WriteString("}")
+	return ts.String()
+}
@@ -0,0 +1,117 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type enumStatus string
+
+func (enumStatus) EnumValues() []string { return []string{"active", "in_progress", "closed"} }
+
+func (enumStatus) SchemaDescription() string { return "Lifecycle of a ticket" }
+
+type enumTicket struct {
+	Status   enumStatus   `json:"status"`
+	Previous *enumStatus  `json:"previous,omitempty"`
+	History  []enumStatus `json:"history"`
+	Priority string       `json:"priority" enum:"low,high"`
+	Legacy   enumStatus   `json:"legacy" enum:"open,done"`
+}
+
+// TestTypeScriptEnums tests declaring SchemaEnum types and enum tags in TypeScript
+func TestTypeScriptEnums(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    string
+		contains []string
+	}{
+		{
+			name:  "unions by default",
+			style: "",
+			contains: []string{
+				"/** Lifecycle of a ticket */\nexport type enumStatus = \"active\" | \"in_progress\" | \"closed\";",
+				"status: enumStatus;",
+				"previous?: enumStatus | null;",
+				"history: enumStatus[];",
+				"priority: \"low\" | \"high\";",
+				"legacy: \"open\" | \"done\";",
+			},
+		},
+		{
+			name:  "enums",
+			style: TypeScriptEnumEnum,
+			contains: []string{
+				"export enum enumStatus {\n  Active = \"active\",\n  InProgress = \"in_progress\",\n  Closed = \"closed\",\n}",
+				"status: enumStatus;",
+				"priority: \"low\" | \"high\";",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := generateTypeScriptSchema("Ticket", enumTicket{}, "", &schemaNames{enums: tt.style})
+			for _, fragment := range tt.contains {
+				if !strings.Contains(ts, fragment) {
+					t.Errorf("Expected %q in:\n%s", fragment, ts)
+				}
+			}
+			if strings.Count(ts, "enumStatus =") > 1 || strings.Count(ts, "enum enumStatus") > 1 {
+				t.Errorf("Expected enumStatus declared once, got:\n%s", ts)
+			}
+		})
+	}
+}
+
+// TestSchemaEnumTypes tests SchemaEnum types in schemas, validation and modules
+func TestSchemaEnumTypes(t *testing.T) {
+	schema, _ := generateJSONSchema("Ticket", enumTicket{}, "", nil)
+	if enum := schema.Properties["status"].Enum; len(enum) != 3 || enum[1] != "in_progress" {
+		t.Errorf("Expected the type's values as the enum, got %v", enum)
+	}
+	if items := schema.Properties["history"].Items; items == nil || len(items.Enum) != 3 {
+		t.Errorf("Expected enum items, got %+v", items)
+	}
+	if enum := schema.Properties["legacy"].Enum; len(enum) != 2 || enum[0] != "open" {
+		t.Errorf("Expected the tag to override the type's values, got %v", enum)
+	}
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", TypeScriptEnums: TypeScriptEnumEnum}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/tickets",
+		Description:    "Open a ticket",
+		SchemasRequest: enumTicket{},
+		Handler:        func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	for body, expected := range map[string]int{
+		`{"status":"active","history":[],"priority":"low","legacy":"open"}`:   fiber.StatusCreated,
+		`{"status":"archived","history":[],"priority":"low","legacy":"open"}`: fiber.StatusBadRequest,
+	} {
+		req := httptest.NewRequest("POST", "/tickets", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Errorf("Expected %d for %s, got %d", expected, body, resp.StatusCode)
+		}
+	}
+
+	modules := api.TypeScriptModules()
+	if model := string(modules["models/enumStatus.ts"]); !strings.Contains(model, "export enum enumStatus {") {
+		t.Errorf("Expected an enum module, got:\n%s", model)
+	}
+	if ticket := string(modules["models/enumTicket.ts"]); !strings.Contains(ticket, "import type { enumStatus } from './enumStatus';") {
+		t.Errorf("Expected the enum imported, got:\n%s", ticket)
+	}
+}
@@ -0,0 +1,271 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+)
+
+// ErrorCode is a business error code registered with RegisterErrorCode
+type ErrorCode struct {
+	Code        string      `json:"code"`
+	Status      int         `json:"status"`
+	Description string      `json:"description"`
+	Schema      interface{} `json:"-"` // Error body; nil uses the configured error schema
+}
+
+// errorCodeObject describes an error code in the x-error-codes extension of the spec
+type errorCodeObject struct {
+	Code        string      `json:"code"`
+	Status      int         `json:"status"`
+	Description string      `json:"description,omitempty"`
+	Schema      *JSONSchema `json:"schema,omitempty"`
+}
+
+// RegisterErrorCode defines a business error code centrally: the HTTP status it
+// is returned with, its description and the schema of its error body (nil for
+// the configured error schema). Routes registered afterwards reference it by
+// code in Responses, e.g. Responses: {"USER_NOT_FOUND": ""}; the code is
+// documented under its status, with a non-empty value replacing the
+// description for that route. Codes are listed in the "Error codes" section of
+// the docs and exported as x-error-codes in the spec.
+func (an *ApiNote) RegisterErrorCode(code string, httpStatus int, description string, schema interface{}) error {
+	if code == "" {
+		return fmt.Errorf("error code is required")
+	}
+	if _, err := strconv.Atoi(code); err == nil {
+		return fmt.Errorf("error code %q must not be a status code", code)
+	}
+	if !isErrorStatus(strconv.Itoa(httpStatus)) {
+		return fmt.Errorf("error code %s: status %d is not a 4xx or 5xx status", code, httpStatus)
+	}
+	if _, ok := an.errorCodes[code]; ok {
+		return fmt.Errorf("error code %s is already registered", code)
+	}
+	if err := an.checkSchemaNames(schema); err != nil {
+		return err
+	}
+	if err := an.checkSchemaTypes("error code "+code, schema); err != nil {
+		return err
+	}
+
+	if an.errorCodes == nil {
+		an.errorCodes = make(map[string]ErrorCode)
+	}
+	an.errorCodes[code] = ErrorCode{Code: code, Status: httpStatus, Description: description, Schema: schema}
+	an.artifacts.invalidate()
+	return nil
+}
+
+// ErrorCodes returns the registered error codes, ordered by status and code
+func (an *ApiNote) ErrorCodes() []ErrorCode {
+	codes := make([]ErrorCode, 0, len(an.errorCodes))
+	for _, code := range an.errorCodes {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i].Status != codes[j].Status {
+			return codes[i].Status < codes[j].Status
+		}
+		return codes[i].Code < codes[j].Code
+	})
+	return codes
+}
+
+// resolveErrorCodes replaces the error codes referenced in an endpoint's
+// Responses, and their translations, with their status, recording the codes on
+// the endpoint. A status the route documents itself keeps its description.
+func (an *ApiNote) resolveErrorCodes(endpoint *Endpoint) {
+	if len(an.errorCodes) == 0 {
+		return
+	}
+	endpoint.ErrorCodes = an.resolveErrorCodeResponses(endpoint.Responses)
+	for _, responses := range endpoint.LocalizedResponses {
+		an.resolveErrorCodeResponses(responses)
+	}
+}
+
+// resolveErrorCodeResponses replaces the error codes among response
+// descriptions with their status, returning the codes sorted
+func (an *ApiNote) resolveErrorCodeResponses(responses map[string]string) []string {
+	var codes []string
+	codeDescriptions := make(map[string][]string)
+	for key, description := range responses {
+		code, ok := an.errorCodes[key]
+		if !ok {
+			continue
+		}
+		delete(responses, key)
+		if description == "" {
+			description = code.Description
+		}
+		status := strconv.Itoa(code.Status)
+		codes = append(codes, key)
+		codeDescriptions[status] = append(codeDescriptions[status], key+": "+description)
+	}
+	sort.Strings(codes)
+
+	for status, descriptions := range codeDescriptions {
+		if responses[status] == "" {
+			sort.Strings(descriptions)
+			responses[status] = strings.Join(descriptions, "; ")
+		}
+	}
+	return codes
+}
+
+// statusErrorCodes returns the error codes an endpoint references for a status
+func (an *ApiNote) statusErrorCodes(endpoint *Endpoint, statusCode string) []ErrorCode {
+	var codes []ErrorCode
+	for _, name := range endpoint.ErrorCodes {
+		if code, ok := an.errorCodes[name]; ok && strconv.Itoa(code.Status) == statusCode {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// errorCodeBody returns the error body schema of a code
+func (an *ApiNote) errorCodeBody(code *ErrorCode) interface{} {
+	if code.Schema != nil {
+		return code.Schema
+	}
+	return an.errorSchema()
+}
+
+// errorCodesResponse documents the error codes of a response: the codes' body
+// schema (oneOf when they differ) and a named example per code
+func (an *ApiNote) errorCodesResponse(response *Response, statusCode string, codes []ErrorCode, componentSchemas map[string]*JSONSchema) {
+	var schemas []*JSONSchema
+	seen := make(map[reflect.Type]bool)
+	examples := make(map[string]ExampleObject, len(codes))
+	for i := range codes {
+		code := &codes[i]
+		response.ErrorCodes = append(response.ErrorCodes, code.Code)
+
+		body := an.errorCodeBody(code)
+		if typ := reflect.TypeOf(body); !seen[typ] {
+			seen[typ] = true
+			schemas = append(schemas, an.bodySchema("ErrorBody", body, componentSchemas))
+		}
+		example, err := an.errorCodeExample(code, statusCode)
+		if err == nil {
+			examples[code.Code] = ExampleObject{Value: example, Summary: code.Description}
+		}
+	}
+
+	schema := schemas[0]
+	if len(schemas) > 1 {
+		schema = &JSONSchema{OneOf: schemas}
+	}
+	response.Content = map[string]MediaType{
+		fiber.MIMEApplicationJSON: {Schema: schema, Examples: examples},
+	}
+}
+
+// errorCodeExample returns the example error body of a code
+func (an *ApiNote) errorCodeExample(code *ErrorCode, statusCode string) (interface{}, error) {
+	if code.Schema == nil {
+		example, err := an.errorExample(statusCode)
+		if errorResponse, ok := example.(ErrorResponse); ok && code.Description != "" {
+			errorResponse.Error = code.Description
+			return errorResponse, err
+		}
+		return example, err
+	}
+	template, err := generateJSONTemplate(code.Schema, an.exampleOptions())
+	if err != nil {
+		return nil, err
+	}
+	var example interface{}
+	err = json.Unmarshal([]byte(template), &example)
+	return example, err
+}
+
+// errorCodesExtension describes the registered error codes for the x-error-codes
+// extension of the spec
+func (an *ApiNote) errorCodesExtension(componentSchemas map[string]*JSONSchema) []errorCodeObject {
+	var objects []errorCodeObject
+	for _, code := range an.ErrorCodes() {
+		objects = append(objects, errorCodeObject{
+			Code:        code.Code,
+			Status:      code.Status,
+			Description: code.Description,
+			Schema:      an.bodySchema("ErrorBody", an.errorCodeBody(&code), componentSchemas),
+		})
+	}
+	return objects
+}
+
+// validatesRequests reports whether any endpoint validates its requests, and
+// so can respond with the field errors of validation.Codes
+func (an *ApiNote) validatesRequests() bool {
+	for _, endpoint := range an.endpoints {
+		if len(endpoint.Parameters) > 0 || endpoint.RequestSchema != nil || len(endpoint.ValidationRules) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// renderErrorCodes renders the "Error codes" section of the docs, with the
+// registered codes and the validation error codes, and its table of contents entry
+func (an *ApiNote) renderErrorCodes() (string, string) {
+	codes := an.ErrorCodes()
+	validates := an.validatesRequests()
+	if len(codes) == 0 && !validates {
+		return "", ""
+	}
+
+	var html strings.Builder
+	html.WriteString(`
+    <details class="top-segment-group" id="error-codes">
+        <summary>Error codes</summary>
+        <div class="error-codes">`)
+	if len(codes) > 0 {
+		html.WriteString(`
+            <table>
+                <thead><tr><th>Code</th><th>Status</th><th>Description</th></tr></thead>
+                <tbody>`)
+		for _, code := range codes {
+			html.WriteString(`
+                    <tr id="error-code-` + escapeHTML(code.Code) + `"><td><code>` + escapeHTML(code.Code) + `</code></td><td>` +
+				strconv.Itoa(code.Status) + `</td><td>` + escapeHTML(code.Description) + `</td></tr>`)
+		}
+		html.WriteString(`
+                </tbody>
+            </table>`)
+	}
+	if validates {
+		html.WriteString(`
+            <h4>Validation error codes</h4>
+            <p>400 responses list the invalid fields in <code>errors</code>, each with one of these codes. Codes are stable across releases; branch on them rather than on messages.</p>
+            <table>
+                <thead><tr><th>Code</th><th>Type</th><th>Description</th></tr></thead>
+                <tbody>`)
+		for _, code := range validation.Codes() {
+			html.WriteString(`
+                    <tr id="error-code-` + code.Code + `"><td><code>` + code.Code + `</code></td><td><code>` + code.Type + `</code></td><td>` +
+				escapeHTML(code.Description) + `</td></tr>`)
+		}
+		html.WriteString(`
+                </tbody>
+            </table>`)
+	}
+	for i := range codes {
+		if codes[i].Schema != nil {
+			html.WriteString(renderSchemaViews(codes[i].Code, identifierName(strings.ToLower(codes[i].Code))+"Error", codes[i].Schema, an.exampleOptions(), an.schemaNames()))
+		}
+	}
+	html.WriteString(`
+        </div>
+    </details>`)
+	return html.String(), `
+                <li><a href="#error-codes">Error codes</a></li>`
+}
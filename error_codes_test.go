@@ -0,0 +1,163 @@
+package notelink
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+)
+
+type quotaError struct {
+	Code      string `json:"code"`
+	Remaining int    `json:"remaining"`
+}
+
+// TestRegisterErrorCode tests registering error codes and referencing them from routes
+func TestRegisterErrorCode(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+
+	invalid := []struct {
+		name   string
+		code   string
+		status int
+	}{
+		{"empty code", "", 404},
+		{"status as code", "404", 404},
+		{"success status", "USER_CREATED", 201},
+	}
+	for _, tt := range invalid {
+		if err := api.RegisterErrorCode(tt.code, tt.status, "", nil); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+
+	for _, code := range []ErrorCode{
+		{Code: "USER_NOT_FOUND", Status: 404, Description: "The user does not exist"},
+		{Code: "ORDER_NOT_FOUND", Status: 404, Description: "The order does not exist"},
+		{Code: "QUOTA_EXCEEDED", Status: 429, Description: "The monthly quota is used up", Schema: quotaError{}},
+	} {
+		if err := api.RegisterErrorCode(code.Code, code.Status, code.Description, code.Schema); err != nil {
+			t.Fatalf("Failed to register error code: %v", err)
+		}
+	}
+	if err := api.RegisterErrorCode("USER_NOT_FOUND", 410, "", nil); err == nil {
+		t.Error("Expected an error for a duplicate code")
+	}
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method: "GET",
+		Path:   "/v1/users/:id/orders/:orderId",
+		Responses: map[string]string{
+			"200":             "Order found",
+			"USER_NOT_FOUND":  "",
+			"ORDER_NOT_FOUND": "No such order for the user",
+			"QUOTA_EXCEEDED":  "",
+		},
+		Handler: func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	endpoint := api.endpoints["GET /v1/users/:id/orders/:orderId"]
+	if got := endpoint.Responses["404"]; got != "ORDER_NOT_FOUND: No such order for the user; USER_NOT_FOUND: The user does not exist" {
+		t.Errorf("Unexpected 404 description %q", got)
+	}
+	if _, ok := endpoint.Responses["USER_NOT_FOUND"]; ok {
+		t.Error("Expected the code to be replaced by its status")
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	responses := spec.Paths["/v1/users/{id}/orders/{orderId}"].Get.Responses
+	notFound := responses["404"]
+	if strings.Join(notFound.ErrorCodes, ",") != "ORDER_NOT_FOUND,USER_NOT_FOUND" {
+		t.Errorf("Unexpected 404 error codes %v", notFound.ErrorCodes)
+	}
+	if example := notFound.Content[fiber.MIMEApplicationJSON].Examples["USER_NOT_FOUND"]; example.Value.(ErrorResponse).Error != "The user does not exist" {
+		t.Errorf("Unexpected USER_NOT_FOUND example %+v", example)
+	}
+	if schema := responses["429"].Content[fiber.MIMEApplicationJSON].Schema; schema == nil || !strings.HasSuffix(schema.Ref, "quotaError") {
+		t.Errorf("Expected the registered schema for 429, got %+v", schema)
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	if !strings.Contains(string(data), `"x-error-codes":[{"code":"ORDER_NOT_FOUND","status":404`) {
+		t.Errorf("Expected the error codes in the spec, got %s", data)
+	}
+
+	html := api.generateHTML()
+	for _, want := range []string{`id="error-codes"`, `id="error-code-QUOTA_EXCEEDED"`, `href="#error-codes"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the docs to contain %q", want)
+		}
+	}
+}
+
+// TestValidationErrorCodes tests the stable codes of validation errors and their reference
+func TestValidationErrorCodes(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	if html := api.generateHTML(); strings.Contains(html, `id="error-codes"`) {
+		t.Error("Expected no error codes section without validated routes")
+	}
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/v1/orders",
+		SchemasRequest: failureOrder{},
+		Handler:        func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		body, contentType, code string
+	}{
+		{`{"quantity":1}`, "application/json", validation.CodeRequired},
+		{`{"sku":1,"quantity":1}`, "application/json", validation.CodeType},
+		{`{"sku":`, "application/json", validation.CodeParse},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/v1/orders", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", tt.contentType)
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		var body ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode error: %v", err)
+		}
+		resp.Body.Close()
+		if len(body.Errors) != 1 || body.Errors[0].Code != tt.code {
+			t.Errorf("Expected %s for %s, got %+v", tt.code, tt.body, body.Errors)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range validation.Codes() {
+		if seen[code.Code] || !strings.HasPrefix(code.Code, "NL4") {
+			t.Errorf("Unexpected code %s", code.Code)
+		}
+		seen[code.Code] = true
+	}
+
+	data, err := json.Marshal(api.GenerateOpenAPISpec())
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	if !strings.Contains(string(data), `"x-validation-error-codes":[{"code":"NL4001_REQUIRED","type":"required"`) {
+		t.Errorf("Expected the validation error codes in the spec, got %s", data)
+	}
+	html := api.generateHTML()
+	for _, want := range []string{`id="error-codes"`, `id="error-code-NL4002_TYPE"`, "Validation error codes"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the docs to contain %q", want)
+		}
+	}
+}
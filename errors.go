@@ -0,0 +1,92 @@
+package notelink
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+)
+
+// ErrorResponse is the error envelope returned for failed requests.
+// Validation failures additionally list the offending fields in Errors, each
+// with a stable Code (see validation.Codes).
+type ErrorResponse struct {
+	Error  string            `json:"error"`
+	Errors []ValidationError `json:"errors,omitempty"`
+}
+
+// DefaultErrorHandler converts errors returned by handlers and middleware into
+// the ErrorResponse envelope with a matching status code:
+//
+//   - *ValidationErrorResponse: 400 Bad Request with the field errors
+//   - *fiber.Error: the error's status code and message
+//   - anything else: 500 Internal Server Error
+//
+// It is installed automatically unless Config.ErrorHandler or
+// Config.FiberConfig.ErrorHandler is set, and can be called from custom
+// handlers to fall back to the default behavior.
+func DefaultErrorHandler(c fiber.Ctx, err error) error {
+	var validationErr *ValidationErrorResponse
+	if errors.As(err, &validationErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(ErrorResponse{
+			Error:  validationErr.ErrorMessage,
+			Errors: validationErr.Errors,
+		})
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return c.Status(fiberErr.Code).JSON(ErrorResponse{Error: fiberErr.Message})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(ErrorResponse{Error: "Internal Server Error"})
+}
+
+// isErrorStatus reports whether a documented response code is a 4xx or 5xx status
+func isErrorStatus(statusCode string) bool {
+	return len(statusCode) == 3 && (statusCode[0] == '4' || statusCode[0] == '5')
+}
+
+// errorSchema returns the schema documented for error responses
+func (an *ApiNote) errorSchema() interface{} {
+	if an.config.ErrorSchema != nil {
+		return an.config.ErrorSchema
+	}
+	return ErrorResponse{}
+}
+
+// errorExample returns the example body for a documented error status. With the
+// default ErrorResponse envelope it mirrors DefaultErrorHandler: the status text
+// as the message, and a sample field error on 400 responses. Custom error
+// schemas get an example generated from the schema.
+func (an *ApiNote) errorExample(statusCode string) (interface{}, error) {
+	schema := an.errorSchema()
+	if _, ok := schema.(ErrorResponse); !ok {
+		template, err := generateJSONTemplate(schema, an.exampleOptions())
+		if err != nil {
+			return nil, err
+		}
+		var example interface{}
+		err = json.Unmarshal([]byte(template), &example)
+		return example, err
+	}
+
+	code, _ := strconv.Atoi(statusCode)
+	example := ErrorResponse{Error: http.StatusText(code)}
+	if example.Error == "" {
+		example.Error = "Error"
+	}
+	if code == fiber.StatusBadRequest {
+		example.Error = "Request body validation failed"
+		example.Errors = []ValidationError{{
+			Field:   "name",
+			Message: "Required field 'name' is missing",
+			Type:    "required",
+			Code:    validation.CodeRequired,
+		}}
+	}
+	return example, nil
+}
@@ -0,0 +1,181 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestDefaultErrorHandler tests conversion of returned errors into the error envelope
+func TestDefaultErrorHandler(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+
+	routes := []DocumentedRouteInput{
+		{
+			Method:         "POST",
+			Path:           "/users",
+			Description:    "Create a user",
+			Handler:        func(c fiber.Ctx) error { return c.SendStatus(201) },
+			SchemasRequest: TestUser{},
+		},
+		{
+			Method:      "GET",
+			Path:        "/missing",
+			Description: "Always not found",
+			Handler:     func(c fiber.Ctx) error { return fiber.NewError(fiber.StatusNotFound, "User not found") },
+		},
+		{
+			Method:      "GET",
+			Path:        "/broken",
+			Description: "Always fails",
+			Handler:     func(c fiber.Ctx) error { return errors.New("database unavailable") },
+		},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedError  string
+		expectedStatus int
+		expectedFields int
+	}{
+		{name: "Validation error", method: "POST", path: "/users", body: `{"name":"John"}`, expectedStatus: 400, expectedError: "Request body validation failed", expectedFields: 2},
+		{name: "Fiber error", method: "GET", path: "/missing", expectedStatus: 404, expectedError: "User not found"},
+		{name: "Unexpected error", method: "GET", path: "/broken", expectedStatus: 500, expectedError: "Internal Server Error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+
+			var envelope ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+				t.Fatalf("Failed to decode error envelope: %v", err)
+			}
+			if envelope.Error != tt.expectedError {
+				t.Errorf("Expected error %q, got %q", tt.expectedError, envelope.Error)
+			}
+			if len(envelope.Errors) != tt.expectedFields {
+				t.Errorf("Expected %d field errors, got %d", tt.expectedFields, len(envelope.Errors))
+			}
+		})
+	}
+}
+
+// TestCustomErrorHandler tests that Config.ErrorHandler replaces the default handler
+func TestCustomErrorHandler(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		ErrorHandler: func(c fiber.Ctx, err error) error {
+			return c.Status(fiber.StatusTeapot).SendString("custom: " + err.Error())
+		},
+	}, "secret")
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/fail",
+		Description: "Always fails",
+		Handler:     func(c fiber.Ctx) error { return errors.New("boom") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/fail", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != fiber.StatusTeapot {
+		t.Errorf("Expected custom status 418, got %d", resp.StatusCode)
+	}
+}
+
+// TestErrorResponseExamples tests the bodies documented for 4xx/5xx responses
+func TestErrorResponseExamples(t *testing.T) {
+	type ProblemDetails struct {
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+	}
+
+	tests := []struct {
+		name        string
+		errorSchema interface{}
+		status      string
+		field       string
+		expected    interface{}
+	}{
+		{name: "Default not found", status: "404", field: "error", expected: "Not Found"},
+		{name: "Default server error", status: "500", field: "error", expected: "Internal Server Error"},
+		{name: "Default validation error", status: "400", field: "error", expected: "Request body validation failed"},
+		{name: "Custom schema", errorSchema: ProblemDetails{}, status: "409", field: "title", expected: "Sample Title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", ErrorSchema: tt.errorSchema}, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:          "GET",
+				Path:            "/users/:id",
+				Handler:         func(c fiber.Ctx) error { return nil },
+				Responses:       map[string]string{"200": "Success", tt.status: "Failure"},
+				SchemasResponse: TestUser{},
+			})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			spec := api.GenerateOpenAPISpec()
+			responses := spec.Paths["/users/{id}"].Get.Responses
+			media, ok := responses[tt.status].Content["application/json"]
+			if !ok {
+				t.Fatalf("Expected a JSON body on the %s response", tt.status)
+			}
+
+			data, _ := json.Marshal(media.Example)
+			var example map[string]interface{}
+			if err := json.Unmarshal(data, &example); err != nil {
+				t.Fatalf("Expected an object example, got %s", data)
+			}
+			if example[tt.field] != tt.expected {
+				t.Errorf("Expected %s %v, got %v", tt.field, tt.expected, example[tt.field])
+			}
+			schema := media.Schema
+			if schema.Ref != "" {
+				schema = spec.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+			}
+			if _, ok := schema.Properties[tt.field]; !ok {
+				t.Errorf("Expected the schema to document %s", tt.field)
+			}
+
+			if responses["200"].Content["application/json"].Example == nil {
+				t.Error("Expected the success example to be kept")
+			}
+		})
+	}
+}
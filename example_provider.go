@@ -0,0 +1,82 @@
+package notelink
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// ExampleField describes a struct field an example value is generated for
+type ExampleField struct {
+	Type     reflect.Type
+	Tag      reflect.StructTag
+	Name     string // Go field name
+	JSONName string // Name in the JSON payload
+}
+
+// ExampleProvider supplies example values for struct fields in generated examples
+// and try-it templates, replacing the built-in field-name heuristics. Returning
+// false falls back to the heuristics for that field.
+//
+// Fields with an `example:"..."` or `enum:"..."` tag always use the tag, and
+// sensitive fields are always masked.
+type ExampleProvider interface {
+	Example(field ExampleField) (interface{}, bool)
+}
+
+// ExampleProviderFunc adapts a function to the ExampleProvider interface
+type ExampleProviderFunc func(field ExampleField) (interface{}, bool)
+
+// Example implements ExampleProvider
+func (f ExampleProviderFunc) Example(field ExampleField) (interface{}, bool) {
+	return f(field)
+}
+
+// ExampleValues is an ExampleProvider with fixed values by JSON field name,
+// e.g. ExampleValues{"email": "jane@acme.test", "currency": "EUR"}
+type ExampleValues map[string]interface{}
+
+// Example implements ExampleProvider
+func (v ExampleValues) Example(field ExampleField) (interface{}, bool) {
+	value, ok := v[field.JSONName]
+	return value, ok
+}
+
+// tagExample returns the value of a field's `example:"..."` tag converted to the
+// field's type: numbers and booleans are parsed, slices, maps and structs are
+// decoded as JSON, and anything that doesn't parse is used as a string.
+func tagExample(field *reflect.StructField) (interface{}, bool) {
+	tag, ok := field.Tag.Lookup("example")
+	if !ok {
+		return nil, false
+	}
+
+	t := field.Type
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(tag, 10, 64); err == nil {
+			return n, true
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(tag, 10, 64); err == nil {
+			return n, true
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(tag, 64); err == nil {
+			return f, true
+		}
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(tag); err == nil {
+			return b, true
+		}
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		var value interface{}
+		if err := json.Unmarshal([]byte(tag), &value); err == nil {
+			return value, true
+		}
+	}
+	return tag, true
+}
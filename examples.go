@@ -0,0 +1,72 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// RequestExample is a named request body example loaded from a fixture file
+type RequestExample struct {
+	Value interface{}
+	Name  string
+}
+
+// ExampleObject represents an OpenAPI example object
+type ExampleObject struct {
+	Value   interface{} `json:"value"`
+	Summary string      `json:"summary,omitempty"`
+}
+
+// loadExampleFiles reads JSON fixture files from fsys (or the working directory
+// when fsys is nil) and returns them as named examples. The example name is the
+// file name without its extension.
+func loadExampleFiles(fsys fs.FS, paths []string) ([]RequestExample, error) {
+	examples := make([]RequestExample, 0, len(paths))
+	for _, p := range paths {
+		var data []byte
+		var err error
+		if fsys != nil {
+			data, err = fs.ReadFile(fsys, p)
+		} else {
+			data, err = os.ReadFile(p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read example file %s: %w", p, err)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON in example file %s: %w", p, err)
+		}
+
+		name := path.Base(p)
+		name = strings.TrimSuffix(name, path.Ext(name))
+		examples = append(examples, RequestExample{Name: name, Value: value})
+	}
+	return examples, nil
+}
+
+// examplesToOpenAPI converts named examples to OpenAPI example objects
+func examplesToOpenAPI(examples []RequestExample) map[string]ExampleObject {
+	if len(examples) == 0 {
+		return nil
+	}
+	result := make(map[string]ExampleObject, len(examples))
+	for _, example := range examples {
+		result[example.Name] = ExampleObject{Summary: example.Name, Value: example.Value}
+	}
+	return result
+}
+
+// exampleJSON returns the indented JSON for an example value
+func exampleJSON(value interface{}) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
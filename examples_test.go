@@ -0,0 +1,153 @@
+package notelink
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestExampleFiles tests loading request body examples from fixture files
+func TestExampleFiles(t *testing.T) {
+	fixtures := fstest.MapFS{
+		"testdata/create_user.json": {Data: []byte(`{"name":"Jane","email":"jane@example.com","age":31}`)},
+		"testdata/minimal.json":     {Data: []byte(`{"name":"Min","email":"min@example.com","age":18}`)},
+		"testdata/broken.json":      {Data: []byte(`{"name":`)},
+	}
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", ExampleFS: fixtures}, "secret")
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/users",
+		Description:    "Create a user",
+		Handler:        func(c fiber.Ctx) error { return c.SendStatus(201) },
+		SchemasRequest: TestUser{},
+		ExampleFiles:   []string{"testdata/create_user.json", "testdata/minimal.json"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	media := spec.Paths["/users"].Post.RequestBody.Content["application/json"]
+	if media.Example != nil {
+		t.Error("Expected generated example to be replaced by fixture examples")
+	}
+	if _, ok := media.Examples["create_user"]; !ok {
+		t.Errorf("Expected create_user example, got %v", media.Examples)
+	}
+	if len(media.Examples) != 2 {
+		t.Errorf("Expected 2 examples, got %d", len(media.Examples))
+	}
+
+	// Fixture examples document routes without a request schema too
+	err = api.DocumentedRoute(&DocumentedRouteInput{
+		Method:       "POST",
+		Path:         "/imports",
+		Handler:      func(c fiber.Ctx) error { return c.SendStatus(202) },
+		ExampleFiles: []string{"testdata/minimal.json"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	body := api.GenerateOpenAPISpec().Paths["/imports"].Post.RequestBody
+	if body == nil {
+		t.Fatal("Expected a request body documented by the fixture example")
+	}
+	if _, ok := body.Content["application/json"].Examples["minimal"]; !ok || body.Content["application/json"].Schema != nil {
+		t.Errorf("Expected the fixture example without a request schema, got %+v", body)
+	}
+
+	html := api.generateHTML()
+	if !strings.Contains(html, `onchange="loadExample(this)"`) || !strings.Contains(html, ">minimal</option>") {
+		t.Error("Expected example picker in the try-it editor")
+	}
+
+	tests := []struct {
+		name  string
+		files []string
+	}{
+		{name: "Missing file", files: []string{"testdata/missing.json"}},
+		{name: "Invalid JSON", files: []string{"testdata/broken.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:       "PUT",
+				Path:         "/users/:id",
+				Handler:      func(c fiber.Ctx) error { return nil },
+				ExampleFiles: tt.files,
+			})
+			if err == nil {
+				t.Error("Expected registration error")
+			}
+		})
+	}
+}
+
+// TestExampleProvider tests example tags and pluggable example providers
+func TestExampleProvider(t *testing.T) {
+	type Order struct {
+		ID       int      `json:"id"`
+		Currency string   `json:"currency" enum:"USD,EUR"`
+		Customer string   `json:"customer" example:"Acme GmbH"`
+		Quantity int      `json:"quantity" example:"3"`
+		Express  bool     `json:"express" example:"true"`
+		Notes    []string `json:"notes" example:"[\"fragile\"]"`
+		Email    string   `json:"email"`
+	}
+
+	provider := ExampleProviderFunc(func(field ExampleField) (interface{}, bool) {
+		switch field.JSONName {
+		case "email":
+			return "orders@acme.test", true
+		case "currency", "customer":
+			return "ignored", true
+		}
+		return nil, false
+	})
+
+	tests := []struct {
+		name     string
+		provider ExampleProvider
+		field    string
+		expected interface{}
+	}{
+		{name: "Example tag string", field: "customer", expected: "Acme GmbH"},
+		{name: "Example tag int", field: "quantity", expected: float64(3)},
+		{name: "Example tag bool", field: "express", expected: true},
+		{name: "Heuristic fallback", field: "email", expected: "user@example.com"},
+		{name: "Provider", provider: provider, field: "email", expected: "orders@acme.test"},
+		{name: "Provider falls back", provider: provider, field: "id", expected: float64(12345)},
+		{name: "Tag wins over provider", provider: provider, field: "customer", expected: "Acme GmbH"},
+		{name: "Enum wins over provider", provider: provider, field: "currency", expected: "USD"},
+		{name: "Fixed values", provider: ExampleValues{"id": 42}, field: "id", expected: float64(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := generateJSONTemplate(Order{}, exampleOptions{Provider: tt.provider})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var example map[string]interface{}
+			if err := json.Unmarshal([]byte(result), &example); err != nil {
+				t.Fatalf("Template is not valid JSON: %v", err)
+			}
+			if example[tt.field] != tt.expected {
+				t.Errorf("Expected %s to be %v, got %v", tt.field, tt.expected, example[tt.field])
+			}
+			if notes, ok := example["notes"].([]interface{}); !ok || len(notes) != 1 || notes[0] != "fragile" {
+				t.Errorf("Expected notes from the JSON example tag, got %v", example["notes"])
+			}
+		})
+	}
+
+	first, _ := generateJSONTemplate(UserWithTimeFields{}, exampleOptions{})
+	second, _ := generateJSONTemplate(UserWithTimeFields{}, exampleOptions{})
+	if first != second {
+		t.Error("Expected generated examples to be deterministic")
+	}
+}
@@ -0,0 +1,107 @@
+package notelink
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// File is an artifact rendered by an Exporter, e.g. "sdk/client.proto"
+type File struct {
+	// Name is the slash-separated path of the file in the snapshot and the
+	// artifact store, without leading slash or dot segments
+	Name string
+	// ContentType defaults to the type of the file's extension, else
+	// application/octet-stream
+	ContentType string
+	Data        []byte
+}
+
+// Exporter generates custom artifacts from the documented routes, e.g. an
+// internal SDK format. Registered exporters run alongside the built-in
+// exports whenever the docs are snapshotted or published (see
+// RegisterExporter).
+type Exporter interface {
+	// Name identifies the exporter in errors, e.g. "internal-sdk"
+	Name() string
+	// Generate renders the exporter's files from the registered routes, read
+	// with methods such as GenerateOpenAPISpec and Endpoints. It must not call
+	// Snapshot, WriteSnapshot or PublishArtifacts, which run it.
+	Generate(registry *ApiNote) ([]File, error)
+}
+
+// ExporterFunc adapts a function to an Exporter named name
+func ExporterFunc(name string, generate func(registry *ApiNote) ([]File, error)) Exporter {
+	return exporterFunc{name: name, generate: generate}
+}
+
+type exporterFunc struct {
+	name     string
+	generate func(registry *ApiNote) ([]File, error)
+}
+
+func (e exporterFunc) Name() string { return e.name }
+
+func (e exporterFunc) Generate(registry *ApiNote) ([]File, error) { return e.generate(registry) }
+
+// RegisterExporter adds a custom artifact generator: its files are written by
+// WriteSnapshot, returned by Snapshot and uploaded by PublishArtifacts next to
+// the specs and docs pages. Exporters run in registration order, and their
+// files must not replace a built-in artifact or another exporter's file.
+func (an *ApiNote) RegisterExporter(exporter Exporter) error {
+	if exporter == nil {
+		return fmt.Errorf("exporter is required")
+	}
+	name := exporter.Name()
+	if name == "" {
+		return fmt.Errorf("exporter name is required")
+	}
+	for _, existing := range an.exporters {
+		if existing.Name() == name {
+			return fmt.Errorf("exporter %s is already registered", name)
+		}
+	}
+	an.exporters = append(an.exporters, exporter)
+	return nil
+}
+
+// exportFiles runs the registered exporters, adding their files to those
+// of the snapshot
+func (an *ApiNote) exportFiles(files []File) ([]File, error) {
+	owners := make(map[string]string, len(files))
+	for _, file := range files {
+		owners[file.Name] = "the built-in exports"
+	}
+	for _, exporter := range an.exporters {
+		generated, err := exporter.Generate(an)
+		if err != nil {
+			return nil, fmt.Errorf("exporter %s: %w", exporter.Name(), err)
+		}
+		for _, file := range generated {
+			if !fs.ValidPath(file.Name) || file.Name == "." {
+				return nil, fmt.Errorf("exporter %s: invalid file name %q", exporter.Name(), file.Name)
+			}
+			if owner, ok := owners[file.Name]; ok {
+				return nil, fmt.Errorf("exporter %s: %s is already generated by %s", exporter.Name(), file.Name, owner)
+			}
+			owners[file.Name] = "exporter " + exporter.Name()
+			if file.ContentType == "" {
+				file.ContentType = fileContentType(file.Name)
+			}
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// fileContentType returns the content type of a snapshot file by extension
+func fileContentType(name string) string {
+	if contentType := snapshotContentTypes[path.Ext(name)]; contentType != "" {
+		return contentType
+	}
+	return fiber.MIMEOctetStream
+}
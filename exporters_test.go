@@ -0,0 +1,103 @@
+package notelink
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestExporters tests running custom artifact generators with the built-in exports
+func TestExporters(t *testing.T) {
+	newAPI := func(t *testing.T) *ApiNote {
+		api := NewApiNote(&Config{Title: "Test API", Host: "api.example.com"}, "secret")
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/orders", Description: "List orders", Handler: func(c fiber.Ctx) error { return nil }}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+		return api
+	}
+	sdk := ExporterFunc("internal-sdk", func(registry *ApiNote) ([]File, error) {
+		var operations []string
+		for _, endpoint := range registry.Endpoints() {
+			operations = append(operations, endpoint.Method+" "+endpoint.Path)
+		}
+		return []File{
+			{Name: "sdk/operations.txt", ContentType: "text/plain", Data: []byte(strings.Join(operations, "\n"))},
+			{Name: "sdk/manifest.json", Data: []byte(`{"title":"` + registry.GenerateOpenAPISpec().Info.Title + `"}`)},
+		}, nil
+	})
+
+	t.Run("snapshot and publish", func(t *testing.T) {
+		api := newAPI(t)
+		if err := api.RegisterExporter(sdk); err != nil {
+			t.Fatalf("Failed to register exporter: %v", err)
+		}
+
+		dir := t.TempDir()
+		if err := api.WriteSnapshot(dir); err != nil {
+			t.Fatalf("Failed to write snapshot: %v", err)
+		}
+		for name, expected := range map[string]string{"sdk/operations.txt": "GET /orders", "sdk/manifest.json": `{"title":"Test API"}`} {
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+			if err != nil || string(data) != expected {
+				t.Errorf("Expected %s to contain %q, got %q (%v)", name, expected, data, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "openapi.json")); err != nil {
+			t.Errorf("Expected the built-in exports next to the custom ones: %v", err)
+		}
+
+		uploads := map[string]string{}
+		store := ArtifactStoreFunc(func(_ context.Context, name, contentType string, _ []byte) error {
+			uploads[name] = contentType
+			return nil
+		})
+		if err := api.PublishArtifacts(context.Background(), store); err != nil {
+			t.Fatalf("Failed to publish: %v", err)
+		}
+		if uploads["sdk/operations.txt"] != "text/plain" || uploads["sdk/manifest.json"] != fiber.MIMEApplicationJSON || uploads["openapi.yaml"] != "application/yaml" {
+			t.Errorf("Expected the custom and extension content types, got %v", uploads)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		exporter Exporter
+		err      string
+	}{
+		{name: "failing", exporter: ExporterFunc("broken", func(*ApiNote) ([]File, error) { return nil, errors.New("no templates") }), err: "exporter broken: no templates"},
+		{name: "built-in file", exporter: ExporterFunc("spec", func(*ApiNote) ([]File, error) { return []File{{Name: "openapi.json"}}, nil }), err: "openapi.json is already generated by the built-in exports"},
+		{name: "escaping path", exporter: ExporterFunc("escape", func(*ApiNote) ([]File, error) { return []File{{Name: "../sdk.zip"}}, nil }), err: `invalid file name "../sdk.zip"`},
+		{name: "absolute path", exporter: ExporterFunc("absolute", func(*ApiNote) ([]File, error) { return []File{{Name: "/sdk.zip"}}, nil }), err: `invalid file name "/sdk.zip"`},
+		{name: "other exporter's file", exporter: ExporterFunc("copy", func(*ApiNote) ([]File, error) { return []File{{Name: "sdk/manifest.json"}}, nil }), err: "sdk/manifest.json is already generated by exporter internal-sdk"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newAPI(t)
+			if err := api.RegisterExporter(sdk); err != nil {
+				t.Fatalf("Failed to register exporter: %v", err)
+			}
+			if err := api.RegisterExporter(tt.exporter); err != nil {
+				t.Fatalf("Failed to register exporter: %v", err)
+			}
+			if _, err := api.Snapshot(); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+
+	api := newAPI(t)
+	if err := api.RegisterExporter(sdk); err != nil {
+		t.Fatalf("Failed to register exporter: %v", err)
+	}
+	if err := api.RegisterExporter(sdk); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("Expected duplicate exporters to be rejected, got %v", err)
+	}
+	if err := api.RegisterExporter(ExporterFunc("", nil)); err == nil {
+		t.Error("Expected exporters without a name to be rejected")
+	}
+}
@@ -0,0 +1,252 @@
+package notelink
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// marshalYAML renders a value as YAML. Its JSON encoding is parsed as YAML so
+// keys keep their JSON order and extensions are included, then switched to
+// block style.
+func marshalYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	blockStyle(&document)
+	return yaml.Marshal(&document)
+}
+
+// blockStyle clears the flow style the JSON syntax left on collections and
+// the quoting of strings that don't need it
+func blockStyle(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if node.Style == yaml.DoubleQuotedStyle {
+			node.Style = 0
+		}
+	} else {
+		node.Style &^= yaml.FlowStyle
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// sortedEndpoints returns the registered endpoints in docs order
+func (an *ApiNote) sortedEndpoints() []Endpoint {
+	endpoints := make([]Endpoint, 0, len(an.endpoints))
+	for _, endpoint := range an.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	an.sortEndpoints(endpoints)
+	return endpoints
+}
+
+// Postman collection v2.1 structures
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanFolder   `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanFolder struct {
+	Name string        `json:"name"`
+	Item []postmanItem `json:"item"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method string          `json:"method"`
+	Header []postmanHeader `json:"header"`
+	URL    postmanURL      `json:"url"`
+	Body   *postmanBody    `json:"body,omitempty"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanVariable `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type postmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// generatePostmanCollection exports the endpoints as a Postman v2.1 collection
+// with a folder per tag. Requests use a {{baseUrl}} variable set to baseURL
+// and the base path, and JSON bodies are filled with generated examples.
+func (an *ApiNote) generatePostmanCollection(baseURL string) postmanCollection {
+	collection := postmanCollection{
+		Info: postmanInfo{
+			Name:        an.config.Title,
+			Description: an.config.Description,
+			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		Item:     []postmanFolder{},
+		Variable: []postmanVariable{{Key: "baseUrl", Value: baseURL + an.config.BasePath}},
+	}
+
+	folders := make(map[string]int)
+	for _, endpoint := range an.sortedEndpoints() {
+		folder := "Other"
+		if tags := endpointTags(&endpoint); len(tags) > 0 {
+			folder = tags[0]
+		}
+		index, ok := folders[folder]
+		if !ok {
+			index = len(collection.Item)
+			folders[folder] = index
+			collection.Item = append(collection.Item, postmanFolder{Name: folder})
+		}
+		collection.Item[index].Item = append(collection.Item[index].Item, an.postmanItem(&endpoint))
+	}
+	return collection
+}
+
+// postmanItem converts an endpoint to a Postman request
+func (an *ApiNote) postmanItem(endpoint *Endpoint) postmanItem {
+	path := strings.Trim(endpoint.routePath(), "/")
+	request := postmanRequest{
+		Method: strings.ToUpper(endpoint.Method),
+		Header: []postmanHeader{},
+		URL: postmanURL{
+			Raw:  "{{baseUrl}}/" + path,
+			Host: []string{"{{baseUrl}}"},
+			Path: strings.Split(path, "/"),
+		},
+	}
+
+	for _, param := range endpoint.Parameters {
+		switch param.In {
+		case "path":
+			request.URL.Variable = append(request.URL.Variable, postmanVariable{Key: param.Name, Description: param.Description})
+		case "query":
+			request.URL.Query = append(request.URL.Query, postmanVariable{Key: param.Name, Description: param.Description, Disabled: !param.Required})
+		case "header":
+			request.Header = append(request.Header, postmanHeader{Key: param.Name})
+		}
+	}
+	if endpoint.AuthRequired {
+		request.Header = append(request.Header, postmanHeader{Key: "Authorization", Value: "Bearer {{token}}"})
+	}
+
+	if endpoint.RequestSchema != nil {
+		var raw string
+		if len(endpoint.RequestExamples) > 0 {
+			raw = exampleJSON(endpoint.RequestExamples[0].Value)
+		} else if template, err := generateJSONTemplate(endpoint.RequestSchema, an.exampleOptions()); err == nil {
+			raw = template
+		}
+		request.Header = append(request.Header, postmanHeader{Key: "Content-Type", Value: "application/json"})
+		request.Body = &postmanBody{
+			Mode:    "raw",
+			Raw:     raw,
+			Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+		}
+	}
+
+	name := endpoint.Description
+	if name == "" {
+		name = endpoint.Method + " " + endpoint.routePath()
+	}
+	return postmanItem{Name: name, Request: request}
+}
+
+// generateTypeScriptTypes bundles the TypeScript interfaces of every request
+// and response body in one module. Each struct is declared once, and each
+// operation gets <OperationId>Request/Response aliases for its bodies.
+func (an *ApiNote) generateTypeScriptTypes() string {
+	var ts strings.Builder
+	ts.WriteString("// Types for " + an.config.Title + ", generated by notelink\n\n")
+
+	names := an.schemaNames()
+	seenTypes := make(map[string]bool)
+	for _, endpoint := range an.sortedEndpoints() {
+		operation := toTitle(generateOperationID(endpoint.Method, endpoint.routePath()))
+		an.writeTypeScriptBody(&ts, operation+"Request", bodyDescription("Request", &endpoint), endpoint.RequestSchema, seenTypes, names)
+		an.writeTypeScriptBody(&ts, operation+"Response", bodyDescription("Response", &endpoint), endpoint.ResponseSchema, seenTypes, names)
+	}
+	return ts.String()
+}
+
+// bodyDescription documents an operation's request or response body type
+func bodyDescription(kind string, endpoint *Endpoint) string {
+	description := kind + " body of " + endpoint.Method + " " + endpoint.routePath()
+	if endpoint.Description != "" {
+		description += ": " + endpoint.Description
+	}
+	return description
+}
+
+// writeTypeScriptBody declares the structs a body uses that aren't declared yet,
+// then the operation's alias or, for anonymous structs, interface
+func (an *ApiNote) writeTypeScriptBody(ts *strings.Builder, name, description string, body interface{}, seenTypes map[string]bool, names *schemaNames) {
+	typ := reflect.TypeOf(body)
+	if typ == nil {
+		return
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	suffix := ""
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+		suffix = "[]"
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	generateAllStructs(typ, ts, seenTypes, an.config.TimeFormat, names)
+	if typ.Name() == "" {
+		ts.WriteString(tsInterface(name, description, typ, an.config.TimeFormat, names) + "\n")
+		if suffix != "" {
+			ts.WriteString("export type " + name + "List = " + name + "[];\n")
+		}
+		ts.WriteString("\n")
+		return
+	}
+
+	typeName := names.name(typ)
+	if !seenTypes[typeName] {
+		seenTypes[typeName] = true
+		ts.WriteString(tsInterface(typeName, typeDescription(typ), typ, an.config.TimeFormat, names) + "\n\n")
+	}
+	ts.WriteString(jsDoc("", []string{description}) + "export type " + name + " = " + typeName + suffix + ";\n\n")
+}
@@ -0,0 +1,116 @@
+package notelink
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type exportAddress struct {
+	City string `json:"city"`
+}
+
+type exportUser struct {
+	ID      int           `json:"id"`
+	Name    string        `json:"name"`
+	Address exportAddress `json:"address"`
+}
+
+// TestExports tests the YAML spec, Postman collection, TypeScript types and landing page
+func TestExports(t *testing.T) {
+	get := func(api *ApiNote, path string) (int, string) {
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/v1"}, "secret")
+	handler := func(c fiber.Ctx) error { return nil }
+	api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "GET",
+		Path:            "/users/:id",
+		Description:     "Get user",
+		Handler:         handler,
+		Params:          []Parameter{{Name: "id", In: "path", Type: "integer", Required: true}},
+		SchemasResponse: exportUser{},
+	})
+	api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "POST",
+		Path:            "/users",
+		Description:     "Create user",
+		Handler:         handler,
+		SchemasRequest:  exportUser{},
+		SchemasResponse: []exportUser{},
+	})
+
+	tests := []struct {
+		path     string
+		snippets []string
+	}{
+		{path: "/api-docs/openapi.yaml", snippets: []string{"openapi: 3.1.0", "/users/{id}:", "operationId: getUsersById"}},
+		{path: "/api-docs/types.ts", snippets: []string{
+			"export interface exportAddress {",
+			"export interface exportUser {",
+			"export type GetUsersByIdResponse = exportUser;",
+			"export type PostUsersRequest = exportUser;",
+			"export type PostUsersResponse = exportUser[];",
+		}},
+		{path: "/api-docs/index", snippets: []string{
+			`<a href="/api-docs">Scalar</a>`,
+			`<a href="/api-docs/openapi.yaml">`,
+			`<a href="/api-docs/postman.json">`,
+			`<a href="/api-docs/types.ts">`,
+			`<a href="/api-docs/metrics">`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			status, body := get(api, tt.path)
+			if status != 200 {
+				t.Fatalf("Expected status 200, got %d", status)
+			}
+			for _, snippet := range tt.snippets {
+				if !strings.Contains(body, snippet) {
+					t.Errorf("Expected %s to contain %q, got:\n%s", tt.path, snippet, body)
+				}
+			}
+		})
+	}
+
+	_, types := get(api, "/api-docs/types.ts")
+	if strings.Count(types, "export interface exportUser {") != 1 {
+		t.Error("Expected shared structs to be declared once")
+	}
+
+	_, body := get(api, "/api-docs/postman.json")
+	var collection postmanCollection
+	if err := json.Unmarshal([]byte(body), &collection); err != nil {
+		t.Fatalf("Failed to parse Postman collection: %v", err)
+	}
+	if collection.Variable[0].Value != "http://localhost:8080/v1" {
+		t.Errorf("Expected baseUrl variable to include the base path, got %q", collection.Variable[0].Value)
+	}
+	if len(collection.Item) != 1 || len(collection.Item[0].Item) != 2 {
+		t.Fatalf("Expected one folder with two requests, got %+v", collection.Item)
+	}
+	for _, item := range collection.Item[0].Item {
+		switch item.Request.Method {
+		case "GET":
+			if item.Request.URL.Raw != "{{baseUrl}}/users/:id" || len(item.Request.URL.Variable) != 1 {
+				t.Errorf("Expected a path variable for the user id, got %+v", item.Request.URL)
+			}
+		case "POST":
+			if item.Request.Body == nil || !strings.Contains(item.Request.Body.Raw, `"city"`) {
+				t.Errorf("Expected an example JSON body, got %+v", item.Request.Body)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// extensionKey prefixes a specification extension name with "x-" when missing
+func extensionKey(name string) string {
+	if strings.HasPrefix(strings.ToLower(name), "x-") {
+		return name
+	}
+	return "x-" + name
+}
+
+// copyExtensions copies an extensions map, normalizing its keys
+func copyExtensions(extensions map[string]interface{}) map[string]interface{} {
+	if len(extensions) == 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(extensions))
+	for name, value := range extensions {
+		result[extensionKey(name)] = value
+	}
+	return result
+}
+
+// fieldExtensions parses the `extensions` tag of a struct field: semicolon
+// separated name=value pairs such as
+//
+//	`extensions:"x-order=1;x-kong-tags=[\"public\"];x-internal"`
+//
+// Values that are valid JSON keep their type, other values are strings, and a
+// name without a value is true.
+func fieldExtensions(field *reflect.StructField) map[string]interface{} {
+	tag := field.Tag.Get("extensions")
+	if tag == "" {
+		return nil
+	}
+
+	extensions := make(map[string]interface{})
+	for _, entry := range strings.Split(tag, ";") {
+		name, raw, hasValue := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !hasValue {
+			extensions[extensionKey(name)] = true
+			continue
+		}
+		raw = strings.TrimSpace(raw)
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		extensions[extensionKey(name)] = value
+	}
+	return extensions
+}
+
+// appendExtensions adds extensions as properties of the JSON object in data.
+// Properties notelink already emits, such as x-owner, take precedence.
+func appendExtensions(data []byte, extensions map[string]interface{}) ([]byte, error) {
+	if len(extensions) == 0 {
+		return data, nil
+	}
+	var existing map[string]json.RawMessage
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		if _, taken := existing[name]; !taken {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var result bytes.Buffer
+	result.Write(bytes.TrimSuffix(bytes.TrimSpace(data), []byte("}")))
+	for i, name := range names {
+		value, err := json.Marshal(extensions[name])
+		if err != nil {
+			return nil, err
+		}
+		key, _ := json.Marshal(name)
+		if i > 0 || len(existing) > 0 {
+			result.WriteByte(',')
+		}
+		result.Write(key)
+		result.WriteByte(':')
+		result.Write(value)
+	}
+	result.WriteByte('}')
+	return result.Bytes(), nil
+}
+
+// MarshalJSON serializes the spec with its root-level extensions
+func (s OpenAPISpec) MarshalJSON() ([]byte, error) {
+	type plain OpenAPISpec
+	data, err := json.Marshal(plain(s))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(data, s.Extensions)
+}
+
+// MarshalJSON serializes the operation with its extensions
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type plain Operation
+	data, err := json.Marshal(plain(o))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(data, o.Extensions)
+}
+
+// MarshalJSON serializes the schema with its extensions
+func (s JSONSchema) MarshalJSON() ([]byte, error) {
+	type plain JSONSchema
+	data, err := json.Marshal(plain(s))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtensions(data, s.Extensions)
+}
@@ -0,0 +1,121 @@
+package notelink
+
+// favoriteToggleMarkup is the star button rendered in every endpoint summary
+const favoriteToggleMarkup = `<button type="button" class="favorite-toggle" onclick="toggleFavorite(event, this)" title="Add to favorites" aria-label="Add to favorites" aria-pressed="false"><i class="far fa-star"></i></button>`
+
+// quickAccessMarkup holds the favorites and recently viewed lists shown above the docs tree
+const quickAccessMarkup = `
+        <div class="quick-access" id="quick-access" hidden>
+            <div class="quick-access-list" id="favorites-block" hidden>
+                <h3><i class="fas fa-star"></i> Favorites</h3>
+                <ul id="favorites-list"></ul>
+            </div>
+            <div class="quick-access-list" id="recent-block" hidden>
+                <h3><i class="fas fa-clock-rotate-left"></i> Recently viewed <button type="button" class="quick-access-clear" onclick="clearRecentlyViewed()">Clear</button></h3>
+                <ul id="recent-list"></ul>
+            </div>
+        </div>`
+
+// quickAccessScript persists favorite and recently viewed endpoints in
+// localStorage and renders the quick access lists from them
+const quickAccessScript = `
+            // Favorites and recently viewed endpoints, persisted in localStorage
+            const favoritesKey = 'notelink.favorites';
+            const recentKey = 'notelink.recent';
+            const recentLimit = 8;
+
+            function loadStoredIds(key) {
+                try {
+                    const ids = JSON.parse(localStorage.getItem(key) || '[]');
+                    return Array.isArray(ids) ? ids.filter(id => typeof id === 'string') : [];
+                } catch (e) {
+                    return [];
+                }
+            }
+
+            function storeIds(key, ids) {
+                try {
+                    localStorage.setItem(key, JSON.stringify(ids));
+                } catch (e) {
+                    // Storage may be full or disabled; the lists just won't persist
+                }
+            }
+
+            function toggleFavorite(event, button) {
+                event.preventDefault();
+                event.stopPropagation();
+                const id = button.closest('details.method-group').id;
+                const favorites = loadStoredIds(favoritesKey);
+                const index = favorites.indexOf(id);
+                if (index === -1) {
+                    favorites.push(id);
+                } else {
+                    favorites.splice(index, 1);
+                }
+                storeIds(favoritesKey, favorites);
+                renderQuickAccess();
+            }
+
+            function recordRecentlyViewed(id) {
+                const recent = loadStoredIds(recentKey).filter(existing => existing !== id);
+                recent.unshift(id);
+                storeIds(recentKey, recent.slice(0, recentLimit));
+                renderQuickAccess();
+            }
+
+            function clearRecentlyViewed() {
+                storeIds(recentKey, []);
+                renderQuickAccess();
+            }
+
+            // Render a list of endpoint ids, skipping endpoints that no longer exist
+            function renderQuickAccessList(listId, blockId, ids) {
+                const list = document.getElementById(listId);
+                list.innerHTML = '';
+                ids.forEach(id => {
+                    const element = document.getElementById(id);
+                    if (!element || !element.classList.contains('method-group')) {
+                        return;
+                    }
+                    const method = element.querySelector(':scope > summary .method').textContent.trim();
+                    const path = element.querySelector(':scope > summary .endpoint-path').textContent.trim();
+                    const item = document.createElement('li');
+                    item.innerHTML = '<a href="#' + escapeHtml(id) + '"><span class="toc-method ' + escapeHtml(method) + '">' + escapeHtml(method) + '</span>' + escapeHtml(path) + '</a>';
+                    item.querySelector('a').addEventListener('click', function(e) {
+                        e.preventDefault();
+                        openOperation(id);
+                        history.replaceState(null, '', '#' + id);
+                    });
+                    list.appendChild(item);
+                });
+                document.getElementById(blockId).hidden = list.children.length === 0;
+                return list.children.length > 0;
+            }
+
+            function renderQuickAccess() {
+                const favorites = loadStoredIds(favoritesKey);
+                document.querySelectorAll('details.method-group .favorite-toggle').forEach(button => {
+                    const active = favorites.includes(button.closest('details.method-group').id);
+                    button.classList.toggle('active', active);
+                    button.setAttribute('aria-pressed', String(active));
+                    button.title = active ? 'Remove from favorites' : 'Add to favorites';
+                    button.querySelector('i').className = (active ? 'fas' : 'far') + ' fa-star';
+                });
+
+                const hasFavorites = renderQuickAccessList('favorites-list', 'favorites-block', favorites);
+                const hasRecent = renderQuickAccessList('recent-list', 'recent-block', loadStoredIds(recentKey));
+                document.getElementById('quick-access').hidden = !hasFavorites && !hasRecent;
+            }
+
+            document.addEventListener('DOMContentLoaded', function() {
+                renderQuickAccess();
+
+                // Only count endpoints the user opens, not expand-all toggles
+                document.addEventListener('click', function(e) {
+                    const summary = e.target.closest('details.method-group > summary');
+                    if (summary && !summary.parentElement.open && !e.target.closest('.favorite-toggle')) {
+                        recordRecentlyViewed(summary.parentElement.id);
+                    }
+                });
+            });
+`
@@ -0,0 +1,82 @@
+package notelink
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// featureFlagExtension names the operation extension documenting a route's flag
+const featureFlagExtension = "x-feature-flag"
+
+// FeatureFlagProvider reports whether a feature flag is enabled for a request,
+// e.g. backed by LaunchDarkly, Unleash or an environment variable
+type FeatureFlagProvider interface {
+	Enabled(c fiber.Ctx, flag string) bool
+}
+
+// FeatureFlagFunc adapts a function to a FeatureFlagProvider
+type FeatureFlagFunc func(c fiber.Ctx, flag string) bool
+
+// Enabled calls f(c, flag)
+func (f FeatureFlagFunc) Enabled(c fiber.Ctx, flag string) bool {
+	return f(c, flag)
+}
+
+// FeatureFlagMiddleware rejects requests with status (404 or 501) while the
+// provider reports the flag disabled. Rejections go through the app's
+// ErrorHandler, so they look like any other 404 or 501.
+func FeatureFlagMiddleware(provider FeatureFlagProvider, flag string, status int) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if !provider.Enabled(c, flag) {
+			return fiber.NewError(status)
+		}
+		return c.Next()
+	}
+}
+
+// featureFlagStatus returns Config.FeatureFlagStatus, defaulting to 404
+func (an *ApiNote) featureFlagStatus() int {
+	if an.config.FeatureFlagStatus == fiber.StatusNotImplemented {
+		return fiber.StatusNotImplemented
+	}
+	return fiber.StatusNotFound
+}
+
+// featureFlagged gates a route behind its feature flag: it documents the
+// rejection response and the flag on the operation
+func (an *ApiNote) featureFlagged(endpoint *Endpoint, flag string) error {
+	if an.config.FeatureFlags == nil {
+		return fmt.Errorf("feature flag %q of %s %s requires Config.FeatureFlags", flag, endpoint.Method, endpoint.Path)
+	}
+	endpoint.FeatureFlag = flag
+
+	if endpoint.Responses == nil {
+		endpoint.Responses = make(map[string]string)
+	}
+	if status := strconv.Itoa(an.featureFlagStatus()); endpoint.Responses[status] == "" {
+		endpoint.Responses[status] = fmt.Sprintf("Feature flag %s is disabled", flag)
+	}
+	if endpoint.Extensions == nil {
+		endpoint.Extensions = make(map[string]interface{})
+	}
+	if _, ok := endpoint.Extensions[featureFlagExtension]; !ok {
+		endpoint.Extensions[featureFlagExtension] = flag
+	}
+	return nil
+}
+
+// hiddenByFlag reports whether a flagged endpoint is left out of the docs
+// page and the spec
+func (an *ApiNote) hiddenByFlag(endpoint *Endpoint) bool {
+	return endpoint.FeatureFlag != "" && an.config.HideFlaggedRoutes
+}
+
+// renderFlagBadge renders the "behind flag" badge of a flagged endpoint
+func renderFlagBadge(endpoint *Endpoint) string {
+	if endpoint.FeatureFlag == "" {
+		return ""
+	}
+	return `<span class="flag-badge" title="Behind feature flag ` + escapeHTML(endpoint.FeatureFlag) + `"><i class="fas fa-flag"></i> behind flag</span>`
+}
@@ -0,0 +1,98 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestFeatureFlags tests gating routes behind feature flags
+func TestFeatureFlags(t *testing.T) {
+	enabled := map[string]bool{}
+	flags := FeatureFlagFunc(func(c fiber.Ctx, flag string) bool { return enabled[flag] || c.Get("X-Beta") == "1" })
+
+	tests := []struct {
+		name       string
+		config     Config
+		wantStatus int
+		hidden     bool
+	}{
+		{"badged", Config{FeatureFlags: flags}, fiber.StatusNotFound, false},
+		{"not implemented", Config{FeatureFlags: flags, FeatureFlagStatus: fiber.StatusNotImplemented}, fiber.StatusNotImplemented, false},
+		{"hidden", Config{FeatureFlags: flags, HideFlaggedRoutes: true}, fiber.StatusNotFound, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clear(enabled)
+			config := tt.config
+			config.Title, config.Host = "Test API", "localhost:8080"
+			api := NewApiNote(&config, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:      "GET",
+				Path:        "/v1/reports",
+				FeatureFlag: "reports",
+				Handler:     func(c fiber.Ctx) error { return c.SendString("reports") },
+			})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			send := func(header string) int {
+				t.Helper()
+				req := httptest.NewRequest("GET", "/v1/reports", nil)
+				if header != "" {
+					req.Header.Set("X-Beta", header)
+				}
+				resp, err := api.Fiber().Test(req)
+				if err != nil {
+					t.Fatalf("Failed to send test request: %v", err)
+				}
+				return resp.StatusCode
+			}
+			if status := send(""); status != tt.wantStatus {
+				t.Errorf("Expected status %d while the flag is disabled, got %d", tt.wantStatus, status)
+			}
+			if status := send("1"); status != fiber.StatusOK {
+				t.Errorf("Expected the provider to enable the flag per request, got %d", status)
+			}
+			enabled["reports"] = true
+			if status := send(""); status != fiber.StatusOK {
+				t.Errorf("Expected status 200 once the flag is enabled, got %d", status)
+			}
+
+			path, documented := api.GenerateOpenAPISpec().Paths["/v1/reports"]
+			html := api.generateHTML()
+			if tt.hidden {
+				if documented || strings.Contains(html, `class="flag-badge"`) {
+					t.Error("Expected the flagged route to be hidden from the spec and the docs")
+				}
+				return
+			}
+			if !documented || path.Get.Extensions[featureFlagExtension] != "reports" {
+				t.Fatalf("Expected the operation to document its flag, got %+v", path.Get)
+			}
+			if _, ok := path.Get.Responses[strconv.Itoa(tt.wantStatus)]; !ok {
+				t.Errorf("Expected a documented %d response, got %v", tt.wantStatus, path.Get.Responses)
+			}
+			if !strings.Contains(html, `title="Behind feature flag reports"`) {
+				t.Error("Expected the docs to badge the flagged route")
+			}
+		})
+	}
+
+	// Flagged routes need a provider
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/v1/reports",
+		FeatureFlag: "reports",
+		Handler:     func(c fiber.Ctx) error { return nil },
+	})
+	if err == nil {
+		t.Error("Expected an error for a feature flag without Config.FeatureFlags")
+	}
+}
@@ -0,0 +1,223 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Extensions read by the gateway exports
+const (
+	// ExtensionKongPlugins lists Kong plugin objects, applied to the service when
+	// set in Config.Extensions and to the route when set on a DocumentedRouteInput
+	ExtensionKongPlugins = "x-kong-plugins"
+	// ExtensionAWSIntegration overrides the generated AWS API Gateway integration of a route
+	ExtensionAWSIntegration = "x-amazon-apigateway-integration"
+)
+
+// GatewayOptions configures the Kong, AWS API Gateway and Terraform exports
+type GatewayOptions struct {
+	// UpstreamURL is the scheme and host the gateway forwards requests to, e.g.
+	// "http://users.internal:8080". Defaults to http:// and Config.Host.
+	UpstreamURL string
+	// ServiceName names the Kong service. Defaults to Config.Title in kebab case.
+	ServiceName string
+	// TerraformAPIID is the Terraform expression for the API Gateway HTTP API id.
+	// Defaults to var.api_id.
+	TerraformAPIID string
+	// TerraformAuthorizerID is the Terraform expression for the JWT authorizer
+	// attached to routes that require authentication, e.g.
+	// aws_apigatewayv2_authorizer.jwt.id. Routes are left unauthorized when empty.
+	TerraformAuthorizerID string
+}
+
+// KongConfig is a Kong declarative configuration, as loaded by decK or DB-less Kong
+type KongConfig struct {
+	FormatVersion string        `json:"_format_version"`
+	Services      []KongService `json:"services"`
+}
+
+// KongService is the upstream service the API's routes belong to
+type KongService struct {
+	Name    string      `json:"name"`
+	URL     string      `json:"url"`
+	Routes  []KongRoute `json:"routes"`
+	Plugins interface{} `json:"plugins,omitempty"`
+}
+
+// KongRoute matches one documented endpoint
+type KongRoute struct {
+	Name      string      `json:"name"`
+	Methods   []string    `json:"methods"`
+	Paths     []string    `json:"paths"`
+	StripPath bool        `json:"strip_path"`
+	Plugins   interface{} `json:"plugins,omitempty"`
+}
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// kebabCase turns a title into a lowercase name such as "orders-api"
+func kebabCase(title string) string {
+	return strings.Trim(nonSlug.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+// upstreamURL returns the configured upstream, defaulting to the API's base URL
+func (an *ApiNote) upstreamURL(opts *GatewayOptions) string {
+	if opts.UpstreamURL != "" {
+		return strings.TrimSuffix(opts.UpstreamURL, "/")
+	}
+	return an.baseURL(nil)
+}
+
+// GenerateKongConfig creates a Kong declarative configuration with one route per
+// documented endpoint, matching its method and path exactly. Plugins come from
+// the ExtensionKongPlugins extension of Config and of each route.
+func (an *ApiNote) GenerateKongConfig(opts GatewayOptions) *KongConfig {
+	name := opts.ServiceName
+	if name == "" {
+		name = kebabCase(an.config.Title)
+	}
+	service := KongService{
+		Name:    name,
+		URL:     an.upstreamURL(&opts),
+		Routes:  []KongRoute{},
+		Plugins: copyExtensions(an.config.Extensions)[ExtensionKongPlugins],
+	}
+
+	for _, endpoint := range an.sortedEndpoints() {
+		service.Routes = append(service.Routes, KongRoute{
+			Name:    generateOperationID(endpoint.Method, endpoint.routePath()),
+			Methods: []string{strings.ToUpper(endpoint.Method)},
+			Paths:   []string{kongPath(endpoint.Path)},
+			Plugins: endpoint.Extensions[ExtensionKongPlugins],
+		})
+	}
+
+	return &KongConfig{FormatVersion: "3.0", Services: []KongService{service}}
+}
+
+// kongPath converts a Fiber route pattern to an anchored Kong regex path with
+// a named capture group per parameter
+// Example: /v1/users/:id -> ~/v1/users/(?<id>[^/]+)$
+func kongPath(path string) string {
+	var pattern strings.Builder
+	pattern.WriteString("~")
+	last := 0
+	for _, match := range fiberPathParam.FindAllStringIndex(path, -1) {
+		literal := path[last:match[0]]
+		param := pathParams(path[match[0]:match[1]])[0]
+		group := "(?<" + param.specName() + ">"
+		switch param.Kind {
+		case segmentOptional:
+			if strings.HasSuffix(literal, "/") {
+				pattern.WriteString(regexp.QuoteMeta(strings.TrimSuffix(literal, "/")) + "(?:/" + group + "[^/]+))?")
+			} else {
+				pattern.WriteString(regexp.QuoteMeta(literal) + group + "[^/]*)")
+			}
+		case segmentWildcard:
+			pattern.WriteString(regexp.QuoteMeta(literal) + group + ".*)")
+		case segmentGreedy:
+			pattern.WriteString(regexp.QuoteMeta(literal) + group + ".+)")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(literal) + group + "[^/]+)")
+		}
+		last = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(path[last:]) + "$")
+	return pattern.String()
+}
+
+// ExportKongConfigToFile exports the Kong declarative configuration to a YAML file
+func (an *ApiNote) ExportKongConfigToFile(filepath string, opts GatewayOptions) error {
+	data, err := marshalYAML(an.GenerateKongConfig(opts))
+	if err != nil {
+		return fmt.Errorf("failed to marshal Kong config: %w", err)
+	}
+
+	if err := os.WriteFile(filepath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// GenerateAWSAPIGatewaySpec creates an OpenAPI 3.0 document for importing into
+// AWS API Gateway. Every operation proxies to the upstream with an http_proxy
+// integration unless its route sets ExtensionAWSIntegration, and wildcard
+// segments become greedy {name+} path parameters.
+func (an *ApiNote) GenerateAWSAPIGatewaySpec(opts GatewayOptions) *OpenAPISpec {
+	spec := an.generateOpenAPISpec(an.baseURL(nil), an.docsLocale())
+	spec.OpenAPI = "3.0.1" // The latest version API Gateway imports
+	upstream := an.upstreamURL(&opts)
+
+	for _, endpoint := range an.endpoints {
+		awsKey := awsPath(endpoint.routePath())
+		pathItem, ok := spec.Paths[awsKey]
+		if !ok {
+			specPath := openAPIPath(endpoint.routePath())
+			pathItem = spec.Paths[specPath]
+			delete(spec.Paths, specPath)
+			spec.Paths[awsKey] = pathItem
+		}
+		operation := pathItem.operation(endpoint.Method)
+		if operation == nil {
+			continue
+		}
+
+		extensions := make(map[string]interface{}, len(operation.Extensions)+1)
+		for name, value := range operation.Extensions {
+			extensions[name] = value
+		}
+		if _, ok := extensions[ExtensionAWSIntegration]; !ok {
+			extensions[ExtensionAWSIntegration] = awsIntegration(&endpoint, upstream)
+		}
+		operation.Extensions = extensions
+	}
+	return spec
+}
+
+// awsIntegration returns the http_proxy integration forwarding an endpoint to upstream
+func awsIntegration(endpoint *Endpoint, upstream string) map[string]interface{} {
+	integration := map[string]interface{}{
+		"type":                "http_proxy",
+		"httpMethod":          strings.ToUpper(endpoint.Method),
+		"uri":                 upstream + openAPIPath(endpoint.Path),
+		"passthroughBehavior": "when_no_match",
+	}
+	params := pathParams(endpoint.Path)
+	if len(params) > 0 {
+		requestParameters := make(map[string]string, len(params))
+		for _, param := range params {
+			requestParameters["integration.request.path."+param.specName()] = "method.request.path." + param.specName()
+		}
+		integration["requestParameters"] = requestParameters
+	}
+	return integration
+}
+
+// awsPath converts a Fiber route pattern to an API Gateway path, where
+// wildcard segments are greedy parameters
+// Example: /files/* -> /files/{wildcard+}
+func awsPath(path string) string {
+	return fiberPathParam.ReplaceAllStringFunc(path, func(match string) string {
+		param := pathParams(match)[0]
+		if param.Kind == segmentWildcard || param.Kind == segmentGreedy {
+			return "{" + param.specName() + "+}"
+		}
+		return "{" + param.specName() + "}"
+	})
+}
+
+// ExportAWSAPIGatewayToFile exports the AWS API Gateway import document to a JSON file
+func (an *ApiNote) ExportAWSAPIGatewayToFile(filepath string, opts GatewayOptions) error {
+	data, err := json.MarshalIndent(an.GenerateAWSAPIGatewaySpec(opts), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal API Gateway spec: %w", err)
+	}
+
+	if err := os.WriteFile(filepath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
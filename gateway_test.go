@@ -0,0 +1,115 @@
+package notelink
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestKongPath tests converting route patterns to Kong regex paths
+func TestKongPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		matches  []string
+		rejects  []string
+	}{
+		{path: "/v1/users", expected: `~/v1/users$`, matches: []string{"/v1/users"}, rejects: []string{"/v1/users/1"}},
+		{path: "/v1/users/:id", expected: `~/v1/users/(?<id>[^/]+)$`, matches: []string{"/v1/users/42"}, rejects: []string{"/v1/users", "/v1/users/42/posts"}},
+		{path: "/v1/users/:id?", expected: `~/v1/users(?:/(?<id>[^/]+))?$`, matches: []string{"/v1/users", "/v1/users/42"}},
+		{path: "/files/*", expected: `~/files/(?<wildcard>.*)$`, matches: []string{"/files/", "/files/a/b.txt"}},
+		{path: "/v1.0/+", expected: `~/v1\.0/(?<plus>.+)$`, matches: []string{"/v1.0/a"}, rejects: []string{"/v1.0/", "/v1x0/a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := kongPath(tt.path)
+			if got != tt.expected {
+				t.Fatalf("Expected %q, got %q", tt.expected, got)
+			}
+			// Go spells named groups (?P<name>), PCRE accepts both
+			re := regexp.MustCompile(strings.ReplaceAll(got[1:], "(?<", "(?P<"))
+			for _, path := range tt.matches {
+				if !re.MatchString(path) {
+					t.Errorf("Expected %q to match %s", got, path)
+				}
+			}
+			for _, path := range tt.rejects {
+				if re.MatchString(path) {
+					t.Errorf("Expected %q not to match %s", got, path)
+				}
+			}
+		})
+	}
+}
+
+// TestGatewayExports tests the Kong and AWS API Gateway exports
+func TestGatewayExports(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title:      "Orders API",
+		Host:       "localhost:8080",
+		BasePath:   "/v1",
+		Extensions: map[string]interface{}{"kong-plugins": []map[string]string{{"name": "cors"}}},
+	}, "secret")
+	handler := func(c fiber.Ctx) error { return nil }
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/orders/:id", Handler: handler, Extensions: map[string]interface{}{
+			ExtensionKongPlugins: []map[string]interface{}{{"name": "rate-limiting", "config": map[string]int{"minute": 10}}},
+		}},
+		{Method: "GET", Path: "/files/*", Handler: handler},
+		{Method: "PUT", Path: "/files/*", Handler: handler},
+		{Method: "POST", Path: "/orders", Handler: handler, Extensions: map[string]interface{}{
+			ExtensionAWSIntegration: map[string]string{"type": "mock"},
+		}},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+	opts := GatewayOptions{UpstreamURL: "http://orders.internal:9000/"}
+
+	kong := api.GenerateKongConfig(opts)
+	service := kong.Services[0]
+	if service.Name != "orders-api" || service.URL != "http://orders.internal:9000" || service.Plugins == nil {
+		t.Errorf("Unexpected Kong service: %+v", service)
+	}
+	if len(service.Routes) != 4 {
+		t.Fatalf("Expected 4 Kong routes, got %d", len(service.Routes))
+	}
+	for _, route := range service.Routes {
+		if route.Name == "getOrdersById" {
+			if route.Paths[0] != `~/v1/orders/(?<id>[^/]+)$` || route.Plugins == nil {
+				t.Errorf("Unexpected Kong route: %+v", route)
+			}
+		}
+	}
+
+	spec := api.GenerateAWSAPIGatewaySpec(opts)
+	if spec.OpenAPI != "3.0.1" {
+		t.Errorf("Expected OpenAPI 3.0.1 for API Gateway, got %s", spec.OpenAPI)
+	}
+	get := spec.Paths["/orders/{id}"].Get
+	integration, _ := get.Extensions[ExtensionAWSIntegration].(map[string]interface{})
+	if integration["uri"] != "http://orders.internal:9000/v1/orders/{id}" || integration["type"] != "http_proxy" {
+		t.Errorf("Unexpected integration: %v", integration)
+	}
+	if params := integration["requestParameters"].(map[string]string); params["integration.request.path.id"] != "method.request.path.id" {
+		t.Errorf("Expected the id path parameter to be mapped, got %v", params)
+	}
+	files, ok := spec.Paths["/files/{wildcard+}"]
+	if !ok {
+		t.Fatal("Expected wildcard segments to become greedy path parameters")
+	}
+	if files.Get.Extensions[ExtensionAWSIntegration] == nil || files.Put.Extensions[ExtensionAWSIntegration] == nil {
+		t.Error("Expected every method of a greedy path to get an integration")
+	}
+	if custom := spec.Paths["/orders"].Post.Extensions[ExtensionAWSIntegration]; custom.(map[string]string)["type"] != "mock" {
+		t.Errorf("Expected the route's integration to be kept, got %v", custom)
+	}
+	if _, ok := api.GenerateOpenAPISpec().Paths["/orders/{id}"].Get.Extensions[ExtensionAWSIntegration]; ok {
+		t.Error("Expected the regular spec to be left without integrations")
+	}
+}
@@ -0,0 +1,58 @@
+package notelink
+
+import (
+	"strings"
+	"unicode"
+)
+
+// GroupBy returns the docs tree groups an endpoint is listed under, outermost
+// first. Endpoints with no groups are listed under "Other".
+type GroupBy func(endpoint Endpoint) []string
+
+// GroupByTag groups endpoints by their first tag (DocumentedRouteInput.Tags, or
+// the tag derived from the path when none is declared)
+func GroupByTag(endpoint Endpoint) []string {
+	tags := endpointTags(&endpoint)
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags[:1]
+}
+
+// GroupByFirstSegment groups endpoints by API version, then by the first path
+// segment that is neither "api", the version nor a parameter. For example
+// /v1/:tenant/users is listed under v1 > users.
+func GroupByFirstSegment(endpoint Endpoint) []string {
+	var groups []string
+	if version := getVersion(endpoint.routePath()); version != "unknown" {
+		groups = append(groups, version)
+	}
+	for _, segment := range strings.Split(strings.Trim(endpoint.routePath(), "/"), "/") {
+		if segment == "" || segment == "api" || (len(groups) > 0 && segment == groups[0]) ||
+			isPathParamSegment(segment) || isDynamicSegment(segment) {
+			continue
+		}
+		return append(groups, segment)
+	}
+	return groups
+}
+
+// endpointTags returns the endpoint's declared tags, falling back to the tag
+// derived from its path
+func endpointTags(endpoint *Endpoint) []string {
+	if len(endpoint.Tags) > 0 {
+		return endpoint.Tags
+	}
+	return extractTagsFromPath(endpoint.routePath())
+}
+
+// groupAnchor returns the HTML id of a docs group from its path in the tree
+func groupAnchor(groupPath []string) string {
+	id := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, strings.Join(groupPath, "/"))
+	return "group-" + id
+}
@@ -0,0 +1,63 @@
+package notelink
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestGroupingStrategies tests the built-in GroupBy strategies
+func TestGroupingStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		groupBy  GroupBy
+		endpoint Endpoint
+		expected []string
+	}{
+		{name: "Tag from path", groupBy: GroupByTag, endpoint: Endpoint{Path: "/api/v1/users/:id"}, expected: []string{"users"}},
+		{name: "Declared tag", groupBy: GroupByTag, endpoint: Endpoint{Path: "/api/v1/users", Tags: []string{"Accounts", "Admin"}}, expected: []string{"Accounts"}},
+		{name: "First segment skips tenant param", groupBy: GroupByFirstSegment, endpoint: Endpoint{Path: "/v1/:tenant/users"}, expected: []string{"v1", "users"}},
+		{name: "First segment without version", groupBy: GroupByFirstSegment, endpoint: Endpoint{Path: "/api/health"}, expected: []string{"health"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.groupBy(tt.endpoint); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestCustomGroupBy tests that a custom GroupBy controls the docs tree
+func TestCustomGroupBy(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		GroupBy: func(endpoint Endpoint) []string {
+			if strings.Contains(endpoint.Path, "/admin/") {
+				return []string{"Administration", "Tenants"}
+			}
+			return nil
+		},
+	}, "secret")
+
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+	for _, path := range []string{"/v1/admin/tenants", "/v1/:tenant/users"} {
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: path, Handler: handler}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	html := api.generateHTML()
+	for _, group := range []string{"<summary>Administration<", "<summary>Tenants<", "<summary>Other<"} {
+		if !strings.Contains(html, group) {
+			t.Errorf("Expected group %s in the docs tree", group)
+		}
+	}
+	if strings.Contains(html, `<details class="version-group">`) {
+		t.Error("Expected the default version grouping to be replaced")
+	}
+}
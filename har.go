@@ -0,0 +1,265 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ResponseExample is a named response body example, e.g. recorded traffic
+// imported with ImportHAR
+type ResponseExample struct {
+	Value  interface{}
+	Name   string
+	Status int
+}
+
+// HARImport summarizes the traffic imported with ImportHAR
+type HARImport struct {
+	// Matched counts the entries attached as examples to a documented route
+	Matched int
+	// Unmatched lists the requests without a documented route as "METHOD /path",
+	// once each in recording order, i.e. the routes still to document
+	Unmatched []string
+
+	interactions []pactInteraction
+}
+
+// harFile is an HTTP Archive (HAR 1.2) as exported by browser dev tools and proxies
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+// harEntry is a recorded request and its response
+type harEntry struct {
+	Request struct {
+		Method   string      `json:"method"`
+		URL      string      `json:"url"`
+		Headers  []harHeader `json:"headers"`
+		PostData *struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status  int         `json:"status"`
+		Headers []harHeader `json:"headers"`
+		Content struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+// harHeader is a recorded header
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harReplayHeaders are the request headers kept in the replay suite. Cookies,
+// credentials and browser noise are dropped.
+var harReplayHeaders = []string{"Accept", "Content-Type"}
+
+// ImportHAR converts recorded traffic (a HAR file) into documentation for the
+// registered routes, to bootstrap the docs of an existing service. Each entry
+// matching a documented route adds its JSON request and response bodies as
+// named examples, deduplicated, and documents its status code when missing.
+// Sensitive values are redacted. Entries without a route are listed in
+// HARImport.Unmatched, and HARImport.Contract turns the traffic into a
+// replayable test suite.
+func (an *ApiNote) ImportHAR(data []byte) (*HARImport, error) {
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("failed to parse HAR file: %w", err)
+	}
+
+	result := &HARImport{}
+	for i := range har.Log.Entries {
+		entry := &har.Log.Entries[i]
+		requestURL, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL in HAR entry %d: %w", i, err)
+		}
+		method := strings.ToUpper(entry.Request.Method)
+
+		key, ok := an.matchEndpoint(method, requestURL.Path)
+		if !ok {
+			route := method + " " + requestURL.Path
+			if !slices.Contains(result.Unmatched, route) {
+				result.Unmatched = append(result.Unmatched, route)
+			}
+			continue
+		}
+		result.Matched++
+
+		endpoint := an.endpoints[key]
+		requestBody, hasRequestBody := entry.requestBody()
+		if hasRequestBody {
+			requestBody = redactExample(requestBody, schemaType(endpoint.RequestSchema))
+		}
+		responseBody, hasResponseBody := entry.responseBody()
+		if hasResponseBody {
+			responseBody = redactExample(responseBody, schemaType(endpoint.ResponseSchema))
+		}
+
+		if hasRequestBody && !hasExample(endpoint.RequestExamples, requestBody) {
+			endpoint.RequestExamples = append(endpoint.RequestExamples, RequestExample{
+				Name:  fmt.Sprintf("recorded-%d", len(endpoint.RequestExamples)+1),
+				Value: requestBody,
+			})
+		}
+		if hasResponseBody && !hasResponseExample(endpoint.ResponseExamples, entry.Response.Status, responseBody) {
+			endpoint.ResponseExamples = append(endpoint.ResponseExamples, ResponseExample{
+				Name:   fmt.Sprintf("recorded-%d", len(endpoint.ResponseExamples)+1),
+				Status: entry.Response.Status,
+				Value:  responseBody,
+			})
+		}
+		if entry.Response.Status != 0 {
+			status := strconv.Itoa(entry.Response.Status)
+			if endpoint.Responses == nil {
+				endpoint.Responses = make(map[string]string)
+			}
+			if _, ok := endpoint.Responses[status]; !ok {
+				endpoint.Responses[status] = http.StatusText(entry.Response.Status)
+			}
+		}
+		an.endpoints[key] = endpoint
+		an.artifacts.invalidate()
+
+		result.interactions = append(result.interactions, entry.interaction(requestURL, requestBody, responseBody, hasRequestBody, hasResponseBody))
+	}
+	return result, nil
+}
+
+// Contract returns the imported traffic as a Pact contract for the given
+// consumer, replayable with VerifyPact or VerifyPactFiles. Response bodies are
+// matched by type, so the suite checks the shape of the responses rather than
+// the recorded values.
+func (h *HARImport) Contract(consumer string) ([]byte, error) {
+	contract := map[string]interface{}{
+		"consumer":     map[string]string{"name": consumer},
+		"interactions": h.interactions,
+		"metadata":     map[string]interface{}{"pactSpecification": map[string]string{"version": "2.0.0"}},
+	}
+	data, err := json.MarshalIndent(contract, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal contract: %w", err)
+	}
+	return data, nil
+}
+
+// interaction converts an entry to a replayable Pact interaction
+func (e *harEntry) interaction(requestURL *url.URL, requestBody, responseBody interface{}, hasRequestBody, hasResponseBody bool) pactInteraction {
+	interaction := pactInteraction{
+		Description: fmt.Sprintf("%s %s (%d)", strings.ToUpper(e.Request.Method), requestURL.RequestURI(), e.Response.Status),
+		Request: pactRequest{
+			Method: strings.ToUpper(e.Request.Method),
+			Path:   requestURL.Path,
+		},
+		Response: pactResponse{Status: e.Response.Status},
+	}
+	if requestURL.RawQuery != "" {
+		interaction.Request.Query, _ = json.Marshal(requestURL.RawQuery)
+	}
+	for _, header := range e.Request.Headers {
+		for _, name := range harReplayHeaders {
+			if strings.EqualFold(header.Name, name) {
+				if interaction.Request.Headers == nil {
+					interaction.Request.Headers = make(map[string]string)
+				}
+				interaction.Request.Headers[name] = header.Value
+			}
+		}
+	}
+	if hasRequestBody {
+		interaction.Request.Body, _ = json.Marshal(requestBody)
+	}
+	if hasResponseBody {
+		interaction.Response.Body, _ = json.Marshal(responseBody)
+		interaction.Response.MatchingRules = map[string]json.RawMessage{
+			"$.body": json.RawMessage(`{"match":"type"}`),
+		}
+	}
+	return interaction
+}
+
+// requestBody decodes the entry's request body when it is JSON
+func (e *harEntry) requestBody() (interface{}, bool) {
+	if e.Request.PostData == nil {
+		return nil, false
+	}
+	return decodeHARBody(e.Request.PostData.MimeType, e.Request.PostData.Text)
+}
+
+// responseBody decodes the entry's response body when it is JSON
+func (e *harEntry) responseBody() (interface{}, bool) {
+	if e.Response.Content.Encoding != "" {
+		return nil, false
+	}
+	return decodeHARBody(e.Response.Content.MimeType, e.Response.Content.Text)
+}
+
+// decodeHARBody decodes a recorded JSON body, skipping other media types
+func decodeHARBody(mimeType, text string) (interface{}, bool) {
+	if !strings.Contains(mimeType, "json") || strings.TrimSpace(text) == "" {
+		return nil, false
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// schemaType returns the type of a body schema, or nil without one
+func schemaType(schema interface{}) reflect.Type {
+	if schema == nil {
+		return nil
+	}
+	return reflect.TypeOf(schema)
+}
+
+// hasExample reports whether examples already hold value
+func hasExample(examples []RequestExample, value interface{}) bool {
+	for _, example := range examples {
+		if reflect.DeepEqual(example.Value, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasResponseExample reports whether examples already hold value for status
+func hasResponseExample(examples []ResponseExample, status int, value interface{}) bool {
+	for _, example := range examples {
+		if example.Status == status && reflect.DeepEqual(example.Value, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// responseExamplesToOpenAPI converts the response examples recorded for a status
+// to OpenAPI example objects
+func responseExamplesToOpenAPI(examples []ResponseExample, status string) map[string]ExampleObject {
+	result := make(map[string]ExampleObject)
+	for _, example := range examples {
+		if strconv.Itoa(example.Status) == status {
+			result[example.Name] = ExampleObject{Summary: example.Name, Value: example.Value}
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
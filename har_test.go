@@ -0,0 +1,137 @@
+package notelink
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type harNewUser struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type harUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+const testHAR = `{
+  "log": {
+    "entries": [
+      {
+        "request": {
+          "method": "POST",
+          "url": "https://api.example.com/v1/users",
+          "headers": [
+            {"name": "content-type", "value": "application/json"},
+            {"name": "Cookie", "value": "session=abc"}
+          ],
+          "postData": {"mimeType": "application/json", "text": "{\"name\":\"Ada\",\"password\":\"hunter2\"}"}
+        },
+        "response": {
+          "status": 201,
+          "content": {"mimeType": "application/json; charset=utf-8", "text": "{\"id\":1,\"name\":\"Ada\"}"}
+        }
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/v1/users/1?fields=name", "headers": []},
+        "response": {
+          "status": 200,
+          "content": {"mimeType": "application/json", "text": "{\"id\":1,\"name\":\"Ada\"}"}
+        }
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/v1/users/2", "headers": []},
+        "response": {
+          "status": 200,
+          "content": {"mimeType": "application/json", "text": "{\"id\":1,\"name\":\"Ada\"}"}
+        }
+      },
+      {
+        "request": {"method": "GET", "url": "https://api.example.com/v1/health", "headers": []},
+        "response": {"status": 200, "content": {"mimeType": "text/plain", "text": "ok"}}
+      }
+    ]
+  }
+}`
+
+// TestImportHAR tests converting recorded traffic into examples and a replay suite
+func TestImportHAR(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/v1"}, "secret")
+	routes := []DocumentedRouteInput{
+		{
+			Method:          "POST",
+			Path:            "/users",
+			SchemasRequest:  harNewUser{},
+			SchemasResponse: harUser{},
+			Responses:       map[string]string{"201": "Created"},
+			Handler: func(c fiber.Ctx) error {
+				return c.Status(fiber.StatusCreated).JSON(harUser{ID: 1, Name: "Ada"})
+			},
+		},
+		{
+			Method:          "GET",
+			Path:            "/users/:id",
+			Params:          []Parameter{{Name: "id", In: "path", Type: "integer", Required: true}},
+			SchemasResponse: harUser{},
+			Handler: func(c fiber.Ctx) error {
+				return c.JSON(harUser{ID: 2, Name: "Grace"})
+			},
+		},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	imported, err := api.ImportHAR([]byte(testHAR))
+	if err != nil {
+		t.Fatalf("Failed to import HAR: %v", err)
+	}
+	if imported.Matched != 3 {
+		t.Errorf("Expected 3 matched entries, got %d", imported.Matched)
+	}
+	if !reflect.DeepEqual(imported.Unmatched, []string{"GET /v1/health"}) {
+		t.Errorf("Expected the health check to be unmatched, got %v", imported.Unmatched)
+	}
+
+	create := api.endpoints["POST /users"]
+	if len(create.RequestExamples) != 1 || create.RequestExamples[0].Value.(map[string]interface{})["password"] != RedactedValue {
+		t.Errorf("Expected a redacted request example, got %+v", create.RequestExamples)
+	}
+	get := api.endpoints["GET /users/:id"]
+	if len(get.ResponseExamples) != 1 || get.ResponseExamples[0].Status != 200 {
+		t.Errorf("Expected one deduplicated response example, got %+v", get.ResponseExamples)
+	}
+	if get.Responses["200"] != "OK" {
+		t.Errorf("Expected the recorded status to be documented, got %v", get.Responses)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	content := spec.Paths["/users"].Post.Responses["201"].Content["application/json"]
+	if content.Example != nil || content.Examples["recorded-1"].Value == nil {
+		t.Errorf("Expected the recorded response example in the spec, got %+v", content)
+	}
+	if html := api.generateHTML(); !strings.Contains(html, "Response Example (recorded-1, 201)") {
+		t.Error("Expected the recorded response example in the docs")
+	}
+
+	contract, err := imported.Contract("web")
+	if err != nil {
+		t.Fatalf("Failed to build contract: %v", err)
+	}
+	if strings.Contains(string(contract), "session=abc") || strings.Contains(string(contract), "hunter2") {
+		t.Errorf("Expected cookies and secrets to be dropped from the contract:\n%s", contract)
+	}
+	failures, err := api.VerifyPact(contract, PactOptions{})
+	if err != nil {
+		t.Fatalf("Failed to replay contract: %v", err)
+	}
+	if len(failures) != 0 {
+		t.Errorf("Expected the recorded traffic to replay, got %+v", failures)
+	}
+}
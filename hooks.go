@@ -0,0 +1,62 @@
+package notelink
+
+// hooks holds the callbacks registered with OnRouteRegistered, OnSpecGenerated,
+// OnDocsRendered and OnThresholdExceeded, run in registration order
+type hooks struct {
+	routeRegistered   []func(endpoint *Endpoint)
+	specGenerated     []func(spec *OpenAPISpec)
+	docsRendered      []func(html string) string
+	thresholdExceeded []func(event ThresholdEvent)
+}
+
+// OnRouteRegistered calls fn for each route registered afterwards, before it is
+// mounted. fn may adjust the endpoint, e.g. add tags or rename parameters; the
+// changes apply to the docs, the spec and parameter validation.
+func (an *ApiNote) OnRouteRegistered(fn func(endpoint *Endpoint)) {
+	an.hooks.routeRegistered = append(an.hooks.routeRegistered, fn)
+}
+
+// OnSpecGenerated calls fn with every generated OpenAPI spec, served or exported,
+// so it can be inspected or amended. Served specs are generated once per base
+// URL and locale until the next registration.
+func (an *ApiNote) OnSpecGenerated(fn func(spec *OpenAPISpec)) {
+	an.hooks.specGenerated = append(an.hooks.specGenerated, fn)
+	an.artifacts.invalidate()
+}
+
+// OnDocsRendered calls fn with the rendered HTML of the built-in docs and print
+// view; the returned HTML is served instead. Pages are rendered once per base
+// URL and locale until the next registration.
+func (an *ApiNote) OnDocsRendered(fn func(html string) string) {
+	an.hooks.docsRendered = append(an.hooks.docsRendered, fn)
+	an.artifacts.invalidate()
+}
+
+// runRouteRegistered runs the OnRouteRegistered hooks
+func (h *hooks) runRouteRegistered(endpoint *Endpoint) {
+	for _, fn := range h.routeRegistered {
+		fn(endpoint)
+	}
+}
+
+// runSpecGenerated runs the OnSpecGenerated hooks
+func (h *hooks) runSpecGenerated(spec *OpenAPISpec) {
+	for _, fn := range h.specGenerated {
+		fn(spec)
+	}
+}
+
+// runDocsRendered runs the OnDocsRendered hooks
+func (h *hooks) runDocsRendered(html string) string {
+	for _, fn := range h.docsRendered {
+		html = fn(html)
+	}
+	return html
+}
+
+// runThresholdExceeded runs the OnThresholdExceeded hooks
+func (h *hooks) runThresholdExceeded(event ThresholdEvent) {
+	for _, fn := range h.thresholdExceeded {
+		fn(event)
+	}
+}
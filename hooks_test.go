@@ -0,0 +1,65 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestHooks tests the route, spec and docs lifecycle hooks
+func TestHooks(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+
+	var registered []string
+	api.OnRouteRegistered(func(endpoint *Endpoint) {
+		registered = append(registered, endpoint.Method+" "+endpoint.Path)
+	})
+	api.OnRouteRegistered(func(endpoint *Endpoint) {
+		endpoint.Tags = append(endpoint.Tags, "audited")
+		for i := range endpoint.Parameters {
+			endpoint.Parameters[i].Required = true
+		}
+	})
+	api.OnSpecGenerated(func(spec *OpenAPISpec) {
+		spec.Info.Version = "from-hook"
+	})
+	api.OnDocsRendered(func(html string) string {
+		return strings.Replace(html, "</body>", "<footer>Internal use only</footer></body>", 1)
+	})
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:  "GET",
+		Path:    "/users",
+		Params:  []Parameter{{Name: "page", In: "query", Type: "integer"}},
+		Handler: func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	if len(registered) != 1 || registered[0] != "GET /users" {
+		t.Errorf("Expected the route hook to run once, got %v", registered)
+	}
+	spec := api.GenerateOpenAPISpec()
+	if spec.Info.Version != "from-hook" {
+		t.Errorf("Expected the spec hook to amend the spec, got version %q", spec.Info.Version)
+	}
+	if tags := spec.Paths["/users"].Get.Tags; len(tags) != 1 || tags[0] != "audited" {
+		t.Errorf("Expected tags set by the route hook, got %v", tags)
+	}
+	if !strings.Contains(api.generateHTML(), "<footer>Internal use only</footer></body>") {
+		t.Error("Expected the docs hook to transform the HTML")
+	}
+
+	// The route hook made page required, so validation rejects requests without it
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/users", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 400 {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+}
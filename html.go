@@ -50,21 +50,66 @@ func escapeHTML(s string) string {
 	return replacer.Replace(s)
 }
 
+// escapeTemplateAttr escapes a JSON template for the editor's data-template attribute,
+// matching the decoding done by loadSchemaTemplate in the docs script
+func escapeTemplateAttr(template string) string {
+	escaped := strings.ReplaceAll(template, `"`, `&quot;`)
+	escaped = strings.ReplaceAll(escaped, `'`, `&#39;`)
+	return strings.ReplaceAll(escaped, `\`, `\\`)
+}
+
+// groupToggleControls renders the expand-all / collapse-all buttons shown on
+// version and top-level group headers
+const groupToggleControls = `<span class="group-toggle">` +
+	`<button type="button" onclick="toggleGroup(event, this, true)" title="Expand all in this group"><i class="fas fa-angles-down"></i></button>` +
+	`<button type="button" onclick="toggleGroup(event, this, false)" title="Collapse all in this group"><i class="fas fa-angles-up"></i></button>` +
+	`</span>`
+
 // generateHTML creates documentation with progressive segment grouping and method grouping
 func (an *ApiNote) generateHTML() string {
+	return an.renderDocsHTML(false, an.baseURL(nil), an.docsLocale())
+}
+
+// generatePrintHTML creates the print view: every group and endpoint expanded,
+// without the try-it forms and other interactive widgets
+func (an *ApiNote) generatePrintHTML(baseURL, locale string) string {
+	return strings.ReplaceAll(an.renderDocsHTML(true, baseURL, locale), "<details class=", "<details open class=")
+}
+
+// renderDocsHTML renders the documentation page, optionally in print mode.
+// baseURL is the scheme and host the try-it client sends requests to and
+// locale the language of the route descriptions.
+func (an *ApiNote) renderDocsHTML(printView bool, baseURL, locale string) string {
 	var html strings.Builder
 
+	// In relative-URL mode the try-it client calls whatever origin the docs were loaded from
+	tryItBaseURL := `'` + escapeJavaScript(baseURL) + `'`
+	if an.config.TryItRelativeURL {
+		baseURL = ""
+		tryItBaseURL = "window.location.origin"
+	} else if len(an.config.Servers) > 0 {
+		// Configured servers are chosen in the docs, copied URLs use the first one's defaults
+		baseURL = expandServerURL(an.config.Servers[0])
+		tryItBaseURL = "serverUrl()"
+	}
+	bodyClass := ""
+	if printView {
+		bodyClass = ` class="print-view"`
+	} else if an.embedded() {
+		bodyClass = ` class="embed-mode"`
+	}
+
 	html.WriteString(`<!DOCTYPE html>
-<html lang="en">
+<html lang="` + escapeHTML(locale) + `">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <link rel="icon" type="image/png" sizes="32x32" href="/icon.png">
-    <link rel="icon" type="image/png" sizes="16x16" href="/icon.png">
-    <link rel="shortcut icon" href="/icon.png">
-    <link rel="apple-touch-icon" href="/icon.png">
-    <link href="https://fonts.googleapis.com/css2?family=Inter:wght@300;400;500;600;700&family=JetBrains+Mono:wght@400;500&display=swap" rel="stylesheet">
-    <link href="https://cdnjs.cloudflare.com/ajax/libs/font-awesome/6.4.0/css/all.min.css" rel="stylesheet">
+    <link rel="icon" type="image/png" sizes="32x32" href="` + an.docsPath() + `/icon.png">
+    <link rel="icon" type="image/png" sizes="16x16" href="` + an.docsPath() + `/icon.png">
+    <link rel="shortcut icon" href="` + an.docsPath() + `/icon.png">
+    <link rel="apple-touch-icon" href="` + an.docsPath() + `/icon.png">
+    ` + an.assetTag(AssetFonts) + `
+    ` + an.assetTag(AssetFontAwesome) + `
     <title>` + escapeHTML(an.config.Title) + `</title>
     <style>
         :root {
@@ -174,7 +219,32 @@ func (an *ApiNote) generateHTML() string {
             align-items: stretch;
         }
 
-        .auth-section input {
+        .device-auth {
+            margin-top: 0.75rem;
+            align-items: center;
+        }
+
+        .device-auth-status {
+            margin: 0;
+            font-size: 0.875rem;
+            color: var(--gray-600);
+        }
+
+        .mock-persistence-toggle {
+            display: flex;
+            gap: 0.5rem;
+            align-items: center;
+            font-size: 0.875rem;
+            color: var(--gray-600);
+        }
+
+        .auth-section .mock-persistence-toggle input {
+            flex: none;
+            padding: 0;
+        }
+
+        .auth-section input,
+        .auth-section select {
             flex: 1;
             padding: 0.75rem 1rem;
             border: 1px solid var(--gray-300);
@@ -184,7 +254,8 @@ func (an *ApiNote) generateHTML() string {
             background: var(--white);
         }
 
-        .auth-section input:focus {
+        .auth-section input:focus,
+        .auth-section select:focus {
             outline: none;
             border-color: var(--primary);
             box-shadow: 0 0 0 3px rgb(99 102 241 / 0.1);
@@ -223,6 +294,492 @@ func (an *ApiNote) generateHTML() string {
             align-items: center;
         }
 
+        .section-actions {
+            display: flex;
+            gap: 0.5rem;
+            align-items: center;
+        }
+
+        .palette-button {
+            display: inline-flex;
+            align-items: center;
+            gap: 0.5rem;
+            padding: 0.5rem 1rem;
+            background: var(--white);
+            color: var(--gray-600);
+            border: 1px solid var(--gray-200);
+            border-radius: var(--radius);
+            font-size: 0.75rem;
+            cursor: pointer;
+        }
+
+        .palette-button:hover {
+            border-color: var(--primary);
+        }
+
+        kbd {
+            font-family: 'JetBrains Mono', monospace;
+            font-size: 0.7rem;
+            padding: 0.05rem 0.35rem;
+            border: 1px solid var(--gray-300);
+            border-bottom-width: 2px;
+            border-radius: 0.25rem;
+            background: var(--gray-50);
+            color: var(--gray-700);
+        }
+
+        .group-toggle {
+            float: right;
+            display: inline-flex;
+            gap: 0.25rem;
+            margin-right: 1rem;
+        }
+
+        .group-toggle button {
+            padding: 0.15rem 0.5rem;
+            font-size: 0.7rem;
+            background: transparent;
+            color: var(--gray-500);
+            border: 1px solid var(--gray-200);
+            border-radius: 0.375rem;
+            cursor: pointer;
+        }
+
+        .group-toggle button:hover {
+            color: var(--primary-dark);
+            border-color: var(--primary);
+        }
+
+        /* Copy to clipboard */
+        .copyable {
+            position: relative;
+        }
+
+        .copy-button {
+            padding: 0.25rem 0.5rem;
+            background: transparent;
+            border: none;
+            color: var(--gray-400);
+            font-size: 0.8rem;
+            cursor: pointer;
+        }
+
+        .copy-button:hover,
+        .copy-button.copied {
+            color: var(--primary);
+        }
+
+        .copyable > .copy-button {
+            position: absolute;
+            top: 0.5rem;
+            right: 0.5rem;
+            color: var(--gray-300);
+            background: rgba(255, 255, 255, 0.08);
+            border-radius: 0.25rem;
+            opacity: 0;
+            transition: opacity 0.2s;
+        }
+
+        .copyable:hover > .copy-button,
+        .copyable > .copy-button:focus,
+        .copyable > .copy-button.copied {
+            opacity: 1;
+        }
+
+        .method-group > summary .copy-button {
+            opacity: 0;
+        }
+
+        .method-group > summary:hover .copy-button,
+        .method-group > summary .copy-button.copied {
+            opacity: 1;
+        }
+
+        /* Schema view toggle */
+        .schema-views-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            gap: 1rem;
+        }
+
+        .schema-view-tabs {
+            margin-left: auto;
+            display: inline-flex;
+            border: 1px solid var(--gray-200);
+            border-radius: 0.375rem;
+            overflow: hidden;
+        }
+
+        .schema-view-tab {
+            padding: 0.25rem 0.75rem;
+            background: var(--white);
+            border: none;
+            color: var(--gray-600);
+            font-size: 0.7rem;
+            cursor: pointer;
+        }
+
+        .schema-view-tab + .schema-view-tab {
+            border-left: 1px solid var(--gray-200);
+        }
+
+        .schema-view-tab.active {
+            background: var(--primary);
+            color: var(--white);
+        }
+
+        .schema-view[hidden] {
+            display: none;
+        }
+
+        /* Favorites and recently viewed */
+        .favorite-toggle {
+            margin-left: auto;
+            padding: 0.25rem 0.5rem;
+            background: transparent;
+            border: none;
+            color: var(--gray-300);
+            font-size: 0.9rem;
+            cursor: pointer;
+        }
+
+        .favorite-toggle:hover,
+        .favorite-toggle.active {
+            color: #f59e0b;
+        }
+
+        .quick-access {
+            display: grid;
+            grid-template-columns: repeat(auto-fit, minmax(260px, 1fr));
+            gap: 1rem;
+            margin-bottom: 1.5rem;
+        }
+
+        .quick-access[hidden],
+        .quick-access-list[hidden] {
+            display: none;
+        }
+
+        .quick-access-list {
+            padding: 1rem;
+            background: var(--white);
+            border: 1px solid var(--gray-200);
+            border-radius: var(--radius);
+            box-shadow: var(--shadow-sm);
+            font-size: 0.8rem;
+        }
+
+        .quick-access-list h3 {
+            display: flex;
+            align-items: center;
+            gap: 0.5rem;
+            margin: 0 0 0.5rem 0;
+            font-size: 0.85rem;
+            text-transform: uppercase;
+            letter-spacing: 0.05em;
+            color: var(--gray-500);
+        }
+
+        .quick-access-list ul {
+            list-style: none;
+            margin: 0;
+            padding: 0;
+        }
+
+        .quick-access-list li {
+            margin: 0.25rem 0;
+        }
+
+        .quick-access-list a {
+            color: var(--gray-700);
+            text-decoration: none;
+            font-family: 'JetBrains Mono', monospace;
+        }
+
+        .quick-access-list a:hover {
+            color: var(--primary);
+        }
+
+        .quick-access-clear {
+            margin-left: auto;
+            padding: 0.1rem 0.5rem;
+            background: transparent;
+            border: 1px solid var(--gray-200);
+            border-radius: 0.25rem;
+            color: var(--gray-500);
+            font-size: 0.7rem;
+            text-transform: none;
+            letter-spacing: normal;
+            cursor: pointer;
+        }
+
+        .try-it-presets {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 0.5rem;
+            margin-bottom: 0.75rem;
+        }
+
+        .preset-chip {
+            padding: 0.25rem 0.75rem;
+            border: 1px solid var(--gray-300);
+            border-radius: 999px;
+            background: white;
+            color: var(--gray-700);
+            font-size: 0.8rem;
+            cursor: pointer;
+        }
+
+        .preset-chip:hover, .preset-chip.active {
+            border-color: var(--primary);
+            color: var(--primary);
+        }
+
+        .try-it-locale {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 0.75rem;
+            margin: 0.75rem 0;
+            font-size: 0.85rem;
+        }
+
+        .try-it-locale input {
+            margin-left: 0.25rem;
+        }
+
+        .try-it-assertions {
+            margin: 0.75rem 0;
+        }
+
+        .try-it-assertions summary {
+            cursor: pointer;
+            font-size: 0.85rem;
+            color: var(--gray-600);
+        }
+
+        .assertions-input {
+            width: 100%;
+            margin: 0.5rem 0;
+            font-family: 'JetBrains Mono', monospace;
+            font-size: 0.8rem;
+        }
+
+        .assertion-results {
+            list-style: none;
+            padding: 0;
+            font-size: 0.85rem;
+        }
+
+        .assertion-badge {
+            display: inline-block;
+            padding: 0 0.4rem;
+            border-radius: 4px;
+            color: white;
+            font-size: 0.7rem;
+            font-weight: 600;
+        }
+
+        .assertion-badge.pass {
+            background: var(--success);
+        }
+
+        .assertion-badge.fail {
+            background: var(--danger);
+        }
+
+        .try-it-disabled {
+            margin: 0.5rem 0;
+            font-size: 0.85rem;
+            color: var(--gray-500);
+        }
+
+        .api-test button[type="submit"]:disabled {
+            opacity: 0.5;
+            cursor: not-allowed;
+        }
+
+        /* Embed mode: the page takes the height of its content, which sizes the iframe */
+        body.embed-mode {
+            min-height: 0;
+            background: transparent;
+        }
+
+        .embed-mode .container {
+            max-width: none;
+            padding: 0 1rem;
+        }
+
+        /* Print view and printing */
+        .print-view .docs-sidebar,
+        .print-view .auth-section,
+        .print-view .section-actions,
+        .print-view .group-toggle,
+        .print-view .quick-access,
+        .print-view .favorite-toggle,
+        .print-view .schema-view-tabs,
+        .print-view .copy-button {
+            display: none;
+        }
+
+        .print-view .docs-layout {
+            grid-template-columns: minmax(0, 1fr);
+            grid-template-areas: "main";
+        }
+
+        @media print {
+            body {
+                background: var(--white);
+                font-size: 11pt;
+            }
+
+            .container {
+                max-width: none;
+                padding: 0;
+            }
+
+            .docs-sidebar,
+            .auth-section,
+            .section-actions,
+            .group-toggle,
+            .quick-access,
+            .favorite-toggle,
+            .copy-button,
+            .api-test,
+            .command-palette {
+                display: none !important;
+            }
+
+            .docs-layout {
+                display: block;
+            }
+
+            details > summary {
+                list-style: none;
+            }
+
+            details > summary::-webkit-details-marker,
+            details > summary::after {
+                display: none;
+            }
+
+            details > *:not(summary) {
+                animation: none !important;
+                transform: none !important;
+            }
+
+            .version-group,
+            .top-segment-group {
+                break-before: page;
+            }
+
+            .version-group:first-of-type,
+            .top-segment-group:first-of-type {
+                break-before: auto;
+            }
+
+            .method-group {
+                break-inside: avoid;
+            }
+
+            summary, h4, h5 {
+                break-after: avoid;
+            }
+
+            pre {
+                white-space: pre-wrap;
+                word-break: break-word;
+                break-inside: avoid;
+            }
+
+            a {
+                color: inherit;
+                text-decoration: none;
+            }
+        }
+
+        /* Command palette */
+        .command-palette {
+            position: fixed;
+            inset: 0;
+            z-index: 1000;
+            background: rgb(17 24 39 / 0.4);
+            display: flex;
+            justify-content: center;
+            align-items: flex-start;
+            padding-top: 12vh;
+        }
+
+        .command-palette[hidden] {
+            display: none;
+        }
+
+        .command-palette-dialog {
+            width: min(640px, 92vw);
+            background: var(--white);
+            border-radius: var(--radius);
+            box-shadow: var(--shadow-lg);
+            overflow: hidden;
+        }
+
+        .command-palette-input {
+            width: 100%;
+            padding: 1rem;
+            border: none;
+            border-bottom: 1px solid var(--gray-200);
+            font-size: 1rem;
+            outline: none;
+        }
+
+        .command-palette-results {
+            list-style: none;
+            margin: 0;
+            padding: 0.25rem;
+            max-height: 50vh;
+            overflow-y: auto;
+        }
+
+        .command-palette-results li {
+            display: flex;
+            gap: 0.75rem;
+            align-items: baseline;
+            margin: 0;
+            padding: 0.5rem 0.75rem;
+            border-radius: 0.5rem;
+            cursor: pointer;
+        }
+
+        .command-palette-results li.selected {
+            background: var(--gray-100);
+        }
+
+        .command-palette-path {
+            font-family: 'JetBrains Mono', monospace;
+            font-size: 0.85rem;
+        }
+
+        .command-palette-description {
+            color: var(--gray-500);
+            font-size: 0.8rem;
+            overflow: hidden;
+            text-overflow: ellipsis;
+            white-space: nowrap;
+        }
+
+        .command-palette-empty {
+            color: var(--gray-500);
+        }
+
+        .command-palette-help {
+            display: flex;
+            flex-wrap: wrap;
+            gap: 1rem;
+            padding: 0.5rem 1rem;
+            border-top: 1px solid var(--gray-200);
+            font-size: 0.7rem;
+            color: var(--gray-500);
+        }
+
         .monitor-button {
             display: inline-flex;
             align-items: center;
@@ -527,8 +1084,18 @@ func (an *ApiNote) generateHTML() string {
             color: var(--white);
         }
 
-        .method.PATCH {
-            background: linear-gradient(135deg, #8b5cf6 0%, #7c3aed 100%);
+        .method.PATCH {
+            background: linear-gradient(135deg, #8b5cf6 0%, #7c3aed 100%);
+            color: var(--white);
+        }
+
+        .method.PUBLISH {
+            background: linear-gradient(135deg, #f97316 0%, #ea580c 100%);
+            color: var(--white);
+        }
+
+        .method.SUBSCRIBE {
+            background: linear-gradient(135deg, #64748b 0%, #475569 100%);
             color: var(--white);
         }
 
@@ -587,12 +1154,44 @@ func (an *ApiNote) generateHTML() string {
             opacity: 1;
         }
 
-        .responses, .schemas, .parameters {
+        .responses, .schemas, .parameters, .related-operations, .ownership, .variants, .changelog {
             margin: 0.75rem 0;
             padding: 0.5rem 0;
             border-bottom: 1px solid var(--gray-200);
         }
 
+        .migration-guide {
+            margin: 0.75rem 0;
+            padding: 0.75rem 1rem;
+            border-left: 4px solid var(--warning);
+            background: rgba(245, 158, 11, 0.08);
+            border-radius: 0.25rem;
+        }
+
+        .migration-guide h4 {
+            margin: 0 0 0.25rem;
+        }
+
+        .migration-sunset {
+            margin: 0.25rem 0;
+            font-weight: 600;
+        }
+
+        .changelog summary {
+            cursor: pointer;
+            font-size: 0.9rem;
+            font-weight: 600;
+        }
+
+        .changelog-version {
+            font-family: 'JetBrains Mono', monospace;
+            font-weight: 600;
+        }
+
+        .changelog time {
+            color: var(--gray-500);
+        }
+
         .api-test {
             margin: 1rem 0;
             padding: 1rem;
@@ -725,6 +1324,154 @@ func (an *ApiNote) generateHTML() string {
             transition: all 0.3s ease;
         }
 
+        .flag-badge {
+            font-size: 0.75rem;
+            font-weight: 600;
+            color: var(--warning);
+            background: rgba(245, 158, 11, 0.1);
+            padding: 0.25rem 0.5rem;
+            border-radius: var(--radius);
+            white-space: nowrap;
+        }
+
+        .status-badge {
+            font-size: 0.75rem;
+            font-weight: 600;
+            padding: 0.25rem 0.5rem;
+            border-radius: var(--radius);
+            white-space: nowrap;
+        }
+
+        .status-draft {
+            color: var(--gray-600);
+            background: var(--gray-100);
+        }
+
+        .status-review {
+            color: var(--warning);
+            background: rgba(245, 158, 11, 0.1);
+        }
+
+        .status-stable {
+            color: var(--success);
+            background: rgba(16, 185, 129, 0.1);
+        }
+
+        .status-filter {
+            padding: 0.4rem 0.6rem;
+            border: 1px solid var(--gray-200);
+            border-radius: var(--radius);
+            background: var(--white);
+            font-size: 0.85rem;
+        }
+
+        .deprecated-badge {
+            font-size: 0.75rem;
+            font-weight: 600;
+            color: var(--gray-600);
+            background: var(--gray-100);
+            padding: 0.25rem 0.5rem;
+            border-radius: var(--radius);
+            white-space: nowrap;
+            text-decoration: line-through;
+        }
+
+        .doc-page-content {
+            line-height: 1.6;
+            color: var(--gray-700);
+        }
+
+        .doc-page-content pre {
+            background: var(--gray-900);
+            color: var(--gray-100);
+            padding: 0.75rem 1rem;
+            border-radius: var(--radius);
+            overflow-x: auto;
+        }
+
+        .doc-page-content blockquote {
+            margin: 0.75rem 0;
+            padding-left: 1rem;
+            border-left: 3px solid var(--gray-300);
+        }
+
+        .error-codes table {
+            width: 100%;
+            border-collapse: collapse;
+            margin: 0.75rem 0;
+            font-size: 0.875rem;
+        }
+
+        .error-codes th,
+        .error-codes td {
+            text-align: left;
+            padding: 0.5rem;
+            border-bottom: 1px solid var(--gray-200);
+        }
+
+        .csv-columns table,
+        .csv-preview {
+            width: 100%;
+            border-collapse: collapse;
+            margin: 0.75rem 0;
+            font-size: 0.875rem;
+        }
+
+        .csv-columns th,
+        .csv-columns td,
+        .csv-preview th,
+        .csv-preview td {
+            text-align: left;
+            padding: 0.5rem;
+            border-bottom: 1px solid var(--gray-200);
+        }
+
+        .docs-environment-banner {
+            background: var(--warning);
+            color: #fff;
+            font-weight: 700;
+            text-align: center;
+            padding: 0.5rem 1rem;
+            letter-spacing: 0.03em;
+        }
+
+        .docs-announcement {
+            background: var(--gray-100);
+            border-bottom: 1px solid var(--gray-200);
+            text-align: center;
+            padding: 0.5rem 1rem;
+            font-size: 0.875rem;
+        }
+
+        .docs-legal-notice {
+            max-width: 1200px;
+            margin: 2rem auto;
+            padding: 1rem;
+            border-top: 1px solid var(--gray-200);
+            color: var(--gray-400);
+            font-size: 0.75rem;
+        }
+
+        .proxied-badge {
+            font-size: 0.75rem;
+            font-weight: 600;
+            color: var(--primary);
+            background: var(--gray-100);
+            padding: 0.25rem 0.5rem;
+            border-radius: var(--radius);
+            white-space: nowrap;
+        }
+
+        .slo-badge {
+            font-size: 0.75rem;
+            font-weight: 600;
+            color: var(--danger);
+            background: rgba(239, 68, 68, 0.1);
+            padding: 0.25rem 0.5rem;
+            border-radius: var(--radius);
+            white-space: nowrap;
+        }
+
         .method-group:hover .lock-icon {
             background: var(--warning);
             color: var(--white);
@@ -767,6 +1514,185 @@ func (an *ApiNote) generateHTML() string {
             }
         }
         
+        /* Sidebar navigation */
+        .docs-layout {
+            display: grid;
+            grid-template-columns: 280px minmax(0, 1fr);
+            grid-template-areas: "sidebar main";
+            gap: 2rem;
+            align-items: start;
+        }
+
+        .docs-main {
+            grid-area: main;
+            min-width: 0;
+        }
+
+        .docs-sidebar {
+            grid-area: sidebar;
+            position: sticky;
+            top: 1rem;
+            max-height: calc(100vh - 2rem);
+            overflow-y: auto;
+            padding: 1rem;
+            background: var(--white);
+            border: 1px solid var(--gray-200);
+            border-radius: var(--radius);
+            box-shadow: var(--shadow-sm);
+            font-size: 0.8rem;
+        }
+
+        .docs-sidebar h3 {
+            margin: 0 0 0.5rem 0;
+            font-size: 0.85rem;
+            text-transform: uppercase;
+            letter-spacing: 0.05em;
+            color: var(--gray-500);
+        }
+
+        .docs-sidebar ul {
+            list-style: none;
+            margin: 0;
+            padding-left: 0.75rem;
+        }
+
+        .docs-sidebar > .toc {
+            padding-left: 0;
+        }
+
+        .docs-sidebar li {
+            margin: 0.15rem 0;
+        }
+
+        .docs-sidebar a {
+            display: block;
+            padding: 0.15rem 0.5rem;
+            border-radius: 0.375rem;
+            color: var(--gray-700);
+            text-decoration: none;
+            overflow: hidden;
+            text-overflow: ellipsis;
+            white-space: nowrap;
+        }
+
+        .docs-sidebar a:hover {
+            background: var(--gray-100);
+        }
+
+        .docs-sidebar a.active {
+            background: var(--gray-100);
+            color: var(--primary-dark);
+            font-weight: 600;
+        }
+
+        .toc-endpoint {
+            font-family: 'JetBrains Mono', monospace;
+        }
+
+        .toc-method {
+            display: inline-block;
+            min-width: 3.5rem;
+            font-weight: 600;
+            font-size: 0.65rem;
+        }
+
+        .toc-method.GET { color: #059669; }
+        .toc-method.POST { color: #2563eb; }
+        .toc-method.PUT { color: #d97706; }
+        .toc-method.DELETE { color: #dc2626; }
+        .toc-method.PATCH { color: #7c3aed; }
+        .toc-method.PUBLISH { color: #ea580c; }
+        .toc-method.SUBSCRIBE { color: #475569; }
+
+        @media (max-width: 1024px) {
+            .docs-layout {
+                grid-template-columns: minmax(0, 1fr);
+                grid-template-areas: "main";
+            }
+
+            .docs-sidebar {
+                display: none;
+            }
+        }
+
+        /* Server-Timing breakdown */
+        .server-timing {
+            margin: 0.5rem 0;
+        }
+
+        .server-timing-row {
+            display: flex;
+            align-items: center;
+            gap: 0.5rem;
+            font-size: 0.75rem;
+        }
+
+        .server-timing-name {
+            width: 110px;
+            font-family: 'JetBrains Mono', monospace;
+        }
+
+        .server-timing-bar {
+            height: 0.5rem;
+            background: var(--primary);
+            border-radius: 9999px;
+            max-width: 60%;
+        }
+
+        /* Request signature calculator */
+        .follow-ups {
+            margin-top: 0.75rem;
+            display: flex;
+            flex-wrap: wrap;
+            gap: 0.5rem;
+            align-items: center;
+        }
+
+        .follow-up-btn {
+            font-family: 'JetBrains Mono', monospace;
+            font-size: 0.8rem;
+        }
+
+        .signature-calculator {
+            border: 1px dashed var(--gray-300);
+            border-radius: var(--radius);
+            padding: 0.75rem;
+            margin: 0.75rem 0;
+            background: var(--gray-50);
+        }
+
+        .signature-calculator h5 {
+            margin: 0 0 0.5rem 0;
+        }
+
+        .signature-preview:empty {
+            display: none;
+        }
+
+        .callback-relay {
+            border: 1px dashed var(--gray-300);
+            border-radius: var(--radius);
+            padding: 0.75rem;
+            margin: 0.75rem 0;
+            background: var(--gray-50);
+        }
+
+        .callback-relay h5 {
+            margin: 0 0 0.5rem 0;
+        }
+
+        .callback-relay-url {
+            display: block;
+            margin: 0.5rem 0;
+            word-break: break-all;
+        }
+
+        .callback-relay-entry summary {
+            cursor: pointer;
+            font-family: 'JetBrains Mono', monospace;
+            font-size: 0.8rem;
+        }
+
         /* JSON Editor Styles */
         .json-editor-container {
             position: relative;
@@ -815,6 +1741,11 @@ func (an *ApiNote) generateHTML() string {
             border-color: var(--primary);
         }
         
+        .ndjson-stream-status {
+            font-size: 0.85em;
+            color: var(--info);
+        }
+
         .json-validation-message {
             padding: 0.5rem;
             font-size: 0.75rem;
@@ -836,43 +1767,52 @@ func (an *ApiNote) generateHTML() string {
     </style>
     
     <!-- CodeMirror for JSON editing -->
-    <link rel="stylesheet" href="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/codemirror.min.css">
-    <link rel="stylesheet" href="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/theme/default.min.css">
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/codemirror.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/mode/javascript/javascript.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/addon/lint/lint.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/addon/lint/json-lint.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/addon/edit/closebrackets.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/addon/edit/matchbrackets.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/addon/fold/foldcode.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/addon/fold/foldgutter.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/codemirror/5.65.2/addon/fold/brace-fold.min.js"></script>
-    <script src="https://cdnjs.cloudflare.com/ajax/libs/jsonlint/1.6.0/jsonlint.min.js"></script>
-    
+    ` + an.assetTag(AssetCodeMirrorCSS) + `
+    ` + an.assetTag(AssetCodeMirrorThemeCSS) + `
+    ` + an.assetTag(AssetCodeMirror) + `
+    ` + an.assetTag(AssetCodeMirrorJavaScript) + `
+    ` + an.assetTag(AssetCodeMirrorLint) + `
+    ` + an.assetTag(AssetCodeMirrorJSONLint) + `
+    ` + an.assetTag(AssetCodeMirrorCloseBrackets) + `
+    ` + an.assetTag(AssetCodeMirrorMatchBrackets) + `
+    ` + an.assetTag(AssetCodeMirrorFoldCode) + `
+    ` + an.assetTag(AssetCodeMirrorFoldGutter) + `
+    ` + an.assetTag(AssetCodeMirrorBraceFold) + `
+    ` + an.assetTag(AssetJSONLint) + `
+    ` + an.renderDocsTemplate("head", printView, locale) + `
 </head>
-<body>
+<body` + bodyClass + `>
+    ` + an.renderDocsTemplate("banner", printView, locale) + `
     <div class="container">
-        <div class="header">
-            <h1>` + escapeHTML(an.config.Title) + `</h1>
-            <p class="subtitle">` + escapeHTML(an.config.Description) + `</p>
-            <span class="version-badge">` + escapeHTML(an.config.Version) + `</span>
-        </div>
-
-        <div class="auth-section">
-            <h2><i class="fas fa-key"></i> Authorize</h2>
-            <div class="auth-input-group">
-                <input type="text" id="auth-token" placeholder="Enter JWT Bearer Token (e.g., Bearer eyJ...)" value="` + escapeHTML(an.config.AuthToken) + `">
-                <button onclick="setAuthToken()">Set Token</button>
-            </div>
-        </div>
+      <div class="docs-layout">
+      <main class="docs-main">` + an.renderDocsChrome(printView) + an.renderServerSelector() + an.renderTenantSelector() + an.renderMockPersistence() + `
         
         <div class="section-header">
             <h2 class="section-title">API Endpoints</h2>
-            <a href="/api-docs/metrics" target="_blank" class="monitor-button">
-                <i class="fas fa-chart-line"></i>
-                Monitor
-            </a>
-        </div>`)
+            <div class="section-actions">
+                <button type="button" class="palette-button" onclick="setAllDetails(true)" title="Expand all (e)">
+                    <i class="fas fa-angles-down"></i>
+                    Expand all
+                </button>
+                <button type="button" class="palette-button" onclick="setAllDetails(false)" title="Collapse all (c)">
+                    <i class="fas fa-angles-up"></i>
+                    Collapse all
+                </button>
+` + an.renderStatusFilter() + `
+                <button type="button" class="palette-button" onclick="openCommandPalette()" title="Jump to endpoint">
+                    <i class="fas fa-search"></i>
+                    Search <kbd>⌘K</kbd>
+                </button>
+                <a href="` + an.docsPath() + `/print" target="_blank" class="palette-button" title="Print-friendly view with every endpoint expanded">
+                    <i class="fas fa-print"></i>
+                    Print view
+                </a>
+                <a href="` + an.docsPath() + `/metrics" target="_blank" class="monitor-button">
+                    <i class="fas fa-chart-line"></i>
+                    Monitor
+                </a>
+            </div>
+        </div>` + quickAccessMarkup)
 
 	// Build a nested structure: version (if exists) > top-level segment > sub-segments > full path > methods
 	type SegmentNode struct {
@@ -885,9 +1825,30 @@ func (an *ApiNote) generateHTML() string {
 	versionGroups := make(map[string]*SegmentNode)
 	nonVersionedRoot := &SegmentNode{Name: "", Children: make(map[string]*SegmentNode)}
 
-	for _, endpoint := range an.endpoints {
-		version := getVersion(endpoint.Path)
-		segments := strings.Split(strings.Trim(endpoint.Path, "/"), "/")
+	for _, endpoint := range an.docsEndpoints() {
+		if an.hiddenByFlag(&endpoint) {
+			continue
+		}
+		endpoint = localizeEndpoint(endpoint, locale)
+		// Custom grouping strategies place endpoints directly in the tree
+		if an.config.GroupBy != nil {
+			groups := an.config.GroupBy(endpoint)
+			if len(groups) == 0 {
+				groups = []string{"Other"}
+			}
+			current := nonVersionedRoot
+			for _, group := range groups {
+				if current.Children[group] == nil {
+					current.Children[group] = &SegmentNode{Name: group, Children: make(map[string]*SegmentNode)}
+				}
+				current = current.Children[group]
+			}
+			current.Endpoints = append(current.Endpoints, endpoint)
+			continue
+		}
+
+		version := getVersion(endpoint.routePath())
+		segments := strings.Split(strings.Trim(endpoint.routePath(), "/"), "/")
 		var versionIdx int = -1
 		for i, seg := range segments {
 			if strings.HasPrefix(seg, "v") && len(seg) > 1 {
@@ -911,9 +1872,13 @@ func (an *ApiNote) generateHTML() string {
 				}
 				current = current.Children[topSeg]
 
-				// Process all remaining segments to build the full hierarchy
+				// Process all remaining segments to build the full hierarchy; optional
+				// and wildcard tails stay with their parent group
 				for i := versionIdx + 2; i < len(segments); i++ {
 					seg := segments[i]
+					if isDynamicSegment(seg) {
+						break
+					}
 					if current.Children[seg] == nil {
 						current.Children[seg] = &SegmentNode{Name: seg, Children: make(map[string]*SegmentNode)}
 					}
@@ -929,9 +1894,13 @@ func (an *ApiNote) generateHTML() string {
 			}
 			current := nonVersionedRoot.Children[topSeg]
 
-			// Process all remaining segments to build the full hierarchy
+			// Process all remaining segments to build the full hierarchy; optional
+			// and wildcard tails stay with their parent group
 			for i := 1; i < len(segments); i++ {
 				seg := segments[i]
+				if isDynamicSegment(seg) {
+					break
+				}
 				if current.Children[seg] == nil {
 					current.Children[seg] = &SegmentNode{Name: seg, Children: make(map[string]*SegmentNode)}
 				}
@@ -942,21 +1911,37 @@ func (an *ApiNote) generateHTML() string {
 		}
 	}
 
+	// The sidebar table of contents mirrors the rendered tree
+	var toc strings.Builder
+
+	// Render the guides before the reference
+	pagesHTML, pagesTOC := an.renderDocPages()
+	html.WriteString(pagesHTML)
+	toc.WriteString(pagesTOC)
+
 	// Render segments recursively
-	var renderSegments func(node *SegmentNode, depth int, groupClass string)
-	renderSegments = func(node *SegmentNode, depth int, groupClass string) {
+	var renderSegments func(node *SegmentNode, depth int, groupClass string, parents []string)
+	renderSegments = func(node *SegmentNode, depth int, groupClass string, parents []string) {
 		// Sort children (segments)
 		var segmentNames []string
 		for name := range node.Children {
 			segmentNames = append(segmentNames, name)
 		}
-		sort.Strings(segmentNames)
+		an.sortGroupNames(segmentNames)
 
 		for _, name := range segmentNames {
 			child := node.Children[name]
+			groupPath := append(append([]string(nil), parents...), name)
+			groupID := groupAnchor(groupPath)
+			controls := ""
+			if depth == 0 {
+				controls = groupToggleControls
+			}
 			html.WriteString(`
-    <details class="` + groupClass + `">
-        <summary>` + name + `</summary>`)
+    <details class="` + groupClass + `" id="` + groupID + `">
+        <summary>` + escapeHTML(name) + controls + `</summary>`)
+			toc.WriteString(`
+                <li><a href="#` + groupID + `">` + escapeHTML(name) + `</a><ul>`)
 
 			// Group endpoints by full path
 			if len(child.Endpoints) > 0 {
@@ -972,32 +1957,50 @@ func (an *ApiNote) generateHTML() string {
 				for fullPath := range pathGroups {
 					fullPaths = append(fullPaths, fullPath)
 				}
-				sort.Strings(fullPaths)
+				for _, endpoints := range pathGroups {
+					an.sortEndpoints(endpoints)
+				}
+				sort.SliceStable(fullPaths, func(i, j int) bool {
+					first, second := pathGroups[fullPaths[i]][0], pathGroups[fullPaths[j]][0]
+					if an.endpointLess(&first, &second) != an.endpointLess(&second, &first) {
+						return an.endpointLess(&first, &second)
+					}
+					return fullPaths[i] < fullPaths[j]
+				})
 
 				for _, fullPath := range fullPaths {
 					endpoints := pathGroups[fullPath]
-					sort.Slice(endpoints, func(i, j int) bool {
-						return endpoints[i].Method < endpoints[j].Method
-					})
 					html.WriteString(`
         <details class="path-group">
             <summary>` + fullPath + ` (` + strconv.Itoa(len(endpoints)) + ` method` + pluralize(len(endpoints)) + `)</summary>`)
 
 					// Render all methods under this path
 					for _, endpoint := range endpoints {
+						toc.WriteString(`
+                    <li><a class="toc-endpoint" href="#` + operationAnchor(endpoint.Method, endpoint.routePath()) + `"><span class="toc-method ` + escapeHTML(endpoint.Method) + `">` + escapeHTML(endpoint.Method) + `</span>` + escapeHTML(endpoint.Path) + `</a></li>`)
 						schemaBaseName := strings.Split(fullPath, "/")[len(strings.Split(fullPath, "/"))-1] // Second-to-last segment
 						lockIcon := ""
 						if endpoint.AuthRequired {
 							lockIcon = `<i class="fas fa-lock lock-icon"></i>`
 						}
+						if endpoint.ClientCertRequired {
+							lockIcon += `<i class="fas fa-certificate lock-icon" title="Client certificate required"></i>`
+						}
+						if endpoint.EncryptedRequest {
+							lockIcon += `<i class="fas fa-user-shield lock-icon" title="Request body encrypted as a JWE"></i>`
+						}
+						if endpoint.IPFilter != nil {
+							lockIcon += `<i class="fas fa-network-wired lock-icon" title="` + escapeHTML(endpoint.IPFilter.describe()) + `"></i>`
+						}
+						lockIcon += renderDeprecatedBadge(&endpoint) + renderFlagBadge(&endpoint) + renderSLOBadge(&endpoint) + renderProxiedBadge(&endpoint) + renderStatusBadge(&endpoint)
 						html.WriteString(`
-            <details class="method-group">
+            <details class="method-group" id="` + operationAnchor(endpoint.Method, endpoint.routePath()) + `" data-status="` + string(endpoint.Status) + `">
                 <summary>
                     <span class="method ` + escapeHTML(endpoint.Method) + `">` + escapeHTML(endpoint.Method) + `</span>
-                    <span class="endpoint-path">` + escapeHTML(endpoint.Path) + `</span>
-                    <span class="endpoint-description">` + escapeHTML(endpoint.Description) + `</span>` + lockIcon + `
+                    <span class="endpoint-path">` + escapeHTML(endpoint.Path) + `</span>` + renderCopyURLButton(baseURL, endpoint.Path) + `
+                    <span class="endpoint-description">` + escapeHTML(endpoint.Description) + `</span>` + lockIcon + favoriteToggleMarkup + `
                 </summary>
-                <div>`)
+                <div>` + an.renderMigrationGuide(&endpoint))
 
 						if len(endpoint.Parameters) > 0 {
 							html.WriteString(`
@@ -1033,29 +2036,63 @@ func (an *ApiNote) generateHTML() string {
 						}
 
 						html.WriteString(`
-                    </div>
+                    </div>` + an.renderRelatedOperations(&endpoint) + renderOwnership(endpoint.Ownership) + renderChangeLog(endpoint.ChangeLog) + renderVariants(endpoint.Variants) + renderValidationRules(endpoint.ValidationRules) + `
                     <div class="schemas">
                         <h4>Schemas:</h4>`)
 
-						if reqTs := generateTypeScriptSchema(schemaBaseName+"Request", endpoint.RequestSchema); reqTs != "" {
+						html.WriteString(renderSchemaViews("Request Body", schemaBaseName+"Request", endpoint.RequestSchema, an.exampleOptions(), an.schemaNames()))
+						if hasMediaType(requestMediaTypes(&endpoint), isCSVContentType) {
+							html.WriteString(renderCSVColumns("Request Body", endpoint.RequestSchema))
+						}
+						for _, example := range endpoint.RequestExamples {
+							html.WriteString(`
+                        <h5>Request Example (` + escapeHTML(example.Name) + `):</h5>
+                        ` + renderCopyablePre("", escapeHTML(exampleJSON(example.Value))))
+						}
+						html.WriteString(renderSchemaViews("Response Body", schemaBaseName+"Response", endpoint.ResponseSchema, an.exampleOptions(), an.schemaNames()))
+						if hasMediaType(responseMediaTypes(&endpoint), isCSVContentType) {
+							html.WriteString(renderCSVColumns("Response Body", endpoint.ResponseSchema))
+						}
+						for _, example := range endpoint.ResponseExamples {
 							html.WriteString(`
-                        <h5>Request Body:</h5>
-                        <pre>` + reqTs + `</pre>`)
+                        <h5>Response Example (` + escapeHTML(example.Name) + `, ` + strconv.Itoa(example.Status) + `):</h5>
+                        ` + renderCopyablePre("", escapeHTML(exampleJSON(example.Value))))
 						}
-						if respTs := generateTypeScriptSchema(schemaBaseName+"Response", endpoint.ResponseSchema); respTs != "" {
+
+						html.WriteString(`
+                    </div>`)
+
+						// The print view leaves out the try-it form
+						if printView {
 							html.WriteString(`
-                        <h5>Response Body:</h5>
-                        <pre>` + respTs + `</pre>`)
+                </div>
+            </details>`)
+							continue
+						}
+
+						followUpAttr := ""
+						if links := an.followUpLinks(&endpoint); links != "" {
+							followUpAttr = ` data-links="` + escapeHTML(links) + `"`
+						}
+						// NDJSON routes send the request body as one JSON value per line
+						if consumesNDJSON(&endpoint) {
+							followUpAttr += ` data-ndjson="true"`
+						}
+						// CSV routes convert the JSON rows of the request body to CSV
+						if consumesCSV(&endpoint) {
+							followUpAttr += ` data-csv="true"`
 						}
 
 						html.WriteString(`
-                    </div>
                     <div class="api-test">
-                        <h4>Test API</h4>
-                        <form id="test-form-` + endpoint.Method + "-" + strings.ReplaceAll(strings.ReplaceAll(endpoint.Path, "/", "-"), ":", "_") + `" onsubmit="testApi(event, '` + endpoint.Method + `', '` + endpoint.Path + `', this)" enctype="multipart/form-data">
+                        <h4>Test API</h4>` + renderPresets(endpoint.Presets) + `
+                        <form id="` + testFormID(endpoint.Method, endpoint.Path) + `"` + followUpAttr + ` onsubmit="testApi(event, '` + endpoint.Method + `', '` + endpoint.Path + `', this)" enctype="multipart/form-data">
                             <input type="hidden" name="method" value="` + endpoint.Method + `">`)
 
 						for _, param := range endpoint.Parameters {
+							if an.isTenantParameter(&param) {
+								continue
+							}
 							inputType := "text"
 							if param.Type == "number" {
 								inputType = "number"
@@ -1072,24 +2109,45 @@ func (an *ApiNote) generateHTML() string {
 							}
 							html.WriteString(`
                             <label>` + labelText + `:</label>
-                            <input type="` + escapeHTML(inputType) + `" name="` + escapeHTML(param.Name) + `" placeholder="Enter ` + escapeHTML(param.Name) + `"` + requiredAttr + ` data-in="` + escapeHTML(param.In) + `">`)
+                            <input type="` + escapeHTML(inputType) + `" name="` + escapeHTML(param.Name) + `" placeholder="` + escapeHTML(parameterPlaceholder(&param)) + `"` + requiredAttr + ` data-in="` + escapeHTML(param.In) + `"` + parameterStyleAttrs(&param) + `>`)
+						}
+
+						html.WriteString(an.renderTryItLocale())
+
+						if endpoint.RequestSigning != nil {
+							html.WriteString(renderSignatureCalculator(endpoint.RequestSigning))
+						}
+
+						if supportsMsgPack(&endpoint) {
+							html.WriteString(`
+                            <label>Wire format:</label>
+                            <select class="wire-format">
+                                <option value="json">JSON</option>
+                                <option value="msgpack">MessagePack</option>
+                            </select>`)
 						}
 
 						if endpoint.Method == "POST" || endpoint.Method == "PUT" {
 							// Generate JSON template from request schema
 							jsonTemplate := ""
-							if endpoint.RequestSchema != nil {
-								if template, err := generateJSONTemplate(endpoint.RequestSchema); err == nil {
-									// Properly escape the JSON for HTML attribute
-									jsonTemplate = strings.ReplaceAll(template, `"`, `&quot;`)
-									jsonTemplate = strings.ReplaceAll(jsonTemplate, `'`, `&#39;`)
-									jsonTemplate = strings.ReplaceAll(jsonTemplate, `\`, `\\`)
+							if len(endpoint.RequestExamples) > 0 {
+								// Prefer the first fixture example over the generated template
+								jsonTemplate = escapeTemplateAttr(exampleJSON(endpoint.RequestExamples[0].Value))
+							} else if endpoint.RequestSchema != nil {
+								if template, err := generateJSONTemplate(endpoint.RequestSchema, an.exampleOptions()); err == nil {
+									jsonTemplate = escapeTemplateAttr(template)
 								}
 							}
 
+							bodyLabel := "Request Body (JSON)"
+							if consumesNDJSON(&endpoint) {
+								bodyLabel = "Request Body (NDJSON, one JSON value per line, or a JSON array of lines)"
+							} else if consumesCSV(&endpoint) {
+								bodyLabel = "Request Body (a JSON array of rows sent as CSV, or CSV text)"
+							}
 							if endpoint.RequestSchema != nil {
 								html.WriteString(`
-                                <label>Request Body (JSON):</label>
+                                <label>` + bodyLabel + `:</label>
                                 <div class="json-editor-container" data-template="` + jsonTemplate + `">
                                     <div class="json-editor-toolbar">
                                         <button type="button" class="json-editor-btn" onclick="formatJSON(this)">
@@ -1103,7 +2161,7 @@ func (an *ApiNote) generateHTML() string {
                                         </button>
                                         <button type="button" class="json-editor-btn" onclick="loadSchemaTemplate(this)">
                                             <i class="fas fa-file-code"></i> Load Template
-                                        </button>
+                                        </button>` + renderExampleSelect(endpoint.RequestExamples) + `
                                     </div>
                                     <textarea name="requestBody" class="json-editor" placeholder="Enter JSON request body..."></textarea>
                                     <div class="json-validation-message" style="display: none;"></div>
@@ -1111,8 +2169,9 @@ func (an *ApiNote) generateHTML() string {
 							}
 						}
 
-						html.WriteString(`
-                            <button type="submit">Test Request</button>
+						html.WriteString(an.renderCallbackRelay(&endpoint))
+
+						html.WriteString(renderAssertionsPanel(&endpoint) + an.renderTryItButton(endpoint.Method) + `
                             <pre id="test-result-` + endpoint.Method + strings.ReplaceAll(endpoint.Path, "/", "-") + `"></pre>
                         </form>
                     </div>
@@ -1125,9 +2184,10 @@ func (an *ApiNote) generateHTML() string {
 			}
 
 			// Recurse into deeper segments
-			renderSegments(child, depth+1, "segment-group")
+			renderSegments(child, depth+1, "segment-group", groupPath)
 			html.WriteString(`
     </details>`)
+			toc.WriteString(`</ul></li>`)
 		}
 	}
 
@@ -1136,26 +2196,50 @@ func (an *ApiNote) generateHTML() string {
 	for version := range versionGroups {
 		versions = append(versions, version)
 	}
-	sort.Strings(versions)
+	an.sortGroupNames(versions)
 
 	for _, version := range versions {
 		node := versionGroups[version]
+		versionID := groupAnchor([]string{version})
 		html.WriteString(`
-    <details class="version-group">
-        <summary>` + version + `</summary>`)
-		renderSegments(node, 1, "segment-group")
+    <details class="version-group" id="` + versionID + `">
+        <summary>` + version + groupToggleControls + `</summary>`)
+		toc.WriteString(`
+                <li><a href="#` + versionID + `">` + escapeHTML(version) + `</a><ul>`)
+		renderSegments(node, 1, "segment-group", []string{version})
 		html.WriteString(`
     </details>`)
+		toc.WriteString(`</ul></li>`)
 	}
 
 	// Render non-versioned groups (directly under top-level segments)
 	if len(nonVersionedRoot.Children) > 0 {
-		renderSegments(nonVersionedRoot, 0, "top-segment-group")
+		renderSegments(nonVersionedRoot, 0, "top-segment-group", nil)
 	}
 
+	// Render documented message channels
+	channelsHTML, channelsTOC := an.renderChannels()
+	html.WriteString(channelsHTML)
+	toc.WriteString(channelsTOC)
+
+	// Render the registered error codes
+	errorCodesHTML, errorCodesTOC := an.renderErrorCodes()
+	html.WriteString(errorCodesHTML)
+	toc.WriteString(errorCodesTOC)
+
+	html.WriteString(`
+      </main>
+      <nav class="docs-sidebar" aria-label="API navigation">
+            <h3>Contents</h3>
+            <ul class="toc">` + toc.String() + `
+            </ul>
+      </nav>
+      </div>` + commandPaletteMarkup)
+
 	html.WriteString(`
         <script>
             let authToken = '` + escapeJavaScript(an.config.AuthToken) + `';
+            const apiKeyHeader = '` + escapeJavaScript(an.apiKeyHeader()) + `';` + an.tenancyScript() + an.serversScript() + an.sessionScript() + an.deviceAuthScript() + an.mockPersistenceScript() + statusFilterScript + requestIDScript + an.tryItScript() + an.tryItLocaleScript() + presetsScript + an.assertionsScript() + paramStylesScript + an.embedScript(printView) + `
 
             if (!authToken) {
                 const storedToken = localStorage.getItem('authToken');
@@ -1201,8 +2285,70 @@ func (an *ApiNote) generateHTML() string {
                 if (authInput) {
                     authInput.value = authToken;
                 }
+                const tenantSelect = document.getElementById('tenant-select');
+                if (tenantSelect) {
+                    tenantSelect.value = tenant;
+                }
+                if (servers.length > 0) {
+                    setServer(serverIndex);
+                }
             };
 
+            // Compose the selected server's URL from its variables' values
+            function serverUrl() {
+                const server = servers[serverIndex] || servers[0];
+                return server.url.replace(/\{([^{}]+)\}/g, function(match, name) {
+                    const variable = (server.variables || {})[name];
+                    if (!variable) {
+                        return match;
+                    }
+                    return serverVariables[name] || variable.default;
+                });
+            }
+
+            function setServer(index) {
+                serverIndex = servers[Number(index)] ? Number(index) : 0;
+                localStorage.setItem('serverIndex', serverIndex);
+                const serverSelect = document.getElementById('server-select');
+                if (serverSelect) {
+                    serverSelect.value = serverIndex;
+                }
+                // Show the selected server's variables, dropping stored values it doesn't allow
+                const variables = servers[serverIndex].variables || {};
+                document.querySelectorAll('.server-variable').forEach(function(control) {
+                    const variable = variables[control.dataset.variable];
+                    control.style.display = variable ? '' : 'none';
+                    if (!variable) {
+                        return;
+                    }
+                    let value = serverVariables[control.dataset.variable] || variable.default;
+                    if (variable.enum && variable.enum.indexOf(value) < 0) {
+                        value = variable.default;
+                    }
+                    control.value = value;
+                    serverVariables[control.dataset.variable] = value;
+                });
+                updateServerUrl();
+            }
+
+            function setServerVariable(name, value) {
+                serverVariables[name] = value.trim();
+                localStorage.setItem('serverVariables', JSON.stringify(serverVariables));
+                updateServerUrl();
+            }
+
+            function updateServerUrl() {
+                const serverUrlElement = document.getElementById('server-url');
+                if (serverUrlElement) {
+                    serverUrlElement.textContent = serverUrl();
+                }
+            }
+
+            function setTenant(value) {
+                tenant = value.trim();
+                localStorage.setItem('tenant', tenant);
+            }
+
             function setAuthToken() {
                 const authInput = document.getElementById('auth-token');
                 authToken = authInput.value.trim();
@@ -1212,6 +2358,9 @@ func (an *ApiNote) generateHTML() string {
 
             function testApi(event, method, path, form) {
                 event.preventDefault();
+                if (!confirmTryIt(method, path)) {
+                    return;
+                }
                 const resultElement = document.getElementById('test-result-' + method + path.replace(/\//g, '-'));
                 resultElement.textContent = 'Sending request...';
 
@@ -1223,11 +2372,29 @@ func (an *ApiNote) generateHTML() string {
                 // Process form inputs
                 const inputs = form.querySelectorAll('input, textarea');
                 let modifiedPath = path; // Start with the original path
+                const requestContext = { path: {}, query: {}, header: {}, body: null };
+                // Styled parameters are serialized as documented (paramStylesScript)
+                const serializedPath = {};
+                const serializedQuery = [];
                 inputs.forEach(input => {
                     const key = input.name;
                     const value = input.value;
                     const paramIn = input.getAttribute('data-in');
 
+                    if (key && value && input.dataset.style && paramIn !== 'formData') {
+                        const serialized = serializeParam(key, value, input);
+                        if (paramIn === 'path') {
+                            serializedPath[key] = { serialized: serialized };
+                            requestContext.path[key] = value;
+                        } else if (paramIn === 'query') {
+                            serializedQuery.push(serialized);
+                            requestContext.query[key] = value;
+                        } else if (paramIn === 'header') {
+                            params[key] = serialized;
+                            requestContext.header[key.toLowerCase()] = serialized;
+                        }
+                        return;
+                    }
                     if (key && paramIn) {
                         if (paramIn === 'formData') {
                             isFormDataRequest = true;
@@ -1237,34 +2404,69 @@ func (an *ApiNote) generateHTML() string {
                                 formData.append(key, value);
                             }
                         } else if (paramIn === 'path' && value) {
-                            // Replace :key with the value in the path
-                            modifiedPath = modifiedPath.replace(':' + key, encodeURIComponent(value));
+                            requestContext.path[key] = value;
                         } else if (paramIn === 'query' && value) {
                             queryParams.append(key, value);
+                            requestContext.query[key] = value;
                         } else if (paramIn === 'header' && value) {
                             params[key] = value;
+                            requestContext.header[key.toLowerCase()] = value;
                         }
                     }
                 });
 
-                const baseUrl = 'http://' + '` + escapeJavaScript(an.config.Host) + `';
-                const url = baseUrl + modifiedPath + (queryParams.toString() ? '?' + queryParams.toString() : '');
+                // The tenancy parameter comes from the tenant selector
+                if (tenant && tenancy.path) {
+                    requestContext.path[tenancy.path] = tenant;
+                }
+                if (tenant && tenancy.header) {
+                    params[tenancy.header] = tenant;
+                    requestContext.header[tenancy.header.toLowerCase()] = tenant;
+                }
+
+                modifiedPath = buildPath(path, Object.assign({}, requestContext.path, serializedPath));
+                const queryString = [queryParams.toString()].concat(serializedQuery).filter(part => part).join('&');
+
+                const baseUrl = ` + tryItBaseURL + `;
+                const url = baseUrl + modifiedPath + (queryString ? '?' + queryString : '');
 
                 const options = {
                     method: method,
                     headers: {},
                 };
 
-                if (authToken) {
+                // Issued API keys go in their header, other tokens are bearer tokens
+                if (authToken.startsWith('` + apiKeyPrefix + `')) {
+                    options.headers[apiKeyHeader] = authToken;
+                } else if (authToken) {
                     const token = authToken.startsWith('Bearer ') ? authToken : 'Bearer ' + authToken;
                     options.headers['Authorization'] = token;
                 }
+                // The toggle of Config.MockPersistence keeps sample data per tab
+                if (mockSession) {
+                    options.headers[mockSessionHeader] = mockSession;
+                }
+                // Correlates the click with the server's log lines, unless a header parameter sets it
+                options.headers[requestIdHeader] = newRequestId();
+                // Lets the server throttle and restrict try-it traffic (Config.TryIt)
+                if (tryItHeader) {
+                    options.headers[tryItHeader] = '1';
+                }
+                // Simulates the viewer's language and timezone (Config.TryItLocale)
+                applyTryItLocale(form, options.headers, requestContext);
 
                 Object.keys(params).forEach(key => {
                     if (params[key]) {
                         options.headers[key] = params[key];
                     }
                 });
+                const requestId = options.headers[requestIdHeader];
+
+                const wireFormat = form.querySelector('.wire-format');
+                const useMsgPack = wireFormat && wireFormat.value === 'msgpack';
+                if (useMsgPack) {
+                    options.headers['Accept'] = 'application/msgpack, application/json;q=0.9';
+                }
 
                 if (isFormDataRequest) {
                     options.body = formData;
@@ -1281,11 +2483,37 @@ func (an *ApiNote) generateHTML() string {
                         }
                         
                         const bodyContent = requestBodyInput.value.trim();
-                        if (bodyContent) {
+                        if (bodyContent && form.dataset.ndjson === 'true') {
+                            try {
+                                const lines = ndjsonLines(bodyContent);
+                                requestContext.body = lines;
+                                options.headers['Content-Type'] = 'application/x-ndjson';
+                                options.body = lines.map(line => JSON.stringify(line)).join('\n') + '\n';
+                            } catch (e) {
+                                resultElement.textContent = 'Invalid NDJSON in request body: ' + e.message;
+                                return;
+                            }
+                        } else if (bodyContent && form.dataset.csv === 'true') {
+                            options.headers['Content-Type'] = 'text/csv';
+                            try {
+                                const rows = JSON.parse(bodyContent);
+                                requestContext.body = rows;
+                                options.body = rowsToCSV(Array.isArray(rows) ? rows : [rows]);
+                            } catch (e) {
+                                // Not JSON: send the text as CSV
+                                options.body = bodyContent + '\n';
+                            }
+                        } else if (bodyContent) {
                             try {
                                 const jsonBody = JSON.parse(bodyContent);
-                                options.headers['Content-Type'] = 'application/json';
-                                options.body = JSON.stringify(jsonBody);
+                                requestContext.body = jsonBody;
+                                if (useMsgPack) {
+                                    options.headers['Content-Type'] = 'application/msgpack';
+                                    options.body = msgpackEncode(jsonBody);
+                                } else {
+                                    options.headers['Content-Type'] = 'application/json';
+                                    options.body = JSON.stringify(jsonBody);
+                                }
                             } catch (e) {
                                 resultElement.textContent = 'Invalid JSON in request body: ' + e.message;
                                 return;
@@ -1295,7 +2523,14 @@ func (an *ApiNote) generateHTML() string {
                     }
                 }
 
-                fetch(url, options)
+                const pathAndQuery = modifiedPath + (queryString ? '?' + queryString : '');
+                // The exported Go test sends the last request
+                form.dataset.target = pathAndQuery;
+                const signer = form.querySelector('.signature-calculator');
+                const prepared = signer ? signRequest(signer, method, pathAndQuery, options) : Promise.resolve();
+
+                prepared
+                    .then(() => fetch(url, options))
                     .then(response => {
                         const contentType = response.headers.get('content-type') || '';
                         const disposition = response.headers.get('content-disposition') || '';
@@ -1313,7 +2548,16 @@ func (an *ApiNote) generateHTML() string {
                             headers[key] = value;
                         }
 
-                        if (!response.ok) {
+                        if (response.ok && contentType.includes('ndjson') && response.body) {
+                            return streamNDJSON(response, resultElement, url).then(text => ({
+                                status: response.status,
+                                statusText: response.statusText,
+                                body: text,
+                                contentType: contentType,
+                                headers: headers,
+                                isStreamed: true
+                            }));
+                        } else if (!response.ok) {
                             return response.text().then(text => ({
                                 status: response.status,
                                 statusText: response.statusText,
@@ -1322,6 +2566,15 @@ func (an *ApiNote) generateHTML() string {
                                 headers: headers,
                                 isError: true
                             }));
+                        } else if (contentType.includes('msgpack')) {
+                            return response.arrayBuffer().then(buffer => ({
+                                status: response.status,
+                                statusText: response.statusText,
+                                body: JSON.stringify(msgpackDecode(buffer), null, 2),
+                                contentType: contentType,
+                                headers: headers,
+                                isMsgPack: true
+                            }));
                         } else if (contentType.includes('application/json')) {
                             return response.json().then(data => ({
                                 status: response.status,
@@ -1330,6 +2583,15 @@ func (an *ApiNote) generateHTML() string {
                                 contentType: contentType,
                                 headers: headers
                             }));
+                        } else if (contentType.includes('text/csv')) {
+                            return response.text().then(text => ({
+                                status: response.status,
+                                statusText: response.statusText,
+                                body: text,
+                                contentType: contentType,
+                                headers: headers,
+                                isCSV: true
+                            }));
                         } else if (contentType.startsWith('image/')) {
                             return response.blob().then(blob => ({
                                 status: response.status,
@@ -1353,7 +2615,13 @@ func (an *ApiNote) generateHTML() string {
                         }
                     })
                     .then(result => {
-                        resultElement.innerHTML = "Url: " + url + "<br>Status: " + result.status + " " + result.statusText + "<br>";
+                        // Streamed lines are already displayed
+                        if (result.isStreamed) {
+                            renderAssertions(form, result);
+                            renderFollowUps(form, resultElement, result, requestContext);
+                            return;
+                        }
+                        resultElement.innerHTML = "Url: " + url + "<br>" + renderRequestId(requestId) + "Status: " + result.status + " " + result.statusText + "<br>";
                         
                         // Display response headers
                         if (result.headers && Object.keys(result.headers).length > 0) {
@@ -1364,10 +2632,15 @@ func (an *ApiNote) generateHTML() string {
                             }
                         }
                         
+                        // Display the Server-Timing breakdown when the server reports one
+                        if (result.headers && result.headers['server-timing']) {
+                            resultElement.innerHTML += renderServerTiming(result.headers['server-timing']);
+                        }
+
                         resultElement.innerHTML += "<br>";
                         
                         if (result.isError) {
-                            resultElement.innerHTML += '<strong>Error Response:</strong><br><pre>' + escapeHtml(result.body) + '</pre>';
+                            resultElement.innerHTML += '<strong>Error Response:</strong><br>' + copyablePre(escapeHtml(result.body));
                         } else if (result.isImage) {
                             const imgUrl = URL.createObjectURL(result.body);
                             resultElement.innerHTML += '<strong>Response (Image):</strong><br><img src="' + imgUrl + '" style="max-width: 100%;" onload="setTimeout(() => URL.revokeObjectURL(this.src), 10000)">';
@@ -1400,13 +2673,21 @@ func (an *ApiNote) generateHTML() string {
                                 // Revoke object URL after download is triggered with a longer delay
                                 setTimeout(() => URL.revokeObjectURL(blobUrl), 5000);
                             }
+                        } else if (result.isCSV) {
+                            resultElement.innerHTML += '<strong>Response Body (CSV preview):</strong><br>' + renderCSVPreview(result.body);
+                            resultElement.innerHTML += '<strong>Raw CSV:</strong><br>' + copyablePre(escapeHtml(result.body));
+                        } else if (result.isMsgPack) {
+                            resultElement.innerHTML += '<strong>Response Body (decoded MessagePack):</strong><br>' + copyablePre(escapeHtml(result.body));
                         } else {
-                            resultElement.innerHTML += '<strong>Response Body:</strong><br><pre>' + escapeHtml(result.body) + '</pre>';
+                            resultElement.innerHTML += '<strong>Response Body:</strong><br>' + copyablePre(escapeHtml(result.body));
                         }
+
+                        renderAssertions(form, result);
+                        renderFollowUps(form, resultElement, result, requestContext);
                     })
                     .catch(error => {
                         console.error('Fetch error:', error);
-                        resultElement.innerHTML = '<strong>Error:</strong><br><pre style="color: var(--danger);">' + escapeHtml(error.message) + '</pre>';
+                        resultElement.innerHTML = renderRequestId(requestId) + '<strong>Error:</strong><br>' + copyablePre(escapeHtml(error.message), ' style="color: var(--danger);"');
                         
                         // Provide more detailed error information
                         if (error.name === 'TypeError' && error.message.includes('fetch')) {
@@ -1417,6 +2698,433 @@ func (an *ApiNote) generateHTML() string {
                     });
             }
 
+            // Parse CSV text into rows of cells, handling quoted cells with
+            // commas, doubled quotes and line breaks
+            function parseCSV(text) {
+                const rows = [];
+                let row = [];
+                let cell = '';
+                let quoted = false;
+                for (let i = 0; i < text.length; i++) {
+                    const ch = text[i];
+                    if (quoted) {
+                        if (ch === '"' && text[i + 1] === '"') {
+                            cell += '"';
+                            i++;
+                        } else if (ch === '"') {
+                            quoted = false;
+                        } else {
+                            cell += ch;
+                        }
+                    } else if (ch === '"') {
+                        quoted = true;
+                    } else if (ch === ',') {
+                        row.push(cell);
+                        cell = '';
+                    } else if (ch === '\n' || ch === '\r') {
+                        if (ch === '\r' && text[i + 1] === '\n') {
+                            i++;
+                        }
+                        row.push(cell);
+                        rows.push(row);
+                        row = [];
+                        cell = '';
+                    } else {
+                        cell += ch;
+                    }
+                }
+                if (cell || row.length) {
+                    row.push(cell);
+                    rows.push(row);
+                }
+                return rows;
+            }
+
+            // Convert JSON rows to CSV with a header row of their keys; nested
+            // values are JSON-encoded
+            function rowsToCSV(rows) {
+                const columns = [];
+                rows.forEach(row => Object.keys(row || {}).forEach(key => {
+                    if (!columns.includes(key)) {
+                        columns.push(key);
+                    }
+                }));
+                const quote = value => {
+                    if (value === null || value === undefined) {
+                        return '';
+                    }
+                    const text = typeof value === 'object' ? JSON.stringify(value) : String(value);
+                    return /[",\r\n]/.test(text) ? '"' + text.replace(/"/g, '""') + '"' : text;
+                };
+                return [columns.map(quote).join(',')]
+                    .concat(rows.map(row => columns.map(column => quote((row || {})[column])).join(',')))
+                    .join('\n') + '\n';
+            }
+
+            // Render the first rows of a CSV response as a table
+            function renderCSVPreview(text, limit = 50) {
+                const rows = parseCSV(text);
+                if (rows.length === 0) {
+                    return '<p>(empty)</p>';
+                }
+                let html = '<table class="csv-preview"><thead><tr>' + rows[0].map(cell => '<th>' + escapeHtml(cell) + '</th>').join('') + '</tr></thead><tbody>';
+                rows.slice(1, limit + 1).forEach(row => {
+                    html += '<tr>' + row.map(cell => '<td>' + escapeHtml(cell) + '</td>').join('') + '</tr>';
+                });
+                html += '</tbody></table>';
+                if (rows.length - 1 > limit) {
+                    html += '<p>Showing ' + limit + ' of ' + (rows.length - 1) + ' rows.</p>';
+                }
+                return html;
+            }
+
+            // Parse an NDJSON request body: a JSON array is sent as one line per
+            // element and a single JSON value as one line; otherwise each
+            // non-blank line must be a JSON value.
+            function ndjsonLines(text) {
+                try {
+                    const value = JSON.parse(text);
+                    return Array.isArray(value) ? value : [value];
+                } catch (e) {
+                    return text.split('\n').filter(line => line.trim()).map((line, i) => {
+                        try {
+                            return JSON.parse(line);
+                        } catch (lineError) {
+                            throw new Error('line ' + (i + 1) + ': ' + lineError.message);
+                        }
+                    });
+                }
+            }
+
+            // Display the lines of a streamed NDJSON response as they arrive,
+            // resolving with the whole body once the stream ends
+            async function streamNDJSON(response, resultElement, url) {
+                resultElement.innerHTML = "Url: " + url + "<br>Status: " + response.status + " " + response.statusText + "<br><br><strong>Streamed lines:</strong><br>";
+                const output = document.createElement('pre');
+                output.className = 'ndjson-stream';
+                resultElement.appendChild(output);
+                const status = document.createElement('div');
+                status.className = 'ndjson-stream-status';
+                status.textContent = 'Receiving...';
+                resultElement.appendChild(status);
+
+                const reader = response.body.getReader();
+                const decoder = new TextDecoder();
+                let buffered = '';
+                let text = '';
+                let count = 0;
+                const show = line => {
+                    if (!line.trim()) {
+                        return;
+                    }
+                    count++;
+                    let display = line;
+                    try {
+                        display = JSON.stringify(JSON.parse(line));
+                    } catch (e) {
+                        // Show lines that aren't JSON as they are
+                    }
+                    output.textContent += display + '\n';
+                    status.textContent = 'Receiving... ' + count + ' line' + (count === 1 ? '' : 's');
+                };
+                for (;;) {
+                    const { done, value } = await reader.read();
+                    if (done) {
+                        break;
+                    }
+                    const chunk = decoder.decode(value, { stream: true });
+                    text += chunk;
+                    buffered += chunk;
+                    const lines = buffered.split('\n');
+                    buffered = lines.pop();
+                    lines.forEach(show);
+                }
+                const tail = decoder.decode();
+                text += tail;
+                show(buffered + tail);
+                status.textContent = 'Stream ended after ' + count + ' line' + (count === 1 ? '' : 's');
+                return text;
+            }
+
+            // Substitute path parameter values into a Fiber route pattern. Optional
+            // segments (:name?) and wildcards (*) left empty are dropped; wildcard
+            // values keep their slashes.
+            function buildPath(path, values) {
+                return path.replace(/(\/?)(?::(\w+)(\?)?|([*+])(\d*))/g, (match, slash, name, optional, wildcard, index) => {
+                    if (name) {
+                        const value = values[name];
+                        if (!value) {
+                            return optional ? '' : match;
+                        }
+                        // Styled values come serialized (serializeParam)
+                        if (value.serialized !== undefined) {
+                            return slash + value.serialized;
+                        }
+                        return slash + encodeURIComponent(value);
+                    }
+                    const value = values[wildcard + index];
+                    if (!value) {
+                        return wildcard === '*' ? slash : match;
+                    }
+                    return slash + value.split('/').map(encodeURIComponent).join('/');
+                });
+            }
+
+            // Sign the request in the browser using the HMAC request-signing scheme
+            async function signRequest(signer, method, pathAndQuery, options) {
+                const keyId = signer.querySelector('.signing-key-id').value.trim();
+                const secret = signer.querySelector('.signing-secret').value;
+                if (!keyId || !secret) {
+                    return;
+                }
+
+                const encoder = new TextEncoder();
+                let body = new Uint8Array();
+                if (typeof options.body === 'string') {
+                    body = encoder.encode(options.body);
+                } else if (options.body instanceof Uint8Array) {
+                    body = options.body;
+                }
+                const timestamp = Math.floor(Date.now() / 1000).toString();
+                const bodyHash = await crypto.subtle.digest('SHA-256', body);
+                const canonical = method.toUpperCase() + '\n' + pathAndQuery + '\n' + timestamp + '\n' + toHex(bodyHash);
+                const key = await crypto.subtle.importKey('raw', encoder.encode(secret), { name: 'HMAC', hash: 'SHA-256' }, false, ['sign']);
+                const signature = toHex(await crypto.subtle.sign('HMAC', key, encoder.encode(canonical)));
+
+                options.headers[signer.dataset.keyHeader] = keyId;
+                options.headers[signer.dataset.timestampHeader] = timestamp;
+                options.headers[signer.dataset.signatureHeader] = signature;
+                signer.querySelector('.signature-preview').textContent = 'Canonical string:\n' + canonical + '\n\nSignature: ' + signature;
+            }
+
+` + msgpackScript + `
+` + callbackRelayScript + `
+` + commandPaletteScript + `
+` + quickAccessScript + `
+` + schemaViewsScript + `
+` + copyScript + `
+            // Render a Server-Timing header as a list of phase bars relative to the total
+            function renderServerTiming(header) {
+                const phases = header.split(',').map(part => {
+                    const fields = part.trim().split(';');
+                    const dur = fields.find(f => f.trim().startsWith('dur='));
+                    return { name: fields[0].trim(), dur: dur ? parseFloat(dur.trim().substring(4)) : 0 };
+                });
+                const total = phases.find(p => p.name === 'total');
+                const max = total ? total.dur : Math.max(...phases.map(p => p.dur), 0);
+
+                let out = '<br><strong>Server Timing:</strong><div class="server-timing">';
+                phases.forEach(phase => {
+                    const width = max > 0 ? Math.max(1, Math.round(phase.dur / max * 100)) : 0;
+                    out += '<div class="server-timing-row"><span class="server-timing-name">' + escapeHtml(phase.name) +
+                        '</span><span class="server-timing-bar" style="width: ' + width + '%"></span><span class="server-timing-dur">' +
+                        phase.dur.toFixed(3) + ' ms</span></div>';
+                });
+                return out + '</div>';
+            }
+
+            // Render follow-up call buttons for the endpoint's links that apply to the response
+            function renderFollowUps(form, resultElement, result, requestContext) {
+                if (!form.dataset.links || result.isError) {
+                    return;
+                }
+
+                let responseBody = null;
+                if (typeof result.body === 'string') {
+                    try {
+                        responseBody = JSON.parse(result.body);
+                    } catch (e) {
+                        responseBody = null;
+                    }
+                }
+                const context = {
+                    statusCode: result.status,
+                    request: requestContext,
+                    response: { header: result.headers || {}, body: responseBody }
+                };
+
+                const links = JSON.parse(form.dataset.links).filter(link => !link.status || link.status === String(result.status));
+                if (links.length === 0) {
+                    return;
+                }
+
+                const container = document.createElement('div');
+                container.className = 'follow-ups';
+                container.innerHTML = '<strong>Follow-up calls:</strong>';
+                links.forEach(link => {
+                    const values = {};
+                    Object.entries(link.parameters || {}).forEach(([name, expression]) => {
+                        values[name] = evaluateLinkExpression(expression, context);
+                    });
+
+                    const button = document.createElement('button');
+                    button.type = 'button';
+                    button.className = 'follow-up-btn';
+                    button.textContent = link.method + ' ' + link.path + ' (' + link.rel + ')';
+                    button.title = Object.entries(values).map(([name, value]) => name + ' = ' + value).join(', ');
+                    button.addEventListener('click', () => followUp(link, values));
+                    container.appendChild(button);
+                });
+                resultElement.appendChild(container);
+            }
+
+            // Evaluate an OpenAPI runtime expression against the previous call
+            function evaluateLinkExpression(expression, context) {
+                if (typeof expression !== 'string' || !expression.startsWith('$')) {
+                    return expression;
+                }
+                if (expression === '$statusCode') {
+                    return context.statusCode;
+                }
+
+                const hashIndex = expression.indexOf('#');
+                const source = hashIndex >= 0 ? expression.substring(0, hashIndex) : expression;
+                const pointer = hashIndex >= 0 ? expression.substring(hashIndex + 1) : '';
+                if (source === '$response.body') {
+                    return resolveJsonPointer(context.response.body, pointer);
+                }
+                if (source === '$request.body') {
+                    return resolveJsonPointer(context.request.body, pointer);
+                }
+
+                const match = source.match(/^\$(request|response)\.(path|query|header)\.(.+)$/);
+                if (!match) {
+                    return undefined;
+                }
+                const bag = match[1] === 'request' ? context.request[match[2]] : context.response[match[2]];
+                if (!bag) {
+                    return undefined;
+                }
+                return match[2] === 'header' ? bag[match[3].toLowerCase()] : bag[match[3]];
+            }
+
+            // Resolve an RFC 6901 JSON pointer
+            function resolveJsonPointer(value, pointer) {
+                if (!pointer) {
+                    return value;
+                }
+                return pointer.split('/').slice(1).reduce((current, token) => {
+                    if (current === null || current === undefined) {
+                        return undefined;
+                    }
+                    return current[token.replace(/~1/g, '/').replace(/~0/g, '~')];
+                }, value);
+            }
+
+            // Fill the target operation's try-it form with the chained values and send it
+            function followUp(link, values) {
+                const target = document.getElementById(link.target);
+                if (!target) {
+                    return;
+                }
+                const operation = target.closest('details.method-group');
+                if (operation) {
+                    openOperation(operation.id);
+                }
+
+                Object.entries(values).forEach(([name, value]) => {
+                    const input = target.querySelector('[name="' + CSS.escape(name) + '"]');
+                    if (input && value !== undefined && value !== null) {
+                        input.value = typeof value === 'object' ? JSON.stringify(value) : String(value);
+                    }
+                });
+                target.requestSubmit();
+            }
+
+            // Sidebar navigation: open targets on click and highlight the entry in view
+            document.addEventListener('DOMContentLoaded', function() {
+                const links = document.querySelectorAll('.docs-sidebar a[href^="#"]');
+                const byId = {};
+                links.forEach(link => {
+                    const id = link.getAttribute('href').slice(1);
+                    byId[id] = link;
+                    link.addEventListener('click', function(e) {
+                        e.preventDefault();
+                        openOperation(id);
+                        history.replaceState(null, '', '#' + id);
+                    });
+                });
+
+                if (!('IntersectionObserver' in window)) {
+                    return;
+                }
+                const visible = new Set();
+                const observer = new IntersectionObserver(entries => {
+                    entries.forEach(entry => {
+                        if (entry.isIntersecting) {
+                            visible.add(entry.target);
+                        } else {
+                            visible.delete(entry.target);
+                        }
+                    });
+
+                    // Highlight the top-most visible endpoint, or the innermost visible group
+                    const elements = Array.from(visible).sort((a, b) => a.getBoundingClientRect().top - b.getBoundingClientRect().top);
+                    const endpoints = elements.filter(element => element.classList.contains('method-group'));
+                    const current = endpoints.length > 0 ? endpoints[0] : elements[elements.length - 1];
+                    links.forEach(link => link.classList.remove('active'));
+                    if (current && byId[current.id]) {
+                        byId[current.id].classList.add('active');
+                        byId[current.id].scrollIntoView({ block: 'nearest' });
+                    }
+                }, { rootMargin: '0px 0px -60% 0px' });
+
+                Object.keys(byId).forEach(id => {
+                    const target = document.getElementById(id);
+                    if (target) {
+                        observer.observe(target);
+                    }
+                });
+            });
+
+            // Expand every group while printing and restore the previous state afterwards
+            let detailsBeforePrint = [];
+            window.addEventListener('beforeprint', function() {
+                detailsBeforePrint = Array.from(document.querySelectorAll('.docs-main details')).filter(details => !details.open);
+                detailsBeforePrint.forEach(details => {
+                    details.open = true;
+                });
+            });
+            window.addEventListener('afterprint', function() {
+                detailsBeforePrint.forEach(details => {
+                    details.open = false;
+                });
+                detailsBeforePrint = [];
+            });
+
+            // Expand or collapse every nested group and endpoint under a version or tag
+            function toggleGroup(event, button, open) {
+                event.preventDefault();
+                event.stopPropagation();
+                const group = button.closest('details');
+                group.open = true;
+                group.querySelectorAll('details').forEach(details => {
+                    details.open = open;
+                });
+            }
+
+            // Open an endpoint's docs entry (and its enclosing groups) and scroll to it
+            function openOperation(id) {
+                let element = document.getElementById(id);
+                const target = element;
+                while (element) {
+                    if (element.tagName === 'DETAILS') {
+                        element.open = true;
+                    }
+                    element = element.parentElement;
+                }
+                if (target) {
+                    target.scrollIntoView({ behavior: 'smooth', block: 'start' });
+                    if (target.classList.contains('method-group')) {
+                        recordRecentlyViewed(id);
+                    }
+                }
+            }
+
+            function toHex(buffer) {
+                return Array.from(new Uint8Array(buffer)).map(b => b.toString(16).padStart(2, '0')).join('');
+            }
+
             function escapeHtml(unsafe) {
                 if (typeof unsafe !== 'string') return unsafe;
                 return unsafe
@@ -1571,6 +3279,20 @@ func (an *ApiNote) generateHTML() string {
                 }
             }
 
+            function loadExample(select) {
+                if (!select.value) {
+                    return;
+                }
+                const editor = getEditorFromButton(select);
+                try {
+                    editor.setValue(JSON.stringify(JSON.parse(select.value), null, 2));
+                    showValidationMessage(select, 'Example "' + select.options[select.selectedIndex].text + '" loaded', 'success');
+                } catch (e) {
+                    showValidationMessage(select, 'Invalid example: ' + e.message, 'error');
+                }
+                select.value = '';
+            }
+
             function loadDefaultTemplate(editor, method) {
                 // Auto-load templates based on the schema
                 const container = editor.getTextArea().closest('.json-editor-container');
@@ -1627,10 +3349,56 @@ func (an *ApiNote) generateHTML() string {
             });
         </script>
     </div>
+    ` + an.renderDocsTemplate("footer", printView, locale) + `
 </body>
 </html>`)
 
-	return html.String()
+	page := html.String()
+	if an.config.Accessible {
+		page = accessibleHTML(page)
+	}
+	return an.hooks.runDocsRendered(page)
+}
+
+// renderDocsChrome renders the header and the authorization section of the
+// docs page, left out in embed mode
+func (an *ApiNote) renderDocsChrome(printView bool) string {
+	if an.embedded() && !printView {
+		return ""
+	}
+	return `
+        <div class="header">
+            <h1>` + escapeHTML(an.config.Title) + `</h1>
+            <p class="subtitle">` + escapeHTML(an.config.Description) + `</p>
+            <span class="version-badge">` + escapeHTML(an.config.Version) + `</span>
+        </div>
+
+        <div class="auth-section">
+            <h2><i class="fas fa-key"></i> Authorize</h2>
+            <div class="auth-input-group">
+                <input type="text" id="auth-token" placeholder="Enter JWT Bearer Token (e.g., Bearer eyJ...)" value="` + escapeHTML(an.config.AuthToken) + `">
+                <button onclick="setAuthToken()">Set Token</button>
+            </div>` + an.renderDeviceAuth() + `
+        </div>` + an.renderSessionLogin()
+}
+
+// renderExampleSelect renders the try-it picker for fixture request examples
+func renderExampleSelect(examples []RequestExample) string {
+	if len(examples) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`
+                                        <select class="json-editor-btn" onchange="loadExample(this)">
+                                            <option value="">Examples…</option>`)
+	for _, example := range examples {
+		sb.WriteString(`
+                                            <option value="` + escapeHTML(exampleJSON(example.Value)) + `">` + escapeHTML(example.Name) + `</option>`)
+	}
+	sb.WriteString(`
+                                        </select>`)
+	return sb.String()
 }
 
 // pluralize returns "s" if count > 1, empty string otherwise
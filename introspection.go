@@ -0,0 +1,176 @@
+package notelink
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxResolveDepth bounds the nesting of resolved schemas
+const maxResolveDepth = 32
+
+// EndpointDescriptor describes a documented operation as the spec does, for
+// tools and tests introspecting the API without parsing the spec. Its schemas
+// have their component references resolved, except for recursive ones, which
+// keep their $ref.
+type EndpointDescriptor struct {
+	Method       string
+	Path         string // Spec path, e.g. /users/{id}
+	Route        string // Fiber route, including Config.BasePath, e.g. /v1/users/:id
+	OperationID  string
+	Summary      string
+	Description  string
+	Tags         []string
+	Deprecated   bool
+	AuthRequired bool
+	Parameters   []ParameterSpec
+	// RequestBody is the schema of the request body, nil for routes without one
+	RequestBody *BodyDescriptor
+	// Responses are keyed by status code, e.g. "200", or "default"
+	Responses map[string]ResponseDescriptor
+}
+
+// BodyDescriptor is the resolved schema of a request or response body
+type BodyDescriptor struct {
+	ContentTypes []string // Sorted, e.g. application/json
+	// Schema is the schema of the application/json content if documented,
+	// else of the first content type
+	Schema   *JSONSchema
+	Required bool
+}
+
+// ResponseDescriptor describes a documented response
+type ResponseDescriptor struct {
+	Description string
+	Body        *BodyDescriptor // nil for responses without content
+}
+
+// Endpoints returns the documented operations in docs order, as the spec
+// generated now describes them. The descriptors are copies: changing them
+// affects neither the API nor later calls.
+func (an *ApiNote) Endpoints() []EndpointDescriptor {
+	spec := an.GenerateOpenAPISpec()
+	endpoints := an.docsEndpoints()
+	an.sortEndpoints(endpoints)
+
+	descriptors := make([]EndpointDescriptor, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		specPath := openAPIPath(endpoint.routePath())
+		pathItem, ok := spec.Paths[specPath]
+		if !ok {
+			continue
+		}
+		operation := pathItem.operation(endpoint.Method)
+		if operation == nil {
+			continue
+		}
+		descriptor := EndpointDescriptor{
+			Method:       strings.ToUpper(endpoint.Method),
+			Path:         specPath,
+			Route:        endpoint.Path,
+			OperationID:  operation.OperationID,
+			Summary:      operation.Summary,
+			Description:  operation.Description,
+			Tags:         append([]string(nil), operation.Tags...),
+			Deprecated:   operation.Deprecated,
+			AuthRequired: endpoint.AuthRequired,
+			Responses:    make(map[string]ResponseDescriptor, len(operation.Responses)),
+		}
+		for _, param := range operation.Parameters {
+			param.Schema = resolveSchema(spec, param.Schema, nil)
+			if param.Explode != nil {
+				explode := *param.Explode
+				param.Explode = &explode
+			}
+			descriptor.Parameters = append(descriptor.Parameters, param)
+		}
+		if operation.RequestBody != nil {
+			descriptor.RequestBody = bodyDescriptor(spec, operation.RequestBody.Content)
+			if descriptor.RequestBody != nil {
+				descriptor.RequestBody.Required = operation.RequestBody.Required
+			}
+		}
+		for status, response := range operation.Responses {
+			descriptor.Responses[status] = ResponseDescriptor{
+				Description: response.Description,
+				Body:        bodyDescriptor(spec, response.Content),
+			}
+		}
+		descriptors = append(descriptors, descriptor)
+	}
+	return descriptors
+}
+
+// bodyDescriptor describes the content of a body, or returns nil without one
+func bodyDescriptor(spec *OpenAPISpec, content map[string]MediaType) *BodyDescriptor {
+	if len(content) == 0 {
+		return nil
+	}
+	body := &BodyDescriptor{}
+	for contentType := range content {
+		body.ContentTypes = append(body.ContentTypes, contentType)
+	}
+	sort.Strings(body.ContentTypes)
+	media, ok := content["application/json"]
+	if !ok {
+		media = content[body.ContentTypes[0]]
+	}
+	body.Schema = resolveSchema(spec, media.Schema, nil)
+	return body
+}
+
+// resolveSchema returns a deep copy of a schema with its component references
+// replaced by the components. References to a component being resolved, i.e.
+// recursive ones, are kept.
+func resolveSchema(spec *OpenAPISpec, schema *JSONSchema, resolving []string) *JSONSchema {
+	if schema == nil {
+		return nil
+	}
+	if len(resolving) > maxResolveDepth {
+		copied := *schema
+		return &copied
+	}
+	if name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/"); ok && spec.Components != nil {
+		component, found := spec.Components.Schemas[name]
+		for _, parent := range resolving {
+			if parent == name {
+				found = false
+			}
+		}
+		if found {
+			// Siblings of the reference, e.g. nullable on a pointer field, still apply
+			resolved := resolveSchema(spec, component, append(resolving, name))
+			resolved.Nullable = resolved.Nullable || schema.Nullable
+			resolved.Deprecated = resolved.Deprecated || schema.Deprecated
+			if schema.Description != "" {
+				resolved.Description = schema.Description
+			}
+			return resolved
+		}
+	}
+
+	resolved := *schema
+	if schema.Properties != nil {
+		resolved.Properties = make(map[string]*JSONSchema, len(schema.Properties))
+		for name, property := range schema.Properties {
+			resolved.Properties[name] = resolveSchema(spec, property, resolving)
+		}
+	}
+	resolved.Items = resolveSchema(spec, schema.Items, resolving)
+	if additional, ok := schema.AdditionalProperties.(*JSONSchema); ok {
+		resolved.AdditionalProperties = resolveSchema(spec, additional, resolving)
+	}
+	if schema.OneOf != nil {
+		resolved.OneOf = make([]*JSONSchema, len(schema.OneOf))
+		for i, variant := range schema.OneOf {
+			resolved.OneOf[i] = resolveSchema(spec, variant, resolving)
+		}
+	}
+	if schema.Minimum != nil {
+		minimum := *schema.Minimum
+		resolved.Minimum = &minimum
+	}
+	resolved.Required = append([]string(nil), schema.Required...)
+	resolved.Enum = append([]interface{}(nil), schema.Enum...)
+	resolved.Extensions = copyExtensions(schema.Extensions)
+	return &resolved
+}
@@ -0,0 +1,102 @@
+package notelink
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type introspectionAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type introspectionUser struct {
+	Name    string               `json:"name" validate:"required"`
+	Address introspectionAddress `json:"address"`
+	Manager *introspectionUser   `json:"manager,omitempty"`
+	Reports []introspectionUser  `json:"reports,omitempty"`
+	Tags    map[string]string    `json:"tags,omitempty"`
+}
+
+// TestEndpoints tests introspecting the documented operations
+func TestEndpoints(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return nil }
+	authRequired := true
+	api := NewApiNote(&Config{Title: "Test API", BasePath: "/v1"}, "secret")
+	routes := []DocumentedRouteInput{
+		{Method: "POST", Path: "/users", Description: "Create a user", Handler: handler, SchemasRequest: introspectionUser{}, SchemasResponse: introspectionUser{}, Responses: map[string]string{"201": "Created"}},
+		{Method: "GET", Path: "/users/:id", Description: "Get a user", Handler: handler, SchemasResponse: introspectionUser{}, Responses: map[string]string{"200": "OK"}, AuthRequired: &authRequired, Params: []Parameter{{Name: "id", In: "path", Type: "integer", Required: true}}},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	endpoints := api.Endpoints()
+	if len(endpoints) != 2 {
+		t.Fatalf("Expected 2 endpoints, got %d", len(endpoints))
+	}
+	spec := api.GenerateOpenAPISpec()
+	for _, endpoint := range endpoints {
+		pathItem := spec.Paths[endpoint.Path]
+		operation := pathItem.operation(endpoint.Method)
+		if operation == nil || operation.OperationID != endpoint.OperationID {
+			t.Errorf("Expected %s %s to carry the spec's operationId, got %q", endpoint.Method, endpoint.Path, endpoint.OperationID)
+		}
+	}
+
+	get := endpoints[0]
+	if get.Method != "GET" {
+		get = endpoints[1]
+	}
+	if get.Path != "/users/{id}" || get.Route != "/v1/users/:id" || !get.AuthRequired || get.Summary != "Get a user" {
+		t.Errorf("Unexpected descriptor %+v", get)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0].Schema.Type != "integer" || !get.Parameters[0].Required {
+		t.Errorf("Expected the id path parameter, got %+v", get.Parameters)
+	}
+
+	user := get.Responses["200"].Body
+	if user == nil || user.ContentTypes[0] != "application/json" || user.Schema.Ref != "" {
+		t.Fatalf("Expected the resolved JSON response schema, got %+v", user)
+	}
+	address := user.Schema.Properties["address"]
+	if address == nil || address.Ref != "" || address.Properties["city"] == nil || len(address.Required) != 1 {
+		t.Errorf("Expected the address component resolved, got %+v", address)
+	}
+	if manager := user.Schema.Properties["manager"]; manager == nil || manager.Ref == "" {
+		t.Errorf("Expected the recursive manager reference kept, got %+v", manager)
+	}
+	if reports := user.Schema.Properties["reports"]; reports == nil || reports.Items == nil || reports.Items.Ref == "" {
+		t.Errorf("Expected the recursive reports reference kept, got %+v", reports)
+	}
+
+	var post EndpointDescriptor
+	for _, endpoint := range endpoints {
+		if endpoint.Method == "POST" {
+			post = endpoint
+		}
+	}
+	if post.RequestBody == nil || post.RequestBody.Schema.Properties["name"] == nil {
+		t.Errorf("Expected the resolved request body, got %+v", post.RequestBody)
+	}
+	if post.Responses["201"].Description != "Created" {
+		t.Errorf("Expected the documented responses, got %+v", post.Responses)
+	}
+
+	// Descriptors are copies
+	address.Properties["city"].Type = "integer"
+	get.Parameters[0].Name = "changed"
+	again := api.Endpoints()
+	for _, endpoint := range again {
+		if endpoint.Method == "GET" {
+			if endpoint.Parameters[0].Name != "id" || endpoint.Responses["200"].Body.Schema.Properties["address"].Properties["city"].Type != "string" {
+				t.Error("Expected changes to descriptors not to leak into later calls")
+			}
+		}
+	}
+	if spec := api.GenerateOpenAPISpec(); spec.Components.Schemas["introspectionAddress"].Properties["city"].Type != "string" {
+		t.Error("Expected changes to descriptors not to leak into the spec")
+	}
+}
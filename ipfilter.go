@@ -0,0 +1,154 @@
+package notelink
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ipFilterDescription documents the 403 response of filtered routes
+const ipFilterDescription = "Client IP address not allowed"
+
+// IPFilter restricts the client IP addresses a route accepts. Entries are
+// addresses or CIDR ranges, e.g. "10.0.0.0/8" or "2001:db8::1".
+//
+// The client address is the connection's remote address. When it belongs to
+// TrustedProxies, X-Forwarded-For is read from right to left, skipping
+// trusted proxies, and the first other address is the client's; the header
+// is ignored for connections from other addresses, so clients can't spoof it.
+type IPFilter struct {
+	Allow          []string `json:"allow,omitempty"`          // Only these clients are accepted; empty accepts all
+	Deny           []string `json:"deny,omitempty"`           // These clients are rejected, even when allowed
+	TrustedProxies []string `json:"trustedProxies,omitempty"` // Reverse proxies whose X-Forwarded-For is trusted
+}
+
+// compiledIPFilter is an IPFilter with parsed ranges
+type compiledIPFilter struct {
+	allow, deny, trusted []netip.Prefix
+}
+
+// compile parses the filter's ranges
+func (f *IPFilter) compile() (*compiledIPFilter, error) {
+	var compiled compiledIPFilter
+	var err error
+	if compiled.allow, err = parsePrefixes(f.Allow); err != nil {
+		return nil, fmt.Errorf("allow list: %w", err)
+	}
+	if compiled.deny, err = parsePrefixes(f.Deny); err != nil {
+		return nil, fmt.Errorf("deny list: %w", err)
+	}
+	if compiled.trusted, err = parsePrefixes(f.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	return &compiled, nil
+}
+
+// parsePrefixes parses addresses and CIDR ranges
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// containsAddr reports whether any prefix contains addr
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAddr returns the client's address, honoring X-Forwarded-For of trusted proxies
+func (f *compiledIPFilter) clientAddr(c fiber.Ctx) (netip.Addr, bool) {
+	addr, ok := netip.AddrFromSlice(c.RequestCtx().RemoteIP())
+	if !ok {
+		return netip.Addr{}, false
+	}
+	addr = addr.Unmap()
+	if !containsAddr(f.trusted, addr) {
+		return addr, true
+	}
+	hops := strings.Split(c.Get(fiber.HeaderXForwardedFor), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// A malformed hop ends the chain the trusted proxies vouch for
+			break
+		}
+		addr = hop.Unmap()
+		if !containsAddr(f.trusted, addr) {
+			break
+		}
+	}
+	return addr, true
+}
+
+// allows reports whether the client address passes the filter
+func (f *compiledIPFilter) allows(addr netip.Addr) bool {
+	if containsAddr(f.deny, addr) {
+		return false
+	}
+	return len(f.allow) == 0 || containsAddr(f.allow, addr)
+}
+
+// describe documents the filter on the operation
+func (f *IPFilter) describe() string {
+	switch {
+	case len(f.Allow) > 0 && len(f.Deny) > 0:
+		return "Only clients from " + strings.Join(f.Allow, ", ") + " are allowed, except " + strings.Join(f.Deny, ", ") + "; other clients are rejected with 403."
+	case len(f.Allow) > 0:
+		return "Only clients from " + strings.Join(f.Allow, ", ") + " are allowed; other clients are rejected with 403."
+	default:
+		return "Clients from " + strings.Join(f.Deny, ", ") + " are rejected with 403."
+	}
+}
+
+// restricts reports whether the filter rejects any client
+func (f *IPFilter) restricts() bool {
+	return len(f.Allow) > 0 || len(f.Deny) > 0
+}
+
+// IPFilterMiddleware returns a Fiber middleware rejecting clients outside the
+// filter with 403, or an error for malformed ranges.
+//
+// It is installed automatically on routes registered with
+// DocumentedRouteInput.IPFilter or under Config.IPFilter.
+func IPFilterMiddleware(filter IPFilter) (fiber.Handler, error) {
+	compiled, err := filter.compile()
+	if err != nil {
+		return nil, err
+	}
+	return func(c fiber.Ctx) error {
+		if addr, ok := compiled.clientAddr(c); !ok || !compiled.allows(addr) {
+			return fiber.NewError(fiber.StatusForbidden, ipFilterDescription)
+		}
+		return c.Next()
+	}, nil
+}
+
+// routeIPFilter returns the filter of a route: its own, or Config.IPFilter
+func (an *ApiNote) routeIPFilter(input *DocumentedRouteInput) *IPFilter {
+	if input.IPFilter != nil {
+		return input.IPFilter
+	}
+	return an.config.IPFilter
+}
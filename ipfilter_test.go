@@ -0,0 +1,111 @@
+package notelink
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestIPFilter tests filtering clients by address behind trusted proxies.
+// Test requests come from 0.0.0.0, trusted as the proxy unless noted.
+func TestIPFilter(t *testing.T) {
+	proxy := []string{"0.0.0.0/32"}
+	tests := []struct {
+		name         string
+		filter       IPFilter
+		forwardedFor string
+		expected     int
+	}{
+		{"allowed range", IPFilter{Allow: []string{"10.0.0.0/8"}, TrustedProxies: proxy}, "10.1.2.3", fiber.StatusOK},
+		{"outside allowed range", IPFilter{Allow: []string{"10.0.0.0/8"}, TrustedProxies: proxy}, "192.168.1.10", fiber.StatusForbidden},
+		{"denied within allowed range", IPFilter{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.6.6.6"}, TrustedProxies: proxy}, "10.6.6.6", fiber.StatusForbidden},
+		{"deny list only", IPFilter{Deny: []string{"203.0.113.0/24"}, TrustedProxies: proxy}, "198.51.100.7", fiber.StatusOK},
+		{"IPv6", IPFilter{Allow: []string{"2001:db8::/32"}, TrustedProxies: proxy}, "2001:db8::42", fiber.StatusOK},
+		{"client spoofing behind a chain of proxies", IPFilter{Allow: []string{"10.0.0.0/8"}, TrustedProxies: []string{"0.0.0.0/32", "172.16.0.0/12"}}, "10.1.2.3, 192.168.1.10, 172.16.0.5", fiber.StatusForbidden},
+		{"client behind a chain of proxies", IPFilter{Allow: []string{"10.0.0.0/8"}, TrustedProxies: []string{"0.0.0.0/32", "172.16.0.0/12"}}, "192.168.1.10, 10.1.2.3, 172.16.0.5", fiber.StatusOK},
+		{"header of an untrusted connection", IPFilter{Allow: []string{"10.0.0.0/8"}}, "10.1.2.3", fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+			filter := tt.filter
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:      "GET",
+				Path:        "/admin/stats",
+				Description: "Admin statistics",
+				IPFilter:    &filter,
+				Handler:     func(c fiber.Ctx) error { return c.SendString("ok") },
+			})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+			req := httptest.NewRequest("GET", "/admin/stats", nil)
+			req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.expected {
+				t.Fatalf("Expected %d, got %d", tt.expected, resp.StatusCode)
+			}
+			if tt.expected == fiber.StatusForbidden {
+				var body ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error != ipFilterDescription {
+					t.Errorf("Expected the error envelope, got %+v %v", body, err)
+				}
+			}
+		})
+	}
+}
+
+// TestIPFilterDocs tests the global filter, route overrides and their documentation
+func TestIPFilterDocs(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title:    "Test API",
+		Host:     "localhost:8080",
+		IPFilter: &IPFilter{Allow: []string{"10.0.0.0/8"}},
+	}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/internal", Description: "Internal route", Handler: handler},
+		{Method: "GET", Path: "/public", Description: "Public route", Handler: handler, IPFilter: &IPFilter{}},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	for path, expected := range map[string]int{"/internal": fiber.StatusForbidden, "/public": fiber.StatusOK} {
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != expected {
+			t.Errorf("Expected %d for %s, got %d", expected, path, resp.StatusCode)
+		}
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	internal := spec.Paths["/internal"].Get
+	if !strings.Contains(internal.Description, "Only clients from 10.0.0.0/8 are allowed") {
+		t.Errorf("Expected the restriction documented, got %q", internal.Description)
+	}
+	if _, ok := internal.Responses["403"]; !ok {
+		t.Error("Expected the 403 response documented")
+	}
+	if public := spec.Paths["/public"].Get; strings.Contains(public.Description, "clients") {
+		t.Errorf("Expected no restriction on the public route, got %q", public.Description)
+	}
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/broken", Handler: handler, IPFilter: &IPFilter{Allow: []string{"10.0.0.0/33"}}})
+	if err == nil {
+		t.Error("Expected an error for a malformed range")
+	}
+}
@@ -0,0 +1,238 @@
+package notelink
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// JobStatus is the state of a background job
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// JobStatusPath is the status endpoint of the jobs started by long-running
+// routes, relative to Config.BasePath
+const JobStatusPath = "/operations/:id"
+
+// ErrJobNotFound is returned by JobStore.Get for unknown job ids
+var ErrJobNotFound = errors.New("notelink: job not found")
+
+// Job is a background job started by a long-running route, served by the
+// status endpoint
+type Job struct {
+	ID        string      `json:"id"`
+	Status    JobStatus   `json:"status" enum:"pending,running,succeeded,failed"`
+	Result    interface{} `json:"result,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+// JobStore persists jobs, e.g. in a database shared by several instances.
+// Config.JobStore defaults to an in-memory store.
+type JobStore interface {
+	Save(job *Job) error
+	Get(id string) (*Job, error) // ErrJobNotFound for unknown ids
+}
+
+// JobFunc is the work of a background job; its result is served once it succeeds
+type JobFunc func(ctx context.Context) (result interface{}, err error)
+
+// JobStarter validates a long-running request and returns the work to run in
+// the background. Returned errors are handled like handler errors, so no job
+// is created for invalid requests.
+type JobStarter func(c fiber.Ctx) (JobFunc, error)
+
+// MemoryJobStore keeps jobs in memory, evicting the least recently used ones
+// beyond its caps
+type MemoryJobStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+	lru  *lruTracker
+}
+
+// NewMemoryJobStore creates an empty in-memory job store keeping up to
+// DefaultMaxJobs jobs and DefaultMaxJobBytes of them
+func NewMemoryJobStore() *MemoryJobStore {
+	return NewLimitedMemoryJobStore(0, 0)
+}
+
+// NewLimitedMemoryJobStore creates an empty in-memory job store with caps on
+// its jobs and their approximate size, as in MemoryLimits
+func NewLimitedMemoryJobStore(maxJobs int, maxBytes int64) *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs: make(map[string]Job),
+		lru:  newLRUTracker(limit(maxJobs, DefaultMaxJobs), limit(maxBytes, DefaultMaxJobBytes)),
+	}
+}
+
+// Save stores a copy of the job
+func (s *MemoryJobStore) Save(job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = *job
+	for _, id := range s.lru.touch(job.ID, approximateSize(job)) {
+		delete(s.jobs, id)
+	}
+	return nil
+}
+
+// Get returns a copy of the job
+func (s *MemoryJobStore) Get(id string) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	s.lru.use(id)
+	return &job, nil
+}
+
+// stats reports the size of the store
+func (s *MemoryJobStore) stats() RegistryStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.stats("jobs")
+}
+
+// LongRunningRoute registers a route starting a background job: it responds
+// 202 Accepted with the job and a Location header pointing at the job's status
+// endpoint (JobStatusPath), which is registered and documented with the first
+// long-running route. input.Handler is replaced, input.Method defaults to POST
+// and input.SchemasResponse is replaced by Job.
+//
+// Example:
+//
+//	api.LongRunningRoute(&notelink.DocumentedRouteInput{
+//	    Path:           "/v1/reports",
+//	    Description:    "Generate a report",
+//	    SchemasRequest: ReportRequest{},
+//	}, func(c fiber.Ctx) (notelink.JobFunc, error) {
+//	    var req ReportRequest
+//	    if err := c.Bind().Body(&req); err != nil {
+//	        return nil, err
+//	    }
+//	    return func(ctx context.Context) (interface{}, error) {
+//	        return reports.Generate(ctx, req)
+//	    }, nil
+//	})
+func (an *ApiNote) LongRunningRoute(input *DocumentedRouteInput, start JobStarter) error {
+	if start == nil {
+		return fmt.Errorf("job starter is required")
+	}
+
+	route := *input
+	if route.Method == "" {
+		route.Method = http.MethodPost
+	}
+	route.Responses = copyResponses(input.Responses)
+	if route.Responses == nil {
+		route.Responses = make(map[string]string)
+	}
+	route.Responses["202"] = "Job accepted; poll the Location header for its status"
+	route.SchemasResponse = Job{}
+	route.Handler = an.startJobHandler(start)
+	if err := an.DocumentedRoute(&route); err != nil {
+		return err
+	}
+
+	if _, registered := an.endpoints[http.MethodGet+" "+JobStatusPath]; registered {
+		return nil
+	}
+	return an.DocumentedRoute(&DocumentedRouteInput{
+		Method:          http.MethodGet,
+		Path:            JobStatusPath,
+		Description:     "Get the status of a background job, with its result once it succeeded",
+		Params:          []Parameter{{Name: "id", In: "path", Type: "string", Description: "Job id from the Location header", Required: true}},
+		Responses:       map[string]string{"200": "Job status", "404": "Job not found"},
+		SchemasResponse: Job{},
+		AuthRequired:    input.AuthRequired,
+		Tags:            []string{"operations"},
+		Handler:         an.jobStatusHandler,
+		builtin:         true,
+	})
+}
+
+// jobStore returns Config.JobStore, or the in-memory store
+func (an *ApiNote) jobStore() JobStore {
+	if an.config.JobStore != nil {
+		return an.config.JobStore
+	}
+	an.jobsOnce.Do(func() {
+		limits := an.limits()
+		an.jobs = NewLimitedMemoryJobStore(limits.MaxJobs, limits.MaxJobBytes)
+	})
+	return an.jobs
+}
+
+// startJobHandler creates a job for each request and runs it in the background
+func (an *ApiNote) startJobHandler(start JobStarter) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		run, err := start(c)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		job := &Job{ID: newRandomID(), Status: JobPending, CreatedAt: now, UpdatedAt: now}
+		store := an.jobStore()
+		if err := store.Save(job); err != nil {
+			return fmt.Errorf("failed to save job: %w", err)
+		}
+		go runJob(store, *job, run)
+
+		c.Location(an.config.BasePath + "/operations/" + job.ID)
+		return c.Status(fiber.StatusAccepted).JSON(job)
+	}
+}
+
+// runJob runs a job's work, saving its progress and outcome
+func runJob(store JobStore, job Job, run JobFunc) {
+	job.Status = JobRunning
+	job.UpdatedAt = time.Now().UTC()
+	_ = store.Save(&job)
+
+	result, err := run(context.Background())
+	job.UpdatedAt = time.Now().UTC()
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = JobSucceeded
+		job.Result = result
+	}
+	_ = store.Save(&job)
+}
+
+// jobStatusHandler serves a job's status
+func (an *ApiNote) jobStatusHandler(c fiber.Ctx) error {
+	job, err := an.jobStore().Get(c.Params("id"))
+	if errors.Is(err, ErrJobNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(job)
+}
+
+// newRandomID returns a random 128-bit hex id, for jobs, sessions and API keys
+func newRandomID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,117 @@
+package notelink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type jobReportRequest struct {
+	Month string `json:"month"`
+}
+
+// TestLongRunningRoute tests starting background jobs and polling their status
+func TestLongRunningRoute(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/v1"}, "secret")
+	release := make(chan struct{})
+	err := api.LongRunningRoute(&DocumentedRouteInput{
+		Path:           "/reports",
+		Description:    "Generate a report",
+		SchemasRequest: jobReportRequest{},
+	}, func(c fiber.Ctx) (JobFunc, error) {
+		var req jobReportRequest
+		if err := c.Bind().Body(&req); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (interface{}, error) {
+			<-release
+			if req.Month == "never" {
+				return nil, errors.New("no data for month")
+			}
+			return map[string]string{"report": "report-" + req.Month}, nil
+		}, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to register long-running route: %v", err)
+	}
+
+	getJob := func(location string) (int, Job) {
+		t.Helper()
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", location, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		var job Job
+		_ = json.NewDecoder(resp.Body).Decode(&job)
+		return resp.StatusCode, job
+	}
+	waitFor := func(location string, status JobStatus) Job {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			_, job := getJob(location)
+			if job.Status == status || time.Now().After(deadline) {
+				return job
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	tests := []struct {
+		month  string
+		status JobStatus
+		result string
+		error  string
+	}{
+		{month: "2026-09", status: JobSucceeded, result: "report-2026-09"},
+		{month: "never", status: JobFailed, error: "no data for month"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.month, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/reports", strings.NewReader(`{"month":"`+tt.month+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			if resp.StatusCode != fiber.StatusAccepted {
+				t.Fatalf("Expected status 202, got %d", resp.StatusCode)
+			}
+			location := resp.Header.Get("Location")
+			if !strings.HasPrefix(location, "/v1/operations/") {
+				t.Fatalf("Expected a Location to the status endpoint, got %q", location)
+			}
+
+			if job := waitFor(location, JobRunning); job.Status != JobRunning {
+				t.Fatalf("Expected a running job, got %+v", job)
+			}
+			release <- struct{}{}
+			job := waitFor(location, tt.status)
+			if job.Status != tt.status || job.Error != tt.error {
+				t.Fatalf("Expected a %s job, got %+v", tt.status, job)
+			}
+			if tt.result != "" && job.Result.(map[string]interface{})["report"] != tt.result {
+				t.Errorf("Expected result %q, got %v", tt.result, job.Result)
+			}
+		})
+	}
+
+	if status, _ := getJob("/v1/operations/unknown"); status != fiber.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown job, got %d", status)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if _, ok := spec.Paths["/reports"].Post.Responses["202"]; !ok {
+		t.Error("Expected a documented 202 response")
+	}
+	status := spec.Paths["/operations/{id}"].Get
+	if status == nil || status.Responses["404"].Description != "Job not found" {
+		t.Errorf("Expected a documented status endpoint, got %+v", status)
+	}
+}
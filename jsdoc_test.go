@@ -0,0 +1,74 @@
+package notelink
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type jsdocAccount struct {
+	ID        string    `json:"id" doc:"Account identifier" example:"7f3c"`
+	Homepage  url.URL   `json:"homepage"`
+	Email     string    `json:"email" doc:"Login e-mail */ address" example:"ada@example.com"`
+	Password  string    `json:"password"`
+	CreatedAt time.Time `json:"created_at"`
+	Legacy    string    `json:"legacy" deprecated:"true"`
+	Count     int       `json:"count"`
+}
+
+func (jsdocAccount) SchemaDescription() string { return "A customer account" }
+
+// TestTypeScriptJSDoc tests carrying field and type documentation into JSDoc
+func TestTypeScriptJSDoc(t *testing.T) {
+	ts := generateTypeScriptSchema("Account", jsdocAccount{}, "", nil)
+
+	expected := []string{
+		"/** A customer account */\nexport interface Account {",
+		"  /**\n   * Login e-mail *\\/ address\n   * @example ada@example.com\n   */\n  email: string;",
+		"  /** @format date-time */\n  created_at: string;",
+		"  /** @format uri */\n  homepage: string;",
+		"  /** @deprecated */\n  legacy: string;",
+		"  count: number;",
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(ts, fragment) {
+			t.Errorf("Expected %q in:\n%s", fragment, ts)
+		}
+	}
+	if strings.Contains(ts, "@format int") {
+		t.Errorf("Expected plain numeric formats left out, got:\n%s", ts)
+	}
+	if !strings.Contains(ts, "@sensitive") || strings.Contains(ts, "@example hunter2") {
+		t.Errorf("Expected the password flagged sensitive without an example, got:\n%s", ts)
+	}
+
+	t.Run("field lines", func(t *testing.T) {
+		field, _ := reflect.TypeOf(jsdocAccount{}).FieldByName("ID")
+		lines := fieldJSDocLines(&field, "", nil)
+		if got := strings.Join(lines, "|"); got != "Account identifier|@example 7f3c" {
+			t.Errorf("Unexpected lines %q", got)
+		}
+	})
+
+	t.Run("operation types", func(t *testing.T) {
+		api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+		err := api.DocumentedRoute(&DocumentedRouteInput{
+			Method:          "GET",
+			Path:            "/accounts",
+			Description:     "List accounts",
+			Handler:         func(c fiber.Ctx) error { return nil },
+			SchemasResponse: []jsdocAccount{},
+		})
+		if err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+		operations := string(api.TypeScriptModules()["operations.ts"])
+		if !strings.Contains(operations, "/** Response body of GET /accounts: List accounts */\nexport type GetAccountsResponse") {
+			t.Errorf("Expected the operation alias documented, got:\n%s", operations)
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package notelink
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+)
+
+// MIMEApplicationJSONAPI is the JSON:API media type
+const MIMEApplicationJSONAPI = "application/vnd.api+json"
+
+// JSONAPIResourceIdentifier identifies a related resource
+type JSONAPIResourceIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// JSONAPIToOneRelationship is a to-one relationship object
+type JSONAPIToOneRelationship struct {
+	Data *JSONAPIResourceIdentifier `json:"data"`
+}
+
+// JSONAPIToManyRelationship is a to-many relationship object
+type JSONAPIToManyRelationship struct {
+	Data []JSONAPIResourceIdentifier `json:"data"`
+}
+
+// JSONAPIResourceObject is a generic resource object, used for included resources
+// and for building response documents
+type JSONAPIResourceObject struct {
+	Attributes    interface{}            `json:"attributes,omitempty"`
+	Relationships map[string]interface{} `json:"relationships,omitempty"`
+	Type          string                 `json:"type"`
+	ID            string                 `json:"id,omitempty"`
+}
+
+// JSONAPIErrorSource points to the part of the request that caused an error
+type JSONAPIErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// JSONAPIError is a JSON:API error object
+type JSONAPIError struct {
+	Source *JSONAPIErrorSource `json:"source,omitempty"`
+	Status string              `json:"status,omitempty"`
+	Code   string              `json:"code,omitempty"`
+	Title  string              `json:"title,omitempty"`
+	Detail string              `json:"detail,omitempty"`
+}
+
+// JSONAPIErrorDocument is a JSON:API error document. Use JSONAPIErrorDocument{}
+// as a response schema for error statuses.
+type JSONAPIErrorDocument struct {
+	Errors []JSONAPIError `json:"errors"`
+}
+
+// JSONAPIDocument returns a schema describing a JSON:API document whose primary
+// data is a single resource of resourceType built from the attributes struct.
+// The result can be used as SchemasRequest or SchemasResponse, so incoming
+// documents are validated against the JSON:API shape (including the resource type).
+//
+// Fields of the attributes struct are mapped as follows:
+//
+//   - `jsonapi:"id"`: excluded from attributes (the resource id is top-level)
+//   - `jsonapi:"relation"`: moved to relationships; slices become to-many relationships
+//   - anything else: an attribute
+//
+// Example usage:
+//
+//	type Article struct {
+//	    ID     string   `json:"id" jsonapi:"id"`
+//	    Title  string   `json:"title"`
+//	    Author string   `json:"author" jsonapi:"relation"`
+//	    Tags   []string `json:"tags,omitempty" jsonapi:"relation"`
+//	}
+//
+//	api.DocumentedRoute(&notelink.DocumentedRouteInput{
+//	    Method:          "POST",
+//	    Path:            "/v1/articles",
+//	    Consumes:        []string{notelink.MIMEApplicationJSONAPI},
+//	    Produces:        []string{notelink.MIMEApplicationJSONAPI},
+//	    SchemasRequest:  notelink.JSONAPIDocument("articles", Article{}),
+//	    SchemasResponse: notelink.JSONAPIDocument("articles", Article{}),
+//	    Handler:         createArticle,
+//	})
+func JSONAPIDocument(resourceType string, attributes interface{}) interface{} {
+	return jsonAPIDocumentSchema(jsonAPIResourceType(resourceType, attributes))
+}
+
+// JSONAPICollectionDocument is like JSONAPIDocument but the primary data is an
+// array of resources
+func JSONAPICollectionDocument(resourceType string, attributes interface{}) interface{} {
+	return jsonAPIDocumentSchema(reflect.SliceOf(jsonAPIResourceType(resourceType, attributes)))
+}
+
+// jsonAPIDocumentSchema builds a zero value of the top-level document type
+func jsonAPIDocumentSchema(dataType reflect.Type) interface{} {
+	docType := reflect.StructOf([]reflect.StructField{
+		{Name: "Data", Type: dataType, Tag: `json:"data"`},
+		{Name: "Included", Type: reflect.TypeOf([]JSONAPIResourceObject{}), Tag: `json:"included,omitempty"`},
+	})
+	return reflect.New(docType).Elem().Interface()
+}
+
+// jsonAPIResourceType builds the resource object type for an attributes struct
+func jsonAPIResourceType(resourceType string, attributes interface{}) reflect.Type {
+	attrType := reflect.TypeOf(attributes)
+	if attrType != nil && attrType.Kind() == reflect.Ptr {
+		attrType = attrType.Elem()
+	}
+
+	fields := []reflect.StructField{
+		{Name: "Type", Type: reflect.TypeOf(""), Tag: reflect.StructTag(`json:"type" enum:"` + resourceType + `"`)},
+		{Name: "ID", Type: reflect.TypeOf(""), Tag: `json:"id,omitempty"`},
+	}
+	if attrType == nil || attrType.Kind() != reflect.Struct {
+		return reflect.StructOf(fields)
+	}
+
+	var attrFields, relationFields []reflect.StructField
+	for i := 0; i < attrType.NumField(); i++ {
+		field := attrType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		switch strings.Split(field.Tag.Get("jsonapi"), ",")[0] {
+		case "id":
+			// The resource id lives at the top level of the resource object
+		case "relation":
+			relType := reflect.TypeOf(JSONAPIToOneRelationship{})
+			if field.Type.Kind() == reflect.Slice {
+				relType = reflect.TypeOf(JSONAPIToManyRelationship{})
+			}
+			relationFields = append(relationFields, reflect.StructField{
+				Name: field.Name,
+				Type: relType,
+				Tag:  reflect.StructTag(`json:"` + validation.JSONFieldName(&field) + `,omitempty"`),
+			})
+		default:
+			attrFields = append(attrFields, reflect.StructField{Name: field.Name, Type: field.Type, Tag: field.Tag})
+		}
+	}
+
+	// Keep the named type (and its component schema) unless fields were moved out
+	attributesType := attrType
+	if len(attrFields) != attrType.NumField() {
+		attributesType = reflect.StructOf(attrFields)
+	}
+	fields = append(fields, reflect.StructField{Name: "Attributes", Type: attributesType, Tag: `json:"attributes"`})
+
+	if len(relationFields) > 0 {
+		fields = append(fields, reflect.StructField{
+			Name: "Relationships",
+			Type: reflect.StructOf(relationFields),
+			Tag:  `json:"relationships,omitempty"`,
+		})
+	}
+
+	return reflect.StructOf(fields)
+}
+
+// JSONAPI sends a JSON:API document with data as its primary data and the
+// optional included resources
+func JSONAPI(c fiber.Ctx, data interface{}, included ...JSONAPIResourceObject) error {
+	document := fiber.Map{"data": data}
+	if len(included) > 0 {
+		document["included"] = included
+	}
+	return c.JSON(document, MIMEApplicationJSONAPI)
+}
+
+// JSONAPIErrorHandler renders errors as JSON:API error documents. Validation
+// failures produce one error object per field, with source pointers such as
+// "/data/attributes/title". Install it with Config.ErrorHandler for services
+// that only speak JSON:API.
+func JSONAPIErrorHandler(c fiber.Ctx, err error) error {
+	var validationErr *ValidationErrorResponse
+	if errors.As(err, &validationErr) {
+		document := JSONAPIErrorDocument{Errors: make([]JSONAPIError, 0, len(validationErr.Errors))}
+		for _, fieldErr := range validationErr.Errors {
+			document.Errors = append(document.Errors, JSONAPIError{
+				Status: strconv.Itoa(fiber.StatusBadRequest),
+				Code:   fieldErr.Type,
+				Title:  validationErr.ErrorMessage,
+				Detail: fieldErr.Message,
+				Source: &JSONAPIErrorSource{Pointer: "/" + strings.ReplaceAll(fieldErr.Field, ".", "/")},
+			})
+		}
+		if len(document.Errors) == 0 {
+			document.Errors = append(document.Errors, JSONAPIError{
+				Status: strconv.Itoa(fiber.StatusBadRequest),
+				Title:  validationErr.ErrorMessage,
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(document, MIMEApplicationJSONAPI)
+	}
+
+	status := fiber.StatusInternalServerError
+	title := "Internal Server Error"
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		status = fiberErr.Code
+		title = fiberErr.Message
+	}
+
+	return c.Status(status).JSON(JSONAPIErrorDocument{
+		Errors: []JSONAPIError{{Status: strconv.Itoa(status), Title: title}},
+	}, MIMEApplicationJSONAPI)
+}
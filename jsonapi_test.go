@@ -0,0 +1,116 @@
+package notelink
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type TestArticle struct {
+	ID     string   `json:"id" jsonapi:"id"`
+	Title  string   `json:"title"`
+	Author string   `json:"author" jsonapi:"relation"`
+	Tags   []string `json:"tags,omitempty" jsonapi:"relation"`
+}
+
+// TestJSONAPIDocument tests JSON:API schemas, validation and error documents
+func TestJSONAPIDocument(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", ErrorHandler: JSONAPIErrorHandler}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "POST",
+		Path:            "/articles",
+		Description:     "Create an article",
+		Responses:       map[string]string{"201": "Created", "400": "Invalid document"},
+		Consumes:        []string{MIMEApplicationJSONAPI},
+		Produces:        []string{MIMEApplicationJSONAPI},
+		SchemasRequest:  JSONAPIDocument("articles", TestArticle{}),
+		SchemasResponse: JSONAPIDocument("articles", TestArticle{}),
+		Handler: func(c fiber.Ctx) error {
+			return JSONAPI(c.Status(201), JSONAPIResourceObject{Type: "articles", ID: "1", Attributes: fiber.Map{"title": "Hello"}})
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		body            string
+		expectedPointer string
+		expectedStatus  int
+	}{
+		{
+			name:           "Valid document",
+			body:           `{"data":{"type":"articles","attributes":{"title":"Hello"},"relationships":{"author":{"data":{"type":"people","id":"9"}}}}}`,
+			expectedStatus: 201,
+		},
+		{
+			name:            "Wrong resource type",
+			body:            `{"data":{"type":"people","attributes":{"title":"Hello"}}}`,
+			expectedStatus:  400,
+			expectedPointer: "/data/type",
+		},
+		{
+			name:            "Invalid attribute",
+			body:            `{"data":{"type":"articles","attributes":{"title":5}}}`,
+			expectedStatus:  400,
+			expectedPointer: "/data/attributes/title",
+		},
+		{
+			name:            "Missing data",
+			body:            `{"meta":{}}`,
+			expectedStatus:  400,
+			expectedPointer: "/data",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/articles", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", MIMEApplicationJSONAPI)
+
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if !strings.HasPrefix(resp.Header.Get("Content-Type"), MIMEApplicationJSONAPI) {
+				t.Errorf("Expected JSON:API content type, got %s", resp.Header.Get("Content-Type"))
+			}
+
+			if tt.expectedPointer != "" {
+				var document JSONAPIErrorDocument
+				if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+					t.Fatalf("Failed to decode error document: %v", err)
+				}
+				if len(document.Errors) == 0 || document.Errors[0].Source == nil || document.Errors[0].Source.Pointer != tt.expectedPointer {
+					t.Errorf("Expected error pointer %s, got %+v", tt.expectedPointer, document.Errors)
+				}
+			}
+		})
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	schema := spec.Paths["/articles"].Post.RequestBody.Content[MIMEApplicationJSONAPI].Schema
+	data := schema.Properties["data"]
+	if data == nil || data.Properties["type"] == nil || len(data.Properties["type"].Enum) != 1 {
+		t.Fatalf("Expected data.type enum in schema, got %+v", data)
+	}
+	if _, ok := data.Properties["attributes"].Properties["id"]; ok {
+		t.Error("Expected id to be excluded from attributes")
+	}
+	relationships := data.Properties["relationships"]
+	if relationships == nil || relationships.Properties["tags"].Ref != "#/components/schemas/JSONAPIToManyRelationship" {
+		t.Errorf("Expected tags to be a to-many relationship, got %+v", relationships)
+	}
+	if _, ok := spec.Components.Schemas["JSONAPIToOneRelationship"]; !ok {
+		t.Error("Expected relationship component schemas to be registered")
+	}
+}
@@ -0,0 +1,226 @@
+package notelink
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Link documents a related operation reachable from an endpoint's responses,
+// in the spirit of HAL _links. Href is a path template such as "/v1/users/{id}"
+// (Fiber-style ":id" segments are accepted too).
+//
+// Parameters maps the target operation's parameter names to OpenAPI runtime
+// expressions, so a value from this call feeds the follow-up call:
+//
+//	notelink.Link{
+//	    Rel:        "user",
+//	    Href:       "/v1/users/{id}",
+//	    Status:     "201",
+//	    Parameters: map[string]string{"id": "$response.body#/id"},
+//	}
+//
+// Supported expressions are $response.body#/pointer, $response.header.Name,
+// $request.body#/pointer, $request.path.name, $request.query.name,
+// $request.header.Name and $statusCode; other values are passed as constants.
+type Link struct {
+	Parameters  map[string]string `json:"parameters,omitempty"`
+	Rel         string            `json:"rel"`
+	Href        string            `json:"href"`
+	Method      string            `json:"method,omitempty"` // Defaults to GET
+	Description string            `json:"description,omitempty"`
+	Status      string            `json:"status,omitempty"` // Response status the link applies to; defaults to every 2xx response
+}
+
+// LinkObject represents an OpenAPI link object
+type LinkObject struct {
+	Parameters   map[string]string `json:"parameters,omitempty"`
+	OperationID  string            `json:"operationId,omitempty"`
+	OperationRef string            `json:"operationRef,omitempty"`
+	Description  string            `json:"description,omitempty"`
+}
+
+// followUpLink is the try-it client's view of a link to a documented operation
+type followUpLink struct {
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Rel        string            `json:"rel"`
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Target     string            `json:"target"`
+	Status     string            `json:"status,omitempty"`
+}
+
+// method returns the link's HTTP method, defaulting to GET
+func (l *Link) method() string {
+	if l.Method == "" {
+		return "GET"
+	}
+	return strings.ToUpper(l.Method)
+}
+
+// pathsMatch compares two path templates segment by segment, treating
+// ":name" and "{name}" segments as interchangeable parameters
+func pathsMatch(a, b string) bool {
+	segmentsA := strings.Split(strings.Trim(a, "/"), "/")
+	segmentsB := strings.Split(strings.Trim(b, "/"), "/")
+	if len(segmentsA) != len(segmentsB) {
+		return false
+	}
+	for i := range segmentsA {
+		if isPathParamSegment(segmentsA[i]) && isPathParamSegment(segmentsB[i]) {
+			continue
+		}
+		if segmentsA[i] != segmentsB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isPathParamSegment reports whether a path segment is a ":name" or "{name}" parameter
+func isPathParamSegment(segment string) bool {
+	return strings.HasPrefix(segment, ":") || (strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"))
+}
+
+// resolveLink finds the registered endpoint a link points to, if any
+func (an *ApiNote) resolveLink(link *Link) *Endpoint {
+	for _, endpoint := range an.endpoints {
+		if endpoint.Method != link.method() {
+			continue
+		}
+		if pathsMatch(endpoint.Path, link.Href) || pathsMatch(endpoint.Path, an.config.BasePath+link.Href) {
+			endpoint := endpoint
+			return &endpoint
+		}
+	}
+	return nil
+}
+
+// linkObject converts a Link to an OpenAPI link object, referencing the target
+// by operationId when it is a documented endpoint and by operationRef otherwise
+func (an *ApiNote) linkObject(link *Link) LinkObject {
+	object := LinkObject{Description: link.Description, Parameters: link.Parameters}
+	if target := an.resolveLink(link); target != nil {
+		object.OperationID = generateOperationID(target.Method, target.routePath())
+		return object
+	}
+
+	pointer := strings.ReplaceAll(strings.ReplaceAll(openAPIPath(link.Href), "~", "~0"), "/", "~1")
+	object.OperationRef = "#/paths/" + pointer + "/" + strings.ToLower(link.method())
+	return object
+}
+
+// addResponseLinks attaches the endpoint's links to its successful responses
+func (an *ApiNote) addResponseLinks(endpoint *Endpoint, operation *Operation) {
+	if len(endpoint.Links) == 0 {
+		return
+	}
+
+	for i := range endpoint.Links {
+		link := &endpoint.Links[i]
+
+		// Links bound to a status create that response if it isn't documented
+		if link.Status != "" {
+			if _, ok := operation.Responses[link.Status]; !ok {
+				operation.Responses[link.Status] = Response{Description: responseDescription(link.Status)}
+			}
+		}
+
+		for code, response := range operation.Responses {
+			if link.Status != "" && code != link.Status {
+				continue
+			}
+			if link.Status == "" && !strings.HasPrefix(code, "2") {
+				continue
+			}
+			if response.Links == nil {
+				response.Links = make(map[string]LinkObject, len(endpoint.Links))
+			}
+			response.Links[link.Rel] = an.linkObject(link)
+			operation.Responses[code] = response
+		}
+	}
+}
+
+// responseDescription returns the standard reason phrase for a status code
+func responseDescription(code string) string {
+	if status, err := strconv.Atoi(code); err == nil && http.StatusText(status) != "" {
+		return http.StatusText(status)
+	}
+	return "Response"
+}
+
+// followUpLinks returns the JSON-encoded links to documented operations that the
+// try-it client offers as follow-up calls after a response
+func (an *ApiNote) followUpLinks(endpoint *Endpoint) string {
+	links := make([]followUpLink, 0, len(endpoint.Links))
+	for i := range endpoint.Links {
+		link := &endpoint.Links[i]
+		target := an.resolveLink(link)
+		if target == nil {
+			continue
+		}
+		links = append(links, followUpLink{
+			Rel:        link.Rel,
+			Method:     target.Method,
+			Path:       target.Path,
+			Target:     testFormID(target.Method, target.Path),
+			Status:     link.Status,
+			Parameters: link.Parameters,
+		})
+	}
+	if len(links) == 0 {
+		return ""
+	}
+
+	data, err := json.Marshal(links)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// testFormID returns the HTML id of an endpoint's try-it form
+func testFormID(method, path string) string {
+	return "test-form-" + method + "-" + strings.ReplaceAll(strings.ReplaceAll(path, "/", "-"), ":", "_")
+}
+
+// operationAnchor returns the HTML id of an endpoint's docs entry
+func operationAnchor(method, path string) string {
+	return "op-" + generateOperationID(method, path)
+}
+
+// renderRelatedOperations renders the "Related operations" list for an endpoint
+func (an *ApiNote) renderRelatedOperations(endpoint *Endpoint) string {
+	if len(endpoint.Links) == 0 {
+		return ""
+	}
+
+	links := append([]Link(nil), endpoint.Links...)
+	sort.SliceStable(links, func(i, j int) bool { return links[i].Rel < links[j].Rel })
+
+	var html strings.Builder
+	html.WriteString(`
+                    <div class="related-operations">
+                        <h4>Related operations:</h4>
+                        <ul>`)
+	for i := range links {
+		link := &links[i]
+		target := escapeHTML(link.Href)
+		if resolved := an.resolveLink(link); resolved != nil {
+			target = `<a href="#` + operationAnchor(resolved.Method, resolved.routePath()) + `" onclick="openOperation(this.getAttribute('href').slice(1))">` + target + `</a>`
+		}
+		description := ""
+		if link.Description != "" {
+			description = ` — ` + escapeHTML(link.Description)
+		}
+		html.WriteString(`
+                            <li><span class="method ` + escapeHTML(link.method()) + `">` + escapeHTML(link.method()) + `</span> ` + target + ` <code>rel="` + escapeHTML(link.Rel) + `"</code>` + description + `</li>`)
+	}
+	html.WriteString(`
+                        </ul>
+                    </div>`)
+	return html.String()
+}
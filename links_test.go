@@ -0,0 +1,119 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestEndpointLinks tests OpenAPI links and the related operations list
+func TestEndpointLinks(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/api"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+
+	routes := []*DocumentedRouteInput{
+		{
+			Method:      "POST",
+			Path:        "/v1/users",
+			Description: "Create a user",
+			Responses:   map[string]string{"201": "Created", "400": "Invalid body"},
+			Handler:     handler,
+			Links: []Link{
+				{Rel: "self", Href: "/v1/users/{id}", Description: "Fetch the created user"},
+				{Rel: "audit", Href: "/v1/audit/{id}", Method: "get"},
+			},
+		},
+		{
+			Method:      "GET",
+			Path:        "/v1/users/:id",
+			Description: "Get a user",
+			Responses:   map[string]string{"200": "Success"},
+			Handler:     handler,
+		},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	responses := api.GenerateOpenAPISpec().Paths["/v1/users"].Post.Responses
+	tests := []struct {
+		name         string
+		rel          string
+		operationID  string
+		operationRef string
+	}{
+		{name: "Documented target", rel: "self", operationID: "getUsersById"},
+		{name: "Undocumented target", rel: "audit", operationRef: "#/paths/~1v1~1audit~1{id}/get"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, ok := responses["201"].Links[tt.rel]
+			if !ok {
+				t.Fatalf("Expected %s link on 201 response, got %v", tt.rel, responses["201"].Links)
+			}
+			if link.OperationID != tt.operationID || link.OperationRef != tt.operationRef {
+				t.Errorf("Unexpected link object: %+v", link)
+			}
+		})
+	}
+	if len(responses["400"].Links) != 0 {
+		t.Error("Expected links only on successful responses")
+	}
+
+	html := api.generateHTML()
+	if !strings.Contains(html, "Related operations:") || !strings.Contains(html, `href="#op-getUsersById"`) {
+		t.Error("Expected related operations list linking to the target endpoint")
+	}
+	if !strings.Contains(html, `id="op-getUsersById"`) {
+		t.Error("Expected endpoint anchors in the docs")
+	}
+}
+
+// TestLinkParameters tests link parameter expressions and try-it follow-up data
+func TestLinkParameters(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "POST",
+		Path:        "/users",
+		Description: "Create a user",
+		Responses:   map[string]string{"200": "Already exists"},
+		Handler:     handler,
+		Links: []Link{{
+			Rel:        "user",
+			Href:       "/users/{id}",
+			Status:     "201",
+			Parameters: map[string]string{"id": "$response.body#/id"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/users/:id", Handler: handler}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	responses := api.GenerateOpenAPISpec().Paths["/users"].Post.Responses
+	created, ok := responses["201"]
+	if !ok || created.Description != "Created" {
+		t.Fatalf("Expected the link to document a 201 response, got %+v", responses)
+	}
+	if got := created.Links["user"].Parameters["id"]; got != "$response.body#/id" {
+		t.Errorf("Expected id parameter expression, got %q", got)
+	}
+	if len(responses["200"].Links) != 0 {
+		t.Error("Expected status-bound link only on its status")
+	}
+
+	html := api.generateHTML()
+	if !strings.Contains(html, `data-links="[{&quot;parameters&quot;:{&quot;id&quot;:&quot;$response.body#/id&quot;}`) {
+		t.Error("Expected follow-up link data on the try-it form")
+	}
+	if !strings.Contains(html, `id="test-form-GET--users-_id"`) {
+		t.Error("Expected follow-up target form")
+	}
+}
@@ -0,0 +1,102 @@
+package notelink
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestListenAddr tests deriving the listen address from Config.Host
+func TestListenAddr(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected string
+	}{
+		{host: "localhost:3000", expected: ":3000"},
+		{host: "0.0.0.0:9090", expected: ":9090"},
+		{host: "[::1]:8081", expected: ":8081"},
+		{host: "localhost", expected: ":8080"},
+		{host: "", expected: ":8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			api := &ApiNote{config: &Config{Host: tt.host}}
+			if got := api.listenAddr(); got != tt.expected {
+				t.Errorf("listenAddr() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestListenUnix tests serving the documentation over a Unix domain socket
+func TestListenUnix(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "notelink.sock")
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- api.ListenUnix(socketPath)
+	}()
+	defer api.Fiber().Shutdown() //nolint:errcheck // best-effort cleanup
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	var resp *http.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://unix/api-docs/openapi.json", http.NoBody)
+		resp, err = client.Do(req)
+		if err == nil {
+			break
+		}
+		select {
+		case listenErr := <-errCh:
+			t.Fatalf("ListenUnix failed: %v", listenErr)
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+	if err != nil {
+		t.Fatalf("Failed to reach server over unix socket: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 OK, got %d", resp.StatusCode)
+	}
+}
+
+// TestFiberConfigPassthrough tests that a user-supplied fiber.Config is applied
+func TestFiberConfigPassthrough(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		FiberConfig: &fiber.Config{
+			BodyLimit:   1024,
+			ReadTimeout: 5 * time.Second,
+		},
+	}, "secret")
+
+	cfg := api.Fiber().Config()
+	if cfg.BodyLimit != 1024 {
+		t.Errorf("Expected BodyLimit 1024, got %d", cfg.BodyLimit)
+	}
+	if cfg.ReadTimeout != 5*time.Second {
+		t.Errorf("Expected ReadTimeout 5s, got %s", cfg.ReadTimeout)
+	}
+	if cfg.JSONEncoder == nil || cfg.JSONDecoder == nil {
+		t.Error("Expected default JSON encoder and decoder to be set")
+	}
+}
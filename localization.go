@@ -0,0 +1,162 @@
+package notelink
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// defaultLocale is the language of the docs when Config.DefaultLocale is empty
+const defaultLocale = "en"
+
+// docsLocale returns the language of Description and Responses
+func (an *ApiNote) docsLocale() string {
+	if an.config.DefaultLocale != "" {
+		return an.config.DefaultLocale
+	}
+	return defaultLocale
+}
+
+// locales returns the default locale followed by the translated locales of the
+// routes, sorted
+func (an *ApiNote) locales() []string {
+	seen := map[string]bool{an.docsLocale(): true}
+	var translated []string
+	add := func(locale string) {
+		if !seen[locale] {
+			seen[locale] = true
+			translated = append(translated, locale)
+		}
+	}
+	for _, endpoint := range an.endpoints {
+		for locale := range endpoint.LocalizedDescriptions {
+			add(locale)
+		}
+		for locale := range endpoint.LocalizedResponses {
+			add(locale)
+		}
+	}
+	sort.Strings(translated)
+	return append([]string{an.docsLocale()}, translated...)
+}
+
+// localeOf picks the viewer's language for a docs request and sets the
+// Content-Language of the response: the lang query parameter, else the most
+// preferred Accept-Language range matching a locale exactly or by primary
+// language (th-TH matches th), else the default locale
+func (an *ApiNote) localeOf(c fiber.Ctx) string {
+	locales := an.locales()
+	locale := requestLocale(c, locales)
+	c.Set(fiber.HeaderContentLanguage, locale)
+	if len(locales) > 1 {
+		c.Vary(fiber.HeaderAcceptLanguage)
+	}
+	return locale
+}
+
+// requestLocale returns the locale a request asks for, defaulting to the first
+func requestLocale(c fiber.Ctx, locales []string) string {
+	if lang := c.Query("lang"); lang != "" {
+		if locale := matchLocale(lang, locales); locale != "" {
+			return locale
+		}
+	}
+	for _, lang := range acceptedLanguages(c.Get(fiber.HeaderAcceptLanguage)) {
+		if lang == "*" {
+			break
+		}
+		if locale := matchLocale(lang, locales); locale != "" {
+			return locale
+		}
+	}
+	return locales[0]
+}
+
+// matchLocale returns the locale matching a language range exactly or by its
+// primary language, or "" when none does
+func matchLocale(lang string, locales []string) string {
+	for _, locale := range locales {
+		if strings.EqualFold(locale, lang) {
+			return locale
+		}
+	}
+	primary, _, _ := strings.Cut(lang, "-")
+	for _, locale := range locales {
+		localePrimary, _, _ := strings.Cut(locale, "-")
+		if strings.EqualFold(localePrimary, primary) {
+			return locale
+		}
+	}
+	return ""
+}
+
+// acceptedLanguages returns the language ranges of an Accept-Language header,
+// most preferred first, without those with q=0
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang = strings.TrimSpace(lang)
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{lang, q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	langs := make([]string, len(ranges))
+	for i, r := range ranges {
+		langs[i] = r.lang
+	}
+	return langs
+}
+
+// localizeEndpoint returns the endpoint with its description and response
+// descriptions in a locale; untranslated ones keep the default language
+func localizeEndpoint(endpoint Endpoint, locale string) Endpoint {
+	if description := endpoint.LocalizedDescriptions[locale]; description != "" {
+		endpoint.Description = description
+	}
+	if translated := endpoint.LocalizedResponses[locale]; len(translated) > 0 {
+		endpoint.Responses = copyResponses(endpoint.Responses)
+		for status, description := range translated {
+			if _, ok := endpoint.Responses[status]; ok && description != "" {
+				endpoint.Responses[status] = description
+			}
+		}
+	}
+	return endpoint
+}
+
+// copyLocalizedResponses deep copies the per-locale response descriptions of a route
+func copyLocalizedResponses(localized map[string]map[string]string) map[string]map[string]string {
+	if localized == nil {
+		return nil
+	}
+	copied := make(map[string]map[string]string, len(localized))
+	for locale, responses := range localized {
+		copied[locale] = copyResponses(responses)
+	}
+	return copied
+}
+
+// GenerateLocalizedOpenAPISpec creates the specification with the route
+// descriptions and response descriptions in a locale, e.g. to export the Thai
+// and English documentation of a service
+func (an *ApiNote) GenerateLocalizedOpenAPISpec(locale string) *OpenAPISpec {
+	return an.generateOpenAPISpec(an.baseURL(nil), locale)
+}
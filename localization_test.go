@@ -0,0 +1,81 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestLocalizedDocs tests serving route and response descriptions in the viewer's language
+func TestLocalizedDocs(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", DocsUI: "notelink"}, "secret")
+	if err := api.RegisterErrorCode("USER_NOT_FOUND", 404, "The user does not exist", nil); err != nil {
+		t.Fatalf("Failed to register error code: %v", err)
+	}
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:                "GET",
+		Path:                  "/v1/users/:id",
+		Description:           "Get a user",
+		Responses:             map[string]string{"200": "User found", "USER_NOT_FOUND": ""},
+		LocalizedDescriptions: map[string]string{"th": "ดึงข้อมูลผู้ใช้"},
+		LocalizedResponses: map[string]map[string]string{
+			"th": {"200": "พบผู้ใช้", "USER_NOT_FOUND": "ไม่พบผู้ใช้"},
+		},
+		Handler: func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptLanguage string
+		language       string
+		expected       []string
+	}{
+		{"default language", "/api-docs/openapi.json", "", "en", []string{"Get a user", "User found", "USER_NOT_FOUND: The user does not exist"}},
+		{"regional Thai", "/api-docs/openapi.json", "th-TH,th;q=0.9,en;q=0.8", "th", []string{"ดึงข้อมูลผู้ใช้", "พบผู้ใช้", "USER_NOT_FOUND: ไม่พบผู้ใช้"}},
+		{"preferred by quality", "/api-docs/openapi.json", "th;q=0.5,en", "en", []string{"Get a user"}},
+		{"unknown language", "/api-docs/openapi.json", "fr-FR", "en", []string{"Get a user"}},
+		{"lang query parameter", "/api-docs/openapi.json?lang=th", "en", "th", []string{"ดึงข้อมูลผู้ใช้"}},
+		{"docs page", "/api-docs", "th", "th", []string{`<html lang="th">`, "ดึงข้อมูลผู้ใช้", "200: พบผู้ใช้"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if got := resp.Header.Get("Content-Language"); got != tt.language {
+				t.Errorf("Expected Content-Language %q, got %q", tt.language, got)
+			}
+			if vary := resp.Header.Get("Vary"); !strings.Contains(vary, "Accept-Language") {
+				t.Errorf("Expected Vary to contain Accept-Language, got %q", vary)
+			}
+			for _, want := range tt.expected {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("Expected the response to contain %q", want)
+				}
+			}
+		})
+	}
+
+	spec := api.GenerateLocalizedOpenAPISpec("th")
+	if got := spec.Paths["/v1/users/{id}"].Get.Responses["404"].Description; got != "USER_NOT_FOUND: ไม่พบผู้ใช้" {
+		t.Errorf("Unexpected localized 404 description %q", got)
+	}
+	if got := api.GenerateOpenAPISpec().Paths["/v1/users/{id}"].Get.Description; got != "Get a user" {
+		t.Errorf("Expected the default description, got %q", got)
+	}
+}
@@ -0,0 +1,190 @@
+package notelink
+
+import (
+	"container/list"
+	"runtime"
+
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v3"
+)
+
+// Default caps of the in-memory registries, applied to zero MemoryLimits fields
+const (
+	DefaultMaxJobs          = 10000
+	DefaultMaxJobBytes      = 64 << 20
+	DefaultMaxMockResources = 10000
+	DefaultMaxMockBytes     = 64 << 20
+	DefaultMaxArtifacts     = 64
+)
+
+// MemoryLimits caps the in-memory registries growing with traffic: the jobs of
+// the in-memory job store, the resources of the mock state and the docs pages
+// and specs rendered per base URL and locale. When a cap is
+// reached the least recently used entries are evicted, so long-running
+// services stay stable. Zero fields use the defaults, negative ones disable
+// the cap. Sizes are approximated by the JSON encoding of the entries.
+type MemoryLimits struct {
+	MaxJobs          int
+	MaxJobBytes      int64
+	MaxMockResources int
+	MaxMockBytes     int64
+	// MaxArtifacts caps the rendered docs pages and specs, which are cached
+	// per base URL: with TrustProxyHeaders or without Host, per requested host
+	MaxArtifacts int
+}
+
+// RegistryStats reports the size of an in-memory registry; a zero max is uncapped
+type RegistryStats struct {
+	Name       string `json:"name"`
+	Entries    int    `json:"entries"`
+	Bytes      int64  `json:"bytes,omitempty"`
+	MaxEntries int    `json:"maxEntries,omitempty"`
+	MaxBytes   int64  `json:"maxBytes,omitempty"`
+	Evictions  int64  `json:"evictions"`
+}
+
+// MemoryStats reports the sizes of the in-memory registries and of the heap,
+// served at /api-docs/debug behind Config.DocsAuth or with
+// Config.PublicDiagnostics
+type MemoryStats struct {
+	Registries []RegistryStats `json:"registries"`
+	HeapAlloc  uint64          `json:"heapAlloc"`
+	Goroutines int             `json:"goroutines"`
+}
+
+// limit returns a cap, its default when zero, or no cap when negative
+func limit[T int | int64](value, fallback T) T {
+	switch {
+	case value == 0:
+		return fallback
+	case value < 0:
+		return 0
+	}
+	return value
+}
+
+// limits returns the configured limits, or the defaults
+func (an *ApiNote) limits() MemoryLimits {
+	if an.config.MemoryLimits == nil {
+		return MemoryLimits{}
+	}
+	return *an.config.MemoryLimits
+}
+
+// lruEntry is a key tracked by an lruTracker with its approximate size
+type lruEntry struct {
+	key  string
+	size int64
+}
+
+// lruTracker orders the keys of a registry by use and reports the least
+// recently used ones once it exceeds its caps. It isn't safe for concurrent
+// use; the registry owning it holds its lock.
+type lruTracker struct {
+	maxEntries int   // 0 for no cap
+	maxBytes   int64 // 0 for no cap
+	order      *list.List
+	elements   map[string]*list.Element
+	bytes      int64
+	evictions  int64
+}
+
+// newLRUTracker creates a tracker with caps, 0 for none
+func newLRUTracker(maxEntries int, maxBytes int64) *lruTracker {
+	return &lruTracker{maxEntries: maxEntries, maxBytes: maxBytes, order: list.New(), elements: make(map[string]*list.Element)}
+}
+
+// touch marks a key as most recently used with its current size and returns
+// the keys to evict, least recently used first. The touched key itself is
+// never evicted.
+func (t *lruTracker) touch(key string, size int64) []string {
+	if element, ok := t.elements[key]; ok {
+		entry := element.Value.(*lruEntry)
+		t.bytes += size - entry.size
+		entry.size = size
+		t.order.MoveToFront(element)
+	} else {
+		t.elements[key] = t.order.PushFront(&lruEntry{key: key, size: size})
+		t.bytes += size
+	}
+
+	var evicted []string
+	for t.order.Len() > 1 && t.exceeded() {
+		entry := t.order.Remove(t.order.Back()).(*lruEntry)
+		delete(t.elements, entry.key)
+		t.bytes -= entry.size
+		t.evictions++
+		evicted = append(evicted, entry.key)
+	}
+	return evicted
+}
+
+// use marks a key as most recently used
+func (t *lruTracker) use(key string) {
+	if element, ok := t.elements[key]; ok {
+		t.order.MoveToFront(element)
+	}
+}
+
+// remove stops tracking a key
+func (t *lruTracker) remove(key string) {
+	if element, ok := t.elements[key]; ok {
+		t.bytes -= t.order.Remove(element).(*lruEntry).size
+		delete(t.elements, key)
+	}
+}
+
+// exceeded reports whether the tracker is over one of its caps
+func (t *lruTracker) exceeded() bool {
+	return (t.maxEntries > 0 && t.order.Len() > t.maxEntries) || (t.maxBytes > 0 && t.bytes > t.maxBytes)
+}
+
+// stats reports the tracked entries as a registry
+func (t *lruTracker) stats(name string) RegistryStats {
+	return RegistryStats{Name: name, Entries: t.order.Len(), Bytes: t.bytes, MaxEntries: t.maxEntries, MaxBytes: t.maxBytes, Evictions: t.evictions}
+}
+
+// approximateSize returns the size of a value's JSON encoding, 0 when it has none
+func approximateSize(value interface{}) int64 {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+// MemoryStats reports the sizes of the in-memory registries, e.g. to check
+// that long-running services stay within their MemoryLimits
+func (an *ApiNote) MemoryStats() MemoryStats {
+	stats := MemoryStats{
+		Registries: []RegistryStats{
+			{Name: "endpoints", Entries: len(an.endpoints)},
+			{Name: "component_schemas", Entries: len(an.componentTypes)},
+			{Name: "error_codes", Entries: len(an.errorCodes)},
+			{Name: "deprecated_field_usage", Entries: len(an.DeprecatedFieldUsage())},
+			{Name: "deprecated_route_callers", Entries: len(an.DeprecatedRouteUsage())},
+			an.mockState.stats(),
+			an.artifacts.stats(),
+		},
+		Goroutines: runtime.NumGoroutine(),
+	}
+	// Jobs of a Config.JobStore other than a MemoryJobStore aren't reported
+	if store, ok := an.jobStore().(*MemoryJobStore); ok {
+		stats.Registries = append(stats.Registries, store.stats())
+	}
+	if an.apiKeys != nil {
+		if store, ok := an.apiKeys.store.(*MemoryAPIKeyStore); ok {
+			stats.Registries = append(stats.Registries, store.stats())
+		}
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	stats.HeapAlloc = mem.HeapAlloc
+	return stats
+}
+
+// debugHandler serves MemoryStats
+func (an *ApiNote) debugHandler(c fiber.Ctx) error {
+	return c.JSON(an.MemoryStats())
+}
@@ -0,0 +1,118 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+// TestMemoryJobStoreLimits tests evicting the least recently used jobs
+func TestMemoryJobStoreLimits(t *testing.T) {
+	jobSize := approximateSize(&Job{ID: "a", Status: JobSucceeded, Result: strings.Repeat("x", 60)})
+	tests := []struct {
+		name     string
+		maxJobs  int
+		maxBytes int64
+		result   string
+		kept     []string
+		evicted  []string
+	}{
+		{"max jobs", 2, -1, "", []string{"a", "c"}, []string{"b"}},
+		{"max bytes", -1, jobSize * 5 / 2, strings.Repeat("x", 60), []string{"a", "c"}, []string{"b"}},
+		{"uncapped", -1, -1, strings.Repeat("x", 60), []string{"a", "b", "c"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewLimitedMemoryJobStore(tt.maxJobs, tt.maxBytes)
+			store.Save(&Job{ID: "a", Status: JobSucceeded, Result: tt.result})
+			store.Save(&Job{ID: "b", Status: JobSucceeded, Result: tt.result})
+			store.Get("a") // a is now more recently used than b
+			store.Save(&Job{ID: "c", Status: JobSucceeded, Result: tt.result})
+
+			for _, id := range tt.kept {
+				if _, err := store.Get(id); err != nil {
+					t.Errorf("Expected job %s to be kept, got %v", id, err)
+				}
+			}
+			for _, id := range tt.evicted {
+				if _, err := store.Get(id); err != ErrJobNotFound {
+					t.Errorf("Expected job %s to be evicted, got %v", id, err)
+				}
+			}
+			if stats := store.stats(); stats.Evictions != int64(len(tt.evicted)) {
+				t.Errorf("Expected %d evictions, got %d", len(tt.evicted), stats.Evictions)
+			}
+		})
+	}
+}
+
+// TestMockStateLimits tests evicting the least recently used mock resources
+func TestMockStateLimits(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", MemoryLimits: &MemoryLimits{MaxMockResources: 3}}, "secret")
+	state := api.MockState()
+	for i := 1; i <= 3; i++ {
+		state.Create("users", map[string]interface{}{"name": "user " + strconv.Itoa(i)})
+	}
+	state.Get("users", "1")
+	state.Create("orders", map[string]interface{}{"total": 10})
+
+	if _, ok := state.Get("users", "2"); ok {
+		t.Error("Expected the least recently used resource to be evicted")
+	}
+	if len(state.List("users")) != 2 || len(state.List("orders")) != 1 {
+		t.Errorf("Expected 2 users and 1 order, got %d and %d", len(state.List("users")), len(state.List("orders")))
+	}
+	state.Delete("users", "1")
+	state.Clear("orders")
+	if stats := state.stats(); stats.Entries != 1 || stats.Evictions != 1 || stats.MaxEntries != 3 {
+		t.Errorf("Expected 1 resource, 1 eviction and a cap of 3, got %+v", stats)
+	}
+}
+
+// TestDebugEndpoint tests reporting the registry sizes
+func TestDebugEndpoint(t *testing.T) {
+	hidden := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	resp, err := hidden.Fiber().Test(httptest.NewRequest("GET", "/api-docs/debug", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 404 {
+		t.Errorf("Expected the registry sizes hidden without DocsAuth or PublicDiagnostics, got %d", resp.StatusCode)
+	}
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", PublicDiagnostics: true}, "secret")
+	api.MockState().Create("users", map[string]interface{}{"name": "Ada"})
+
+	resp, err = api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/debug", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != 200 || resp.Header.Get("Cache-Control") != "no-store" {
+		t.Fatalf("Expected an uncached 200, got %d %s", resp.StatusCode, resp.Header.Get("Cache-Control"))
+	}
+
+	var stats MemoryStats
+	if err := json.Unmarshal(body, &stats); err != nil {
+		t.Fatalf("Failed to decode the stats: %v", err)
+	}
+	registries := map[string]RegistryStats{}
+	for _, registry := range stats.Registries {
+		registries[registry.Name] = registry
+	}
+	if mock := registries["mock_resources"]; mock.Entries != 1 || mock.MaxEntries != DefaultMaxMockResources || mock.Bytes == 0 {
+		t.Errorf("Expected 1 mock resource under the default cap, got %+v", mock)
+	}
+	if jobs, ok := registries["jobs"]; !ok || jobs.MaxEntries != DefaultMaxJobs {
+		t.Errorf("Expected the job store under the default cap, got %+v", jobs)
+	}
+	if _, ok := registries["endpoints"]; !ok || stats.HeapAlloc == 0 {
+		t.Error("Expected the endpoints registry and heap size")
+	}
+}
@@ -0,0 +1,62 @@
+package notelink
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// methodNotAllowedDescription documents the 405 response of documented paths
+const methodNotAllowedDescription = "Method not allowed; the Allow header lists the methods of the path"
+
+// documentMethodNotAllowed documents the 405 response Fiber sends, with an
+// Allow header, for unregistered methods on the path of a route. Every
+// documented path has at least its route and the OPTIONS route mountOptions
+// adds, so a single-method path answers 405 too.
+func (an *ApiNote) documentMethodNotAllowed(path string) {
+	for key, endpoint := range an.endpoints {
+		if endpoint.Path != path {
+			continue
+		}
+		if _, ok := endpoint.Responses["405"]; ok {
+			continue
+		}
+		if endpoint.Responses == nil {
+			endpoint.Responses = make(map[string]string)
+		}
+		endpoint.Responses["405"] = methodNotAllowedDescription
+		an.endpoints[key] = endpoint
+	}
+}
+
+// allowedMethods returns the methods Fiber lists in the Allow header of a
+// path, including the HEAD routes it adds for GET routes and OPTIONS, which
+// is answered for every documented path
+func (an *ApiNote) allowedMethods(path string) string {
+	methods := []string{fiber.MethodOptions}
+	for _, endpoint := range an.endpoints {
+		if endpoint.Path != path {
+			continue
+		}
+		method := strings.ToUpper(endpoint.Method)
+		if !slices.Contains(methods, method) {
+			methods = append(methods, method)
+		}
+		if method == fiber.MethodGet && !slices.Contains(methods, fiber.MethodHead) {
+			methods = append(methods, fiber.MethodHead)
+		}
+	}
+	slices.Sort(methods)
+	return strings.Join(methods, ", ")
+}
+
+// allowHeader documents the Allow header of a path's 405 response
+func (an *ApiNote) allowHeader(path string) map[string]HeaderObject {
+	return map[string]HeaderObject{
+		fiber.HeaderAllow: {
+			Description: "Methods of the path: " + an.allowedMethods(path),
+			Schema:      &JSONSchema{Type: "string"},
+		},
+	}
+}
@@ -0,0 +1,58 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestMethodNotAllowed tests the 405 responses of documented paths
+func TestMethodNotAllowed(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+	routes := []DocumentedRouteInput{
+		{Method: "GET", Path: "/v1/users", Responses: map[string]string{"200": "Users"}, Handler: handler},
+		{Method: "POST", Path: "/v1/users", Responses: map[string]string{"201": "Created"}, Handler: handler},
+		{Method: "PUT", Path: "/v1/users", Responses: map[string]string{"405": "Use PATCH"}, Handler: handler},
+		{Method: "GET", Path: "/v1/health", Handler: handler},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	for _, path := range []string{"/v1/users", "/v1/health"} {
+		resp, err := api.Fiber().Test(httptest.NewRequest("DELETE", path, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusMethodNotAllowed || resp.Header.Get("Allow") == "" {
+			t.Errorf("%s: expected 405 with an Allow header, got %d %q", path, resp.StatusCode, resp.Header.Get("Allow"))
+		}
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	users := spec.Paths["/v1/users"]
+	response, ok := users.Get.Responses["405"]
+	if !ok || response.Description != methodNotAllowedDescription {
+		t.Fatalf("Expected a documented 405 response, got %+v", users.Get.Responses)
+	}
+	if allow := response.Headers["Allow"]; allow.Description != "Methods of the path: GET, HEAD, OPTIONS, POST, PUT" {
+		t.Errorf("Expected the Allow header to list the path's methods, got %q", allow.Description)
+	}
+	if users.Post.Responses["405"].Description != methodNotAllowedDescription {
+		t.Error("Expected the 405 response on the earlier routes of the path")
+	}
+	if users.Put.Responses["405"].Description != "Use PATCH" {
+		t.Errorf("Expected a declared 405 response to be kept, got %q", users.Put.Responses["405"].Description)
+	}
+	health := spec.Paths["/v1/health"].Get.Responses["405"]
+	if health.Description != methodNotAllowedDescription {
+		t.Errorf("Expected a documented 405 response on single-method paths, got %+v", spec.Paths["/v1/health"].Get.Responses)
+	}
+	if allow := health.Headers["Allow"]; allow.Description != "Methods of the path: GET, HEAD, OPTIONS" {
+		t.Errorf("Expected the Allow header to list the single-method path's methods, got %q", allow.Description)
+	}
+}
@@ -0,0 +1,105 @@
+package notelink
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// namedMiddleware is a global middleware added with Use, UseNamed, UseBefore or UseAfter
+type namedMiddleware struct {
+	name    string // Empty for middleware added with Use
+	handler fiber.Handler
+}
+
+// UseNamed adds a named middleware to all subsequent routes, after the
+// middleware already added. UseBefore and UseAfter position other middleware
+// relative to it.
+func (an *ApiNote) UseNamed(name string, middleware fiber.Handler) error {
+	return an.insertMiddleware(len(an.middlewares), name, middleware)
+}
+
+// UseBefore adds a named middleware to all subsequent routes, running right
+// before the middleware named existing.
+//
+// Example:
+//
+//	api.UseNamed("logger", RequestLoggerMiddleware())
+//	api.UseBefore("logger", "request-id", RequestIDMiddleware())
+func (an *ApiNote) UseBefore(existing, name string, middleware fiber.Handler) error {
+	index, err := an.middlewareIndex(existing)
+	if err != nil {
+		return err
+	}
+	return an.insertMiddleware(index, name, middleware)
+}
+
+// UseAfter adds a named middleware to all subsequent routes, running right
+// after the middleware named existing
+func (an *ApiNote) UseAfter(existing, name string, middleware fiber.Handler) error {
+	index, err := an.middlewareIndex(existing)
+	if err != nil {
+		return err
+	}
+	return an.insertMiddleware(index+1, name, middleware)
+}
+
+// middlewareIndex returns the position of a named middleware
+func (an *ApiNote) middlewareIndex(name string) (int, error) {
+	for i, middleware := range an.middlewares {
+		if middleware.name == name {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("middleware %q is not registered", name)
+}
+
+// insertMiddleware adds a named middleware at index, rejecting duplicate names
+func (an *ApiNote) insertMiddleware(index int, name string, middleware fiber.Handler) error {
+	if name == "" {
+		return fmt.Errorf("middleware name is required")
+	}
+	if middleware == nil {
+		return fmt.Errorf("middleware %q has no handler", name)
+	}
+	if _, err := an.middlewareIndex(name); err == nil {
+		return fmt.Errorf("middleware %q is already registered", name)
+	}
+
+	an.middlewares = append(an.middlewares, namedMiddleware{})
+	copy(an.middlewares[index+1:], an.middlewares[index:])
+	an.middlewares[index] = namedMiddleware{name: name, handler: middleware}
+	return nil
+}
+
+// RouteScope registers routes with extra middleware that runs after the global
+// middleware, without affecting routes registered elsewhere. Create one with
+// ApiNote.WithMiddlewares.
+//
+// Example:
+//
+//	admin := api.WithMiddlewares(RequireRole("admin"), AuditLog())
+//	admin.DocumentedRoute(&notelink.DocumentedRouteInput{...})
+type RouteScope struct {
+	api         *ApiNote
+	middlewares []fiber.Handler
+}
+
+// WithMiddlewares returns a scope whose routes run middleware, in order, after
+// the global middleware added with Use
+func (an *ApiNote) WithMiddlewares(middleware ...fiber.Handler) *RouteScope {
+	return &RouteScope{api: an, middlewares: append([]fiber.Handler(nil), middleware...)}
+}
+
+// WithMiddlewares returns a nested scope running the scope's middleware, then middleware
+func (s *RouteScope) WithMiddlewares(middleware ...fiber.Handler) *RouteScope {
+	middlewares := make([]fiber.Handler, 0, len(s.middlewares)+len(middleware))
+	middlewares = append(middlewares, s.middlewares...)
+	return &RouteScope{api: s.api, middlewares: append(middlewares, middleware...)}
+}
+
+// DocumentedRoute registers a documented route running the scope's middleware
+// right before its handler
+func (s *RouteScope) DocumentedRoute(input *DocumentedRouteInput) error {
+	return s.api.documentedRoute(input, s.middlewares)
+}
@@ -0,0 +1,81 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestMiddlewareScopes tests middleware ordering and per-route scopes
+func TestMiddlewareScopes(t *testing.T) {
+	// trace appends its name to the order the middleware ran in
+	trace := func(name string) fiber.Handler {
+		return func(c fiber.Ctx) error {
+			order, _ := c.Locals("order").(string)
+			c.Locals("order", order+name+",")
+			return c.Next()
+		}
+	}
+	handler := func(c fiber.Ctx) error {
+		order, _ := c.Locals("order").(string)
+		return c.SendString(order)
+	}
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	api.Use(trace("global"))
+	if err := api.UseNamed("logger", trace("logger")); err != nil {
+		t.Fatalf("Failed to add middleware: %v", err)
+	}
+	if err := api.UseBefore("logger", "request-id", trace("request-id")); err != nil {
+		t.Fatalf("Failed to add middleware: %v", err)
+	}
+	if err := api.UseAfter("request-id", "tracing", trace("tracing")); err != nil {
+		t.Fatalf("Failed to add middleware: %v", err)
+	}
+	if err := api.UseNamed("logger", trace("logger")); err == nil {
+		t.Error("Expected an error for a duplicate middleware name")
+	}
+	if err := api.UseBefore("missing", "x", trace("x")); err == nil {
+		t.Error("Expected an error for an unknown middleware")
+	}
+
+	admin := api.WithMiddlewares(trace("admin"))
+	audited := admin.WithMiddlewares(trace("audit"))
+	routes := []struct {
+		register func(*DocumentedRouteInput) error
+		path     string
+	}{
+		{register: api.DocumentedRoute, path: "/public"},
+		{register: admin.DocumentedRoute, path: "/admin"},
+		{register: audited.DocumentedRoute, path: "/admin/audited"},
+	}
+	for _, route := range routes {
+		if err := route.register(&DocumentedRouteInput{Method: "GET", Path: route.path, Handler: handler}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/public", expected: "global,request-id,tracing,logger,"},
+		{path: "/admin", expected: "global,request-id,tracing,logger,admin,"},
+		{path: "/admin/audited", expected: "global,request-id,tracing,logger,admin,audit,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.expected {
+				t.Errorf("Expected middleware order %q, got %q", tt.expected, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package notelink
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MigrationGuide tells the consumers of a deprecated route what to switch to,
+// e.g.
+//
+//	{Method: "GET", Path: "/v2/users/{id}", Sunset: "2027-01-31", Markdown: "`name` is split into `givenName` and `familyName`."}
+//
+// The docs show it at the top of the route, the spec exports it as
+// x-migration and responses announce the successor and sunset date.
+type MigrationGuide struct {
+	// Method and Path identify the replacement route, ":id" and "{id}"
+	// segments alike; Method defaults to GET
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path"`
+	// Markdown explains the migration, e.g. renamed fields
+	Markdown string `json:"markdown,omitempty"`
+	// Sunset is the date the route stops being served, YYYY-MM-DD, sent in
+	// the Sunset header (RFC 8594)
+	Sunset string `json:"sunset,omitempty"`
+}
+
+// MigrationObject is the x-migration extension of a deprecated operation,
+// referencing its replacement by operationId when it is documented and by
+// operationRef otherwise
+type MigrationObject struct {
+	OperationID  string `json:"operationId,omitempty"`
+	OperationRef string `json:"operationRef,omitempty"`
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Guide        string `json:"guide,omitempty"`
+	Sunset       string `json:"sunset,omitempty"`
+}
+
+// method returns the replacement's HTTP method, defaulting to GET
+func (g *MigrationGuide) method() string {
+	if g.Method == "" {
+		return http.MethodGet
+	}
+	return strings.ToUpper(g.Method)
+}
+
+// link returns the replacement as a Link, to resolve it like one
+func (g *MigrationGuide) link() *Link {
+	return &Link{Href: g.Path, Method: g.method()}
+}
+
+// validateMigrationGuide checks that a guide belongs to a deprecated route and
+// names its replacement
+func validateMigrationGuide(guide *MigrationGuide, deprecated bool) error {
+	if !deprecated {
+		return fmt.Errorf("a MigrationGuide requires Deprecated")
+	}
+	if !strings.HasPrefix(guide.Path, "/") {
+		return fmt.Errorf("MigrationGuide.Path %q must be the replacement's path, e.g. /v2/users/{id}", guide.Path)
+	}
+	if guide.Sunset != "" {
+		if _, err := time.Parse(time.DateOnly, guide.Sunset); err != nil {
+			return fmt.Errorf("MigrationGuide.Sunset %q is not YYYY-MM-DD", guide.Sunset)
+		}
+	}
+	return nil
+}
+
+// sunsetHeader returns the Sunset header of a guide, or "" without a date
+func (g *MigrationGuide) sunsetHeader() string {
+	date, err := time.Parse(time.DateOnly, g.Sunset)
+	if err != nil {
+		return ""
+	}
+	return date.UTC().Format(http.TimeFormat)
+}
+
+// successorLink returns the Link header pointing to the replacement, or ""
+// when its path has parameters and so names no single resource
+func (an *ApiNote) successorLink(guide *MigrationGuide) string {
+	path := guide.Path
+	if target := an.resolveLink(guide.link()); target != nil {
+		path = target.Path
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if isPathParamSegment(segment) || strings.ContainsAny(segment, "*+?") {
+			return ""
+		}
+	}
+	return "<" + path + `>; rel="successor-version"`
+}
+
+// migrationObject converts a guide to its x-migration extension
+func (an *ApiNote) migrationObject(guide *MigrationGuide) *MigrationObject {
+	link := an.linkObject(guide.link())
+	object := &MigrationObject{
+		OperationID:  link.OperationID,
+		OperationRef: link.OperationRef,
+		Method:       guide.method(),
+		Path:         openAPIPath(guide.Path),
+		Guide:        guide.Markdown,
+		Sunset:       guide.Sunset,
+	}
+	if target := an.resolveLink(guide.link()); target != nil {
+		object.Path = openAPIPath(target.routePath())
+	}
+	return object
+}
+
+// renderMigrationGuide renders the migration callout at the top of a
+// deprecated endpoint, linking to the replacement when it is documented
+func (an *ApiNote) renderMigrationGuide(endpoint *Endpoint) string {
+	guide := endpoint.Migration
+	if guide == nil {
+		return ""
+	}
+
+	target := `<code>` + escapeHTML(guide.method()+" "+guide.Path) + `</code>`
+	if resolved := an.resolveLink(guide.link()); resolved != nil {
+		target = `<a href="#` + operationAnchor(resolved.Method, resolved.routePath()) + `"><code>` + escapeHTML(resolved.Method+" "+resolved.Path) + `</code></a>`
+	}
+	var html strings.Builder
+	html.WriteString(`
+                    <div class="migration-guide" role="note">
+                        <h4><i class="fas fa-route"></i> Deprecated: migrate to ` + target + `</h4>`)
+	if guide.Sunset != "" {
+		html.WriteString(`
+                        <p class="migration-sunset">This route stops being served on <time datetime="` + escapeHTML(guide.Sunset) + `">` + escapeHTML(guide.Sunset) + `</time>.</p>`)
+	}
+	if guide.Markdown != "" {
+		html.WriteString(`
+                        <div class="migration-steps">` + renderMarkdown(guide.Markdown) + `</div>`)
+	}
+	html.WriteString(`
+                    </div>`)
+	return html.String()
+}
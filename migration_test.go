@@ -0,0 +1,95 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestMigrationGuide tests pointing deprecated routes to their replacement
+func TestMigrationGuide(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+	api := NewApiNote(&Config{Title: "Test API", DocsUI: "notelink"}, "secret")
+	routes := []DocumentedRouteInput{
+		{
+			Method: "GET", Path: "/v1/users/:id", Description: "Get a user", Handler: handler, Deprecated: true,
+			Params:    []Parameter{{Name: "id", In: "path", Type: "string", Required: true}},
+			Migration: &MigrationGuide{Path: "/v2/users/:id", Sunset: "2027-01-31", Markdown: "`name` is split into `givenName` and `familyName`."},
+		},
+		{Method: "GET", Path: "/v1/users", Description: "List users", Handler: handler, Deprecated: true, Migration: &MigrationGuide{Path: "/v2/users"}},
+		{Method: "POST", Path: "/v1/exports", Description: "Export", Handler: handler, Deprecated: true, Migration: &MigrationGuide{Method: "post", Path: "/v3/exports/{format}"}},
+		// The replacements are registered after the routes they replace
+		{Method: "GET", Path: "/v2/users/:id", Description: "Get a user", Handler: handler, Params: []Parameter{{Name: "id", In: "path", Type: "string", Required: true}}},
+		{Method: "GET", Path: "/v2/users", Description: "List users", Handler: handler},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	migration := spec.Paths["/v1/users/{id}"].Get.Migration
+	if migration == nil || migration.OperationID != generateOperationID("GET", "/v2/users/:id") || migration.Path != "/v2/users/{id}" || migration.Sunset != "2027-01-31" || !strings.Contains(migration.Guide, "givenName") {
+		t.Errorf("Expected x-migration to reference the documented replacement, got %+v", migration)
+	}
+	if migration := spec.Paths["/v1/exports"].Post.Migration; migration == nil || migration.OperationRef != "#/paths/~1v3~1exports~1{format}/post" || migration.Method != "POST" {
+		t.Errorf("Expected an operationRef to an undocumented replacement, got %+v", migration)
+	}
+	if spec.Paths["/v2/users"].Get.Migration != nil {
+		t.Error("Expected no x-migration on the replacement")
+	}
+
+	tests := []struct {
+		path   string
+		sunset string
+		link   string
+	}{
+		{path: "/v1/users/42", sunset: "Sun, 31 Jan 2027 00:00:00 GMT"},
+		{path: "/v1/users", link: `</v2/users>; rel="successor-version"`},
+	}
+	for _, tt := range tests {
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", tt.path, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		if got := resp.Header.Get("Sunset"); got != tt.sunset {
+			t.Errorf("%s: expected Sunset %q, got %q", tt.path, tt.sunset, got)
+		}
+		if got := resp.Header.Get("Link"); got != tt.link {
+			t.Errorf("%s: expected Link %q, got %q", tt.path, tt.link, got)
+		}
+	}
+
+	html := api.generateHTML()
+	for _, expected := range []string{
+		`Deprecated: migrate to <a href="#` + operationAnchor("GET", "/v2/users/:id") + `"><code>GET /v2/users/:id</code></a>`,
+		`stops being served on <time datetime="2027-01-31">2027-01-31</time>`,
+		`<code>givenName</code>`,
+		`Deprecated: migrate to <code>POST /v3/exports/{format}</code>`,
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected the docs to contain %q", expected)
+		}
+	}
+
+	invalid := []struct {
+		name  string
+		input DocumentedRouteInput
+		err   string
+	}{
+		{name: "not deprecated", input: DocumentedRouteInput{Method: "GET", Path: "/old", Handler: handler, Migration: &MigrationGuide{Path: "/new"}}, err: "requires Deprecated"},
+		{name: "relative path", input: DocumentedRouteInput{Method: "GET", Path: "/old", Handler: handler, Deprecated: true, Migration: &MigrationGuide{Path: "new"}}, err: "must be the replacement's path"},
+		{name: "sunset date", input: DocumentedRouteInput{Method: "GET", Path: "/old", Handler: handler, Deprecated: true, Migration: &MigrationGuide{Path: "/new", Sunset: "31/01/2027"}}, err: "is not YYYY-MM-DD"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API"}, "secret")
+			if err := api.DocumentedRoute(&tt.input); err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
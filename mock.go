@@ -0,0 +1,262 @@
+package notelink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ErrMockSkip is returned by MockRule.Respond to pass the request on to the
+// next rule, e.g. when the requested resource isn't in the MockState
+var ErrMockSkip = errors.New("notelink: mock rule skipped")
+
+// MockRule customizes a route's response in mock mode (Config.Mock). Rules are
+// tried in order and the first whose Params and Body match responds; without a
+// match the route returns its generated example.
+//
+//	notelink.MockRule{Params: map[string]string{"id": "404"}, Status: 404, Response: fiber.Map{"error": "not found"}}
+//	notelink.MockRule{Respond: func(c fiber.Ctx, state *notelink.MockState) error {
+//	    return c.Status(201).JSON(state.Create("users", body))
+//	}}
+type MockRule struct {
+	Params   map[string]string                         // Path and query parameter values that must match
+	Body     map[string]interface{}                    // Top-level request body fields that must match
+	Status   int                                       // Response status; defaults to the route's success status
+	Response interface{}                               // Response body; defaults to the generated example
+	Respond  func(c fiber.Ctx, state *MockState) error // Custom response with access to the shared state, overriding Status and Response
+}
+
+// MockState is an in-memory store shared by mock rules, holding resources per
+// collection so a created resource can be read, listed and deleted afterwards.
+// Beyond DefaultMaxMockResources resources or DefaultMaxMockBytes of them (or
+// Config.MemoryLimits), the least recently used resources are evicted.
+type MockState struct {
+	mu          sync.Mutex
+	collections map[string]map[string]map[string]interface{}
+	nextID      int
+	lru         *lruTracker
+}
+
+// mockResourceKey keys a resource in the LRU tracker
+func mockResourceKey(collection, id string) string {
+	return collection + "\x00" + id
+}
+
+// setLimits caps the resources of the state
+func (s *MockState) setLimits(maxResources int, maxBytes int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lru = newLRUTracker(limit(maxResources, DefaultMaxMockResources), limit(maxBytes, DefaultMaxMockBytes))
+}
+
+// tracker returns the LRU tracker, with the default caps unless limited; the
+// caller holds the lock
+func (s *MockState) tracker() *lruTracker {
+	if s.lru == nil {
+		s.lru = newLRUTracker(DefaultMaxMockResources, DefaultMaxMockBytes)
+	}
+	return s.lru
+}
+
+// stats reports the size of the state
+func (s *MockState) stats() RegistryStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tracker().stats("mock_resources")
+}
+
+// Create stores a resource in a collection, assigning an "id" when missing, and returns it
+func (s *MockState) Create(collection string, resource map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := make(map[string]interface{}, len(resource)+1)
+	for key, value := range resource {
+		stored[key] = value
+	}
+	if _, ok := stored["id"]; !ok {
+		s.nextID++
+		stored["id"] = strconv.Itoa(s.nextID)
+	}
+	if s.collections == nil {
+		s.collections = make(map[string]map[string]map[string]interface{})
+	}
+	if s.collections[collection] == nil {
+		s.collections[collection] = make(map[string]map[string]interface{})
+	}
+	id := fmt.Sprint(stored["id"])
+	s.collections[collection][id] = stored
+	for _, key := range s.tracker().touch(mockResourceKey(collection, id), approximateSize(stored)) {
+		evictedCollection, evictedID, _ := strings.Cut(key, "\x00")
+		delete(s.collections[evictedCollection], evictedID)
+	}
+	return stored
+}
+
+// Get returns a resource by id
+func (s *MockState) Get(collection, id string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resource, ok := s.collections[collection][id]
+	if ok {
+		s.tracker().use(mockResourceKey(collection, id))
+	}
+	return resource, ok
+}
+
+// List returns the resources of a collection ordered by id
+func (s *MockState) List(collection string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.collections[collection]))
+	for id := range s.collections[collection] {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.Atoi(ids[i])
+		b, errB := strconv.Atoi(ids[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return ids[i] < ids[j]
+	})
+
+	resources := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		resources = append(resources, s.collections[collection][id])
+	}
+	return resources
+}
+
+// Delete removes a resource, reporting whether it existed
+func (s *MockState) Delete(collection, id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.collections[collection][id]; !ok {
+		return false
+	}
+	delete(s.collections[collection], id)
+	s.tracker().remove(mockResourceKey(collection, id))
+	return true
+}
+
+// Clear removes every resource of a collection
+func (s *MockState) Clear(collection string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.collections[collection] {
+		s.tracker().remove(mockResourceKey(collection, id))
+	}
+	delete(s.collections, collection)
+}
+
+// Reset clears every collection, e.g. between end-to-end test runs
+func (s *MockState) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.collections = nil
+	s.nextID = 0
+	if s.lru != nil {
+		s.lru = newLRUTracker(s.lru.maxEntries, s.lru.maxBytes)
+	}
+}
+
+// MockState returns the state shared by the mock rules of every route
+func (an *ApiNote) MockState() *MockState {
+	return &an.mockState
+}
+
+// matches reports whether a request satisfies the rule's conditions
+func (r *MockRule) matches(c fiber.Ctx, endpoint *Endpoint) bool {
+	for name, expected := range r.Params {
+		value := c.Query(name)
+		if hasPathParameter(endpoint.Parameters, name) {
+			value = c.Params(name)
+		}
+		if value != expected {
+			return false
+		}
+	}
+	if len(r.Body) == 0 {
+		return true
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return false
+	}
+	for field, expected := range r.Body {
+		if fmt.Sprint(body[field]) != fmt.Sprint(expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// mockHandler serves a route in mock mode from its rules, falling back to the
+// generated example of its response schema
+func (an *ApiNote) mockHandler(endpoint Endpoint, rules []MockRule) fiber.Handler {
+	status := successStatus(endpoint.Responses)
+	return func(c fiber.Ctx) error {
+		for i := range rules {
+			rule := &rules[i]
+			if !rule.matches(c, &endpoint) {
+				continue
+			}
+			if rule.Respond != nil {
+				if err := rule.Respond(c, &an.mockState); err != ErrMockSkip {
+					return err
+				}
+				continue
+			}
+			ruleStatus := status
+			if rule.Status != 0 {
+				ruleStatus = rule.Status
+			}
+			if rule.Response != nil {
+				return c.Status(ruleStatus).JSON(rule.Response)
+			}
+			return an.mockExample(c, &endpoint, ruleStatus)
+		}
+		if handled, err := an.mockPersisted(c, &endpoint, status); handled {
+			return err
+		}
+		return an.mockExample(c, &endpoint, status)
+	}
+}
+
+// mockExample responds with the generated example of the endpoint's response schema
+func (an *ApiNote) mockExample(c fiber.Ctx, endpoint *Endpoint, status int) error {
+	if endpoint.ResponseSchema == nil {
+		return c.SendStatus(status)
+	}
+	example, err := generateJSONTemplate(endpoint.ResponseSchema, an.exampleOptions())
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(status).SendString(example)
+}
+
+// successStatus returns the lowest documented 2xx status, defaulting to 200
+func successStatus(responses map[string]string) int {
+	status := 0
+	for code := range responses {
+		value, err := strconv.Atoi(code)
+		if err == nil && value >= 200 && value < 300 && (status == 0 || value < status) {
+			status = value
+		}
+	}
+	if status == 0 {
+		return http.StatusOK
+	}
+	return status
+}
@@ -0,0 +1,135 @@
+package notelink
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// MockSessionHeader scopes the sample data of Config.MockPersistence; the
+// docs page sends a random value per browser tab while its toggle is on
+const MockSessionHeader = "X-Mock-Session"
+
+// maxMockSessionLength caps MockSessionHeader values, which key the MockState
+const maxMockSessionLength = 64
+
+// mockCollection returns the MockState collection and the resource id of a
+// request persisting sample data, e.g. the /users collection of the session
+// and "7" for GET /users/7. Routes ending in a path parameter address a
+// resource of the collection before it; other routes address the collection
+// at their path.
+func mockCollection(c fiber.Ctx, endpoint *Endpoint) (collection, id string) {
+	segments := strings.Split(strings.TrimSuffix(endpoint.routePath(), "/"), "/")
+	last := segments[len(segments)-1]
+	path := strings.TrimSuffix(c.Path(), "/")
+	if strings.HasPrefix(last, ":") && !isDynamicSegment(last) {
+		id = c.Params(last[1:])
+		path = strings.TrimSuffix(path, "/"+id)
+	}
+	return "mock-session:" + c.Get(MockSessionHeader) + ":" + path, id
+}
+
+// mockPersisted simulates CRUD on the sample data of the request's docs
+// session: POST to a collection stores the body, GET returns a resource or,
+// for list responses, the collection, PUT replaces and PATCH updates a
+// resource and DELETE removes it; unknown resources are 404. It reports false for requests it doesn't
+// simulate, which get the generated example.
+func (an *ApiNote) mockPersisted(c fiber.Ctx, endpoint *Endpoint, status int) (bool, error) {
+	session := c.Get(MockSessionHeader)
+	if !an.config.MockPersistence || session == "" || len(session) > maxMockSessionLength {
+		return false, nil
+	}
+	collection, id := mockCollection(c, endpoint)
+	state := &an.mockState
+
+	switch c.Method() {
+	case http.MethodGet:
+		if id == "" {
+			if endpoint.ResponseSchema == nil || reflect.TypeOf(endpoint.ResponseSchema).Kind() != reflect.Slice {
+				return false, nil
+			}
+			return true, c.Status(status).JSON(state.List(collection))
+		}
+		resource, ok := state.Get(collection, id)
+		if !ok {
+			return true, c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "Sample resource not found"})
+		}
+		return true, c.Status(status).JSON(resource)
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		if (c.Method() == http.MethodPost) != (id == "") {
+			return false, nil
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(c.Body(), &body); err != nil {
+			return false, nil
+		}
+		if id != "" {
+			existing, ok := state.Get(collection, id)
+			if !ok {
+				return true, c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "Sample resource not found"})
+			}
+			if c.Method() == http.MethodPatch {
+				for field, value := range existing {
+					if _, ok := body[field]; !ok {
+						body[field] = value
+					}
+				}
+			}
+			body["id"] = existing["id"]
+		} else if value, ok := body["id"]; ok && (value == nil || value == "" || value == float64(0)) {
+			// Placeholder ids of the example bodies get assigned ones
+			delete(body, "id")
+		}
+		return true, c.Status(status).JSON(state.Create(collection, body))
+	case http.MethodDelete:
+		if id == "" {
+			return false, nil
+		}
+		if !state.Delete(collection, id) {
+			return true, c.Status(fiber.StatusNotFound).JSON(ErrorResponse{Error: "Sample resource not found"})
+		}
+		return true, c.SendStatus(fiber.StatusNoContent)
+	}
+	return false, nil
+}
+
+// renderMockPersistence renders the docs page's toggle persisting the sample
+// data of try-it requests
+func (an *ApiNote) renderMockPersistence() string {
+	if !an.config.Mock || !an.config.MockPersistence {
+		return ""
+	}
+	return `
+        <div class="auth-section mock-persistence">
+            <h2><i class="fas fa-database"></i> Sample data</h2>
+            <label class="mock-persistence-toggle">
+                <input type="checkbox" id="mock-persistence" onchange="setMockPersistence(this.checked)">
+                Keep the sample data of try-it requests: POST stores the body, GET returns it, DELETE removes it
+            </label>
+        </div>`
+}
+
+// mockPersistenceScript scopes try-it requests to a sample data session per
+// browser tab while the toggle is on
+func (an *ApiNote) mockPersistenceScript() string {
+	if !an.config.Mock || !an.config.MockPersistence {
+		return `
+            const mockSession = '';`
+	}
+	return `
+            const mockSessionHeader = '` + MockSessionHeader + `';
+            let mockSession = sessionStorage.getItem('mockSession') || '';
+            function setMockPersistence(enabled) {
+                mockSession = enabled ? (crypto.randomUUID ? crypto.randomUUID() : String(Math.random()).slice(2)) : '';
+                sessionStorage.setItem('mockSession', mockSession);
+            }
+            document.addEventListener('DOMContentLoaded', () => {
+                const toggle = document.getElementById('mock-persistence');
+                if (toggle) {
+                    toggle.checked = mockSession !== '';
+                }
+            });`
+}
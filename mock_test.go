@@ -0,0 +1,164 @@
+package notelink
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestMockMode tests serving routes from mock rules, state and generated examples
+func TestMockMode(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", Mock: true}, "secret")
+	routes := []*DocumentedRouteInput{
+		{
+			Method:          "GET",
+			Path:            "/users/:id",
+			Params:          []Parameter{{Name: "id", In: "path", Type: "string", Required: true}},
+			Responses:       map[string]string{"200": "OK", "404": "Not found"},
+			SchemasResponse: exportUser{},
+			MockRules: []MockRule{
+				{Params: map[string]string{"id": "missing"}, Status: 404, Response: fiber.Map{"error": "not found"}},
+				{Respond: func(c fiber.Ctx, state *MockState) error {
+					if user, ok := state.Get("users", c.Params("id")); ok {
+						return c.JSON(user)
+					}
+					return ErrMockSkip
+				}},
+			},
+		},
+		{
+			Method:          "POST",
+			Path:            "/users",
+			Responses:       map[string]string{"201": "Created", "400": "Invalid"},
+			SchemasRequest:  exportUser{},
+			SchemasResponse: exportUser{},
+			MockRules: []MockRule{
+				{Body: map[string]interface{}{"name": "conflict"}, Status: 409},
+				{Respond: func(c fiber.Ctx, state *MockState) error {
+					var body map[string]interface{}
+					if err := json.Unmarshal(c.Body(), &body); err != nil {
+						return err
+					}
+					delete(body, "id")
+					return c.Status(201).JSON(state.Create("users", body))
+				}},
+			},
+		},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	send := func(method, path, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := api.Fiber().Test(req)
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+	user := `{"id": 0, "name": "Ada", "address": {"city": "London"}}`
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		status  int
+		snippet string
+	}{
+		{name: "Generated example", method: "GET", path: "/users/7", status: 200, snippet: `"address"`},
+		{name: "Matching params", method: "GET", path: "/users/missing", status: 404, snippet: "not found"},
+		{name: "Matching body", method: "POST", path: "/users", body: `{"id": 0, "name": "conflict", "address": {"city": "x"}}`, status: 409},
+		{name: "Create", method: "POST", path: "/users", body: user, status: 201, snippet: `"id":"1"`},
+		{name: "Read created", method: "GET", path: "/users/1", status: 200, snippet: `"name":"Ada"`},
+		{name: "Validation still applies", method: "POST", path: "/users", body: `{"name": 5}`, status: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := send(tt.method, tt.path, tt.body)
+			if status != tt.status || !strings.Contains(body, tt.snippet) {
+				t.Errorf("Expected %d with %q, got %d: %s", tt.status, tt.snippet, status, body)
+			}
+		})
+	}
+
+	api.MockState().Create("users", map[string]interface{}{"id": "b"})
+	if list := api.MockState().List("users"); len(list) != 2 || list[0]["id"] != "1" {
+		t.Errorf("Expected resources ordered by id, got %v", list)
+	}
+	api.MockState().Reset()
+	if _, ok := api.MockState().Get("users", "1"); ok {
+		t.Error("Expected Reset to clear the state")
+	}
+}
+
+// TestMockPersistence tests the CRUD simulation of sample data per docs session
+func TestMockPersistence(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", Mock: true, MockPersistence: true}, "secret")
+	routes := []*DocumentedRouteInput{
+		{Method: "POST", Path: "/users", Responses: map[string]string{"201": "Created"}, SchemasRequest: exportUser{}, SchemasResponse: exportUser{}},
+		{Method: "GET", Path: "/users", SchemasResponse: []exportUser{}},
+		{Method: "GET", Path: "/users/:id", Params: []Parameter{{Name: "id", In: "path", Type: "string", Required: true}}, SchemasResponse: exportUser{}},
+		{Method: "PATCH", Path: "/users/:id", Params: []Parameter{{Name: "id", In: "path", Type: "string", Required: true}}, SchemasResponse: exportUser{}},
+		{Method: "DELETE", Path: "/users/:id", Params: []Parameter{{Name: "id", In: "path", Type: "string", Required: true}}, Responses: map[string]string{"204": "Deleted"}},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		session string
+		method  string
+		path    string
+		body    string
+		status  int
+		snippet string
+	}{
+		{name: "Create", session: "tab-a", method: "POST", path: "/users", body: `{"id": 0, "name": "Ada", "address": {"city": "London"}}`, status: 201, snippet: `"id":"1"`},
+		{name: "Read", session: "tab-a", method: "GET", path: "/users/1", status: 200, snippet: `"name":"Ada"`},
+		{name: "List", session: "tab-a", method: "GET", path: "/users", status: 200, snippet: `[{"address"`},
+		{name: "Update", session: "tab-a", method: "PATCH", path: "/users/1", body: `{"id": 1, "name": "Grace", "address": {"city": "London"}}`, status: 200, snippet: `"city":"London"`},
+		{name: "Read updated", session: "tab-a", method: "GET", path: "/users/1", status: 200, snippet: `"name":"Grace"`},
+		{name: "Other session", session: "tab-b", method: "GET", path: "/users/1", status: 404, snippet: "not found"},
+		{name: "Other session list", session: "tab-b", method: "GET", path: "/users", status: 200, snippet: `[]`},
+		{name: "Without a session", method: "GET", path: "/users/1", status: 200, snippet: `"address"`},
+		{name: "Delete", session: "tab-a", method: "DELETE", path: "/users/1", status: 204},
+		{name: "Read deleted", session: "tab-a", method: "GET", path: "/users/1", status: 404, snippet: "not found"},
+		{name: "Delete again", session: "tab-a", method: "DELETE", path: "/users/1", status: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.session != "" {
+				req.Header.Set(MockSessionHeader, tt.session)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			data, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status || !strings.Contains(string(data), tt.snippet) {
+				t.Errorf("Expected %d with %q, got %d: %s", tt.status, tt.snippet, resp.StatusCode, data)
+			}
+		})
+	}
+
+	if html := api.generateHTML(); !strings.Contains(html, `id="mock-persistence"`) {
+		t.Error("Expected the sample data toggle on the docs page")
+	}
+}
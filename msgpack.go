@@ -0,0 +1,244 @@
+package notelink
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// isMsgPackContentType reports whether a Content-Type header denotes a MessagePack body
+func isMsgPackContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch mediaType {
+	case MIMEApplicationMsgPack, fiber.MIMEApplicationMsgPack, "application/x-msgpack":
+		return true
+	default:
+		return false
+	}
+}
+
+// DecodeBody decodes the request body into out based on its Content-Type.
+// MessagePack bodies (application/msgpack, application/x-msgpack and
+// application/vnd.msgpack) are decoded with the app's MsgPackDecoder; all other
+// content types are handled by Fiber's body binder.
+func DecodeBody(c fiber.Ctx, out interface{}) error {
+	if isMsgPackContentType(c.Get(fiber.HeaderContentType)) {
+		return c.App().Config().MsgPackDecoder(c.Body(), out)
+	}
+	return c.Bind().Body(out)
+}
+
+// decodeMsgPackMap decodes a MessagePack body into the JSON-shaped map used by
+// the validator, so the same type rules apply to both encodings.
+func decodeMsgPackMap(c fiber.Ctx) (map[string]interface{}, error) {
+	var raw interface{}
+	if err := c.App().Config().MsgPackDecoder(c.Body(), &raw); err != nil {
+		return nil, err
+	}
+
+	body, ok := normalizeMsgPackValue(raw).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("MessagePack body must be a map")
+	}
+	return body, nil
+}
+
+// normalizeMsgPackValue converts decoded MessagePack values to the types produced
+// by encoding/json: numbers become float64 and maps get string keys.
+func normalizeMsgPackValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = normalizeMsgPackValue(elem)
+		}
+		return v
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			result[fmt.Sprint(key)] = normalizeMsgPackValue(elem)
+		}
+		return result
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = normalizeMsgPackValue(elem)
+		}
+		return v
+	case int:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint8:
+		return float64(v)
+	case uint16:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+// requestMediaTypes returns the documented request media types for an endpoint
+func requestMediaTypes(endpoint *Endpoint) []string {
+	if len(endpoint.Consumes) == 0 {
+		return []string{fiber.MIMEApplicationJSON}
+	}
+	return endpoint.Consumes
+}
+
+// supportsMsgPack reports whether an endpoint accepts or produces MessagePack
+func supportsMsgPack(endpoint *Endpoint) bool {
+	for _, mediaType := range append(requestMediaTypes(endpoint), responseMediaTypes(endpoint)...) {
+		if isMsgPackContentType(mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// msgpackScript is the browser-side MessagePack codec used by the try-it client
+// to send MessagePack bodies and display MessagePack responses as JSON.
+const msgpackScript = `
+            // Minimal MessagePack encoder for try-it request bodies
+            function msgpackEncode(value) {
+                const bytes = [];
+                const encoder = new TextEncoder();
+                function writeUint(n, size) {
+                    for (let i = size - 1; i >= 0; i--) {
+                        bytes.push(Math.floor(n / Math.pow(2, 8 * i)) & 0xff);
+                    }
+                }
+                function writeLength(len, fix, fixMax, type16, type32) {
+                    if (len < fixMax) {
+                        bytes.push(fix | len);
+                    } else if (len < 0x10000) {
+                        bytes.push(type16);
+                        writeUint(len, 2);
+                    } else {
+                        bytes.push(type32);
+                        writeUint(len, 4);
+                    }
+                }
+                function write(v) {
+                    if (v === null || v === undefined) {
+                        bytes.push(0xc0);
+                    } else if (typeof v === 'boolean') {
+                        bytes.push(v ? 0xc3 : 0xc2);
+                    } else if (typeof v === 'number') {
+                        if (Number.isInteger(v) && v >= 0 && v < 0x100000000) {
+                            if (v < 0x80) { bytes.push(v); }
+                            else if (v < 0x100) { bytes.push(0xcc, v); }
+                            else if (v < 0x10000) { bytes.push(0xcd); writeUint(v, 2); }
+                            else { bytes.push(0xce); writeUint(v, 4); }
+                        } else if (Number.isInteger(v) && v < 0 && v >= -0x80000000) {
+                            if (v >= -32) { bytes.push(v & 0xff); }
+                            else if (v >= -128) { bytes.push(0xd0, v & 0xff); }
+                            else if (v >= -32768) { bytes.push(0xd1); writeUint(v & 0xffff, 2); }
+                            else { bytes.push(0xd2); writeUint(v >>> 0, 4); }
+                        } else {
+                            const view = new DataView(new ArrayBuffer(8));
+                            view.setFloat64(0, v);
+                            bytes.push(0xcb);
+                            for (let i = 0; i < 8; i++) { bytes.push(view.getUint8(i)); }
+                        }
+                    } else if (typeof v === 'string') {
+                        const data = encoder.encode(v);
+                        if (data.length < 32) { bytes.push(0xa0 | data.length); }
+                        else if (data.length < 0x100) { bytes.push(0xd9, data.length); }
+                        else if (data.length < 0x10000) { bytes.push(0xda); writeUint(data.length, 2); }
+                        else { bytes.push(0xdb); writeUint(data.length, 4); }
+                        data.forEach(b => bytes.push(b));
+                    } else if (Array.isArray(v)) {
+                        writeLength(v.length, 0x90, 16, 0xdc, 0xdd);
+                        v.forEach(write);
+                    } else {
+                        const keys = Object.keys(v);
+                        writeLength(keys.length, 0x80, 16, 0xde, 0xdf);
+                        keys.forEach(k => { write(k); write(v[k]); });
+                    }
+                }
+                write(value);
+                return new Uint8Array(bytes);
+            }
+
+            // Minimal MessagePack decoder for displaying try-it responses as JSON
+            function msgpackDecode(buffer) {
+                const view = new DataView(buffer);
+                const decoder = new TextDecoder();
+                let offset = 0;
+                function take(size) { const start = offset; offset += size; return start; }
+                function str(len) { return decoder.decode(new Uint8Array(buffer, take(len), len)); }
+                function bin(len) { return Array.from(new Uint8Array(buffer, take(len), len)); }
+                function arr(len) { const a = []; for (let i = 0; i < len; i++) { a.push(read()); } return a; }
+                function map(len) { const m = {}; for (let i = 0; i < len; i++) { const k = read(); m[k] = read(); } return m; }
+                function ext(len) {
+                    const type = view.getInt8(take(1));
+                    if (type === -1 && len === 4) { return new Date(view.getUint32(take(4)) * 1000).toISOString(); }
+                    if (type === -1 && len === 8) {
+                        const hi = view.getUint32(take(4)); const lo = view.getUint32(take(4));
+                        return new Date(((hi & 0x3) * 0x100000000 + lo) * 1000 + Math.floor((hi >>> 2) / 1e6)).toISOString();
+                    }
+                    if (type === -1 && len === 12) {
+                        const nanos = view.getUint32(take(4));
+                        return new Date(Number(view.getBigInt64(take(8))) * 1000 + Math.floor(nanos / 1e6)).toISOString();
+                    }
+                    return { extType: type, data: bin(len) };
+                }
+                function read() {
+                    const type = view.getUint8(take(1));
+                    if (type < 0x80) { return type; }
+                    if (type < 0x90) { return map(type & 0x0f); }
+                    if (type < 0xa0) { return arr(type & 0x0f); }
+                    if (type < 0xc0) { return str(type & 0x1f); }
+                    if (type >= 0xe0) { return type - 0x100; }
+                    switch (type) {
+                        case 0xc0: return null;
+                        case 0xc2: return false;
+                        case 0xc3: return true;
+                        case 0xc4: return bin(view.getUint8(take(1)));
+                        case 0xc5: return bin(view.getUint16(take(2)));
+                        case 0xc6: return bin(view.getUint32(take(4)));
+                        case 0xc7: return ext(view.getUint8(take(1)));
+                        case 0xc8: return ext(view.getUint16(take(2)));
+                        case 0xc9: return ext(view.getUint32(take(4)));
+                        case 0xca: return view.getFloat32(take(4));
+                        case 0xcb: return view.getFloat64(take(8));
+                        case 0xcc: return view.getUint8(take(1));
+                        case 0xcd: return view.getUint16(take(2));
+                        case 0xce: return view.getUint32(take(4));
+                        case 0xcf: return Number(view.getBigUint64(take(8)));
+                        case 0xd0: return view.getInt8(take(1));
+                        case 0xd1: return view.getInt16(take(2));
+                        case 0xd2: return view.getInt32(take(4));
+                        case 0xd3: return Number(view.getBigInt64(take(8)));
+                        case 0xd4: return ext(1);
+                        case 0xd5: return ext(2);
+                        case 0xd6: return ext(4);
+                        case 0xd7: return ext(8);
+                        case 0xd8: return ext(16);
+                        case 0xd9: return str(view.getUint8(take(1)));
+                        case 0xda: return str(view.getUint16(take(2)));
+                        case 0xdb: return str(view.getUint32(take(4)));
+                        case 0xdc: return arr(view.getUint16(take(2)));
+                        case 0xdd: return arr(view.getUint32(take(4)));
+                        case 0xde: return map(view.getUint16(take(2)));
+                        case 0xdf: return map(view.getUint32(take(4)));
+                        default: throw new Error('Unsupported MessagePack type 0x' + type.toString(16));
+                    }
+                }
+                return read();
+            }
+`
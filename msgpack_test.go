@@ -0,0 +1,96 @@
+package notelink
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/shamaton/msgpack/v2"
+)
+
+// TestMsgPackRequestBody tests validation of MessagePack request bodies
+func TestMsgPackRequestBody(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/users",
+		Description:    "Create a user",
+		Responses:      map[string]string{"200": "Success"},
+		Consumes:       []string{fiber.MIMEApplicationJSON, MIMEApplicationMsgPack},
+		SchemasRequest: TestUser{},
+		Handler: func(c fiber.Ctx) error {
+			var user TestUser
+			if err := DecodeBody(c, &user); err != nil {
+				return err
+			}
+			return c.JSON(user)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		body           interface{}
+		raw            []byte
+		expectedStatus int
+	}{
+		{
+			name:           "Valid body",
+			body:           map[string]interface{}{"name": "John", "email": "john@example.com", "age": 25},
+			expectedStatus: 200,
+		},
+		{
+			name:           "Wrong type",
+			body:           map[string]interface{}{"name": "John", "email": "john@example.com", "age": "25"},
+			expectedStatus: 400,
+		},
+		{
+			name:           "Missing field",
+			body:           map[string]interface{}{"name": "John", "age": 25},
+			expectedStatus: 400,
+		},
+		{
+			name:           "Not a map",
+			body:           []interface{}{"John"},
+			expectedStatus: 400,
+		},
+		{
+			name:           "Malformed",
+			raw:            []byte{0xc1},
+			expectedStatus: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := tt.raw
+			if payload == nil {
+				payload, err = msgpack.Marshal(tt.body)
+				if err != nil {
+					t.Fatalf("Failed to encode body: %v", err)
+				}
+			}
+
+			req := httptest.NewRequest("POST", "/users", bytes.NewReader(payload))
+			req.Header.Set("Content-Type", MIMEApplicationMsgPack)
+
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+
+	content := api.GenerateOpenAPISpec().Paths["/users"].Post.RequestBody.Content
+	if _, ok := content[MIMEApplicationMsgPack]; !ok {
+		t.Errorf("Expected %s request body media type, got %v", MIMEApplicationMsgPack, content)
+	}
+}
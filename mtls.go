@@ -0,0 +1,58 @@
+package notelink
+
+import (
+	"crypto/tls"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// ListenMutualTLS starts the Fiber server with TLS and client certificate verification
+// on the port specified in Config.Host.
+//
+// Client certificates are verified against clientCAFile whenever they are presented.
+// Routes registered with ClientCertRequired reject requests without a verified
+// certificate, while the documentation pages remain reachable without one.
+//
+// Returns an error if the certificates cannot be loaded or the server fails to start.
+func (an *ApiNote) ListenMutualTLS(certFile, keyFile, clientCAFile string) error {
+	listenConfig := an.listenConfig()
+	listenConfig.CertFile = certFile
+	listenConfig.CertKeyFile = keyFile
+	listenConfig.CertClientFile = clientCAFile
+
+	userTLSConfigFunc := listenConfig.TLSConfigFunc
+	listenConfig.TLSConfigFunc = func(tlsConfig *tls.Config) {
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if userTLSConfigFunc != nil {
+			userTLSConfigFunc(tlsConfig)
+		}
+	}
+
+	return an.app.Listen(an.listenAddr(), listenConfig)
+}
+
+// ClientCertMiddleware returns a Fiber middleware that requires a verified client certificate.
+// It must be used with ListenMutualTLS, which verifies presented certificates against the client CA.
+//
+// On success the certificate subject is stored in the context under "client_cert_subject".
+// Requests without a verified certificate are rejected with a 401 *fiber.Error,
+// rendered by the app's ErrorHandler.
+func ClientCertMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		state := c.RequestCtx().TLSConnectionState()
+		if state == nil || len(state.VerifiedChains) == 0 || len(state.PeerCertificates) == 0 {
+			return fiber.NewError(fiber.StatusUnauthorized, "Client certificate required")
+		}
+
+		c.Locals("client_cert_subject", state.PeerCertificates[0].Subject.String())
+		return c.Next()
+	}
+}
+
+// mutualTLSSecurityScheme returns the OpenAPI security scheme for client certificate authentication
+func mutualTLSSecurityScheme() SecurityScheme {
+	return SecurityScheme{
+		Type:        "mutualTLS",
+		Description: "TLS client certificate issued by a trusted certificate authority",
+	}
+}
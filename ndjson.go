@@ -0,0 +1,189 @@
+package notelink
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// MIMEApplicationNDJSON is the media type of newline-delimited JSON bodies
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// MaxNDJSONLineSize bounds the lines of NDJSON request bodies (1 MiB)
+const MaxNDJSONLineSize = 1 << 20
+
+// ndjsonDescription documents NDJSON operations in the spec
+const ndjsonDescription = "Bodies in " + MIMEApplicationNDJSON + " are newline-delimited JSON: each line is one JSON value matching the documented schema. " +
+	"Streamed responses send lines as they are produced."
+
+// isNDJSONContentType reports whether a media type is newline-delimited JSON
+func isNDJSONContentType(contentType string) bool {
+	return strings.Contains(strings.ToLower(contentType), "ndjson")
+}
+
+// hasNDJSON reports whether an endpoint accepts or produces NDJSON bodies
+func hasNDJSON(endpoint *Endpoint) bool {
+	return hasMediaType(requestMediaTypes(endpoint), isNDJSONContentType) || hasMediaType(responseMediaTypes(endpoint), isNDJSONContentType)
+}
+
+// consumesNDJSON reports whether an endpoint's first request media type is
+// NDJSON, which the try-it form then sends
+func consumesNDJSON(endpoint *Endpoint) bool {
+	return len(endpoint.Consumes) > 0 && isNDJSONContentType(endpoint.Consumes[0])
+}
+
+// ndjsonLineSchema returns the schema of one NDJSON line: the element schema
+// of array schemas, so routes can declare []T for JSON and NDJSON alike
+func ndjsonLineSchema(schema *JSONSchema) *JSONSchema {
+	if schema != nil && schema.Type == "array" && schema.Items != nil {
+		return schema.Items
+	}
+	return schema
+}
+
+// ndjsonLineType returns the type each NDJSON line is validated against
+func ndjsonLineType(schema interface{}) reflect.Type {
+	typ := reflect.TypeOf(schema)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+	}
+	return typ
+}
+
+// ValidateNDJSON reads a newline-delimited JSON request body line by line,
+// validating each line against schema (a struct, or a slice of structs whose
+// elements describe one line) and passing it to fn as it is read. Blank lines
+// are skipped. Reading stops at the first invalid line, returning a
+// *ValidationErrorResponse whose fields are prefixed with the line number, or
+// at the first error returned by fn. Documented routes validate buffered NDJSON
+// bodies before the handler runs; with Fiber's StreamRequestBody enabled they
+// leave it to ValidateNDJSON, which then streams the body rather than buffer it.
+//
+// Example usage:
+//
+//	Handler: func(c fiber.Ctx) error {
+//	    return notelink.ValidateNDJSON(c, Reading{}, func(line map[string]interface{}) error {
+//	        return store.Insert(line)
+//	    })
+//	}
+func ValidateNDJSON(c fiber.Ctx, schema interface{}, fn func(line map[string]interface{}) error) error {
+	var body io.Reader = c.Request().BodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+	return validateNDJSON(body, schema, "", fn)
+}
+
+// validateNDJSON validates the lines of an NDJSON body, checking time.Time
+// fields without a timeformat tag against timeFormat
+func validateNDJSON(body io.Reader, schema interface{}, timeFormat string, fn func(line map[string]interface{}) error) error {
+	lineType := ndjsonLineType(schema)
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxNDJSONLineSize)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var line map[string]interface{}
+		if err := json.Unmarshal(text, &line); err != nil {
+			return &ValidationErrorResponse{
+				ErrorMessage: "Invalid NDJSON body",
+				Errors: []ValidationError{{
+					Field:   fmt.Sprintf("line %d", number),
+					Message: err.Error(),
+					Type:    "parse_error",
+					Code:    validation.CodeParse,
+				}},
+			}
+		}
+		if lineType.Kind() == reflect.Struct {
+			if errs := validation.Struct(line, lineType, timeFormat); len(errs) > 0 {
+				for i := range errs {
+					errs[i].Field = fmt.Sprintf("line %d: %s", number, errs[i].Field)
+				}
+				return &ValidationErrorResponse{
+					ErrorMessage: "Request body validation failed",
+					Errors:       errs,
+				}
+			}
+		}
+		if fn != nil {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return &ValidationErrorResponse{
+			ErrorMessage: "Invalid NDJSON body",
+			Errors: []ValidationError{{
+				Field:   fmt.Sprintf("line %d", number+1),
+				Message: err.Error(),
+				Type:    "parse_error",
+				Code:    validation.CodeParse,
+			}},
+		}
+	}
+	return nil
+}
+
+// NDJSONWriter writes the lines of a streamed NDJSON response
+type NDJSONWriter struct {
+	w *bufio.Writer
+}
+
+// Write sends one value as a line, flushing it to the client
+func (w *NDJSONWriter) Write(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+// StreamNDJSON streams a newline-delimited JSON response, sending each value
+// written by fn as soon as it is written. The status and headers are sent
+// before fn runs, so errors returned by fn end the stream and are only logged.
+//
+// Example usage:
+//
+//	Handler: func(c fiber.Ctx) error {
+//	    return notelink.StreamNDJSON(c, func(w *notelink.NDJSONWriter) error {
+//	        for event := range events {
+//	            if err := w.Write(event); err != nil {
+//	                return err
+//	            }
+//	        }
+//	        return nil
+//	    })
+//	}
+func StreamNDJSON(c fiber.Ctx, fn func(w *NDJSONWriter) error) error {
+	method, path := c.Method(), c.Route().Path
+	c.Set(fiber.HeaderContentType, MIMEApplicationNDJSON)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		if err := fn(&NDJSONWriter{w: w}); err != nil {
+			log.Warnf("notelink: %s %s: NDJSON stream ended: %v", method, path, err)
+		}
+	})
+}
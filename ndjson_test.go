@@ -0,0 +1,110 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type ndjsonReading struct {
+	Device string  `json:"device"`
+	Value  float64 `json:"value"`
+}
+
+// TestNDJSON tests NDJSON request validation, streamed responses and their documentation
+func TestNDJSON(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	routes := []DocumentedRouteInput{
+		{Method: "POST", Path: "/v1/readings", Consumes: []string{MIMEApplicationNDJSON}, SchemasRequest: ndjsonReading{},
+			Handler: func(c fiber.Ctx) error {
+				count := 0
+				err := ValidateNDJSON(c, ndjsonReading{}, func(line map[string]interface{}) error {
+					count++
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				return c.SendString(strconv.Itoa(count))
+			}},
+		{Method: "GET", Path: "/v1/readings", Produces: []string{fiber.MIMEApplicationJSON, MIMEApplicationNDJSON},
+			Responses: map[string]string{"200": "The readings"}, SchemasResponse: []ndjsonReading{},
+			Handler: func(c fiber.Ctx) error {
+				return StreamNDJSON(c, func(w *NDJSONWriter) error {
+					for i := 1; i <= 3; i++ {
+						if err := w.Write(ndjsonReading{Device: "d" + strconv.Itoa(i), Value: float64(i)}); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			}},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{"valid lines", "{\"device\":\"a\",\"value\":1}\n\n{\"device\":\"b\",\"value\":2}\n", 200, "2"},
+		{"invalid line", "{\"device\":\"a\",\"value\":1}\n{\"value\":2}\n", 400, `"field":"line 2: device"`},
+		{"malformed line", "{\"device\":\"a\",\"value\":1}\nnot json\n", 400, `"field":"line 2"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/readings", strings.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, MIMEApplicationNDJSON)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || !strings.Contains(string(body), tt.want) {
+				t.Errorf("Expected %d containing %s, got %d %s", tt.status, tt.want, resp.StatusCode, body)
+			}
+		})
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/v1/readings", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	want := "{\"device\":\"d1\",\"value\":1}\n{\"device\":\"d2\",\"value\":2}\n{\"device\":\"d3\",\"value\":3}\n"
+	if resp.Header.Get(fiber.HeaderContentType) != MIMEApplicationNDJSON || string(body) != want {
+		t.Errorf("Expected streamed NDJSON lines, got %s %q", resp.Header.Get(fiber.HeaderContentType), body)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	request := spec.Paths["/v1/readings"].Post.RequestBody.Content[MIMEApplicationNDJSON].Schema
+	if request == nil || request.Ref != "#/components/schemas/ndjsonReading" {
+		t.Errorf("Expected the request line schema, got %+v", request)
+	}
+	get := spec.Paths["/v1/readings"].Get
+	if line := get.Responses["200"].Content[MIMEApplicationNDJSON].Schema; line == nil || line.Ref != "#/components/schemas/ndjsonReading" {
+		t.Errorf("Expected the NDJSON response schema to describe one line, got %+v", line)
+	}
+	if array := get.Responses["200"].Content[fiber.MIMEApplicationJSON].Schema; array == nil || array.Type != "array" {
+		t.Errorf("Expected the JSON response schema to stay an array, got %+v", array)
+	}
+	if !strings.Contains(get.Description, "newline-delimited JSON") {
+		t.Errorf("Expected NDJSON to be described, got %q", get.Description)
+	}
+
+	html := api.generateHTML()
+	if !strings.Contains(html, `data-ndjson="true"`) || !strings.Contains(html, "function streamNDJSON") {
+		t.Error("Expected the try-it form to send NDJSON and stream NDJSON responses")
+	}
+}
@@ -0,0 +1,55 @@
+package notelink
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// MIMEApplicationMsgPack is the media type used for MessagePack bodies
+const MIMEApplicationMsgPack = "application/msgpack"
+
+// NegotiatedContentTypes lists the response media types supported by Respond.
+// Use it as DocumentedRouteInput.Produces for handlers that respond with Respond.
+var NegotiatedContentTypes = []string{
+	fiber.MIMEApplicationJSON,
+	fiber.MIMEApplicationXML,
+	MIMEApplicationMsgPack,
+}
+
+// Respond serializes data as JSON, XML or MessagePack depending on the request's
+// Accept header, defaulting to JSON when the client expresses no preference.
+//
+// If none of the supported media types is acceptable, it returns a
+// 406 Not Acceptable error handled by the app's ErrorHandler.
+//
+// Example usage:
+//
+//	api.DocumentedRoute(&notelink.DocumentedRouteInput{
+//	    Method:   "GET",
+//	    Path:     "/v1/users/:id",
+//	    Produces: notelink.NegotiatedContentTypes,
+//	    Handler: func(c fiber.Ctx) error {
+//	        return notelink.Respond(c, user)
+//	    },
+//	})
+func Respond(c fiber.Ctx, data interface{}) error {
+	switch c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMEApplicationXML, MIMEApplicationMsgPack, fiber.MIMEApplicationMsgPack) {
+	case fiber.MIMEApplicationJSON:
+		return c.JSON(data)
+	case fiber.MIMEApplicationXML:
+		return c.XML(data)
+	case MIMEApplicationMsgPack, fiber.MIMEApplicationMsgPack:
+		return c.MsgPack(data, MIMEApplicationMsgPack)
+	default:
+		return fiber.NewError(fiber.StatusNotAcceptable, "Supported content types: "+strings.Join(NegotiatedContentTypes, ", "))
+	}
+}
+
+// responseMediaTypes returns the documented response media types for an endpoint
+func responseMediaTypes(endpoint *Endpoint) []string {
+	if len(endpoint.Produces) == 0 {
+		return []string{fiber.MIMEApplicationJSON}
+	}
+	return endpoint.Produces
+}
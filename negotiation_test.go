@@ -0,0 +1,83 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/shamaton/msgpack/v2"
+)
+
+// TestRespondNegotiation tests Accept-based serialization in Respond
+func TestRespondNegotiation(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "GET",
+		Path:            "/users/:id",
+		Description:     "Get a user",
+		Responses:       map[string]string{"200": "Success"},
+		Produces:        NegotiatedContentTypes,
+		SchemasResponse: TestUser{},
+		Handler: func(c fiber.Ctx) error {
+			return Respond(c, TestUser{Name: "John", Email: "john@example.com", Age: 25})
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+		expectedStatus      int
+	}{
+		{name: "No preference", accept: "", expectedStatus: 200, expectedContentType: "application/json"},
+		{name: "JSON", accept: "application/json", expectedStatus: 200, expectedContentType: "application/json"},
+		{name: "XML", accept: "application/xml", expectedStatus: 200, expectedContentType: "application/xml"},
+		{name: "MessagePack", accept: "application/msgpack", expectedStatus: 200, expectedContentType: "application/msgpack"},
+		{name: "Unsupported", accept: "text/csv", expectedStatus: 406},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/users/1", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedContentType != "" && !strings.HasPrefix(resp.Header.Get("Content-Type"), tt.expectedContentType) {
+				t.Errorf("Expected content type %s, got %s", tt.expectedContentType, resp.Header.Get("Content-Type"))
+			}
+
+			if tt.accept == "application/msgpack" {
+				body, _ := io.ReadAll(resp.Body)
+				var decoded map[string]interface{}
+				if err := msgpack.Unmarshal(body, &decoded); err != nil {
+					t.Fatalf("Failed to decode MessagePack body: %v", err)
+				}
+				if decoded["Name"] != "John" && decoded["name"] != "John" {
+					t.Errorf("Unexpected MessagePack body: %v", decoded)
+				}
+			}
+		})
+	}
+
+	content := api.GenerateOpenAPISpec().Paths["/users/{id}"].Get.Responses["200"].Content
+	for _, mediaType := range NegotiatedContentTypes {
+		if _, ok := content[mediaType]; !ok {
+			t.Errorf("Expected %s to be documented on the response", mediaType)
+		}
+	}
+}
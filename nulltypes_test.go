@@ -0,0 +1,126 @@
+package notelink
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// NullString mirrors the guregu/null wrappers, which embed the sql type
+type NullString struct {
+	sql.NullString
+}
+
+type Account struct {
+	Nickname  sql.NullString       `json:"nickname"`
+	Balance   sql.NullInt64        `json:"balance"`
+	ClosedAt  sql.NullTime         `json:"closed_at" timeformat:"unix"`
+	Referrer  NullString           `json:"referrer"`
+	Scores    []sql.NullFloat64    `json:"scores,omitempty"`
+	Generic   sql.Null[int]        `json:"generic"`
+	Lookalike struct{ Valid bool } `json:"lookalike,omitempty"`
+}
+
+// TestNullableTypes tests sql.Null* fields across schemas, TypeScript and examples
+func TestNullableTypes(t *testing.T) {
+	schema, components := generateJSONSchema("Account", Account{}, "", nil)
+	for name := range components {
+		if strings.HasPrefix(name, "Null") {
+			t.Errorf("Expected no component schema for %s", name)
+		}
+	}
+	if len(schema.Required) != 0 {
+		t.Errorf("Expected nullable fields not to be required, got %v", schema.Required)
+	}
+
+	template, err := generateJSONTemplate(Account{}, exampleOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var example map[string]interface{}
+	if err := json.Unmarshal([]byte(template), &example); err != nil {
+		t.Fatalf("Template is not valid JSON: %v", err)
+	}
+	ts := generateTypeScriptSchema("Account", Account{}, "", nil)
+
+	tests := []struct {
+		field      string
+		schemaType string
+		tsType     string
+	}{
+		{field: "nickname", schemaType: "string", tsType: "string | null"},
+		{field: "balance", schemaType: "integer", tsType: "number | null"},
+		{field: "closed_at", schemaType: "integer", tsType: "number | null"},
+		{field: "referrer", schemaType: "string", tsType: "string | null"},
+		{field: "scores", schemaType: "array", tsType: "(number | null)[]"},
+		{field: "generic", schemaType: "integer", tsType: "number | null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			property := schema.Properties[tt.field]
+			if property.Type != tt.schemaType {
+				t.Errorf("Expected %s schema, got %s", tt.schemaType, property.Type)
+			}
+			if tt.schemaType != "array" && !property.Nullable {
+				t.Error("Expected the schema to be nullable")
+			}
+			if _, isObject := example[tt.field].(map[string]interface{}); isObject {
+				t.Errorf("Expected a primitive example, got %v", example[tt.field])
+			}
+			// Nullable fields aren't required, so they are optional too
+			if !strings.Contains(ts, tt.field+"?: "+tt.tsType+";") {
+				t.Errorf("Expected %s?: %s in TypeScript, got:\n%s", tt.field, tt.tsType, ts)
+			}
+		})
+	}
+
+	if schema.Properties["lookalike"].Type != "object" {
+		t.Error("Expected structs outside database/sql to stay objects")
+	}
+}
+
+// TestNullableValidation tests request validation of sql.Null* fields
+func TestNullableValidation(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/accounts",
+		Handler:        func(c fiber.Ctx) error { return c.SendStatus(201) },
+		SchemasRequest: Account{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "Values", body: `{"nickname":"jd","balance":10,"closed_at":1718445600,"referrer":"ann","generic":1}`, expectedStatus: 201},
+		{name: "Nulls", body: `{"nickname":null,"balance":null,"closed_at":null,"referrer":null,"generic":null}`, expectedStatus: 201},
+		{name: "Omitted", body: `{}`, expectedStatus: 201},
+		{name: "Wrong type", body: `{"balance":"ten"}`, expectedStatus: 400},
+		{name: "Wrapped wrong type", body: `{"referrer":42}`, expectedStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/accounts", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -6,18 +6,22 @@ import (
 	"os"
 	"reflect"
 	"strings"
-	"time"
 	"unicode"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
 )
 
 // OpenAPI 3.1 root structure
 type OpenAPISpec struct {
-	OpenAPI    string                `json:"openapi"`
-	Info       OpenAPIInfo           `json:"info"`
-	Servers    []OpenAPIServer       `json:"servers,omitempty"`
-	Paths      map[string]PathItem   `json:"paths"`
-	Components *Components           `json:"components,omitempty"`
-	Security   []map[string][]string `json:"security,omitempty"`
+	OpenAPI    string                 `json:"openapi"`
+	Info       OpenAPIInfo            `json:"info"`
+	Servers    []OpenAPIServer        `json:"servers,omitempty"`
+	Paths      map[string]PathItem    `json:"paths"`
+	Components *Components            `json:"components,omitempty"`
+	Security   []map[string][]string  `json:"security,omitempty"`
+	Tags       []OpenAPITag           `json:"tags,omitempty"`
+	Extensions map[string]interface{} `json:"-"` // x-* properties of the document root
 }
 
 type OpenAPIInfo struct {
@@ -27,8 +31,9 @@ type OpenAPIInfo struct {
 }
 
 type OpenAPIServer struct {
-	URL         string `json:"url"`
-	Description string `json:"description,omitempty"`
+	URL         string                    `json:"url"`
+	Description string                    `json:"description,omitempty"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"`
 }
 
 type PathItem struct {
@@ -42,15 +47,48 @@ type PathItem struct {
 	Trace   *Operation `json:"trace,omitempty"`
 }
 
+// operation returns the path item's operation for an HTTP method, or nil
+func (p *PathItem) operation(method string) *Operation {
+	switch strings.ToUpper(method) {
+	case "GET":
+		return p.Get
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "DELETE":
+		return p.Delete
+	case "PATCH":
+		return p.Patch
+	case "HEAD":
+		return p.Head
+	case "OPTIONS":
+		return p.Options
+	case "TRACE":
+		return p.Trace
+	}
+	return nil
+}
+
 type Operation struct {
-	OperationID string                `json:"operationId"`
-	Summary     string                `json:"summary,omitempty"`
-	Description string                `json:"description,omitempty"`
-	Parameters  []ParameterSpec       `json:"parameters,omitempty"`
-	RequestBody *RequestBody          `json:"requestBody,omitempty"`
-	Responses   map[string]Response   `json:"responses"`
-	Security    []map[string][]string `json:"security,omitempty"`
-	Tags        []string              `json:"tags,omitempty"`
+	OperationID string                 `json:"operationId"`
+	Summary     string                 `json:"summary,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  []ParameterSpec        `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]Response    `json:"responses"`
+	Security    []map[string][]string  `json:"security,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+	Route       string                 `json:"x-notelink-route,omitempty"` // Fiber route pattern when it has optional or wildcard segments
+	Owner       *Ownership             `json:"x-owner,omitempty"`
+	ChangeLog   []ChangeLogEntry       `json:"x-changelog,omitempty"`
+	Status      EndpointStatus         `json:"x-status,omitempty"`
+	Variants    []RouteVariant         `json:"x-variants,omitempty"`
+	Migration   *MigrationObject       `json:"x-migration,omitempty"`                 // Replacement of a deprecated operation
+	Encodings   []string               `json:"x-request-content-encodings,omitempty"` // Accepted request Content-Encodings
+	Rules       []string               `json:"x-validation-rules,omitempty"`          // Descriptions of the ValidationRules
+	Extensions  map[string]interface{} `json:"-"`                                     // x-* properties of the operation
 }
 
 type ParameterSpec struct {
@@ -58,7 +96,12 @@ type ParameterSpec struct {
 	Name        string      `json:"name"`
 	In          string      `json:"in"` // "query", "path", "header", "cookie"
 	Description string      `json:"description,omitempty"`
+	Segment     string      `json:"x-notelink-segment,omitempty"` // "optional", "wildcard" or "greedy" path segments
+	Style       string      `json:"style,omitempty"`
+	Explode     *bool       `json:"explode,omitempty"`
 	Required    bool        `json:"required,omitempty"`
+	// AllowReserved marks query values sent with reserved characters unencoded
+	AllowReserved bool `json:"allowReserved,omitempty"`
 }
 
 type RequestBody struct {
@@ -68,13 +111,22 @@ type RequestBody struct {
 }
 
 type Response struct {
-	Content     map[string]MediaType `json:"content,omitempty"`
-	Description string               `json:"description"`
+	Content     map[string]MediaType    `json:"content,omitempty"`
+	Links       map[string]LinkObject   `json:"links,omitempty"`
+	Headers     map[string]HeaderObject `json:"headers,omitempty"`
+	Description string                  `json:"description"`
+	ErrorCodes  []string                `json:"x-error-codes,omitempty"` // Registered error codes returned with the status
+}
+
+type HeaderObject struct {
+	Description string      `json:"description,omitempty"`
+	Schema      *JSONSchema `json:"schema"`
 }
 
 type MediaType struct {
-	Schema  *JSONSchema `json:"schema,omitempty"`
-	Example interface{} `json:"example,omitempty"`
+	Schema   *JSONSchema              `json:"schema,omitempty"`
+	Example  interface{}              `json:"example,omitempty"`
+	Examples map[string]ExampleObject `json:"examples,omitempty"`
 }
 
 type Components struct {
@@ -83,12 +135,27 @@ type Components struct {
 }
 
 type SecurityScheme struct {
-	Type         string `json:"type"` // "http", "apiKey", "oauth2", "openIdConnect"
-	Scheme       string `json:"scheme,omitempty"`
-	BearerFormat string `json:"bearerFormat,omitempty"`
-	Description  string `json:"description,omitempty"`
-	Name         string `json:"name,omitempty"`
-	In           string `json:"in,omitempty"`
+	Type         string      `json:"type"` // "http", "apiKey", "oauth2", "openIdConnect"
+	Scheme       string      `json:"scheme,omitempty"`
+	BearerFormat string      `json:"bearerFormat,omitempty"`
+	Description  string      `json:"description,omitempty"`
+	Name         string      `json:"name,omitempty"`
+	In           string      `json:"in,omitempty"`
+	Flows        *OAuthFlows `json:"flows,omitempty"` // Of "oauth2" schemes
+}
+
+// OAuthFlows lists the flows of an "oauth2" security scheme. The device flow
+// is an extension, as OpenAPI 3.1 defines none.
+type OAuthFlows struct {
+	DeviceAuthorization *OAuthFlow `json:"x-deviceAuthorization,omitempty"`
+}
+
+// OAuthFlow describes an OAuth 2.0 flow of a security scheme
+type OAuthFlow struct {
+	DeviceAuthorizationURL string            `json:"deviceAuthorizationUrl,omitempty"`
+	TokenURL               string            `json:"tokenUrl"`
+	RefreshURL             string            `json:"refreshUrl,omitempty"`
+	Scopes                 map[string]string `json:"scopes"`
 }
 
 // JSONSchema represents JSON Schema (compatible with OpenAPI 3.1)
@@ -103,11 +170,21 @@ type JSONSchema struct {
 	Description          string                 `json:"description,omitempty"`
 	Ref                  string                 `json:"$ref,omitempty"`
 	Required             []string               `json:"required,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
 	Nullable             bool                   `json:"nullable,omitempty"`
+	Deprecated           bool                   `json:"deprecated,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+	Extensions           map[string]interface{} `json:"-"` // x-* properties from the field's extensions tag
 }
 
 // GenerateOpenAPISpec creates an OpenAPI 3.1 specification from registered endpoints
 func (an *ApiNote) GenerateOpenAPISpec() *OpenAPISpec {
+	return an.generateOpenAPISpec(an.baseURL(nil), an.docsLocale())
+}
+
+// generateOpenAPISpec creates the specification with baseURL (scheme and host)
+// as its server
+func (an *ApiNote) generateOpenAPISpec(baseURL, locale string) *OpenAPISpec {
 	spec := &OpenAPISpec{
 		OpenAPI: "3.1.0",
 		Info: OpenAPIInfo{
@@ -115,13 +192,9 @@ func (an *ApiNote) GenerateOpenAPISpec() *OpenAPISpec {
 			Description: an.config.Description,
 			Version:     an.config.Version,
 		},
-		Servers: []OpenAPIServer{
-			{
-				URL:         "http://" + an.config.Host + an.config.BasePath,
-				Description: "API Server",
-			},
-		},
-		Paths: make(map[string]PathItem),
+		Servers:    an.servers(baseURL),
+		Paths:      make(map[string]PathItem),
+		Extensions: copyExtensions(an.config.Extensions),
 		Components: &Components{
 			Schemas:         make(map[string]*JSONSchema),
 			SecuritySchemes: make(map[string]SecurityScheme),
@@ -147,14 +220,92 @@ func (an *ApiNote) GenerateOpenAPISpec() *OpenAPISpec {
 		}
 	}
 
-	// Process each endpoint
+	// Add the device authorization scheme, an alternative to bearerAuth
+	if hasAuth && an.config.DeviceAuth != nil && an.config.DeviceAuth.Verify != nil {
+		spec.Components.SecuritySchemes["deviceAuth"] = an.deviceAuthSecurityScheme()
+	}
+
+	// Add the API key scheme if any endpoint requires an API key
+	for _, endpoint := range an.endpoints {
+		if endpoint.APIKeyRequired {
+			spec.Components.SecuritySchemes["apiKeyAuth"] = an.apiKeySecurityScheme()
+			break
+		}
+	}
+
+	// Add the session cookie scheme if any endpoint requires a session
+	for _, endpoint := range an.endpoints {
+		if endpoint.SessionRequired {
+			spec.Components.SecuritySchemes["cookieAuth"] = an.sessionSecurityScheme()
+			break
+		}
+	}
+
+	// Add a request signing scheme per signing config of the endpoints
+	for _, scheme := range an.requestSigningSchemes() {
+		spec.Components.SecuritySchemes[scheme.name] = scheme.scheme
+	}
+
+	// Add the mutual TLS scheme if any endpoint requires client certificates
+	for _, endpoint := range an.endpoints {
+		if endpoint.ClientCertRequired {
+			spec.Components.SecuritySchemes["mutualTLS"] = mutualTLSSecurityScheme()
+			break
+		}
+	}
+
+	// Export the registered error codes
+	if len(an.errorCodes) > 0 {
+		if spec.Extensions == nil {
+			spec.Extensions = make(map[string]interface{})
+		}
+		spec.Extensions["x-error-codes"] = an.errorCodesExtension(spec.Components.Schemas)
+	}
+	// Export the codes of the field errors of 400 responses
+	if an.validatesRequests() {
+		if spec.Extensions == nil {
+			spec.Extensions = make(map[string]interface{})
+		}
+		spec.Extensions["x-validation-error-codes"] = validation.Codes()
+	}
+
+	// Publish the public keys encrypting request bodies
 	for _, endpoint := range an.endpoints {
-		pathItem, ok := spec.Paths[endpoint.Path]
+		if !endpoint.EncryptedRequest {
+			continue
+		}
+		if keys := an.config.RequestEncryption.publicKeys(); len(keys) > 0 {
+			if spec.Extensions == nil {
+				spec.Extensions = make(map[string]interface{})
+			}
+			spec.Extensions["x-jwe-keys"] = keys
+		}
+		break
+	}
+
+	// Process each endpoint
+	for _, endpoint := range an.docsEndpoints() {
+		if an.hiddenByFlag(&endpoint) || an.hiddenAsDraft(&endpoint) {
+			continue
+		}
+		endpoint = localizeEndpoint(endpoint, locale)
+		specPath := openAPIPath(endpoint.routePath())
+		pathItem, ok := spec.Paths[specPath]
 		if !ok {
 			pathItem = PathItem{}
 		}
 
-		operation := an.endpointToOperation(&endpoint, spec.Components.Schemas)
+		var operation *Operation
+		if endpoint.ProxiedOperation != nil {
+			upstream := *endpoint.ProxiedOperation
+			operation = &upstream
+		} else {
+			operation = an.endpointToOperation(&endpoint, spec.Components.Schemas)
+		}
+		if response, ok := operation.Responses["405"]; ok {
+			response.Headers = an.allowHeader(endpoint.Path)
+			operation.Responses["405"] = response
+		}
 
 		// Assign operation to the correct HTTP method
 		switch strings.ToUpper(endpoint.Method) {
@@ -176,16 +327,47 @@ func (an *ApiNote) GenerateOpenAPISpec() *OpenAPISpec {
 			pathItem.Trace = operation
 		}
 
-		spec.Paths[endpoint.Path] = pathItem
+		spec.Paths[specPath] = pathItem
+	}
+	an.addProxiedSchemas(spec.Components.Schemas)
+
+	// Document the CORS preflight answered for each path
+	if an.config.CORS != nil {
+		for _, endpoint := range an.sortedEndpoints() {
+			specPath := openAPIPath(endpoint.routePath())
+			if pathItem, ok := spec.Paths[specPath]; ok && pathItem.Options == nil {
+				pathItem.Options = an.preflightOperation(&endpoint)
+				spec.Paths[specPath] = pathItem
+			}
+		}
 	}
 
+	spec.Tags = an.specTags()
+	an.hooks.runSpecGenerated(spec)
+
 	return spec
 }
 
+// exampleRequestBody documents the request body of a route without a request
+// schema by its fixture examples, under its JSON media types
+func exampleRequestBody(endpoint *Endpoint) *RequestBody {
+	body := &RequestBody{Required: true, Content: make(map[string]MediaType)}
+	for _, contentType := range requestMediaTypes(endpoint) {
+		if strings.Contains(contentType, "json") && !isNDJSONContentType(contentType) {
+			body.Content[contentType] = MediaType{Examples: examplesToOpenAPI(endpoint.RequestExamples)}
+		}
+	}
+	// Examples are JSON-encoded, so routes consuming other types get them as JSON
+	if len(body.Content) == 0 {
+		body.Content[fiber.MIMEApplicationJSON] = MediaType{Examples: examplesToOpenAPI(endpoint.RequestExamples)}
+	}
+	return body
+}
+
 // endpointToOperation converts an Endpoint to an OpenAPI Operation
 func (an *ApiNote) endpointToOperation(endpoint *Endpoint, componentSchemas map[string]*JSONSchema) *Operation {
 	// Generate operation ID from method and path
-	operationID := generateOperationID(endpoint.Method, endpoint.Path)
+	operationID := generateOperationID(endpoint.Method, endpoint.routePath())
 
 	operation := &Operation{
 		OperationID: operationID,
@@ -195,59 +377,169 @@ func (an *ApiNote) endpointToOperation(endpoint *Endpoint, componentSchemas map[
 		Responses:   make(map[string]Response),
 	}
 
-	// Extract tags from path (e.g., "/api/v1/users" -> ["users"])
-	tags := extractTagsFromPath(endpoint.Path)
+	if endpoint.Compressed {
+		operation.Description = appendDescription(operation.Description, compressionDescription)
+	}
+	if hasNDJSON(endpoint) {
+		operation.Description = appendDescription(operation.Description, ndjsonDescription)
+	}
+	if hasCSV(endpoint) {
+		operation.Description = appendDescription(operation.Description, csvDescription)
+	}
+	if endpoint.RequestDecompression != nil {
+		operation.Description = appendDescription(operation.Description, endpoint.RequestDecompression.describe())
+		operation.Encodings = requestEncodings
+	}
+	if endpoint.EncryptedRequest {
+		operation.Description = appendDescription(operation.Description, an.config.RequestEncryption.describe())
+	}
+	if endpoint.IPFilter != nil {
+		operation.Description = appendDescription(operation.Description, endpoint.IPFilter.describe())
+	}
+	if endpoint.ConcurrencyLimit != nil {
+		operation.Description = appendDescription(operation.Description, endpoint.ConcurrencyLimit.describe())
+	}
+	if endpoint.SLO != nil {
+		operation.Description = appendDescription(operation.Description, endpoint.SLO.slo.describe())
+	}
+
+	// Use declared tags or extract them from path (e.g., "/api/v1/users" -> ["users"])
+	tags := endpointTags(endpoint)
 	if len(tags) > 0 {
 		operation.Tags = tags
 	}
 
 	// Add security requirement if endpoint requires authentication
+	requirement := map[string][]string{}
 	if endpoint.AuthRequired {
-		operation.Security = []map[string][]string{
-			{"bearerAuth": []string{}},
+		requirement["bearerAuth"] = []string{}
+	}
+	if endpoint.APIKeyRequired {
+		requirement["apiKeyAuth"] = append([]string{}, endpoint.APIKeyScopes...)
+	}
+	if endpoint.SessionRequired {
+		requirement["cookieAuth"] = []string{}
+	}
+	if endpoint.RequestSigning != nil {
+		requirement[an.requestSigningSchemeName(endpoint.RequestSigning)] = []string{}
+	}
+	if endpoint.ClientCertRequired {
+		requirement["mutualTLS"] = []string{}
+	}
+	if len(requirement) > 0 {
+		operation.Security = []map[string][]string{requirement}
+	}
+	// Device sign-ins issue the same JWTs, so they satisfy bearerAuth too
+	if endpoint.AuthRequired && an.config.DeviceAuth != nil && an.config.DeviceAuth.Verify != nil {
+		alternative := map[string][]string{"deviceAuth": {}}
+		for name, scopes := range requirement {
+			if name != "bearerAuth" {
+				alternative[name] = scopes
+			}
 		}
+		operation.Security = append(operation.Security, alternative)
+	}
+
+	if hasDynamicSegments(endpoint.routePath()) {
+		operation.Route = endpoint.routePath()
+	}
+	if !endpoint.Ownership.isZero() {
+		operation.Owner = endpoint.Ownership
 	}
+	operation.ChangeLog = endpoint.ChangeLog
+	operation.Status = endpoint.Status
+	operation.Deprecated = endpoint.Deprecated
+	if endpoint.Migration != nil {
+		operation.Migration = an.migrationObject(endpoint.Migration)
+	}
+	operation.Variants = endpoint.Variants
+	operation.Rules = ruleDescriptions(endpoint.ValidationRules)
+	operation.Extensions = endpoint.Extensions
 
 	// Convert parameters
 	for _, param := range endpoint.Parameters {
-		paramSchema := parameterTypeToJSONSchema(param.Type)
 		paramSpec := ParameterSpec{
-			Name:        param.Name,
-			In:          param.In,
-			Description: param.Description,
-			Required:    param.Required,
-			Schema:      paramSchema,
+			Name:          param.Name,
+			In:            param.In,
+			Description:   param.Description,
+			Required:      param.Required,
+			Schema:        parameterSchema(&param),
+			Style:         param.Style,
+			Explode:       param.Explode,
+			AllowReserved: param.AllowReserved,
+		}
+		if param.In == "path" {
+			// OpenAPI requires path parameters; optional segments are flagged instead
+			paramSpec.Name = specParamName(param.Name)
+			paramSpec.Required = true
+			paramSpec.Segment = pathParamKind(endpoint.routePath(), param.Name)
 		}
 		operation.Parameters = append(operation.Parameters, paramSpec)
 	}
 
+	// Declare path placeholders that have no matching parameter
+	for _, param := range pathParams(endpoint.routePath()) {
+		if !hasPathParameter(endpoint.Parameters, param.Name) {
+			operation.Parameters = append(operation.Parameters, ParameterSpec{
+				Name:     param.specName(),
+				In:       "path",
+				Required: true,
+				Segment:  param.Kind,
+				Schema:   &JSONSchema{Type: "string"},
+			})
+		}
+	}
+
 	// Add request body if RequestSchema exists
 	if endpoint.RequestSchema != nil {
-		schema, nestedSchemas := generateJSONSchema("RequestBody", endpoint.RequestSchema)
-
-		// Add nested schemas to components
-		for name, nestedSchema := range nestedSchemas {
-			if _, exists := componentSchemas[name]; !exists {
-				componentSchemas[name] = nestedSchema
-			}
-		}
+		schema := an.bodySchema("RequestBody", endpoint.RequestSchema, componentSchemas)
 
 		// Generate example from schema
-		exampleJSON, err := generateJSONTemplate(endpoint.RequestSchema)
+		exampleJSON, err := generateJSONTemplate(endpoint.RequestSchema, an.exampleOptions())
 		if err == nil {
 			var exampleData interface{}
 			if err := json.Unmarshal([]byte(exampleJSON), &exampleData); err == nil {
+				mediaType := MediaType{
+					Schema:  schema,
+					Example: exampleData,
+				}
+				// Fixture examples replace the generated example (OpenAPI forbids both)
+				if len(endpoint.RequestExamples) > 0 {
+					mediaType.Example = nil
+					mediaType.Examples = examplesToOpenAPI(endpoint.RequestExamples)
+				}
 				operation.RequestBody = &RequestBody{
 					Required: true,
-					Content: map[string]MediaType{
-						"application/json": {
-							Schema:  schema,
-							Example: exampleData,
-						},
-					},
+					Content:  make(map[string]MediaType),
+				}
+				for _, contentType := range requestMediaTypes(endpoint) {
+					if isNDJSONContentType(contentType) {
+						// The schema of NDJSON bodies describes one line
+						operation.RequestBody.Content[contentType] = MediaType{Schema: ndjsonLineSchema(schema)}
+					} else if isCSVContentType(contentType) {
+						operation.RequestBody.Content[contentType] = MediaType{Schema: schema, Example: csvExample(endpoint.RequestSchema, an.exampleOptions())}
+					} else if strings.Contains(contentType, "json") {
+						operation.RequestBody.Content[contentType] = mediaType
+					} else {
+						// Examples are JSON-encoded, so non-JSON media types only carry the schema
+						operation.RequestBody.Content[contentType] = MediaType{Schema: schema}
+					}
 				}
 			}
 		}
+		// Encrypted bodies replace the plaintext media types, described in x-jwe
+		if endpoint.EncryptedRequest && operation.RequestBody != nil {
+			operation.Extensions = copyExtensions(operation.Extensions)
+			if operation.Extensions == nil {
+				operation.Extensions = make(map[string]interface{})
+			}
+			operation.Extensions["x-jwe"] = an.config.RequestEncryption.jweExtension(schema)
+			operation.RequestBody.Content = map[string]MediaType{
+				MIMEApplicationJOSE: {Schema: &JSONSchema{Type: "string", Description: "Compact JWE of the JSON request body"}},
+			}
+		}
+	} else if len(endpoint.RequestExamples) > 0 {
+		operation.RequestBody = exampleRequestBody(endpoint)
 	}
 
 	// Add responses
@@ -259,31 +551,57 @@ func (an *ApiNote) endpointToOperation(endpoint *Endpoint, componentSchemas map[
 		// Add response schema for successful responses
 		if statusCode == "200" || statusCode == "201" {
 			if endpoint.ResponseSchema != nil {
-				schema, nestedSchemas := generateJSONSchema("ResponseBody", endpoint.ResponseSchema)
-
-				// Add nested schemas to components
-				for name, nestedSchema := range nestedSchemas {
-					if _, exists := componentSchemas[name]; !exists {
-						componentSchemas[name] = nestedSchema
-					}
-				}
+				schema := an.bodySchema("ResponseBody", endpoint.ResponseSchema, componentSchemas)
 
 				// Generate example from schema
-				exampleJSON, err := generateJSONTemplate(endpoint.ResponseSchema)
+				exampleJSON, err := generateJSONTemplate(endpoint.ResponseSchema, an.exampleOptions())
 				if err == nil {
 					var exampleData interface{}
 					if err := json.Unmarshal([]byte(exampleJSON), &exampleData); err == nil {
-						response.Content = map[string]MediaType{
-							"application/json": {
-								Schema:  schema,
-								Example: exampleData,
-							},
+						response.Content = make(map[string]MediaType)
+						for _, mediaType := range responseMediaTypes(endpoint) {
+							// The generated example is JSON, so only attach it to JSON media types
+							content := MediaType{Schema: schema}
+							if isNDJSONContentType(mediaType) {
+								content.Schema = ndjsonLineSchema(schema)
+							} else if isCSVContentType(mediaType) {
+								content.Example = csvExample(endpoint.ResponseSchema, an.exampleOptions())
+							} else if strings.Contains(mediaType, "json") {
+								content.Example = exampleData
+							}
+							response.Content[mediaType] = content
 						}
 					}
 				}
 			}
 		}
 
+		// Document error bodies with the registered error codes or the configured error schema
+		if codes := an.statusErrorCodes(endpoint, statusCode); len(codes) > 0 {
+			an.errorCodesResponse(&response, statusCode, codes, componentSchemas)
+		} else if isErrorStatus(statusCode) {
+			schema := an.bodySchema("ErrorBody", an.errorSchema(), componentSchemas)
+			if example, err := an.errorExample(statusCode); err == nil {
+				response.Content = map[string]MediaType{
+					fiber.MIMEApplicationJSON: {Schema: schema, Example: example},
+				}
+			}
+		}
+
+		// Recorded examples replace the generated example (OpenAPI forbids both)
+		if examples := responseExamplesToOpenAPI(endpoint.ResponseExamples, statusCode); examples != nil {
+			if response.Content == nil {
+				response.Content = map[string]MediaType{fiber.MIMEApplicationJSON: {}}
+			}
+			for mediaType, content := range response.Content {
+				if strings.Contains(mediaType, "json") && !isNDJSONContentType(mediaType) {
+					content.Example = nil
+					content.Examples = examples
+					response.Content[mediaType] = content
+				}
+			}
+		}
+
 		operation.Responses[statusCode] = response
 	}
 
@@ -294,11 +612,61 @@ func (an *ApiNote) endpointToOperation(endpoint *Endpoint, componentSchemas map[
 		}
 	}
 
+	an.addResponseLinks(endpoint, operation)
+	if len(endpoint.SparseFields) > 0 {
+		documentSparseFields(endpoint, operation, componentSchemas)
+	}
+
 	return operation
 }
 
+// bodySchema generates the schema of a request, response or error body and adds
+// the structs it uses to componentSchemas. Named top-level structs are components
+// too and are referenced with $ref, so operations sharing a body type share one
+// definition; anonymous structs and other types are inlined.
+func (an *ApiNote) bodySchema(name string, body interface{}, componentSchemas map[string]*JSONSchema) *JSONSchema {
+	names := an.schemaNames()
+	schema, nestedSchemas := generateJSONSchema(name, body, an.config.TimeFormat, names)
+
+	// Add nested schemas to components
+	for componentName, nestedSchema := range nestedSchemas {
+		if _, exists := componentSchemas[componentName]; !exists {
+			componentSchemas[componentName] = nestedSchema
+		}
+	}
+
+	typ := reflect.TypeOf(body)
+	if typ == nil {
+		return schema
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	isArray := false
+	if typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+		isArray = true
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+	}
+	if typ.Kind() != reflect.Struct || typ.Name() == "" || typ == timeType {
+		return schema
+	}
+	componentName := names.name(typ)
+	if _, exists := componentSchemas[componentName]; !exists {
+		return schema
+	}
+
+	ref := &JSONSchema{Ref: "#/components/schemas/" + componentName}
+	if isArray {
+		return &JSONSchema{Type: "array", Items: ref}
+	}
+	return ref
+}
+
 // generateJSONSchema converts a Go type to JSON Schema format
-func generateJSONSchema(name string, schema interface{}) (mainSchema *JSONSchema, componentSchemas map[string]*JSONSchema) {
+func generateJSONSchema(name string, schema interface{}, timeFormat string, names *schemaNames) (mainSchema *JSONSchema, componentSchemas map[string]*JSONSchema) {
 	if schema == nil {
 		return &JSONSchema{Type: "object"}, nil
 	}
@@ -328,10 +696,10 @@ func generateJSONSchema(name string, schema interface{}) (mainSchema *JSONSchema
 
 	// Generate schemas for all nested structs
 	componentSchemas = make(map[string]*JSONSchema)
-	collectComponentSchemas(typ, componentSchemas)
+	collectComponentSchemas(typ, componentSchemas, timeFormat, names)
 
 	// Generate the main schema
-	mainSchema = structToJSONSchema(typ, name, componentSchemas)
+	mainSchema = structToJSONSchema(typ, name, componentSchemas, timeFormat, names)
 
 	if isArray {
 		return &JSONSchema{
@@ -344,7 +712,7 @@ func generateJSONSchema(name string, schema interface{}) (mainSchema *JSONSchema
 }
 
 // collectComponentSchemas recursively collects all nested struct schemas
-func collectComponentSchemas(typ reflect.Type, schemas map[string]*JSONSchema) {
+func collectComponentSchemas(typ reflect.Type, schemas map[string]*JSONSchema, timeFormat string, names *schemaNames) {
 	if typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
 	}
@@ -355,20 +723,25 @@ func collectComponentSchemas(typ reflect.Type, schemas map[string]*JSONSchema) {
 		}
 	}
 
-	if typ.Kind() != reflect.Struct || typ.Name() == "" {
+	if typ.Kind() != reflect.Struct {
 		return
 	}
 
 	// Skip if already processed
-	if _, exists := schemas[typ.Name()]; exists {
+	if _, exists := schemas[names.name(typ)]; exists {
 		return
 	}
 
 	// Special case for time.Time
-	if typ == reflect.TypeOf(time.Time{}) {
+	if typ == timeType {
 		return
 	}
 
+	// Reserve the name so self-referential types don't recurse forever
+	if typ.Name() != "" {
+		schemas[names.name(typ)] = &JSONSchema{}
+	}
+
 	// Process all fields to find nested structs
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
@@ -384,17 +757,28 @@ func collectComponentSchemas(typ reflect.Type, schemas map[string]*JSONSchema) {
 			}
 		}
 
-		if fieldType.Kind() == reflect.Struct && fieldType.Name() != "" && fieldType != reflect.TypeOf(time.Time{}) {
-			collectComponentSchemas(fieldType, schemas)
+		if valueType, ok := validation.NullableValueType(fieldType); ok {
+			fieldType = valueType
+		}
+		if _, ok := lookupStdType(fieldType); ok {
+			continue
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType {
+			collectComponentSchemas(fieldType, schemas, timeFormat, names)
 		}
 	}
 
+	// Anonymous structs are inlined, only their named fields become components
+	if typ.Name() == "" {
+		return
+	}
+
 	// Add this struct to schemas
-	schemas[typ.Name()] = structToJSONSchema(typ, typ.Name(), schemas)
+	schemas[names.name(typ)] = structToJSONSchema(typ, names.name(typ), schemas, timeFormat, names)
 }
 
 // structToJSONSchema converts a struct type to JSON Schema
-func structToJSONSchema(typ reflect.Type, name string, componentSchemas map[string]*JSONSchema) *JSONSchema {
+func structToJSONSchema(typ reflect.Type, name string, componentSchemas map[string]*JSONSchema, timeFormat string, names *schemaNames) *JSONSchema {
 	schema := &JSONSchema{
 		Type:       "object",
 		Title:      name,
@@ -409,20 +793,31 @@ func structToJSONSchema(typ reflect.Type, name string, componentSchemas map[stri
 			continue
 		}
 
-		fieldName := getJSONFieldName(&field)
+		fieldName := validation.JSONFieldName(&field)
 		if fieldName == "-" {
 			continue
 		}
 
-		fieldSchema := fieldToJSONSchema(field.Type, field.Name, componentSchemas)
+		fieldSchema := fieldToJSONSchema(field.Type, field.Name, componentSchemas, validation.FieldTimeFormat(&field, timeFormat), names)
+		if values := validation.EnumValues(&field); len(values) > 0 && fieldSchema.Ref == "" {
+			// Tag values replace those of a SchemaEnum type
+			fieldSchema.Enum = make([]interface{}, len(values))
+			for i, value := range values {
+				fieldSchema.Enum[i] = value
+			}
+		}
+		if extensions := fieldExtensions(&field); extensions != nil {
+			fieldSchema.Extensions = extensions
+		}
+		fieldSchema.Deprecated = isDeprecatedField(&field)
 		schema.Properties[fieldName] = fieldSchema
 
-		// Check if field is required (not a pointer and no omitempty tag)
+		// Check if field is required (not nullable and no omitempty tag)
 		jsonTag := field.Tag.Get("json")
 		isOmitEmpty := strings.Contains(jsonTag, "omitempty")
-		isPointer := field.Type.Kind() == reflect.Ptr
+		isNullable := validation.IsNullable(field.Type)
 
-		if !isOmitEmpty && !isPointer {
+		if !isOmitEmpty && !isNullable {
 			schema.Required = append(schema.Required, fieldName)
 		}
 	}
@@ -436,41 +831,58 @@ func structToJSONSchema(typ reflect.Type, name string, componentSchemas map[stri
 }
 
 // fieldToJSONSchema converts a field type to JSON Schema
-func fieldToJSONSchema(t reflect.Type, fieldName string, componentSchemas map[string]*JSONSchema) *JSONSchema {
+func fieldToJSONSchema(t reflect.Type, fieldName string, componentSchemas map[string]*JSONSchema, timeFormat string, names *schemaNames) *JSONSchema {
 	// Handle pointers
 	if t.Kind() == reflect.Ptr {
-		schema := fieldToJSONSchema(t.Elem(), fieldName, componentSchemas)
+		schema := fieldToJSONSchema(t.Elem(), fieldName, componentSchemas, timeFormat, names)
 		schema.Nullable = true
 		return schema
 	}
 
+	// sql.Null* types document as their nullable value type
+	if valueType, ok := validation.NullableValueType(t); ok {
+		schema := fieldToJSONSchema(valueType, fieldName, componentSchemas, timeFormat, names)
+		schema.Nullable = true
+		return schema
+	}
+
+	// Standard library types with a string representation (net.IP is a byte slice)
+	if std, ok := lookupStdType(t); ok {
+		return std.jsonSchema()
+	}
+
+	if values := validation.TypeEnumValues(t); values != nil {
+		schema := &JSONSchema{Type: "string", Description: typeDescription(t)}
+		for _, value := range values {
+			schema.Enum = append(schema.Enum, value)
+		}
+		return schema
+	}
+
 	// Handle slices
 	if t.Kind() == reflect.Slice {
 		return &JSONSchema{
 			Type:  "array",
-			Items: fieldToJSONSchema(t.Elem(), fieldName, componentSchemas),
+			Items: fieldToJSONSchema(t.Elem(), fieldName, componentSchemas, timeFormat, names),
 		}
 	}
 
 	// Handle structs
 	if t.Kind() == reflect.Struct {
 		// Special case for time.Time
-		if t == reflect.TypeOf(time.Time{}) {
-			return &JSONSchema{
-				Type:   "string",
-				Format: "date-time",
-			}
+		if t == timeType {
+			return timeJSONSchema(timeFormat)
 		}
 
 		// Reference to component schema if it has a name
 		if t.Name() != "" {
 			return &JSONSchema{
-				Ref: "#/components/schemas/" + t.Name(),
+				Ref: "#/components/schemas/" + names.name(t),
 			}
 		}
 
 		// Anonymous struct - inline it
-		return structToJSONSchema(t, "", componentSchemas)
+		return structToJSONSchema(t, "", componentSchemas, timeFormat, names)
 	}
 
 	return goTypeToJSONSchema(t)
@@ -478,6 +890,10 @@ func fieldToJSONSchema(t reflect.Type, fieldName string, componentSchemas map[st
 
 // goTypeToJSONSchema maps Go primitive types to JSON Schema types
 func goTypeToJSONSchema(t reflect.Type) *JSONSchema {
+	if std, ok := lookupStdType(t); ok {
+		return std.jsonSchema()
+	}
+
 	switch t.Kind() {
 	case reflect.String:
 		return &JSONSchema{Type: "string"}
@@ -498,6 +914,9 @@ func goTypeToJSONSchema(t reflect.Type) *JSONSchema {
 	case reflect.Bool:
 		return &JSONSchema{Type: "boolean"}
 	default:
+		if unsupportedKind(t.Kind()) {
+			return unsupportedSchema(t)
+		}
 		return &JSONSchema{} // Empty schema for unknown types
 	}
 }
@@ -538,7 +957,9 @@ func generateOperationID(method, path string) string {
 		// Handle path parameters like :id or {id}
 		switch {
 		case strings.HasPrefix(segment, ":"):
-			segment = "By" + toTitle(segment[1:])
+			segment = "By" + toTitle(strings.TrimSuffix(segment[1:], "?"))
+		case strings.HasPrefix(segment, "*") || strings.HasPrefix(segment, "+"):
+			segment = "By" + toTitle(specParamName(segment))
 		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
 			segment = "By" + toTitle(segment[1:len(segment)-1])
 		default:
@@ -0,0 +1,268 @@
+package notelink
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestBodySchemaRefs tests that named body types are shared components referenced with $ref
+func TestBodySchemaRefs(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+	routes := []*DocumentedRouteInput{
+		{Method: "POST", Path: "/users", Handler: handler, Responses: map[string]string{"201": "Created"}, SchemasRequest: TestUser{}, SchemasResponse: &TestUser{}},
+		{Method: "PUT", Path: "/users/:id", Handler: handler, Responses: map[string]string{"200": "Updated"}, SchemasRequest: TestUser{}, SchemasResponse: TestUser{}},
+		{Method: "GET", Path: "/users", Handler: handler, Responses: map[string]string{"200": "Listed"}, SchemasResponse: []TestUser{}},
+		{Method: "PATCH", Path: "/users/:id", Handler: handler, SchemasRequest: struct {
+			Name string `json:"name"`
+		}{}},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	const ref = "#/components/schemas/TestUser"
+	tests := []struct {
+		name   string
+		schema *JSONSchema
+		array  bool
+		inline bool
+	}{
+		{name: "Request body", schema: spec.Paths["/users"].Post.RequestBody.Content["application/json"].Schema},
+		{name: "Pointer response", schema: spec.Paths["/users"].Post.Responses["201"].Content["application/json"].Schema},
+		{name: "Second operation", schema: spec.Paths["/users/{id}"].Put.RequestBody.Content["application/json"].Schema},
+		{name: "Array response", schema: spec.Paths["/users"].Get.Responses["200"].Content["application/json"].Schema, array: true},
+		{name: "Anonymous struct", schema: spec.Paths["/users/{id}"].Patch.RequestBody.Content["application/json"].Schema, inline: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			switch {
+			case tt.inline:
+				if tt.schema.Ref != "" || tt.schema.Properties["name"] == nil {
+					t.Errorf("Expected an inline schema, got %+v", tt.schema)
+				}
+			case tt.array:
+				if tt.schema.Type != "array" || tt.schema.Items == nil || tt.schema.Items.Ref != ref {
+					t.Errorf("Expected an array of %s, got %+v", ref, tt.schema)
+				}
+			case tt.schema.Ref != ref:
+				t.Errorf("Expected %s, got %+v", ref, tt.schema)
+			}
+		})
+	}
+
+	component, ok := spec.Components.Schemas["TestUser"]
+	if !ok || component.Properties["email"] == nil {
+		t.Fatalf("Expected a TestUser component, got %v", spec.Components.Schemas)
+	}
+	data, _ := json.Marshal(spec.Paths)
+	if strings.Contains(string(data), `"email":{"type":"string"}`) {
+		t.Error("Expected TestUser properties not to be inlined in operations")
+	}
+}
+
+// TestBasePath tests that the base path is expressed by the server URL, not the spec paths
+func TestBasePath(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/internal"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/orders/:id", Handler: handler}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if spec.Servers[0].URL != "http://localhost:8080/internal" {
+		t.Errorf("Expected the base path in the server URL, got %s", spec.Servers[0].URL)
+	}
+	item, ok := spec.Paths["/orders/{id}"]
+	if !ok || item.Get == nil {
+		t.Fatalf("Expected /orders/{id} in the spec, got %v", spec.Paths)
+	}
+	if item.Get.OperationID != "getOrdersById" {
+		t.Errorf("Expected operationId getOrdersById, got %s", item.Get.OperationID)
+	}
+	if len(item.Get.Tags) != 1 || item.Get.Tags[0] != "orders" {
+		t.Errorf("Expected tag orders, got %v", item.Get.Tags)
+	}
+
+	endpoint := api.endpoints["GET /orders/:id"]
+	if groups := GroupByFirstSegment(endpoint); len(groups) != 1 || groups[0] != "orders" {
+		t.Errorf("Expected docs group orders, got %v", groups)
+	}
+
+	html := api.generateHTML()
+	if !strings.Contains(html, `id="op-getOrdersById"`) || !strings.Contains(html, "'/internal/orders/:id'") {
+		t.Error("Expected the docs to anchor by operationId and call the full path")
+	}
+
+	req := httptest.NewRequest("GET", "/internal/orders/1", nil)
+	resp, err := api.Fiber().Test(req)
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Errorf("Expected the route to be served under the base path, got %d", resp.StatusCode)
+	}
+}
+
+// TestProxyHeaders tests the base URL of spec servers and try-it requests behind a proxy
+func TestProxyHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		trust    bool
+		proto    string
+		host     string
+		expected string
+	}{
+		{name: "No headers", trust: true, expected: "http://localhost:8080"},
+		{name: "Untrusted headers", proto: "https", host: "api.example.com", expected: "http://localhost:8080"},
+		{name: "Trusted headers", trust: true, proto: "https", host: "api.example.com", expected: "https://api.example.com"},
+		{name: "Proxy chain", trust: true, proto: "HTTPS, http", host: "api.example.com:8443, ingress.local", expected: "https://api.example.com:8443"},
+		{name: "Invalid values", trust: true, proto: "javascript", host: "evil.com/'", expected: "http://localhost:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/api", DocsUI: "notelink", TrustProxyHeaders: tt.trust}, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/users", Handler: func(c fiber.Ctx) error { return nil }})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			get := func(path string) string {
+				req := httptest.NewRequest("GET", path, nil)
+				if tt.proto != "" {
+					req.Header.Set("X-Forwarded-Proto", tt.proto)
+				}
+				if tt.host != "" {
+					req.Header.Set("X-Forwarded-Host", tt.host)
+				}
+				resp, err := api.Fiber().Test(req)
+				if err != nil {
+					t.Fatalf("Failed to send test request: %v", err)
+				}
+				defer resp.Body.Close()
+				body, _ := io.ReadAll(resp.Body)
+				return string(body)
+			}
+
+			var spec OpenAPISpec
+			if err := json.Unmarshal([]byte(get("/api-docs/openapi.json")), &spec); err != nil {
+				t.Fatalf("Failed to decode spec: %v", err)
+			}
+			if spec.Servers[0].URL != tt.expected+"/api" {
+				t.Errorf("Expected server %s/api, got %s", tt.expected, spec.Servers[0].URL)
+			}
+
+			html := get("/api-docs")
+			if !strings.Contains(html, "const baseUrl = '"+tt.expected+"';") {
+				t.Errorf("Expected try-it base URL %s", tt.expected)
+			}
+			if !strings.Contains(html, `data-copy="`+tt.expected+`/api/users"`) {
+				t.Errorf("Expected copy URL under %s", tt.expected)
+			}
+		})
+	}
+}
+
+type gatewayOrder struct {
+	ID     string `json:"id" extensions:"x-order=1;x-kong-tags=[\"public\"]"`
+	Secret string `json:"secret" extensions:"internal"`
+}
+
+// TestExtensions tests x-* properties from Config, routes and struct tags
+func TestExtensions(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title:      "Test API",
+		Host:       "localhost:8080",
+		Extensions: map[string]interface{}{"x-kong-plugins": []string{"cors"}, "logo": "https://acme.io/logo.png"},
+	}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "GET",
+		Path:            "/orders",
+		Handler:         func(c fiber.Ctx) error { return nil },
+		Responses:       map[string]string{"200": "OK"},
+		SchemasResponse: gatewayOrder{},
+		Ownership:       &Ownership{Team: "orders"},
+		Extensions: map[string]interface{}{
+			"x-amazon-apigateway-integration": map[string]string{"type": "http_proxy"},
+			"x-owner":                         "ignored",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	data, err := json.Marshal(api.GenerateOpenAPISpec())
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	var spec struct {
+		KongPlugins []string `json:"x-kong-plugins"`
+		Logo        string   `json:"x-logo"`
+		Paths       map[string]map[string]struct {
+			Integration map[string]string `json:"x-amazon-apigateway-integration"`
+			Owner       Ownership         `json:"x-owner"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	if len(spec.KongPlugins) != 1 || spec.Logo != "https://acme.io/logo.png" {
+		t.Errorf("Expected root extensions with x- prefixed keys, got %v and %q", spec.KongPlugins, spec.Logo)
+	}
+	operation := spec.Paths["/orders"]["get"]
+	if operation.Integration["type"] != "http_proxy" {
+		t.Errorf("Expected the operation extension, got %v", operation.Integration)
+	}
+	if operation.Owner.Team != "orders" {
+		t.Errorf("Expected x-owner from Ownership to take precedence, got %+v", operation.Owner)
+	}
+	properties := spec.Components.Schemas["gatewayOrder"].Properties
+	if properties["id"]["x-order"] != float64(1) || len(properties["id"]["x-kong-tags"].([]interface{})) != 1 {
+		t.Errorf("Expected tag extensions on id, got %v", properties["id"])
+	}
+	if properties["secret"]["x-internal"] != true {
+		t.Errorf("Expected a valueless tag extension to be true, got %v", properties["secret"])
+	}
+}
+
+// TestProxyHeadersCache tests that specs rendered for a forwarded host aren't
+// served for another
+func TestProxyHeadersCache(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", TrustProxyHeaders: true}, "secret")
+	for _, round := range []int{1, 2} {
+		for _, host := range []string{"api.example.com", "api.example.org"} {
+			req := httptest.NewRequest("GET", "/api-docs/openapi.json", nil)
+			req.Header.Set("X-Forwarded-Host", host)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			var spec OpenAPISpec
+			err = json.NewDecoder(resp.Body).Decode(&spec)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatalf("Failed to decode spec: %v", err)
+			}
+			if spec.Servers[0].URL != "http://"+host {
+				t.Errorf("Round %d: expected server http://%s, got %s", round, host, spec.Servers[0].URL)
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package notelink
+
+import (
+	"sort"
+)
+
+// OpenAPITag describes a tag in the spec's top-level tags list, whose order
+// documentation UIs use to order operation groups
+type OpenAPITag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// sortGroupNames orders docs groups and tags by Config.GroupWeights (lower first),
+// then alphabetically
+func (an *ApiNote) sortGroupNames(names []string) {
+	sort.SliceStable(names, func(i, j int) bool {
+		wi, wj := an.config.GroupWeights[names[i]], an.config.GroupWeights[names[j]]
+		if wi != wj {
+			return wi < wj
+		}
+		return names[i] < names[j]
+	})
+}
+
+// endpointLess orders endpoints with Config.SortEndpoints when set, otherwise
+// by Weight (lower first), then path and method
+func (an *ApiNote) endpointLess(a, b *Endpoint) bool {
+	if an.config.SortEndpoints != nil {
+		return an.config.SortEndpoints(*a, *b)
+	}
+	if a.Weight != b.Weight {
+		return a.Weight < b.Weight
+	}
+	if a.Path != b.Path {
+		return a.Path < b.Path
+	}
+	return a.Method < b.Method
+}
+
+// sortEndpoints orders endpoints in place using endpointLess
+func (an *ApiNote) sortEndpoints(endpoints []Endpoint) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return an.endpointLess(&endpoints[i], &endpoints[j])
+	})
+}
+
+// specTags lists every operation tag in docs order for the spec's tags array
+func (an *ApiNote) specTags() []OpenAPITag {
+	seen := make(map[string]bool)
+	var names []string
+	for _, endpoint := range an.endpoints {
+		for _, tag := range endpointTags(&endpoint) {
+			if !seen[tag] {
+				seen[tag] = true
+				names = append(names, tag)
+			}
+		}
+	}
+	an.sortGroupNames(names)
+
+	tags := make([]OpenAPITag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, OpenAPITag{Name: name})
+	}
+	return tags
+}
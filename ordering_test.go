@@ -0,0 +1,64 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestCustomSortOrder tests group weights and endpoint weights in the docs and spec
+func TestCustomSortOrder(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title:        "Test API",
+		Host:         "localhost:8080",
+		GroupBy:      GroupByTag,
+		GroupWeights: map[string]int{"Getting started": -10, "Admin": 10},
+	}, "secret")
+
+	handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/admin/users", Tags: []string{"Admin"}},
+		{Method: "GET", Path: "/billing/invoices", Tags: []string{"Billing"}},
+		{Method: "POST", Path: "/start/token", Tags: []string{"Getting started"}, Weight: 2},
+		{Method: "GET", Path: "/start/welcome", Tags: []string{"Getting started"}, Weight: 1},
+	}
+	for _, route := range routes {
+		route.Handler = handler
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	html := api.generateHTML()
+	order := []string{"<summary>Getting started<", "/start/welcome", "/start/token", "<summary>Billing<", "<summary>Admin<"}
+	last := -1
+	for _, marker := range order {
+		index := strings.Index(html, marker)
+		if index < 0 {
+			t.Fatalf("Expected %s in the docs", marker)
+		}
+		if index < last {
+			t.Errorf("Expected %s to appear after the previous entries", marker)
+		}
+		last = index
+	}
+
+	tags := api.GenerateOpenAPISpec().Tags
+	expected := []string{"Getting started", "Billing", "Admin"}
+	if len(tags) != len(expected) {
+		t.Fatalf("Expected %d tags, got %+v", len(expected), tags)
+	}
+	for i, name := range expected {
+		if tags[i].Name != name {
+			t.Errorf("Expected tag %d to be %s, got %s", i, name, tags[i].Name)
+		}
+	}
+
+	// A comparator overrides endpoint weights
+	api.config.SortEndpoints = func(a, b Endpoint) bool { return a.Method > b.Method }
+	html = api.generateHTML()
+	if strings.Index(html, "/start/token") > strings.Index(html, "/start/welcome") {
+		t.Error("Expected SortEndpoints to order POST before GET")
+	}
+}
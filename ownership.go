@@ -0,0 +1,57 @@
+package notelink
+
+import "strings"
+
+// Ownership names who maintains an endpoint and where consumers ask for help.
+// It is shown on the endpoint's docs card and exported as the x-owner
+// extension of the operation.
+type Ownership struct {
+	Owner          string `json:"owner,omitempty"`          // Person or role responsible, e.g. "jane@acme.io"
+	Team           string `json:"team,omitempty"`           // Owning team, e.g. "payments"
+	SupportChannel string `json:"supportChannel,omitempty"` // Where to report problems, e.g. "#payments-support" or a URL
+}
+
+// isZero reports whether no ownership field is set
+func (o *Ownership) isZero() bool {
+	return o == nil || (o.Owner == "" && o.Team == "" && o.SupportChannel == "")
+}
+
+// supportLink renders the support channel, linked when it is a URL or mailto address
+func (o *Ownership) supportLink() string {
+	channel := escapeHTML(o.SupportChannel)
+	for _, scheme := range []string{"https://", "http://", "mailto:"} {
+		if strings.HasPrefix(strings.ToLower(o.SupportChannel), scheme) {
+			return `<a href="` + channel + `" target="_blank" rel="noopener">` + channel + `</a>`
+		}
+	}
+	return channel
+}
+
+// renderOwnership renders the ownership section of an endpoint card
+func renderOwnership(o *Ownership) string {
+	if o.isZero() {
+		return ""
+	}
+
+	var html strings.Builder
+	html.WriteString(`
+                    <div class="ownership">
+                        <h4>Ownership:</h4>
+                        <ul>`)
+	if o.Team != "" {
+		html.WriteString(`
+                            <li><strong>Team:</strong> ` + escapeHTML(o.Team) + `</li>`)
+	}
+	if o.Owner != "" {
+		html.WriteString(`
+                            <li><strong>Owner:</strong> ` + escapeHTML(o.Owner) + `</li>`)
+	}
+	if o.SupportChannel != "" {
+		html.WriteString(`
+                            <li><strong>Support:</strong> ` + o.supportLink() + `</li>`)
+	}
+	html.WriteString(`
+                        </ul>
+                    </div>`)
+	return html.String()
+}
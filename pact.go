@@ -0,0 +1,434 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+)
+
+// PactOptions configures contract verification
+type PactOptions struct {
+	// StateHandlers set up the provider state an interaction expects, keyed by
+	// state name, e.g. "user 1 exists". Interactions with an unknown state fail.
+	StateHandlers map[string]func(params map[string]interface{}) error
+}
+
+// PactFailure is an interaction whose response doesn't satisfy the contract
+type PactFailure struct {
+	Interaction string
+	Mismatches  []string
+}
+
+// pactContract is a Pact file (specification v2 or v3)
+type pactContract struct {
+	Consumer     struct{ Name string } `json:"consumer"`
+	Interactions []pactInteraction     `json:"interactions"`
+}
+
+// pactInteraction is a request the consumer sends and the response it expects
+type pactInteraction struct {
+	Description    string       `json:"description"`
+	ProviderState  string       `json:"providerState,omitempty"`  // v2
+	ProviderStates []pactState  `json:"providerStates,omitempty"` // v3
+	Request        pactRequest  `json:"request"`
+	Response       pactResponse `json:"response"`
+}
+
+// pactState is a provider state an interaction depends on
+type pactState struct {
+	Name   string                 `json:"name"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// pactRequest is the request an interaction replays
+type pactRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   json.RawMessage   `json:"query,omitempty"` // "a=1&b=2" in v2, {"a": ["1"]} in v3
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// pactResponse is the response an interaction expects
+type pactResponse struct {
+	Status        int                        `json:"status"`
+	Headers       map[string]string          `json:"headers,omitempty"`
+	Body          json.RawMessage            `json:"body,omitempty"`
+	MatchingRules map[string]json.RawMessage `json:"matchingRules,omitempty"`
+}
+
+// pactRule is a matching rule: "type" compares JSON types, "regex" matches strings
+type pactRule struct {
+	Match string `json:"match"`
+	Regex string `json:"regex"`
+}
+
+// pactArrayIndex matches array indexes in matching rule paths
+var pactArrayIndex = regexp.MustCompile(`\[\d+\]`)
+
+// VerifyPact replays the interactions of a Pact contract against the registered
+// handlers. Responses must have the expected status and headers, a body
+// satisfying the expected body and matching rules, and successful bodies must
+// also validate against the endpoint's response schema.
+func (an *ApiNote) VerifyPact(data []byte, opts PactOptions) ([]PactFailure, error) {
+	var contract pactContract
+	if err := json.Unmarshal(data, &contract); err != nil {
+		return nil, fmt.Errorf("failed to parse Pact contract: %w", err)
+	}
+
+	var failures []PactFailure
+	for i := range contract.Interactions {
+		interaction := &contract.Interactions[i]
+		if mismatches := an.verifyInteraction(interaction, &opts); len(mismatches) > 0 {
+			failures = append(failures, PactFailure{Interaction: interaction.Description, Mismatches: mismatches})
+		}
+	}
+	return failures, nil
+}
+
+// VerifyPactFiles verifies Pact contract files from a test, with a subtest per interaction
+//
+//	func TestContracts(t *testing.T) {
+//	    api := newAPI()
+//	    api.VerifyPactFiles(t, notelink.PactOptions{}, "pacts/web-orders.json")
+//	}
+func (an *ApiNote) VerifyPactFiles(t *testing.T, opts PactOptions, paths ...string) {
+	t.Helper()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read Pact contract: %v", err)
+		}
+		var contract pactContract
+		if err := json.Unmarshal(data, &contract); err != nil {
+			t.Fatalf("failed to parse Pact contract %s: %v", path, err)
+		}
+
+		for i := range contract.Interactions {
+			interaction := &contract.Interactions[i]
+			t.Run(contract.Consumer.Name+"/"+interaction.Description, func(t *testing.T) {
+				for _, mismatch := range an.verifyInteraction(interaction, &opts) {
+					t.Error(mismatch)
+				}
+			})
+		}
+	}
+}
+
+// verifyInteraction replays an interaction and returns its mismatches
+func (an *ApiNote) verifyInteraction(interaction *pactInteraction, opts *PactOptions) []string {
+	states := interaction.ProviderStates
+	if interaction.ProviderState != "" {
+		states = append(states, pactState{Name: interaction.ProviderState})
+	}
+	for _, state := range states {
+		handler, ok := opts.StateHandlers[state.Name]
+		if !ok {
+			return []string{fmt.Sprintf("no state handler for provider state %q", state.Name)}
+		}
+		if err := handler(state.Params); err != nil {
+			return []string{fmt.Sprintf("setting up provider state %q: %v", state.Name, err)}
+		}
+	}
+
+	req, err := interaction.Request.httpRequest()
+	if err != nil {
+		return []string{err.Error()}
+	}
+	resp, err := an.app.Test(req)
+	if err != nil {
+		return []string{fmt.Sprintf("request failed: %v", err)}
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return []string{fmt.Sprintf("reading response: %v", err)}
+	}
+
+	var mismatches []string
+	expected := &interaction.Response
+	if expected.Status != 0 && resp.StatusCode != expected.Status {
+		mismatches = append(mismatches, fmt.Sprintf("expected status %d, got %d", expected.Status, resp.StatusCode))
+	}
+	for name, value := range expected.Headers {
+		if actual := resp.Header.Get(name); !matchPactHeader(value, actual) {
+			mismatches = append(mismatches, fmt.Sprintf("expected header %s: %q, got %q", name, value, actual))
+		}
+	}
+
+	var actualBody interface{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &actualBody); err != nil {
+			actualBody = string(body)
+		}
+	}
+	if len(expected.Body) > 0 && string(expected.Body) != "null" {
+		var expectedBody interface{}
+		if err := json.Unmarshal(expected.Body, &expectedBody); err != nil {
+			return append(mismatches, fmt.Sprintf("invalid expected body: %v", err))
+		}
+		mismatches = append(mismatches, matchPactBody("$", expectedBody, actualBody, expected.rules(), false)...)
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if endpoint := an.findEndpoint(interaction.Request.Method, interaction.Request.Path); endpoint != nil {
+			mismatches = append(mismatches, an.validateResponseSchema(endpoint, actualBody)...)
+		}
+	}
+	return mismatches
+}
+
+// httpRequest builds the request an interaction replays
+func (r *pactRequest) httpRequest() (*http.Request, error) {
+	target := r.Path
+	query, err := r.queryString()
+	if err != nil {
+		return nil, err
+	}
+	if query != "" {
+		target += "?" + query
+	}
+
+	var body io.Reader
+	if len(r.Body) > 0 && string(r.Body) != "null" {
+		body = bytes.NewReader(r.Body)
+	}
+	req := httptest.NewRequest(strings.ToUpper(r.Method), target, body)
+	for name, value := range r.Headers {
+		req.Header.Set(name, value)
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// matchPactHeader compares header values, ignoring parameters such as a
+// charset the expected value doesn't specify
+func matchPactHeader(expected, actual string) bool {
+	if actual == expected {
+		return true
+	}
+	return !strings.Contains(expected, ";") && strings.TrimSpace(strings.Split(actual, ";")[0]) == expected
+}
+
+// rules returns the body matching rules keyed by JSON path from "$", accepting
+// v2 ("$.body.id") and v3 ({"body": {"$.id": {"matchers": [...]}}}) layouts
+func (r *pactResponse) rules() map[string]pactRule {
+	rules := make(map[string]pactRule)
+	for path, raw := range r.MatchingRules {
+		if strings.HasPrefix(path, "$.body") {
+			var rule pactRule
+			if json.Unmarshal(raw, &rule) == nil {
+				rules["$"+strings.TrimPrefix(path, "$.body")] = rule
+			}
+			continue
+		}
+		if path != "body" {
+			continue
+		}
+		var body map[string]struct {
+			Matchers []pactRule `json:"matchers"`
+		}
+		if json.Unmarshal(raw, &body) != nil {
+			continue
+		}
+		for bodyPath, entry := range body {
+			if len(entry.Matchers) > 0 {
+				rules[bodyPath] = entry.Matchers[0]
+			}
+		}
+	}
+	return rules
+}
+
+// matchPactBody compares an actual value to the expected one. Objects may have
+// extra keys; arrays must have the same length unless a type rule applies, in
+// which case every element is matched against the first expected element. Type
+// rules cascade to nested values without a rule of their own.
+func matchPactBody(path string, expected, actual interface{}, rules map[string]pactRule, inherited bool) []string {
+	rule, hasRule := rules[path]
+	if !hasRule {
+		rule, hasRule = rules[pactArrayIndex.ReplaceAllString(path, "[*]")]
+	}
+	if hasRule && rule.Match == "regex" {
+		value, ok := actual.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected a string matching %q, got %s", path, rule.Regex, pactValue(actual))}
+		}
+		re, err := regexp.Compile(rule.Regex)
+		if err != nil {
+			return []string{fmt.Sprintf("%s: invalid regex %q: %v", path, rule.Regex, err)}
+		}
+		if !re.MatchString(value) {
+			return []string{fmt.Sprintf("%s: expected %q to match %q", path, value, rule.Regex)}
+		}
+		return nil
+	}
+	typeMatch := inherited
+	if hasRule {
+		typeMatch = rule.Match == "type"
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an object, got %s", path, pactValue(actual))}
+		}
+		var mismatches []string
+		for key, value := range expectedValue {
+			field, exists := actualValue[key]
+			if !exists {
+				mismatches = append(mismatches, fmt.Sprintf("%s.%s: missing", path, key))
+				continue
+			}
+			mismatches = append(mismatches, matchPactBody(path+"."+key, value, field, rules, typeMatch)...)
+		}
+		return mismatches
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected an array, got %s", path, pactValue(actual))}
+		}
+		if typeMatch {
+			if len(expectedValue) == 0 {
+				return nil
+			}
+			var mismatches []string
+			for i, element := range actualValue {
+				mismatches = append(mismatches, matchPactBody(fmt.Sprintf("%s[%d]", path, i), expectedValue[0], element, rules, typeMatch)...)
+			}
+			return mismatches
+		}
+		if len(actualValue) != len(expectedValue) {
+			return []string{fmt.Sprintf("%s: expected %d elements, got %d", path, len(expectedValue), len(actualValue))}
+		}
+		var mismatches []string
+		for i := range expectedValue {
+			mismatches = append(mismatches, matchPactBody(fmt.Sprintf("%s[%d]", path, i), expectedValue[i], actualValue[i], rules, typeMatch)...)
+		}
+		return mismatches
+	default:
+		if typeMatch {
+			if reflect.TypeOf(expected) != reflect.TypeOf(actual) {
+				return []string{fmt.Sprintf("%s: expected a value like %s, got %s", path, pactValue(expected), pactValue(actual))}
+			}
+			return nil
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			return []string{fmt.Sprintf("%s: expected %s, got %s", path, pactValue(expected), pactValue(actual))}
+		}
+		return nil
+	}
+}
+
+// pactValue formats a body value for mismatch messages
+func pactValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// findEndpoint returns the endpoint whose route pattern matches a request path
+func (an *ApiNote) findEndpoint(method, path string) *Endpoint {
+	key, ok := an.matchEndpoint(method, path)
+	if !ok {
+		return nil
+	}
+	endpoint := an.endpoints[key]
+	return &endpoint
+}
+
+// matchEndpoint returns the key of the endpoint whose route matches a request
+// path, preferring a literal match over parameterized routes
+func (an *ApiNote) matchEndpoint(method, path string) (string, bool) {
+	keys := make([]string, 0, len(an.endpoints))
+	for key, endpoint := range an.endpoints {
+		if !strings.EqualFold(endpoint.Method, method) {
+			continue
+		}
+		if endpoint.Path == path {
+			return key, true
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if regexp.MustCompile("^" + kongPath(an.endpoints[key].Path)[1:]).MatchString(path) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// validateResponseSchema validates a decoded response body against the
+// endpoint's response schema, element by element for array schemas
+func (an *ApiNote) validateResponseSchema(endpoint *Endpoint, body interface{}) []string {
+	if endpoint.ResponseSchema == nil {
+		return nil
+	}
+	schemaType := reflect.TypeOf(endpoint.ResponseSchema)
+	for schemaType.Kind() == reflect.Ptr {
+		schemaType = schemaType.Elem()
+	}
+
+	var objects []map[string]interface{}
+	switch value := body.(type) {
+	case map[string]interface{}:
+		objects = append(objects, value)
+	case []interface{}:
+		if schemaType.Kind() != reflect.Slice {
+			return []string{"response: expected an object matching the response schema, got an array"}
+		}
+		for _, element := range value {
+			if object, ok := element.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+	}
+	if schemaType.Kind() == reflect.Slice {
+		schemaType = schemaType.Elem()
+		for schemaType.Kind() == reflect.Ptr {
+			schemaType = schemaType.Elem()
+		}
+	}
+
+	var mismatches []string
+	for _, object := range objects {
+		for _, err := range validation.Struct(object, schemaType, an.config.TimeFormat) {
+			mismatches = append(mismatches, fmt.Sprintf("response schema: %s: %s", err.Field, err.Message))
+		}
+	}
+	return mismatches
+}
+
+// queryString returns the request's query in URL encoding
+func (r *pactRequest) queryString() (string, error) {
+	if len(r.Query) == 0 || string(r.Query) == "null" {
+		return "", nil
+	}
+	var raw string
+	if json.Unmarshal(r.Query, &raw) == nil {
+		return raw, nil
+	}
+	var values url.Values
+	if err := json.Unmarshal(r.Query, &values); err != nil {
+		return "", fmt.Errorf("invalid query: %w", err)
+	}
+	return values.Encode(), nil
+}
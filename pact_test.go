@@ -0,0 +1,167 @@
+package notelink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type pactUser struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+const testPact = `{
+  "consumer": {"name": "web"},
+  "provider": {"name": "users"},
+  "interactions": [
+    {
+      "description": "a request for user 1",
+      "providerState": "user 1 exists",
+      "request": {"method": "GET", "path": "/users/1"},
+      "response": {
+        "status": 200,
+        "headers": {"Content-Type": "application/json"},
+        "body": {"id": 1, "name": "Ada", "email": "ada@example.com"},
+        "matchingRules": {"$.body.email": {"match": "regex", "regex": "^[^@]+@example\\.com$"}}
+      }
+    },
+    {
+      "description": "a request for the user list",
+      "providerStates": [{"name": "users exist", "params": {"count": 2}}],
+      "request": {"method": "GET", "path": "/users", "query": {"page": ["1"]}},
+      "response": {
+        "status": 200,
+        "body": [{"id": 1, "name": "Ada"}],
+        "matchingRules": {"body": {"$": {"matchers": [{"match": "type"}]}, "$[*].name": {"matchers": [{"match": "type"}]}}}
+      }
+    },
+    {
+      "description": "a request for a missing user",
+      "request": {"method": "GET", "path": "/users/2"},
+      "response": {"status": 404}
+    }
+  ]
+}`
+
+// newPactAPI registers the provider routes verified in the tests
+func newPactAPI(t *testing.T, broken bool) *ApiNote {
+	t.Helper()
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	routes := []DocumentedRouteInput{
+		{
+			Method:          "GET",
+			Path:            "/users/:id",
+			Params:          []Parameter{{Name: "id", In: "path", Type: "integer", Required: true}},
+			SchemasResponse: pactUser{},
+			Responses:       map[string]string{"200": "User", "404": "Not found"},
+			Handler: func(c fiber.Ctx) error {
+				if c.Params("id") != "1" {
+					return c.SendStatus(fiber.StatusNotFound)
+				}
+				if broken {
+					return c.JSON(fiber.Map{"id": "1", "email": "ada@elsewhere.org"})
+				}
+				return c.JSON(pactUser{ID: 1, Name: "Ada", Email: "ada@example.com"})
+			},
+		},
+		{
+			Method:          "GET",
+			Path:            "/users",
+			SchemasResponse: []pactUser{},
+			Handler: func(c fiber.Ctx) error {
+				return c.JSON([]pactUser{{ID: 1, Name: "Ada"}, {ID: 2, Name: "Grace"}})
+			},
+		},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+	return api
+}
+
+// TestVerifyPact tests replaying a Pact contract against the registered handlers
+func TestVerifyPact(t *testing.T) {
+	opts := PactOptions{StateHandlers: map[string]func(map[string]interface{}) error{
+		"user 1 exists": func(map[string]interface{}) error { return nil },
+		"users exist": func(params map[string]interface{}) error {
+			if params["count"] != float64(2) {
+				t.Errorf("Expected the state params, got %v", params)
+			}
+			return nil
+		},
+	}}
+
+	tests := []struct {
+		name     string
+		broken   bool
+		opts     PactOptions
+		expected []string // Substrings of the failing interactions' mismatches
+	}{
+		{name: "satisfied contract", opts: opts},
+		{
+			name:   "broken provider",
+			broken: true,
+			opts:   opts,
+			expected: []string{
+				`$.id: expected 1, got "1"`,
+				"$.name: missing",
+				`"ada@elsewhere.org" to match`,
+				"response schema: name",
+			},
+		},
+		{
+			name:     "missing state handler",
+			opts:     PactOptions{},
+			expected: []string{`no state handler for provider state "user 1 exists"`, `"users exist"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newPactAPI(t, tt.broken)
+			failures, err := api.VerifyPact([]byte(testPact), tt.opts)
+			if err != nil {
+				t.Fatalf("Failed to verify contract: %v", err)
+			}
+
+			var mismatches []string
+			for _, failure := range failures {
+				mismatches = append(mismatches, failure.Mismatches...)
+			}
+			all := strings.Join(mismatches, "\n")
+			if len(tt.expected) == 0 && len(failures) > 0 {
+				t.Fatalf("Expected no failures, got:\n%s", all)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(all, expected) {
+					t.Errorf("Expected a mismatch containing %q, got:\n%s", expected, all)
+				}
+			}
+		})
+	}
+
+	if _, err := newPactAPI(t, false).VerifyPact([]byte("{"), opts); err == nil {
+		t.Error("Expected an error for an invalid contract")
+	}
+}
+
+// TestVerifyPactFiles tests verifying contract files from go test
+func TestVerifyPactFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "web-users.json")
+	if err := os.WriteFile(path, []byte(testPact), 0o600); err != nil {
+		t.Fatalf("Failed to write contract: %v", err)
+	}
+
+	noop := func(map[string]interface{}) error { return nil }
+	newPactAPI(t, false).VerifyPactFiles(t, PactOptions{StateHandlers: map[string]func(map[string]interface{}) error{
+		"user 1 exists": noop,
+		"users exist":   noop,
+	}}, path)
+}
@@ -0,0 +1,177 @@
+package notelink
+
+// commandPaletteMarkup is the command palette dialog, opened with ⌘K / Ctrl+K
+const commandPaletteMarkup = `
+    <div class="command-palette" id="command-palette" hidden>
+        <div class="command-palette-dialog" role="dialog" aria-modal="true" aria-label="Jump to endpoint">
+            <input type="text" class="command-palette-input" placeholder="Jump to endpoint by name or path..." aria-label="Search endpoints">
+            <ul class="command-palette-results" role="listbox"></ul>
+            <div class="command-palette-help">
+                <span><kbd>↑</kbd><kbd>↓</kbd> navigate</span>
+                <span><kbd>Enter</kbd> open</span>
+                <span><kbd>Esc</kbd> close</span>
+                <span><kbd>e</kbd> expand all</span>
+                <span><kbd>c</kbd> collapse all</span>
+                <span><kbd>a</kbd> auth token</span>
+            </div>
+        </div>
+    </div>`
+
+// commandPaletteScript implements the command palette and global keyboard shortcuts
+const commandPaletteScript = `
+            // Command palette (⌘K / Ctrl+K) and keyboard shortcuts
+            let paletteEntries = [];
+            let paletteSelection = 0;
+
+            function collectPaletteEntries() {
+                return Array.from(document.querySelectorAll('details.method-group')).map(element => {
+                    const method = element.querySelector(':scope > summary .method');
+                    const path = element.querySelector(':scope > summary .endpoint-path');
+                    const description = element.querySelector(':scope > summary .endpoint-description');
+                    return {
+                        id: element.id,
+                        method: method ? method.textContent.trim() : '',
+                        path: path ? path.textContent.trim() : '',
+                        description: description ? description.textContent.trim() : ''
+                    };
+                });
+            }
+
+            function openCommandPalette() {
+                const palette = document.getElementById('command-palette');
+                paletteEntries = collectPaletteEntries();
+                palette.hidden = false;
+                const input = palette.querySelector('.command-palette-input');
+                input.value = '';
+                renderPaletteResults('');
+                input.focus();
+            }
+
+            function closeCommandPalette() {
+                document.getElementById('command-palette').hidden = true;
+            }
+
+            function filterPaletteEntries(query) {
+                const terms = query.toLowerCase().split(/\s+/).filter(Boolean);
+                return paletteEntries.filter(entry => {
+                    const haystack = (entry.method + ' ' + entry.path + ' ' + entry.description).toLowerCase();
+                    return terms.every(term => haystack.includes(term));
+                }).slice(0, 50);
+            }
+
+            function renderPaletteResults(query) {
+                const list = document.querySelector('.command-palette-results');
+                const results = filterPaletteEntries(query);
+                paletteSelection = 0;
+                list.innerHTML = '';
+                if (results.length === 0) {
+                    list.innerHTML = '<li class="command-palette-empty">No matching endpoints</li>';
+                    return;
+                }
+                results.forEach((entry, index) => {
+                    const item = document.createElement('li');
+                    item.setAttribute('role', 'option');
+                    item.dataset.target = entry.id;
+                    item.innerHTML = '<span class="toc-method ' + escapeHtml(entry.method) + '">' + escapeHtml(entry.method) + '</span>' +
+                        '<span class="command-palette-path">' + escapeHtml(entry.path) + '</span>' +
+                        '<span class="command-palette-description">' + escapeHtml(entry.description) + '</span>';
+                    item.addEventListener('mousedown', e => {
+                        e.preventDefault();
+                        jumpToPaletteEntry(entry.id);
+                    });
+                    if (index === 0) {
+                        item.classList.add('selected');
+                    }
+                    list.appendChild(item);
+                });
+            }
+
+            function movePaletteSelection(delta) {
+                const items = document.querySelectorAll('.command-palette-results li[data-target]');
+                if (items.length === 0) {
+                    return;
+                }
+                items[paletteSelection].classList.remove('selected');
+                paletteSelection = (paletteSelection + delta + items.length) % items.length;
+                items[paletteSelection].classList.add('selected');
+                items[paletteSelection].scrollIntoView({ block: 'nearest' });
+            }
+
+            function jumpToPaletteEntry(id) {
+                closeCommandPalette();
+                openOperation(id);
+                const summary = document.querySelector('#' + CSS.escape(id) + ' > summary');
+                if (summary) {
+                    summary.focus();
+                }
+            }
+
+            function setAllDetails(open) {
+                document.querySelectorAll('.docs-main details').forEach(details => {
+                    details.open = open;
+                });
+            }
+
+            document.addEventListener('DOMContentLoaded', function() {
+                const palette = document.getElementById('command-palette');
+                const input = palette.querySelector('.command-palette-input');
+                input.addEventListener('input', () => renderPaletteResults(input.value));
+                input.addEventListener('keydown', e => {
+                    if (e.key === 'ArrowDown') {
+                        e.preventDefault();
+                        movePaletteSelection(1);
+                    } else if (e.key === 'ArrowUp') {
+                        e.preventDefault();
+                        movePaletteSelection(-1);
+                    } else if (e.key === 'Enter') {
+                        e.preventDefault();
+                        const selected = palette.querySelector('.command-palette-results li.selected');
+                        if (selected) {
+                            jumpToPaletteEntry(selected.dataset.target);
+                        }
+                    }
+                });
+                palette.addEventListener('mousedown', e => {
+                    if (e.target === palette) {
+                        closeCommandPalette();
+                    }
+                });
+            });
+
+            document.addEventListener('keydown', function(e) {
+                if ((e.metaKey || e.ctrlKey) && e.key.toLowerCase() === 'k') {
+                    e.preventDefault();
+                    const palette = document.getElementById('command-palette');
+                    if (palette.hidden) {
+                        openCommandPalette();
+                    } else {
+                        closeCommandPalette();
+                    }
+                    return;
+                }
+                if (e.key === 'Escape') {
+                    closeCommandPalette();
+                    return;
+                }
+
+                // Single-key shortcuts are ignored while typing
+                const target = e.target;
+                if (e.metaKey || e.ctrlKey || e.altKey || target.isContentEditable ||
+                    ['INPUT', 'TEXTAREA', 'SELECT'].includes(target.tagName) || target.closest('.CodeMirror')) {
+                    return;
+                }
+                if (e.key === 'e') {
+                    setAllDetails(true);
+                } else if (e.key === 'c') {
+                    setAllDetails(false);
+                } else if (e.key === 'a') {
+                    e.preventDefault();
+                    const auth = document.getElementById('auth-token');
+                    auth.scrollIntoView({ behavior: 'smooth', block: 'center' });
+                    auth.focus();
+                } else if (e.key === '/') {
+                    e.preventDefault();
+                    openCommandPalette();
+                }
+            });
+`
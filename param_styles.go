@@ -0,0 +1,206 @@
+package notelink
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Parameter serialization styles of OpenAPI 3, set with Parameter.Style
+const (
+	StyleSimple         = "simple"         // Paths and headers: 3,4,5
+	StyleLabel          = "label"          // Paths: .3.4.5
+	StyleMatrix         = "matrix"         // Paths: ;id=3;id=4;id=5
+	StyleForm           = "form"           // Queries: id=3&id=4&id=5
+	StyleSpaceDelimited = "spaceDelimited" // Queries: id=3%204%205
+	StylePipeDelimited  = "pipeDelimited"  // Queries: id=3|4|5
+	StyleDeepObject     = "deepObject"     // Queries: id[role]=admin
+)
+
+// parameterStyles are the styles allowed per location, the default first
+var parameterStyles = map[string][]string{
+	"path":   {StyleSimple, StyleLabel, StyleMatrix},
+	"query":  {StyleForm, StyleSpaceDelimited, StylePipeDelimited, StyleDeepObject},
+	"header": {StyleSimple},
+	"cookie": {StyleForm},
+}
+
+// parameterStyle returns the style of a parameter, or its location's default
+func parameterStyle(param *Parameter) string {
+	if param.Style != "" {
+		return param.Style
+	}
+	if styles := parameterStyles[param.In]; len(styles) > 0 {
+		return styles[0]
+	}
+	return StyleSimple
+}
+
+// parameterExplode returns whether a parameter's value is exploded: as set,
+// or only for form and deepObject by default
+func parameterExplode(param *Parameter) bool {
+	if param.Explode != nil {
+		return *param.Explode
+	}
+	style := parameterStyle(param)
+	return style == StyleForm || style == StyleDeepObject
+}
+
+// styledParameter reports whether a parameter needs the try-it client's
+// serializer rather than a plain value
+func styledParameter(param *Parameter) bool {
+	return param.Style != "" || param.Explode != nil || param.AllowReserved || param.Type == "array" || param.Type == "object"
+}
+
+// validateParameterStyles checks that the parameters' styles are defined for
+// their locations and value types
+func validateParameterStyles(params []Parameter) error {
+	for _, param := range params {
+		styles, known := parameterStyles[param.In]
+		if param.Style != "" && known && !slices.Contains(styles, param.Style) {
+			return fmt.Errorf("parameter %q: style %q is not allowed in %s, use one of %s", param.Name, param.Style, param.In, strings.Join(styles, ", "))
+		}
+		composite := param.Type == "array" || param.Type == "object"
+		switch param.Style {
+		case StyleSpaceDelimited, StylePipeDelimited:
+			if !composite {
+				return fmt.Errorf("parameter %q: style %s takes an array or object", param.Name, param.Style)
+			}
+		case StyleDeepObject:
+			if param.Type != "object" {
+				return fmt.Errorf("parameter %q: style deepObject takes an object", param.Name)
+			}
+			if param.Explode != nil && !*param.Explode {
+				return fmt.Errorf("parameter %q: style deepObject is always exploded", param.Name)
+			}
+		}
+		if param.AllowReserved && param.In != "query" {
+			return fmt.Errorf("parameter %q: AllowReserved only applies to query parameters", param.Name)
+		}
+		if param.Items != "" && param.Type != "array" {
+			return fmt.Errorf("parameter %q: Items only applies to array parameters", param.Name)
+		}
+	}
+	return nil
+}
+
+// parameterSchema returns the JSON Schema of a parameter's value
+func parameterSchema(param *Parameter) *JSONSchema {
+	switch param.Type {
+	case "array":
+		return &JSONSchema{Type: "array", Items: parameterTypeToJSONSchema(param.Items)}
+	case "object":
+		return &JSONSchema{Type: "object"}
+	}
+	return parameterTypeToJSONSchema(param.Type)
+}
+
+// parameterStyleAttrs renders the data attributes the try-it client
+// serializes a styled parameter's input with
+func parameterStyleAttrs(param *Parameter) string {
+	if !styledParameter(param) {
+		return ""
+	}
+	attrs := ` data-style="` + escapeHTML(parameterStyle(param)) + `" data-explode="` + strconv.FormatBool(parameterExplode(param)) + `" data-type="` + escapeHTML(param.Type) + `"`
+	if param.AllowReserved {
+		attrs += ` data-allow-reserved="true"`
+	}
+	return attrs
+}
+
+// parameterPlaceholder describes how to enter a parameter's value in the
+// try-it form
+func parameterPlaceholder(param *Parameter) string {
+	switch param.Type {
+	case "array":
+		return "Enter " + param.Name + " (comma-separated or a JSON array)"
+	case "object":
+		return "Enter " + param.Name + " (key=value pairs, comma-separated, or a JSON object)"
+	}
+	return "Enter " + param.Name
+}
+
+// paramStylesScript serializes the values of styled parameters as their
+// OpenAPI style, explode and allowReserved settings document them
+const paramStylesScript = `
+            // parseParamValue reads an array as comma-separated values or JSON,
+            // and an object as comma-separated key=value pairs or JSON
+            function parseParamValue(value, type) {
+                const text = value.trim();
+                if (type === 'array') {
+                    if (text.startsWith('[')) {
+                        try {
+                            return JSON.parse(text).map(String);
+                        } catch (e) {}
+                    }
+                    return text.split(',').map(item => item.trim());
+                }
+                if (type === 'object') {
+                    if (text.startsWith('{')) {
+                        try {
+                            return Object.entries(JSON.parse(text)).map(([key, item]) => [key, String(item)]);
+                        } catch (e) {}
+                    }
+                    return text.split(',').map(pair => {
+                        const [key, ...rest] = pair.split('=');
+                        return [key.trim(), rest.join('=').trim()];
+                    });
+                }
+                return value;
+            }
+
+            // serializeParam returns a styled path segment, header value or
+            // query string part
+            function serializeParam(name, value, input) {
+                const location = input.dataset.in;
+                const style = input.dataset.style;
+                const explode = input.dataset.explode === 'true';
+                const type = input.dataset.type;
+                const reserved = /%(3A|2F|3F|23|5B|5D|40|21|24|26|27|28|29|2A|2B|2C|3B|3D)/gi;
+                const encode = location === 'header' ? (text => text)
+                    : input.dataset.allowReserved === 'true' ? (text => encodeURIComponent(text).replace(reserved, decodeURIComponent))
+                    : encodeURIComponent;
+                const parsed = parseParamValue(value, type);
+                const items = type === 'array' ? parsed.map(encode) : null;
+                const pairs = type === 'object' ? parsed.map(([key, item]) => [encode(key), encode(item)]) : null;
+                const flat = pairs ? pairs.flat() : items;
+                const key = location === 'query' ? encodeURIComponent(name) : name;
+
+                switch (style) {
+                    case 'label':
+                        if (!flat) {
+                            return '.' + encode(parsed);
+                        }
+                        return '.' + (explode ? (pairs ? pairs.map(pair => pair.join('=')) : items).join('.') : flat.join(','));
+                    case 'matrix':
+                        if (!flat) {
+                            return ';' + key + '=' + encode(parsed);
+                        }
+                        if (explode) {
+                            return pairs ? pairs.map(pair => ';' + pair.join('=')).join('') : items.map(item => ';' + key + '=' + item).join('');
+                        }
+                        return ';' + key + '=' + flat.join(',');
+                    case 'form':
+                        if (!flat) {
+                            return key + '=' + encode(parsed);
+                        }
+                        if (explode) {
+                            return pairs ? pairs.map(pair => pair.join('=')).join('&') : items.map(item => key + '=' + item).join('&');
+                        }
+                        return key + '=' + flat.join(',');
+                    case 'spaceDelimited':
+                    case 'pipeDelimited':
+                        if (explode) {
+                            return pairs ? pairs.map(pair => pair.join('=')).join('&') : items.map(item => key + '=' + item).join('&');
+                        }
+                        return key + '=' + flat.join(style === 'pipeDelimited' ? '|' : '%20');
+                    case 'deepObject':
+                        return pairs.map(([field, item]) => key + '[' + field + ']=' + item).join('&');
+                    default:
+                        if (!flat) {
+                            return encode(parsed);
+                        }
+                        return (explode && pairs ? pairs.map(pair => pair.join('=')) : flat).join(',');
+                }
+            }`
@@ -0,0 +1,88 @@
+package notelink
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestParameterStyles tests documenting and validating parameter serialization
+func TestParameterStyles(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return nil }
+	noExplode := false
+	tests := []struct {
+		name  string
+		param Parameter
+		spec  string
+		attrs string
+		err   string
+	}{
+		{
+			name:  "plain",
+			param: Parameter{Name: "q", In: "query", Type: "string"},
+			spec:  `{"schema":{"type":"string"},"name":"q","in":"query"}`,
+		},
+		{
+			name:  "pipe delimited array",
+			param: Parameter{Name: "ids", In: "query", Type: "array", Items: "integer", Style: StylePipeDelimited, Explode: &noExplode},
+			spec:  `{"schema":{"items":{"type":"integer"},"type":"array"},"name":"ids","in":"query","style":"pipeDelimited","explode":false}`,
+			attrs: `data-in="query" data-style="pipeDelimited" data-explode="false" data-type="array">`,
+		},
+		{
+			name:  "deep object",
+			param: Parameter{Name: "filter", In: "query", Type: "object", Style: StyleDeepObject},
+			spec:  `{"schema":{"type":"object"},"name":"filter","in":"query","style":"deepObject"}`,
+			attrs: `data-in="query" data-style="deepObject" data-explode="true" data-type="object">`,
+		},
+		{
+			name:  "reserved characters",
+			param: Parameter{Name: "redirect", In: "query", Type: "string", AllowReserved: true},
+			spec:  `{"schema":{"type":"string"},"name":"redirect","in":"query","allowReserved":true}`,
+			attrs: `data-style="form" data-explode="true" data-type="string" data-allow-reserved="true">`,
+		},
+		{
+			name:  "default array style",
+			param: Parameter{Name: "tags", In: "header", Type: "array"},
+			attrs: `data-in="header" data-style="simple" data-explode="false" data-type="array">`,
+		},
+		{name: "style of another location", param: Parameter{Name: "q", In: "header", Type: "array", Style: StyleForm}, err: `style "form" is not allowed in header`},
+		{name: "delimited primitive", param: Parameter{Name: "q", In: "query", Type: "string", Style: StyleSpaceDelimited}, err: "takes an array or object"},
+		{name: "deep array", param: Parameter{Name: "q", In: "query", Type: "array", Style: StyleDeepObject}, err: "takes an object"},
+		{name: "unexploded deep object", param: Parameter{Name: "q", In: "query", Type: "object", Style: StyleDeepObject, Explode: &noExplode}, err: "always exploded"},
+		{name: "reserved header", param: Parameter{Name: "X-Next", In: "header", Type: "string", AllowReserved: true}, err: "only applies to query parameters"},
+		{name: "items of a string", param: Parameter{Name: "q", In: "query", Type: "string", Items: "integer"}, err: "only applies to array parameters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API"}, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/items", Handler: handler, Params: []Parameter{tt.param}})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+			if tt.spec != "" {
+				data, err := json.Marshal(api.GenerateOpenAPISpec().Paths["/items"].Get.Parameters[0])
+				if err != nil {
+					t.Fatalf("Failed to marshal the parameter: %v", err)
+				}
+				if string(data) != tt.spec {
+					t.Errorf("Expected parameter %s, got %s", tt.spec, data)
+				}
+			}
+			html := api.generateHTML()
+			if tt.attrs == "" && strings.Contains(html, "data-style=") {
+				t.Error("Expected plain parameters to skip the serializer")
+			} else if !strings.Contains(html, tt.attrs) {
+				t.Errorf("Expected the try-it input to carry %q", tt.attrs)
+			}
+		})
+	}
+}
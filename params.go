@@ -0,0 +1,117 @@
+package notelink
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+)
+
+// paramTags maps the struct tags read by ParamsFromStruct to parameter locations.
+// They match the tags c.Bind().Query, Header and URI decode, so one struct
+// documents, validates and binds the parameters.
+var paramTags = []struct {
+	tag string
+	in  string
+}{
+	{tag: "query", in: "query"},
+	{tag: "header", in: "header"},
+	{tag: "uri", in: "path"},
+}
+
+// ParamsFromStruct derives parameters from the tagged fields of a struct, e.g.
+//
+//	type ListUsersQuery struct {
+//	    Page   int    `query:"page" doc:"Page number" required:"true"`
+//	    Search string `query:"q" doc:"Full-text search"`
+//	    Tenant string `header:"X-Tenant-ID"`
+//	}
+//
+// query, header and uri tags set the parameter name and location, doc the
+// description and required:"true" makes it required. The type follows the Go
+// field type. Embedded structs are flattened and untagged fields are skipped.
+func ParamsFromStruct(v interface{}) []Parameter {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return nil
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	return structParams(typ)
+}
+
+// structParams collects the parameters declared by a struct type's fields
+func structParams(typ reflect.Type) []Parameter {
+	var params []Parameter
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == reflect.Struct {
+			params = append(params, structParams(fieldType)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		for _, location := range paramTags {
+			name := strings.Split(field.Tag.Get(location.tag), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			params = append(params, Parameter{
+				Name:        name,
+				In:          location.in,
+				Type:        paramType(fieldType),
+				Description: field.Tag.Get("doc"),
+				Required:    field.Tag.Get("required") == "true" || location.in == "path",
+			})
+			break
+		}
+	}
+	return params
+}
+
+// paramType maps a Go field type to a parameter type. Slices and other
+// composite values are documented as strings, as they arrive unparsed.
+func paramType(t reflect.Type) string {
+	if valueType, ok := validation.NullableValueType(t); ok {
+		t = valueType
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// mergeParams appends derived parameters that params doesn't already declare
+func mergeParams(params, derived []Parameter) []Parameter {
+	for _, param := range derived {
+		declared := false
+		for _, existing := range params {
+			if existing.Name == param.Name && existing.In == param.In {
+				declared = true
+				break
+			}
+		}
+		if !declared {
+			params = append(params, param)
+		}
+	}
+	return params
+}
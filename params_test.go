@@ -0,0 +1,97 @@
+package notelink
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type pagination struct {
+	Page  int `query:"page" doc:"Page number" required:"true"`
+	Limit int `query:"limit,omitempty" doc:"Page size"`
+}
+
+type listUsersQuery struct {
+	pagination
+	Search   string          `query:"q" doc:"Full-text search"`
+	Active   *bool           `query:"active"`
+	MinScore sql.NullFloat64 `query:"min_score"`
+	Roles    []string        `query:"role"`
+	Tenant   string          `header:"X-Tenant-ID" required:"true"`
+	Org      string          `uri:"org"`
+	Internal string
+}
+
+// TestParamsFromStruct tests deriving parameters from struct tags
+func TestParamsFromStruct(t *testing.T) {
+	expected := []Parameter{
+		{Name: "page", In: "query", Type: "integer", Description: "Page number", Required: true},
+		{Name: "limit", In: "query", Type: "integer", Description: "Page size"},
+		{Name: "q", In: "query", Type: "string", Description: "Full-text search"},
+		{Name: "active", In: "query", Type: "boolean"},
+		{Name: "min_score", In: "query", Type: "number"},
+		{Name: "role", In: "query", Type: "string"},
+		{Name: "X-Tenant-ID", In: "header", Type: "string", Required: true},
+		{Name: "org", In: "path", Type: "string", Required: true},
+	}
+	if got := ParamsFromStruct(&listUsersQuery{}); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+	if got := ParamsFromStruct("not a struct"); got != nil {
+		t.Errorf("Expected no parameters for a non-struct, got %+v", got)
+	}
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:       "GET",
+		Path:         "/orgs/:org/users",
+		Params:       []Parameter{{Name: "org", In: "path", Type: "string", Description: "Organization slug", Required: true}},
+		ParamsSchema: listUsersQuery{},
+		Handler: func(c fiber.Ctx) error {
+			var query listUsersQuery
+			if err := c.Bind().Query(&query); err != nil {
+				return err
+			}
+			return c.JSON(fiber.Map{"page": query.Page})
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	params := api.endpoints["GET /orgs/:org/users"].Parameters
+	if len(params) != len(expected) || params[0].Description != "Organization slug" {
+		t.Errorf("Expected declared Params to take precedence over derived ones, got %+v", params)
+	}
+
+	tests := []struct {
+		name   string
+		url    string
+		tenant string
+		status int
+	}{
+		{name: "Valid", url: "/orgs/acme/users?page=2", tenant: "t1", status: 200},
+		{name: "Missing required query", url: "/orgs/acme/users", tenant: "t1", status: 400},
+		{name: "Wrong type", url: "/orgs/acme/users?page=two", tenant: "t1", status: 400},
+		{name: "Missing required header", url: "/orgs/acme/users?page=2", status: 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.tenant != "" {
+				req.Header.Set("X-Tenant-ID", tt.tenant)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package notelink
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// fiberPathParam matches Fiber-style path parameters: ":name", optional ":name?",
+// wildcards "*" / "*1" and greedy "+" / "+1"
+var fiberPathParam = regexp.MustCompile(`:(\w+)(\?)?|([*+])(\d*)`)
+
+// Path segment kinds reported as x-notelink-segment in the spec
+const (
+	segmentOptional = "optional"
+	segmentWildcard = "wildcard"
+	segmentGreedy   = "greedy"
+)
+
+// pathParam describes a parameter in a Fiber route pattern
+type pathParam struct {
+	Name string // Runtime name, as passed to c.Params
+	Kind string // "", segmentOptional, segmentWildcard or segmentGreedy
+}
+
+// specName returns the name used for the parameter in OpenAPI path templates
+func (p pathParam) specName() string {
+	return specParamName(p.Name)
+}
+
+// specParamName maps Fiber wildcard names to valid OpenAPI parameter names
+// Example: * -> wildcard, *2 -> wildcard2, + -> plus
+func specParamName(name string) string {
+	switch {
+	case strings.HasPrefix(name, "*"):
+		return "wildcard" + name[1:]
+	case strings.HasPrefix(name, "+"):
+		return "plus" + name[1:]
+	default:
+		return name
+	}
+}
+
+// pathParams returns the parameters in a Fiber route pattern
+func pathParams(path string) []pathParam {
+	matches := fiberPathParam.FindAllStringSubmatch(path, -1)
+	params := make([]pathParam, 0, len(matches))
+	for _, match := range matches {
+		switch {
+		case match[1] != "" && match[2] != "":
+			params = append(params, pathParam{Name: match[1], Kind: segmentOptional})
+		case match[1] != "":
+			params = append(params, pathParam{Name: match[1]})
+		case match[3] == "*":
+			params = append(params, pathParam{Name: "*" + match[4], Kind: segmentWildcard})
+		default:
+			params = append(params, pathParam{Name: "+" + match[4], Kind: segmentGreedy})
+		}
+	}
+	return params
+}
+
+// pathParamKind returns the segment kind of the named parameter in a route pattern
+func pathParamKind(path, name string) string {
+	for _, param := range pathParams(path) {
+		if param.Name == name {
+			return param.Kind
+		}
+	}
+	return ""
+}
+
+// openAPIPath converts a Fiber route pattern to OpenAPI path template syntax.
+// Optional and wildcard segments become regular templates; the original pattern
+// is kept in the operation's x-notelink-route extension.
+// Example: /api/v1/users/:id -> /api/v1/users/{id}, /files/* -> /files/{wildcard}
+func openAPIPath(path string) string {
+	return fiberPathParam.ReplaceAllStringFunc(path, func(match string) string {
+		params := pathParams(match)
+		if len(params) == 0 {
+			return match
+		}
+		return "{" + params[0].specName() + "}"
+	})
+}
+
+// hasDynamicSegments reports whether a route pattern contains optional or wildcard segments
+func hasDynamicSegments(path string) bool {
+	for _, param := range pathParams(path) {
+		if param.Kind != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// isDynamicSegment reports whether a path segment is an optional or wildcard segment
+func isDynamicSegment(segment string) bool {
+	return strings.HasPrefix(segment, "*") || strings.HasPrefix(segment, "+") ||
+		(strings.HasPrefix(segment, ":") && strings.HasSuffix(segment, "?"))
+}
+
+// hasPathParameter reports whether params declares the named path parameter
+func hasPathParameter(params []Parameter, name string) bool {
+	for _, param := range params {
+		if param.In == "path" && param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// routePath returns the endpoint's path without Config.BasePath, falling back
+// to Path for endpoints built without one
+func (e *Endpoint) routePath() string {
+	if e.RoutePath != "" {
+		return e.RoutePath
+	}
+	return e.Path
+}
+
+// derivePathParameters declares path placeholders missing from the endpoint's
+// parameters, so they are validated, documented and fillable in the try-it form.
+// Regular and greedy segments are required strings; optional and wildcard
+// segments may be empty. A warning is logged for each derived parameter.
+func derivePathParameters(endpoint *Endpoint) {
+	for _, param := range pathParams(endpoint.Path) {
+		if hasPathParameter(endpoint.Parameters, param.Name) {
+			continue
+		}
+
+		log.Warnf("notelink: %s %s: path parameter %q is not declared in Params, documenting it as a string", endpoint.Method, endpoint.Path, param.Name)
+		derived := Parameter{
+			Name:     param.Name,
+			In:       "path",
+			Type:     "string",
+			Required: param.Kind == "" || param.Kind == segmentGreedy,
+		}
+		switch param.Kind {
+		case segmentOptional:
+			derived.Description = "Optional path segment"
+		case segmentWildcard:
+			derived.Description = "Wildcard, matches the rest of the path (may be empty)"
+		case segmentGreedy:
+			derived.Description = "Wildcard, matches the rest of the path"
+		}
+		endpoint.Parameters = append(endpoint.Parameters, derived)
+	}
+}
@@ -0,0 +1,190 @@
+package notelink
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// TestOpenAPIPath tests converting Fiber route patterns to OpenAPI paths
+func TestOpenAPIPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "Static", path: "/api/v1/users", expected: "/api/v1/users"},
+		{name: "Single param", path: "/api/v1/users/:id", expected: "/api/v1/users/{id}"},
+		{name: "Multiple params", path: "/orgs/:orgId/users/:userId", expected: "/orgs/{orgId}/users/{userId}"},
+		{name: "Params within a segment", path: "/files/:name.:ext", expected: "/files/{name}.{ext}"},
+		{name: "Already OpenAPI style", path: "/users/{id}", expected: "/users/{id}"},
+		{name: "Optional param", path: "/users/:id?", expected: "/users/{id}"},
+		{name: "Wildcard", path: "/files/*", expected: "/files/{wildcard}"},
+		{name: "Numbered wildcards", path: "/copy/*1/to/*2", expected: "/copy/{wildcard1}/to/{wildcard2}"},
+		{name: "Greedy param", path: "/static/+", expected: "/static/{plus}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := openAPIPath(tt.path); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestSpecPathParameters tests that spec paths use {param} syntax and declare path placeholders
+func TestSpecPathParameters(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/orgs/:orgId/users/:userId",
+		Description: "Get an org member",
+		Handler:     func(c fiber.Ctx) error { return c.SendStatus(200) },
+		Params: []Parameter{
+			{Name: "userId", In: "path", Type: "integer", Description: "User ID", Required: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if _, ok := spec.Paths["/orgs/:orgId/users/:userId"]; ok {
+		t.Error("Expected Fiber-style path to be converted")
+	}
+	pathItem, ok := spec.Paths["/orgs/{orgId}/users/{userId}"]
+	if !ok || pathItem.Get == nil {
+		t.Fatalf("Expected OpenAPI-style path, got %v", spec.Paths)
+	}
+
+	params := make(map[string]ParameterSpec)
+	for _, param := range pathItem.Get.Parameters {
+		params[param.Name] = param
+	}
+	if len(params) != 2 {
+		t.Fatalf("Expected 2 parameters, got %d", len(params))
+	}
+	if params["userId"].Schema.Type != "integer" {
+		t.Errorf("Expected declared parameter to keep its type, got %s", params["userId"].Schema.Type)
+	}
+	if orgID := params["orgId"]; orgID.In != "path" || !orgID.Required || orgID.Schema.Type != "string" {
+		t.Errorf("Expected orgId to be auto-declared as a required string path parameter, got %+v", orgID)
+	}
+
+	// Runtime routes keep the Fiber syntax
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/orgs/1/users/2", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+// TestDerivePathParameters tests that undeclared path parameters are added at registration
+func TestDerivePathParameters(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/orders/:orderId/items/:itemId",
+		Description: "Get an order item",
+		Handler:     func(c fiber.Ctx) error { return c.SendStatus(200) },
+		Params: []Parameter{
+			{Name: "itemId", In: "path", Type: "number", Required: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	params := api.endpoints["GET /orders/:orderId/items/:itemId"].Parameters
+	if len(params) != 2 {
+		t.Fatalf("Expected 2 parameters, got %+v", params)
+	}
+	if derived := params[1]; derived.Name != "orderId" || derived.In != "path" || derived.Type != "string" || !derived.Required {
+		t.Errorf("Expected orderId to be derived as a required string, got %+v", derived)
+	}
+	if !strings.Contains(logs.String(), `"orderId"`) || strings.Contains(logs.String(), `"itemId"`) {
+		t.Errorf("Expected a warning for orderId only, got %q", logs.String())
+	}
+	if !strings.Contains(api.generateHTML(), `name="orderId"`) {
+		t.Error("Expected derived parameter in the try-it form")
+	}
+}
+
+// TestDynamicPathSegments tests documenting optional and wildcard path segments
+func TestDynamicPathSegments(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendString(c.Params("*")) }
+	for _, path := range []string{"/v1/files/*", "/v1/users/:id?"} {
+		err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: path, Description: "Dynamic", Handler: handler})
+		if err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		specPath       string
+		route          string
+		paramName      string
+		segment        string
+		requestPath    string
+		expectedStatus int
+	}{
+		{name: "Wildcard", specPath: "/v1/files/{wildcard}", route: "/v1/files/*", paramName: "wildcard", segment: "wildcard", requestPath: "/v1/files/a/b.txt", expectedStatus: 200},
+		{name: "Empty wildcard", specPath: "/v1/files/{wildcard}", route: "/v1/files/*", paramName: "wildcard", segment: "wildcard", requestPath: "/v1/files/", expectedStatus: 200},
+		{name: "Optional present", specPath: "/v1/users/{id}", route: "/v1/users/:id?", paramName: "id", segment: "optional", requestPath: "/v1/users/7", expectedStatus: 200},
+		{name: "Optional missing", specPath: "/v1/users/{id}", route: "/v1/users/:id?", paramName: "id", segment: "optional", requestPath: "/v1/users", expectedStatus: 200},
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathItem, ok := spec.Paths[tt.specPath]
+			if !ok || pathItem.Get == nil {
+				t.Fatalf("Expected spec path %s, got %v", tt.specPath, spec.Paths)
+			}
+			if pathItem.Get.Route != tt.route {
+				t.Errorf("Expected x-notelink-route %s, got %s", tt.route, pathItem.Get.Route)
+			}
+			params := pathItem.Get.Parameters
+			if len(params) != 1 || params[0].Name != tt.paramName || !params[0].Required || params[0].Segment != tt.segment {
+				t.Errorf("Unexpected path parameters: %+v", params)
+			}
+
+			// Optional and wildcard segments may be empty at runtime
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", tt.requestPath, nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+
+	html := api.generateHTML()
+	if strings.Contains(html, "<summary>*</summary>") || strings.Contains(html, "<summary>:id?</summary>") {
+		t.Error("Expected optional and wildcard segments to stay with their parent group")
+	}
+	if !strings.Contains(html, "function buildPath(path, values)") {
+		t.Error("Expected the try-it URL builder")
+	}
+}
@@ -0,0 +1,266 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+const (
+	// DefaultPayloadSizeSamples is the number of recent payloads per route
+	// and direction the size percentiles are computed over
+	DefaultPayloadSizeSamples = 1000
+	// DefaultPayloadDivergenceFactor is how many times larger or smaller than
+	// the documented example the median payload may be before a warning
+	DefaultPayloadDivergenceFactor = 10
+)
+
+// minDivergenceSamples is the number of payloads recorded before they are
+// compared with the documented example
+const minDivergenceSamples = 100
+
+// PayloadSizeConfig records the request and response body sizes of every
+// documented route (see Config.PayloadSizes), served as p50/p95 sizes at
+// /api-docs/payload-sizes and, for Prometheus, at
+// /api-docs/payload-sizes/metrics. Sizes are of the bodies before
+// compression; failed requests don't record a response size.
+type PayloadSizeConfig struct {
+	// Samples is the number of recent payloads the percentiles are computed
+	// over (default DefaultPayloadSizeSamples)
+	Samples int
+	// DivergenceFactor logs a warning, once per route and direction, when the
+	// median payload is this many times larger or smaller than the documented
+	// example; defaults to DefaultPayloadDivergenceFactor, negative disables it
+	DivergenceFactor float64
+}
+
+// samples returns the configured sample count or the default
+func (p *PayloadSizeConfig) samples() int {
+	if p.Samples <= 0 {
+		return DefaultPayloadSizeSamples
+	}
+	return p.Samples
+}
+
+// divergenceFactor returns the configured factor or the default
+func (p *PayloadSizeConfig) divergenceFactor() float64 {
+	if p.DivergenceFactor == 0 {
+		return DefaultPayloadDivergenceFactor
+	}
+	return p.DivergenceFactor
+}
+
+// PayloadSizeStats summarizes the body sizes of a route in one direction
+type PayloadSizeStats struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	Direction    string `json:"direction"` // "request" or "response"
+	Count        int64  `json:"count"`
+	TotalBytes   int64  `json:"total_bytes"`
+	P50          int    `json:"p50"` // Over the recent payloads
+	P95          int    `json:"p95"`
+	Max          int    `json:"max"`
+	ExampleBytes int    `json:"example_bytes,omitempty"` // Size of the documented example
+	Diverges     bool   `json:"diverges,omitempty"`      // The median is DivergenceFactor off the example
+}
+
+// payloadSizes keeps the recent body sizes of a route in one direction
+type payloadSizes struct {
+	mu      sync.Mutex
+	samples []int // Ring of the recent sizes
+	next    int
+	count   int64
+	total   int64
+	example int  // Compact size of the documented example, 0 when there is none
+	warned  bool // The divergence warning was logged
+}
+
+// record adds a body size, returning the median once enough payloads were
+// recorded to compare with the example and no warning was logged yet
+func (p *payloadSizes) record(size, capacity int) (median int, compare bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.samples) < capacity {
+		p.samples = append(p.samples, size)
+	} else {
+		p.samples[p.next] = size
+		p.next = (p.next + 1) % capacity
+	}
+	p.count++
+	p.total += int64(size)
+	if p.warned || p.example == 0 || p.count < minDivergenceSamples {
+		return 0, false
+	}
+	return percentile(p.samples, 0.5), true
+}
+
+// stats summarizes the sizes
+func (p *payloadSizes) stats(factor float64) PayloadSizeStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := PayloadSizeStats{Count: p.count, TotalBytes: p.total, ExampleBytes: p.example}
+	if len(p.samples) > 0 {
+		stats.P50, stats.P95 = percentile(p.samples, 0.5), percentile(p.samples, 0.95)
+		for _, size := range p.samples {
+			stats.Max = max(stats.Max, size)
+		}
+		stats.Diverges = p.count >= minDivergenceSamples && diverges(stats.P50, p.example, factor)
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sizes
+func percentile(sizes []int, p float64) int {
+	sorted := append([]int(nil), sizes...)
+	sort.Ints(sorted)
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+// diverges reports whether a size is factor times larger or smaller than the example
+func diverges(size, example int, factor float64) bool {
+	if factor <= 0 || example == 0 {
+		return false
+	}
+	return float64(size) > float64(example)*factor || float64(size)*factor < float64(example)
+}
+
+// PayloadSizeTracker records the request and response body sizes of a route
+type PayloadSizeTracker struct {
+	request, response payloadSizes
+}
+
+// exampleSize returns the compact size of a documented example body: the
+// first named example, else the example generated from the schema
+func (an *ApiNote) exampleSize(schema interface{}, named interface{}) int {
+	if named != nil {
+		data, err := json.Marshal(named)
+		if err == nil {
+			return len(data)
+		}
+	}
+	if schema == nil {
+		return 0
+	}
+	example, err := generateJSONTemplate(schema, an.exampleOptions())
+	if err != nil {
+		return 0
+	}
+	var compact bytes.Buffer
+	if json.Compact(&compact, []byte(example)) != nil {
+		return 0
+	}
+	return compact.Len()
+}
+
+// newPayloadSizeTracker creates the tracker of an endpoint, sizing its examples
+func (an *ApiNote) newPayloadSizeTracker(endpoint *Endpoint) *PayloadSizeTracker {
+	tracker := &PayloadSizeTracker{}
+	var requestExample, responseExample interface{}
+	if len(endpoint.RequestExamples) > 0 {
+		requestExample = endpoint.RequestExamples[0].Value
+	}
+	for _, example := range endpoint.ResponseExamples {
+		if example.Status >= 200 && example.Status < 300 {
+			responseExample = example.Value
+			break
+		}
+	}
+	tracker.request.example = an.exampleSize(endpoint.RequestSchema, requestExample)
+	tracker.response.example = an.exampleSize(endpoint.ResponseSchema, responseExample)
+	return tracker
+}
+
+// payloadSizesMiddleware records the body sizes of an endpoint's requests and
+// warns when they diverge from the documented examples
+func (an *ApiNote) payloadSizesMiddleware(endpoint *Endpoint) fiber.Handler {
+	tracker := endpoint.PayloadSizes
+	method, path := endpoint.Method, endpoint.Path
+	capacity := an.config.PayloadSizes.samples()
+	factor := an.config.PayloadSizes.divergenceFactor()
+	check := func(sizes *payloadSizes, direction string, median int) {
+		if !diverges(median, sizes.example, factor) {
+			return
+		}
+		sizes.mu.Lock()
+		warned := sizes.warned
+		sizes.warned = true
+		sizes.mu.Unlock()
+		if !warned {
+			log.Warnw("notelink: payload sizes diverge from the documented example", "method", method, "path", path,
+				"direction", direction, "median_bytes", median, "example_bytes", sizes.example)
+		}
+	}
+	return func(c fiber.Ctx) error {
+		if len(c.Body()) > 0 {
+			if median, compare := tracker.request.record(len(c.Body()), capacity); compare {
+				check(&tracker.request, "request", median)
+			}
+		}
+		err := c.Next()
+		if err == nil && !c.Response().IsBodyStream() {
+			if median, compare := tracker.response.record(len(c.Response().Body()), capacity); compare {
+				check(&tracker.response, "response", median)
+			}
+		}
+		return err
+	}
+}
+
+// PayloadSizes returns the body sizes of every route with recorded payloads,
+// ordered by route and direction
+func (an *ApiNote) PayloadSizes() []PayloadSizeStats {
+	stats := []PayloadSizeStats{}
+	if an.config.PayloadSizes == nil {
+		return stats
+	}
+	factor := an.config.PayloadSizes.divergenceFactor()
+	for _, endpoint := range an.endpoints {
+		if endpoint.PayloadSizes == nil {
+			continue
+		}
+		for _, direction := range []struct {
+			name  string
+			sizes *payloadSizes
+		}{{"request", &endpoint.PayloadSizes.request}, {"response", &endpoint.PayloadSizes.response}} {
+			stat := direction.sizes.stats(factor)
+			if stat.Count == 0 {
+				continue
+			}
+			stat.Method, stat.Path, stat.Direction = endpoint.Method, endpoint.Path, direction.name
+			stats = append(stats, stat)
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Path != stats[j].Path {
+			return stats[i].Path < stats[j].Path
+		}
+		if stats[i].Method != stats[j].Method {
+			return stats[i].Method < stats[j].Method
+		}
+		return stats[i].Direction < stats[j].Direction
+	})
+	return stats
+}
+
+// payloadSizesPrometheus renders the body sizes as Prometheus summaries
+func (an *ApiNote) payloadSizesPrometheus() string {
+	var out strings.Builder
+	out.WriteString("# HELP notelink_payload_size_bytes Body sizes per route and direction\n")
+	out.WriteString("# TYPE notelink_payload_size_bytes summary\n")
+	for _, stat := range an.PayloadSizes() {
+		labels := fmt.Sprintf("method=%q,path=%q,direction=%q", stat.Method, stat.Path, stat.Direction)
+		fmt.Fprintf(&out, "notelink_payload_size_bytes{%s,quantile=\"0.5\"} %d\n", labels, stat.P50)
+		fmt.Fprintf(&out, "notelink_payload_size_bytes{%s,quantile=\"0.95\"} %d\n", labels, stat.P95)
+		fmt.Fprintf(&out, "notelink_payload_size_bytes_sum{%s} %s\n", labels, strconv.FormatInt(stat.TotalBytes, 10))
+		fmt.Fprintf(&out, "notelink_payload_size_bytes_count{%s} %s\n", labels, strconv.FormatInt(stat.Count, 10))
+	}
+	return out.String()
+}
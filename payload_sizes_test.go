@@ -0,0 +1,106 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestPayloadSizeStats tests the percentiles and the divergence from the example
+func TestPayloadSizeStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    []int
+		example  int
+		capacity int
+		want     PayloadSizeStats
+	}{
+		{"single payload", []int{40}, 0, 10, PayloadSizeStats{Count: 1, TotalBytes: 40, P50: 40, P95: 40, Max: 40}},
+		{"percentiles", repeatSizes(0, 100), 50, 1000, PayloadSizeStats{Count: 100, TotalBytes: 5050, P50: 50, P95: 95, Max: 100, ExampleBytes: 50}},
+		{"ring keeps the recent payloads", append(repeatSizes(1000, 1000), repeatSizes(0, 10)...), 0, 10, PayloadSizeStats{Count: 1010, TotalBytes: 1000*1000 + 55, P50: 5, P95: 10, Max: 10}},
+		{"diverging example", repeatSizes(1000, 100), 20, 1000, PayloadSizeStats{Count: 100, TotalBytes: 100000, P50: 1000, P95: 1000, Max: 1000, ExampleBytes: 20, Diverges: true}},
+		{"too few payloads to diverge", repeatSizes(1000, 10), 20, 1000, PayloadSizeStats{Count: 10, TotalBytes: 10000, P50: 1000, P95: 1000, Max: 1000, ExampleBytes: 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sizes := &payloadSizes{example: tt.example}
+			for _, size := range tt.sizes {
+				sizes.record(size, tt.capacity)
+			}
+			if got := sizes.stats(DefaultPayloadDivergenceFactor); got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+// repeatSizes returns count payloads of the same size, or 1..count for size 0
+func repeatSizes(size, count int) []int {
+	sizes := make([]int, count)
+	for i := range sizes {
+		sizes[i] = size
+		if size == 0 {
+			sizes[i] = i + 1
+		}
+	}
+	return sizes
+}
+
+// TestPayloadSizesRoutes tests recording body sizes and serving them
+func TestPayloadSizesRoutes(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+	api := NewApiNote(&Config{Title: "Test API", PayloadSizes: &PayloadSizeConfig{}}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "POST",
+		Path:            "/v1/users",
+		Description:     "Create user",
+		SchemasRequest:  user{},
+		SchemasResponse: user{},
+		Handler: func(c fiber.Ctx) error {
+			return c.SendString(strings.Repeat("x", 500))
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	for i := 0; i < minDivergenceSamples; i++ {
+		req := httptest.NewRequest("POST", "/v1/users", strings.NewReader(`{"name":"Ada"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if _, err := api.Fiber().Test(req); err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+	}
+
+	stats := api.PayloadSizes()
+	if len(stats) != 2 {
+		t.Fatalf("Expected request and response sizes, got %+v", stats)
+	}
+	request, response := stats[0], stats[1]
+	if request.Direction != "request" || request.P95 != 14 || request.Count != minDivergenceSamples || request.Diverges {
+		t.Errorf("Unexpected request sizes %+v", request)
+	}
+	if response.Direction != "response" || response.P95 != 500 || response.ExampleBytes == 0 || !response.Diverges {
+		t.Errorf("Expected the response to diverge from the example, got %+v", response)
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/payload-sizes/metrics", nil))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`notelink_payload_size_bytes{method="POST",path="/v1/users",direction="response",quantile="0.95"} 500`,
+		`notelink_payload_size_bytes_count{method="POST",path="/v1/users",direction="request"} 100`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RequestPreset is a named test case of a route, rendered as a chip above its
+// try-it form that fills in the parameters and request body, e.g.
+//
+//	{Name: "expired card", Params: map[string]string{"id": "42"}, Body: Payment{Card: "4000000000000069"}}
+type RequestPreset struct {
+	Name string `json:"name"`
+	// Params holds the values of the route's parameters by name; the
+	// parameters it leaves out are cleared
+	Params map[string]string `json:"params,omitempty"`
+	// Body is the request body, encoded as JSON; nil keeps the form's body
+	Body interface{} `json:"body,omitempty"`
+}
+
+// validatePresets checks that presets have unique names, set documented
+// parameters other than files and only send a body to routes taking one
+func validatePresets(presets []RequestPreset, endpoint *Endpoint) error {
+	names := make(map[string]bool)
+	for i, preset := range presets {
+		name := strings.TrimSpace(preset.Name)
+		if name == "" {
+			return fmt.Errorf("preset %d: Name is required", i)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate preset %q", name)
+		}
+		names[name] = true
+		for param := range preset.Params {
+			found := false
+			for _, declared := range endpoint.Parameters {
+				if declared.Name == param {
+					if declared.Type == "file" {
+						return fmt.Errorf("preset %q: file parameter %q cannot be preset", name, param)
+					}
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("preset %q: unknown parameter %q", name, param)
+			}
+		}
+		if preset.Body != nil {
+			if endpoint.RequestSchema == nil {
+				return fmt.Errorf("preset %q: the route takes no request body", name)
+			}
+			if _, err := json.Marshal(preset.Body); err != nil {
+				return fmt.Errorf("preset %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderPresets renders the preset chips above an endpoint's try-it form
+func renderPresets(presets []RequestPreset) string {
+	if len(presets) == 0 {
+		return ""
+	}
+
+	var html strings.Builder
+	html.WriteString(`
+                        <div class="try-it-presets">`)
+	for _, preset := range presets {
+		data, err := json.Marshal(preset)
+		if err != nil {
+			continue
+		}
+		html.WriteString(`
+                            <button type="button" class="preset-chip" data-preset="` + escapeHTML(string(data)) + `" onclick="applyPreset(this)">` + escapeHTML(preset.Name) + `</button>`)
+	}
+	html.WriteString(`
+                        </div>`)
+	return html.String()
+}
+
+// presetsScript fills a try-it form from a preset chip
+const presetsScript = `
+            function applyPreset(chip) {
+                const form = chip.closest('.api-test').querySelector('form');
+                const preset = JSON.parse(chip.dataset.preset);
+                const params = preset.params || {};
+                form.querySelectorAll('input[data-in]').forEach(input => {
+                    if (input.type !== 'file') {
+                        input.value = params[input.name] !== undefined ? params[input.name] : '';
+                    }
+                });
+                const textarea = form.querySelector('textarea[name="requestBody"]');
+                if (textarea && preset.body !== undefined) {
+                    const body = JSON.stringify(preset.body, null, 2);
+                    const editor = codeMirrorEditors[textarea.getAttribute('data-editor-id')];
+                    if (editor) {
+                        editor.setValue(body);
+                    } else {
+                        textarea.value = body;
+                    }
+                }
+                chip.parentElement.querySelectorAll('.preset-chip').forEach(other => {
+                    other.classList.toggle('active', other === chip);
+                });
+            }`
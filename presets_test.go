@@ -0,0 +1,59 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestRequestPresets tests validating and rendering try-it presets
+func TestRequestPresets(t *testing.T) {
+	type payment struct {
+		Card string `json:"card"`
+	}
+	handler := func(c fiber.Ctx) error { return nil }
+	params := []Parameter{{Name: "id", In: "path", Type: "string", Required: true}, {Name: "receipt", In: "formData", Type: "file"}}
+	tests := []struct {
+		name    string
+		schema  interface{}
+		presets []RequestPreset
+		err     string
+	}{
+		{name: "valid", schema: payment{}, presets: []RequestPreset{
+			{Name: "admin user", Params: map[string]string{"id": "1"}},
+			{Name: "expired card", Params: map[string]string{"id": "42"}, Body: payment{Card: "4000000000000069"}},
+		}},
+		{name: "unnamed", presets: []RequestPreset{{Params: map[string]string{"id": "1"}}}, err: "Name is required"},
+		{name: "duplicate", presets: []RequestPreset{{Name: "admin"}, {Name: "admin"}}, err: `duplicate preset "admin"`},
+		{name: "unknown parameter", presets: []RequestPreset{{Name: "admin", Params: map[string]string{"role": "admin"}}}, err: `unknown parameter "role"`},
+		{name: "file parameter", presets: []RequestPreset{{Name: "admin", Params: map[string]string{"receipt": "a.pdf"}}}, err: `file parameter "receipt"`},
+		{name: "body without schema", presets: []RequestPreset{{Name: "admin", Body: payment{}}}, err: "takes no request body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API"}, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{Method: "POST", Path: "/payments/:id", Handler: handler, Params: params, SchemasRequest: tt.schema, Presets: tt.presets})
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+			html := api.generateHTML()
+			for _, snippet := range []string{
+				`<div class="try-it-presets">`,
+				`data-preset="{&quot;name&quot;:&quot;expired card&quot;,&quot;params&quot;:{&quot;id&quot;:&quot;42&quot;},&quot;body&quot;:{&quot;card&quot;:&quot;4000000000000069&quot;}}" onclick="applyPreset(this)">expired card</button>`,
+				`onclick="applyPreset(this)">admin user</button>`,
+			} {
+				if !strings.Contains(html, snippet) {
+					t.Errorf("Expected the docs to contain %q", snippet)
+				}
+			}
+		})
+	}
+}
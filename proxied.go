@@ -0,0 +1,234 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v3/log"
+	"gopkg.in/yaml.v3"
+)
+
+// ProxiedService attaches the OpenAPI document of the service a route proxies
+// to. The document's operations for the route's method are documented under
+// the route's prefix in place of the route itself, marked with x-proxied, so
+// consumers find the upstream API on the same docs portal.
+type ProxiedService struct {
+	// Name identifies the upstream service, e.g. "billing"
+	Name string
+	// OpenAPI is the service's OpenAPI document or fragment, JSON or YAML; only
+	// its paths and component schemas are used
+	OpenAPI []byte
+	// Prefix is prepended to the document's paths. Defaults to the route path
+	// up to its first parameter or wildcard, e.g. /billing for /billing/*.
+	Prefix string
+
+	paths   map[string]PathItem
+	schemas map[string]*JSONSchema
+}
+
+// proxiedFragment is the part of an upstream OpenAPI document embedded in the spec
+type proxiedFragment struct {
+	Paths      map[string]PathItem `json:"paths"`
+	Components *Components         `json:"components"`
+}
+
+// fragmentPathParam matches the {name} parameters of OpenAPI paths
+var fragmentPathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// proxiedService parses the OpenAPI document attached to a route
+func proxiedService(input *DocumentedRouteInput, routePath string) (*ProxiedService, error) {
+	service := *input.Proxy
+	if service.Name == "" {
+		return nil, fmt.Errorf("proxied service of %s %s: Name is required", input.Method, input.Path)
+	}
+
+	data, err := fragmentJSON(service.OpenAPI)
+	if err != nil {
+		return nil, fmt.Errorf("proxied service %s: invalid OpenAPI document: %w", service.Name, err)
+	}
+	var fragment proxiedFragment
+	if err := json.Unmarshal(data, &fragment); err != nil {
+		return nil, fmt.Errorf("proxied service %s: invalid OpenAPI document: %w", service.Name, err)
+	}
+	if len(fragment.Paths) == 0 {
+		return nil, fmt.Errorf("proxied service %s: the OpenAPI document has no paths", service.Name)
+	}
+
+	if service.Prefix == "" {
+		service.Prefix = routePrefix(routePath)
+	}
+	service.Prefix = strings.TrimRight(service.Prefix, "/")
+	service.paths = fragment.Paths
+	if fragment.Components != nil {
+		service.schemas = fragment.Components.Schemas
+	}
+	return &service, nil
+}
+
+// fragmentJSON converts an OpenAPI document in JSON or YAML to JSON
+func fragmentJSON(document []byte) ([]byte, error) {
+	if json.Valid(document) {
+		return document, nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(document, &node); err != nil {
+		return nil, err
+	}
+	value, err := yamlNodeValue(&node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// yamlNodeValue converts a YAML node to JSON-compatible values, keeping
+// mapping keys such as response codes as strings
+func yamlNodeValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlNodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlNodeValue(node.Alias)
+	case yaml.MappingNode:
+		mapping := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := yamlNodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			mapping[node.Content[i].Value] = value
+		}
+		return mapping, nil
+	case yaml.SequenceNode:
+		sequence := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := yamlNodeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+		}
+		return sequence, nil
+	default:
+		var value interface{}
+		err := node.Decode(&value)
+		return value, err
+	}
+}
+
+// routePrefix returns a route path up to its first parameter or wildcard
+func routePrefix(path string) string {
+	if i := strings.IndexAny(path, ":*+"); i >= 0 {
+		path = path[:i]
+	}
+	return strings.TrimRight(path, "/")
+}
+
+// docsEndpoints returns the endpoints shown in the docs and spec, with proxied
+// routes replaced by the upstream operations for their method
+func (an *ApiNote) docsEndpoints() []Endpoint {
+	endpoints := make([]Endpoint, 0, len(an.endpoints))
+	for _, endpoint := range an.endpoints {
+		if endpoint.Proxy == nil {
+			endpoints = append(endpoints, endpoint)
+			continue
+		}
+		endpoints = append(endpoints, an.proxiedEndpoints(&endpoint)...)
+	}
+	return endpoints
+}
+
+// proxiedEndpoints describes the upstream operations of a proxied route as endpoints
+func (an *ApiNote) proxiedEndpoints(route *Endpoint) []Endpoint {
+	service := route.Proxy
+	var paths []string
+	for path := range service.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var endpoints []Endpoint
+	for _, path := range paths {
+		pathItem := service.paths[path]
+		upstream := pathItem.operation(route.Method)
+		if upstream == nil {
+			continue
+		}
+		operation := *upstream
+		operation.Tags = append([]string(nil), operation.Tags...)
+		if len(operation.Tags) == 0 {
+			operation.Tags = []string{service.Name}
+		}
+		operation.Extensions = copyExtensions(operation.Extensions)
+		if operation.Extensions == nil {
+			operation.Extensions = make(map[string]interface{})
+		}
+		operation.Extensions["x-proxied"] = service.Name
+		if operation.OperationID != "" {
+			operation.OperationID = identifierName(service.Name) + toTitle(operation.OperationID)
+		}
+
+		// Docs and try-it use Fiber-style paths
+		routePath := service.Prefix + fragmentPathParam.ReplaceAllString(path, ":$1")
+		endpoint := *route
+		endpoint.Path = an.config.BasePath + routePath
+		endpoint.RoutePath = routePath
+		endpoint.Description = operation.Summary
+		if endpoint.Description == "" {
+			endpoint.Description = operation.Description
+		}
+		endpoint.Tags = operation.Tags
+		endpoint.RequestSchema, endpoint.ResponseSchema = nil, nil
+		endpoint.Parameters = nil
+		for _, param := range operation.Parameters {
+			paramType := "string"
+			if param.Schema != nil && param.Schema.Type != "" {
+				paramType = param.Schema.Type
+			}
+			endpoint.Parameters = append(endpoint.Parameters, Parameter{
+				Name: param.Name, In: param.In, Type: paramType, Description: param.Description, Required: param.Required,
+			})
+		}
+		endpoint.Responses = make(map[string]string, len(operation.Responses))
+		for status, response := range operation.Responses {
+			endpoint.Responses[status] = response.Description
+		}
+		endpoint.ProxiedOperation = &operation
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// addProxiedSchemas adds the component schemas of the proxied services to the
+// spec; names already taken are kept and reported
+func (an *ApiNote) addProxiedSchemas(componentSchemas map[string]*JSONSchema) {
+	for _, endpoint := range an.sortedEndpoints() {
+		if endpoint.Proxy == nil {
+			continue
+		}
+		for name, schema := range endpoint.Proxy.schemas {
+			if existing, ok := componentSchemas[name]; ok {
+				if !reflect.DeepEqual(existing, schema) {
+					log.Warnf("notelink: schema %s of proxied service %s clashes with an existing component, keeping the existing one", name, endpoint.Proxy.Name)
+				}
+				continue
+			}
+			componentSchemas[name] = schema
+		}
+	}
+}
+
+// renderProxiedBadge renders the badge of an upstream operation
+func renderProxiedBadge(endpoint *Endpoint) string {
+	if endpoint.ProxiedOperation == nil {
+		return ""
+	}
+	return `<span class="proxied-badge" title="Proxied to the ` + escapeHTML(endpoint.Proxy.Name) + ` service"><i class="fas fa-right-left"></i> ` + escapeHTML(endpoint.Proxy.Name) + `</span>`
+}
@@ -0,0 +1,107 @@
+package notelink
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const billingOpenAPI = `
+openapi: 3.1.0
+paths:
+  /invoices/{id}:
+    get:
+      operationId: getInvoice
+      summary: Get an invoice
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        200:
+          description: The invoice
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Invoice'
+        404:
+          description: No such invoice
+  /invoices:
+    post:
+      summary: Create an invoice
+      responses:
+        201:
+          description: Invoice created
+components:
+  schemas:
+    Invoice:
+      type: object
+      properties:
+        total:
+          type: number
+`
+
+// TestProxiedService tests documenting the upstream operations of proxied routes
+func TestProxiedService(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	proxy := func(c fiber.Ctx) error { return c.SendString("proxied " + c.Params("*")) }
+	for _, method := range []string{"GET", "POST"} {
+		err := api.DocumentedRoute(&DocumentedRouteInput{
+			Method:  method,
+			Path:    "/billing/*",
+			Proxy:   &ProxiedService{Name: "billing", OpenAPI: []byte(billingOpenAPI)},
+			Handler: proxy,
+		})
+		if err != nil {
+			t.Fatalf("Failed to register proxied route: %v", err)
+		}
+	}
+
+	invalid := []*ProxiedService{
+		{OpenAPI: []byte(billingOpenAPI)},
+		{Name: "empty", OpenAPI: []byte(`{"openapi": "3.1.0"}`)},
+		{Name: "broken", OpenAPI: []byte("paths: [")},
+	}
+	for _, service := range invalid {
+		input := DocumentedRouteInput{Method: "GET", Path: "/other/*", Proxy: service, Handler: proxy}
+		if err := api.DocumentedRoute(&input); err == nil {
+			t.Errorf("Expected an error for %+v", service)
+		}
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if _, ok := spec.Paths["/billing/{wildcard}"]; ok {
+		t.Error("Expected the proxied route to be replaced by the upstream operations")
+	}
+	get := spec.Paths["/billing/invoices/{id}"].Get
+	if get == nil || get.OperationID != "BillingGetInvoice" || get.Extensions["x-proxied"] != "billing" {
+		t.Fatalf("Expected the upstream GET operation, got %+v", get)
+	}
+	if get.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/Invoice" {
+		t.Errorf("Expected the upstream response schema, got %+v", get.Responses["200"])
+	}
+	if post := spec.Paths["/billing/invoices"].Post; post == nil || post.Tags[0] != "billing" {
+		t.Errorf("Expected the upstream POST operation tagged with the service, got %+v", post)
+	}
+	if spec.Paths["/billing/invoices"].Get != nil {
+		t.Error("Expected only operations of the route's method")
+	}
+	if _, ok := spec.Components.Schemas["Invoice"]; !ok {
+		t.Error("Expected the upstream component schemas")
+	}
+	data, err := json.Marshal(spec)
+	if err != nil || !strings.Contains(string(data), `"x-proxied":"billing"`) {
+		t.Errorf("Expected x-proxied in the serialized spec (%v)", err)
+	}
+
+	html := api.generateHTML()
+	for _, want := range []string{"/billing/invoices/:id", "Get an invoice", `class="proxied-badge"`, "404: No such invoice"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the docs to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package notelink
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// forwardedHost matches the host[:port] values accepted from X-Forwarded-Host
+var forwardedHost = regexp.MustCompile(`^[A-Za-z0-9.-]+(?::\d+)?$|^\[[0-9A-Fa-f:.]+\](?::\d+)?$`)
+
+// baseURL returns the scheme and host the API is reached at, used by the try-it
+// client and the spec's servers. It defaults to http:// and Config.Host; docs
+// served over TLS use https, and with Config.TrustProxyHeaders the
+// X-Forwarded-Proto and X-Forwarded-Host headers set by a reverse proxy win.
+// Rendered docs and specs embed it, so they are cached per base URL: without
+// Config.Host or with TrustProxyHeaders, per requested or forwarded host.
+// c may be nil when there is no request, e.g. when exporting the spec.
+func (an *ApiNote) baseURL(c fiber.Ctx) string {
+	scheme, host := "http", an.config.Host
+	if c != nil {
+		if c.RequestCtx().IsTLS() {
+			scheme = "https"
+		}
+		if host == "" {
+			host = c.Host()
+		}
+		if an.config.TrustProxyHeaders {
+			if proto := strings.ToLower(firstForwardedValue(c.Get(fiber.HeaderXForwardedProto))); proto == "http" || proto == "https" {
+				scheme = proto
+			}
+			if forwarded := firstForwardedValue(c.Get(fiber.HeaderXForwardedHost)); forwardedHost.MatchString(forwarded) {
+				host = forwarded
+			}
+		}
+	}
+	return scheme + "://" + host
+}
+
+// firstForwardedValue returns the first entry of a comma-separated forwarded
+// header, which describes the request as the client made it
+func firstForwardedValue(header string) string {
+	value, _, _ := strings.Cut(header, ",")
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,135 @@
+package notelink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/proxy"
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultProxyTimeout bounds upstream requests of DocumentedProxy routes
+const DefaultProxyTimeout = 30 * time.Second
+
+// proxyForwardedHeaders are the headers DocumentedProxy sets on upstream requests
+var proxyForwardedHeaders = []string{fiber.HeaderXForwardedFor, fiber.HeaderXForwardedHost, fiber.HeaderXForwardedProto}
+
+// ProxyOptions configures a route registered with DocumentedProxy
+type ProxyOptions struct {
+	Description  string
+	Params       []Parameter
+	Responses    map[string]string
+	AuthRequired *bool
+	Tags         []string
+	// Timeout bounds the upstream request; slower upstreams get 504 Gateway
+	// Timeout. Defaults to DefaultProxyTimeout.
+	Timeout time.Duration
+	// SetHeaders sets request headers on upstream requests, e.g. an internal API
+	// key. They are documented by name only.
+	SetHeaders map[string]string
+	// Client sends the upstream requests. Defaults to a client streaming
+	// response bodies.
+	Client *fasthttp.Client
+	// Service attaches the upstream OpenAPI document, documenting its
+	// operations in place of the proxy route (see ProxiedService)
+	Service *ProxiedService
+}
+
+// DocumentedProxy registers a reverse-proxy route forwarding requests to
+// upstreamURL and documents it. Requests keep their method, headers, body and
+// query string; the wildcard of the path (e.g. /billing/*) is appended to the
+// upstream URL. X-Forwarded-For, X-Forwarded-Host and X-Forwarded-Proto are set
+// on upstream requests and responses are streamed back unchanged. Unreachable
+// upstreams answer 502 Bad Gateway and slow ones 504 Gateway Timeout.
+func (an *ApiNote) DocumentedProxy(method, path, upstreamURL string, opts ProxyOptions) error {
+	upstream, err := url.Parse(upstreamURL)
+	if err != nil || (upstream.Scheme != "http" && upstream.Scheme != "https") || upstream.Host == "" {
+		return fmt.Errorf("proxy %s %s: invalid upstream URL %q", method, path, upstreamURL)
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultProxyTimeout
+	}
+	client := opts.Client
+	if client == nil {
+		client = &fasthttp.Client{StreamResponseBody: true, NoDefaultUserAgentHeader: true, DisablePathNormalizing: true}
+	}
+
+	responses := copyResponses(opts.Responses)
+	if responses == nil {
+		responses = make(map[string]string)
+	}
+	if _, ok := responses["502"]; !ok {
+		responses["502"] = "The upstream service is unreachable"
+	}
+	if _, ok := responses["504"]; !ok {
+		responses["504"] = "The upstream service didn't answer within " + timeout.String()
+	}
+
+	return an.DocumentedRoute(&DocumentedRouteInput{
+		Method:       method,
+		Path:         path,
+		Description:  appendDescription(opts.Description, proxyDescription(upstream, timeout, opts.SetHeaders)),
+		Params:       opts.Params,
+		Responses:    responses,
+		AuthRequired: opts.AuthRequired,
+		Tags:         opts.Tags,
+		Proxy:        opts.Service,
+		Handler:      proxyHandler(strings.TrimRight(upstreamURL, "/"), timeout, opts.SetHeaders, client),
+	})
+}
+
+// proxyHandler forwards requests to the upstream
+func proxyHandler(upstream string, timeout time.Duration, setHeaders map[string]string, client *fasthttp.Client) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		target := upstream
+		if wildcard := c.Params("*"); wildcard != "" {
+			target += "/" + strings.TrimLeft(wildcard, "/")
+		}
+		if query := c.Request().URI().QueryString(); len(query) > 0 {
+			target += "?" + string(query)
+		}
+
+		forwardedFor := c.IP()
+		if previous := c.Get(fiber.HeaderXForwardedFor); previous != "" {
+			forwardedFor = previous + ", " + forwardedFor
+		}
+		c.Request().Header.Set(fiber.HeaderXForwardedFor, forwardedFor)
+		c.Request().Header.Set(fiber.HeaderXForwardedHost, c.Host())
+		c.Request().Header.Set(fiber.HeaderXForwardedProto, c.Scheme())
+		for name, value := range setHeaders {
+			c.Request().Header.Set(name, value)
+		}
+
+		err := proxy.DoTimeout(c, target, timeout, client)
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, fasthttp.ErrTimeout) || errors.Is(err, fasthttp.ErrDialTimeout):
+			return fiber.NewError(fiber.StatusGatewayTimeout, "Upstream timed out")
+		default:
+			return fiber.NewError(fiber.StatusBadGateway, "Upstream unreachable")
+		}
+	}
+}
+
+// proxyDescription documents the forwarding of a proxy route
+func proxyDescription(upstream *url.URL, timeout time.Duration, setHeaders map[string]string) string {
+	headers := append([]string(nil), proxyForwardedHeaders...)
+	var set []string
+	for name := range setHeaders {
+		set = append(set, name)
+	}
+	sort.Strings(set)
+	headers = append(headers, set...)
+
+	return "Proxied to " + upstream.Scheme + "://" + upstream.Host + upstream.Path + ". " +
+		"The method, headers, body and query string are forwarded with " + strings.Join(headers, ", ") +
+		" set, and the upstream response is streamed back unchanged. " +
+		"Requests time out after " + timeout.String() + " with 504 Gateway Timeout; unreachable upstreams answer 502 Bad Gateway."
+}
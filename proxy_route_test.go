@@ -0,0 +1,83 @@
+package notelink
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDocumentedProxy tests forwarding requests to an upstream and documenting the proxy
+func TestDocumentedProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("X-Upstream-Path", r.URL.RequestURI())
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, r.Method+" "+r.Header.Get("X-Forwarded-Host")+" "+r.Header.Get("X-Api-Key"))
+	}))
+	defer upstream.Close()
+
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	opts := ProxyOptions{Description: "Billing API", Timeout: 50 * time.Millisecond, SetHeaders: map[string]string{"X-Api-Key": "internal"}}
+	if err := api.DocumentedProxy("POST", "/billing/*", upstream.URL+"/v2", opts); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+	if err := api.DocumentedProxy("GET", "/slow", upstream.URL+"/slow", opts); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+	if err := api.DocumentedProxy("GET", "/down", "http://127.0.0.1:1", ProxyOptions{}); err != nil {
+		t.Fatalf("Failed to register proxy: %v", err)
+	}
+	if err := api.DocumentedProxy("GET", "/invalid", "ftp://example.com", ProxyOptions{}); err == nil {
+		t.Error("Expected an error for a non-HTTP upstream")
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+		body   string
+		header string
+	}{
+		{"forwarded", "POST", "/billing/invoices/1?expand=lines", http.StatusCreated, "POST example.com internal", "/v2/invoices/1?expand=lines"},
+		{"timeout", "GET", "/slow", http.StatusGatewayTimeout, "", ""},
+		{"unreachable", "GET", "/down", http.StatusBadGateway, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := api.Fiber().Test(httptest.NewRequest(tt.method, tt.path, strings.NewReader("{}")))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, resp.StatusCode, body)
+			}
+			if tt.body != "" && string(body) != tt.body {
+				t.Errorf("Expected body %q, got %q", tt.body, body)
+			}
+			if got := resp.Header.Get("X-Upstream-Path"); got != tt.header {
+				t.Errorf("Expected upstream path %q, got %q", tt.header, got)
+			}
+		})
+	}
+
+	operation := api.GenerateOpenAPISpec().Paths["/billing/{wildcard}"].Post
+	if operation == nil {
+		t.Fatal("Expected the proxy route to be documented")
+	}
+	for _, want := range []string{"Billing API", "Proxied to http://127.0.0.1", "X-Forwarded-For, X-Forwarded-Host, X-Forwarded-Proto, X-Api-Key", "time out after 50ms"} {
+		if !strings.Contains(operation.Description, want) {
+			t.Errorf("Expected the description to contain %q, got %q", want, operation.Description)
+		}
+	}
+	if _, ok := operation.Responses["504"]; !ok {
+		t.Error("Expected the documented 504 response")
+	}
+}
@@ -0,0 +1,151 @@
+package notelink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// ArtifactStore receives the published docs artifacts, e.g. an S3 or GCS
+// bucket, so other systems consume the spec, types and docs without calling
+// the service. Adapting an SDK client takes a few lines:
+//
+//	store := notelink.ArtifactStoreFunc(func(ctx context.Context, name, contentType string, data []byte) error {
+//	    _, err := s3Client.PutObject(ctx, &s3.PutObjectInput{
+//	        Bucket:      aws.String("api-docs"),
+//	        Key:         aws.String("orders/" + name),
+//	        Body:        bytes.NewReader(data),
+//	        ContentType: aws.String(contentType),
+//	    })
+//	    return err
+//	})
+type ArtifactStore interface {
+	// Put stores an artifact under name, a slash-separated path such as
+	// "openapi.json" or "print/index.html"
+	Put(ctx context.Context, name, contentType string, data []byte) error
+}
+
+// ArtifactStoreFunc adapts a function to an ArtifactStore
+type ArtifactStoreFunc func(ctx context.Context, name, contentType string, data []byte) error
+
+// Put calls f(ctx, name, contentType, data)
+func (f ArtifactStoreFunc) Put(ctx context.Context, name, contentType string, data []byte) error {
+	return f(ctx, name, contentType, data)
+}
+
+// DirArtifactStore writes artifacts into a directory, e.g. one synced to a
+// bucket or served by a CDN
+type DirArtifactStore string
+
+// Put writes the artifact to the directory
+func (d DirArtifactStore) Put(_ context.Context, name, _ string, data []byte) error {
+	target := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(target, data, 0o644)
+}
+
+// HTTPArtifactStore uploads artifacts with PUT requests to BaseURL + "/" +
+// name, as S3- and GCS-compatible endpoints, WebDAV servers and artifact
+// repositories accept them. Authenticate with Header or a Client whose
+// transport signs the requests.
+type HTTPArtifactStore struct {
+	BaseURL string
+	Header  http.Header
+	Client  *http.Client // Defaults to a client with a 30s timeout
+}
+
+// Put uploads the artifact, failing on non-2xx responses
+func (s *HTTPArtifactStore) Put(ctx context.Context, name, contentType string, data []byte) error {
+	return upload(ctx, s.Client, http.MethodPut, strings.TrimRight(s.BaseURL, "/")+"/"+name, s.Header, contentType, data)
+}
+
+// upload sends data with the given method, failing on non-2xx responses. A nil
+// client defaults to one with a 30s timeout.
+func upload(ctx context.Context, client *http.Client, method, url string, header http.Header, contentType string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set(fiber.HeaderContentType, contentType)
+
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// PublishArtifacts renders the docs artifacts and puts them into store:
+// openapi.json, openapi.yaml, postman.json and types.ts, and the static docs
+// bundle (index.html, print/index.html and the other pages of Snapshot),
+// and the files of the registered exporters. The names match WriteSnapshot,
+// so a published bundle can also be served with Config.DocsSnapshot.
+// Publishing stops at the first failing artifact.
+func (an *ApiNote) PublishArtifacts(ctx context.Context, store ArtifactStore) error {
+	files, err := an.snapshotFiles()
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := store.Put(ctx, file.Name, file.ContentType, file.Data); err != nil {
+			return fmt.Errorf("publishing %s: %w", file.Name, err)
+		}
+	}
+	return nil
+}
+
+// mountPublishing runs the configured publishing once the app listens, after
+// the routes are registered: the schemas are checked for compatibility, the
+// spec changes are announced while the previously published spec is still
+// in place, then the artifacts and spec are published
+func (an *ApiNote) mountPublishing() {
+	an.app.Hooks().OnListen(func(fiber.ListenData) error {
+		go func() {
+			ctx := context.Background()
+			if an.config.SchemaCompatibility != nil {
+				if _, err := an.CheckSchemaCompatibility(ctx, an.config.SchemaCompatibility); err != nil {
+					log.Errorf("notelink: not publishing: %v", err)
+					return
+				}
+			}
+			if an.config.SpecChangeNotifier != nil {
+				if _, err := an.NotifySpecChanges(ctx, an.config.SpecChangeNotifier); err != nil {
+					log.Errorf("notelink: %v", err)
+				}
+			}
+			if an.config.PublishArtifacts != nil {
+				if err := an.PublishArtifacts(ctx, an.config.PublishArtifacts); err != nil {
+					log.Errorf("notelink: %v", err)
+				}
+			}
+			if len(an.config.SpecRegistries) > 0 {
+				if err := an.PublishSpec(ctx, an.config.SpecRegistries...); err != nil {
+					log.Errorf("notelink: publishing the spec: %v", err)
+				}
+			}
+		}()
+		return nil
+	})
+}
@@ -0,0 +1,93 @@
+package notelink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestPublishArtifacts tests pushing the docs artifacts to stores
+func TestPublishArtifacts(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "api.example.com"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/orders",
+		Description: "List orders",
+		Handler:     func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	t.Run("http", func(t *testing.T) {
+		var mu sync.Mutex
+		uploads := map[string]string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut || r.Header.Get("Authorization") != "Bearer upload" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			uploads[r.URL.Path] = r.Header.Get("Content-Type") + " " + string(body)
+			mu.Unlock()
+		}))
+		defer server.Close()
+
+		store := &HTTPArtifactStore{BaseURL: server.URL + "/bucket/", Header: http.Header{"Authorization": {"Bearer upload"}}}
+		if err := api.PublishArtifacts(context.Background(), store); err != nil {
+			t.Fatalf("Failed to publish: %v", err)
+		}
+		want := map[string]string{
+			"/bucket/openapi.json":     "application/json",
+			"/bucket/openapi.yaml":     "application/yaml",
+			"/bucket/types.ts":         "application/typescript",
+			"/bucket/index.html":       "text/html",
+			"/bucket/print/index.html": "text/html",
+		}
+		for name, contentType := range want {
+			if !strings.HasPrefix(uploads[name], contentType) {
+				t.Errorf("Expected %s uploaded as %s, got %.60q", name, contentType, uploads[name])
+			}
+		}
+		if !strings.Contains(uploads["/bucket/openapi.json"], `"/orders"`) {
+			t.Error("Expected the published spec to list the routes")
+		}
+
+		forbidden := &HTTPArtifactStore{BaseURL: server.URL}
+		if err := api.PublishArtifacts(context.Background(), forbidden); err == nil || !strings.Contains(err.Error(), "403") {
+			t.Errorf("Expected the upload status in the error, got %v", err)
+		}
+	})
+
+	t.Run("dir", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := api.PublishArtifacts(context.Background(), DirArtifactStore(dir)); err != nil {
+			t.Fatalf("Failed to publish: %v", err)
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, "print", "index.html")); err != nil || !strings.Contains(string(data), "List orders") {
+			t.Errorf("Expected the print view in the directory, got %v", err)
+		}
+	})
+
+	t.Run("failing store", func(t *testing.T) {
+		calls := 0
+		store := ArtifactStoreFunc(func(ctx context.Context, name, contentType string, data []byte) error {
+			calls++
+			return errors.New("bucket unavailable")
+		})
+		err := api.PublishArtifacts(context.Background(), store)
+		if err == nil || !strings.Contains(err.Error(), "bucket unavailable") || calls != 1 {
+			t.Errorf("Expected publishing to stop at the first failure, got %v after %d calls", err, calls)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package notelink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// SpecRegistry receives the OpenAPI spec, e.g. a central API registry such as
+// Apicurio or SwaggerHub
+type SpecRegistry interface {
+	// PublishSpec stores the JSON spec as the given version of the API
+	PublishSpec(ctx context.Context, spec []byte, version string) error
+}
+
+// SpecRegistryFunc adapts a function to a SpecRegistry
+type SpecRegistryFunc func(ctx context.Context, spec []byte, version string) error
+
+// PublishSpec calls f(ctx, spec, version)
+func (f SpecRegistryFunc) PublishSpec(ctx context.Context, spec []byte, version string) error {
+	return f(ctx, spec, version)
+}
+
+// ApicurioRegistry publishes the spec to an Apicurio Registry through its v2
+// REST API, creating the artifact or adding a version to it
+type ApicurioRegistry struct {
+	URL        string // API root, e.g. https://registry.example.com/apis/registry/v2
+	GroupID    string // Defaults to "default"
+	ArtifactID string
+	Version    string      // Overrides the version, Config.Version by default
+	Header     http.Header // e.g. an Authorization header
+	Client     *http.Client
+}
+
+// PublishSpec creates or updates the artifact
+func (r *ApicurioRegistry) PublishSpec(ctx context.Context, spec []byte, version string) error {
+	if r.ArtifactID == "" {
+		return errors.New("apicurio: ArtifactID is required")
+	}
+	group := r.GroupID
+	if group == "" {
+		group = "default"
+	}
+	if r.Version != "" {
+		version = r.Version
+	}
+
+	header := http.Header{}
+	for key, values := range r.Header {
+		header[key] = values
+	}
+	header.Set("X-Registry-ArtifactId", r.ArtifactID)
+	header.Set("X-Registry-ArtifactType", "OPENAPI")
+	if version != "" {
+		header.Set("X-Registry-Version", version)
+	}
+	target := strings.TrimRight(r.URL, "/") + "/groups/" + url.PathEscape(group) + "/artifacts?ifExists=UPDATE"
+	if err := upload(ctx, r.Client, http.MethodPost, target, header, fiber.MIMEApplicationJSON, spec); err != nil {
+		return fmt.Errorf("apicurio: %w", err)
+	}
+	return nil
+}
+
+// DefaultSwaggerHubURL is the API of the hosted SwaggerHub
+const DefaultSwaggerHubURL = "https://api.swaggerhub.com"
+
+// SwaggerHubRegistry publishes the spec to SwaggerHub as a version of
+// Owner/API, creating the API on first publish
+type SwaggerHubRegistry struct {
+	URL     string // Defaults to DefaultSwaggerHubURL; set for SwaggerHub On-Premise
+	Owner   string
+	API     string
+	APIKey  string
+	Version string // Overrides the version, Config.Version by default
+	Private bool
+	Client  *http.Client
+}
+
+// PublishSpec saves the spec as the version of the API
+func (r *SwaggerHubRegistry) PublishSpec(ctx context.Context, spec []byte, version string) error {
+	if r.Owner == "" || r.API == "" {
+		return errors.New("swaggerhub: Owner and API are required")
+	}
+	base := r.URL
+	if base == "" {
+		base = DefaultSwaggerHubURL
+	}
+	if r.Version != "" {
+		version = r.Version
+	}
+
+	query := url.Values{"isPrivate": {strconv.FormatBool(r.Private)}}
+	if version != "" {
+		query.Set("version", version)
+	}
+	target := strings.TrimRight(base, "/") + "/apis/" + url.PathEscape(r.Owner) + "/" + url.PathEscape(r.API) + "?" + query.Encode()
+	header := http.Header{}
+	if r.APIKey != "" {
+		header.Set(fiber.HeaderAuthorization, r.APIKey)
+	}
+	if err := upload(ctx, r.Client, http.MethodPost, target, header, fiber.MIMEApplicationJSON, spec); err != nil {
+		return fmt.Errorf("swaggerhub: %w", err)
+	}
+	return nil
+}
+
+// PublishSpec renders the OpenAPI spec in the default locale and publishes it
+// to the registries as Config.Version. Every registry is tried; the errors of
+// the failing ones are joined. Set Host so the spec names the public server.
+func (an *ApiNote) PublishSpec(ctx context.Context, registries ...SpecRegistry) error {
+	spec, err := an.cachedOpenAPISpec(an.baseURL(nil), an.docsLocale(), false)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, registry := range registries {
+		if err := registry.PublishSpec(ctx, spec, an.config.Version); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
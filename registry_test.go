@@ -0,0 +1,147 @@
+package notelink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// registryRequest records a request received by a test registry
+type registryRequest struct {
+	method string
+	url    string
+	header http.Header
+	body   string
+}
+
+func newTestRegistryServer(t *testing.T, status int) (*httptest.Server, func() []registryRequest) {
+	var mu sync.Mutex
+	var requests []registryRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, registryRequest{r.Method, r.URL.String(), r.Header, string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte(`{"message":"rejected"}`)) //nolint:errcheck // test server
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []registryRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]registryRequest(nil), requests...)
+	}
+}
+
+// TestPublishSpec tests publishing the spec to schema registries
+func TestPublishSpec(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Version: "2.1.0", Host: "api.example.com"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/orders",
+		Description: "List orders",
+		Handler:     func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	t.Run("apicurio", func(t *testing.T) {
+		server, requests := newTestRegistryServer(t, http.StatusOK)
+		registry := &ApicurioRegistry{
+			URL:        server.URL + "/apis/registry/v2/",
+			GroupID:    "payments",
+			ArtifactID: "orders-api",
+			Header:     http.Header{"Authorization": {"Bearer token"}},
+		}
+		if err := api.PublishSpec(context.Background(), registry); err != nil {
+			t.Fatalf("Failed to publish: %v", err)
+		}
+		got := requests()
+		if len(got) != 1 {
+			t.Fatalf("Expected 1 request, got %d", len(got))
+		}
+		req := got[0]
+		if req.method != "POST" || req.url != "/apis/registry/v2/groups/payments/artifacts?ifExists=UPDATE" {
+			t.Errorf("Unexpected request %s %s", req.method, req.url)
+		}
+		for header, want := range map[string]string{
+			"Authorization":           "Bearer token",
+			"X-Registry-ArtifactId":   "orders-api",
+			"X-Registry-ArtifactType": "OPENAPI",
+			"X-Registry-Version":      "2.1.0",
+			"Content-Type":            "application/json",
+		} {
+			if req.header.Get(header) != want {
+				t.Errorf("Expected %s %q, got %q", header, want, req.header.Get(header))
+			}
+		}
+		if !strings.Contains(req.body, `"/orders"`) || !strings.Contains(req.body, "http://api.example.com") {
+			t.Errorf("Expected the spec in the body, got %.80s", req.body)
+		}
+	})
+
+	t.Run("swaggerhub", func(t *testing.T) {
+		server, requests := newTestRegistryServer(t, http.StatusCreated)
+		registry := &SwaggerHubRegistry{URL: server.URL, Owner: "acme", API: "orders", APIKey: "key", Version: "2.1.0-rc1"}
+		if err := api.PublishSpec(context.Background(), registry); err != nil {
+			t.Fatalf("Failed to publish: %v", err)
+		}
+		req := requests()[0]
+		if req.method != "POST" || req.url != "/apis/acme/orders?isPrivate=false&version=2.1.0-rc1" {
+			t.Errorf("Unexpected request %s %s", req.method, req.url)
+		}
+		if req.header.Get("Authorization") != "key" {
+			t.Errorf("Expected the API key, got %q", req.header.Get("Authorization"))
+		}
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		server, _ := newTestRegistryServer(t, http.StatusConflict)
+		published := false
+		err := api.PublishSpec(context.Background(),
+			&ApicurioRegistry{URL: server.URL, ArtifactID: "orders-api"},
+			&SwaggerHubRegistry{Owner: "acme"},
+			SpecRegistryFunc(func(ctx context.Context, spec []byte, version string) error {
+				published = version == "2.1.0"
+				return nil
+			}),
+		)
+		if !published {
+			t.Error("Expected the remaining registries published after a failure")
+		}
+		if err == nil || !strings.Contains(err.Error(), "apicurio: unexpected status 409: {\"message\":\"rejected\"}") || !strings.Contains(err.Error(), "swaggerhub: Owner and API are required") {
+			t.Errorf("Expected both failures reported, got %v", err)
+		}
+	})
+}
+
+// TestSpecRegistriesOnListen tests publishing the spec once the app listens
+func TestSpecRegistriesOnListen(t *testing.T) {
+	published := make(chan string, 1)
+	registry := SpecRegistryFunc(func(ctx context.Context, spec []byte, version string) error {
+		published <- version
+		return errors.New("ignored")
+	})
+	api := NewApiNote(&Config{Title: "Test API", Version: "3.0.0", Host: "localhost:8080", SpecRegistries: []SpecRegistry{registry}}, "secret")
+	go api.ListenUnix(filepath.Join(t.TempDir(), "notelink.sock")) //nolint:errcheck // stopped by Shutdown
+	defer api.Fiber().Shutdown()                                   //nolint:errcheck // best-effort cleanup
+
+	select {
+	case version := <-published:
+		if version != "3.0.0" {
+			t.Errorf("Expected version 3.0.0, got %q", version)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the spec published after listening")
+	}
+}
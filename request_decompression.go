@@ -0,0 +1,111 @@
+package notelink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// DefaultMaxDecompressedSize bounds decompressed request bodies (10 MiB)
+const DefaultMaxDecompressedSize = 10 << 20
+
+// requestEncodings are the request Content-Encodings accepted with request decompression
+var requestEncodings = []string{"gzip", "identity"}
+
+// RequestDecompressionConfig configures the decompression of request bodies
+type RequestDecompressionConfig struct {
+	// MaxSize bounds the decompressed body in bytes; larger bodies get 413
+	// Payload Too Large. Defaults to DefaultMaxDecompressedSize. The compressed
+	// body is bounded by Fiber's BodyLimit.
+	MaxSize int
+}
+
+// maxSize returns the configured limit, or the default
+func (cfg RequestDecompressionConfig) maxSize() int {
+	if cfg.MaxSize > 0 {
+		return cfg.MaxSize
+	}
+	return DefaultMaxDecompressedSize
+}
+
+// describe documents the accepted request encodings in the spec
+func (cfg RequestDecompressionConfig) describe() string {
+	return fmt.Sprintf("Request bodies may be compressed with Content-Encoding: gzip; they are decompressed before validation. "+
+		"Decompressed bodies are limited to %d bytes and larger ones are rejected with 413 Payload Too Large. "+
+		"Other encodings are rejected with 415 Unsupported Media Type and an Accept-Encoding response header listing the supported ones.", cfg.maxSize())
+}
+
+// RequestDecompressionMiddleware returns a Fiber middleware decompressing
+// gzip request bodies (Content-Encoding: gzip) in place, so later handlers
+// and validation see the plain body. Bodies decompressing beyond MaxSize get
+// 413, corrupt ones 400 and other encodings 415.
+//
+// It is installed automatically on documented routes when
+// Config.RequestDecompression is set or DocumentedRouteInput.DecompressRequest
+// is true.
+func RequestDecompressionMiddleware(cfg RequestDecompressionConfig) fiber.Handler {
+	limit := cfg.maxSize()
+	return func(c fiber.Ctx) error {
+		encoding := strings.ToLower(strings.TrimSpace(string(c.Request().Header.ContentEncoding())))
+		switch encoding {
+		case "", "identity":
+			return c.Next()
+		case "gzip", "x-gzip":
+		default:
+			c.Set(fiber.HeaderAcceptEncoding, strings.Join(requestEncodings, ", "))
+			return fiber.NewError(fiber.StatusUnsupportedMediaType, fmt.Sprintf("Unsupported Content-Encoding '%s'", encoding))
+		}
+
+		reader, err := gzip.NewReader(bytes.NewReader(c.Request().Body()))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid gzip request body")
+		}
+		defer reader.Close()
+		// Read one byte past the limit to detect oversized bodies without
+		// inflating them entirely
+		body, err := io.ReadAll(io.LimitReader(reader, int64(limit)+1))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid gzip request body")
+		}
+		if len(body) > limit {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("Decompressed request body exceeds %d bytes", limit))
+		}
+
+		c.Request().Header.Del(fiber.HeaderContentEncoding)
+		c.Request().SetBodyRaw(body)
+		return c.Next()
+	}
+}
+
+// requestDecompression returns a route's request decompression settings: the
+// route's DecompressRequest override when set, otherwise Config.RequestDecompression
+func (an *ApiNote) requestDecompression(input *DocumentedRouteInput) *RequestDecompressionConfig {
+	if input.DecompressRequest != nil && !*input.DecompressRequest {
+		return nil
+	}
+	if an.config.RequestDecompression != nil {
+		cfg := *an.config.RequestDecompression
+		return &cfg
+	}
+	if input.DecompressRequest != nil {
+		return &RequestDecompressionConfig{}
+	}
+	return nil
+}
+
+// documentRequestDecompression documents the rejections of decompressed routes
+func documentRequestDecompression(endpoint *Endpoint) {
+	if endpoint.Responses == nil {
+		endpoint.Responses = make(map[string]string)
+	}
+	if _, ok := endpoint.Responses["413"]; !ok {
+		endpoint.Responses["413"] = "The decompressed request body is too large"
+	}
+	if _, ok := endpoint.Responses["415"]; !ok {
+		endpoint.Responses["415"] = "Unsupported request Content-Encoding"
+	}
+}
@@ -0,0 +1,100 @@
+package notelink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type telemetryUpload struct {
+	Device  string    `json:"device" validate:"required"`
+	Samples []float64 `json:"samples,omitempty"`
+}
+
+func gzipBody(t *testing.T, body string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		t.Fatalf("Failed to compress body: %v", err)
+	}
+	writer.Close()
+	return buf.Bytes()
+}
+
+// TestRequestDecompression tests accepting gzip request bodies
+func TestRequestDecompression(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title:                "Test API",
+		Host:                 "localhost:8080",
+		RequestDecompression: &RequestDecompressionConfig{MaxSize: 64},
+	}, "secret")
+	routes := []DocumentedRouteInput{
+		{Method: "POST", Path: "/v1/telemetry", SchemasRequest: telemetryUpload{}, Handler: func(c fiber.Ctx) error {
+			return c.Send(c.Body())
+		}},
+		{Method: "POST", Path: "/v1/raw", DecompressRequest: new(bool), Handler: func(c fiber.Ctx) error {
+			return c.SendString(c.Get(fiber.HeaderContentEncoding))
+		}},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		encoding string
+		body     []byte
+		status   int
+		response string
+	}{
+		{"gzip", "/v1/telemetry", "gzip", gzipBody(t, `{"device":"d1","samples":[1,2]}`), 200, `{"device":"d1","samples":[1,2]}`},
+		{"plain", "/v1/telemetry", "", []byte(`{"device":"d1"}`), 200, `{"device":"d1"}`},
+		{"validated after decompression", "/v1/telemetry", "gzip", gzipBody(t, `{"samples":[1]}`), 400, ""},
+		{"too large", "/v1/telemetry", "gzip", gzipBody(t, `{"device":"`+strings.Repeat("x", 100)+`"}`), 413, ""},
+		{"corrupt", "/v1/telemetry", "gzip", []byte("not gzip"), 400, ""},
+		{"unsupported encoding", "/v1/telemetry", "br", []byte("x"), 415, ""},
+		{"disabled per route", "/v1/raw", "br", []byte("x"), 200, "br"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, bytes.NewReader(tt.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			if tt.encoding != "" {
+				req.Header.Set(fiber.HeaderContentEncoding, tt.encoding)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || (tt.response != "" && string(body) != tt.response) {
+				t.Errorf("Expected %d %s, got %d %s", tt.status, tt.response, resp.StatusCode, body)
+			}
+			if tt.status == 415 && resp.Header.Get(fiber.HeaderAcceptEncoding) != "gzip, identity" {
+				t.Errorf("Expected Accept-Encoding listing the supported encodings, got %q", resp.Header.Get(fiber.HeaderAcceptEncoding))
+			}
+		})
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	operation := spec.Paths["/v1/telemetry"].Post
+	if !reflect.DeepEqual(operation.Encodings, []string{"gzip", "identity"}) || !strings.Contains(operation.Description, "Content-Encoding: gzip") {
+		t.Errorf("Expected the accepted encodings to be documented, got %v %q", operation.Encodings, operation.Description)
+	}
+	if _, ok := operation.Responses["413"]; !ok {
+		t.Error("Expected a documented 413 response")
+	}
+	if raw := spec.Paths["/v1/raw"].Post; raw.Encodings != nil {
+		t.Errorf("Expected no encodings on a route opting out, got %v", raw.Encodings)
+	}
+}
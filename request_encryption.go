@@ -0,0 +1,336 @@
+package notelink
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"mime"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// MIMEApplicationJOSE is the media type of compact JWE request bodies
+const MIMEApplicationJOSE = "application/jose"
+
+// Key management algorithms of JWE request bodies
+const (
+	JWEAlgRSAOAEP256 = "RSA-OAEP-256" // Content key wrapped with an RSA key
+	JWEAlgDirect     = "dir"          // Shared content key
+)
+
+// jweEncryptions maps the accepted content encryptions to their key sizes
+var jweEncryptions = map[string]int{"A128GCM": 16, "A192GCM": 24, "A256GCM": 32}
+
+// EncryptionKey decrypts JWE request bodies whose kid header is ID. Set
+// PrivateKey for RSA-OAEP-256 or Secret, a 16, 24 or 32-byte AES key, for
+// direct encryption with the matching AES-GCM encryption.
+type EncryptionKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+	Secret     []byte
+}
+
+// alg returns the key management algorithm of the key
+func (k *EncryptionKey) alg() string {
+	if k.PrivateKey != nil {
+		return JWEAlgRSAOAEP256
+	}
+	return JWEAlgDirect
+}
+
+// RequestEncryptionConfig holds the keys of encrypted request bodies, for
+// routes carrying personal data (see DocumentedRouteInput.EncryptedRequest).
+// Clients send the JSON body as a compact JWE with Content-Type
+// application/jose; its protected header names the key in kid and may name
+// the plaintext media type in cty. Rotate keys by listing the new key next
+// to the old one until clients have switched.
+type RequestEncryptionConfig struct {
+	Keys []EncryptionKey
+}
+
+// key returns the key with the given id
+func (cfg *RequestEncryptionConfig) key(id string) *EncryptionKey {
+	for i := range cfg.Keys {
+		if cfg.Keys[i].ID == id {
+			return &cfg.Keys[i]
+		}
+	}
+	return nil
+}
+
+// check validates the configured keys
+func (cfg *RequestEncryptionConfig) check() error {
+	if len(cfg.Keys) == 0 {
+		return errors.New("no keys configured")
+	}
+	seen := map[string]bool{}
+	for _, key := range cfg.Keys {
+		switch {
+		case key.ID == "":
+			return errors.New("key without an ID")
+		case seen[key.ID]:
+			return fmt.Errorf("duplicate key ID %q", key.ID)
+		case key.PrivateKey == nil && !validDirectKey(key.Secret):
+			return fmt.Errorf("key %q needs a PrivateKey or a 16, 24 or 32-byte Secret", key.ID)
+		}
+		seen[key.ID] = true
+	}
+	return nil
+}
+
+// validDirectKey reports whether secret is an AES key size
+func validDirectKey(secret []byte) bool {
+	switch len(secret) {
+	case 16, 24, 32:
+		return true
+	}
+	return false
+}
+
+// keyIDs returns the ids of the configured keys
+func (cfg *RequestEncryptionConfig) keyIDs() []string {
+	ids := make([]string, len(cfg.Keys))
+	for i, key := range cfg.Keys {
+		ids[i] = key.ID
+	}
+	return ids
+}
+
+// algs returns the key management algorithms of the configured keys
+func (cfg *RequestEncryptionConfig) algs() []string {
+	seen := map[string]bool{}
+	var algs []string
+	for i := range cfg.Keys {
+		if alg := cfg.Keys[i].alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// publicKeys returns the RSA keys as public JWKs, so clients can encrypt
+// without exchanging keys out of band
+func (cfg *RequestEncryptionConfig) publicKeys() []map[string]string {
+	var keys []map[string]string
+	for _, key := range cfg.Keys {
+		if key.PrivateKey == nil {
+			continue
+		}
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": key.ID,
+			"use": "enc",
+			"alg": JWEAlgRSAOAEP256,
+			"n":   base64.RawURLEncoding.EncodeToString(key.PrivateKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PrivateKey.E)).Bytes()),
+		})
+	}
+	return keys
+}
+
+// describe documents the encryption of request bodies in the spec
+func (cfg *RequestEncryptionConfig) describe() string {
+	return "Request bodies carry personal data and must be encrypted as a compact JWE with Content-Type: " + MIMEApplicationJOSE +
+		". The protected header sets alg (" + strings.Join(cfg.algs(), " or ") + "), enc (A128GCM, A192GCM or A256GCM) and kid (" +
+		strings.Join(cfg.keyIDs(), ", ") + "); the plaintext is the JSON body described in x-jwe. " +
+		"Unencrypted bodies are rejected with 415 and bodies that don't decrypt with 400."
+}
+
+// jweExtension documents the accepted JWE headers and the plaintext schema
+func (cfg *RequestEncryptionConfig) jweExtension(plaintext *JSONSchema) map[string]interface{} {
+	encs := make([]string, 0, len(jweEncryptions))
+	for enc := range jweEncryptions {
+		encs = append(encs, enc)
+	}
+	sort.Strings(encs)
+	return map[string]interface{}{
+		"alg":       cfg.algs(),
+		"enc":       encs,
+		"kid":       cfg.keyIDs(),
+		"plaintext": plaintext,
+	}
+}
+
+// jweHeader is the protected header of a compact JWE
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid"`
+	Cty string `json:"cty,omitempty"`
+	Zip string `json:"zip,omitempty"`
+}
+
+// decryptJWE decrypts a compact JWE, returning its plaintext and header
+func (cfg *RequestEncryptionConfig) decryptJWE(compact string) ([]byte, *jweHeader, error) {
+	parts := strings.Split(strings.TrimSpace(compact), ".")
+	if len(parts) != 5 {
+		return nil, nil, errors.New("not a compact JWE")
+	}
+	decoded := make([][]byte, 5)
+	for i, part := range parts {
+		data, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return nil, nil, errors.New("malformed JWE segment")
+		}
+		decoded[i] = data
+	}
+
+	var header jweHeader
+	if err := json.Unmarshal(decoded[0], &header); err != nil {
+		return nil, nil, errors.New("malformed JWE header")
+	}
+	if header.Zip != "" {
+		return nil, nil, errors.New("compressed JWE payloads are not supported")
+	}
+	size, ok := jweEncryptions[header.Enc]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported enc %q", header.Enc)
+	}
+	key := cfg.key(header.Kid)
+	if key == nil {
+		return nil, nil, fmt.Errorf("unknown kid %q", header.Kid)
+	}
+	if header.Alg != key.alg() {
+		return nil, nil, fmt.Errorf("key %q requires alg %s", key.ID, key.alg())
+	}
+
+	var cek []byte
+	if key.PrivateKey != nil {
+		unwrapped, err := rsa.DecryptOAEP(sha256.New(), nil, key.PrivateKey, decoded[1], nil)
+		if err != nil {
+			return nil, nil, errors.New("content key doesn't decrypt")
+		}
+		cek = unwrapped
+	} else {
+		if len(decoded[1]) != 0 {
+			return nil, nil, errors.New("direct encryption takes no encrypted key")
+		}
+		cek = key.Secret
+	}
+	if len(cek) != size {
+		return nil, nil, fmt.Errorf("content key doesn't match enc %s", header.Enc)
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(decoded[2]) != aead.NonceSize() || len(decoded[4]) != aead.Overhead() {
+		return nil, nil, errors.New("malformed JWE initialization vector or tag")
+	}
+	sealed := append(append([]byte{}, decoded[3]...), decoded[4]...)
+	// The protected header is authenticated as the ASCII of its encoding
+	plaintext, err := aead.Open(nil, decoded[2], sealed, []byte(parts[0]))
+	if err != nil {
+		return nil, nil, errors.New("payload doesn't decrypt")
+	}
+	return plaintext, &header, nil
+}
+
+// RequestEncryptionMiddleware returns a Fiber middleware decrypting compact
+// JWE request bodies in place, so later handlers and validation see the
+// plaintext. Unencrypted bodies get 415 and bodies that don't decrypt 400.
+//
+// It is installed automatically on routes registered with
+// DocumentedRouteInput.EncryptedRequest.
+func RequestEncryptionMiddleware(cfg RequestEncryptionConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if len(c.Request().Body()) == 0 {
+			return c.Next()
+		}
+		mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+		if mediaType != MIMEApplicationJOSE {
+			return fiber.NewError(fiber.StatusUnsupportedMediaType, "Request body must be encrypted as a compact JWE with Content-Type "+MIMEApplicationJOSE)
+		}
+		plaintext, header, err := cfg.decryptJWE(string(c.Request().Body()))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid JWE request body: "+err.Error())
+		}
+
+		contentType := fiber.MIMEApplicationJSON
+		if header.Cty != "" && strings.Contains(header.Cty, "/") {
+			contentType = header.Cty
+		}
+		c.Request().Header.SetContentType(contentType)
+		c.Request().SetBodyRaw(plaintext)
+		return c.Next()
+	}
+}
+
+// documentRequestEncryption documents the rejections of encrypted routes
+func documentRequestEncryption(endpoint *Endpoint) {
+	if endpoint.Responses == nil {
+		endpoint.Responses = make(map[string]string)
+	}
+	if _, ok := endpoint.Responses["400"]; !ok {
+		endpoint.Responses["400"] = "The request body doesn't decrypt"
+	}
+	if _, ok := endpoint.Responses["415"]; !ok {
+		endpoint.Responses["415"] = "The request body isn't encrypted"
+	}
+}
+
+// EncryptJWE encrypts a request body for routes with EncryptedRequest, as Go
+// clients send it: with publicKey the content key is wrapped with
+// RSA-OAEP-256 and the body encrypted with A256GCM, otherwise secret is the
+// direct AES-GCM key. Send the result with Content-Type application/jose.
+func EncryptJWE(plaintext []byte, kid string, publicKey *rsa.PublicKey, secret []byte) (string, error) {
+	header := jweHeader{Alg: JWEAlgDirect, Enc: fmt.Sprintf("A%dGCM", len(secret)*8), Kid: kid}
+	cek := secret
+	var encryptedKey []byte
+	if publicKey != nil {
+		header.Alg, header.Enc = JWEAlgRSAOAEP256, "A256GCM"
+		cek = make([]byte, 32)
+		if _, err := rand.Read(cek); err != nil {
+			return "", err
+		}
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, cek, nil)
+		if err != nil {
+			return "", err
+		}
+		encryptedKey = wrapped
+	} else if !validDirectKey(secret) {
+		return "", errors.New("notelink: secret must be a 16, 24 or 32-byte AES key")
+	}
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	protected := base64.RawURLEncoding.EncodeToString(headerJSON)
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return "", err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nil, iv, plaintext, []byte(protected))
+	tagStart := len(sealed) - aead.Overhead()
+	return strings.Join([]string{
+		protected,
+		base64.RawURLEncoding.EncodeToString(encryptedKey),
+		base64.RawURLEncoding.EncodeToString(iv),
+		base64.RawURLEncoding.EncodeToString(sealed[:tagStart]),
+		base64.RawURLEncoding.EncodeToString(sealed[tagStart:]),
+	}, "."), nil
+}
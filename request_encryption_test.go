@@ -0,0 +1,131 @@
+package notelink
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type encryptedPatient struct {
+	Name string `json:"name"`
+	SSN  string `json:"ssn"`
+}
+
+// TestRequestEncryption tests decrypting JWE request bodies before validation
+func TestRequestEncryption(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		RequestEncryption: &RequestEncryptionConfig{Keys: []EncryptionKey{
+			{ID: "rsa-2026", PrivateKey: privateKey},
+			{ID: "shared-1", Secret: secret},
+		}},
+	}, "secret")
+	err = api.DocumentedRoute(&DocumentedRouteInput{
+		Method:           "POST",
+		Path:             "/patients",
+		Description:      "Register a patient",
+		SchemasRequest:   encryptedPatient{},
+		EncryptedRequest: true,
+		Handler: func(c fiber.Ctx) error {
+			var patient encryptedPatient
+			if err := c.Bind().JSON(&patient); err != nil {
+				return err
+			}
+			return c.SendString(patient.Name)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	plaintext := []byte(`{"name":"Ada","ssn":"078-05-1120"}`)
+	rsaBody, err := EncryptJWE(plaintext, "rsa-2026", &privateKey.PublicKey, nil)
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	directBody, err := EncryptJWE(plaintext, "shared-1", nil, secret)
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	invalidBody, _ := EncryptJWE([]byte(`{"name":"Ada"}`), "shared-1", nil, secret)
+	unknownKey, _ := EncryptJWE(plaintext, "retired", nil, secret)
+	parts := strings.Split(directBody, ".")
+	parts[3] = parts[3][:len(parts[3])-2] + "AA"
+	tampered := strings.Join(parts, ".")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    int
+	}{
+		{"RSA-OAEP-256", MIMEApplicationJOSE, rsaBody, fiber.StatusOK},
+		{"direct key", MIMEApplicationJOSE, directBody, fiber.StatusOK},
+		{"plaintext body", fiber.MIMEApplicationJSON, string(plaintext), fiber.StatusUnsupportedMediaType},
+		{"unknown key id", MIMEApplicationJOSE, unknownKey, fiber.StatusBadRequest},
+		{"tampered ciphertext", MIMEApplicationJOSE, tampered, fiber.StatusBadRequest},
+		{"invalid plaintext", MIMEApplicationJOSE, invalidBody, fiber.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/patients", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.expected {
+				t.Fatalf("Expected %d, got %d: %s", tt.expected, resp.StatusCode, body)
+			}
+			if tt.expected == fiber.StatusOK && string(body) != "Ada" {
+				t.Errorf("Expected the decrypted body, got %q", body)
+			}
+		})
+	}
+
+	t.Run("spec", func(t *testing.T) {
+		data, err := json.Marshal(api.GenerateOpenAPISpec())
+		if err != nil {
+			t.Fatalf("Failed to marshal spec: %v", err)
+		}
+		var spec map[string]interface{}
+		json.Unmarshal(data, &spec) //nolint:errcheck // marshalled above
+		operation := spec["paths"].(map[string]interface{})["/patients"].(map[string]interface{})["post"].(map[string]interface{})
+
+		content := operation["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+		if _, ok := content[MIMEApplicationJOSE]; !ok || len(content) != 1 {
+			t.Errorf("Expected only the JWE media type, got %v", content)
+		}
+		jwe, ok := operation["x-jwe"].(map[string]interface{})
+		if !ok || jwe["plaintext"] == nil {
+			t.Fatalf("Expected x-jwe with the plaintext schema, got %v", operation["x-jwe"])
+		}
+		if kids, _ := json.Marshal(jwe["kid"]); string(kids) != `["rsa-2026","shared-1"]` {
+			t.Errorf("Expected the key ids documented, got %s", kids)
+		}
+		if !strings.Contains(operation["description"].(string), "compact JWE") {
+			t.Errorf("Expected the encryption described, got %q", operation["description"])
+		}
+		keys, _ := spec["x-jwe-keys"].([]interface{})
+		if len(keys) != 1 || keys[0].(map[string]interface{})["kid"] != "rsa-2026" {
+			t.Errorf("Expected the RSA public key published, got %v", spec["x-jwe-keys"])
+		}
+		if strings.Contains(string(data), "0123456789abcdef") {
+			t.Error("Expected the shared secret kept out of the spec")
+		}
+	})
+}
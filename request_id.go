@@ -0,0 +1,107 @@
+package notelink
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// RequestIDHeader carries the id correlating a request with its server logs.
+// The docs' try-it client sends a fresh one with every request and shows it
+// in the result panel.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request ids accepted from clients
+const maxRequestIDLength = 128
+
+// validRequestID reports whether a client's request id is safe to log: up to
+// 128 printable ASCII characters without spaces
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random request id
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id) //nolint:errcheck // never fails
+	return hex.EncodeToString(id)
+}
+
+// RequestID returns the id of a request: the one RequestIDMiddleware set,
+// else a valid X-Request-ID header, else ""
+func RequestID(c fiber.Ctx) string {
+	if id, ok := c.Locals("request_id").(string); ok {
+		return id
+	}
+	if id := c.Get(RequestIDHeader); validRequestID(id) {
+		return id
+	}
+	return ""
+}
+
+// RequestIDMiddleware tags every request with an id, taken from its
+// X-Request-ID header or generated, stored in the request_id local and echoed
+// in the response's X-Request-ID header. Config.RequestLogging adds it to the
+// documented routes.
+func RequestIDMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		id := c.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Locals("request_id", id)
+		c.Set(RequestIDHeader, id)
+		return c.Next()
+	}
+}
+
+// RequestLoggerMiddleware logs every request with its method, route, status,
+// latency and request id once it completes. Config.RequestLogging adds it to
+// the documented routes.
+func RequestLoggerMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		status := c.Response().StatusCode()
+		// Errors are rendered by the ErrorHandler after the route returns
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+		log.Infow("notelink: request", "method", c.Method(), "path", c.Path(), "route", c.Route().Path,
+			"status", status, "latency", time.Since(start).String(), "request_id", RequestID(c))
+		return err
+	}
+}
+
+// requestIDScript generates the request ids of the try-it client and shows
+// them in the result panel
+const requestIDScript = `
+            const requestIdHeader = '` + RequestIDHeader + `';
+
+            function newRequestId() {
+                if (window.crypto && crypto.randomUUID) {
+                    return crypto.randomUUID();
+                }
+                return Array.from({length: 32}, () => Math.floor(Math.random() * 16).toString(16)).join('');
+            }
+
+            function renderRequestId(requestId) {
+                return 'Request ID: <code class="request-id" title="Sent as ' + requestIdHeader + '; search the server logs for it">' + escapeHtml(requestId) + '</code><br>';
+            }
+`
@@ -0,0 +1,88 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestRequestID tests tagging requests with the try-it client's request id
+func TestRequestID(t *testing.T) {
+	type user struct {
+		Name string `json:"name" validate:"required"`
+	}
+	var logged []*ValidationFailure
+	var events []ThresholdEvent
+	api := NewApiNote(&Config{
+		Title:              "Test API",
+		RequestLogging:     true,
+		Thresholds:         &Thresholds{Latency: time.Nanosecond},
+		ValidationFailures: &ValidationFailureLog{Log: func(failure *ValidationFailure) { logged = append(logged, failure) }},
+	}, "secret")
+	api.OnThresholdExceeded(func(event ThresholdEvent) { events = append(events, event) })
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/v1/users",
+		Description:    "Create user",
+		SchemasRequest: user{},
+		Handler: func(c fiber.Ctx) error {
+			return c.SendString(RequestID(c))
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		requestID string
+		generated bool
+	}{
+		{name: "sent by the client", requestID: "3f2c9a1e-try-it"},
+		{name: "generated when missing", generated: true},
+		{name: "generated when invalid", requestID: "has spaces in it", generated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/users", strings.NewReader(`{"name":"Ada"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.requestID != "" {
+				req.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			id := resp.Header.Get(RequestIDHeader)
+			if tt.generated && (len(id) != 32 || id == tt.requestID) {
+				t.Errorf("Expected a generated request id, got %q", id)
+			}
+			if !tt.generated && id != tt.requestID {
+				t.Errorf("Expected request id %q echoed, got %q", tt.requestID, id)
+			}
+			if len(events) == 0 || events[len(events)-1].RequestID != id {
+				t.Errorf("Expected the threshold event tagged with %q, got %+v", id, events)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("POST", "/v1/users", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(RequestIDHeader, "invalid-body-click")
+	if _, err := api.Fiber().Test(req); err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if len(logged) != 1 || logged[0].RequestID != "invalid-body-click" {
+		t.Errorf("Expected the validation failure tagged with the request id, got %+v", logged)
+	}
+
+	html := api.generateHTML()
+	for _, snippet := range []string{"const requestIdHeader = 'X-Request-ID';", "options.headers[requestIdHeader] = newRequestId();", "renderRequestId(requestId)"} {
+		if !strings.Contains(html, snippet) {
+			t.Errorf("Expected docs to contain %q", snippet)
+		}
+	}
+}
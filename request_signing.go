@@ -0,0 +1,203 @@
+package notelink
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// RequestSigningConfig configures the HMAC request-signing scheme used by partner APIs.
+//
+// Clients sign a canonical string built from the request:
+//
+//	METHOD + "\n" + PATH_AND_QUERY + "\n" + TIMESTAMP + "\n" + hex(sha256(BODY))
+//
+// with HMAC-SHA256 using the secret associated with their key id, and send the
+// hex-encoded result together with the key id and the unix timestamp.
+type RequestSigningConfig struct {
+	// SecretLookup resolves the signing secret for a key id. Required.
+	SecretLookup    func(keyID string) (string, error)
+	KeyIDHeader     string        // Header carrying the key id (default: "X-Key-Id")
+	SignatureHeader string        // Header carrying the signature (default: "X-Signature")
+	TimestampHeader string        // Header carrying the unix timestamp (default: "X-Timestamp")
+	Tolerance       time.Duration // Allowed clock skew (default: 5 minutes)
+}
+
+// keyIDHeader returns the configured key id header or the default
+func (r *RequestSigningConfig) keyIDHeader() string {
+	if r.KeyIDHeader == "" {
+		return "X-Key-Id"
+	}
+	return r.KeyIDHeader
+}
+
+// signatureHeader returns the configured signature header or the default
+func (r *RequestSigningConfig) signatureHeader() string {
+	if r.SignatureHeader == "" {
+		return "X-Signature"
+	}
+	return r.SignatureHeader
+}
+
+// timestampHeader returns the configured timestamp header or the default
+func (r *RequestSigningConfig) timestampHeader() string {
+	if r.TimestampHeader == "" {
+		return "X-Timestamp"
+	}
+	return r.TimestampHeader
+}
+
+// tolerance returns the configured timestamp tolerance or the default
+func (r *RequestSigningConfig) tolerance() time.Duration {
+	if r.Tolerance <= 0 {
+		return 5 * time.Minute
+	}
+	return r.Tolerance
+}
+
+// Describe returns a description of the signing scheme for the security scheme documentation
+func (r *RequestSigningConfig) Describe() string {
+	return fmt.Sprintf("HMAC-SHA256 request signature. Sign the canonical string "+
+		"METHOD\\nPATH_AND_QUERY\\nTIMESTAMP\\nhex(sha256(BODY)) with your secret and send the hex digest in %s, "+
+		"your key id in %s and the unix timestamp in %s. Timestamps older than %s are rejected.",
+		r.signatureHeader(), r.keyIDHeader(), r.timestampHeader(), r.tolerance())
+}
+
+// CanonicalRequestString builds the string that is signed for a request
+func CanonicalRequestString(method, pathAndQuery, timestamp string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.ToUpper(method) + "\n" + pathAndQuery + "\n" + timestamp + "\n" + hex.EncodeToString(bodyHash[:])
+}
+
+// SignRequest computes the hex-encoded HMAC-SHA256 signature of a request
+func SignRequest(method, pathAndQuery, timestamp string, body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(CanonicalRequestString(method, pathAndQuery, timestamp, body)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// RequestSigningMiddleware returns a Fiber middleware that verifies HMAC-signed requests.
+//
+// Requests with missing headers, an unknown key id, a timestamp outside the
+// tolerance window or an invalid signature are rejected with 401 Unauthorized.
+// On success the key id is stored in the context under "signing_key_id".
+//
+// Example usage:
+//
+//	api.Use(notelink.RequestSigningMiddleware(notelink.RequestSigningConfig{
+//	    SecretLookup: partnerSecrets.Lookup,
+//	}))
+func RequestSigningMiddleware(cfg RequestSigningConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		keyID := c.Get(cfg.keyIDHeader())
+		signature := c.Get(cfg.signatureHeader())
+		timestamp := c.Get(cfg.timestampHeader())
+		if keyID == "" || signature == "" || timestamp == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "Request signature headers required")
+		}
+
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid request timestamp")
+		}
+		skew := time.Since(time.Unix(unix, 0))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > cfg.tolerance() {
+			return fiber.NewError(fiber.StatusUnauthorized, "Request timestamp outside tolerance")
+		}
+
+		if cfg.SecretLookup == nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Request signing secret lookup not configured")
+		}
+		secret, err := cfg.SecretLookup(keyID)
+		if err != nil || secret == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "Unknown signing key")
+		}
+
+		expected := SignRequest(c.Method(), c.OriginalURL(), timestamp, c.Body(), secret)
+		if !hmac.Equal([]byte(strings.ToLower(signature)), []byte(expected)) {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid request signature")
+		}
+
+		c.Locals("signing_key_id", keyID)
+		return c.Next()
+	}
+}
+
+// requestSigningSecurityScheme returns the OpenAPI security scheme for request signing
+func requestSigningSecurityScheme(cfg *RequestSigningConfig) SecurityScheme {
+	return SecurityScheme{
+		Type:        "apiKey",
+		In:          "header",
+		Name:        cfg.signatureHeader(),
+		Description: cfg.Describe(),
+	}
+}
+
+// namedSecurityScheme is a security scheme of the spec's components
+type namedSecurityScheme struct {
+	name   string
+	scheme SecurityScheme
+}
+
+// requestSigningSchemes returns a security scheme per distinct request signing
+// config of the endpoints, in endpoint key order: "requestSignature" for the
+// first, then "requestSignature2" and so on, so the spec is stable
+func (an *ApiNote) requestSigningSchemes() []namedSecurityScheme {
+	keys := make([]string, 0, len(an.endpoints))
+	for key, endpoint := range an.endpoints {
+		if endpoint.RequestSigning != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var schemes []namedSecurityScheme
+	for _, key := range keys {
+		scheme := requestSigningSecurityScheme(an.endpoints[key].RequestSigning)
+		if slices.ContainsFunc(schemes, func(named namedSecurityScheme) bool { return named.scheme == scheme }) {
+			continue
+		}
+		name := "requestSignature"
+		if len(schemes) > 0 {
+			name += strconv.Itoa(len(schemes) + 1)
+		}
+		schemes = append(schemes, namedSecurityScheme{name: name, scheme: scheme})
+	}
+	return schemes
+}
+
+// requestSigningSchemeName returns the name of the security scheme of a
+// request signing config
+func (an *ApiNote) requestSigningSchemeName(cfg *RequestSigningConfig) string {
+	scheme := requestSigningSecurityScheme(cfg)
+	for _, named := range an.requestSigningSchemes() {
+		if named.scheme == scheme {
+			return named.name
+		}
+	}
+	return "requestSignature"
+}
+
+// renderSignatureCalculator renders the try-it widget that signs requests in the browser
+func renderSignatureCalculator(cfg *RequestSigningConfig) string {
+	return `
+                            <div class="signature-calculator" data-key-header="` + escapeHTML(cfg.keyIDHeader()) + `" data-signature-header="` + escapeHTML(cfg.signatureHeader()) + `" data-timestamp-header="` + escapeHTML(cfg.timestampHeader()) + `">
+                                <h5><i class="fas fa-signature"></i> Request Signature</h5>
+                                <label>Key ID:</label>
+                                <input type="text" class="signing-key-id" placeholder="Enter key id">
+                                <label>Secret:</label>
+                                <input type="password" class="signing-secret" placeholder="Enter signing secret (never sent)">
+                                <pre class="signature-preview"></pre>
+                            </div>`
+}
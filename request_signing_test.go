@@ -0,0 +1,167 @@
+package notelink
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestRequestSigningMiddleware tests verification of HMAC-signed partner requests
+func TestRequestSigningMiddleware(t *testing.T) {
+	cfg := RequestSigningConfig{
+		SecretLookup: func(keyID string) (string, error) {
+			if keyID == "partner-1" {
+				return "partner-secret", nil
+			}
+			return "", errors.New("unknown key")
+		},
+	}
+	body := []byte(`{"amount":100}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name           string
+		keyID          string
+		timestamp      string
+		signature      string
+		expectedStatus int
+	}{
+		{
+			name:           "Valid signature",
+			keyID:          "partner-1",
+			timestamp:      now,
+			signature:      SignRequest("POST", "/payments?dry_run=true", now, body, "partner-secret"),
+			expectedStatus: 200,
+		},
+		{
+			name:           "Unknown key",
+			keyID:          "partner-2",
+			timestamp:      now,
+			signature:      SignRequest("POST", "/payments?dry_run=true", now, body, "partner-secret"),
+			expectedStatus: 401,
+		},
+		{
+			name:           "Stale timestamp",
+			keyID:          "partner-1",
+			timestamp:      stale,
+			signature:      SignRequest("POST", "/payments?dry_run=true", stale, body, "partner-secret"),
+			expectedStatus: 401,
+		},
+		{
+			name:           "Signature over different path",
+			keyID:          "partner-1",
+			timestamp:      now,
+			signature:      SignRequest("POST", "/payments", now, body, "partner-secret"),
+			expectedStatus: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Rejections reach the app's ErrorHandler
+			var handled error
+			app := fiber.New(fiber.Config{ErrorHandler: func(c fiber.Ctx, err error) error {
+				handled = err
+				return DefaultErrorHandler(c, err)
+			}})
+			app.Post("/payments", RequestSigningMiddleware(cfg), func(c fiber.Ctx) error {
+				return c.SendString("OK")
+			})
+
+			req := httptest.NewRequest("POST", "/payments?dry_run=true", bytes.NewReader(body))
+			req.Header.Set("X-Key-Id", tt.keyID)
+			req.Header.Set("X-Timestamp", tt.timestamp)
+			req.Header.Set("X-Signature", tt.signature)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			var fiberErr *fiber.Error
+			if tt.expectedStatus != 200 && (!errors.As(handled, &fiberErr) || fiberErr.Code != tt.expectedStatus) {
+				t.Errorf("Expected the ErrorHandler to receive a %d error, got %v", tt.expectedStatus, handled)
+			}
+		})
+	}
+}
+
+// TestRequestSigningSecurityScheme tests that signed routes document the custom security scheme
+func TestRequestSigningSecurityScheme(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/v1/payments",
+		Description:    "Create a payment",
+		Handler:        func(c fiber.Ctx) error { return c.SendStatus(201) },
+		RequestSigning: &RequestSigningConfig{SecretLookup: func(string) (string, error) { return "s", nil }},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	scheme, ok := spec.Components.SecuritySchemes["requestSignature"]
+	if !ok {
+		t.Fatal("Expected requestSignature security scheme")
+	}
+	if scheme.Type != "apiKey" || scheme.Name != "X-Signature" {
+		t.Errorf("Unexpected security scheme: %+v", scheme)
+	}
+
+	operation := spec.Paths["/v1/payments"].Post
+	if len(operation.Security) != 1 {
+		t.Fatalf("Expected one security requirement, got %d", len(operation.Security))
+	}
+	if _, ok := operation.Security[0]["requestSignature"]; !ok {
+		t.Error("Expected operation to require requestSignature")
+	}
+}
+
+// TestRequestSigningSecuritySchemes tests naming a scheme per distinct signing config
+func TestRequestSigningSecuritySchemes(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	lookup := func(string) (string, error) { return "s", nil }
+	routes := []DocumentedRouteInput{
+		{Method: "POST", Path: "/v1/payments", RequestSigning: &RequestSigningConfig{SecretLookup: lookup}},
+		{Method: "POST", Path: "/v1/payouts", RequestSigning: &RequestSigningConfig{SecretLookup: lookup, SignatureHeader: "X-Partner-Signature"}},
+		{Method: "POST", Path: "/v1/refunds", RequestSigning: &RequestSigningConfig{SecretLookup: lookup}},
+	}
+	for i := range routes {
+		routes[i].Handler = func(c fiber.Ctx) error { return c.SendStatus(201) }
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		"/v1/payments": "requestSignature",
+		"/v1/payouts":  "requestSignature2",
+		"/v1/refunds":  "requestSignature",
+	}
+	// Map iteration order must not change the spec
+	for run := 0; run < 10; run++ {
+		spec := api.GenerateOpenAPISpec()
+		if len(spec.Components.SecuritySchemes) != 2 {
+			t.Fatalf("Expected 2 security schemes, got %+v", spec.Components.SecuritySchemes)
+		}
+		if scheme := spec.Components.SecuritySchemes["requestSignature2"]; scheme.Name != "X-Partner-Signature" {
+			t.Errorf("Expected the second scheme to use X-Partner-Signature, got %+v", scheme)
+		}
+		for path, name := range expected {
+			if _, ok := spec.Paths[path].Post.Security[0][name]; !ok {
+				t.Errorf("Expected %s to require %s, got %+v", path, name, spec.Paths[path].Post.Security)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package notelink
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// ResponseTransformMiddleware reshapes the JSON responses of a handler into the
+// output struct declared with DocumentedRouteInput.TransformResponse, e.g. to
+// put a documented contract in front of a legacy handler. Each field is read
+// from the handler's field of the same JSON name, or the one named by its
+// `from` tag, which may be a dotted path such as `from:"profile.email_addr"`.
+// Fields the struct doesn't declare are dropped. Transformed successful
+// responses are validated against the struct; mismatches are logged and
+// answered with 500 Internal Server Error, so the documented schema holds.
+func ResponseTransformMiddleware(output interface{}, timeFormat string) fiber.Handler {
+	outputType := reflect.TypeOf(output)
+	return func(c fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		status := c.Response().StatusCode()
+		if status < 200 || status >= 300 || !strings.Contains(string(c.Response().Header.ContentType()), "json") {
+			return nil
+		}
+		body := c.Response().Body()
+		if len(body) == 0 {
+			return nil
+		}
+
+		var decoded interface{}
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			return nil
+		}
+		transformed := transformValue(decoded, outputType)
+		if errs := validateTransformed(transformed, outputType, timeFormat); len(errs) > 0 {
+			log.Warnf("notelink: %s %s: response doesn't match the declared output schema: %s", c.Method(), c.Route().Path, strings.Join(errs, "; "))
+			return fiber.NewError(fiber.StatusInternalServerError, "Response does not match the documented schema")
+		}
+
+		data, err := json.Marshal(transformed)
+		if err != nil {
+			return err
+		}
+		c.Response().SetBodyRaw(data)
+		return nil
+	}
+}
+
+// transformValue reshapes a decoded JSON value into the fields of a type
+func transformValue(value interface{}, typ reflect.Type) interface{} {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.Struct:
+		if object, ok := value.(map[string]interface{}); ok && !validation.IsTimeType(typ) {
+			return transformObject(object, typ)
+		}
+	case reflect.Slice, reflect.Array:
+		if elements, ok := value.([]interface{}); ok {
+			transformed := make([]interface{}, len(elements))
+			for i, element := range elements {
+				transformed[i] = transformValue(element, typ.Elem())
+			}
+			return transformed
+		}
+	}
+	return value
+}
+
+// transformObject builds the fields of a struct type from a decoded object
+func transformObject(object map[string]interface{}, typ reflect.Type) map[string]interface{} {
+	result := make(map[string]interface{}, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := validation.JSONFieldName(&field)
+		if name == "-" {
+			continue
+		}
+		source := name
+		if from := field.Tag.Get("from"); from != "" {
+			source = from
+		}
+		if value, ok := lookupPath(object, source); ok {
+			result[name] = transformValue(value, field.Type)
+		}
+	}
+	return result
+}
+
+// lookupPath returns the value at a dotted path of a decoded object
+func lookupPath(object map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = object
+	for _, key := range strings.Split(path, ".") {
+		current, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = current[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// validateTransformed validates a transformed response against the output
+// type, element by element for slices
+func validateTransformed(value interface{}, typ reflect.Type, timeFormat string) []string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	var objects []map[string]interface{}
+	switch transformed := value.(type) {
+	case map[string]interface{}:
+		objects = append(objects, transformed)
+	case []interface{}:
+		if typ.Kind() != reflect.Slice {
+			return []string{"expected an object, got an array"}
+		}
+		typ = typ.Elem()
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		for _, element := range transformed {
+			if object, ok := element.(map[string]interface{}); ok {
+				objects = append(objects, object)
+			}
+		}
+	}
+
+	var errs []string
+	for _, object := range objects {
+		for _, err := range validation.Struct(object, typ, timeFormat) {
+			errs = append(errs, fmt.Sprintf("%s: %s", err.Field, err.Message))
+		}
+	}
+	return errs
+}
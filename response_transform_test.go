@@ -0,0 +1,78 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type transformedUser struct {
+	ID     int                `json:"id" from:"user_id"`
+	Email  string             `json:"email" from:"profile.email_addr"`
+	Name   string             `json:"name,omitempty"`
+	Groups []transformedGroup `json:"groups,omitempty" from:"memberships"`
+}
+
+type transformedGroup struct {
+	Name string `json:"name" from:"group_name"`
+}
+
+// TestResponseTransform tests reshaping legacy handler output into the declared output struct
+func TestResponseTransform(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	legacy := func(body string, status int) fiber.Handler {
+		return func(c fiber.Ctx) error {
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(status).SendString(body)
+		}
+	}
+	routes := []DocumentedRouteInput{
+		{Method: "GET", Path: "/v1/user", Responses: map[string]string{"200": "The user"}, TransformResponse: transformedUser{}, Handler: legacy(
+			`{"user_id": 7, "password_hash": "x", "profile": {"email_addr": "a@example.com"}, "memberships": [{"group_name": "admins", "since": 2020}]}`, 200)},
+		{Method: "GET", Path: "/v1/users", TransformResponse: []transformedUser{}, Handler: legacy(
+			`[{"user_id": 1, "profile": {"email_addr": "b@example.com"}, "name": "B"}]`, 200)},
+		{Method: "GET", Path: "/v1/broken", TransformResponse: transformedUser{}, Handler: legacy(`{"profile": {}}`, 200)},
+		{Method: "GET", Path: "/v1/missing", TransformResponse: transformedUser{}, Handler: legacy(`{"error": "gone"}`, 404)},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+	both := DocumentedRouteInput{Method: "GET", Path: "/v1/both", TransformResponse: transformedUser{}, SchemasResponse: transformedUser{}, Handler: legacy("{}", 200)}
+	if err := api.DocumentedRoute(&both); err == nil {
+		t.Error("Expected an error when both TransformResponse and SchemasResponse are set")
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"renamed and filtered", "/v1/user", 200, `{"email":"a@example.com","groups":[{"name":"admins"}],"id":7}`},
+		{"array", "/v1/users", 200, `[{"email":"b@example.com","id":1,"name":"B"}]`},
+		{"invalid output", "/v1/broken", 500, `{"error":"Response does not match the documented schema"}`},
+		{"error responses untouched", "/v1/missing", 404, `{"error": "gone"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || string(body) != tt.body {
+				t.Errorf("Expected %d %s, got %d %s", tt.status, tt.body, resp.StatusCode, body)
+			}
+		})
+	}
+
+	schema := api.GenerateOpenAPISpec().Paths["/v1/user"].Get.Responses["200"].Content["application/json"].Schema
+	if schema == nil || schema.Ref != "#/components/schemas/transformedUser" {
+		t.Errorf("Expected the output struct as the documented schema, got %+v", schema)
+	}
+}
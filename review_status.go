@@ -0,0 +1,99 @@
+package notelink
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EndpointStatus is where a route is in the API review process
+type EndpointStatus string
+
+const (
+	// StatusDraft marks a route still being designed; Config.HideDraftRoutes
+	// leaves it out of the spec
+	StatusDraft EndpointStatus = "draft"
+	// StatusReview marks a route awaiting API review
+	StatusReview EndpointStatus = "review"
+	// StatusStable marks a reviewed route
+	StatusStable EndpointStatus = "stable"
+)
+
+// endpointStatuses are the statuses in review order
+var endpointStatuses = []EndpointStatus{StatusDraft, StatusReview, StatusStable}
+
+// validateStatus checks that a route's status is a known one
+func validateStatus(status EndpointStatus) error {
+	if status == "" {
+		return nil
+	}
+	for _, known := range endpointStatuses {
+		if status == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown status %q, expected draft, review or stable", status)
+}
+
+// hiddenAsDraft reports whether a draft endpoint is left out of the spec
+func (an *ApiNote) hiddenAsDraft(endpoint *Endpoint) bool {
+	return endpoint.Status == StatusDraft && an.config.HideDraftRoutes
+}
+
+// renderStatusBadge renders the review status badge of an endpoint
+func renderStatusBadge(endpoint *Endpoint) string {
+	switch endpoint.Status {
+	case StatusDraft:
+		return `<span class="status-badge status-draft" title="Draft; not reviewed yet"><i class="fas fa-pen-ruler"></i> draft</span>`
+	case StatusReview:
+		return `<span class="status-badge status-review" title="Awaiting API review"><i class="fas fa-magnifying-glass"></i> in review</span>`
+	case StatusStable:
+		return `<span class="status-badge status-stable" title="Reviewed and stable"><i class="fas fa-circle-check"></i> stable</span>`
+	}
+	return ""
+}
+
+// renderStatusFilter renders the status filter of the endpoint list, when
+// any endpoint has a status
+func (an *ApiNote) renderStatusFilter() string {
+	used := make(map[EndpointStatus]bool)
+	for _, endpoint := range an.endpoints {
+		if endpoint.Status != "" {
+			used[endpoint.Status] = true
+		}
+	}
+	if len(used) == 0 {
+		return ""
+	}
+
+	var html strings.Builder
+	html.WriteString(`
+                <select id="status-filter" class="status-filter" onchange="filterByStatus(this.value)" title="Filter endpoints by review status">
+                    <option value="">All statuses</option>`)
+	for _, status := range endpointStatuses {
+		if used[status] {
+			html.WriteString(`
+                    <option value="` + string(status) + `">` + strings.ToUpper(string(status[:1])) + string(status[1:]) + `</option>`)
+		}
+	}
+	html.WriteString(`
+                </select>`)
+	return html.String()
+}
+
+// statusFilterScript shows only the endpoints with the selected status,
+// hiding the groups left empty
+const statusFilterScript = `
+            function filterByStatus(status) {
+                document.querySelectorAll('details.method-group[data-status]').forEach(group => {
+                    group.style.display = !status || group.dataset.status === status ? '' : 'none';
+                });
+                document.querySelectorAll('.docs-main details:not(.method-group)').forEach(group => {
+                    const endpoints = group.querySelectorAll('details.method-group[data-status]');
+                    if (endpoints.length === 0) {
+                        return;
+                    }
+                    const visible = Array.from(endpoints).some(endpoint => endpoint.style.display !== 'none');
+                    group.style.display = visible ? '' : 'none';
+                });
+            }
+`
@@ -0,0 +1,77 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestEndpointStatus tests the review status badges, filter and spec export
+func TestEndpointStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		hideDrafts bool
+		inSpec     []string
+		notInSpec  []string
+	}{
+		{name: "drafts exported", inSpec: []string{"/drafts", "/reviews", "/stable", "/plain"}},
+		{name: "drafts hidden", hideDrafts: true, inSpec: []string{"/reviews", "/stable", "/plain"}, notInSpec: []string{"/drafts"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", HideDraftRoutes: tt.hideDrafts}, "secret")
+			handler := func(c fiber.Ctx) error { return nil }
+			routes := []*DocumentedRouteInput{
+				{Method: "GET", Path: "/drafts", Handler: handler, Status: StatusDraft},
+				{Method: "GET", Path: "/reviews", Handler: handler, Status: StatusReview},
+				{Method: "GET", Path: "/stable", Handler: handler, Status: StatusStable},
+				{Method: "GET", Path: "/plain", Handler: handler},
+			}
+			for _, route := range routes {
+				if err := api.DocumentedRoute(route); err != nil {
+					t.Fatalf("Failed to register route: %v", err)
+				}
+			}
+
+			html := api.generateHTML()
+			for _, snippet := range []string{
+				`class="status-badge status-draft"`,
+				`class="status-badge status-review"`,
+				`class="status-badge status-stable"`,
+				`data-status="draft"`,
+				`<select id="status-filter"`,
+				`<option value="review">Review</option>`,
+				"function filterByStatus(status)",
+			} {
+				if !strings.Contains(html, snippet) {
+					t.Errorf("Expected docs to contain %q", snippet)
+				}
+			}
+
+			spec := api.generateOpenAPISpec("http://localhost:8080", api.docsLocale())
+			for _, path := range tt.inSpec {
+				if _, ok := spec.Paths[path]; !ok {
+					t.Errorf("Expected %s in the spec", path)
+				}
+			}
+			for _, path := range tt.notInSpec {
+				if _, ok := spec.Paths[path]; ok {
+					t.Errorf("Expected %s left out of the spec", path)
+				}
+			}
+			if status := spec.Paths["/reviews"].Get.Status; status != StatusReview {
+				t.Errorf("Expected x-status review, got %q", status)
+			}
+		})
+	}
+
+	api := NewApiNote(&Config{Title: "Test API"}, "secret")
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/x", Handler: func(c fiber.Ctx) error { return nil }, Status: "beta"}); err == nil {
+		t.Error("Expected an unknown status to be rejected")
+	}
+	if strings.Contains(api.generateHTML(), `<select id="status-filter"`) {
+		t.Error("Expected no status filter without statuses")
+	}
+}
@@ -0,0 +1,215 @@
+package notelink
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// DocumentedRoutes registers a route table: every route is validated first,
+// and the routes are mounted only if all of them are valid. Otherwise nothing
+// is registered and the error lists every invalid route by its index, so a
+// service declaring its routes in one table fails fast with a full report.
+//
+// Routes are mounted in table order; a table must not declare a method and
+// path twice nor redeclare an already registered route.
+//
+// Example:
+//
+//	err := api.DocumentedRoutes([]notelink.DocumentedRouteInput{
+//	    {Method: "GET", Path: "/users", Description: "List users", Handler: listUsers},
+//	    {Method: "POST", Path: "/users", Description: "Create a user", Handler: createUser},
+//	})
+func (an *ApiNote) DocumentedRoutes(routes []DocumentedRouteInput) error {
+	return an.documentedRoutes(routes, nil)
+}
+
+// DocumentedRoutes registers a route table, all or nothing, running the
+// scope's middleware right before each handler
+func (s *RouteScope) DocumentedRoutes(routes []DocumentedRouteInput) error {
+	return s.api.documentedRoutes(routes, s.middlewares)
+}
+
+// documentedRoutes prepares every route of a table, rolling back the schema
+// names and unsupported types they recorded if any is invalid, then mounts them
+func (an *ApiNote) documentedRoutes(routes []DocumentedRouteInput, scoped []fiber.Handler) error {
+	componentTypes := maps.Clone(an.componentTypes)
+	unsupportedTypes := len(an.unsupportedTypes)
+
+	var errs []error
+	prepared := make([]*preparedRoute, 0, len(routes))
+	declared := make(map[string]int, len(routes))
+	// Methods are matched case-insensitively, as Fiber routes them
+	registered := make(map[string]bool, len(an.endpoints))
+	for key := range an.endpoints {
+		method, path, _ := strings.Cut(key, " ")
+		registered[strings.ToUpper(method)+" "+path] = true
+	}
+	for i := range routes {
+		input := &routes[i]
+		key := strings.ToUpper(input.Method) + " " + input.Path
+		if first, ok := declared[key]; ok {
+			errs = append(errs, fmt.Errorf("routes[%d]: %s %s is already declared by routes[%d]", i, input.Method, input.Path, first))
+			continue
+		}
+		declared[key] = i
+		if registered[key] {
+			errs = append(errs, fmt.Errorf("routes[%d]: %s %s is already registered", i, input.Method, input.Path))
+			continue
+		}
+		route, err := an.prepareRoute(input)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("routes[%d]: %w", i, err))
+			continue
+		}
+		prepared = append(prepared, route)
+	}
+	if len(errs) > 0 {
+		an.componentTypes = componentTypes
+		an.unsupportedTypes = an.unsupportedTypes[:unsupportedTypes]
+		return fmt.Errorf("%d of %d routes are invalid, none registered: %w", len(errs), len(routes), errors.Join(errs...))
+	}
+
+	for _, route := range prepared {
+		if err := an.mountRoute(route, scoped); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RouteTable declares a route table fluently, for DocumentedRoutes.
+//
+// Example:
+//
+//	routes := notelink.NewRouteTable().
+//	    Route("GET", "/users", listUsers).
+//	    Describe("List users").
+//	    Respond("200", "Users").
+//	    Response([]User{}).
+//	    Route("POST", "/users", createUser).
+//	    Describe("Create a user").
+//	    Respond("201", "User created").
+//	    Request(CreateUserRequest{}).
+//	    Routes()
+//	err := api.DocumentedRoutes(routes)
+type RouteTable struct {
+	routes []DocumentedRouteInput
+}
+
+// NewRouteTable returns an empty route table
+func NewRouteTable() *RouteTable {
+	return &RouteTable{}
+}
+
+// Route starts the declaration of a route; the RouteBuilder's methods set its
+// documentation
+func (t *RouteTable) Route(method, path string, handler fiber.Handler) *RouteBuilder {
+	t.routes = append(t.routes, DocumentedRouteInput{Method: method, Path: path, Handler: handler})
+	return &RouteBuilder{table: t, index: len(t.routes) - 1}
+}
+
+// Add appends a fully declared route
+func (t *RouteTable) Add(input DocumentedRouteInput) *RouteTable {
+	t.routes = append(t.routes, input)
+	return t
+}
+
+// Routes returns a copy of the declared routes, in declaration order
+func (t *RouteTable) Routes() []DocumentedRouteInput {
+	return append([]DocumentedRouteInput(nil), t.routes...)
+}
+
+// RouteBuilder sets the documentation of the route last started with Route
+type RouteBuilder struct {
+	table *RouteTable
+	index int
+}
+
+func (b *RouteBuilder) input() *DocumentedRouteInput {
+	return &b.table.routes[b.index]
+}
+
+// Describe sets the route's description
+func (b *RouteBuilder) Describe(description string) *RouteBuilder {
+	b.input().Description = description
+	return b
+}
+
+// Respond documents a response by status code, e.g. Respond("404", "User not found")
+func (b *RouteBuilder) Respond(status, description string) *RouteBuilder {
+	input := b.input()
+	if input.Responses == nil {
+		input.Responses = make(map[string]string)
+	}
+	input.Responses[status] = description
+	return b
+}
+
+// Params adds parameters to the route
+func (b *RouteBuilder) Params(params ...Parameter) *RouteBuilder {
+	input := b.input()
+	input.Params = append(input.Params, params...)
+	return b
+}
+
+// Request sets the route's request body schema
+func (b *RouteBuilder) Request(schema interface{}) *RouteBuilder {
+	b.input().SchemasRequest = schema
+	return b
+}
+
+// Response sets the route's response body schema
+func (b *RouteBuilder) Response(schema interface{}) *RouteBuilder {
+	b.input().SchemasResponse = schema
+	return b
+}
+
+// Tags adds tags to the route
+func (b *RouteBuilder) Tags(tags ...string) *RouteBuilder {
+	input := b.input()
+	input.Tags = append(input.Tags, tags...)
+	return b
+}
+
+// Auth sets whether the route requires authentication, overriding the
+// detection from UseJWT and UseCustomAuth
+func (b *RouteBuilder) Auth(required bool) *RouteBuilder {
+	b.input().AuthRequired = &required
+	return b
+}
+
+// Deprecate marks the route deprecated, pointing to its replacement when
+// guide is not nil
+func (b *RouteBuilder) Deprecate(guide *MigrationGuide) *RouteBuilder {
+	input := b.input()
+	input.Deprecated = true
+	input.Migration = guide
+	return b
+}
+
+// With sets any other field of the route, e.g.
+//
+//	With(func(r *notelink.DocumentedRouteInput) { r.SLO = &notelink.SLO{LatencyP99: time.Second} })
+func (b *RouteBuilder) With(set func(input *DocumentedRouteInput)) *RouteBuilder {
+	set(b.input())
+	return b
+}
+
+// Route ends the route's declaration and starts the next one
+func (b *RouteBuilder) Route(method, path string, handler fiber.Handler) *RouteBuilder {
+	return b.table.Route(method, path, handler)
+}
+
+// Add ends the route's declaration and appends a fully declared route
+func (b *RouteBuilder) Add(input DocumentedRouteInput) *RouteTable {
+	return b.table.Add(input)
+}
+
+// Routes ends the route's declaration and returns the table's routes
+func (b *RouteBuilder) Routes() []DocumentedRouteInput {
+	return b.table.Routes()
+}
@@ -0,0 +1,168 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type tableUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// TestDocumentedRoutes tests registering route tables all or nothing
+func TestDocumentedRoutes(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+
+	tests := []struct {
+		name     string
+		routes   []DocumentedRouteInput
+		errors   []string // Expected in the error, in order; none if the table is valid
+		mounted  []string // Paths answering GET
+		rejected []string // Paths not answering GET
+	}{
+		{
+			name: "valid table",
+			routes: []DocumentedRouteInput{
+				{Method: "GET", Path: "/users", Description: "List users", Handler: handler, SchemasResponse: []tableUser{}, Responses: map[string]string{"200": "Users"}},
+				{Method: "POST", Path: "/users", Description: "Create a user", Handler: handler, SchemasRequest: tableUser{}},
+				{Method: "GET", Path: "/users/:id", Description: "Get a user", Handler: handler},
+			},
+			mounted: []string{"/users", "/users/42"},
+		},
+		{
+			name: "invalid entries",
+			routes: []DocumentedRouteInput{
+				{Method: "GET", Path: "/orders", Description: "List orders", Handler: handler, SchemasResponse: tableUser{}},
+				{Method: "GET", Path: "/invoices", Description: "List invoices"},
+				{Method: "FETCH", Path: "/refunds", Handler: handler},
+				{Method: "GET", Path: "/orders", Handler: handler},
+				{Method: "GET", Path: "/legacy", Handler: handler, Migration: &MigrationGuide{Path: "/orders"}},
+			},
+			errors: []string{
+				"4 of 5 routes are invalid, none registered",
+				"routes[1]: handler is required",
+				"routes[2]: unsupported HTTP method: FETCH",
+				"routes[3]: GET /orders is already declared by routes[0]",
+				"routes[4]: GET /legacy: a MigrationGuide requires Deprecated",
+			},
+			rejected: []string{"/orders", "/legacy"},
+		},
+		{
+			name: "redeclared route",
+			routes: []DocumentedRouteInput{
+				{Method: "GET", Path: "/health", Handler: handler},
+				{Method: "GET", Path: "/registered", Handler: handler},
+			},
+			errors:   []string{"routes[1]: GET /registered is already registered"},
+			rejected: []string{"/health"},
+		},
+		{
+			name: "redeclared route in another case",
+			routes: []DocumentedRouteInput{
+				{Method: "get", Path: "/registered", Handler: handler},
+				{Method: "GET", Path: "/status", Handler: handler},
+			},
+			errors:   []string{"routes[0]: get /registered is already registered"},
+			rejected: []string{"/status"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API"}, "secret")
+			if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/registered", Handler: handler}); err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+			endpoints, componentTypes := len(api.endpoints), len(api.componentTypes)
+
+			err := api.DocumentedRoutes(tt.routes)
+			if len(tt.errors) == 0 {
+				if err != nil {
+					t.Fatalf("Expected the table to register, got %v", err)
+				}
+				if len(api.endpoints) != endpoints+len(tt.routes) {
+					t.Errorf("Expected %d endpoints, got %d", endpoints+len(tt.routes), len(api.endpoints))
+				}
+			} else {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				rest := err.Error()
+				for _, expected := range tt.errors {
+					index := strings.Index(rest, expected)
+					if index < 0 {
+						t.Fatalf("Expected %q in order in the error, got %v", expected, err)
+					}
+					rest = rest[index+len(expected):]
+				}
+				if len(api.endpoints) != endpoints || len(api.componentTypes) != componentTypes {
+					t.Errorf("Expected nothing registered, got %d endpoints and %d schemas", len(api.endpoints), len(api.componentTypes))
+				}
+			}
+
+			for _, path := range append(append([]string(nil), tt.mounted...), tt.rejected...) {
+				resp, err := api.Fiber().Test(httptest.NewRequest("GET", path, nil))
+				if err != nil {
+					t.Fatalf("Failed to send test request: %v", err)
+				}
+				mounted := resp.StatusCode == 200
+				if expected := !slices.Contains(tt.rejected, path); mounted != expected {
+					t.Errorf("%s: expected mounted %v, got status %d", path, expected, resp.StatusCode)
+				}
+			}
+		})
+	}
+}
+
+// TestRouteTable tests declaring route tables fluently
+func TestRouteTable(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+	routes := NewRouteTable().
+		Route("GET", "/users", handler).
+		Describe("List users").
+		Respond("200", "Users").
+		Response([]tableUser{}).
+		Tags("users").
+		Route("POST", "/users", handler).
+		Describe("Create a user").
+		Respond("201", "User created").
+		Request(tableUser{}).
+		Auth(true).
+		Route("GET", "/v1/users", handler).
+		Deprecate(&MigrationGuide{Path: "/users"}).
+		With(func(input *DocumentedRouteInput) { input.Weight = 5 }).
+		Add(DocumentedRouteInput{Method: "DELETE", Path: "/users/:id", Handler: handler}).
+		Routes()
+
+	if len(routes) != 4 {
+		t.Fatalf("Expected 4 routes, got %d", len(routes))
+	}
+	if routes[0].Description != "List users" || routes[0].Responses["200"] != "Users" || len(routes[0].Tags) != 1 || routes[0].SchemasRequest != nil {
+		t.Errorf("Unexpected first route %+v", routes[0])
+	}
+	if routes[1].AuthRequired == nil || !*routes[1].AuthRequired || routes[1].SchemasRequest == nil {
+		t.Errorf("Unexpected second route %+v", routes[1])
+	}
+	if !routes[2].Deprecated || routes[2].Migration == nil || routes[2].Weight != 5 {
+		t.Errorf("Unexpected third route %+v", routes[2])
+	}
+
+	api := NewApiNote(&Config{Title: "Test API"}, "secret")
+	if err := api.DocumentedRoutes(routes); err != nil {
+		t.Fatalf("Failed to register the table: %v", err)
+	}
+	descriptors := api.Endpoints()
+	if len(descriptors) != 4 {
+		t.Fatalf("Expected 4 documented endpoints, got %d", len(descriptors))
+	}
+	for _, descriptor := range descriptors {
+		if descriptor.Method == "POST" && !descriptor.AuthRequired {
+			t.Error("Expected POST /users to require authentication")
+		}
+	}
+}
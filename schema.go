@@ -4,11 +4,12 @@ import (
 	"encoding/json"
 	"reflect"
 	"strings"
-	"time"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
 )
 
 // generateTypeScriptSchema converts a Go type to TypeScript interfaces, including nested structs
-func generateTypeScriptSchema(name string, schema interface{}) string {
+func generateTypeScriptSchema(name string, schema interface{}, timeFormat string, names *schemaNames) string {
 	if schema == nil {
 		return ""
 	}
@@ -36,12 +37,10 @@ func generateTypeScriptSchema(name string, schema interface{}) string {
 	}
 
 	// Generate all nested structs first
-	generateAllStructs(typ, &ts, seenTypes)
+	generateAllStructs(typ, &ts, seenTypes, timeFormat, names)
 
 	// Generate the main interface
-	ts.WriteString(`export interface ` + name + " {\n")
-	ts.WriteString(generateStructSchema(typ))
-	ts.WriteString("}")
+	ts.WriteString(tsInterface(name, typeDescription(typ), typ, timeFormat, names))
 
 	if isArray {
 		return ts.String() + "[]"
@@ -50,7 +49,7 @@ func generateTypeScriptSchema(name string, schema interface{}) string {
 }
 
 // generateAllStructs recursively generates interfaces for all nested structs
-func generateAllStructs(typ reflect.Type, ts *strings.Builder, seenTypes map[string]bool) {
+func generateAllStructs(typ reflect.Type, ts *strings.Builder, seenTypes map[string]bool, timeFormat string, names *schemaNames) {
 	if typ.Kind() != reflect.Struct {
 		return
 	}
@@ -66,28 +65,54 @@ func generateAllStructs(typ reflect.Type, ts *strings.Builder, seenTypes map[str
 		if fieldType.Kind() == reflect.Slice {
 			fieldType = fieldType.Elem()
 		}
+		if valueType, ok := validation.NullableValueType(fieldType); ok {
+			fieldType = valueType
+		}
+
+		// Times and stdlib string types are rendered inline, not as an interface
+		if _, ok := lookupStdType(fieldType); ok || fieldType == timeType {
+			continue
+		}
+
+		if validation.TypeEnumValues(fieldType) != nil {
+			if field.Tag.Get("enum") != "" {
+				continue // Rendered as an inline union
+			}
+			if name := names.name(fieldType); !seenTypes[name] {
+				seenTypes[name] = true
+				ts.WriteString(tsEnumDeclaration(name, fieldType, names) + "\n\n")
+			}
+			continue
+		}
+
+		// Anonymous structs are inlined, but their named fields still need interfaces
+		if fieldType.Kind() == reflect.Struct && fieldType.Name() == "" {
+			generateAllStructs(fieldType, ts, seenTypes, timeFormat, names)
+			continue
+		}
 
-		if fieldType.Kind() == reflect.Struct && !seenTypes[fieldType.Name()] && fieldType.Name() != "" {
-			seenTypes[fieldType.Name()] = true
+		if fieldType.Kind() == reflect.Struct && !seenTypes[names.name(fieldType)] && fieldType.Name() != "" {
+			seenTypes[names.name(fieldType)] = true
 			// Recursively generate nested structs
-			generateAllStructs(fieldType, ts, seenTypes)
+			generateAllStructs(fieldType, ts, seenTypes, timeFormat, names)
 			// Generate the interface for this struct
-			ts.WriteString(`export interface ` + fieldType.Name() + " {\n")
-			ts.WriteString(generateStructSchema(fieldType))
-			ts.WriteString("}\n\n")
+			ts.WriteString(tsInterface(names.name(fieldType), typeDescription(fieldType), fieldType, timeFormat, names) + "\n\n")
 		}
 	}
 }
 
 // generateStructSchema generates TypeScript for a struct type
-func generateStructSchema(typ reflect.Type) string {
+func generateStructSchema(typ reflect.Type, timeFormat string, names *schemaNames) string {
 	var ts strings.Builder
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
 		fieldName := field.Name
 		fieldType := field.Type
 
-		tsType := goTypeToTsType(fieldType)
+		tsType := goTypeToTsType(fieldType, validation.FieldTimeFormat(&field, timeFormat), names)
+		if tag := field.Tag.Get("enum"); tag != "" {
+			tsType = tsEnumFieldType(fieldType, strings.Split(tag, ","))
+		}
 		jsonTag := field.Tag.Get("json")
 		if jsonTag != "" && jsonTag != "-" {
 			parts := strings.Split(jsonTag, ",")
@@ -96,13 +121,101 @@ func generateStructSchema(typ reflect.Type) string {
 			// Default to camelCase if no JSON tag
 			fieldName = strings.ToLower(fieldName[:1]) + fieldName[1:]
 		}
+		// Optional like the JSON Schema: omitempty and nullable fields aren't required
+		if strings.Contains(jsonTag, "omitempty") || validation.IsNullable(fieldType) {
+			fieldName += "?"
+		}
+		ts.WriteString(jsDoc("  ", fieldJSDocLines(&field, timeFormat, names)))
 		ts.WriteString("  " + fieldName + ": " + tsType + ";\n")
 	}
 	return ts.String()
 }
 
+// SchemaDescriber is implemented by body types describing themselves, e.g.
+//
+//	func (User) SchemaDescription() string { return "A registered user" }
+//
+// The description documents the type's component schema and TypeScript interface.
+type SchemaDescriber interface {
+	SchemaDescription() string
+}
+
+// typeDescription returns the SchemaDescription of a type, or ""
+func typeDescription(typ reflect.Type) string {
+	if describer, ok := reflect.New(typ).Interface().(SchemaDescriber); ok {
+		return describer.SchemaDescription()
+	}
+	return ""
+}
+
+// tsInterface renders a struct as a TypeScript interface with its JSDoc
+func tsInterface(name, description string, typ reflect.Type, timeFormat string, names *schemaNames) string {
+	var lines []string
+	if description != "" {
+		lines = append(lines, description)
+	}
+	return jsDoc("", lines) + "export interface " + name + " {\n" + generateStructSchema(typ, timeFormat, names) + "}"
+}
+
+// fieldJSDocLines returns the JSDoc of a field: its doc tag, format, example
+// and sensitive and deprecated flags
+func fieldJSDocLines(field *reflect.StructField, timeFormat string, names *schemaNames) []string {
+	var lines []string
+	schema := fieldToJSONSchema(field.Type, field.Name, make(map[string]*JSONSchema), timeFormat, names)
+	for schema.Type == "array" && schema.Items != nil {
+		schema = schema.Items
+	}
+	if doc := field.Tag.Get("doc"); doc != "" {
+		lines = append(lines, doc)
+	} else if schema.Description != "" {
+		lines = append(lines, schema.Description)
+	}
+	switch schema.Format {
+	case "", "int32", "int64", "float", "double":
+		// Numeric formats only restate the number type
+	default:
+		lines = append(lines, "@format "+schema.Format)
+	}
+	if example, ok := field.Tag.Lookup("example"); ok && !isSensitiveField(field) {
+		lines = append(lines, "@example "+example)
+	}
+	if isSensitiveField(field) {
+		lines = append(lines, "@sensitive Redacted in documentation examples")
+	}
+	if isDeprecatedField(field) {
+		lines = append(lines, "@deprecated")
+	}
+	return lines
+}
+
+// jsDoc renders a JSDoc block at the indent, on one line for a single line,
+// or "" without lines
+func jsDoc(indent string, lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	for i, line := range lines {
+		// A literal */ would end the comment early
+		lines[i] = strings.ReplaceAll(strings.ReplaceAll(line, "*/", "*\\/"), "\n", " ")
+	}
+	if len(lines) == 1 {
+		return indent + "/** " + lines[0] + " */\n"
+	}
+	return indent + "/**\n" + indent + " * " + strings.Join(lines, "\n"+indent+" * ") + "\n" + indent + " */\n"
+}
+
 // goTypeToTsType maps Go types to TypeScript types
-func goTypeToTsType(t reflect.Type) string {
+func goTypeToTsType(t reflect.Type, timeFormat string, names *schemaNames) string {
+	if _, ok := lookupStdType(t); ok {
+		return "string"
+	}
+	if validation.TypeEnumValues(t) != nil {
+		return names.name(t) // Declared by tsEnumDeclaration
+	}
+	if valueType, ok := validation.NullableValueType(t); ok {
+		return goTypeToTsType(valueType, timeFormat, names) + " | null"
+	}
+
 	switch t.Kind() {
 	case reflect.String:
 		return "string"
@@ -114,26 +227,39 @@ func goTypeToTsType(t reflect.Type) string {
 	case reflect.Bool:
 		return "boolean"
 	case reflect.Slice:
-		return goTypeToTsType(t.Elem()) + "[]"
+		elem := goTypeToTsType(t.Elem(), timeFormat, names)
+		if strings.Contains(elem, " | ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
 	case reflect.Ptr:
-		return goTypeToTsType(t.Elem())
+		elem := goTypeToTsType(t.Elem(), timeFormat, names)
+		if strings.HasSuffix(elem, " | null") {
+			return elem
+		}
+		return elem + " | null"
 	case reflect.Struct:
+		if t == timeType {
+			return timeTsType(timeFormat)
+		}
 		if t.Name() == "" {
-			return "any" // Anonymous structs
+			// Inline anonymous structs
+			return "{ " + strings.ReplaceAll(strings.TrimSpace(generateStructSchema(t, timeFormat, names)), "\n ", "") + " }"
 		}
-		return t.Name() // Named structs
+		return names.name(t) // Named structs
 	default:
 		return "any"
 	}
 }
 
 // generateJSONTemplate creates a JSON template from a Go struct schema
-func generateJSONTemplate(schema interface{}) (string, error) {
+func generateJSONTemplate(schema interface{}, opts exampleOptions) (string, error) {
 	if schema == nil {
 		return "{}", nil
 	}
 
-	template := generateJSONFromType(reflect.TypeOf(schema))
+	g := &exampleGenerator{opts: opts, visiting: make(map[reflect.Type]bool)}
+	template := g.jsonFromType(reflect.TypeOf(schema), opts.TimeFormat)
 	jsonBytes, err := json.MarshalIndent(template, "", "  ")
 	if err != nil {
 		return "{}", err
@@ -142,22 +268,109 @@ func generateJSONTemplate(schema interface{}) (string, error) {
 	return string(jsonBytes), nil
 }
 
-// generateJSONFromType recursively creates example JSON data from a reflect.Type
-func generateJSONFromType(t reflect.Type) interface{} {
+// exampleOptions controls generated JSON examples and try-it templates
+type exampleOptions struct {
+	TimeFormat  string
+	MaxDepth    int // Struct nesting depth before truncation, 0 for DefaultExampleMaxDepth
+	ArrayLength int // Elements generated per array, 0 for DefaultExampleArrayLength
+	Provider    ExampleProvider
+}
+
+// exampleOptions returns the example settings from the config
+func (an *ApiNote) exampleOptions() exampleOptions {
+	return exampleOptions{
+		TimeFormat:  an.config.TimeFormat,
+		MaxDepth:    an.config.ExampleMaxDepth,
+		ArrayLength: an.config.ExampleArrayLength,
+		Provider:    an.config.ExampleProvider,
+	}
+}
+
+// Defaults for Config.ExampleMaxDepth and Config.ExampleArrayLength
+const (
+	DefaultExampleMaxDepth    = 5
+	DefaultExampleArrayLength = 1
+)
+
+// exampleGenerator builds example values, truncating structs nested deeper than
+// MaxDepth or recursing into a type already being generated. Truncated structs
+// become {}, and pointers and arrays of them null and [].
+type exampleGenerator struct {
+	opts     exampleOptions
+	visiting map[reflect.Type]bool
+	depth    int
+}
+
+// truncates reports whether a struct of type t would be cut off at the current depth
+func (g *exampleGenerator) truncates(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	if _, ok := lookupStdType(t); ok {
+		return false
+	}
+	if _, ok := validation.NullableValueType(t); ok {
+		return false
+	}
+
+	maxDepth := g.opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultExampleMaxDepth
+	}
+	return g.depth >= maxDepth || g.visiting[t]
+}
+
+// arrayExample returns ArrayLength example elements of type elem
+func (g *exampleGenerator) arrayExample(elem reflect.Type, fieldName, timeFormat string) []interface{} {
+	if g.truncates(elem) {
+		return []interface{}{}
+	}
+
+	length := g.opts.ArrayLength
+	if length <= 0 {
+		length = DefaultExampleArrayLength
+	}
+	items := make([]interface{}, 0, length)
+	for i := 0; i < length; i++ {
+		items = append(items, g.exampleValue(elem, fieldName, timeFormat))
+	}
+	return items
+}
+
+// jsonFromType recursively creates example JSON data from a reflect.Type
+func (g *exampleGenerator) jsonFromType(t reflect.Type, timeFormat string) interface{} {
 	// Handle pointers
 	if t.Kind() == reflect.Ptr {
-		return generateJSONFromType(t.Elem())
+		if g.truncates(t) {
+			return nil
+		}
+		return g.jsonFromType(t.Elem(), timeFormat)
+	}
+
+	if std, ok := lookupStdType(t); ok {
+		return std.example
 	}
 
 	// Handle slices
 	if t.Kind() == reflect.Slice {
-		elemExample := generateJSONFromType(t.Elem())
-		return []interface{}{elemExample}
+		return g.arrayExample(t.Elem(), "", timeFormat)
 	}
 
 	// Handle structs
-	if t.Kind() == reflect.Struct {
+	if t.Kind() == reflect.Struct && t != timeType {
 		result := make(map[string]interface{})
+		if g.truncates(t) {
+			return result
+		}
+		g.depth++
+		g.visiting[t] = true
+		defer func() {
+			g.depth--
+			delete(g.visiting, t)
+		}()
 
 		for i := 0; i < t.NumField(); i++ {
 			field := t.Field(i)
@@ -168,56 +381,79 @@ func generateJSONFromType(t reflect.Type) interface{} {
 			}
 
 			// Get field name from JSON tag or use field name
-			fieldName := getJSONFieldName(&field)
+			fieldName := validation.JSONFieldName(&field)
 			if fieldName == "-" {
 				continue // Skip fields marked with json:"-"
 			}
 
-			// Generate example value for this field
-			result[fieldName] = generateExampleValue(field.Type, field.Name)
+			// Generate example value for this field. Sensitive fields are always masked;
+			// otherwise prefer example tags, declared enum values and then the
+			// configured provider over the built-in heuristics
+			if isSensitiveField(&field) {
+				result[fieldName] = maskedExample(field.Type)
+				continue
+			}
+			if value, ok := tagExample(&field); ok {
+				result[fieldName] = value
+				continue
+			}
+			if values := validation.EnumValues(&field); len(values) > 0 {
+				result[fieldName] = values[0]
+				continue
+			}
+			if g.opts.Provider != nil {
+				exampleField := ExampleField{Type: field.Type, Tag: field.Tag, Name: field.Name, JSONName: fieldName}
+				if value, ok := g.opts.Provider.Example(exampleField); ok {
+					result[fieldName] = value
+					continue
+				}
+			}
+			result[fieldName] = g.exampleValue(field.Type, field.Name, validation.FieldTimeFormat(&field, timeFormat))
 		}
 
 		return result
 	}
 
 	// For non-struct types, generate example values
-	return generateExampleValue(t, "")
+	return g.exampleValue(t, "", timeFormat)
 }
 
-// getJSONFieldName extracts the JSON field name from struct field tags
-func getJSONFieldName(field *reflect.StructField) string {
-	jsonTag := field.Tag.Get("json")
-	if jsonTag == "" {
-		// Convert to camelCase if no JSON tag
-		name := field.Name
-		return strings.ToLower(name[:1]) + name[1:]
+// exampleValue creates example values based on type and field name
+func (g *exampleGenerator) exampleValue(t reflect.Type, fieldName string, timeFormat string) interface{} {
+	// Handle pointers
+	if t.Kind() == reflect.Ptr {
+		if g.truncates(t) {
+			return nil
+		}
+		return g.exampleValue(t.Elem(), fieldName, timeFormat)
 	}
 
-	// Parse JSON tag (e.g., "field_name,omitempty")
-	parts := strings.Split(jsonTag, ",")
-	return parts[0]
-}
+	// Standard library types with a string representation (net.IP is a byte slice)
+	if std, ok := lookupStdType(t); ok {
+		return std.example
+	}
 
-// generateExampleValue creates example values based on type and field name
-func generateExampleValue(t reflect.Type, fieldName string) interface{} {
-	// Handle pointers
-	if t.Kind() == reflect.Ptr {
-		return generateExampleValue(t.Elem(), fieldName)
+	if values := validation.TypeEnumValues(t); len(values) > 0 {
+		return values[0]
+	}
+
+	// sql.Null* types are exampled with their value
+	if valueType, ok := validation.NullableValueType(t); ok {
+		return g.exampleValue(valueType, fieldName, timeFormat)
 	}
 
 	// Handle slices
 	if t.Kind() == reflect.Slice {
-		elemExample := generateExampleValue(t.Elem(), fieldName)
-		return []interface{}{elemExample}
+		return g.arrayExample(t.Elem(), fieldName, timeFormat)
 	}
 
 	// Handle structs
 	if t.Kind() == reflect.Struct {
 		// Special case for time.Time
-		if t == reflect.TypeOf(time.Time{}) {
-			return time.Now().Format(time.RFC3339)
+		if t == timeType {
+			return timeExample(timeFormat)
 		}
-		return generateJSONFromType(t)
+		return g.jsonFromType(t, timeFormat)
 	}
 
 	// Generate examples based on field name patterns and types
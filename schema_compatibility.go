@@ -0,0 +1,270 @@
+package notelink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ErrIncompatibleSchemas is returned by CheckSchemaCompatibility when a
+// component schema changed incompatibly
+var ErrIncompatibleSchemas = errors.New("notelink: incompatible schema changes")
+
+// CompatibilityMode tells which schema changes are compatible, named as in
+// schema registries
+type CompatibilityMode string
+
+const (
+	// CompatibilityNone accepts every change
+	CompatibilityNone CompatibilityMode = "NONE"
+	// CompatibilityBackward lets readers of the new schema read data of the
+	// old one: no required fields added, no fields made required, no enum
+	// values removed
+	CompatibilityBackward CompatibilityMode = "BACKWARD"
+	// CompatibilityForward lets readers of the old schema read data of the
+	// new one: no required fields removed or made optional, no enum values added
+	CompatibilityForward CompatibilityMode = "FORWARD"
+	// CompatibilityFull is both backward and forward compatible
+	CompatibilityFull CompatibilityMode = "FULL"
+)
+
+// backward reports whether the mode checks backward compatibility
+func (m CompatibilityMode) backward() bool {
+	return m == CompatibilityBackward || m == CompatibilityFull
+}
+
+// forward reports whether the mode checks forward compatibility
+func (m CompatibilityMode) forward() bool {
+	return m == CompatibilityForward || m == CompatibilityFull
+}
+
+// SchemaCompatibility checks the component schemas of the spec against the
+// previously published versions, e.g. in CI or before publishing (see
+// Config.SchemaCompatibility). Type changes are incompatible in every mode
+// but CompatibilityNone. Schemas no longer in the spec aren't checked.
+type SchemaCompatibility struct {
+	// Mode defaults to CompatibilityFull, as the schemas are both sent and received
+	Mode CompatibilityMode
+	// Modes overrides Mode per component schema, e.g. {"AuditEvent": CompatibilityBackward}
+	Modes map[string]CompatibilityMode
+	// BaselineURLs serve the published JSON specs to check against, e.g. the
+	// openapi.json uploaded by Config.PublishArtifacts; a 404 is skipped
+	BaselineURLs []string
+	// Baselines loads the published JSON specs instead of BaselineURLs, e.g.
+	// every version of the artifact in a schema registry
+	Baselines func(ctx context.Context) ([][]byte, error)
+	Client    *http.Client // Defaults to a client with a 30s timeout
+}
+
+// SchemaIncompatibility is an incompatible change of a component schema
+type SchemaIncompatibility struct {
+	Schema      string            `json:"schema"`
+	Field       string            `json:"field,omitempty"` // e.g. "address.zip" or "lines[].sku"
+	Description string            `json:"description"`
+	Mode        CompatibilityMode `json:"mode"`
+	Version     string            `json:"version"` // Info.Version of the spec checked against
+}
+
+// mode returns the compatibility mode of a component schema
+func (s *SchemaCompatibility) mode(schema string) CompatibilityMode {
+	if mode, ok := s.Modes[schema]; ok {
+		return mode
+	}
+	if s.Mode == "" {
+		return CompatibilityFull
+	}
+	return s.Mode
+}
+
+// baselines loads the published specs
+func (s *SchemaCompatibility) baselines(ctx context.Context) ([][]byte, error) {
+	if s.Baselines != nil {
+		return s.Baselines(ctx)
+	}
+	if len(s.BaselineURLs) == 0 {
+		return nil, errors.New("BaselineURLs or Baselines is required")
+	}
+	var specs [][]byte
+	for _, url := range s.BaselineURLs {
+		data, err := fetchSpec(ctx, s.Client, url)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", url, err)
+		}
+		if data != nil {
+			specs = append(specs, data)
+		}
+	}
+	return specs, nil
+}
+
+// Check lists the incompatible changes of the component schemas from the old
+// spec to the new one
+func (s *SchemaCompatibility) Check(old, new *OpenAPISpec) []SchemaIncompatibility {
+	if old.Components == nil || new.Components == nil {
+		return nil
+	}
+	names := make([]string, 0, len(old.Components.Schemas))
+	for name := range old.Components.Schemas {
+		if _, ok := new.Components.Schemas[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var incompatibilities []SchemaIncompatibility
+	for _, name := range names {
+		mode := s.mode(name)
+		if mode == CompatibilityNone {
+			continue
+		}
+		c := &compatibilityChecker{old: old, new: new, schema: name, mode: mode, version: old.Info.Version}
+		c.compare("", old.Components.Schemas[name], new.Components.Schemas[name])
+		incompatibilities = append(incompatibilities, c.incompatibilities...)
+	}
+	return incompatibilities
+}
+
+// compatibilityChecker collects the incompatible changes of a component schema
+type compatibilityChecker struct {
+	old, new          *OpenAPISpec
+	schema            string
+	mode              CompatibilityMode
+	version           string
+	incompatibilities []SchemaIncompatibility
+	seen              map[[2]*JSONSchema]bool
+}
+
+func (c *compatibilityChecker) add(field, format string, args ...interface{}) {
+	c.incompatibilities = append(c.incompatibilities, SchemaIncompatibility{
+		Schema:      c.schema,
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+		Mode:        c.mode,
+		Version:     c.version,
+	})
+}
+
+// compare checks a schema and its fields; path names the field within the
+// component, "" for the component itself
+func (c *compatibilityChecker) compare(path string, old, new *JSONSchema) {
+	// Other components are checked on their own, unless a field now refers to
+	// a different one
+	if path != "" && old != nil && new != nil && old.Ref != "" && old.Ref == new.Ref {
+		return
+	}
+	old, new = resolveSchemaRef(c.old, old), resolveSchemaRef(c.new, new)
+	if old == nil || new == nil {
+		return
+	}
+	pair := [2]*JSONSchema{old, new}
+	if c.seen[pair] {
+		return
+	}
+	if c.seen == nil {
+		c.seen = map[[2]*JSONSchema]bool{}
+	}
+	c.seen[pair] = true
+	defer delete(c.seen, pair)
+
+	if old.Type != "" && new.Type != "" && old.Type != new.Type {
+		c.add(path, "type changed from %s to %s", old.Type, new.Type)
+		return
+	}
+	if len(old.Enum) > 0 && len(new.Enum) > 0 {
+		if removed := missingValues(old.Enum, new.Enum); len(removed) > 0 && c.mode.backward() {
+			c.add(path, "no longer allows %s", strings.Join(removed, ", "))
+		}
+		if added := missingValues(new.Enum, old.Enum); len(added) > 0 && c.mode.forward() {
+			c.add(path, "now allows %s", strings.Join(added, ", "))
+		}
+	}
+
+	field := func(property string) string {
+		if path == "" {
+			return property
+		}
+		return path + "." + property
+	}
+	oldRequired, newRequired := stringSet(old.Required), stringSet(new.Required)
+	properties := make([]string, 0, len(old.Properties)+len(new.Properties))
+	for property := range old.Properties {
+		properties = append(properties, property)
+	}
+	for property := range new.Properties {
+		if _, ok := old.Properties[property]; !ok {
+			properties = append(properties, property)
+		}
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		oldProp, inOld := old.Properties[property]
+		newProp, inNew := new.Properties[property]
+		switch {
+		case !inOld:
+			if newRequired[property] && c.mode.backward() {
+				c.add(field(property), "required field added")
+			}
+		case !inNew:
+			if oldRequired[property] && c.mode.forward() {
+				c.add(field(property), "required field removed")
+			}
+		default:
+			if newRequired[property] && !oldRequired[property] && c.mode.backward() {
+				c.add(field(property), "field is now required")
+			}
+			if oldRequired[property] && !newRequired[property] && c.mode.forward() {
+				c.add(field(property), "field is now optional")
+			}
+			c.compare(field(property), oldProp, newProp)
+		}
+	}
+	if old.Items != nil && new.Items != nil {
+		c.compare(path+"[]", old.Items, new.Items)
+	}
+}
+
+// CheckSchemaCompatibility checks the component schemas of the current spec
+// against every published spec of the checker and returns the incompatible
+// changes, with an error wrapping ErrIncompatibleSchemas when there are any.
+// Nothing is checked when nothing was published yet.
+func (an *ApiNote) CheckSchemaCompatibility(ctx context.Context, checker *SchemaCompatibility) ([]SchemaIncompatibility, error) {
+	baselines, err := checker.baselines(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading the published specs: %w", err)
+	}
+	current := an.GenerateOpenAPISpec()
+	var incompatibilities []SchemaIncompatibility
+	for _, data := range baselines {
+		var baseline OpenAPISpec
+		if err := json.Unmarshal(data, &baseline); err != nil {
+			return nil, fmt.Errorf("parsing a published spec: %w", err)
+		}
+		incompatibilities = append(incompatibilities, checker.Check(&baseline, current)...)
+	}
+	if len(incompatibilities) > 0 {
+		return incompatibilities, fmt.Errorf("%w: %s", ErrIncompatibleSchemas, SchemaIncompatibilitySummary(incompatibilities))
+	}
+	return nil, nil
+}
+
+// SchemaIncompatibilitySummary describes incompatible changes for people, one
+// per line, e.g. for CI output
+func SchemaIncompatibilitySummary(incompatibilities []SchemaIncompatibility) string {
+	lines := make([]string, len(incompatibilities))
+	for i, incompatibility := range incompatibilities {
+		name := incompatibility.Schema
+		if incompatibility.Field != "" {
+			name += "." + incompatibility.Field
+		}
+		version := incompatibility.Version
+		if version == "" {
+			version = "the published spec"
+		}
+		lines[i] = fmt.Sprintf("%s: %s (%s against %s)", name, incompatibility.Description, incompatibility.Mode, version)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,147 @@
+package notelink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// compatibilityAPI documents the same component schemas in their first or
+// second version; the types are declared per version so they share names
+func compatibilityAPI(t *testing.T, v2 bool) *ApiNote {
+	t.Helper()
+	api := NewApiNote(&Config{Title: "Orders API", Version: "1.0.0", Host: "api.example.com"}, "secret")
+	route := &DocumentedRouteInput{Method: "POST", Path: "/orders", Handler: func(c fiber.Ctx) error { return nil }}
+	if v2 {
+		type compatAddress struct {
+			City string `json:"city,omitempty"`
+		}
+		type compatOrder struct {
+			ID      string        `json:"id"`
+			Status  string        `json:"status" enum:"open,closed,refunded"`
+			Channel string        `json:"channel"`
+			Address compatAddress `json:"address"`
+		}
+		route.SchemasRequest = compatOrder{}
+	} else {
+		type compatAddress struct {
+			City string `json:"city"`
+		}
+		type compatOrder struct {
+			ID      int           `json:"id"`
+			Status  string        `json:"status" enum:"open,closed,pending"`
+			Note    string        `json:"note"`
+			Address compatAddress `json:"address"`
+		}
+		route.SchemasRequest = compatOrder{}
+	}
+	if err := api.DocumentedRoute(route); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	return api
+}
+
+// TestSchemaCompatibility tests detecting incompatible schema changes per mode
+func TestSchemaCompatibility(t *testing.T) {
+	v1, v2 := compatibilityAPI(t, false).GenerateOpenAPISpec(), compatibilityAPI(t, true).GenerateOpenAPISpec()
+
+	tests := []struct {
+		name  string
+		check SchemaCompatibility
+		want  []string
+	}{
+		{
+			name:  "full by default",
+			check: SchemaCompatibility{},
+			want: []string{
+				"compatAddress.city: field is now optional",
+				"compatOrder.channel: required field added",
+				"compatOrder.id: type changed from integer to string",
+				"compatOrder.note: required field removed",
+				`compatOrder.status: no longer allows "pending"`,
+				`compatOrder.status: now allows "refunded"`,
+			},
+		},
+		{
+			name:  "backward",
+			check: SchemaCompatibility{Mode: CompatibilityBackward},
+			want: []string{
+				"compatOrder.channel: required field added",
+				"compatOrder.id: type changed from integer to string",
+				`compatOrder.status: no longer allows "pending"`,
+			},
+		},
+		{
+			name:  "forward",
+			check: SchemaCompatibility{Mode: CompatibilityForward},
+			want: []string{
+				"compatAddress.city: field is now optional",
+				"compatOrder.id: type changed from integer to string",
+				"compatOrder.note: required field removed",
+				`compatOrder.status: now allows "refunded"`,
+			},
+		},
+		{
+			name:  "per schema",
+			check: SchemaCompatibility{Mode: CompatibilityBackward, Modes: map[string]CompatibilityMode{"compatOrder": CompatibilityNone, "compatAddress": CompatibilityForward}},
+			want:  []string{"compatAddress.city: field is now optional"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, incompatibility := range tt.check.Check(v1, v2) {
+				got = append(got, incompatibility.Schema+"."+incompatibility.Field+": "+incompatibility.Description)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected %q, got %q", tt.want[i], got[i])
+				}
+			}
+		})
+	}
+
+	if same := (&SchemaCompatibility{}).Check(v2, v2); len(same) != 0 {
+		t.Errorf("Expected equal schemas to be compatible, got %v", same)
+	}
+}
+
+// TestCheckSchemaCompatibility tests checking against the published specs
+func TestCheckSchemaCompatibility(t *testing.T) {
+	published, err := json.Marshal(compatibilityAPI(t, false).GenerateOpenAPISpec())
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/1.0.0/openapi.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(published) //nolint:errcheck // test server
+	}))
+	defer server.Close()
+
+	api := compatibilityAPI(t, true)
+	incompatibilities, err := api.CheckSchemaCompatibility(context.Background(), &SchemaCompatibility{
+		Mode:         CompatibilityBackward,
+		BaselineURLs: []string{server.URL + "/1.0.0/openapi.json", server.URL + "/0.9.0/openapi.json"},
+	})
+	if !errors.Is(err, ErrIncompatibleSchemas) || len(incompatibilities) != 3 || incompatibilities[0].Version != "1.0.0" {
+		t.Errorf("Expected the incompatible changes against 1.0.0, got %v (%v)", incompatibilities, err)
+	}
+
+	incompatibilities, err = api.CheckSchemaCompatibility(context.Background(), &SchemaCompatibility{
+		Baselines: func(ctx context.Context) ([][]byte, error) { return nil, nil },
+	})
+	if err != nil || len(incompatibilities) != 0 {
+		t.Errorf("Expected nothing to check before the first publish, got %v (%v)", incompatibilities, err)
+	}
+}
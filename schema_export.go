@@ -0,0 +1,126 @@
+package notelink
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// jsonSchemaDialect is the JSON Schema draft of the standalone schemas
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// standaloneSchema returns a component schema of the spec as a standalone
+// JSON Schema: the components it references are embedded under $defs and
+// referenced there, so validators need nothing but the document. False when
+// there is no such component.
+func standaloneSchema(schemas map[string]interface{}, name, id string) (map[string]interface{}, bool) {
+	root, ok := schemas[name].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	defs := make(map[string]interface{})
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, ref := range collectSchemaRefs(schemas[current], nil) {
+			if _, seen := defs[ref]; seen || ref == name {
+				continue
+			}
+			if def, ok := schemas[ref]; ok {
+				defs[ref] = def
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	document := make(map[string]interface{}, len(root)+3)
+	for key, value := range root {
+		document[key] = value
+	}
+	document["$schema"] = jsonSchemaDialect
+	document["$id"] = id
+	if len(defs) > 0 {
+		document["$defs"] = defs
+	}
+	return rewriteSchemaRefs(document, name).(map[string]interface{}), true
+}
+
+// collectSchemaRefs appends the component schemas a schema references
+func collectSchemaRefs(value interface{}, refs []string) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, schemaRefPrefix) {
+			refs = append(refs, strings.TrimPrefix(ref, schemaRefPrefix))
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			refs = collectSchemaRefs(v[key], refs)
+		}
+	case []interface{}:
+		for _, item := range v {
+			refs = collectSchemaRefs(item, refs)
+		}
+	}
+	return refs
+}
+
+// rewriteSchemaRefs copies a schema, pointing its component $refs at $defs,
+// or at the document itself for the root component
+func rewriteSchemaRefs(value interface{}, rootName string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		rewritten := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			rewritten[key] = rewriteSchemaRefs(item, rootName)
+		}
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, schemaRefPrefix) {
+			if name := strings.TrimPrefix(ref, schemaRefPrefix); name == rootName {
+				rewritten["$ref"] = "#"
+			} else {
+				rewritten["$ref"] = "#/$defs/" + name
+			}
+		}
+		return rewritten
+	case []interface{}:
+		rewritten := make([]interface{}, len(v))
+		for i, item := range v {
+			rewritten[i] = rewriteSchemaRefs(item, rootName)
+		}
+		return rewritten
+	}
+	return value
+}
+
+// schemaHandler serves a component schema of the spec as a standalone JSON
+// Schema (draft 2020-12) at /schemas/<name>.json, for services and message
+// validators reusing the schemas derived from the Go types
+func (an *ApiNote) schemaHandler(c fiber.Ctx) error {
+	data, err := an.cachedOpenAPISpec(an.baseURL(c), an.localeOf(c), false)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).SendString("Error marshaling OpenAPI spec")
+	}
+	var spec struct {
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+
+	name := c.Params("name")
+	schema, ok := standaloneSchema(spec.Components.Schemas, name, an.baseURL(c)+an.docsPath()+"/schemas/"+name+".json")
+	if !ok {
+		return c.Status(http.StatusNotFound).JSON(ErrorResponse{Error: "Unknown schema " + name})
+	}
+	return c.JSON(schema, "application/schema+json")
+}
@@ -0,0 +1,82 @@
+package notelink
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestSchemaExport tests serving component schemas as standalone JSON Schemas
+func TestSchemaExport(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Orders API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "GET",
+		Path:            "/orders",
+		Handler:         func(c fiber.Ctx) error { return nil },
+		Responses:       map[string]string{"200": "Orders"},
+		SchemasResponse: []tsModuleOrder{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		status int
+		refs   map[string]string // Property -> expected $ref
+		defs   []string
+	}{
+		{name: "tsModuleOrder", status: 200, refs: map[string]string{"customer": "#/$defs/tsModuleCustomer"}, defs: []string{"tsModuleCustomer", "tsModuleAddress"}},
+		{name: "tsModuleCustomer", status: 200, refs: map[string]string{"address": "#/$defs/tsModuleAddress"}, defs: []string{"tsModuleAddress"}},
+		{name: "tsModuleAddress", status: 200},
+		{name: "Missing", status: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/schemas/"+tt.name+".json", nil))
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected %d, got %d: %s", tt.status, resp.StatusCode, body)
+			}
+			if tt.status != 200 {
+				return
+			}
+			if contentType := resp.Header.Get("Content-Type"); contentType != "application/schema+json" {
+				t.Errorf("Expected a JSON Schema content type, got %q", contentType)
+			}
+
+			var schema struct {
+				Schema     string                            `json:"$schema"`
+				ID         string                            `json:"$id"`
+				Properties map[string]map[string]interface{} `json:"properties"`
+				Defs       map[string]json.RawMessage        `json:"$defs"`
+			}
+			if err := json.Unmarshal(body, &schema); err != nil {
+				t.Fatalf("Expected JSON: %v", err)
+			}
+			if schema.Schema != "https://json-schema.org/draft/2020-12/schema" || schema.ID != "http://localhost:8080/api-docs/schemas/"+tt.name+".json" {
+				t.Errorf("Expected the draft 2020-12 dialect and the schema's URL, got %q and %q", schema.Schema, schema.ID)
+			}
+			for property, ref := range tt.refs {
+				if got := schema.Properties[property]["$ref"]; got != ref {
+					t.Errorf("Expected %s to reference %s, got %v", property, ref, got)
+				}
+			}
+			if len(schema.Defs) != len(tt.defs) {
+				t.Errorf("Expected $defs %v, got %s", tt.defs, body)
+			}
+			for _, def := range tt.defs {
+				if _, ok := schema.Defs[def]; !ok {
+					t.Errorf("Expected %s in $defs, got %s", def, body)
+				}
+			}
+		})
+	}
+}
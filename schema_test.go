@@ -1,8 +1,8 @@
 package notelink
 
 import (
+	"bytes"
 	"encoding/json"
-	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -42,6 +42,14 @@ type UserWithPointers struct {
 	Name  string  `json:"name"`
 }
 
+type UserWithOptionalFields struct {
+	Nickname string    `json:"nickname,omitempty"`
+	Tags     []*string `json:"tags"`
+	Manager  *struct {
+		Name string `json:"name"`
+	} `json:"manager"`
+}
+
 type UserWithTimeFields struct {
 	CreatedAt time.Time `json:"created_at"`
 	Name      string    `json:"name"`
@@ -103,9 +111,19 @@ func TestGenerateTypeScriptSchema(t *testing.T) {
 			schemaName: "User",
 			schema:     UserWithPointers{},
 			expectedFields: []string{
-				"name: string",
-				"email: string",
-				"age: number",
+				"name: string;",
+				"email?: string | null;",
+				"age?: number | null;",
+			},
+		},
+		{
+			name:       "Struct with optional fields",
+			schemaName: "User",
+			schema:     UserWithOptionalFields{},
+			expectedFields: []string{
+				"nickname?: string;",
+				"tags: (string | null)[];",
+				"manager?: { name: string; } | null;",
 			},
 		},
 		{
@@ -118,7 +136,7 @@ func TestGenerateTypeScriptSchema(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := generateTypeScriptSchema(tt.schemaName, tt.schema)
+			result := generateTypeScriptSchema(tt.schemaName, tt.schema, "", nil)
 
 			// Check expected fields
 			for _, field := range tt.expectedFields {
@@ -139,7 +157,7 @@ func TestGenerateTypeScriptSchema(t *testing.T) {
 
 // TestGenerateTypeScriptSchemaArray tests TypeScript schema generation for arrays
 func TestGenerateTypeScriptSchemaArray(t *testing.T) {
-	result := generateTypeScriptSchema("UserList", []SimpleUser{})
+	result := generateTypeScriptSchema("UserList", []SimpleUser{}, "", nil)
 
 	if !strings.Contains(result, "export interface UserList") {
 		t.Errorf("Expected interface name UserList, got:\n%s", result)
@@ -207,7 +225,7 @@ func TestGenerateJSONTemplate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := generateJSONTemplate(tt.schema)
+			result, err := generateJSONTemplate(tt.schema, exampleOptions{})
 			if err != nil {
 				t.Fatalf("Unexpected error: %v", err)
 			}
@@ -232,7 +250,7 @@ func TestGenerateJSONTemplate(t *testing.T) {
 
 // TestGenerateJSONTemplateArray tests JSON template generation for arrays
 func TestGenerateJSONTemplateArray(t *testing.T) {
-	result, err := generateJSONTemplate([]SimpleUser{})
+	result, err := generateJSONTemplate([]SimpleUser{}, exampleOptions{})
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -378,48 +396,9 @@ func TestGenerateBoolExample(t *testing.T) {
 	}
 }
 
-// TestGetJSONFieldName tests JSON field name extraction
-func TestGetJSONFieldName(t *testing.T) {
-	tests := []struct {
-		name      string
-		fieldName string
-		jsonTag   string
-		expected  string
-	}{
-		{"With json tag", "UserID", `json:"user_id"`, "user_id"},
-		{"With omitempty", "Email", `json:"email,omitempty"`, "email"},
-		{"Skip field", "Internal", `json:"-"`, "-"},
-		{"No json tag", "UserName", "", "userName"},
-		{"Empty json tag", "Field", ``, "field"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			type TestStruct struct {
-				Field string
-			}
-
-			structType := new(TestStruct)
-			field := reflect.TypeOf(structType).Elem().Field(0)
-
-			// Create a mock field with the desired tag
-			mockField := reflect.StructField{
-				Name: tt.fieldName,
-				Type: field.Type,
-				Tag:  reflect.StructTag(tt.jsonTag),
-			}
-
-			result := getJSONFieldName(&mockField)
-			if result != tt.expected {
-				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
-			}
-		})
-	}
-}
-
 // TestGenerateJSONTemplateWithTime tests JSON template generation with time fields
 func TestGenerateJSONTemplateWithTime(t *testing.T) {
-	result, err := generateJSONTemplate(UserWithTimeFields{})
+	result, err := generateJSONTemplate(UserWithTimeFields{}, exampleOptions{})
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -457,7 +436,7 @@ func TestComplexNestedStructure(t *testing.T) {
 	}
 
 	// Test TypeScript generation
-	tsSchema := generateTypeScriptSchema("Root", Level1{})
+	tsSchema := generateTypeScriptSchema("Root", Level1{}, "", nil)
 
 	expectedInterfaces := []string{
 		"export interface Level3",
@@ -475,7 +454,7 @@ func TestComplexNestedStructure(t *testing.T) {
 	}
 
 	// Test JSON template generation
-	jsonTemplate, err := generateJSONTemplate(Level1{})
+	jsonTemplate, err := generateJSONTemplate(Level1{}, exampleOptions{})
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -490,3 +469,81 @@ func TestComplexNestedStructure(t *testing.T) {
 		t.Errorf("Expected 'level2' field in JSON template")
 	}
 }
+
+type TreeNode struct {
+	Name     string     `json:"name"`
+	Parent   *TreeNode  `json:"parent,omitempty"`
+	Children []TreeNode `json:"children"`
+}
+
+// TestExampleDepthControl tests truncation of deep and self-referential examples
+func TestExampleDepthControl(t *testing.T) {
+	type Level3 struct {
+		Value string `json:"value"`
+	}
+	type Level2 struct {
+		Level3 Level3 `json:"level3"`
+	}
+	type Level1 struct {
+		Level2 Level2   `json:"level2"`
+		Tags   []string `json:"tags"`
+	}
+
+	tests := []struct {
+		name     string
+		schema   interface{}
+		opts     exampleOptions
+		expected string
+	}{
+		{
+			name:     "Self-referential type",
+			schema:   TreeNode{},
+			expected: `{"children":[],"name":"John Doe","parent":null}`,
+		},
+		{
+			name:     "Max depth",
+			schema:   Level1{},
+			opts:     exampleOptions{MaxDepth: 2},
+			expected: `{"level2":{"level3":{}},"tags":["example_value"]}`,
+		},
+		{
+			name:     "Within default depth",
+			schema:   Level1{},
+			expected: `{"level2":{"level3":{"value":"example_value"}},"tags":["example_value"]}`,
+		},
+		{
+			name:     "Array length",
+			schema:   Level1{},
+			opts:     exampleOptions{MaxDepth: 1, ArrayLength: 3},
+			expected: `{"level2":{},"tags":["example_value","example_value","example_value"]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := generateJSONTemplate(tt.schema, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, []byte(result)); err != nil {
+				t.Fatalf("Result is not valid JSON: %v", err)
+			}
+			if compact.String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, compact.String())
+			}
+		})
+	}
+
+	// Schemas and TypeScript reference the recursive type instead of expanding it
+	schema, components := generateJSONSchema("Tree", TreeNode{}, "", nil)
+	if schema.Properties["parent"].Ref != "#/components/schemas/TreeNode" {
+		t.Errorf("Expected parent to reference TreeNode, got %+v", schema.Properties["parent"])
+	}
+	if node := components["TreeNode"]; node == nil || node.Properties["children"] == nil {
+		t.Errorf("Expected a complete TreeNode component, got %+v", node)
+	}
+	if ts := generateTypeScriptSchema("Tree", TreeNode{}, "", nil); !strings.Contains(ts, "children: TreeNode[];") {
+		t.Errorf("Unexpected TypeScript:\n%s", ts)
+	}
+}
@@ -0,0 +1,118 @@
+package notelink
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// schemaViewJSON returns the indented JSON Schema for a body type. Nested
+// structs are referenced as #/components/schemas/..., so the referenced
+// component schemas are embedded under "components" to keep the document
+// self-contained.
+func schemaViewJSON(name string, schema interface{}, timeFormat string, names *schemaNames) string {
+	mainSchema, componentSchemas := generateJSONSchema(name, schema, timeFormat, names)
+	data, err := json.Marshal(mainSchema)
+	if err != nil {
+		return "{}"
+	}
+
+	var document map[string]interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return "{}"
+	}
+	if len(componentSchemas) > 0 {
+		document["components"] = map[string]interface{}{"schemas": componentSchemas}
+	}
+	return exampleJSON(document)
+}
+
+// renderSchemaViews renders a request or response body schema with a toggle
+// between the TypeScript interface, the JSON Schema and a generated example
+func renderSchemaViews(title, name string, schema interface{}, opts exampleOptions, names *schemaNames) string {
+	ts := generateTypeScriptSchema(name, schema, opts.TimeFormat, names)
+	if ts == "" {
+		return ""
+	}
+	example, err := generateJSONTemplate(schema, opts)
+	if err != nil {
+		example = "{}"
+	}
+
+	views := []struct {
+		id, label, content string
+	}{
+		{id: "typescript", label: "TypeScript", content: ts},
+		{id: "json-schema", label: "JSON Schema", content: schemaViewJSON(name, schema, opts.TimeFormat, names)},
+		{id: "example", label: "Example", content: example},
+	}
+
+	var html strings.Builder
+	html.WriteString(`
+                        <div class="schema-views copyable">
+                            <div class="schema-views-header">
+                                <h5>` + escapeHTML(title) + `:</h5>
+                                <div class="schema-view-tabs" role="tablist">`)
+	for i, view := range views {
+		class, selected := "schema-view-tab", "false"
+		if i == 0 {
+			class, selected = "schema-view-tab active", "true"
+		}
+		html.WriteString(`
+                                    <button type="button" class="` + class + `" data-view="` + view.id + `" role="tab" aria-selected="` + selected + `" onclick="showSchemaView(this)">` + view.label + `</button>`)
+	}
+	html.WriteString(`
+                                </div>
+                                ` + copyButtonMarkup + `
+                            </div>`)
+	for i, view := range views {
+		hidden := ""
+		if i > 0 {
+			hidden = " hidden"
+		}
+		html.WriteString(`
+                            <pre class="schema-view" data-view="` + view.id + `"` + hidden + `>` + escapeHTML(view.content) + `</pre>`)
+	}
+	html.WriteString(`
+                        </div>`)
+	return html.String()
+}
+
+// schemaViewsScript switches between the views rendered by renderSchemaViews and
+// remembers the last choice so every endpoint opens in the preferred view
+const schemaViewsScript = `
+            // Schema view toggle (TypeScript / JSON Schema / Example)
+            function showSchemaView(button, remember = true) {
+                const container = button.closest('.schema-views');
+                const view = button.dataset.view;
+                container.querySelectorAll('.schema-view-tab').forEach(tab => {
+                    const active = tab.dataset.view === view;
+                    tab.classList.toggle('active', active);
+                    tab.setAttribute('aria-selected', String(active));
+                });
+                container.querySelectorAll('.schema-view').forEach(pre => {
+                    pre.hidden = pre.dataset.view !== view;
+                });
+                if (remember) {
+                    try {
+                        localStorage.setItem('notelink.schemaView', view);
+                    } catch (e) {
+                        // Preference just won't persist
+                    }
+                }
+            }
+
+            document.addEventListener('DOMContentLoaded', function() {
+                let preferred = null;
+                try {
+                    preferred = localStorage.getItem('notelink.schemaView');
+                } catch (e) {
+                    return;
+                }
+                if (!preferred) {
+                    return;
+                }
+                document.querySelectorAll('.schema-view-tab[data-view="' + CSS.escape(preferred) + '"]').forEach(tab => {
+                    showSchemaView(tab, false);
+                });
+            });
+`
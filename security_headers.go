@@ -0,0 +1,216 @@
+package notelink
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	// DefaultHSTSMaxAge is the max-age of the Strict-Transport-Security header
+	DefaultHSTSMaxAge = 365 * 24 * time.Hour
+	// DefaultReferrerPolicy is the Referrer-Policy of all responses
+	DefaultReferrerPolicy = "strict-origin-when-cross-origin"
+	// DefaultAPIContentSecurityPolicy locks down API responses, which browsers
+	// never need to render as pages
+	DefaultAPIContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+)
+
+// SecurityHeadersConfig tunes the security headers set on every response,
+// e.g. per deployment. They are on by default; see Config.DisableSecurityHeaders.
+//
+// Responses get X-Content-Type-Options: nosniff, X-Frame-Options,
+// Referrer-Policy and, when served over HTTPS, Strict-Transport-Security. The
+// docs pages get a Content-Security-Policy allowing their inline scripts and
+// configured assets, and the try-it client to reach the API's servers; other
+// responses get DefaultAPIContentSecurityPolicy. Handlers may override any
+// of them with c.Set.
+type SecurityHeadersConfig struct {
+	HSTSMaxAge            time.Duration // Defaults to DefaultHSTSMaxAge; negative omits the header
+	HSTSIncludeSubdomains bool
+	HSTSPreload           bool
+	FrameOptions          string // "DENY" (default) or "SAMEORIGIN", e.g. to embed the docs in a portal
+	ReferrerPolicy        string // Defaults to DefaultReferrerPolicy
+	// DocsCSP replaces the generated policy of the docs pages
+	DocsCSP string
+	// APICSP replaces DefaultAPIContentSecurityPolicy
+	APICSP string
+	// ConnectSources are extra origins the try-it client may call, e.g.
+	// per-tenant hosts, added to the docs policy's connect-src
+	ConnectSources []string
+}
+
+// hstsValue returns the Strict-Transport-Security header, or "" to omit it
+func (s *SecurityHeadersConfig) hstsValue() string {
+	maxAge := s.HSTSMaxAge
+	if maxAge < 0 {
+		return ""
+	}
+	if maxAge == 0 {
+		maxAge = DefaultHSTSMaxAge
+	}
+	value := "max-age=" + strconv.Itoa(int(maxAge.Seconds()))
+	if s.HSTSIncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if s.HSTSPreload {
+		value += "; preload"
+	}
+	return value
+}
+
+// frameOptions returns the X-Frame-Options header
+func (s *SecurityHeadersConfig) frameOptions() string {
+	if s.FrameOptions == "" {
+		return "DENY"
+	}
+	return strings.ToUpper(s.FrameOptions)
+}
+
+// frameAncestors returns the CSP frame-ancestors source matching X-Frame-Options
+func (s *SecurityHeadersConfig) frameAncestors() string {
+	if s.frameOptions() == "SAMEORIGIN" {
+		return "'self'"
+	}
+	return "'none'"
+}
+
+// securityHeaders returns Config.SecurityHeaders, or the defaults
+func (an *ApiNote) securityHeaders() *SecurityHeadersConfig {
+	if an.config.SecurityHeaders != nil {
+		return an.config.SecurityHeaders
+	}
+	return &SecurityHeadersConfig{}
+}
+
+// mountSecurityHeaders sets the security headers on every response
+func (an *ApiNote) mountSecurityHeaders() {
+	cfg := an.securityHeaders()
+	referrerPolicy := cfg.ReferrerPolicy
+	if referrerPolicy == "" {
+		referrerPolicy = DefaultReferrerPolicy
+	}
+	apiCSP := cfg.APICSP
+	if apiCSP == "" {
+		apiCSP = DefaultAPIContentSecurityPolicy
+	}
+	hsts := cfg.hstsValue()
+	docsPath := an.docsPath()
+
+	an.app.Use(func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+		c.Set(fiber.HeaderXFrameOptions, cfg.frameOptions())
+		c.Set(fiber.HeaderReferrerPolicy, referrerPolicy)
+		if hsts != "" && strings.HasPrefix(an.baseURL(c), "https://") {
+			c.Set(fiber.HeaderStrictTransportSecurity, hsts)
+		}
+		if !an.config.DisableDocsRoutes && (c.Path() == docsPath || strings.HasPrefix(c.Path(), docsPath+"/")) {
+			c.Set(fiber.HeaderContentSecurityPolicy, an.docsContentSecurityPolicy(c))
+			if embed := an.config.EmbedMode; embed != nil {
+				// X-Frame-Options cannot name the portal's origins, frame-ancestors does
+				if len(embed.ParentOrigins) > 0 {
+					c.Response().Header.Del(fiber.HeaderXFrameOptions)
+				} else {
+					c.Set(fiber.HeaderXFrameOptions, "SAMEORIGIN")
+				}
+			}
+		} else {
+			c.Set(fiber.HeaderContentSecurityPolicy, apiCSP)
+		}
+		return c.Next()
+	})
+}
+
+// docsContentSecurityPolicy returns the policy of the docs pages: their
+// inline scripts and styles, the configured assets' origins, and the API's
+// servers for the try-it client
+func (an *ApiNote) docsContentSecurityPolicy(c fiber.Ctx) string {
+	cfg := an.securityHeaders()
+	if cfg.DocsCSP != "" {
+		return cfg.DocsCSP
+	}
+
+	scripts := map[string]bool{"'self'": true, "'unsafe-inline'": true}
+	styles := map[string]bool{"'self'": true, "'unsafe-inline'": true}
+	fonts := map[string]bool{"'self'": true, "data:": true, "https://fonts.gstatic.com": true, "https://fonts.scalar.com": true}
+	for name, def := range defaultAssets {
+		source := def.url
+		if configured := an.config.Assets[name].URL; configured != "" {
+			source = configured
+		}
+		origin := sourceOrigin(source)
+		if origin == "" {
+			continue
+		}
+		if def.stylesheet {
+			styles[origin] = true
+			// Icon fonts load from the stylesheet's origin
+			fonts[origin] = true
+		} else {
+			scripts[origin] = true
+		}
+	}
+
+	connect := map[string]bool{"'self'": true}
+	if origin := sourceOrigin(an.baseURL(c)); origin != "" {
+		connect[origin] = true
+	}
+	for _, server := range an.config.Servers {
+		if origin := sourceOrigin(server.URL); origin != "" {
+			connect[origin] = true
+		} else if scheme, _, ok := strings.Cut(server.URL, "://"); ok && (scheme == "http" || scheme == "https") {
+			// Templated hosts can only be allowed by scheme
+			connect[scheme+":"] = true
+		}
+	}
+	for _, source := range cfg.ConnectSources {
+		connect[source] = true
+	}
+
+	frameAncestors := cfg.frameAncestors()
+	if an.config.EmbedMode != nil {
+		frameAncestors = an.config.EmbedMode.frameAncestors()
+	}
+
+	return "default-src 'self'" +
+		"; script-src " + sortedSources(scripts) +
+		"; style-src " + sortedSources(styles) +
+		"; font-src " + sortedSources(fonts) +
+		"; img-src 'self' data: https:" +
+		"; connect-src " + sortedSources(connect) +
+		"; frame-ancestors " + frameAncestors +
+		"; base-uri 'self'; form-action 'self'; object-src 'none'"
+}
+
+// sourceOrigin returns the scheme and host of an absolute http(s) URL, or ""
+// for relative and templated URLs
+func sourceOrigin(raw string) string {
+	if strings.ContainsAny(raw, "{}") {
+		return ""
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// sortedSources joins CSP sources, keywords first
+func sortedSources(sources map[string]bool) string {
+	list := make([]string, 0, len(sources))
+	for source := range sources {
+		list = append(list, source)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		ki, kj := strings.HasPrefix(list[i], "'"), strings.HasPrefix(list[j], "'")
+		if ki != kj {
+			return ki
+		}
+		return list[i] < list[j]
+	})
+	return strings.Join(list, " ")
+}
@@ -0,0 +1,115 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestSecurityHeaders tests the default and configured security headers
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		path     string
+		proto    string // X-Forwarded-Proto
+		expected map[string]string
+		csp      []string // Fragments of the Content-Security-Policy
+	}{
+		{
+			name:   "API defaults over HTTP",
+			config: Config{Title: "Test API", Host: "localhost:8080"},
+			path:   "/ping",
+			expected: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           DefaultReferrerPolicy,
+				"Content-Security-Policy":   DefaultAPIContentSecurityPolicy,
+				"Strict-Transport-Security": "",
+			},
+		},
+		{
+			name:     "HSTS behind a TLS proxy",
+			config:   Config{Title: "Test API", Host: "localhost:8080", TrustProxyHeaders: true, SecurityHeaders: &SecurityHeadersConfig{HSTSIncludeSubdomains: true}},
+			path:     "/ping",
+			proto:    "https",
+			expected: map[string]string{"Strict-Transport-Security": "max-age=31536000; includeSubDomains"},
+		},
+		{
+			name: "docs policy",
+			config: Config{Title: "Test API", Host: "api.example.com", Servers: []OpenAPIServer{{URL: "https://{region}.example.com"}},
+				Assets: map[string]Asset{AssetRedoc: {URL: "https://assets.example.com/redoc.js"}}},
+			path:     DefaultDocsPath,
+			expected: map[string]string{"X-Frame-Options": "DENY"},
+			csp: []string{
+				"script-src 'self' 'unsafe-inline' https://assets.example.com",
+				"https://cdnjs.cloudflare.com",
+				"connect-src 'self' http://api.example.com https:;",
+				"frame-ancestors 'none'",
+			},
+		},
+		{
+			name: "configured per deployment",
+			config: Config{Title: "Test API", Host: "localhost:8080", SecurityHeaders: &SecurityHeadersConfig{
+				FrameOptions: "sameorigin", ReferrerPolicy: "no-referrer", APICSP: "default-src 'none'", ConnectSources: []string{"https://acme.api.example.com"}}},
+			path:     DefaultDocsPath,
+			expected: map[string]string{"X-Frame-Options": "SAMEORIGIN", "Referrer-Policy": "no-referrer"},
+			csp:      []string{"https://acme.api.example.com", "frame-ancestors 'self'"},
+		},
+		{
+			name:     "HSTS omitted",
+			config:   Config{Title: "Test API", Host: "localhost:8080", TrustProxyHeaders: true, SecurityHeaders: &SecurityHeadersConfig{HSTSMaxAge: -time.Second}},
+			path:     "/ping",
+			proto:    "https",
+			expected: map[string]string{"Strict-Transport-Security": "", "X-Content-Type-Options": "nosniff"},
+		},
+		{
+			name:     "opted out",
+			config:   Config{Title: "Test API", Host: "localhost:8080", DisableSecurityHeaders: true},
+			path:     "/ping",
+			expected: map[string]string{"X-Content-Type-Options": "", "X-Frame-Options": "", "Content-Security-Policy": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			api := NewApiNote(&config, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:      "GET",
+				Path:        "/ping",
+				Description: "Ping",
+				Handler:     func(c fiber.Ctx) error { return c.SendString("pong") },
+			})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("Expected 200, got %d", resp.StatusCode)
+			}
+			for header, expected := range tt.expected {
+				if got := resp.Header.Get(header); got != expected {
+					t.Errorf("Expected %s %q, got %q", header, expected, got)
+				}
+			}
+			csp := resp.Header.Get("Content-Security-Policy")
+			for _, fragment := range tt.csp {
+				if !strings.Contains(csp, fragment) {
+					t.Errorf("Expected the docs policy to contain %q, got %q", fragment, csp)
+				}
+			}
+		})
+	}
+}
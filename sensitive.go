@@ -0,0 +1,127 @@
+package notelink
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+)
+
+// RedactedValue replaces sensitive values in generated examples and example files
+const RedactedValue = "********"
+
+// sensitiveNames are name fragments that mark a field as sensitive without a tag.
+// Names are compared lowercased with underscores and dashes removed.
+var sensitiveNames = []string{"password", "passwd", "secret", "token", "apikey", "privatekey", "credential"}
+
+// isSensitiveName reports whether a field or key name looks like it holds a secret
+func isSensitiveName(name string) bool {
+	name = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	for _, fragment := range sensitiveNames {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitiveField reports whether a struct field is sensitive: fields tagged
+// `sensitive:"true"`, or whose Go or JSON name matches the name heuristics.
+// `sensitive:"false"` opts a field out of the heuristics.
+func isSensitiveField(field *reflect.StructField) bool {
+	if tag, ok := field.Tag.Lookup("sensitive"); ok {
+		return tag == "true"
+	}
+	return isSensitiveName(field.Name) || isSensitiveName(validation.JSONFieldName(field))
+}
+
+// maskedExample returns the example for a sensitive field: the redaction mask for
+// strings and the zero value for other scalars, so templates still validate
+func maskedExample(t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if valueType, ok := validation.NullableValueType(t); ok {
+		t = valueType
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return RedactedValue
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return 0
+	case reflect.Bool:
+		return false
+	default:
+		return RedactedValue
+	}
+}
+
+// redactExample masks sensitive values in a decoded example, such as a fixture
+// file recorded from real traffic. Struct fields are matched against t; keys of
+// objects without a known struct type fall back to the name heuristics.
+func redactExample(value interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		fields := map[string]*reflect.StructField{}
+		if t != nil && t.Kind() == reflect.Struct {
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if field.IsExported() {
+					fields[validation.JSONFieldName(&field)] = &field
+				}
+			}
+		}
+		var valueType reflect.Type
+		if t != nil && t.Kind() == reflect.Map {
+			valueType = t.Elem()
+		}
+
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			field, known := fields[key]
+			switch {
+			case item == nil:
+				result[key] = nil
+			case known && isSensitiveField(field):
+				result[key] = RedactedValue
+			case known:
+				result[key] = redactExample(item, field.Type)
+			case isSensitiveName(key):
+				result[key] = RedactedValue
+			default:
+				result[key] = redactExample(item, valueType)
+			}
+		}
+		return result
+	case []interface{}:
+		var elemType reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elemType = t.Elem()
+		}
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = redactExample(item, elemType)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// redactExamples masks sensitive values in named examples for the schema's type
+func redactExamples(examples []RequestExample, schema interface{}) []RequestExample {
+	var t reflect.Type
+	if schema != nil {
+		t = reflect.TypeOf(schema)
+	}
+	for i := range examples {
+		examples[i].Value = redactExample(examples[i].Value, t)
+	}
+	return examples
+}
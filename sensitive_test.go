@@ -0,0 +1,93 @@
+package notelink
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type Credentials struct {
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	APIKey    string    `json:"api_key"`
+	PIN       int       `json:"pin" sensitive:"true"`
+	TokenType string    `json:"token_type" sensitive:"false"`
+	Recovery  *Recovery `json:"recovery,omitempty"`
+}
+
+type Recovery struct {
+	Email  string `json:"email"`
+	Secret string `json:"secret"`
+}
+
+// TestSensitiveFields tests masking of sensitive fields in examples, fixtures and TypeScript
+func TestSensitiveFields(t *testing.T) {
+	template, err := generateJSONTemplate(Credentials{}, exampleOptions{Provider: ExampleValues{"password": "hunter2"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var example map[string]interface{}
+	if err := json.Unmarshal([]byte(template), &example); err != nil {
+		t.Fatalf("Template is not valid JSON: %v", err)
+	}
+
+	tests := []struct {
+		field    string
+		expected interface{}
+	}{
+		{field: "username", expected: "john_doe"},
+		{field: "password", expected: RedactedValue},
+		{field: "api_key", expected: RedactedValue},
+		{field: "pin", expected: float64(0)},
+		{field: "token_type", expected: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if example[tt.field] != tt.expected {
+				t.Errorf("Expected %s example %v, got %v", tt.field, tt.expected, example[tt.field])
+			}
+		})
+	}
+	if recovery, ok := example["recovery"].(map[string]interface{}); !ok || recovery["secret"] != RedactedValue {
+		t.Errorf("Expected nested secret to be masked, got %v", example["recovery"])
+	}
+
+	ts := generateTypeScriptSchema("Credentials", Credentials{}, "", nil)
+	if !strings.Contains(ts, "@sensitive Redacted in documentation examples */\n  password: string;") {
+		t.Errorf("Expected password to be flagged in TypeScript, got:\n%s", ts)
+	}
+	if strings.Contains(ts, "*/\n  username:") || strings.Contains(ts, "*/\n  token_type:") {
+		t.Errorf("Expected only sensitive fields to be flagged, got:\n%s", ts)
+	}
+
+	fixtures := fstest.MapFS{
+		"testdata/login.json": {Data: []byte(`{"username":"jane","password":"hunter2","pin":1234,"recovery":{"email":"jane@example.com","secret":"s3cr3t"},"session_token":"abc"}`)},
+	}
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", ExampleFS: fixtures}, "secret")
+	err = api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/login",
+		Handler:        func(c fiber.Ctx) error { return c.SendStatus(200) },
+		SchemasRequest: Credentials{},
+		ExampleFiles:   []string{"testdata/login.json"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	spec, _ := json.Marshal(api.GenerateOpenAPISpec())
+	for _, leaked := range []string{"hunter2", "1234", "s3cr3t", `"abc"`} {
+		if strings.Contains(string(spec), leaked) {
+			t.Errorf("Expected %s to be redacted from the spec", leaked)
+		}
+	}
+	if !strings.Contains(string(spec), "jane@example.com") {
+		t.Error("Expected non-sensitive fixture values to be kept")
+	}
+	if strings.Contains(api.generateHTML(), "hunter2") {
+		t.Error("Expected fixture secrets to be redacted from the docs page")
+	}
+}
@@ -0,0 +1,114 @@
+package notelink
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// serverTimingKey is the context key holding the per-request timing recorder
+const serverTimingKey = "notelink_server_timing"
+
+// serverTimingEntry is a single named phase duration
+type serverTimingEntry struct {
+	name     string
+	duration time.Duration
+}
+
+// serverTimings collects phase durations for one request
+type serverTimings struct {
+	entries []serverTimingEntry
+}
+
+// timingsFor returns the recorder stored in the context, creating it if needed
+func timingsFor(c fiber.Ctx) *serverTimings {
+	if timings, ok := c.Locals(serverTimingKey).(*serverTimings); ok {
+		return timings
+	}
+	timings := &serverTimings{}
+	c.Locals(serverTimingKey, timings)
+	return timings
+}
+
+// total returns the summed duration of all phases with the given name
+func (s *serverTimings) total(name string) time.Duration {
+	var sum time.Duration
+	for _, entry := range s.entries {
+		if entry.name == name {
+			sum += entry.duration
+		}
+	}
+	return sum
+}
+
+// header formats the recorded phases as a Server-Timing header value
+func (s *serverTimings) header() string {
+	parts := make([]string, 0, len(s.entries))
+	for _, entry := range s.entries {
+		ms := float64(entry.duration.Microseconds()) / 1000
+		parts = append(parts, entry.name+";dur="+strconv.FormatFloat(ms, 'f', 3, 64))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RecordServerTiming records a named phase duration for the current request.
+// Recorded phases are emitted in the Server-Timing header by ServerTimingMiddleware.
+//
+// Example usage:
+//
+//	start := time.Now()
+//	users, err := repo.List(ctx)
+//	notelink.RecordServerTiming(c, "db", time.Since(start))
+func RecordServerTiming(c fiber.Ctx, name string, duration time.Duration) {
+	timings := timingsFor(c)
+	timings.entries = append(timings.entries, serverTimingEntry{name: name, duration: duration})
+}
+
+// ServerTimingMiddleware returns a Fiber middleware that emits a Server-Timing
+// response header containing all phases recorded for the request plus the total.
+//
+// It is installed automatically when Config.ServerTiming is enabled.
+func ServerTimingMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		RecordServerTiming(c, "total", time.Since(start))
+		c.Set("Server-Timing", timingsFor(c).header())
+		return err
+	}
+}
+
+// TimedJSON serializes data as JSON and records the time spent as the
+// "serialization" phase. Use it instead of c.JSON to separate serialization
+// from handler time in the Server-Timing breakdown: the phase isn't recorded
+// for responses written with c.JSON, whose serialization counts as handler time.
+func TimedJSON(c fiber.Ctx, data interface{}) error {
+	start := time.Now()
+	body, err := c.App().Config().JSONEncoder(data)
+	RecordServerTiming(c, "serialization", time.Since(start))
+	if err != nil {
+		return err
+	}
+
+	c.Set("Content-Type", fiber.MIMEApplicationJSON)
+	return c.Send(body)
+}
+
+// timedHandler wraps a route handler and records its duration as the "handler"
+// phase, excluding time already attributed to serialization.
+func timedHandler(handler fiber.Handler) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		timings := timingsFor(c)
+		serializationBefore := timings.total("serialization")
+		start := time.Now()
+
+		err := handler(c)
+
+		elapsed := time.Since(start) - (timings.total("serialization") - serializationBefore)
+		RecordServerTiming(c, "handler", elapsed)
+		return err
+	}
+}
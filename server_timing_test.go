@@ -0,0 +1,67 @@
+package notelink
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestServerTimingHeader tests that documented routes report their phase timings
+func TestServerTimingHeader(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", ServerTiming: true}, "secret")
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/users",
+		Description:    "Create a user",
+		Handler:        func(c fiber.Ctx) error { return TimedJSON(c, fiber.Map{"ok": true}) },
+		SchemasRequest: TestUser{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	req := httptest.NewRequest("POST", "/users", bytes.NewBufferString(`{"name":"John","email":"john@example.com","age":25}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := api.Fiber().Test(req)
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	header := resp.Header.Get("Server-Timing")
+	for _, phase := range []string{"validation;dur=", "handler;dur=", "serialization;dur=", "total;dur="} {
+		if !strings.Contains(header, phase) {
+			t.Errorf("Expected Server-Timing header to contain %q, got %q", phase, header)
+		}
+	}
+}
+
+// TestServerTimingDisabled tests that no header is emitted unless enabled
+func TestServerTimingDisabled(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/ping",
+		Description: "Ping",
+		Handler:     func(c fiber.Ctx) error { return c.SendString("pong") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/ping", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if header := resp.Header.Get("Server-Timing"); header != "" {
+		t.Errorf("Expected no Server-Timing header, got %q", header)
+	}
+}
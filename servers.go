@@ -0,0 +1,128 @@
+package notelink
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// serverVariablePattern matches the {name} variables of a server URL
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// ServerVariable is a variable substituted into a server URL, e.g. the tenant of
+// https://{tenant}.api.example.com
+type ServerVariable struct {
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// servers returns the spec's servers: Config.Servers with Config.BasePath
+// appended, or baseURL (scheme and host) without them
+func (an *ApiNote) servers(baseURL string) []OpenAPIServer {
+	if len(an.config.Servers) == 0 {
+		return []OpenAPIServer{{URL: baseURL + an.config.BasePath, Description: "API Server"}}
+	}
+	servers := make([]OpenAPIServer, len(an.config.Servers))
+	for i, server := range an.config.Servers {
+		servers[i] = server
+		servers[i].URL = server.URL + an.config.BasePath
+	}
+	return servers
+}
+
+// expandServerURL substitutes the defaults of a server's variables into its URL
+func expandServerURL(server OpenAPIServer) string {
+	return serverVariablePattern.ReplaceAllStringFunc(server.URL, func(match string) string {
+		if variable, ok := server.Variables[match[1:len(match)-1]]; ok {
+			return variable.Default
+		}
+		return match
+	})
+}
+
+// serverVariableNames returns the variables of all servers in the order their
+// URLs use them, once each
+func (an *ApiNote) serverVariableNames() []string {
+	var names []string
+	for _, server := range an.config.Servers {
+		for _, match := range serverVariablePattern.FindAllStringSubmatch(server.URL, -1) {
+			if _, ok := server.Variables[match[1]]; ok && !slices.Contains(names, match[1]) {
+				names = append(names, match[1])
+			}
+		}
+	}
+	return names
+}
+
+// serverVariable returns the first declaration of a variable across the servers
+func (an *ApiNote) serverVariable(name string) ServerVariable {
+	for _, server := range an.config.Servers {
+		if variable, ok := server.Variables[name]; ok {
+			return variable
+		}
+	}
+	return ServerVariable{}
+}
+
+// renderServerSelector renders the docs controls choosing the server of try-it
+// requests and the values of its URL variables. Enumerated variables are selects.
+func (an *ApiNote) renderServerSelector() string {
+	if len(an.config.Servers) == 0 {
+		return ""
+	}
+
+	var controls strings.Builder
+	if len(an.config.Servers) > 1 {
+		controls.WriteString(`<select id="server-select" onchange="setServer(this.value)">`)
+		for i, server := range an.config.Servers {
+			label := server.URL
+			if server.Description != "" {
+				label = server.Description + " (" + server.URL + ")"
+			}
+			controls.WriteString(`<option value="` + strconv.Itoa(i) + `">` + escapeHTML(label) + `</option>`)
+		}
+		controls.WriteString(`</select>`)
+	}
+	for _, name := range an.serverVariableNames() {
+		variable := an.serverVariable(name)
+		title := name
+		if variable.Description != "" {
+			title = variable.Description
+		}
+		attrs := ` class="server-variable" data-variable="` + escapeHTML(name) + `" title="` + escapeHTML(title) + `" onchange="setServerVariable(this.dataset.variable, this.value)"`
+		if len(variable.Enum) > 0 {
+			controls.WriteString(`<select` + attrs + `>`)
+			for _, value := range variable.Enum {
+				controls.WriteString(`<option value="` + escapeHTML(value) + `">` + escapeHTML(value) + `</option>`)
+			}
+			controls.WriteString(`</select>`)
+		} else {
+			controls.WriteString(`<input type="text"` + attrs + ` placeholder="` + escapeHTML(name) + `">`)
+		}
+	}
+
+	return `
+        <div class="auth-section server-section">
+            <h2><i class="fas fa-server"></i> Server <code id="server-url"></code></h2>
+            <div class="auth-input-group">
+                ` + controls.String() + `
+            </div>
+        </div>`
+}
+
+// serversScript declares the servers and the variable values used by the
+// try-it script to compose request URLs
+func (an *ApiNote) serversScript() string {
+	servers, err := json.Marshal(an.config.Servers)
+	if err != nil || len(an.config.Servers) == 0 {
+		servers = []byte("[]")
+	}
+	return `
+            const servers = ` + string(servers) + `;
+            let serverIndex = Number(localStorage.getItem('serverIndex')) || 0;
+            let serverVariables = JSON.parse(localStorage.getItem('serverVariables') || '{}');`
+}
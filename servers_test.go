@@ -0,0 +1,90 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestServerVariables tests server URL variables in the spec and the docs
+func TestServerVariables(t *testing.T) {
+	servers := []OpenAPIServer{
+		{
+			URL:         "https://{tenant}.api.example.com",
+			Description: "Production",
+			Variables: map[string]ServerVariable{
+				"tenant": {Default: "acme", Enum: []string{"acme", "globex"}, Description: "Tenant subdomain"},
+			},
+		},
+		{
+			URL:         "http://localhost:{port}",
+			Description: "Local",
+			Variables:   map[string]ServerVariable{"port": {Default: "8080"}},
+		},
+	}
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/v1", Servers: servers}, "secret")
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/users", Handler: func(c fiber.Ctx) error { return nil }}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	if len(spec.Servers) != 2 || spec.Servers[0].URL != "https://{tenant}.api.example.com/v1" {
+		t.Fatalf("Expected the configured servers below BasePath, got %+v", spec.Servers)
+	}
+	if tenant := spec.Servers[0].Variables["tenant"]; tenant.Default != "acme" || len(tenant.Enum) != 2 {
+		t.Errorf("Expected the tenant variable with its values, got %+v", tenant)
+	}
+	if servers[0].URL != "https://{tenant}.api.example.com" {
+		t.Error("Expected the spec not to modify Config.Servers")
+	}
+
+	html := api.generateHTML()
+	for _, want := range []string{
+		`id="server-select"`,
+		`<option value="1">Local (http://localhost:{port})</option>`,
+		`data-variable="tenant" title="Tenant subdomain"`,
+		`<option value="globex">globex</option>`,
+		`data-variable="port"`,
+		"const baseUrl = serverUrl();",
+		// Copied URLs use the first server's defaults
+		"https://acme.api.example.com/v1/users",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the docs to contain %q", want)
+		}
+	}
+
+	// Without servers the spec and the try-it client use Host
+	api = NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", BasePath: "/v1"}, "secret")
+	if spec := api.GenerateOpenAPISpec(); len(spec.Servers) != 1 || spec.Servers[0].URL != "http://localhost:8080/v1" {
+		t.Errorf("Expected the Host server, got %+v", spec.Servers)
+	}
+	if html := api.generateHTML(); strings.Contains(html, `id="server-select"`) || strings.Contains(html, "const baseUrl = serverUrl();") {
+		t.Error("Expected no server controls without Config.Servers")
+	}
+}
+
+// TestExpandServerURL tests substituting variable defaults into server URLs
+func TestExpandServerURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   OpenAPIServer
+		expected string
+	}{
+		{"no variables", OpenAPIServer{URL: "https://api.example.com"}, "https://api.example.com"},
+		{"defaults", OpenAPIServer{
+			URL:       "https://{tenant}.api.example.com:{port}",
+			Variables: map[string]ServerVariable{"tenant": {Default: "acme"}, "port": {Default: "443"}},
+		}, "https://acme.api.example.com:443"},
+		{"undeclared variable", OpenAPIServer{URL: "https://{region}.example.com"}, "https://{region}.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandServerURL(tt.server); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,418 @@
+package notelink
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+const (
+	// DefaultSessionCookie names the session cookie
+	DefaultSessionCookie = "notelink_session"
+	// DefaultSessionTTL is the lifetime of sessions
+	DefaultSessionTTL = 24 * time.Hour
+	// DefaultSessionPath is where the session endpoints are mounted, relative
+	// to Config.BasePath
+	DefaultSessionPath = "/auth/session"
+)
+
+// ErrSessionNotFound is returned by SessionStore.Get for unknown or expired sessions
+var ErrSessionNotFound = errors.New("notelink: session not found")
+
+// Session is a signed-in user's session
+type Session struct {
+	ID        string                 `json:"id,omitempty"` // Set when sessions are kept in a store
+	Subject   string                 `json:"sub"`
+	Claims    map[string]interface{} `json:"claims,omitempty"`
+	ExpiresAt time.Time              `json:"exp"`
+}
+
+// SessionInfo describes the current session
+type SessionInfo struct {
+	Subject   string    `json:"subject"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SessionStore keeps sessions server-side, e.g. in Redis shared by several
+// instances, so signing out revokes them
+type SessionStore interface {
+	Save(session *Session) error
+	Get(id string) (*Session, error) // ErrSessionNotFound for unknown or expired ids
+	Delete(id string) error
+}
+
+// MemorySessionStore keeps sessions in memory; sessions are lost on restart
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+}
+
+// NewMemorySessionStore creates an empty in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]Session)}
+}
+
+// Save stores a copy of the session, dropping expired sessions
+func (s *MemorySessionStore) Save(session *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, stored := range s.sessions {
+		if !now.Before(stored.ExpiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	s.sessions[session.ID] = *session
+	return nil
+}
+
+// Get returns a copy of an unexpired session
+func (s *MemorySessionStore) Get(id string) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok || !time.Now().Before(session.ExpiresAt) {
+		return nil, ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+// Delete removes a session
+func (s *MemorySessionStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// SessionConfig enables cookie sessions as an alternative to JWTs: POST
+// {Path} signs in with credentials checked by Verify and sets an encrypted,
+// HttpOnly session cookie, DELETE {Path} signs out and GET {Path} describes
+// the session. Routes registered after ApiNote.UseSessions require the
+// cookie, and the docs page gets a sign-in form.
+//
+// Without a Store the whole session is kept in the cookie, so signing out
+// only clears it; with a Store the cookie holds the session id.
+type SessionConfig struct {
+	Verify     CredentialVerifier // Required
+	Store      SessionStore
+	TTL        time.Duration // Defaults to DefaultSessionTTL
+	CookieName string        // Defaults to DefaultSessionCookie
+	Path       string        // Defaults to DefaultSessionPath
+	// Secret encrypts the cookies, defaulting to the ApiNote's JWT secret
+	Secret string
+	// InsecureCookie drops the cookie's Secure attribute, e.g. for plain
+	// http development servers other than localhost
+	InsecureCookie bool
+}
+
+// ttl returns the session lifetime
+func (s *SessionConfig) ttl() time.Duration {
+	if s.TTL <= 0 {
+		return DefaultSessionTTL
+	}
+	return s.TTL
+}
+
+// cookieName returns the session cookie's name
+func (s *SessionConfig) cookieName() string {
+	if s.CookieName == "" {
+		return DefaultSessionCookie
+	}
+	return s.CookieName
+}
+
+// path returns the mount path of the session endpoints
+func (s *SessionConfig) path() string {
+	if s.Path == "" {
+		return DefaultSessionPath
+	}
+	return s.Path
+}
+
+// sessionCipher returns the AEAD encrypting session cookies
+func (an *ApiNote) sessionCipher() (cipher.AEAD, error) {
+	secret := an.config.Sessions.Secret
+	if secret == "" {
+		secret = an.jwtSecret
+	}
+	key := sha256.Sum256([]byte("notelink session:" + secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encodeSession encrypts a session, or only its id when it's stored
+func (an *ApiNote) encodeSession(session *Session) (string, error) {
+	payload := session
+	if an.config.Sessions.Store != nil {
+		payload = &Session{ID: session.ID, ExpiresAt: session.ExpiresAt}
+	}
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	aead, err := an.sessionCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// decodeSession decrypts a session cookie, loading stored sessions
+func (an *ApiNote) decodeSession(cookie string) (*Session, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cookie)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	aead, err := an.sessionCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, ErrSessionNotFound
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	var session Session
+	if err := json.Unmarshal(plaintext, &session); err != nil || !time.Now().Before(session.ExpiresAt) {
+		return nil, ErrSessionNotFound
+	}
+	if store := an.config.Sessions.Store; store != nil {
+		return store.Get(session.ID)
+	}
+	return &session, nil
+}
+
+// setSessionCookie sets or, with an empty value, clears the session cookie
+func (an *ApiNote) setSessionCookie(c fiber.Ctx, value string, expires time.Time) {
+	cookie := &fiber.Cookie{
+		Name:     an.config.Sessions.cookieName(),
+		Value:    value,
+		Path:     "/",
+		Expires:  expires,
+		HTTPOnly: true,
+		Secure:   !an.config.Sessions.InsecureCookie,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+	if value == "" {
+		cookie.Expires = time.Unix(0, 0)
+		cookie.MaxAge = -1
+	}
+	c.Cookie(cookie)
+}
+
+// SessionMiddleware returns a Fiber middleware requiring a valid session
+// cookie. It sets "user_id" to the session's subject, like JWTMiddleware, and
+// "session" to the *Session in the context, or returns a 401 *fiber.Error
+// rendered by Config.ErrorHandler.
+func (an *ApiNote) SessionMiddleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		session, err := an.requestSession(c)
+		if errors.Is(err, ErrSessionNotFound) {
+			return fiber.NewError(fiber.StatusUnauthorized, "Sign in required")
+		}
+		if err != nil {
+			return err
+		}
+		c.Locals("user_id", session.Subject)
+		c.Locals("session", session)
+		return c.Next()
+	}
+}
+
+// requestSession returns the session of the request's cookie
+func (an *ApiNote) requestSession(c fiber.Ctx) (*Session, error) {
+	cookie := c.Cookies(an.config.Sessions.cookieName())
+	if cookie == "" {
+		return nil, ErrSessionNotFound
+	}
+	return an.decodeSession(cookie)
+}
+
+// UseSessions requires a session cookie on all subsequent routes, like
+// UseJWT. Config.Sessions must be set.
+func (an *ApiNote) UseSessions() {
+	an.sessionsRequired = true
+}
+
+// sessionSecurityScheme returns the OpenAPI security scheme of session cookies
+func (an *ApiNote) sessionSecurityScheme() SecurityScheme {
+	return SecurityScheme{
+		Type:        "apiKey",
+		In:          "cookie",
+		Name:        an.config.Sessions.cookieName(),
+		Description: "Session cookie set by POST " + an.config.BasePath + an.config.Sessions.path(),
+	}
+}
+
+// mountSessionEndpoints registers the documented session endpoints
+func (an *ApiNote) mountSessionEndpoints() {
+	if an.config.Sessions.Verify == nil {
+		log.Errorf("notelink: Config.Sessions.Verify is required, the session endpoints aren't mounted")
+		return
+	}
+	noAuth := false
+	path := an.config.Sessions.path()
+	routes := []DocumentedRouteInput{
+		{
+			Method:          http.MethodPost,
+			Path:            path,
+			Description:     "Sign in with credentials, setting the session cookie",
+			Responses:       map[string]string{"200": "Signed in", "401": "Invalid credentials"},
+			SchemasRequest:  TokenRequest{},
+			SchemasResponse: SessionInfo{},
+			Handler:         an.signInHandler,
+		},
+		{
+			Method:          http.MethodGet,
+			Path:            path,
+			Description:     "Describe the current session",
+			Responses:       map[string]string{"200": "Current session", "401": "Not signed in"},
+			SchemasResponse: SessionInfo{},
+			Handler:         an.sessionInfoHandler,
+		},
+		{
+			Method:      http.MethodDelete,
+			Path:        path,
+			Description: "Sign out, clearing the session cookie",
+			Responses:   map[string]string{"204": "Signed out"},
+			Handler:     an.signOutHandler,
+		},
+	}
+	for i := range routes {
+		routes[i].AuthRequired = &noAuth
+		routes[i].Tags = []string{"auth"}
+		routes[i].builtin = true
+		if err := an.DocumentedRoute(&routes[i]); err != nil {
+			log.Errorf("notelink: registering %s %s: %v", routes[i].Method, routes[i].Path, err)
+		}
+	}
+}
+
+// signInHandler starts a session for valid credentials
+func (an *ApiNote) signInHandler(c fiber.Ctx) error {
+	var req TokenRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid sign-in request")
+	}
+	subject, claims, err := an.config.Sessions.Verify(c, req.Username, req.Password)
+	if errors.Is(err, ErrInvalidCredentials) {
+		return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
+	}
+	if err != nil {
+		return err
+	}
+
+	session := &Session{Subject: subject, Claims: claims, ExpiresAt: time.Now().Add(an.config.Sessions.ttl()).UTC()}
+	if store := an.config.Sessions.Store; store != nil {
+		session.ID = newRandomID()
+		if err := store.Save(session); err != nil {
+			return err
+		}
+	}
+	value, err := an.encodeSession(session)
+	if err != nil {
+		return err
+	}
+	an.setSessionCookie(c, value, session.ExpiresAt)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(SessionInfo{Subject: session.Subject, ExpiresAt: session.ExpiresAt})
+}
+
+// sessionInfoHandler describes the request's session
+func (an *ApiNote) sessionInfoHandler(c fiber.Ctx) error {
+	session, err := an.requestSession(c)
+	if errors.Is(err, ErrSessionNotFound) {
+		return fiber.NewError(fiber.StatusUnauthorized, "Not signed in")
+	}
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(SessionInfo{Subject: session.Subject, ExpiresAt: session.ExpiresAt})
+}
+
+// signOutHandler ends the request's session
+func (an *ApiNote) signOutHandler(c fiber.Ctx) error {
+	if store := an.config.Sessions.Store; store != nil {
+		if session, err := an.requestSession(c); err == nil {
+			if err := store.Delete(session.ID); err != nil {
+				return err
+			}
+		}
+	}
+	an.setSessionCookie(c, "", time.Time{})
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// renderSessionLogin renders the docs page's sign-in form
+func (an *ApiNote) renderSessionLogin() string {
+	if an.config.Sessions == nil || an.config.Sessions.Verify == nil {
+		return ""
+	}
+	return `
+        <div class="auth-section session-section">
+            <h2><i class="fas fa-right-to-bracket"></i> Sign in</h2>
+            <form class="auth-input-group" onsubmit="sessionSignIn(event, this)">
+                <input type="text" name="username" placeholder="Username" autocomplete="username">
+                <input type="password" name="password" placeholder="Password" autocomplete="current-password">
+                <button type="submit">Sign in</button>
+                <button type="button" onclick="sessionSignOut()">Sign out</button>
+            </form>
+            <p class="session-status" id="session-status"></p>
+        </div>`
+}
+
+// sessionScript declares the sign-in functions of the docs page; requests of
+// the try-it forms send the session cookie as same-origin requests
+func (an *ApiNote) sessionScript() string {
+	if an.config.Sessions == nil || an.config.Sessions.Verify == nil {
+		return ""
+	}
+	return `
+            const sessionURL = '` + escapeJavaScript(an.config.BasePath+an.config.Sessions.path()) + `';
+            function showSession(info) {
+                const status = document.getElementById('session-status');
+                if (status) {
+                    status.textContent = info ? 'Signed in as ' + info.subject + ' until ' + new Date(info.expiresAt).toLocaleString() : 'Not signed in';
+                }
+            }
+            async function sessionSignIn(event, form) {
+                event.preventDefault();
+                const response = await fetch(sessionURL, {
+                    method: 'POST',
+                    credentials: 'same-origin',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ username: form.username.value, password: form.password.value }),
+                });
+                form.password.value = '';
+                showSession(response.ok ? await response.json() : null);
+            }
+            async function sessionSignOut() {
+                await fetch(sessionURL, { method: 'DELETE', credentials: 'same-origin' });
+                showSession(null);
+            }
+            fetch(sessionURL, { credentials: 'same-origin' })
+                .then(response => response.ok ? response.json() : null)
+                .then(showSession)
+                .catch(() => showSession(null));`
+}
@@ -0,0 +1,190 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// sessionAPI documents a /me route requiring a session
+func sessionAPI(t *testing.T, store SessionStore) *ApiNote {
+	t.Helper()
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		Sessions: &SessionConfig{
+			Store: store,
+			Verify: func(c fiber.Ctx, username, password string) (string, map[string]interface{}, error) {
+				if username != "ada" || password != "s3cret" {
+					return "", nil, ErrInvalidCredentials
+				}
+				return "user-1", map[string]interface{}{"role": "admin"}, nil
+			},
+		},
+	}, "secret")
+	api.UseSessions()
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/me",
+		Description: "Current user",
+		Handler: func(c fiber.Ctx) error {
+			session := c.Locals("session").(*Session)
+			return c.SendString(c.Locals("user_id").(string) + " " + session.Claims["role"].(string))
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	return api
+}
+
+// TestSessions tests signing in and out with cookie sessions, with and without a store
+func TestSessions(t *testing.T) {
+	tests := []struct {
+		name  string
+		store SessionStore
+	}{
+		{"cookie", nil},
+		{"store", NewMemorySessionStore()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := sessionAPI(t, tt.store)
+			send := func(method, path, cookie string, body interface{}) (*http.Response, string) {
+				var reader io.Reader
+				if body != nil {
+					payload, _ := json.Marshal(body)
+					reader = bytes.NewReader(payload)
+				}
+				req := httptest.NewRequest(method, path, reader)
+				req.Header.Set("Content-Type", "application/json")
+				if cookie != "" {
+					req.Header.Set("Cookie", DefaultSessionCookie+"="+cookie)
+				}
+				resp, err := api.Fiber().Test(req)
+				if err != nil {
+					t.Fatalf("Failed to send test request: %v", err)
+				}
+				defer resp.Body.Close()
+				data, _ := io.ReadAll(resp.Body)
+				return resp, string(data)
+			}
+
+			if resp, _ := send("GET", "/me", "", nil); resp.StatusCode != fiber.StatusUnauthorized {
+				t.Errorf("Expected 401 without a session, got %d", resp.StatusCode)
+			}
+			if resp, _ := send("POST", DefaultSessionPath, "", TokenRequest{Username: "ada", Password: "wrong"}); resp.StatusCode != fiber.StatusUnauthorized {
+				t.Errorf("Expected 401 for wrong credentials, got %d", resp.StatusCode)
+			}
+
+			resp, _ := send("POST", DefaultSessionPath, "", TokenRequest{Username: "ada", Password: "s3cret"})
+			if resp.StatusCode != fiber.StatusOK {
+				t.Fatalf("Expected sign-in to succeed, got %d", resp.StatusCode)
+			}
+			var cookie *http.Cookie
+			for _, c := range resp.Cookies() {
+				if c.Name == DefaultSessionCookie {
+					cookie = c
+				}
+			}
+			if cookie == nil || !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+				t.Fatalf("Expected an HttpOnly, Secure, SameSite=Lax session cookie, got %v", cookie)
+			}
+			if strings.Contains(cookie.Value, "user-1") {
+				t.Error("Expected the session cookie encrypted")
+			}
+
+			if resp, body := send("GET", "/me", cookie.Value, nil); resp.StatusCode != fiber.StatusOK || body != "user-1 admin" {
+				t.Errorf("Expected the session's subject and claims, got %d %q", resp.StatusCode, body)
+			}
+			if resp, body := send("GET", DefaultSessionPath, cookie.Value, nil); resp.StatusCode != fiber.StatusOK || !strings.Contains(body, `"subject":"user-1"`) {
+				t.Errorf("Expected the session described, got %d %s", resp.StatusCode, body)
+			}
+			tampered := cookie.Value[:len(cookie.Value)-2] + "AA"
+			if resp, _ := send("GET", "/me", tampered, nil); resp.StatusCode != fiber.StatusUnauthorized {
+				t.Errorf("Expected 401 for a tampered cookie, got %d", resp.StatusCode)
+			}
+
+			resp, _ = send("DELETE", DefaultSessionPath, cookie.Value, nil)
+			if resp.StatusCode != fiber.StatusNoContent {
+				t.Errorf("Expected sign-out to succeed, got %d", resp.StatusCode)
+			}
+			if cleared := resp.Cookies(); len(cleared) != 1 || cleared[0].Value != "" {
+				t.Errorf("Expected the cookie cleared, got %v", cleared)
+			}
+			if tt.store != nil {
+				if resp, _ := send("GET", "/me", cookie.Value, nil); resp.StatusCode != fiber.StatusUnauthorized {
+					t.Errorf("Expected the stored session revoked, got %d", resp.StatusCode)
+				}
+			}
+		})
+	}
+}
+
+// TestSessionErrorHandler tests rendering missing sessions with Config.ErrorHandler
+func TestSessionErrorHandler(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title: "Test API",
+		Host:  "localhost:8080",
+		Sessions: &SessionConfig{Verify: func(c fiber.Ctx, username, password string) (string, map[string]interface{}, error) {
+			return "", nil, ErrInvalidCredentials
+		}},
+		ErrorHandler: func(c fiber.Ctx, err error) error {
+			return c.Status(fiber.StatusTeapot).SendString("custom: " + err.Error())
+		},
+	}, "secret")
+	api.UseSessions()
+	err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/me", Handler: func(c fiber.Ctx) error { return c.SendString("ok") }})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/me", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusTeapot || string(body) != "custom: Sign in required" {
+		t.Errorf("Expected the rejection rendered by the ErrorHandler, got %d %s", resp.StatusCode, body)
+	}
+}
+
+// TestSessionDocs tests documenting the cookie scheme and the docs sign-in form
+func TestSessionDocs(t *testing.T) {
+	api := sessionAPI(t, nil)
+	spec := api.GenerateOpenAPISpec()
+	scheme, ok := spec.Components.SecuritySchemes["cookieAuth"]
+	if !ok || scheme.In != "cookie" || scheme.Name != DefaultSessionCookie {
+		t.Errorf("Expected the cookieAuth scheme, got %+v", scheme)
+	}
+	operation := spec.Paths["/me"].Get
+	if len(operation.Security) != 1 || operation.Security[0]["cookieAuth"] == nil {
+		t.Errorf("Expected /me to require cookieAuth, got %v", operation.Security)
+	}
+	if _, ok := operation.Responses["401"]; !ok {
+		t.Error("Expected the 401 response documented")
+	}
+	if signIn := spec.Paths[DefaultSessionPath].Post; signIn == nil || len(signIn.Security) != 0 {
+		t.Errorf("Expected the sign-in endpoint public, got %v", signIn.Security)
+	}
+
+	html := api.generateHTML()
+	if !strings.Contains(html, `onsubmit="sessionSignIn(event, this)"`) || !strings.Contains(html, "const sessionURL = '/auth/session';") {
+		t.Error("Expected the sign-in form on the docs page")
+	}
+
+	plain := NewApiNote(&Config{Title: "Test API"}, "secret")
+	if strings.Contains(plain.generateHTML(), "sessionSignIn") {
+		t.Error("Expected no sign-in form without Config.Sessions")
+	}
+	plain.UseSessions()
+	if err := plain.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/me", Handler: func(c fiber.Ctx) error { return nil }}); err == nil {
+		t.Error("Expected an error for sessions without Config.Sessions")
+	}
+}
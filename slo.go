@@ -0,0 +1,210 @@
+package notelink
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// DefaultSLOWindow is the rolling window SLO compliance is computed over
+const DefaultSLOWindow = time.Hour
+
+// sloBuckets is the number of buckets an SLO window is split into
+const sloBuckets = 60
+
+// SLO declares the service level objective of a route: 99% of requests faster
+// than LatencyP99 and an Availability share of requests without a 5xx status.
+// Zero disables an objective.
+type SLO struct {
+	LatencyP99   time.Duration `json:"latencyP99,omitempty"`
+	Availability float64       `json:"availability,omitempty"` // e.g. 0.999
+	Window       time.Duration `json:"window,omitempty"`       // Rolling window, default DefaultSLOWindow
+}
+
+// describe documents the objectives on the operation
+func (s *SLO) describe() string {
+	var objectives []string
+	if s.LatencyP99 > 0 {
+		objectives = append(objectives, "p99 latency under "+s.LatencyP99.String())
+	}
+	if s.Availability > 0 {
+		objectives = append(objectives, strconv.FormatFloat(s.Availability*100, 'f', -1, 64)+"% availability")
+	}
+	return "SLO: " + strings.Join(objectives, ", ") + " over " + s.window().String() + "."
+}
+
+// window returns Window, defaulting to DefaultSLOWindow
+func (s *SLO) window() time.Duration {
+	if s.Window > 0 {
+		return s.Window
+	}
+	return DefaultSLOWindow
+}
+
+// SLOStatus is the compliance of a route with its SLO over the rolling window.
+// A burn rate is the share of bad requests relative to the share the objective
+// allows: above 1 the error budget runs out before the window ends.
+type SLOStatus struct {
+	Route                string  `json:"route"`
+	Objective            SLO     `json:"objective"`
+	Requests             int64   `json:"requests"`
+	Errors               int64   `json:"errors"`
+	SlowRequests         int64   `json:"slowRequests"`
+	LatencyBurnRate      float64 `json:"latencyBurnRate"`
+	AvailabilityBurnRate float64 `json:"availabilityBurnRate"`
+	Breaching            bool    `json:"breaching"`
+}
+
+// sloBucket counts the requests of a slice of the window
+type sloBucket struct {
+	start    time.Time
+	requests int64
+	errors   int64
+	slow     int64
+}
+
+// SLOTracker records a route's requests against its SLO over a rolling window
+type SLOTracker struct {
+	slo     SLO
+	mu      sync.Mutex
+	buckets [sloBuckets]sloBucket
+}
+
+// NewSLOTracker creates a tracker for an SLO
+func NewSLOTracker(slo SLO) *SLOTracker {
+	return &SLOTracker{slo: slo}
+}
+
+// record counts a request finished at now
+func (t *SLOTracker) record(now time.Time, latency time.Duration, failed bool) {
+	width := t.slo.window() / sloBuckets
+	start := now.Truncate(width)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	bucket := &t.buckets[(start.UnixNano()/int64(width))%sloBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = sloBucket{start: start}
+	}
+	bucket.requests++
+	if failed {
+		bucket.errors++
+	}
+	if t.slo.LatencyP99 > 0 && latency > t.slo.LatencyP99 {
+		bucket.slow++
+	}
+}
+
+// status computes the compliance over the window ending at now
+func (t *SLOTracker) status(now time.Time, route string) SLOStatus {
+	status := SLOStatus{Route: route, Objective: t.slo}
+	cutoff := now.Add(-t.slo.window())
+	t.mu.Lock()
+	for _, bucket := range t.buckets {
+		if bucket.start.After(cutoff) && !bucket.start.After(now) {
+			status.Requests += bucket.requests
+			status.Errors += bucket.errors
+			status.SlowRequests += bucket.slow
+		}
+	}
+	t.mu.Unlock()
+
+	if status.Requests > 0 {
+		requests := float64(status.Requests)
+		if t.slo.LatencyP99 > 0 {
+			status.LatencyBurnRate = float64(status.SlowRequests) / requests / 0.01
+		}
+		if t.slo.Availability > 0 && t.slo.Availability < 1 {
+			status.AvailabilityBurnRate = float64(status.Errors) / requests / (1 - t.slo.Availability)
+		}
+	}
+	status.Breaching = status.LatencyBurnRate > 1 || status.AvailabilityBurnRate > 1
+	return status
+}
+
+// SLOMiddleware records the latency and outcome of each request to a route;
+// responses with a 5xx status, or errors the ErrorHandler renders as one,
+// count against availability
+func SLOMiddleware(t *SLOTracker) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+		t.record(time.Now(), time.Since(start), status >= fiber.StatusInternalServerError)
+		return err
+	}
+}
+
+// hasSLOs reports whether a route declares an SLO
+func (an *ApiNote) hasSLOs() bool {
+	for _, endpoint := range an.endpoints {
+		if endpoint.SLO != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// SLOs returns the SLO compliance of every route declaring one, ordered by route
+func (an *ApiNote) SLOs() []SLOStatus {
+	statuses := []SLOStatus{}
+	now := time.Now()
+	for _, endpoint := range an.endpoints {
+		if endpoint.SLO != nil {
+			statuses = append(statuses, endpoint.SLO.status(now, endpoint.Method+" "+endpoint.Path))
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Route < statuses[j].Route })
+	return statuses
+}
+
+// sloPrometheus renders the SLO compliance in the Prometheus text format
+func (an *ApiNote) sloPrometheus() string {
+	metrics := []struct {
+		name, help, kind string
+		value            func(s *SLOStatus) float64
+	}{
+		{"notelink_slo_requests", "Requests in the SLO window", "gauge", func(s *SLOStatus) float64 { return float64(s.Requests) }},
+		{"notelink_slo_errors", "Requests with a 5xx status in the SLO window", "gauge", func(s *SLOStatus) float64 { return float64(s.Errors) }},
+		{"notelink_slo_slow_requests", "Requests slower than the p99 latency objective in the SLO window", "gauge", func(s *SLOStatus) float64 { return float64(s.SlowRequests) }},
+		{"notelink_slo_latency_burn_rate", "Latency error budget burn rate", "gauge", func(s *SLOStatus) float64 { return s.LatencyBurnRate }},
+		{"notelink_slo_availability_burn_rate", "Availability error budget burn rate", "gauge", func(s *SLOStatus) float64 { return s.AvailabilityBurnRate }},
+		{"notelink_slo_latency_objective_seconds", "p99 latency objective", "gauge", func(s *SLOStatus) float64 { return s.Objective.LatencyP99.Seconds() }},
+		{"notelink_slo_availability_objective", "Availability objective", "gauge", func(s *SLOStatus) float64 { return s.Objective.Availability }},
+	}
+
+	statuses := an.SLOs()
+	var out strings.Builder
+	for _, metric := range metrics {
+		fmt.Fprintf(&out, "# HELP %s %s\n# TYPE %s %s\n", metric.name, metric.help, metric.name, metric.kind)
+		for i := range statuses {
+			method, path, _ := strings.Cut(statuses[i].Route, " ")
+			fmt.Fprintf(&out, "%s{method=%q,path=%q} %s\n", metric.name, method, path, strconv.FormatFloat(metric.value(&statuses[i]), 'g', -1, 64))
+		}
+	}
+	return out.String()
+}
+
+// renderSLOBadge renders the badge of an endpoint breaching its SLO
+func renderSLOBadge(endpoint *Endpoint) string {
+	if endpoint.SLO == nil {
+		return ""
+	}
+	status := endpoint.SLO.status(time.Now(), "")
+	if !status.Breaching {
+		return ""
+	}
+	return `<span class="slo-badge" title="` + escapeHTML(fmt.Sprintf("Latency burn rate %.2f, availability burn rate %.2f", status.LatencyBurnRate, status.AvailabilityBurnRate)) + `"><i class="fas fa-fire"></i> SLO breach</span>`
+}
@@ -0,0 +1,116 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestSLOTracker tests burn rates over the rolling window
+func TestSLOTracker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		slo      SLO
+		record   func(tracker *SLOTracker)
+		latency  float64
+		avail    float64
+		breached bool
+	}{
+		{"no requests", SLO{LatencyP99: time.Second, Availability: 0.99}, func(*SLOTracker) {}, 0, 0, false},
+		{"within budget", SLO{LatencyP99: time.Second, Availability: 0.9}, func(tracker *SLOTracker) {
+			for i := 0; i < 100; i++ {
+				tracker.record(now, 10*time.Millisecond, i == 0)
+			}
+		}, 0, 0.1, false},
+		{"latency breach", SLO{LatencyP99: 100 * time.Millisecond}, func(tracker *SLOTracker) {
+			for i := 0; i < 50; i++ {
+				tracker.record(now, time.Duration(i)*10*time.Millisecond, false)
+			}
+		}, 78, 0, true},
+		{"requests outside the window", SLO{Availability: 0.99, Window: time.Minute}, func(tracker *SLOTracker) {
+			tracker.record(now.Add(-2*time.Minute), 0, true)
+			tracker.record(now, 0, false)
+		}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewSLOTracker(tt.slo)
+			tt.record(tracker)
+			status := tracker.status(now, "GET /v1/users")
+			if diff := status.LatencyBurnRate - tt.latency; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected latency burn rate %v, got %v", tt.latency, status.LatencyBurnRate)
+			}
+			if diff := status.AvailabilityBurnRate - tt.avail; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected availability burn rate %v, got %v", tt.avail, status.AvailabilityBurnRate)
+			}
+			if status.Breaching != tt.breached {
+				t.Errorf("Expected breaching %v, got %+v", tt.breached, status)
+			}
+		})
+	}
+}
+
+// TestSLORoutes tests tracking declared SLOs, the compliance routes and the breach badge
+func TestSLORoutes(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	routes := []DocumentedRouteInput{
+		{Method: "GET", Path: "/v1/users", SLO: &SLO{LatencyP99: time.Second, Availability: 0.75}, Handler: func(c fiber.Ctx) error {
+			return fiber.NewError(fiber.StatusServiceUnavailable, "down")
+		}},
+		{Method: "GET", Path: "/v1/health", Handler: func(c fiber.Ctx) error { return c.SendString("ok") }},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+	for _, invalid := range []*SLO{{}, {Availability: 1}} {
+		input := DocumentedRouteInput{Method: "GET", Path: "/v1/invalid", SLO: invalid, Handler: routes[1].Handler}
+		if err := api.DocumentedRoute(&input); err == nil {
+			t.Errorf("Expected an error for SLO %+v", invalid)
+		}
+	}
+
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/v1/users", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	resp.Body.Close()
+
+	statuses := api.SLOs()
+	if len(statuses) != 1 || statuses[0].Route != "GET /v1/users" || statuses[0].Errors != 1 || !statuses[0].Breaching {
+		t.Fatalf("Expected a breaching SLO for GET /v1/users, got %+v", statuses)
+	}
+
+	resp, err = api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/slo/metrics", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	for _, want := range []string{
+		"# TYPE notelink_slo_availability_burn_rate gauge",
+		`notelink_slo_availability_burn_rate{method="GET",path="/v1/users"} 4`,
+		`notelink_slo_latency_objective_seconds{method="GET",path="/v1/users"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	if cacheControl := resp.Header.Get("Cache-Control"); cacheControl != "no-store" {
+		t.Errorf("Expected Cache-Control no-store, got %q", cacheControl)
+	}
+
+	if description := api.GenerateOpenAPISpec().Paths["/v1/users"].Get.Description; !strings.Contains(description, "SLO: p99 latency under 1s, 75% availability over 1h0m0s.") {
+		t.Errorf("Expected the SLO in the description, got %q", description)
+	}
+	if html := api.generateHTML(); !strings.Contains(html, `class="slo-badge"`) {
+		t.Error("Expected the breach badge in the docs")
+	}
+}
@@ -0,0 +1,152 @@
+package notelink
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// liveDocsRoutes are the docs routes reporting runtime state, never snapshotted
+var liveDocsRoutes = map[string]bool{
+	"/metrics":                     true,
+	"/go-test":                     true,
+	"/indent":                      true,
+	"/breakers":                    true,
+	"/slo":                         true,
+	"/slo/metrics":                 true,
+	"/payload-sizes":               true,
+	"/payload-sizes/metrics":       true,
+	"/debug":                       true,
+	"/deprecations":                true,
+	"/callback-relay":              true,
+	"/validation-failures":         true,
+	"/validation-failures/metrics": true,
+}
+
+// snapshotContentTypes are the content types of snapshot files by extension
+var snapshotContentTypes = map[string]string{
+	".html": "text/html; charset=utf-8",
+	".json": fiber.MIMEApplicationJSON,
+	".yaml": "application/yaml",
+	".ts":   "application/typescript",
+	".png":  "image/png",
+}
+
+// snapshotFile returns the file a docs route is snapshotted to: pages are
+// written as <path>/index.html so static file servers serve them at their
+// route, other routes keep their file name
+func snapshotFile(routePath string) string {
+	routePath = strings.Trim(routePath, "/")
+	if routePath == "" {
+		return "index.html"
+	}
+	if path.Ext(routePath) == "" {
+		return routePath + "/index.html"
+	}
+	return routePath
+}
+
+// Snapshot renders the docs pages, specs and exports as they would be served
+// now, and the files of the registered exporters, keyed by their snapshot
+// file name (see WriteSnapshot). Pages reporting runtime state, such as the
+// metrics and SLO pages, are left out.
+func (an *ApiNote) Snapshot() (map[string][]byte, error) {
+	snapshot, err := an.snapshotFiles()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(snapshot))
+	for _, file := range snapshot {
+		files[file.Name] = file.Data
+	}
+	return files, nil
+}
+
+// snapshotFiles renders the files of Snapshot, ordered by name
+func (an *ApiNote) snapshotFiles() ([]File, error) {
+	app := fiber.New(fiber.Config{ErrorHandler: an.app.Config().ErrorHandler})
+	var paths []string
+	for routePath, handler := range an.DocsHandlers() {
+		if liveDocsRoutes[routePath] {
+			continue
+		}
+		app.Get(routePath, handler)
+		paths = append(paths, routePath)
+	}
+	sort.Strings(paths)
+
+	files := make([]File, 0, len(paths))
+	for _, routePath := range paths {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, routePath, nil))
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", routePath, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", routePath, err)
+		}
+		// Routes without content, e.g. the icon when no icon.png is found, are skipped
+		if resp.StatusCode == fiber.StatusNotFound {
+			continue
+		}
+		if resp.StatusCode != fiber.StatusOK {
+			return nil, fmt.Errorf("snapshot %s: status %d: %s", routePath, resp.StatusCode, body)
+		}
+		name := snapshotFile(routePath)
+		files = append(files, File{Name: name, ContentType: fileContentType(name), Data: body})
+	}
+	return an.exportFiles(files)
+}
+
+// WriteSnapshot renders the docs surface into dir at build time, so production
+// binaries can embed it and serve the docs without rendering them: no
+// reflection over the routes at runtime and no dependency on the order routes
+// are registered in. Set Config.Host (or Config.TryItRelativeURL) so the
+// snapshot doesn't depend on the host it was rendered for. Typically run by
+// go generate from a program registering the same routes:
+//
+//	//go:generate go run ./cmd/docsnapshot -out docs_snapshot
+//	//go:embed docs_snapshot
+//	var docsSnapshot embed.FS
+//
+//	snapshot, _ := fs.Sub(docsSnapshot, "docs_snapshot")
+//	api := notelink.NewApiNote(&notelink.Config{Title: "My API", DocsSnapshot: snapshot}, secret)
+func (an *ApiNote) WriteSnapshot(dir string) error {
+	files, err := an.Snapshot()
+	if err != nil {
+		return err
+	}
+	for name, data := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotHandler serves a snapshot file, or nil when the snapshot lacks it
+func snapshotHandler(snapshot fs.FS, routePath string) fiber.Handler {
+	name := snapshotFile(routePath)
+	data, err := fs.ReadFile(snapshot, name)
+	if err != nil {
+		return nil
+	}
+	contentType := fileContentType(name)
+	return func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, contentType)
+		return c.Send(data)
+	}
+}
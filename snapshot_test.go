@@ -0,0 +1,72 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestSnapshot tests rendering the docs at build time and serving the snapshot
+func TestSnapshot(t *testing.T) {
+	config := Config{Title: "Test API", Host: "api.example.com", DocsUI: "notelink"}
+	api := NewApiNote(&config, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "GET",
+		Path:        "/orders",
+		Description: "List orders",
+		Handler:     func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := api.WriteSnapshot(dir); err != nil {
+		t.Fatalf("Failed to write the snapshot: %v", err)
+	}
+	for _, name := range []string{"index.html", "print/index.html", "openapi.json", "openapi.yaml", "postman.json", "types.ts"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s in the snapshot: %v", name, err)
+		}
+	}
+	for _, name := range []string{"metrics/index.html", "slo/index.html", "breakers/index.html"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Errorf("Expected no %s in the snapshot", name)
+		}
+	}
+
+	// The production binary registers nothing with the docs and serves the snapshot
+	config.DocsSnapshot = os.DirFS(dir)
+	served := NewApiNote(&config, "secret")
+	tests := []struct {
+		path        string
+		contentType string
+		want        string
+	}{
+		{"/api-docs", "text/html", "List orders"},
+		{"/api-docs/openapi.json", "application/json", `"/orders"`},
+		{"/api-docs/openapi.yaml", "application/yaml", "http://api.example.com"},
+		{"/api-docs/breakers", "application/json", "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := served.Fiber().Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != 200 || !strings.Contains(string(body), tt.want) {
+				t.Errorf("Expected 200 containing %s, got %d %.200s", tt.want, resp.StatusCode, body)
+			}
+			if !strings.HasPrefix(resp.Header.Get(fiber.HeaderContentType), tt.contentType) {
+				t.Errorf("Expected content type %s, got %s", tt.contentType, resp.Header.Get(fiber.HeaderContentType))
+			}
+		})
+	}
+}
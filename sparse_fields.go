@@ -0,0 +1,167 @@
+package notelink
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/goccy/go-json"
+	"github.com/gofiber/fiber/v3"
+)
+
+// SparseFieldsParam is the query parameter selecting the response fields of
+// routes with sparse fieldsets, e.g. ?fields=id,name
+const SparseFieldsParam = "fields"
+
+// filterableFields returns the top-level JSON fields of a struct response
+// schema, or of the elements of a slice schema; nil for other schemas
+func filterableFields(schema interface{}) []string {
+	typ := reflect.TypeOf(schema)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct || validation.IsTimeType(typ) {
+		return nil
+	}
+
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if name := validation.JSONFieldName(&field); name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// sparseFieldsParameter documents the fields query parameter of a route
+func sparseFieldsParameter(fields []string) Parameter {
+	return Parameter{
+		Name:        SparseFieldsParam,
+		In:          "query",
+		Type:        "string",
+		Description: "Comma-separated response fields to return, e.g. " + strings.Join(fields[:min(2, len(fields))], ",") + ". Filterable fields: " + strings.Join(fields, ", "),
+	}
+}
+
+// SparseFieldsMiddleware implements sparse fieldsets: with ?fields=id,name the
+// route's successful JSON responses only keep the listed top-level fields, of
+// the object or of each element of an array. Names outside fields are
+// rejected with 400 Bad Request before the handler runs.
+func SparseFieldsMiddleware(fields []string) fiber.Handler {
+	filterable := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		filterable[field] = true
+	}
+	return func(c fiber.Ctx) error {
+		selection := c.Query(SparseFieldsParam)
+		if selection == "" {
+			return c.Next()
+		}
+
+		selected := make(map[string]bool)
+		var errs []ValidationError
+		for _, name := range strings.Split(selection, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if !filterable[name] {
+				errs = append(errs, ValidationError{
+					Field:   SparseFieldsParam,
+					Message: fmt.Sprintf("Unknown field '%s', filterable fields are: %s", name, strings.Join(fields, ", ")),
+					Type:    "enum_error",
+					Code:    validation.CodeEnum,
+				})
+				continue
+			}
+			selected[name] = true
+		}
+		if len(errs) > 0 {
+			return &ValidationErrorResponse{ErrorMessage: "Invalid fields parameter", Errors: errs}
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		status := c.Response().StatusCode()
+		if status < 200 || status >= 300 || !strings.Contains(string(c.Response().Header.ContentType()), "json") {
+			return nil
+		}
+		var body interface{}
+		if err := json.Unmarshal(c.Response().Body(), &body); err != nil {
+			return nil
+		}
+		data, err := json.Marshal(selectFields(body, selected))
+		if err != nil {
+			return err
+		}
+		c.Response().SetBodyRaw(data)
+		return nil
+	}
+}
+
+// selectFields keeps the selected top-level fields of an object, or of each
+// object of an array
+func selectFields(body interface{}, selected map[string]bool) interface{} {
+	switch value := body.(type) {
+	case map[string]interface{}:
+		for name := range value {
+			if !selected[name] {
+				delete(value, name)
+			}
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = selectFields(value[i], selected)
+		}
+	}
+	return body
+}
+
+// documentSparseFields documents the fields parameter as a comma-separated
+// array of the filterable fields and lists them as x-filterable-fields on the
+// response schema's component
+func documentSparseFields(endpoint *Endpoint, operation *Operation, componentSchemas map[string]*JSONSchema) {
+	enum := make([]interface{}, len(endpoint.SparseFields))
+	for i, field := range endpoint.SparseFields {
+		enum[i] = field
+	}
+	explode := false
+	for i := range operation.Parameters {
+		param := &operation.Parameters[i]
+		if param.Name == SparseFieldsParam && param.In == "query" {
+			param.Schema = &JSONSchema{Type: "array", Items: &JSONSchema{Type: "string", Enum: enum}}
+			param.Style = "form"
+			param.Explode = &explode
+		}
+	}
+
+	for status, response := range operation.Responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		for _, content := range response.Content {
+			schema := content.Schema
+			if schema != nil && schema.Items != nil {
+				schema = schema.Items
+			}
+			if schema != nil && schema.Ref != "" {
+				schema = componentSchemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+			}
+			if schema == nil {
+				continue
+			}
+			extensions := copyExtensions(schema.Extensions)
+			if extensions == nil {
+				extensions = make(map[string]interface{})
+			}
+			extensions["x-filterable-fields"] = endpoint.SparseFields
+			schema.Extensions = extensions
+		}
+	}
+}
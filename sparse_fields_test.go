@@ -0,0 +1,85 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type sparseUser struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	secret string
+}
+
+// TestSparseFields tests selecting response fields with ?fields=
+func TestSparseFields(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	user := sparseUser{ID: 1, Name: "Ada", Email: "ada@example.com", secret: "x"}
+	routes := []DocumentedRouteInput{
+		{Method: "GET", Path: "/v1/user", Responses: map[string]string{"200": "The user"}, SchemasResponse: sparseUser{}, SparseFields: true,
+			Handler: func(c fiber.Ctx) error { return c.JSON(user) }},
+		{Method: "GET", Path: "/v1/users", Responses: map[string]string{"200": "The users"}, SchemasResponse: []sparseUser{}, SparseFields: true,
+			Handler: func(c fiber.Ctx) error { return c.JSON([]sparseUser{user, user}) }},
+		{Method: "GET", Path: "/v1/missing", SchemasResponse: sparseUser{}, SparseFields: true,
+			Handler: func(c fiber.Ctx) error { return c.Status(404).JSON(fiber.Map{"error": "gone"}) }},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+	scalar := DocumentedRouteInput{Method: "GET", Path: "/v1/count", SchemasResponse: 0, SparseFields: true, Handler: func(c fiber.Ctx) error { return nil }}
+	if err := api.DocumentedRoute(&scalar); err == nil {
+		t.Error("Expected an error for SparseFields on a non-struct response")
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{"all fields", "/v1/user", 200, `{"id":1,"name":"Ada","email":"ada@example.com"}`},
+		{"object", "/v1/user?fields=id,name", 200, `{"id":1,"name":"Ada"}`},
+		{"array", "/v1/users?fields=email", 200, `[{"email":"ada@example.com"},{"email":"ada@example.com"}]`},
+		{"unknown field", "/v1/user?fields=id,password", 400, ""},
+		{"error responses untouched", "/v1/missing?fields=id", 404, `{"error":"gone"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", tt.path, nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status || (tt.body != "" && string(body) != tt.body) {
+				t.Errorf("Expected %d %s, got %d %s", tt.status, tt.body, resp.StatusCode, body)
+			}
+		})
+	}
+
+	spec := api.GenerateOpenAPISpec()
+	var param *ParameterSpec
+	for i, p := range spec.Paths["/v1/users"].Get.Parameters {
+		if p.Name == SparseFieldsParam {
+			param = &spec.Paths["/v1/users"].Get.Parameters[i]
+		}
+	}
+	if param == nil || param.In != "query" || param.Style != "form" || param.Explode == nil || *param.Explode {
+		t.Fatalf("Expected a documented fields query parameter, got %+v", param)
+	}
+	want := []interface{}{"id", "name", "email"}
+	if param.Schema == nil || param.Schema.Type != "array" || !reflect.DeepEqual(param.Schema.Items.Enum, want) {
+		t.Errorf("Expected an array of %v, got %+v", want, param.Schema)
+	}
+	component := spec.Components.Schemas["sparseUser"]
+	if component == nil || !reflect.DeepEqual(component.Extensions["x-filterable-fields"], []string{"id", "name", "email"}) {
+		t.Errorf("Expected x-filterable-fields on the response schema, got %+v", component)
+	}
+}
@@ -0,0 +1,408 @@
+package notelink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// SpecChange is a difference between two versions of the OpenAPI spec
+type SpecChange struct {
+	Operation   string `json:"operation"` // e.g. "GET /orders"
+	Description string `json:"description"`
+	Breaking    bool   `json:"breaking"`
+}
+
+// specMethods are the operations of a path item in the order changes are listed
+var specMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE"}
+
+// DiffSpecs lists the changes from the old spec to the new one, by path and
+// method. Changes that can break existing clients are marked Breaking: removed
+// operations, parameters and responses, newly required parameters and request
+// fields, changed types, enum values a request no longer accepts and response
+// fields that were removed or became optional.
+func DiffSpecs(old, new *OpenAPISpec) []SpecChange {
+	d := &specDiffer{old: old, new: new}
+	paths := make(map[string]bool, len(old.Paths)+len(new.Paths))
+	for p := range old.Paths {
+		paths[p] = true
+	}
+	for p := range new.Paths {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	for _, p := range sorted {
+		oldItem, newItem := old.Paths[p], new.Paths[p]
+		for _, method := range specMethods {
+			d.operation = method + " " + p
+			oldOp, newOp := oldItem.operation(method), newItem.operation(method)
+			switch {
+			case oldOp == nil && newOp == nil:
+			case oldOp == nil:
+				d.add(false, "operation added")
+			case newOp == nil:
+				d.add(true, "operation removed")
+			default:
+				d.diffOperation(oldOp, newOp)
+			}
+		}
+	}
+	return d.changes
+}
+
+// specDiffer collects the changes between two specs
+type specDiffer struct {
+	old, new  *OpenAPISpec
+	operation string
+	changes   []SpecChange
+	seen      map[[2]*JSONSchema]bool
+}
+
+func (d *specDiffer) add(breaking bool, format string, args ...interface{}) {
+	d.changes = append(d.changes, SpecChange{Operation: d.operation, Description: fmt.Sprintf(format, args...), Breaking: breaking})
+}
+
+func (d *specDiffer) diffOperation(old, new *Operation) {
+	if new.Deprecated && !old.Deprecated {
+		d.add(false, "operation deprecated")
+	}
+	type paramKey struct{ in, name string }
+	oldParams := map[paramKey]ParameterSpec{}
+	for _, param := range old.Parameters {
+		oldParams[paramKey{param.In, param.Name}] = param
+	}
+	newParams := map[paramKey]bool{}
+	for _, param := range new.Parameters {
+		key := paramKey{param.In, param.Name}
+		newParams[key] = true
+		previous, ok := oldParams[key]
+		switch {
+		case !ok && param.Required:
+			d.add(true, "required %s parameter %q added", param.In, param.Name)
+		case !ok:
+			d.add(false, "%s parameter %q added", param.In, param.Name)
+		default:
+			if param.Required && !previous.Required {
+				d.add(true, "%s parameter %q is now required", param.In, param.Name)
+			}
+			d.diffSchema(fmt.Sprintf("%s parameter %q", param.In, param.Name), "", true, previous.Schema, param.Schema)
+		}
+	}
+	for _, param := range old.Parameters {
+		if !newParams[paramKey{param.In, param.Name}] {
+			d.add(true, "%s parameter %q removed", param.In, param.Name)
+		}
+	}
+
+	switch {
+	case old.RequestBody == nil && new.RequestBody != nil:
+		d.add(new.RequestBody.Required, "request body added")
+	case old.RequestBody != nil && new.RequestBody == nil:
+		d.add(true, "request body removed")
+	case old.RequestBody != nil:
+		if new.RequestBody.Required && !old.RequestBody.Required {
+			d.add(true, "request body is now required")
+		}
+		d.diffContent("request", true, old.RequestBody.Content, new.RequestBody.Content)
+	}
+
+	statuses := make([]string, 0, len(old.Responses)+len(new.Responses))
+	for status := range old.Responses {
+		statuses = append(statuses, status)
+	}
+	for status := range new.Responses {
+		if _, ok := old.Responses[status]; !ok {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		oldResp, inOld := old.Responses[status]
+		newResp, inNew := new.Responses[status]
+		switch {
+		case !inOld:
+			d.add(false, "response %s added", status)
+		case !inNew:
+			d.add(true, "response %s removed", status)
+		default:
+			d.diffContent("response "+status, false, oldResp.Content, newResp.Content)
+		}
+	}
+}
+
+// diffContent compares the schemas of the media types in both versions
+func (d *specDiffer) diffContent(name string, request bool, old, new map[string]MediaType) {
+	mediaTypes := make([]string, 0, len(old))
+	for mediaType := range old {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		newMedia, ok := new[mediaType]
+		if !ok {
+			d.add(request || len(new) == 0, "%s no longer uses %s", name, mediaType)
+			continue
+		}
+		d.diffSchema(name, "", request, old[mediaType].Schema, newMedia.Schema)
+	}
+}
+
+// diffSchema compares a parameter, request or response schema. base names
+// the parameter or body and path the field within it, e.g. "address.zip".
+func (d *specDiffer) diffSchema(base, path string, request bool, old, new *JSONSchema) {
+	old, new = resolveSchemaRef(d.old, old), resolveSchemaRef(d.new, new)
+	if old == nil || new == nil {
+		return
+	}
+	pair := [2]*JSONSchema{old, new}
+	if d.seen[pair] {
+		return
+	}
+	if d.seen == nil {
+		d.seen = map[[2]*JSONSchema]bool{}
+	}
+	d.seen[pair] = true
+	defer delete(d.seen, pair)
+
+	name := base
+	if path != "" {
+		name = fmt.Sprintf("%s field %q", base, path)
+	}
+	if old.Type != "" && new.Type != "" && old.Type != new.Type {
+		d.add(true, "%s type changed from %s to %s", name, old.Type, new.Type)
+		return
+	}
+	d.diffEnum(name, request, old.Enum, new.Enum)
+
+	field := func(property string) string {
+		if path == "" {
+			return property
+		}
+		return path + "." + property
+	}
+	oldRequired, newRequired := stringSet(old.Required), stringSet(new.Required)
+	properties := make([]string, 0, len(old.Properties)+len(new.Properties))
+	for property := range old.Properties {
+		properties = append(properties, property)
+	}
+	for property := range new.Properties {
+		if _, ok := old.Properties[property]; !ok {
+			properties = append(properties, property)
+		}
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		oldProp, inOld := old.Properties[property]
+		newProp, inNew := new.Properties[property]
+		switch {
+		case !inOld && request && newRequired[property]:
+			d.add(true, "required %s field %q added", base, field(property))
+		case !inOld:
+			d.add(false, "%s field %q added", base, field(property))
+		case !inNew:
+			d.add(!request, "%s field %q removed", base, field(property))
+		default:
+			if request && newRequired[property] && !oldRequired[property] {
+				d.add(true, "%s field %q is now required", base, field(property))
+			}
+			if !request && oldRequired[property] && !newRequired[property] {
+				d.add(true, "%s field %q is now optional", base, field(property))
+			}
+			d.diffSchema(base, field(property), request, oldProp, newProp)
+		}
+	}
+	if old.Items != nil && new.Items != nil {
+		d.diffSchema(base, path+"[]", request, old.Items, new.Items)
+	}
+}
+
+// diffEnum reports the values a request no longer accepts and the values a
+// response may now return
+func (d *specDiffer) diffEnum(name string, request bool, old, new []interface{}) {
+	if len(old) == 0 || len(new) == 0 {
+		return
+	}
+	if request {
+		if removed := missingValues(old, new); len(removed) > 0 {
+			d.add(true, "%s no longer accepts %s", name, strings.Join(removed, ", "))
+		}
+		return
+	}
+	if added := missingValues(new, old); len(added) > 0 {
+		d.add(false, "%s may now be %s", name, strings.Join(added, ", "))
+	}
+}
+
+// missingValues returns the values of a not in b, formatted as JSON
+func missingValues(a, b []interface{}) []string {
+	present := map[string]bool{}
+	for _, value := range b {
+		encoded, _ := json.Marshal(value)
+		present[string(encoded)] = true
+	}
+	var missing []string
+	for _, value := range a {
+		encoded, _ := json.Marshal(value)
+		if !present[string(encoded)] {
+			missing = append(missing, string(encoded))
+		}
+	}
+	return missing
+}
+
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// resolveSchemaRef follows a #/components/schemas reference of the spec
+func resolveSchemaRef(spec *OpenAPISpec, schema *JSONSchema) *JSONSchema {
+	for depth := 0; schema != nil && schema.Ref != "" && depth < 32; depth++ {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if name == schema.Ref || spec.Components == nil {
+			return schema
+		}
+		schema = spec.Components.Schemas[name]
+	}
+	return schema
+}
+
+// SpecChangeSummary describes the changes for people, breaking ones first,
+// e.g. for a chat message or a changelog
+func SpecChangeSummary(title, version string, changes []SpecChange) string {
+	breaking := 0
+	for _, change := range changes {
+		if change.Breaking {
+			breaking++
+		}
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(title + " " + version))
+	fmt.Fprintf(&b, ": %d breaking, %d non-breaking change%s", breaking, len(changes)-breaking, pluralize(len(changes)))
+	for _, breakingFirst := range []bool{true, false} {
+		for _, change := range changes {
+			if change.Breaking != breakingFirst {
+				continue
+			}
+			marker := "•"
+			if change.Breaking {
+				marker = "⚠ BREAKING"
+			}
+			fmt.Fprintf(&b, "\n%s %s: %s", marker, change.Operation, change.Description)
+		}
+	}
+	return b.String()
+}
+
+// SpecChangeNotifier posts a summary of the spec changes since the last
+// published version to a Slack or Microsoft Teams incoming webhook. The
+// payload carries the summary as "text", which both render, along with the
+// version, the number of breaking changes and the changes themselves.
+type SpecChangeNotifier struct {
+	WebhookURL string
+	Header     http.Header
+	Client     *http.Client // Defaults to a client with a 30s timeout
+	// BaselineURL serves the last published JSON spec, e.g. the openapi.json
+	// uploaded by Config.PublishArtifacts. A 404 means nothing was published yet.
+	BaselineURL string
+	// Baseline loads the last published JSON spec instead of BaselineURL, e.g.
+	// from a bucket; nil data means nothing was published yet
+	Baseline func(ctx context.Context) ([]byte, error)
+	// OnlyBreaking skips the notification when no change is breaking
+	OnlyBreaking bool
+}
+
+// baseline loads the last published spec, or nil
+func (n *SpecChangeNotifier) baseline(ctx context.Context) ([]byte, error) {
+	if n.Baseline != nil {
+		return n.Baseline(ctx)
+	}
+	if n.BaselineURL == "" {
+		return nil, errors.New("BaselineURL or Baseline is required")
+	}
+	return fetchSpec(ctx, n.Client, n.BaselineURL)
+}
+
+// fetchSpec downloads a published spec, or nil on a 404. A nil client
+// defaults to one with a 30s timeout.
+func fetchSpec(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// NotifySpecChanges compares the current spec with the last published one
+// and posts the changes to the notifier's webhook. Nothing is posted when
+// nothing was published yet or nothing changed. It returns the changes.
+func (an *ApiNote) NotifySpecChanges(ctx context.Context, notifier *SpecChangeNotifier) ([]SpecChange, error) {
+	data, err := notifier.baseline(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading the published spec: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var baseline OpenAPISpec
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("parsing the published spec: %w", err)
+	}
+	current := an.GenerateOpenAPISpec()
+	changes := DiffSpecs(&baseline, current)
+
+	breaking := 0
+	for _, change := range changes {
+		if change.Breaking {
+			breaking++
+		}
+	}
+	if len(changes) == 0 || notifier.OnlyBreaking && breaking == 0 {
+		return changes, nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"text":             SpecChangeSummary(current.Info.Title, current.Info.Version, changes),
+		"previous_version": baseline.Info.Version,
+		"version":          current.Info.Version,
+		"breaking":         breaking,
+		"changes":          changes,
+	})
+	if err != nil {
+		return changes, err
+	}
+	if err := upload(ctx, notifier.Client, http.MethodPost, notifier.WebhookURL, notifier.Header, fiber.MIMEApplicationJSON, payload); err != nil {
+		return changes, fmt.Errorf("notifying the spec changes: %w", err)
+	}
+	return changes, nil
+}
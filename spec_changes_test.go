@@ -0,0 +1,174 @@
+package notelink
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type specChangeAddressV1 struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type specChangeOrderV1 struct {
+	ID      int                 `json:"id"`
+	Status  string              `json:"status" enum:"open,closed,pending"`
+	Note    string              `json:"note,omitempty"`
+	Address specChangeAddressV1 `json:"address"`
+}
+
+type specChangeAddressV2 struct {
+	City string `json:"city,omitempty"`
+	Zip  string `json:"zip"`
+}
+
+type specChangeOrderV2 struct {
+	ID      string              `json:"id"`
+	Status  string              `json:"status" enum:"open,closed"`
+	Address specChangeAddressV2 `json:"address"`
+	Channel string              `json:"channel"`
+}
+
+// specChangeAPI documents the orders API in its first or second version
+func specChangeAPI(t *testing.T, v2 bool) *ApiNote {
+	t.Helper()
+	version := "1.0.0"
+	if v2 {
+		version = "2.0.0"
+	}
+	api := NewApiNote(&Config{Title: "Orders API", Version: version, Host: "api.example.com"}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/orders", Description: "List orders", Handler: handler,
+			Params:          []Parameter{{Name: "limit", In: "query", Type: "integer", Required: v2}},
+			Responses:       map[string]string{"200": "The orders"},
+			SchemasResponse: []specChangeOrderV1{}},
+	}
+	if v2 {
+		routes[0].SchemasResponse = []specChangeOrderV2{}
+		routes = append(routes,
+			&DocumentedRouteInput{Method: "POST", Path: "/orders", Description: "Create an order", Handler: handler, SchemasRequest: specChangeOrderV2{}})
+	} else {
+		routes = append(routes,
+			&DocumentedRouteInput{Method: "POST", Path: "/orders", Description: "Create an order", Handler: handler, SchemasRequest: specChangeOrderV1{}},
+			&DocumentedRouteInput{Method: "DELETE", Path: "/orders/:id", Description: "Delete an order", Handler: handler,
+				Params: []Parameter{{Name: "id", In: "path", Type: "string", Required: true}}})
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+	return api
+}
+
+// TestDiffSpecs tests classifying the changes between two specs
+func TestDiffSpecs(t *testing.T) {
+	changes := DiffSpecs(specChangeAPI(t, false).GenerateOpenAPISpec(), specChangeAPI(t, true).GenerateOpenAPISpec())
+	got := map[string]bool{}
+	for _, change := range changes {
+		got[change.Operation+": "+change.Description] = change.Breaking
+	}
+
+	tests := []struct {
+		change   string
+		breaking bool
+	}{
+		{`DELETE /orders/{id}: operation removed`, true},
+		{`GET /orders: query parameter "limit" is now required`, true},
+		{`GET /orders: response 200 field "[].note" removed`, true},
+		{`GET /orders: response 200 field "[].id" type changed from integer to string`, true},
+		{`GET /orders: response 200 field "[].channel" added`, false},
+		{`GET /orders: response 200 field "[].address.city" is now optional`, true},
+		{`POST /orders: required request field "channel" added`, true},
+		{`POST /orders: request field "address.zip" is now required`, true},
+		{`POST /orders: request field "status" no longer accepts "pending"`, true},
+		{`POST /orders: request field "note" removed`, false},
+	}
+	for _, tt := range tests {
+		breaking, ok := got[tt.change]
+		if !ok {
+			t.Errorf("Expected change %q, got %v", tt.change, changes)
+			continue
+		}
+		if breaking != tt.breaking {
+			t.Errorf("Expected %q breaking=%v", tt.change, tt.breaking)
+		}
+	}
+
+	if same := DiffSpecs(specChangeAPI(t, true).GenerateOpenAPISpec(), specChangeAPI(t, true).GenerateOpenAPISpec()); len(same) != 0 {
+		t.Errorf("Expected no changes between equal specs, got %v", same)
+	}
+
+	summary := SpecChangeSummary("Orders API", "2.0.0", changes)
+	if !strings.HasPrefix(summary, "Orders API 2.0.0: ") || !strings.Contains(summary, "⚠ BREAKING DELETE /orders/{id}: operation removed") {
+		t.Errorf("Unexpected summary %s", summary)
+	}
+	if strings.Index(summary, "• ") < strings.LastIndex(summary, "BREAKING") {
+		t.Error("Expected the breaking changes listed first")
+	}
+}
+
+// TestNotifySpecChanges tests posting the changes since the published spec to a webhook
+func TestNotifySpecChanges(t *testing.T) {
+	published, err := json.Marshal(specChangeAPI(t, false).GenerateOpenAPISpec())
+	if err != nil {
+		t.Fatalf("Failed to marshal spec: %v", err)
+	}
+	var baseline []byte
+	var posted []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bucket/openapi.json":
+			if baseline == nil {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(baseline) //nolint:errcheck // test server
+		case "/hooks/slack":
+			var payload map[string]interface{}
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Errorf("Expected a JSON payload, got %s", body)
+			}
+			posted = append(posted, payload)
+		}
+	}))
+	defer server.Close()
+
+	api := specChangeAPI(t, true)
+	notifier := &SpecChangeNotifier{WebhookURL: server.URL + "/hooks/slack", BaselineURL: server.URL + "/bucket/openapi.json"}
+
+	changes, err := api.NotifySpecChanges(context.Background(), notifier)
+	if err != nil || changes != nil || len(posted) != 0 {
+		t.Fatalf("Expected nothing posted before a first publish, got %v %v", changes, err)
+	}
+
+	baseline = published
+	changes, err = api.NotifySpecChanges(context.Background(), notifier)
+	if err != nil {
+		t.Fatalf("Failed to notify: %v", err)
+	}
+	if len(changes) == 0 || len(posted) != 1 {
+		t.Fatalf("Expected the changes posted once, got %d changes and %d posts", len(changes), len(posted))
+	}
+	payload := posted[0]
+	if text, _ := payload["text"].(string); !strings.HasPrefix(text, "Orders API 2.0.0: ") {
+		t.Errorf("Expected the summary as text, got %q", payload["text"])
+	}
+	if payload["previous_version"] != "1.0.0" || payload["version"] != "2.0.0" || payload["breaking"] == 0.0 {
+		t.Errorf("Unexpected payload %v", payload)
+	}
+
+	baseline, _ = json.Marshal(api.GenerateOpenAPISpec())
+	if changes, err := api.NotifySpecChanges(context.Background(), notifier); err != nil || len(changes) != 0 || len(posted) != 1 {
+		t.Errorf("Expected nothing posted without changes, got %v %v", changes, err)
+	}
+}
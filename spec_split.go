@@ -0,0 +1,147 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemaRefPrefix starts the $refs of component schemas in a bundled spec
+const schemaRefPrefix = "#/components/schemas/"
+
+// SpecExportOptions chooses the layout of ExportOpenAPIYAML
+type SpecExportOptions struct {
+	// Split writes the spec as openapi.yaml referencing one file per path
+	// under paths/ and one file per schema under components/, so spec
+	// reviews show the paths and schemas that changed. Otherwise openapi.yaml
+	// bundles the whole spec.
+	Split bool
+}
+
+// OpenAPIFiles renders the spec as YAML files in the layout of opts. Keys are
+// slash-separated paths relative to the output directory; openapi.yaml is
+// the root document.
+func (an *ApiNote) OpenAPIFiles(opts SpecExportOptions) (map[string][]byte, error) {
+	data, err := json.Marshal(an.GenerateOpenAPISpec())
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	root := document.Content[0]
+
+	files := make(map[string]*yaml.Node)
+	if opts.Split {
+		if paths := mappingValue(root, "paths"); paths != nil {
+			for i := 0; i+1 < len(paths.Content); i += 2 {
+				name := "paths/" + specPathFileName(paths.Content[i].Value)
+				if _, taken := files[name]; taken {
+					return nil, fmt.Errorf("paths %s and another path both map to %s", paths.Content[i].Value, name)
+				}
+				files[name] = paths.Content[i+1]
+				rewriteSplitRefs(paths.Content[i+1], "../components/", "../")
+				paths.Content[i+1] = refNode(name)
+			}
+		}
+		if schemas := mappingValue(mappingValue(root, "components"), "schemas"); schemas != nil {
+			for i := 0; i+1 < len(schemas.Content); i += 2 {
+				name := "components/" + schemas.Content[i].Value + ".yaml"
+				files[name] = schemas.Content[i+1]
+				rewriteSplitRefs(schemas.Content[i+1], "./", "../")
+				schemas.Content[i+1] = refNode(name)
+			}
+		}
+	}
+	files["openapi.yaml"] = root
+
+	rendered := make(map[string][]byte, len(files))
+	for name, node := range files {
+		blockStyle(node)
+		out, err := yaml.Marshal(node)
+		if err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", name, err)
+		}
+		rendered[name] = out
+	}
+	return rendered, nil
+}
+
+// ExportOpenAPIYAML writes the spec as YAML to dir in the layout of opts, see
+// OpenAPIFiles. Files of paths and schemas that no longer exist are left in
+// place.
+func (an *ApiNote) ExportOpenAPIYAML(dir string, opts SpecExportOptions) error {
+	files, err := an.OpenAPIFiles(opts)
+	if err != nil {
+		return fmt.Errorf("failed to render OpenAPI spec: %w", err)
+	}
+	for name, data := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	return nil
+}
+
+// specPathFileName names the file of a path of the spec after its segments,
+// e.g. /v1/users/{id} -> v1_users_{id}.yaml
+func specPathFileName(path string) string {
+	name := strings.ReplaceAll(strings.Trim(path, "/"), "/", "_")
+	if name == "" {
+		name = "root"
+	}
+	return name + ".yaml"
+}
+
+// mappingValue returns the value of a key of a YAML mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// refNode returns a mapping of a single $ref
+func refNode(target string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "$ref"},
+		{Kind: yaml.ScalarNode, Value: target},
+	}}
+}
+
+// rewriteSplitRefs points the $refs of a file split off the spec at the
+// files of the schemas, under schemaDir, and other local $refs at the root
+// document, under rootDir
+func rewriteSplitRefs(node *yaml.Node, schemaDir, rootDir string) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value := node.Content[i+1]
+			if node.Content[i].Value != "$ref" || value.Kind != yaml.ScalarNode {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(value.Value, schemaRefPrefix):
+				value.Value = schemaDir + strings.TrimPrefix(value.Value, schemaRefPrefix) + ".yaml"
+			case strings.HasPrefix(value.Value, "#/"):
+				value.Value = rootDir + "openapi.yaml" + value.Value
+			}
+		}
+	}
+	for _, child := range node.Content {
+		rewriteSplitRefs(child, schemaDir, rootDir)
+	}
+}
@@ -0,0 +1,78 @@
+package notelink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestExportOpenAPIYAML tests exporting the spec bundled and split into files
+func TestExportOpenAPIYAML(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return nil }
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/v1/users/:id", Description: "Get user", Handler: handler, Params: []Parameter{{Name: "id", In: "path", Type: "string", Required: true}}, SchemasResponse: exportUser{}},
+		{Method: "POST", Path: "/v1/users", Description: "Create user", Handler: handler, SchemasRequest: exportUser{}, SchemasResponse: exportUser{}},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		split    bool
+		contains map[string][]string
+		absent   []string
+	}{
+		{
+			name: "bundled",
+			contains: map[string][]string{
+				"openapi.yaml": {"    /v1/users/{id}:\n        get:", "$ref: '#/components/schemas/exportUser'", "    exportUser:\n            properties:"},
+			},
+			absent: []string{"paths", "components"},
+		},
+		{
+			name:  "split",
+			split: true,
+			contains: map[string][]string{
+				"openapi.yaml":                  {"    /v1/users/{id}:\n        $ref: paths/v1_users_{id}.yaml", "        exportUser:\n            $ref: components/exportUser.yaml"},
+				"paths/v1_users_{id}.yaml":      {"get:\n", "operationId: getUsersById"},
+				"paths/v1_users.yaml":           {"post:\n", "$ref: ../components/exportUser.yaml"},
+				"components/exportUser.yaml":    {"type: object", "$ref: ./exportAddress.yaml"},
+				"components/exportAddress.yaml": {"city:"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := api.ExportOpenAPIYAML(dir, SpecExportOptions{Split: tt.split}); err != nil {
+				t.Fatalf("Failed to export spec: %v", err)
+			}
+			for file, snippets := range tt.contains {
+				data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+				if err != nil {
+					t.Fatalf("Expected %s to be written: %v", file, err)
+				}
+				for _, snippet := range snippets {
+					if !strings.Contains(string(data), snippet) {
+						t.Errorf("Expected %s to contain %q, got:\n%s", file, snippet, data)
+					}
+				}
+				if tt.split && strings.Contains(string(data), "#/components/schemas/") {
+					t.Errorf("Expected %s to reference schema files, got:\n%s", file, data)
+				}
+			}
+			for _, name := range tt.absent {
+				if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+					t.Errorf("Expected no %s directory", name)
+				}
+			}
+		})
+	}
+}
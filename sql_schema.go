@@ -0,0 +1,227 @@
+package notelink
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+)
+
+// SQL dialects of GenerateSQLSchema
+const (
+	SQLDialectPostgres = "postgres" // Default
+	SQLDialectMySQL    = "mysql"
+	SQLDialectSQLite   = "sqlite"
+)
+
+// SQLSchemaOptions configures GenerateSQLSchema
+type SQLSchemaOptions struct {
+	// Dialect selects the column types and identifier quoting:
+	// SQLDialectPostgres (default), SQLDialectMySQL or SQLDialectSQLite
+	Dialect string
+	// Resources are extra resource structs to sketch tables for, besides the
+	// resources registered with CRUD, e.g. []interface{}{Invoice{}}
+	Resources []interface{}
+}
+
+// resourceType is a resource struct registered with CRUD
+type resourceType struct {
+	name string // CRUDOptions.Name
+	typ  reflect.Type
+}
+
+// sqlColumn is a column of a sketched table
+type sqlColumn struct {
+	name, sqlType, check, comment string
+	nullable                      bool
+}
+
+// GenerateSQLSchema sketches a CREATE TABLE statement per resource struct, as
+// a starting point when the API's schemas drive the storage design. It is
+// experimental: review keys, indexes, lengths and constraints before use.
+//
+// Resources registered with CRUD and SQLSchemaOptions.Resources get a table
+// named after their plural snake_case name. Columns are named by their `db`
+// tag, else their JSON name, and are NOT NULL unless the field is a pointer or
+// sql null type. An "id" column is the primary key, enum values become CHECK
+// constraints, and nested structs, maps and slices are JSON columns.
+func (an *ApiNote) GenerateSQLSchema(opts SQLSchemaOptions) string {
+	resources := append([]resourceType(nil), an.resources...)
+	for _, resource := range opts.Resources {
+		typ := reflect.TypeOf(resource)
+		if typ == nil {
+			continue
+		}
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		resources = append(resources, resourceType{name: SchemaNameShort(typ), typ: typ})
+	}
+
+	var sql strings.Builder
+	sql.WriteString("-- Tables for " + an.config.Title + ", generated by notelink\n")
+	sql.WriteString("-- Experimental sketch: review keys, indexes and constraints before use\n")
+	seen := make(map[string]bool)
+	for _, resource := range resources {
+		if resource.typ.Kind() != reflect.Struct {
+			continue
+		}
+		table := pluralTableName(terraformName(resource.name))
+		if seen[table] {
+			continue
+		}
+		seen[table] = true
+
+		columns := sqlColumns(resource.typ, opts.Dialect)
+		sql.WriteString("\nCREATE TABLE " + quoteSQLIdentifier(table, opts.Dialect) + " (\n")
+		for i, column := range columns {
+			sql.WriteString("  " + quoteSQLIdentifier(column.name, opts.Dialect) + " " + column.sqlType)
+			switch {
+			case column.name == "id":
+				sql.WriteString(" PRIMARY KEY")
+			case !column.nullable:
+				sql.WriteString(" NOT NULL")
+			}
+			if column.check != "" {
+				sql.WriteString(" CHECK (" + quoteSQLIdentifier(column.name, opts.Dialect) + " IN (" + column.check + "))")
+			}
+			if i < len(columns)-1 {
+				sql.WriteString(",")
+			}
+			if column.comment != "" {
+				sql.WriteString(" -- " + column.comment)
+			}
+			sql.WriteString("\n")
+		}
+		sql.WriteString(");\n")
+	}
+	return sql.String()
+}
+
+// ExportSQLSchemaToFile exports the CREATE TABLE sketches to a .sql file
+func (an *ApiNote) ExportSQLSchemaToFile(filepath string, opts SQLSchemaOptions) error {
+	if err := os.WriteFile(filepath, []byte(an.GenerateSQLSchema(opts)), 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// sqlColumns returns the columns of a resource struct, flattening embedded
+// structs like encoding/json
+func sqlColumns(typ reflect.Type, dialect string) []sqlColumn {
+	var columns []sqlColumn
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			columns = append(columns, sqlColumns(field.Type, dialect)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := validation.JSONFieldName(&field)
+		if db := strings.Split(field.Tag.Get("db"), ",")[0]; db != "" {
+			name = db
+		}
+		if name == "-" {
+			continue
+		}
+
+		column := sqlColumn{name: name, nullable: validation.IsNullable(field.Type), comment: field.Tag.Get("doc")}
+		valueType := field.Type
+		if valueType.Kind() == reflect.Ptr {
+			valueType = valueType.Elem()
+		}
+		if nullType, ok := validation.NullableValueType(valueType); ok {
+			valueType = nullType
+		}
+		column.sqlType = sqlColumnType(valueType, dialect)
+		if values := validation.EnumValues(&field); len(values) > 0 && valueType.Kind() == reflect.String {
+			quoted := make([]string, len(values))
+			for i, value := range values {
+				quoted[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+			}
+			column.check = strings.Join(quoted, ", ")
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// sqlColumnType maps a Go type to a column type of the dialect
+func sqlColumnType(t reflect.Type, dialect string) string {
+	types := map[string][3]string{ // postgres, mysql, sqlite
+		"text":      {"TEXT", "VARCHAR(255)", "TEXT"},
+		"boolean":   {"BOOLEAN", "BOOLEAN", "INTEGER"},
+		"integer":   {"INTEGER", "INT", "INTEGER"},
+		"bigint":    {"BIGINT", "BIGINT", "INTEGER"},
+		"double":    {"DOUBLE PRECISION", "DOUBLE", "REAL"},
+		"timestamp": {"TIMESTAMPTZ", "DATETIME", "TEXT"},
+		"ip":        {"INET", "VARCHAR(45)", "TEXT"},
+		"bytes":     {"BYTEA", "BLOB", "BLOB"},
+		"json":      {"JSONB", "JSON", "TEXT"},
+	}
+	pick := func(kind string) string {
+		switch dialect {
+		case SQLDialectMySQL:
+			return types[kind][1]
+		case SQLDialectSQLite:
+			return types[kind][2]
+		default:
+			return types[kind][0]
+		}
+	}
+
+	switch t {
+	case timeType:
+		return pick("timestamp")
+	case reflect.TypeOf(time.Duration(0)):
+		return pick("bigint") // Nanoseconds
+	case reflect.TypeOf(net.IP{}):
+		return pick("ip")
+	}
+	if _, ok := lookupStdType(t); ok {
+		return pick("text")
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return pick("text")
+	case reflect.Bool:
+		return pick("boolean")
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return pick("integer")
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return pick("bigint")
+	case reflect.Float32, reflect.Float64:
+		return pick("double")
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return pick("bytes")
+		}
+	}
+	return pick("json")
+}
+
+// quoteSQLIdentifier quotes a table or column name for the dialect
+func quoteSQLIdentifier(name, dialect string) string {
+	if dialect == SQLDialectMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// pluralTableName returns the naive English plural of a snake_case table name
+func pluralTableName(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && !strings.HasSuffix(name, "ay") && !strings.HasSuffix(name, "ey") && !strings.HasSuffix(name, "oy"):
+		return strings.TrimSuffix(name, "y") + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
@@ -0,0 +1,76 @@
+package notelink
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sqlAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type sqlInvoice struct {
+	sqlAudit
+	ID       string                 `json:"id"`
+	Number   int64                  `json:"number" doc:"Sequential invoice number"`
+	Status   enumStatus             `json:"status"`
+	PaidAt   *time.Time             `json:"paid_at,omitempty"`
+	Note     sql.NullString         `json:"note"`
+	Customer string                 `json:"customer" db:"customer_id"`
+	Lines    []struct{ SKU string } `json:"lines"`
+	Secret   string                 `json:"-"`
+}
+
+// TestGenerateSQLSchema tests sketching tables from CRUD and extra resources
+func TestGenerateSQLSchema(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	if err := CRUD(api, "/users", Repository[crudUser](&softDeleteUsers{}), CRUDOptions{Name: "user"}); err != nil {
+		t.Fatalf("Failed to register CRUD routes: %v", err)
+	}
+
+	tests := []struct {
+		dialect  string
+		contains []string
+	}{
+		{
+			dialect: "",
+			contains: []string{
+				`CREATE TABLE "users" (`,
+				"CREATE TABLE \"sql_invoices\" (\n  \"created_at\" TIMESTAMPTZ NOT NULL,\n  \"id\" TEXT PRIMARY KEY,",
+				`"number" BIGINT NOT NULL, -- Sequential invoice number`,
+				`"status" TEXT NOT NULL CHECK ("status" IN ('active', 'in_progress', 'closed')),`,
+				`"paid_at" TIMESTAMPTZ,`,
+				`"note" TEXT,`,
+				`"customer_id" TEXT NOT NULL,`,
+				"\"lines\" JSONB NOT NULL\n);",
+			},
+		},
+		{
+			dialect:  SQLDialectMySQL,
+			contains: []string{"CREATE TABLE `sql_invoices` (", "`paid_at` DATETIME,", "`lines` JSON NOT NULL"},
+		},
+		{
+			dialect:  SQLDialectSQLite,
+			contains: []string{`"number" INTEGER NOT NULL`, `"lines" TEXT NOT NULL`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("dialect "+tt.dialect, func(t *testing.T) {
+			ddl := api.GenerateSQLSchema(SQLSchemaOptions{Dialect: tt.dialect, Resources: []interface{}{&sqlInvoice{}}})
+			for _, fragment := range tt.contains {
+				if !strings.Contains(ddl, fragment) {
+					t.Errorf("Expected %q in:\n%s", fragment, ddl)
+				}
+			}
+			if strings.Contains(ddl, "secret") {
+				t.Errorf("Expected json:\"-\" fields left out, got:\n%s", ddl)
+			}
+		})
+	}
+
+	if got := pluralTableName("category") + " " + pluralTableName("address") + " " + pluralTableName("key"); got != "categories addresses keys" {
+		t.Errorf("Unexpected plurals %q", got)
+	}
+}
@@ -0,0 +1,48 @@
+package notelink
+
+import (
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// stdType describes how a standard library type with a string representation
+// is documented and exampled; the validation package validates them
+type stdType struct {
+	schema  JSONSchema
+	example string
+}
+
+// stdTypes maps standard library types to their string representations.
+// time.Time is handled separately since its format is configurable.
+var stdTypes = map[reflect.Type]stdType{
+	reflect.TypeOf(time.Duration(0)): {
+		schema: JSONSchema{
+			Type:        "string",
+			Format:      "duration",
+			Description: `Duration as a Go duration string (e.g. "1h30m") or ISO 8601 (e.g. "PT1H30M")`,
+		},
+		example: "1h30m",
+	},
+	reflect.TypeOf(net.IP{}): {
+		schema:  JSONSchema{Type: "string", Format: "ipv4", Description: "IPv4 or IPv6 address"},
+		example: "192.168.1.1",
+	},
+	reflect.TypeOf(url.URL{}): {
+		schema:  JSONSchema{Type: "string", Format: "uri"},
+		example: "https://example.com",
+	},
+}
+
+// lookupStdType returns the string mapping of t, if it is a known standard library type
+func lookupStdType(t reflect.Type) (stdType, bool) {
+	std, ok := stdTypes[t]
+	return std, ok
+}
+
+// jsonSchema returns a copy of the type's schema, safe to modify
+func (s stdType) jsonSchema() *JSONSchema {
+	schema := s.schema
+	return &schema
+}
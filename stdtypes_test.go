@@ -0,0 +1,116 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type Probe struct {
+	Timeout  time.Duration `json:"timeout"`
+	Address  net.IP        `json:"address"`
+	Target   url.URL       `json:"target"`
+	Callback *url.URL      `json:"callback,omitempty"`
+	Backups  []net.IP      `json:"backups,omitempty"`
+}
+
+// TestStdTypeMappings tests schemas, examples and TypeScript for stdlib string types
+func TestStdTypeMappings(t *testing.T) {
+	schema, components := generateJSONSchema("Probe", Probe{}, "", nil)
+	if _, ok := components["URL"]; ok {
+		t.Error("Expected url.URL not to become a component schema")
+	}
+
+	tests := []struct {
+		field   string
+		format  string
+		example string
+		tsType  string
+	}{
+		{field: "timeout", format: "duration", example: "1h30m", tsType: "timeout: string;"},
+		{field: "address", format: "ipv4", example: "192.168.1.1", tsType: "address: string;"},
+		{field: "target", format: "uri", example: "https://example.com", tsType: "target: string;"},
+		{field: "callback", format: "uri", example: "https://example.com", tsType: "callback?: string | null;"},
+	}
+
+	template, err := generateJSONTemplate(Probe{}, exampleOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var example map[string]interface{}
+	if err := json.Unmarshal([]byte(template), &example); err != nil {
+		t.Fatalf("Template is not valid JSON: %v", err)
+	}
+	ts := generateTypeScriptSchema("Probe", Probe{}, "", nil)
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			property := schema.Properties[tt.field]
+			if property.Type != "string" || property.Format != tt.format {
+				t.Errorf("Expected string/%s schema, got %s/%s", tt.format, property.Type, property.Format)
+			}
+			if example[tt.field] != tt.example {
+				t.Errorf("Expected example %q, got %v", tt.example, example[tt.field])
+			}
+			if !strings.Contains(ts, tt.tsType) {
+				t.Errorf("Expected %s in TypeScript, got:\n%s", tt.tsType, ts)
+			}
+		})
+	}
+
+	if items := schema.Properties["backups"].Items; items == nil || items.Format != "ipv4" {
+		t.Errorf("Expected []net.IP items to be IP strings, got %+v", items)
+	}
+	if strings.Contains(ts, "interface URL") {
+		t.Error("Expected no interface for url.URL")
+	}
+}
+
+// TestStdTypeValidation tests request validation of stdlib string types
+func TestStdTypeValidation(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/probes",
+		Handler:        func(c fiber.Ctx) error { return c.SendStatus(201) },
+		SchemasRequest: Probe{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "Valid", body: `{"timeout":"1h30m","address":"10.0.0.1","target":"https://example.com/health","backups":["::1"]}`, expectedStatus: 201},
+		{name: "ISO 8601 duration", body: `{"timeout":"PT1H30M","address":"10.0.0.1","target":"https://example.com"}`, expectedStatus: 201},
+		{name: "Invalid duration", body: `{"timeout":"90 minutes","address":"10.0.0.1","target":"https://example.com"}`, expectedStatus: 400},
+		{name: "Invalid IP", body: `{"timeout":"1h","address":"10.0.0.300","target":"https://example.com"}`, expectedStatus: 400},
+		{name: "Relative URL", body: `{"timeout":"1h","address":"10.0.0.1","target":"/health"}`, expectedStatus: 400},
+		{name: "Object URL", body: `{"timeout":"1h","address":"10.0.0.1","target":{"Host":"example.com"}}`, expectedStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/probes", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package notelink
+
+import (
+	"io"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+)
+
+// DefaultStreamValidationThreshold is the body size from which JSON request
+// bodies are validated while tokenizing them, unless
+// Config.StreamValidationThreshold says otherwise
+const DefaultStreamValidationThreshold = 1 << 20
+
+// streamValidationThreshold returns Config.StreamValidationThreshold, or its
+// default; 0 disables streaming validation
+func (an *ApiNote) streamValidationThreshold() int {
+	switch {
+	case an.config.StreamValidationThreshold == 0:
+		return DefaultStreamValidationThreshold
+	case an.config.StreamValidationThreshold < 0:
+		return 0
+	}
+	return an.config.StreamValidationThreshold
+}
+
+// ValidateJSONStream validates a JSON object read from r against schema while
+// tokenizing it, without materializing it as a map, so multi-MB bodies are
+// validated in little memory. It reports the same errors as ValidateJSON.
+func ValidateJSONStream(r io.Reader, schema interface{}) error {
+	return validation.JSONStream(r, schema, "")
+}
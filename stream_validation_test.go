@@ -0,0 +1,117 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type streamAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type streamOrder struct {
+	ID        int               `json:"id"`
+	Status    string            `json:"status" enum:"open,closed"`
+	Note      *string           `json:"note"`
+	Tags      []string          `json:"tags,omitempty"`
+	Address   streamAddress     `json:"address"`
+	Previous  []streamAddress   `json:"previous,omitempty"`
+	Meta      map[string]string `json:"meta,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	IP        net.IP            `json:"ip,omitempty"`
+	Extra     interface{}       `json:"extra,omitempty"`
+}
+
+// TestValidateJSONStream tests that streaming validation reports the errors
+// of validating the decoded body
+func TestValidateJSONStream(t *testing.T) {
+	valid := `{"id":1,"status":"open","address":{"city":"Oslo","zip":"0150"},"created_at":"2024-01-02T03:04:05Z"`
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"valid", valid + `}`},
+		{"valid with extras", valid + `,"tags":["a"],"meta":{"k":"v"},"unknown":{"deep":[1,{"x":null}]},"extra":[{}],"ip":"10.0.0.1"}`},
+		{"missing fields", `{"status":"open"}`},
+		{"null required", `{"id":null,"status":"open","address":{"city":"Oslo","zip":"0150"},"created_at":"2024-01-02T03:04:05Z"}`},
+		{"wrong types", `{"id":1.5,"status":3,"address":"Oslo","created_at":true,"tags":"a"}`},
+		{"enum", `{"id":1,"status":"pending","address":{"city":"Oslo","zip":"0150"},"created_at":"2024-01-02T03:04:05Z"}`},
+		{"nested", `{"id":1,"status":"open","address":{"city":7},"created_at":"2024-01-02T03:04:05Z"}`},
+		{"array elements", valid + `,"tags":["a",2,3],"previous":[{"city":"Oslo","zip":"1"},{"zip":"2"}]}`},
+		{"object for array", valid + `,"tags":{"a":1}}`},
+		{"array for map", valid + `,"meta":["v"]}`},
+		{"bad ip", valid + `,"ip":"nope"}`},
+		{"repeated member", `{"id":"x","id":2,"status":"open","address":{"city":"Oslo","zip":"0150"},"created_at":"2024-01-02T03:04:05Z"}`},
+		{"null body", `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := ValidateJSON([]byte(tt.body), streamOrder{}, nil)
+			got := ValidateJSONStream(strings.NewReader(tt.body), streamOrder{})
+			if !reflect.DeepEqual(got, want) {
+				wantJSON, _ := json.Marshal(want)
+				gotJSON, _ := json.Marshal(got)
+				t.Errorf("Expected %s, got %s", wantJSON, gotJSON)
+			}
+		})
+	}
+
+	for _, body := range []string{`[{"id":1}]`, `{"id":`, `{"id":1} {}`, `"text"`} {
+		err := ValidateJSONStream(strings.NewReader(body), streamOrder{})
+		if verr, ok := err.(*ValidationErrorResponse); !ok || verr.Errors[0].Type != "parse_error" {
+			t.Errorf("Expected a parse error for %s, got %v", body, err)
+		}
+	}
+}
+
+// TestStreamValidationThreshold tests validating large route bodies by streaming them
+func TestStreamValidationThreshold(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", StreamValidationThreshold: 64}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/orders",
+		Description:    "Create an order",
+		SchemasRequest: streamOrder{},
+		Handler:        func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if api.streamValidationThreshold() != 64 {
+		t.Errorf("Expected the configured threshold, got %d", api.streamValidationThreshold())
+	}
+
+	padding := `,"unknown":"` + strings.Repeat("x", 128) + `"`
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"large valid", `{"id":1,"status":"open","address":{"city":"Oslo","zip":"0150"},"created_at":"2024-01-02T03:04:05Z"` + padding + `}`, 200},
+		{"large invalid", `{"id":"1","status":"open","address":{"city":"Oslo","zip":"0150"},"created_at":"2024-01-02T03:04:05Z"` + padding + `}`, 400},
+		{"small invalid", `{"id":"1"}`, 400},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/orders", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -5,7 +5,7 @@ import (
 )
 
 // SwaggerUIHandler returns a handler that serves the Swagger UI
-// The Swagger UI is loaded from CDN and points to /api-docs/openapi.json
+// The Swagger UI is loaded from CDN (see Config.Assets) and points to the served openapi.json
 func (an *ApiNote) SwaggerUIHandler() fiber.Handler {
 	return func(c fiber.Ctx) error {
 		html := an.generateSwaggerHTML()
@@ -24,6 +24,16 @@ func (an *ApiNote) ScalarUIHandler() fiber.Handler {
 	}
 }
 
+// RedocUIHandler returns a handler that serves ReDoc, a read-only three-panel
+// reference pointing to the served openapi.json
+func (an *ApiNote) RedocUIHandler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		html := an.generateRedocHTML()
+		c.Set("Content-Type", "text/html")
+		return c.SendString(html)
+	}
+}
+
 // generateSwaggerHTML creates the Swagger UI HTML page
 func (an *ApiNote) generateSwaggerHTML() string {
 	return `<!DOCTYPE html>
@@ -32,8 +42,8 @@ func (an *ApiNote) generateSwaggerHTML() string {
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>` + an.config.Title + ` - Swagger UI</title>
-    <link rel="icon" type="image/png" sizes="32x32" href="/icon.png">
-    <link rel="stylesheet" type="text/css" href="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui.css">
+    <link rel="icon" type="image/png" sizes="32x32" href="` + an.docsPath() + `/icon.png">
+    ` + an.assetTag(AssetSwaggerUICSS) + `
     <style>
         body {
             margin: 0;
@@ -50,12 +60,12 @@ func (an *ApiNote) generateSwaggerHTML() string {
 <body>
     <div id="swagger-ui"></div>
 
-    <script src="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui-bundle.js"></script>
-    <script src="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui-standalone-preset.js"></script>
+    ` + an.assetTag(AssetSwaggerUIBundle) + `
+    ` + an.assetTag(AssetSwaggerUIStandalonePreset) + `
     <script>
         window.onload = function() {
             const ui = SwaggerUIBundle({
-                url: "/api-docs/openapi.json",
+                url: "` + an.docsPath() + `/openapi.json",
                 dom_id: '#swagger-ui',
                 deepLinking: true,
                 presets: [
@@ -85,7 +95,7 @@ func (an *ApiNote) generateScalarHTML() string {
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>` + an.config.Title + ` - Scalar API Documentation</title>
-    <link rel="icon" type="image/png" sizes="32x32" href="/icon.png">
+    <link rel="icon" type="image/png" sizes="32x32" href="` + an.docsPath() + `/icon.png">
     <style>
         body {
             margin: 0;
@@ -96,10 +106,33 @@ func (an *ApiNote) generateScalarHTML() string {
 <body>
     <script
         id="api-reference"
-        data-url="/api-docs/openapi.json"
+        data-url="` + an.docsPath() + `/openapi.json"
         data-configuration='{"showToolbar":"never","theme":"mars","hideClientButton":true,"customCss":":root { --scalar-font: ui-sans-serif, system-ui; --scalar-radius: 14px; --scalar-primary: 265 84% 54%; } [data-theme=\"dark\"] { --scalar-background-1: 230 15% 10%; --scalar-text-1: 0 0% 98%; }"}'
     ></script>
-    <script src="https://cdn.jsdelivr.net/npm/@scalar/api-reference"></script>
+    ` + an.assetTag(AssetScalar) + `
+</body>
+</html>`
+}
+
+// generateRedocHTML creates the ReDoc HTML page
+func (an *ApiNote) generateRedocHTML() string {
+	return `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>` + an.config.Title + ` - ReDoc</title>
+    <link rel="icon" type="image/png" sizes="32x32" href="` + an.docsPath() + `/icon.png">
+    <style>
+        body {
+            margin: 0;
+            padding: 0;
+        }
+    </style>
+</head>
+<body>
+    <redoc spec-url="` + an.docsPath() + `/openapi.json"></redoc>
+    ` + an.assetTag(AssetRedoc) + `
 </body>
 </html>`
 }
@@ -0,0 +1,118 @@
+package notelink
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TenantPathParam is the path parameter of Config.TenantPathPrefix tenancy
+const TenantPathParam = "tenant"
+
+// tenantDescription documents the tenancy parameter
+const tenantDescription = "Tenant identifier"
+
+// tenancyEnabled reports whether routes identify a tenant
+func (an *ApiNote) tenancyEnabled() bool {
+	return an.config.TenantHeader != "" || an.config.TenantPathPrefix
+}
+
+// Tenant returns the tenant identifier of a request, from Config.TenantHeader
+// or the /{tenant} path prefix
+func (an *ApiNote) Tenant(c fiber.Ctx) string {
+	if an.config.TenantPathPrefix {
+		return c.Params(TenantPathParam)
+	}
+	if an.config.TenantHeader != "" {
+		return c.Get(an.config.TenantHeader)
+	}
+	return ""
+}
+
+// tenantRoutePath returns a route's path below Config.BasePath, prefixed with
+// the tenant parameter in path-prefix tenancy. Built-in routes aren't tenanted.
+func (an *ApiNote) tenantRoutePath(input *DocumentedRouteInput) string {
+	if an.config.TenantPathPrefix && !input.builtin {
+		return "/:" + TenantPathParam + input.Path
+	}
+	return input.Path
+}
+
+// tenantParameter returns the parameter identifying the tenant
+func (an *ApiNote) tenantParameter() Parameter {
+	if an.config.TenantPathPrefix {
+		return Parameter{Name: TenantPathParam, In: "path", Type: "string", Description: tenantDescription, Required: true}
+	}
+	return Parameter{Name: an.config.TenantHeader, In: "header", Type: "string", Description: tenantDescription, Required: true}
+}
+
+// addTenantParameter declares the tenancy parameter on a route, so it is
+// documented on the operation and required by validation
+func (an *ApiNote) addTenantParameter(endpoint *Endpoint, input *DocumentedRouteInput) {
+	if !an.tenancyEnabled() || input.builtin {
+		return
+	}
+	tenant := an.tenantParameter()
+	for _, param := range endpoint.Parameters {
+		if param.In == tenant.In && strings.EqualFold(param.Name, tenant.Name) {
+			return
+		}
+	}
+	endpoint.Parameters = append([]Parameter{tenant}, endpoint.Parameters...)
+}
+
+// isTenantParameter reports whether a parameter is the tenancy parameter, which
+// the try-it forms fill from the tenant selector instead of an input
+func (an *ApiNote) isTenantParameter(param *Parameter) bool {
+	if !an.tenancyEnabled() {
+		return false
+	}
+	tenant := an.tenantParameter()
+	return param.In == tenant.In && strings.EqualFold(param.Name, tenant.Name)
+}
+
+// renderTenantSelector renders the docs control choosing the tenant of try-it
+// requests: a select of Config.Tenants, or a text input without them
+func (an *ApiNote) renderTenantSelector() string {
+	if !an.tenancyEnabled() {
+		return ""
+	}
+
+	var control strings.Builder
+	if len(an.config.Tenants) > 0 {
+		control.WriteString(`<select id="tenant-select" onchange="setTenant(this.value)">`)
+		for _, tenant := range an.config.Tenants {
+			control.WriteString(`<option value="` + escapeHTML(tenant) + `">` + escapeHTML(tenant) + `</option>`)
+		}
+		control.WriteString(`</select>`)
+	} else {
+		control.WriteString(`<input type="text" id="tenant-select" placeholder="Enter tenant identifier" onchange="setTenant(this.value)">`)
+	}
+
+	return `
+        <div class="auth-section tenant-section">
+            <h2><i class="fas fa-building"></i> Tenant</h2>
+            <div class="auth-input-group">
+                ` + control.String() + `
+            </div>
+        </div>`
+}
+
+// tenancyScript declares the tenancy settings used by the try-it script
+func (an *ApiNote) tenancyScript() string {
+	header, path, fallback := "", "", ""
+	if an.tenancyEnabled() {
+		tenant := an.tenantParameter()
+		if tenant.In == "path" {
+			path = tenant.Name
+		} else {
+			header = tenant.Name
+		}
+	}
+	if len(an.config.Tenants) > 0 {
+		fallback = an.config.Tenants[0]
+	}
+	return `
+            const tenancy = { header: '` + escapeJavaScript(header) + `', path: '` + escapeJavaScript(path) + `' };
+            let tenant = localStorage.getItem('tenant') || '` + escapeJavaScript(fallback) + `';`
+}
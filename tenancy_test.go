@@ -0,0 +1,108 @@
+package notelink
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestTenancy tests header and path-prefix tenancy in routing, the spec and the docs
+func TestTenancy(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		specPath   string
+		param      ParameterSpec
+		request    func() *http.Request
+		missing    string // Request without a tenant
+		wantStatus int
+	}{
+		{
+			name:     "header",
+			config:   Config{TenantHeader: "X-Tenant-ID", Tenants: []string{"acme", "globex"}},
+			specPath: "/users",
+			param:    ParameterSpec{Name: "X-Tenant-ID", In: "header"},
+			request: func() *http.Request {
+				req := httptest.NewRequest("GET", "/v1/users", nil)
+				req.Header.Set("X-Tenant-ID", "acme")
+				return req
+			},
+			missing:    "/v1/users",
+			wantStatus: fiber.StatusBadRequest,
+		},
+		{
+			name:     "path prefix",
+			config:   Config{TenantPathPrefix: true},
+			specPath: "/{tenant}/users",
+			param:    ParameterSpec{Name: "tenant", In: "path"},
+			request: func() *http.Request {
+				return httptest.NewRequest("GET", "/v1/acme/users", nil)
+			},
+			missing:    "/v1/users",
+			wantStatus: fiber.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.Title, config.Host, config.BasePath = "Test API", "localhost:8080", "/v1"
+			api := NewApiNote(&config, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:  "GET",
+				Path:    "/users",
+				Handler: func(c fiber.Ctx) error { return c.SendString("users of " + api.Tenant(c)) },
+			})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			resp, err := api.Fiber().Test(tt.request())
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "users of acme" {
+				t.Errorf("Expected the handler to see the tenant, got %d %q", resp.StatusCode, body)
+			}
+			resp, err = api.Fiber().Test(httptest.NewRequest("GET", tt.missing, nil))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d without a tenant, got %d", tt.wantStatus, resp.StatusCode)
+			}
+
+			operation := api.GenerateOpenAPISpec().Paths[tt.specPath].Get
+			if operation == nil {
+				t.Fatalf("Expected an operation at %s", tt.specPath)
+			}
+			found := false
+			for _, param := range operation.Parameters {
+				if param.Name == tt.param.Name && param.In == tt.param.In && param.Required {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected a required %s %s parameter, got %+v", tt.param.In, tt.param.Name, operation.Parameters)
+			}
+
+			html := api.generateHTML()
+			if !strings.Contains(html, `id="tenant-select"`) {
+				t.Error("Expected a tenant selector in the docs")
+			}
+			if strings.Contains(html, `name="`+tt.param.Name+`"`) {
+				t.Error("Expected the try-it form to fill the tenant from the selector")
+			}
+			for _, tenant := range config.Tenants {
+				if !strings.Contains(html, `<option value="`+tenant+`">`) {
+					t.Errorf("Expected tenant %q in the selector", tenant)
+				}
+			}
+		})
+	}
+}
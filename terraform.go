@@ -0,0 +1,81 @@
+package notelink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// GenerateTerraform creates Terraform resources exposing every documented
+// endpoint on an AWS API Gateway HTTP API: an aws_apigatewayv2_integration
+// proxying to the upstream and an aws_apigatewayv2_route per endpoint.
+// Wildcard segments become greedy {name+} path parameters.
+func (an *ApiNote) GenerateTerraform(opts GatewayOptions) string {
+	apiID := opts.TerraformAPIID
+	if apiID == "" {
+		apiID = "var.api_id"
+	}
+	upstream := an.upstreamURL(&opts)
+
+	var tf strings.Builder
+	tf.WriteString("# Routes for " + an.config.Title + ", generated by notelink\n")
+	for _, endpoint := range an.sortedEndpoints() {
+		name := terraformName(generateOperationID(endpoint.Method, endpoint.routePath()))
+		method := strings.ToUpper(endpoint.Method)
+
+		tf.WriteString(`
+resource "aws_apigatewayv2_integration" "` + name + `" {
+  api_id             = ` + apiID + `
+  integration_type   = "HTTP_PROXY"
+  integration_method = ` + hclString(method) + `
+  integration_uri    = ` + hclString(upstream+openAPIPath(endpoint.Path)) + `
+}
+
+resource "aws_apigatewayv2_route" "` + name + `" {
+  api_id    = ` + apiID + `
+  route_key = ` + hclString(method+" "+awsPath(endpoint.Path)) + `
+  target    = "integrations/${aws_apigatewayv2_integration.` + name + `.id}"`)
+		if endpoint.AuthRequired && opts.TerraformAuthorizerID != "" {
+			tf.WriteString(`
+
+  authorization_type = "JWT"
+  authorizer_id      = ` + opts.TerraformAuthorizerID)
+		}
+		tf.WriteString("\n}\n")
+	}
+	return tf.String()
+}
+
+// terraformName converts an operation id to a snake_case resource name
+// Example: getUsersById -> get_users_by_id
+func terraformName(operationID string) string {
+	var name strings.Builder
+	for i, r := range operationID {
+		if unicode.IsUpper(r) && i > 0 {
+			name.WriteByte('_')
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			name.WriteRune(unicode.ToLower(r))
+		} else {
+			name.WriteByte('_')
+		}
+	}
+	return name.String()
+}
+
+// hclString quotes a literal HCL string, escaping template sequences
+func hclString(s string) string {
+	data, _ := json.Marshal(s)
+	quoted := strings.ReplaceAll(string(data), "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
+
+// ExportTerraformToFile exports the Terraform route resources to a .tf file
+func (an *ApiNote) ExportTerraformToFile(filepath string, opts GatewayOptions) error {
+	if err := os.WriteFile(filepath, []byte(an.GenerateTerraform(opts)), 0o600); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestGenerateTerraform tests the API Gateway route resources
+func TestGenerateTerraform(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Orders API", Host: "localhost:8080", BasePath: "/v1"}, "secret")
+	handler := func(c fiber.Ctx) error { return nil }
+	authRequired := true
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/orders/:id", Handler: handler, AuthRequired: &authRequired},
+		{Method: "GET", Path: "/files/*", Handler: handler},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		opts    GatewayOptions
+		present []string
+		absent  []string
+	}{
+		{
+			name: "Defaults",
+			present: []string{
+				`resource "aws_apigatewayv2_integration" "get_orders_by_id" {`,
+				`  api_id             = var.api_id`,
+				`  integration_uri    = "http://localhost:8080/v1/orders/{id}"`,
+				`resource "aws_apigatewayv2_route" "get_orders_by_id" {`,
+				`  route_key = "GET /v1/orders/{id}"`,
+				`  target    = "integrations/${aws_apigatewayv2_integration.get_orders_by_id.id}"`,
+				`  route_key = "GET /v1/files/{wildcard+}"`,
+			},
+			absent: []string{"authorization_type"},
+		},
+		{
+			name: "Custom API and authorizer",
+			opts: GatewayOptions{UpstreamURL: "https://orders.internal", TerraformAPIID: "aws_apigatewayv2_api.main.id", TerraformAuthorizerID: "aws_apigatewayv2_authorizer.jwt.id"},
+			present: []string{
+				`  api_id    = aws_apigatewayv2_api.main.id`,
+				`  integration_uri    = "https://orders.internal/v1/orders/{id}"`,
+				"  authorization_type = \"JWT\"\n  authorizer_id      = aws_apigatewayv2_authorizer.jwt.id",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tf := api.GenerateTerraform(tt.opts)
+			for _, snippet := range tt.present {
+				if !strings.Contains(tf, snippet) {
+					t.Errorf("Expected Terraform to contain %q, got:\n%s", snippet, tf)
+				}
+			}
+			for _, snippet := range tt.absent {
+				if strings.Contains(tf, snippet) {
+					t.Errorf("Expected Terraform not to contain %q", snippet)
+				}
+			}
+			if strings.Count(tf, "authorization_type") > 1 {
+				t.Error("Expected only routes requiring auth to get the authorizer")
+			}
+		})
+	}
+
+	if got := hclString("${var.x} %{if}"); got != `"$${var.x} %%{if}"` {
+		t.Errorf("Expected template sequences to be escaped, got %s", got)
+	}
+}
@@ -0,0 +1,115 @@
+package notelink
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// ThresholdKind names the limit a request exceeded
+type ThresholdKind string
+
+const (
+	ThresholdLatency      ThresholdKind = "latency"
+	ThresholdRequestBody  ThresholdKind = "request_body"
+	ThresholdResponseBody ThresholdKind = "response_body"
+)
+
+// Thresholds are the latency and body-size limits of a route; requests
+// exceeding them are logged and reported to the OnThresholdExceeded hooks.
+// Zero disables a limit.
+type Thresholds struct {
+	Latency          time.Duration `json:"latency,omitempty"`
+	RequestBodySize  int           `json:"requestBodySize,omitempty"`  // Bytes
+	ResponseBodySize int           `json:"responseBodySize,omitempty"` // Bytes, as sent (after compression)
+}
+
+// ThresholdEvent reports a request that exceeded a limit of its route
+type ThresholdEvent struct {
+	Kind         ThresholdKind
+	Method       string
+	Path         string // Documented route pattern, e.g. /v1/users/:id
+	Status       int
+	Latency      time.Duration
+	RequestSize  int
+	ResponseSize int
+	Limits       Thresholds
+	RequestID    string // See RequestID
+}
+
+// OnThresholdExceeded calls fn for each request exceeding the latency or
+// body-size limits of its route (Config.Thresholds or
+// DocumentedRouteInput.Thresholds), e.g. to feed SLO alerts. fn runs on the
+// request's goroutine after the handler, once per exceeded limit.
+func (an *ApiNote) OnThresholdExceeded(fn func(event ThresholdEvent)) {
+	an.hooks.thresholdExceeded = append(an.hooks.thresholdExceeded, fn)
+}
+
+// thresholds returns the limits of a route: its own, or Config.Thresholds
+func (an *ApiNote) thresholds(input *DocumentedRouteInput) *Thresholds {
+	limits := input.Thresholds
+	if limits == nil {
+		limits = an.config.Thresholds
+	}
+	if limits == nil || *limits == (Thresholds{}) {
+		return nil
+	}
+	copied := *limits
+	return &copied
+}
+
+// thresholdsMiddleware measures the latency and body sizes of an endpoint's
+// requests, reporting those exceeding its limits
+func (an *ApiNote) thresholdsMiddleware(endpoint *Endpoint) fiber.Handler {
+	limits := *endpoint.Thresholds
+	method, path := endpoint.Method, endpoint.Path
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		event := ThresholdEvent{
+			Method:       method,
+			Path:         path,
+			Status:       c.Response().StatusCode(),
+			Latency:      time.Since(start),
+			RequestSize:  len(c.Body()),
+			ResponseSize: len(c.Response().Body()),
+			Limits:       limits,
+			RequestID:    RequestID(c),
+		}
+		// Errors are rendered by the ErrorHandler after the route returns
+		if err != nil {
+			event.Status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				event.Status = fiberErr.Code
+			}
+		}
+
+		if limits.Latency > 0 && event.Latency > limits.Latency {
+			event.Kind = ThresholdLatency
+			an.reportThreshold(event, event.Latency.String(), limits.Latency.String())
+		}
+		if limits.RequestBodySize > 0 && event.RequestSize > limits.RequestBodySize {
+			event.Kind = ThresholdRequestBody
+			an.reportThreshold(event, strconv.Itoa(event.RequestSize)+"B", strconv.Itoa(limits.RequestBodySize)+"B")
+		}
+		if limits.ResponseBodySize > 0 && event.ResponseSize > limits.ResponseBodySize {
+			event.Kind = ThresholdResponseBody
+			an.reportThreshold(event, strconv.Itoa(event.ResponseSize)+"B", strconv.Itoa(limits.ResponseBodySize)+"B")
+		}
+		return err
+	}
+}
+
+// reportThreshold logs an exceeded limit and runs the OnThresholdExceeded hooks
+func (an *ApiNote) reportThreshold(event ThresholdEvent, value, limit string) {
+	if event.RequestID != "" {
+		log.Warnf("notelink: %s %s exceeded the %s threshold: %s > %s (request %s)", event.Method, event.Path, event.Kind, value, limit, event.RequestID)
+	} else {
+		log.Warnf("notelink: %s %s exceeded the %s threshold: %s > %s", event.Method, event.Path, event.Kind, value, limit)
+	}
+	an.hooks.runThresholdExceeded(event)
+}
@@ -0,0 +1,77 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestThresholds tests reporting requests that exceed latency and body-size limits
+func TestThresholds(t *testing.T) {
+	api := NewApiNote(&Config{
+		Title:      "Test API",
+		Host:       "localhost:8080",
+		Thresholds: &Thresholds{RequestBodySize: 16, ResponseBodySize: 8},
+	}, "secret")
+	var events []ThresholdEvent
+	api.OnThresholdExceeded(func(event ThresholdEvent) { events = append(events, event) })
+
+	routes := []DocumentedRouteInput{
+		{Method: "POST", Path: "/v1/echo/:id", Handler: func(c fiber.Ctx) error { return c.Send(c.Body()) }},
+		{Method: "GET", Path: "/v1/slow", Thresholds: &Thresholds{Latency: time.Millisecond}, Handler: func(c fiber.Ctx) error {
+			time.Sleep(5 * time.Millisecond)
+			return fiber.NewError(fiber.StatusServiceUnavailable, "busy")
+		}},
+	}
+	for i := range routes {
+		if err := api.DocumentedRoute(&routes[i]); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		expected []ThresholdEvent
+	}{
+		{"within limits", "POST", "/v1/echo/1", "small", nil},
+		{"large bodies", "POST", "/v1/echo/1", strings.Repeat("x", 32), []ThresholdEvent{
+			{Kind: ThresholdRequestBody, Path: "/v1/echo/:id", Status: fiber.StatusOK, RequestSize: 32},
+			{Kind: ThresholdResponseBody, Path: "/v1/echo/:id", Status: fiber.StatusOK, ResponseSize: 32},
+		}},
+		{"slow route override", "GET", "/v1/slow", "", []ThresholdEvent{
+			{Kind: ThresholdLatency, Path: "/v1/slow", Status: fiber.StatusServiceUnavailable},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events = nil
+			resp, err := api.Fiber().Test(httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			resp.Body.Close()
+			if len(events) != len(tt.expected) {
+				t.Fatalf("Expected %d events, got %+v", len(tt.expected), events)
+			}
+			for i, want := range tt.expected {
+				got := events[i]
+				if got.Kind != want.Kind || got.Path != want.Path || got.Status != want.Status {
+					t.Errorf("Expected event %+v, got %+v", want, got)
+				}
+				if want.RequestSize > 0 && got.RequestSize != want.RequestSize || want.ResponseSize > 0 && got.ResponseSize != want.ResponseSize {
+					t.Errorf("Expected sizes %d/%d, got %d/%d", want.RequestSize, want.ResponseSize, got.RequestSize, got.ResponseSize)
+				}
+				if want.Kind == ThresholdLatency && got.Latency < got.Limits.Latency {
+					t.Errorf("Expected a latency above %s, got %s", got.Limits.Latency, got.Latency)
+				}
+			}
+		})
+	}
+}
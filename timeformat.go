@@ -0,0 +1,59 @@
+package notelink
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+)
+
+// Time formats for time.Time fields, set per field with a `timeformat:"unix"`
+// struct tag or for the whole API with Config.TimeFormat. Any other value is
+// treated as a Go time layout, e.g. "2006-01-02".
+const (
+	TimeFormatRFC3339   = validation.TimeFormatRFC3339   // RFC 3339 string (encoding/json's default)
+	TimeFormatUnix      = validation.TimeFormatUnix      // Seconds since the Unix epoch
+	TimeFormatUnixMilli = validation.TimeFormatUnixMilli // Milliseconds since the Unix epoch
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// timeJSONSchema returns the JSON Schema of a time value in the given format
+func timeJSONSchema(format string) *JSONSchema {
+	switch {
+	case strings.EqualFold(format, TimeFormatUnix):
+		return &JSONSchema{Type: "integer", Format: "int64", Description: "Unix timestamp in seconds"}
+	case strings.EqualFold(format, TimeFormatUnixMilli):
+		return &JSONSchema{Type: "integer", Format: "int64", Description: "Unix timestamp in milliseconds"}
+	case validation.TimeLayout(format) == time.RFC3339:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case validation.TimeLayout(format) == time.DateOnly:
+		return &JSONSchema{Type: "string", Format: "date"}
+	default:
+		return &JSONSchema{Type: "string", Description: "Time in Go layout " + format}
+	}
+}
+
+// exampleTime is the fixed time used in examples, so generated payloads are stable
+var exampleTime = time.Date(2024, time.January, 15, 9, 30, 0, 0, time.UTC)
+
+// timeExample returns an example time value in the given format
+func timeExample(format string) interface{} {
+	switch {
+	case strings.EqualFold(format, TimeFormatUnix):
+		return exampleTime.Unix()
+	case strings.EqualFold(format, TimeFormatUnixMilli):
+		return exampleTime.UnixMilli()
+	default:
+		return exampleTime.Format(validation.TimeLayout(format))
+	}
+}
+
+// timeTsType returns the TypeScript type of a time value in the given format
+func timeTsType(format string) string {
+	if validation.IsUnixTimeFormat(format) {
+		return "number"
+	}
+	return "string"
+}
@@ -0,0 +1,114 @@
+package notelink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type Event struct {
+	StartsAt  time.Time   `json:"starts_at"`
+	EndsAt    time.Time   `json:"ends_at" timeformat:"unix"`
+	Day       time.Time   `json:"day" timeformat:"2006-01-02"`
+	Reminders []time.Time `json:"reminders,omitempty" timeformat:"unixmilli"`
+}
+
+// TestTimeFormats tests time.Time formats across examples, schemas, TypeScript and validation
+func TestTimeFormats(t *testing.T) {
+	tests := []struct {
+		name       string
+		timeFormat string
+		field      string
+		schemaType string
+		format     string
+		tsType     string
+		number     bool
+	}{
+		{name: "Default RFC 3339", field: "starts_at", schemaType: "string", format: "date-time", tsType: "string"},
+		{name: "Config default unix", timeFormat: TimeFormatUnix, field: "starts_at", schemaType: "integer", format: "int64", tsType: "number", number: true},
+		{name: "Unix tag", field: "ends_at", schemaType: "integer", format: "int64", tsType: "number", number: true},
+		{name: "Tag overrides config", timeFormat: TimeFormatUnix, field: "day", schemaType: "string", format: "date", tsType: "string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := generateJSONTemplate(Event{}, exampleOptions{TimeFormat: tt.timeFormat})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var example map[string]interface{}
+			if err := json.Unmarshal([]byte(template), &example); err != nil {
+				t.Fatalf("Template is not valid JSON: %v", err)
+			}
+			_, isNumber := example[tt.field].(float64)
+			if isNumber != tt.number {
+				t.Errorf("Expected %s example to be numeric: %v, got %v", tt.field, tt.number, example[tt.field])
+			}
+
+			schema, _ := generateJSONSchema("Event", Event{}, tt.timeFormat, nil)
+			property := schema.Properties[tt.field]
+			if property.Type != tt.schemaType || property.Format != tt.format {
+				t.Errorf("Expected %s/%s schema, got %s/%s", tt.schemaType, tt.format, property.Type, property.Format)
+			}
+
+			ts := generateTypeScriptSchema("Event", Event{}, tt.timeFormat, nil)
+			if !strings.Contains(ts, tt.field+": "+tt.tsType+";") {
+				t.Errorf("Expected %s: %s in TypeScript, got:\n%s", tt.field, tt.tsType, ts)
+			}
+			if strings.Contains(ts, "interface Time") {
+				t.Error("Expected no interface for time.Time")
+			}
+		})
+	}
+
+	reminders, _ := generateJSONSchema("Event", Event{}, "", nil)
+	if items := reminders.Properties["reminders"].Items; items == nil || items.Type != "integer" {
+		t.Errorf("Expected unixmilli items to be integers, got %+v", items)
+	}
+}
+
+// TestTimeFormatValidation tests request validation of time fields
+func TestTimeFormatValidation(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/events",
+		Handler:        func(c fiber.Ctx) error { return c.SendStatus(201) },
+		SchemasRequest: Event{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "Valid", body: `{"starts_at":"2024-06-15T10:00:00Z","ends_at":1718445600,"day":"2024-06-15","reminders":[1718445600000]}`, expectedStatus: 201},
+		{name: "Invalid RFC 3339", body: `{"starts_at":"June 15","ends_at":1718445600,"day":"2024-06-15"}`, expectedStatus: 400},
+		{name: "String for unix", body: `{"starts_at":"2024-06-15T10:00:00Z","ends_at":"1718445600","day":"2024-06-15"}`, expectedStatus: 400},
+		{name: "Wrong layout", body: `{"starts_at":"2024-06-15T10:00:00Z","ends_at":1718445600,"day":"15/06/2024"}`, expectedStatus: 400},
+		{name: "Fractional unix", body: `{"starts_at":"2024-06-15T10:00:00Z","ends_at":1718445600.5,"day":"2024-06-15"}`, expectedStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/events", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package notelink
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TryItHeader marks the requests of the docs' try-it client, which
+// Config.TryIt throttles and restricts
+const TryItHeader = "X-Try-It"
+
+// maxTryItUsers caps the throttled users tracked before idle ones are dropped
+const maxTryItUsers = 10000
+
+// TryItConfig adds safeguards to the docs' try-it client, e.g. for docs
+// served against production. The server-side checks only apply to requests
+// sending TryItHeader, so they protect against accidents rather than abuse.
+type TryItConfig struct {
+	// ConfirmMethods are confirmed in a dialog before the try-it client sends
+	// them; defaults to DELETE and PUT
+	ConfirmMethods      []string
+	DisableConfirmation bool // Send every method without asking
+	// RateLimit caps the try-it requests per user and minute, answering 429
+	// with Retry-After beyond it; 0 is unlimited. Users are told apart by
+	// their bearer token or API key, and by client IP when they send neither.
+	RateLimit int
+	// IdempotentOnly limits try-it to the idempotent methods GET, HEAD,
+	// OPTIONS, TRACE, PUT and DELETE: the docs disable the other forms and
+	// the routes answer their try-it requests with 403
+	IdempotentOnly bool
+}
+
+// confirmMethods returns the methods confirmed before they are sent
+func (t *TryItConfig) confirmMethods() []string {
+	if t.DisableConfirmation {
+		return []string{}
+	}
+	if t.ConfirmMethods == nil {
+		return []string{fiber.MethodDelete, fiber.MethodPut}
+	}
+	methods := make([]string, len(t.ConfirmMethods))
+	for i, method := range t.ConfirmMethods {
+		methods[i] = strings.ToUpper(method)
+	}
+	return methods
+}
+
+// idempotentMethod reports whether a method is idempotent (RFC 9110, 9.2.2)
+func idempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// tryItDisabled reports whether the docs disable the try-it form of a method
+func (an *ApiNote) tryItDisabled(method string) bool {
+	return an.config.TryIt != nil && an.config.TryIt.IdempotentOnly && !idempotentMethod(method)
+}
+
+// tryItThrottle holds the per-user buckets of TryItConfig.RateLimit
+type tryItThrottle struct {
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+}
+
+// take takes a token from a user's bucket
+func (t *tryItThrottle) take(user string, limit int, now time.Time) (remaining int, wait time.Duration, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.buckets == nil {
+		t.buckets = make(map[string]*rateBucket)
+	}
+	bucket, exists := t.buckets[user]
+	if !exists {
+		if len(t.buckets) >= maxTryItUsers {
+			// Buckets idle for a minute are full again and can be recreated
+			for key, idle := range t.buckets {
+				if now.Sub(idle.updated) >= time.Minute {
+					delete(t.buckets, key)
+				}
+			}
+		}
+		bucket = &rateBucket{tokens: float64(limit), updated: now}
+		t.buckets[user] = bucket
+	}
+	return bucket.take(limit, now)
+}
+
+// tryItUser identifies the user of a try-it request by a hash of its
+// credentials, or by client IP
+func (an *ApiNote) tryItUser(c fiber.Ctx) string {
+	credentials := c.Get(fiber.HeaderAuthorization)
+	if credentials == "" {
+		credentials = c.Get(an.apiKeyHeader())
+	}
+	if credentials == "" {
+		return "ip:" + c.IP()
+	}
+	sum := sha256.Sum256([]byte(credentials))
+	return "user:" + hex.EncodeToString(sum[:])
+}
+
+// tryItMiddleware enforces Config.TryIt on the try-it requests of a route,
+// returning its rejections to the app's ErrorHandler
+func (an *ApiNote) tryItMiddleware() fiber.Handler {
+	cfg := an.config.TryIt
+	return func(c fiber.Ctx) error {
+		if c.Get(TryItHeader) == "" {
+			return c.Next()
+		}
+		if cfg.IdempotentOnly && !idempotentMethod(c.Method()) {
+			return fiber.NewError(fiber.StatusForbidden, "Try-it is limited to idempotent methods")
+		}
+		if cfg.RateLimit > 0 {
+			remaining, wait, ok := an.tryIt.take(an.tryItUser(c), cfg.RateLimit, time.Now())
+			c.Set("X-RateLimit-Limit", strconv.Itoa(cfg.RateLimit))
+			c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			if !ok {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(1, int(math.Ceil(wait.Seconds())))))
+				return fiber.NewError(fiber.StatusTooManyRequests, "Try-it rate limit exceeded")
+			}
+		}
+		return c.Next()
+	}
+}
+
+// renderTryItButton renders the submit button of a try-it form, disabled
+// for methods TryItConfig.IdempotentOnly rules out
+func (an *ApiNote) renderTryItButton(method string) string {
+	if an.tryItDisabled(method) {
+		return `
+                            <button type="submit" disabled>Test Request</button>
+                            <p class="try-it-disabled"><i class="fas fa-ban"></i> Try-it is limited to idempotent methods on these docs</p>`
+	}
+	return `
+                            <button type="submit">Test Request</button>`
+}
+
+// tryItScript declares the try-it safeguards of Config.TryIt: the header
+// marking its requests, the methods to confirm and the idempotent-only restriction
+func (an *ApiNote) tryItScript() string {
+	header, confirmMethods, idempotentOnly := "", []byte("[]"), false
+	if cfg := an.config.TryIt; cfg != nil {
+		header = TryItHeader
+		if methods, err := json.Marshal(cfg.confirmMethods()); err == nil {
+			confirmMethods = methods
+		}
+		idempotentOnly = cfg.IdempotentOnly
+	}
+	return `
+            const tryItHeader = '` + header + `';
+            const tryItConfirmMethods = ` + string(confirmMethods) + `;
+            const tryItIdempotentOnly = ` + strconv.FormatBool(idempotentOnly) + `;
+            // confirmTryIt asks before sending methods that may change or delete data
+            function confirmTryIt(method, path) {
+                if (tryItIdempotentOnly && !['GET', 'HEAD', 'OPTIONS', 'TRACE', 'PUT', 'DELETE'].includes(method)) {
+                    return false;
+                }
+                return !tryItConfirmMethods.includes(method) ||
+                    confirm('Send ' + method + ' ' + path + '? This request may change or delete data.');
+            }`
+}
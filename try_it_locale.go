@@ -0,0 +1,146 @@
+package notelink
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TimezoneHeader carries the IANA timezone a client simulates, e.g.
+// "Asia/Bangkok", set by the timezone control of the try-it forms with
+// Config.TryItLocale. Handlers read it with RequestTimezone.
+const TimezoneHeader = "X-Timezone"
+
+// TryItLocaleConfig adds language and timezone controls to the try-it forms,
+// sending Accept-Language and TimezoneHeader with their requests to test
+// localized and time-sensitive routes. The values are remembered per endpoint
+// in the browser.
+type TryItLocaleConfig struct {
+	// Languages are suggested for Accept-Language, e.g. "th-TH"; defaults to
+	// the locales of the docs (see Config.DefaultLocale)
+	Languages []string
+	// Timezones are suggested for TimezoneHeader, e.g. "Asia/Bangkok";
+	// defaults to every timezone the browser knows
+	Timezones []string
+}
+
+// RequestTimezone returns the timezone of a request's TimezoneHeader, or UTC
+// and false when it is missing or not a known IANA timezone
+func RequestTimezone(c fiber.Ctx) (*time.Location, bool) {
+	name := c.Get(TimezoneHeader)
+	// LoadLocation reads "" and "UTC" as UTC and "Local" as the server's zone
+	if name == "" || name == "Local" {
+		return time.UTC, false
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC, false
+	}
+	return location, true
+}
+
+// renderTryItLocale renders the language and timezone controls of a try-it form
+func (an *ApiNote) renderTryItLocale() string {
+	if an.config.TryItLocale == nil {
+		return ""
+	}
+	return `
+                            <div class="try-it-locale">
+                                <label>Accept-Language: <input type="text" class="try-it-language" list="try-it-languages" placeholder="e.g. th-TH, en;q=0.8" onchange="storeTryItLocale(this.form)"></label>
+                                <label>Timezone (` + TimezoneHeader + `): <input type="text" class="try-it-timezone" list="try-it-timezones" placeholder="e.g. Asia/Bangkok" onchange="storeTryItLocale(this.form)"></label>
+                            </div>`
+}
+
+// tryItLocaleScript adds the simulated language and timezone of a try-it
+// form to its requests, remembering them per endpoint in localStorage
+func (an *ApiNote) tryItLocaleScript() string {
+	cfg := an.config.TryItLocale
+	if cfg == nil {
+		return `
+            function applyTryItLocale(form, headers, requestContext) {}`
+	}
+	languages := cfg.Languages
+	if len(languages) == 0 {
+		languages = an.locales()
+	}
+	languagesJSON, err := json.Marshal(languages)
+	if err != nil {
+		languagesJSON = []byte("[]")
+	}
+	timezonesJSON := []byte("null")
+	if len(cfg.Timezones) > 0 {
+		if data, err := json.Marshal(cfg.Timezones); err == nil {
+			timezonesJSON = data
+		}
+	}
+	return `
+            const tryItLocaleKey = 'notelink.tryItLocale';
+            const tryItLanguages = ` + string(languagesJSON) + `;
+            const tryItTimezones = ` + string(timezonesJSON) + ` ||
+                (Intl.supportedValuesOf ? Intl.supportedValuesOf('timeZone') : []);
+
+            function loadTryItLocales() {
+                try {
+                    const stored = JSON.parse(localStorage.getItem(tryItLocaleKey) || '{}');
+                    return stored && typeof stored === 'object' ? stored : {};
+                } catch (e) {
+                    return {};
+                }
+            }
+
+            // storeTryItLocale remembers the language and timezone of an endpoint's form
+            function storeTryItLocale(form) {
+                const stored = loadTryItLocales();
+                const language = form.querySelector('.try-it-language').value.trim();
+                const timezone = form.querySelector('.try-it-timezone').value.trim();
+                if (language || timezone) {
+                    stored[form.id] = { language: language, timezone: timezone };
+                } else {
+                    delete stored[form.id];
+                }
+                try {
+                    localStorage.setItem(tryItLocaleKey, JSON.stringify(stored));
+                } catch (e) {
+                    // Storage may be full or disabled; the values just won't persist
+                }
+            }
+
+            // applyTryItLocale sends the simulated language and timezone; header
+            // parameters of the route still override them
+            function applyTryItLocale(form, headers, requestContext) {
+                const language = form.querySelector('.try-it-language');
+                const timezone = form.querySelector('.try-it-timezone');
+                if (language && language.value.trim()) {
+                    headers['Accept-Language'] = language.value.trim();
+                    requestContext.header['accept-language'] = language.value.trim();
+                }
+                if (timezone && timezone.value.trim()) {
+                    headers['` + TimezoneHeader + `'] = timezone.value.trim();
+                    requestContext.header['` + strings.ToLower(TimezoneHeader) + `'] = timezone.value.trim();
+                }
+            }
+
+            document.addEventListener('DOMContentLoaded', function() {
+                [['try-it-languages', tryItLanguages], ['try-it-timezones', tryItTimezones]].forEach(([id, values]) => {
+                    const list = document.createElement('datalist');
+                    list.id = id;
+                    values.forEach(value => {
+                        const option = document.createElement('option');
+                        option.value = value;
+                        list.appendChild(option);
+                    });
+                    document.body.appendChild(list);
+                });
+                const stored = loadTryItLocales();
+                Object.keys(stored).forEach(id => {
+                    const form = document.getElementById(id);
+                    if (!form || !form.querySelector('.try-it-locale')) {
+                        return;
+                    }
+                    form.querySelector('.try-it-language').value = stored[id].language || '';
+                    form.querySelector('.try-it-timezone').value = stored[id].timezone || '';
+                });
+            });`
+}
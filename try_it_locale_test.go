@@ -0,0 +1,77 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestTryItLocale tests the language and timezone controls of the try-it forms
+func TestTryItLocale(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return nil }
+
+	api := NewApiNote(&Config{Title: "Test API", DefaultLocale: "en", CORS: &CORSConfig{AllowOrigins: []string{"https://app.example.com"}}}, "secret")
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/orders", Handler: handler, LocalizedDescriptions: map[string]string{"th": "รายการคำสั่งซื้อ"}}); err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	if html := api.generateHTML(); strings.Contains(html, `class="try-it-locale"`) || !strings.Contains(html, "function applyTryItLocale(form, headers, requestContext) {}") {
+		t.Error("Expected no locale controls without Config.TryItLocale")
+	}
+
+	api.config.TryItLocale = &TryItLocaleConfig{}
+	html := api.generateHTML()
+	if !strings.Contains(html, `class="try-it-language" list="try-it-languages"`) || !strings.Contains(html, `class="try-it-timezone" list="try-it-timezones"`) {
+		t.Error("Expected the language and timezone controls in the try-it form")
+	}
+	if !strings.Contains(html, `const tryItLanguages = ["en","th"];`) || !strings.Contains(html, "const tryItTimezones = null ||") {
+		t.Error("Expected the docs locales as suggestions and the browser's timezones by default")
+	}
+
+	api.config.TryItLocale = &TryItLocaleConfig{Languages: []string{"th-TH"}, Timezones: []string{"Asia/Bangkok"}}
+	if html := api.generateHTML(); !strings.Contains(html, `const tryItLanguages = ["th-TH"];`) || !strings.Contains(html, `const tryItTimezones = ["Asia/Bangkok"] ||`) {
+		t.Error("Expected the configured suggestions")
+	}
+	if headers := strings.Join(api.corsAllowHeaders("/orders"), ", "); !strings.Contains(headers, "Accept-Language") || !strings.Contains(headers, TimezoneHeader) {
+		t.Errorf("Expected CORS to allow the simulated headers, got %q", headers)
+	}
+}
+
+// TestRequestTimezone tests reading the simulated timezone of a request
+func TestRequestTimezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+		ok       bool
+	}{
+		{name: "missing", expected: "UTC"},
+		{name: "known", header: "Asia/Bangkok", expected: "Asia/Bangkok", ok: true},
+		{name: "unknown", header: "Mars/Olympus", expected: "UTC"},
+		{name: "server local", header: "Local", expected: "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			var location string
+			var ok bool
+			app.Get("/", func(c fiber.Ctx) error {
+				loc, found := RequestTimezone(c)
+				location, ok = loc.String(), found
+				return nil
+			})
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(TimezoneHeader, tt.header)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			if location != tt.expected || ok != tt.ok {
+				t.Errorf("Expected %s (%v), got %s (%v)", tt.expected, tt.ok, location, ok)
+			}
+		})
+	}
+}
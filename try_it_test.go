@@ -0,0 +1,83 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestTryItSafeguards tests throttling and restricting try-it requests
+func TestTryItSafeguards(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", DocsUI: "notelink", TryIt: &TryItConfig{RateLimit: 2, IdempotentOnly: true}, ErrorHandler: func(c fiber.Ctx, err error) error {
+		c.Set("X-Error-Handler", "called")
+		return DefaultErrorHandler(c, err)
+	}}, "secret")
+	handler := func(c fiber.Ctx) error { return c.SendString("ok") }
+	for _, method := range []string{"GET", "POST", "DELETE"} {
+		if err := api.DocumentedRoute(&DocumentedRouteInput{Method: method, Path: "/notes", Description: method + " notes", Handler: handler}); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		tryIt    bool
+		token    string
+		expected int
+	}{
+		{name: "first", method: "GET", tryIt: true, expected: fiber.StatusOK},
+		{name: "second", method: "DELETE", tryIt: true, expected: fiber.StatusOK},
+		{name: "throttled", method: "GET", tryIt: true, expected: fiber.StatusTooManyRequests},
+		{name: "another user", method: "GET", tryIt: true, token: "Bearer other", expected: fiber.StatusOK},
+		{name: "not try-it", method: "GET", expected: fiber.StatusOK},
+		{name: "not idempotent", method: "POST", tryIt: true, token: "Bearer third", expected: fiber.StatusForbidden},
+		{name: "not idempotent outside try-it", method: "POST", expected: fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/notes", nil)
+			if tt.tryIt {
+				req.Header.Set(TryItHeader, "1")
+			}
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, resp.StatusCode)
+			}
+			if tt.expected == fiber.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+				t.Error("Expected Retry-After on a throttled request")
+			}
+			if rejected := tt.expected != fiber.StatusOK; rejected != (resp.Header.Get("X-Error-Handler") != "") {
+				t.Errorf("Expected rejections, and only them, rendered by the ErrorHandler, got %d", resp.StatusCode)
+			}
+		})
+	}
+
+	html := api.generateHTML()
+	for _, snippet := range []string{
+		`const tryItConfirmMethods = ["DELETE","PUT"];`,
+		"const tryItIdempotentOnly = true;",
+		`<button type="submit" disabled>Test Request</button>`,
+	} {
+		if !strings.Contains(html, snippet) {
+			t.Errorf("Expected the docs to contain %q", snippet)
+		}
+	}
+	if strings.Count(html, `<button type="submit" disabled>`) != 1 {
+		t.Error("Expected only the POST form to be disabled")
+	}
+
+	plain := NewApiNote(&Config{Title: "Test API"}, "secret")
+	if html := plain.generateHTML(); !strings.Contains(html, "const tryItHeader = '';") || !strings.Contains(html, "const tryItConfirmMethods = [];") {
+		t.Error("Expected no try-it safeguards without Config.TryIt")
+	}
+}
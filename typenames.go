@@ -0,0 +1,127 @@
+package notelink
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	// packageQualifier matches the package path in front of a type name, e.g.
+	// "github.com/acme/api/dto." in "Response[github.com/acme/api/dto.UserDTO]"
+	packageQualifier = regexp.MustCompile(`(?:[\w.-]+/)*([\w-]+)\.`)
+	nonIdentifier    = regexp.MustCompile(`[^A-Za-z0-9]+`)
+)
+
+// SchemaNaming returns the component schema and TypeScript interface name of a
+// named struct type. Names must be valid identifiers.
+type SchemaNaming func(t reflect.Type) string
+
+// SchemaNameShort names types by their bare Go name, the default. Generic
+// instantiations get a stable name built from their type arguments without
+// package paths, e.g. Response[dto.UserDTO] becomes ResponseUserDTO and
+// Page[[]dto.User] becomes PageListUser.
+func SchemaNameShort(t reflect.Type) string {
+	name := t.Name()
+	if !strings.Contains(name, "[") {
+		return name
+	}
+	return identifierName(packageQualifier.ReplaceAllString(name, ""))
+}
+
+// SchemaNamePackageQualified prefixes type names with their package name, so
+// billing.User and auth.User become BillingUser and AuthUser, and
+// Page[dto.User] in package api becomes ApiPageDtoUser
+func SchemaNamePackageQualified(t reflect.Type) string {
+	name := packageQualifier.ReplaceAllString(t.Name(), "$1.")
+	if pkg := t.PkgPath(); pkg != "" {
+		name = path.Base(pkg) + "." + name
+	}
+	return identifierName(name)
+}
+
+// identifierName turns a qualified type name into an identifier by dropping
+// punctuation and capitalizing each part
+func identifierName(name string) string {
+	name = strings.ReplaceAll(name, "[]", "List")
+	name = strings.ReplaceAll(name, "*", "")
+
+	var result strings.Builder
+	for _, part := range nonIdentifier.Split(name, -1) {
+		if part != "" {
+			result.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return result.String()
+}
+
+// schemaNames names component schemas with a naming strategy and, when types is
+// set, records which type each name was given to so collisions are detected.
+// A nil *schemaNames names types with SchemaNameShort.
+type schemaNames struct {
+	naming SchemaNaming
+	types  map[string]reflect.Type
+	err    error
+	enums  string // Config.TypeScriptEnums, how TypeScript declares SchemaEnum types
+}
+
+// name returns the component name of a named type
+func (n *schemaNames) name(t reflect.Type) string {
+	if n == nil {
+		return SchemaNameShort(t)
+	}
+	naming := n.naming
+	if naming == nil {
+		naming = SchemaNameShort
+	}
+	name := naming(t)
+	n.record(name, t)
+	return name
+}
+
+// record remembers the type a name was given to, keeping the first collision
+func (n *schemaNames) record(name string, t reflect.Type) {
+	if n.types == nil || t.Name() == "" {
+		return
+	}
+	other, exists := n.types[name]
+	if !exists {
+		n.types[name] = t
+		return
+	}
+	if other != t && n.err == nil {
+		n.err = fmt.Errorf("component schema name %q is used by both %s and %s; set Config.SchemaNaming to tell them apart",
+			name, qualifiedTypeName(other), qualifiedTypeName(t))
+	}
+}
+
+// qualifiedTypeName returns the type name with its full package path for error messages
+func qualifiedTypeName(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// schemaNames returns the component namer for documentation output
+func (an *ApiNote) schemaNames() *schemaNames {
+	return &schemaNames{naming: an.config.SchemaNaming, enums: an.config.TypeScriptEnums}
+}
+
+// checkSchemaNames reports a registered type whose component name is already
+// taken by a different type
+func (an *ApiNote) checkSchemaNames(schemas ...interface{}) error {
+	for _, schema := range schemas {
+		if schema == nil {
+			continue
+		}
+		names := &schemaNames{naming: an.config.SchemaNaming, types: an.componentTypes}
+		generateJSONSchema("", schema, an.config.TimeFormat, names)
+		if names.err != nil {
+			return names.err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package notelink
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type UserDTO struct {
+	Name string `json:"name"`
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+type Envelope[T any] struct {
+	Data T `json:"data"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// TestSchemaTypeName tests stable names for generic instantiations
+func TestSchemaTypeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      reflect.Type
+		expected string
+	}{
+		{name: "Plain type", typ: reflect.TypeOf(UserDTO{}), expected: "UserDTO"},
+		{name: "Single argument", typ: reflect.TypeOf(Envelope[UserDTO]{}), expected: "EnvelopeUserDTO"},
+		{name: "Builtin argument", typ: reflect.TypeOf(Page[int]{}), expected: "PageInt"},
+		{name: "Slice argument", typ: reflect.TypeOf(Envelope[[]UserDTO]{}), expected: "EnvelopeListUserDTO"},
+		{name: "Pointer argument", typ: reflect.TypeOf(Envelope[*UserDTO]{}), expected: "EnvelopeUserDTO"},
+		{name: "Nested instantiation", typ: reflect.TypeOf(Envelope[Page[UserDTO]]{}), expected: "EnvelopePageUserDTO"},
+		{name: "Multiple arguments", typ: reflect.TypeOf(Pair[string, UserDTO]{}), expected: "PairStringUserDTO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SchemaNameShort(tt.typ); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestGenericSchemas tests components and TypeScript generated for generic types
+func TestGenericSchemas(t *testing.T) {
+	schema, components := generateJSONSchema("ResponseBody", Envelope[Page[UserDTO]]{}, "", nil)
+	if ref := schema.Properties["data"].Ref; ref != "#/components/schemas/PageUserDTO" {
+		t.Errorf("Expected data to reference PageUserDTO, got %q", ref)
+	}
+	page, ok := components["PageUserDTO"]
+	if !ok {
+		t.Fatalf("Expected a PageUserDTO component, got %v", components)
+	}
+	if items := page.Properties["items"].Items; items == nil || items.Ref != "#/components/schemas/UserDTO" {
+		t.Errorf("Expected items to reference UserDTO, got %+v", items)
+	}
+	for name := range components {
+		if strings.ContainsAny(name, "[]./") {
+			t.Errorf("Expected a clean component name, got %s", name)
+		}
+	}
+
+	ts := generateTypeScriptSchema("ResponseBody", Envelope[Page[UserDTO]]{}, "", nil)
+	for _, snippet := range []string{"export interface PageUserDTO {", "items: UserDTO[];", "data: PageUserDTO;"} {
+		if !strings.Contains(ts, snippet) {
+			t.Errorf("Expected TypeScript to contain %q, got:\n%s", snippet, ts)
+		}
+	}
+}
+
+// TestSchemaNaming tests naming strategies and component name collisions
+func TestSchemaNaming(t *testing.T) {
+	qualified := []struct {
+		typ      reflect.Type
+		expected string
+	}{
+		{typ: reflect.TypeOf(url.Userinfo{}), expected: "UrlUserinfo"},
+		{typ: reflect.TypeOf(UserDTO{}), expected: "NotelinkUserDTO"},
+		{typ: reflect.TypeOf(Envelope[Page[url.Userinfo]]{}), expected: "NotelinkEnvelopeNotelinkPageUrlUserinfo"},
+	}
+	for _, tt := range qualified {
+		if got := SchemaNamePackageQualified(tt.typ); got != tt.expected {
+			t.Errorf("Expected %s, got %s", tt.expected, got)
+		}
+	}
+
+	profile := Envelope[UserDTO]{}
+
+	// A second UserDTO type, as if declared in another package
+	type UserDTO struct {
+		ID int `json:"id"`
+	}
+	type Team struct {
+		Lead    UserDTO   `json:"lead"`
+		Members []UserDTO `json:"members"`
+	}
+	localUser := reflect.TypeOf(UserDTO{})
+
+	tests := []struct {
+		name      string
+		naming    SchemaNaming
+		expectErr bool
+		component string
+	}{
+		{name: "Default naming collides", expectErr: true},
+		{
+			name: "Custom naming",
+			naming: func(t reflect.Type) string {
+				if t == localUser {
+					return "TeamMember"
+				}
+				return SchemaNameShort(t)
+			},
+			component: "TeamMember",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", SchemaNaming: tt.naming}, "secret")
+			handler := func(c fiber.Ctx) error { return c.SendStatus(200) }
+			ok := map[string]string{"200": "OK"}
+			if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/profile", Handler: handler, Responses: ok, SchemasResponse: profile}); err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/team", Handler: handler, Responses: ok, SchemasResponse: Team{}})
+			if tt.expectErr {
+				if err == nil || !strings.Contains(err.Error(), `"UserDTO"`) {
+					t.Errorf("Expected a UserDTO collision error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+
+			spec := api.GenerateOpenAPISpec()
+			if _, ok := spec.Components.Schemas[tt.component]; !ok {
+				t.Errorf("Expected %s component, got %v", tt.component, spec.Components.Schemas)
+			}
+			if _, ok := spec.Components.Schemas["UserDTO"]; !ok {
+				t.Error("Expected the original UserDTO component to be kept")
+			}
+			if ts := generateTypeScriptSchema("Team", Team{}, "", api.schemaNames()); !strings.Contains(ts, "lead: TeamMember;") {
+				t.Errorf("Expected TypeScript to use the custom name, got:\n%s", ts)
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package notelink
 
-import "github.com/gofiber/fiber/v3"
+import (
+	"io/fs"
+	"time"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+)
 
 // Config holds the API documentation configuration
 type Config struct {
@@ -10,30 +16,387 @@ type Config struct {
 	Host                 string
 	BasePath             string
 	AuthToken            string // Optional authorization token (e.g., Bearer token)
-	DocsUI               string // UI to use for /api-docs endpoint: "scalar" (default) or "swagger"
+	DocsUI               string // UI to use for /api-docs endpoint: "scalar" (default), "swagger", "redoc" or any other value for the built-in HTML; ignored when UIs or DefaultUI is set
 	EnableValidation     bool   // Enable server-side validation (default: true)
 	StrictTypeValidation bool   // Strict type checking vs coercion (default: false)
+	ServerTiming         bool   // Emit Server-Timing headers with validation/handler/serialization phases; serialization is only measured for responses written with TimedJSON, plain c.JSON counts as handler time
+	Mock                 bool   // Serve routes from their MockRules and generated examples instead of their handlers
+	MockPersistence      bool   // In mock mode, keep the sample data of requests sending MockSessionHeader, e.g. from the docs page's toggle: POST stores the body, GET returns it, DELETE removes it
+	TrustProxyHeaders    bool   // Use X-Forwarded-Proto/Host for the try-it base URL and spec servers (enable only behind a proxy that sets them)
+	TryItRelativeURL     bool   // Send try-it requests to the docs page's origin (window.location.origin) instead of Host
+	// TryIt confirms destructive try-it requests, throttles them per user and
+	// can limit them to idempotent methods, e.g. for production docs
+	TryIt *TryItConfig
+	// TryItLocale adds Accept-Language and timezone controls to the try-it
+	// forms, remembered per endpoint, to test localized and time-sensitive routes
+	TryItLocale *TryItLocaleConfig
+
+	// ErrorHandler converts errors returned by handlers (including *ValidationErrorResponse)
+	// into responses. Defaults to DefaultErrorHandler, which renders the ErrorResponse envelope.
+	ErrorHandler fiber.ErrorHandler
+	// ErrorSchema documents the body of 4xx/5xx responses in the spec. Defaults to
+	// ErrorResponse; set it alongside a custom ErrorHandler that renders another shape.
+	ErrorSchema interface{}
+	// JSONDecoder decodes JSON request bodies, for validation and c.Bind(),
+	// e.g. sonic.Unmarshal. Defaults to goccy/go-json; decoding into
+	// interface{}, it must decode numbers as float64. Overrides
+	// FiberConfig.JSONDecoder.
+	JSONDecoder func(data []byte, v interface{}) error
+	// StreamValidationThreshold is the size from which JSON request bodies are
+	// validated while tokenizing them instead of decoding them into a map,
+	// DefaultStreamValidationThreshold (1 MiB) by default; negative disables it
+	StreamValidationThreshold int
+
+	// RequestLogging tags each request to a documented route with an id (its
+	// X-Request-ID header, sent by the docs' try-it client, or a generated
+	// one) and logs it once it completes with its status and latency
+	RequestLogging bool
+
+	// Thresholds sets the latency and body-size limits of documented routes;
+	// requests exceeding them are logged with the route's pattern and reported
+	// to the OnThresholdExceeded hooks. DocumentedRouteInput.Thresholds
+	// overrides it per route.
+	Thresholds *Thresholds
+	// PayloadSizes records the request and response body sizes of documented
+	// routes, served as p95 sizes at /api-docs/payload-sizes and warning when
+	// the documented examples diverge grossly from real payloads
+	PayloadSizes *PayloadSizeConfig
+
+	// CORS lets browser clients on other origins call the documented routes:
+	// responses carry the CORS headers and preflights are documented. OPTIONS
+	// requests are answered with the Allow header with or without it.
+	CORS *CORSConfig
+
+	// DocsPath is where the docs pages, specs and exports are served and linked,
+	// "/api-docs" by default
+	DocsPath string
+	// DisableDocsRoutes leaves the docs surface unmounted, for mounting
+	// DocsHandlers into an existing router at DocsPath instead
+	DisableDocsRoutes bool
+	// DocsSnapshot serves the docs pages, specs and exports from a snapshot
+	// written by WriteSnapshot, e.g. an embed.FS, instead of rendering them
+	// from the registered routes. Pages missing from it and the pages reporting
+	// runtime state are rendered as usual.
+	DocsSnapshot fs.FS
+	// DocsAuth protects every docs page, spec and export, e.g. with
+	// basicauth.New(...) or a token check. Protected pages are cached privately
+	// and vary by Authorization and Cookie.
+	DocsAuth fiber.Handler
+	// PublicDiagnostics serves the docs pages identifying the API's clients,
+	// /deprecations and /validation-failures, and the registry sizes at
+	// /debug without DocsAuth. They aren't served otherwise, as they would
+	// expose the callers and internals to anyone reading the docs.
+	PublicDiagnostics bool
+	// DocsCacheTTL lets clients cache the docs pages and specs for the duration.
+	// Zero makes them revalidate on every request; the metrics page is never cached.
+	DocsCacheTTL time.Duration
+	// SecurityHeaders tunes the HSTS, X-Content-Type-Options, X-Frame-Options,
+	// Referrer-Policy and Content-Security-Policy headers set on every
+	// response (see SecurityHeadersConfig); DisableSecurityHeaders opts out
+	SecurityHeaders        *SecurityHeadersConfig
+	DisableSecurityHeaders bool
+	// WarmUp renders the docs, specs and validators once the app listens (see
+	// ApiNote.WarmUp) and serves readiness at ReadinessPath: 503 until the
+	// warm-up completes, then 200. Point the readiness probe at it.
+	WarmUp bool
+	// ReadinessPath is where readiness is served with WarmUp, "/readyz" by default
+	ReadinessPath string
+	// PublishArtifacts receives the specs, TypeScript types and static docs
+	// bundle once the app listens (see ApiNote.PublishArtifacts), e.g. an S3
+	// bucket other systems read them from. Set Host so the specs name the
+	// public server.
+	PublishArtifacts ArtifactStore
+	// SpecRegistries receive the OpenAPI spec as Version once the app listens
+	// (see ApiNote.PublishSpec), e.g. an ApicurioRegistry or SwaggerHubRegistry
+	// keeping a central API catalog current
+	SpecRegistries []SpecRegistry
+	// SpecChangeNotifier posts the changes since the last published spec to a
+	// Slack or Teams webhook once the app listens, before publishing the new
+	// one (see ApiNote.NotifySpecChanges)
+	SpecChangeNotifier *SpecChangeNotifier
+	// SchemaCompatibility checks the component schemas against the published
+	// ones once the app listens; incompatible changes are logged and nothing
+	// is published (see ApiNote.CheckSchemaCompatibility)
+	SchemaCompatibility *SchemaCompatibility
+	// APIKeyHeader carries the API keys of Config.APIKeys and identifies the
+	// callers of deprecated routes, besides the JWT subject and User-Agent;
+	// "X-API-Key" by default. Only a fingerprint of the key is recorded.
+	APIKeyHeader string
+	// Tokens mounts /auth/token and /auth/refresh, issuing the JWTs of
+	// UseJWT for credentials checked by Tokens.Verify (see TokenConfig)
+	Tokens *TokenConfig
+	// APIKeys enables per-client API keys with scopes and rate limits, and the
+	// admin endpoints managing them (see APIKeyConfig)
+	APIKeys *APIKeyConfig
+	// Sessions enables cookie sessions, signed in with credentials checked by
+	// Sessions.Verify, and a sign-in form on the docs page (see SessionConfig)
+	Sessions *SessionConfig
+	// DeviceAuth mounts a device authorization flow issuing the JWTs of UseJWT,
+	// and a device sign-in on the docs page (see DeviceAuthConfig)
+	DeviceAuth *DeviceAuthConfig
+	// IPFilter restricts the clients of every documented route by IP address,
+	// unless the route sets its own DocumentedRouteInput.IPFilter
+	IPFilter *IPFilter
+	// ValidationFailures logs a sample of the requests failing validation by
+	// route, field and client, and counts them all (see ValidationFailureLog)
+	ValidationFailures *ValidationFailureLog
+	// CallbackRelay lets the try-it forms of routes with a CallbackURLField
+	// receive their callbacks live over a WebSocket (see CallbackRelayConfig)
+	CallbackRelay *CallbackRelayConfig
+
+	// DefaultLocale is the language of route descriptions and Responses, e.g.
+	// "en" (the default). Routes add translations with LocalizedDescriptions
+	// and LocalizedResponses; the docs and specs are served in the viewer's
+	// language from Accept-Language or the lang query parameter.
+	DefaultLocale string
+
+	// Servers lists the scheme and host of the API's servers, before BasePath, for
+	// the spec and the try-it client, e.g. https://{tenant}.api.example.com with
+	// a tenant variable. The docs choose the server and edit its variables.
+	// Defaults to Host.
+	Servers []OpenAPIServer
+
+	// TenantHeader names the header identifying the tenant, e.g. "X-Tenant-ID".
+	// It is a required parameter of every operation, filled in try-it requests
+	// from the tenant selector of the docs.
+	TenantHeader string
+	// TenantPathPrefix identifies the tenant by a /{tenant} path prefix after
+	// BasePath instead; every route is served and documented under it
+	TenantPathPrefix bool
+	// Tenants lists the tenants offered by the docs tenant selector; without it
+	// the selector is a text input
+	Tenants []string
+
+	// Demo registers /demo/seed and /demo/reset to create and remove sample
+	// data for the try-it forms of shared dev environments. Leave it nil in production.
+	Demo *DemoConfig
+
+	// JobStore persists the background jobs of long-running routes (see
+	// LongRunningRoute). Defaults to an in-memory store.
+	JobStore JobStore
+
+	// MemoryLimits caps the jobs of the in-memory job store, the resources of
+	// the mock state and the rendered docs artifacts, evicting the least
+	// recently used ones. Defaults apply
+	// when nil; /api-docs/debug reports the registry sizes behind DocsAuth or
+	// with PublicDiagnostics.
+	MemoryLimits *MemoryLimits
+
+	// Compression compresses the responses of documented routes with br or gzip
+	// according to Accept-Encoding, documenting it on each operation.
+	// DocumentedRouteInput.Compress overrides it per route.
+	Compression *CompressionConfig
+
+	// ExtraTemplateData is passed to DocsTemplates as .Data. Without templates,
+	// the TemplateDataEnvironment, TemplateDataAnnouncement and
+	// TemplateDataLegalNotice keys render an environment banner, an
+	// announcement bar and a legal notice on the docs.
+	ExtraTemplateData map[string]interface{}
+	// DocsTemplates injects html/template content into the docs page and
+	// print view, e.g. banners or notices read from environment variables
+	DocsTemplates *DocsTemplates
+	// Accessible renders the docs page and print view for WCAG AA: labeled
+	// icons and controls, aria-expanded on the endpoint tree, a skip link,
+	// focus moved to try-it results, a high-contrast theme and no animations
+	Accessible bool
+	// EmbedMode renders the built-in docs page as a widget for iframes, e.g. in a
+	// developer portal: without the header and authorization chrome, posting
+	// its height to the parent window and framable by EmbedConfig.ParentOrigins
+	EmbedMode *EmbedConfig
+	// RequestDecompression accepts gzip-compressed request bodies on documented
+	// routes that take a body, decompressing them before validation, and
+	// documents the accepted encodings. DocumentedRouteInput.DecompressRequest
+	// overrides it per route.
+	RequestDecompression *RequestDecompressionConfig
+	// RequestEncryption holds the keys decrypting the JWE request bodies of
+	// routes with DocumentedRouteInput.EncryptedRequest
+	RequestEncryption *RequestEncryptionConfig
+
+	// FeatureFlags reports whether the feature flags of flagged routes (see
+	// DocumentedRouteInput.FeatureFlag) are enabled for each request
+	FeatureFlags FeatureFlagProvider
+	// FeatureFlagStatus is the status of requests to disabled routes: 404
+	// (default), as if the route didn't exist, or 501
+	FeatureFlagStatus int
+	// HideFlaggedRoutes leaves flagged routes out of the docs page and the spec
+	// instead of badging them
+	HideFlaggedRoutes bool
+	// HideDraftRoutes leaves routes with StatusDraft out of the spec and its
+	// exports; the docs page still lists them, badged as drafts
+	HideDraftRoutes bool
+
+	// TimeFormat is the default format of time.Time fields in examples, schemas and
+	// validation: TimeFormatRFC3339 (default), TimeFormatUnix, TimeFormatUnixMilli or
+	// a Go layout. Fields override it with a `timeformat:"unix"` struct tag.
+	TimeFormat string
+
+	// ExampleMaxDepth limits how deeply nested structs are expanded in generated
+	// examples and try-it templates (default DefaultExampleMaxDepth). Deeper and
+	// self-referential structs are truncated to {}, null or [].
+	ExampleMaxDepth int
+	// ExampleArrayLength is the number of elements generated for arrays in
+	// examples (default DefaultExampleArrayLength)
+	ExampleArrayLength int
+	// ExampleProvider supplies example values for fields instead of the built-in
+	// field-name heuristics, e.g. ExampleValues or a faker-backed ExampleProviderFunc
+	ExampleProvider ExampleProvider
+
+	// SchemaNaming names component schemas and TypeScript interfaces, e.g.
+	// SchemaNamePackageQualified or a custom func. Defaults to SchemaNameShort;
+	// registering two types that get the same name is an error.
+	SchemaNaming SchemaNaming
+	// AllowUnsupportedTypes documents schema fields of types without a JSON
+	// representation (channels, funcs, unsafe pointers and complex numbers)
+	// with a placeholder schema and a warning, listing them again once the
+	// app listens (see ApiNote.UnsupportedTypes). Registering such a schema
+	// is an error by default.
+	AllowUnsupportedTypes bool
+	// TypeScriptEnums declares SchemaEnum types in generated TypeScript as
+	// string-literal unions (TypeScriptEnumUnion, the default) or as enums
+	// (TypeScriptEnumEnum). Fields with an enum tag are always inline unions.
+	TypeScriptEnums string
+
+	// UIs mounts several documentation frontends at /api-docs/<route>, e.g.
+	// {NotelinkUI(), SwaggerUI(), RedocUI()}, with a landing page at /api-docs
+	// linking to them. Custom frontends implement DocsUI.
+	UIs []DocsUI
+	// DefaultUI serves a frontend at /api-docs: "notelink", "swagger",
+	// "scalar", "redoc" or the Route of a custom frontend in UIs. Every
+	// frontend of UIs, or the four built-in ones when UIs is empty, is also
+	// served at /api-docs/<route>, and the landing page moves to
+	// /api-docs/links. SetDefaultUI switches it at runtime. Overrides DocsUI.
+	DefaultUI string
+
+	// Assets overrides the URL and Subresource Integrity hash of external assets
+	// loaded by the docs, Swagger UI, Scalar and ReDoc pages, keyed by AssetFonts,
+	// AssetSwaggerUIBundle and the other Asset* names, e.g. to self-host them
+	Assets map[string]Asset
+	// AssetIntegrity emits integrity attributes for every asset, computing the
+	// sha384 hash of assets without a configured Integrity by fetching them once
+	AssetIntegrity bool
+
+	// ExampleFS resolves DocumentedRouteInput.ExampleFiles, typically an embed.FS.
+	// When nil, example files are read relative to the working directory.
+	ExampleFS fs.FS
+
+	// FiberConfig is passed to fiber.New, allowing BodyLimit, ReadTimeout, ErrorHandler,
+	// TrustProxy and other settings. JSONEncoder/JSONDecoder default to goccy/go-json when unset.
+	FiberConfig *fiber.Config
+	// ListenConfig is used by Listen, ListenMutualTLS and ListenUnix, e.g. to set
+	// EnablePrefork for cluster mode or a ShutdownTimeout.
+	ListenConfig *fiber.ListenConfig
+
+	// GroupBy controls the docs navigation hierarchy, e.g. GroupByTag,
+	// GroupByFirstSegment or a custom func. Defaults to grouping by version and path segments.
+	GroupBy GroupBy
+	// GroupWeights orders docs groups and spec tags by weight (lower first, default 0),
+	// then alphabetically, e.g. {"Getting started": -10, "Admin": 10}
+	GroupWeights map[string]int
+	// SortEndpoints orders endpoints within a group, overriding Weight ordering
+	SortEndpoints func(a, b Endpoint) bool
+
+	// Extensions adds specification extensions to the root of the spec, e.g.
+	// {"x-kong-plugin-cors": {...}}. Keys get an "x-" prefix when missing; schema
+	// properties take theirs from an `extensions` struct tag.
+	Extensions map[string]interface{}
+
+	// Catalog describes the API for a Backstage software catalog. When set, a
+	// catalog-info.yaml entity is served at /api-docs/catalog-info.yaml.
+	Catalog *CatalogInfo
 }
 
 // Parameter represents an API parameter
-type Parameter struct {
-	Name        string
-	In          string // "query", "path", "header"
-	Type        string // e.g., "string", "number", "boolean"
-	Description string
-	Required    bool
-}
+type Parameter = validation.Parameter
 
 // Endpoint represents a single API endpoint with schema and parameters
 type Endpoint struct {
 	Method         string
-	Path           string
+	Path           string // Full route path, including Config.BasePath
+	RoutePath      string // Path without Config.BasePath; spec paths, operation ids, tags and docs groups use it
 	Description    string
 	Responses      map[string]string
 	RequestSchema  interface{}
 	ResponseSchema interface{}
 	Parameters     []Parameter
 	AuthRequired   bool // Indicates if authorization is required
+	// WebhookSignature describes the HMAC signature required on inbound webhook requests
+	WebhookSignature *WebhookSignatureConfig
+	// RequestSigning describes the HMAC request-signing scheme required by the endpoint
+	RequestSigning *RequestSigningConfig
+	// ClientCertRequired indicates the endpoint requires a TLS client certificate
+	ClientCertRequired bool
+	// RequestExamples holds named request body examples loaded from fixture files
+	RequestExamples []RequestExample
+	// ResponseExamples holds named response body examples, e.g. traffic imported with ImportHAR
+	ResponseExamples []ResponseExample
+	// Presets lists the test cases offered above the try-it form
+	Presets []RequestPreset
+	// Produces lists the response media types (defaults to application/json)
+	Produces []string
+	// Consumes lists the request body media types (defaults to application/json)
+	Consumes []string
+	// Links lists related operations reachable from the endpoint's responses
+	Links []Link
+	// Tags overrides the tag derived from the path in the spec and GroupByTag
+	Tags []string
+	// Weight orders the endpoint within its group (lower first)
+	Weight int
+	// Ownership names the endpoint's owner, team and support channel
+	Ownership *Ownership
+	// Status is the endpoint's review status
+	Status EndpointStatus
+	// ChangeLog lists the endpoint's changes, newest first
+	ChangeLog []ChangeLogEntry
+	// Compressed indicates responses are compressed according to Accept-Encoding
+	Compressed bool
+	// RequestDecompression is set when gzip request bodies are accepted
+	RequestDecompression *RequestDecompressionConfig
+	// CircuitBreaker guards the handler when the route has a circuit breaker
+	CircuitBreaker *CircuitBreaker
+	// ConcurrencyLimit caps the requests the endpoint processes at once
+	ConcurrencyLimit *ConcurrencyLimit
+	// IPFilter restricts the client addresses the endpoint accepts
+	IPFilter *IPFilter
+	// EncryptedRequest indicates the endpoint takes JWE-encrypted request bodies
+	EncryptedRequest bool
+	// FeatureFlag names the flag gating the endpoint
+	FeatureFlag string
+	// Deprecated marks the endpoint deprecated; its callers are recorded
+	Deprecated bool
+	// Migration names the replacement of a deprecated endpoint
+	Migration *MigrationGuide
+	// APIKeyRequired indicates the endpoint requires an API key of Config.APIKeys
+	APIKeyRequired bool
+	// APIKeyScopes lists the scopes the endpoint's API key must grant
+	APIKeyScopes []string
+	// SessionRequired indicates the endpoint requires a session cookie of Config.Sessions
+	SessionRequired bool
+	// Variants are the alternative handlers of the endpoint
+	Variants []RouteVariant
+	// Thresholds are the latency and body-size limits reported for the endpoint
+	Thresholds *Thresholds
+	// SLO tracks the endpoint's compliance with its service level objective
+	SLO *SLOTracker
+	// PayloadSizes records the endpoint's body sizes when Config.PayloadSizes is set
+	PayloadSizes *PayloadSizeTracker
+	// ErrorCodes lists the registered error codes referenced in Responses
+	ErrorCodes []string
+	// LocalizedDescriptions holds the description translated per locale
+	LocalizedDescriptions map[string]string
+	// LocalizedResponses holds the response descriptions translated per locale
+	LocalizedResponses map[string]map[string]string
+	// SparseFields lists the response fields selectable with ?fields=
+	SparseFields []string
+	// Proxy is the upstream service of a proxied route
+	Proxy *ProxiedService
+	// ProxiedOperation is the upstream operation documented in place of a proxied route
+	ProxiedOperation *Operation
+	// Extensions holds the x-* properties of the endpoint's operation
+	Extensions map[string]interface{}
+	// ValidationRules are the custom and cross-field checks of the request
+	ValidationRules []ValidationRule
+	// CallbackURLField names the request body field taking the callback URL
+	CallbackURLField string
 }
 
 // DocumentedRouteInput represents the input for registering a documented route
@@ -47,4 +410,125 @@ type DocumentedRouteInput struct {
 	Path            string            `json:"path"`
 	Description     string            `json:"description"`
 	Params          []Parameter       `json:"params"`
+	// ParamsSchema derives parameters from a struct with query, header and uri
+	// tags (see ParamsFromStruct). Entries in Params take precedence.
+	ParamsSchema interface{} `json:"paramsSchema,omitempty"`
+	// MockRules customize the route's responses in mock mode (Config.Mock),
+	// e.g. to return errors for given parameters or keep created resources
+	MockRules []MockRule `json:"-"`
+	// WebhookSignature enables HMAC signature verification for this route and
+	// documents the signature header and signing scheme automatically.
+	WebhookSignature *WebhookSignatureConfig `json:"webhookSignature,omitempty"`
+	// RequestSigning requires HMAC-signed requests (partner APIs) and documents
+	// the scheme as a custom security scheme.
+	RequestSigning *RequestSigningConfig `json:"requestSigning,omitempty"`
+	// ClientCertRequired rejects requests without a verified TLS client certificate
+	// (see ListenMutualTLS) and documents the mutualTLS security scheme.
+	ClientCertRequired bool `json:"clientCertRequired,omitempty"`
+	// ExampleFiles lists JSON fixture files (resolved via Config.ExampleFS) used as
+	// request body examples in the spec, the docs and the try-it editor.
+	ExampleFiles []string `json:"exampleFiles,omitempty"`
+	// Presets are named test cases, e.g. "admin user" or "expired card",
+	// rendered as chips above the try-it form that fill in its parameters and
+	// request body in one click
+	Presets []RequestPreset `json:"presets,omitempty"`
+	// Produces documents the response media types, e.g. NegotiatedContentTypes
+	// for handlers that respond with Respond. Defaults to application/json.
+	Produces []string `json:"produces,omitempty"`
+	// Consumes documents the accepted request body media types, e.g.
+	// []string{"application/json", "application/msgpack"}. Defaults to application/json.
+	Consumes []string `json:"consumes,omitempty"`
+	// Links declares related operations (rel, href template, method), emitted as
+	// OpenAPI links on successful responses and listed in the HTML docs.
+	Links []Link `json:"links,omitempty"`
+	// Tags sets the operation tags, replacing the tag derived from the path.
+	// The first tag is used by GroupByTag.
+	Tags []string `json:"tags,omitempty"`
+	// Weight orders the endpoint within its docs group (lower first, default 0)
+	Weight int `json:"weight,omitempty"`
+	// Ownership documents who maintains the route and where to ask for help,
+	// shown on the docs card and exported as x-owner in the spec.
+	Ownership *Ownership `json:"ownership,omitempty"`
+	// ChangeLog lists the route's changes, newest first, shown as a History
+	// accordion on the docs card and exported as x-changelog in the spec.
+	ChangeLog []ChangeLogEntry `json:"changeLog,omitempty"`
+	// Extensions adds specification extensions to the route's operation, e.g.
+	// {"x-amazon-apigateway-integration": {...}}. Keys get an "x-" prefix when missing.
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	// CircuitBreaker rejects requests with 503 while the handler keeps failing,
+	// documenting the 503 response. Breaker states are served at /api-docs/breakers.
+	CircuitBreaker *CircuitBreakerConfig `json:"circuitBreaker,omitempty"`
+	// ConcurrencyLimit caps the requests the route processes at once, queueing
+	// or rejecting the others, and documents the limit and rejection status
+	ConcurrencyLimit *ConcurrencyLimit `json:"concurrencyLimit,omitempty"`
+	// IPFilter overrides Config.IPFilter for the route, e.g. to restrict admin
+	// routes to an office range; an empty filter accepts every client. Other
+	// clients get a documented 403 and the docs note the restriction.
+	IPFilter *IPFilter `json:"ipFilter,omitempty"`
+	// Compress overrides Config.Compression for the route: false disables it,
+	// e.g. for streamed responses, and true enables it with the default level.
+	Compress *bool `json:"compress,omitempty"`
+	// DecompressRequest overrides Config.RequestDecompression for the route:
+	// false disables it and true enables it with the default size limit.
+	DecompressRequest *bool `json:"decompressRequest,omitempty"`
+	// EncryptedRequest requires the request body encrypted as a compact JWE
+	// with a key of Config.RequestEncryption, e.g. for personal data. It is
+	// decrypted before validation; the spec documents the JWE headers and keys.
+	EncryptedRequest bool `json:"encryptedRequest,omitempty"`
+	// FeatureFlag gates the route behind a flag of Config.FeatureFlags: while it
+	// is disabled, requests get Config.FeatureFlagStatus. The docs badge the
+	// route as "behind flag" or hide it with Config.HideFlaggedRoutes.
+	FeatureFlag string `json:"featureFlag,omitempty"`
+	// Status is the route's review status (draft, review or stable), badged
+	// and filterable on the docs page and exported as x-status in the spec
+	Status EndpointStatus `json:"status,omitempty"`
+	// Deprecated marks the route deprecated in the spec and docs and answers
+	// with a Deprecation header. Its callers are recorded by JWT subject, API
+	// key and User-Agent (see ApiNote.DeprecatedRouteUsage) and reported at
+	// /api-docs/deprecations, to plan its removal.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Migration tells the consumers of a Deprecated route what to switch to
+	// and when it is sunset (see MigrationGuide)
+	Migration *MigrationGuide `json:"migration,omitempty"`
+	// APIKeyScopes requires an API key of Config.APIKeys granting every scope,
+	// e.g. []string{"orders:write"}; see ApiNote.UseAPIKeys to require keys
+	// without scopes. The scopes are documented with the apiKeyAuth scheme.
+	APIKeyScopes []string `json:"apiKeyScopes,omitempty"`
+	// Variants routes some requests to alternative handlers, e.g. for canary
+	// releases or A/B tests, by header or percentage. Handler serves the others.
+	Variants []RouteVariant `json:"variants,omitempty"`
+	// Thresholds overrides Config.Thresholds for the route
+	Thresholds *Thresholds `json:"thresholds,omitempty"`
+	// SLO declares the route's latency and availability objectives. Compliance
+	// and burn rates are served at /api-docs/slo and, for Prometheus, at
+	// /api-docs/slo/metrics; the docs badge routes breaching their SLO.
+	SLO *SLO `json:"slo,omitempty"`
+	// LocalizedDescriptions translates Description per locale, e.g.
+	// {"th": "..."}; the docs and spec show the viewer's language.
+	LocalizedDescriptions map[string]string `json:"localizedDescriptions,omitempty"`
+	// LocalizedResponses translates the Responses descriptions per locale and
+	// status (or registered error code), e.g. {"th": {"404": "..."}}
+	LocalizedResponses map[string]map[string]string `json:"localizedResponses,omitempty"`
+	// SparseFields lets clients select the top-level response fields with
+	// ?fields=id,name (see SparseFieldsMiddleware). The parameter is documented
+	// automatically; the response schema must be a struct or a slice of structs.
+	SparseFields bool `json:"sparseFields,omitempty"`
+	// TransformResponse declares the output struct the handler's JSON responses
+	// are reshaped into (see ResponseTransformMiddleware). It becomes the
+	// documented response schema in place of SchemasResponse.
+	TransformResponse interface{} `json:"transformResponse,omitempty"`
+	// Proxy attaches the OpenAPI document of the service the route proxies to;
+	// its operations for the route's method are documented in place of the route
+	Proxy *ProxiedService `json:"-"`
+	// ValidationRules check the request against the caller and the route once
+	// the body matches its schema, e.g. that only admins may set role to
+	// "admin" (see the validation package's RestrictValue). Violations are
+	// 400 validation errors; the rules are listed in the docs and the spec.
+	ValidationRules []ValidationRule `json:"-"`
+	// CallbackURLField names the request body field taking the URL the route
+	// calls back, e.g. "callbackUrl". With Config.CallbackRelay its try-it
+	// form fills in a temporary receive URL and shows the callbacks live.
+	CallbackURLField string `json:"callbackUrlField,omitempty"`
+
+	builtin bool // Built-in routes, e.g. the demo endpoints, keep their handler in mock mode
 }
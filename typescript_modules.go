@@ -0,0 +1,202 @@
+package notelink
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+)
+
+// TypeScriptModules renders the TypeScript types as an ES module tree, for
+// frontends that keep one file per type: models/<Name>.ts declares each
+// named struct and imports the models it references, operations.ts declares
+// the <OperationId>Request/Response aliases of every route, and index.ts
+// re-exports both. Keys are slash-separated paths relative to the output
+// directory.
+func (an *ApiNote) TypeScriptModules() map[string][]byte {
+	names := an.schemaNames()
+	header := "// Types for " + an.config.Title + ", generated by notelink\n"
+	files := make(map[string][]byte)
+
+	// Collect the named structs of every body and the structs they reference
+	models := make(map[string]reflect.Type)
+	var queue []reflect.Type
+	endpoints := an.sortedEndpoints()
+	for _, endpoint := range endpoints {
+		for _, body := range []interface{}{endpoint.RequestSchema, endpoint.ResponseSchema} {
+			if typ := bodyStructType(body); typ != nil {
+				if typ.Name() != "" {
+					queue = append(queue, typ)
+				} else {
+					queue = append(queue, tsReferences(typ)...)
+				}
+			}
+		}
+	}
+	for len(queue) > 0 {
+		typ := queue[0]
+		queue = queue[1:]
+		name := names.name(typ)
+		if _, ok := models[name]; ok {
+			continue
+		}
+		models[name] = typ
+		queue = append(queue, tsReferences(typ)...)
+	}
+
+	modelNames := make([]string, 0, len(models))
+	for name, typ := range models {
+		modelNames = append(modelNames, name)
+		var ts strings.Builder
+		ts.WriteString(header)
+		writeTypeScriptImports(&ts, "./", tsReferences(typ), name, names)
+		if typ.Kind() == reflect.Struct {
+			ts.WriteString("\n" + tsInterface(name, typeDescription(typ), typ, an.config.TimeFormat, names) + "\n")
+		} else {
+			ts.WriteString("\n" + tsEnumDeclaration(name, typ, names) + "\n")
+		}
+		files["models/"+name+".ts"] = []byte(ts.String())
+	}
+	sort.Strings(modelNames)
+
+	var imports []reflect.Type
+	var operations strings.Builder
+	for _, endpoint := range endpoints {
+		operation := toTitle(generateOperationID(endpoint.Method, endpoint.routePath()))
+		for _, body := range []struct {
+			name, description string
+			schema            interface{}
+		}{
+			{operation + "Request", bodyDescription("Request", &endpoint), endpoint.RequestSchema},
+			{operation + "Response", bodyDescription("Response", &endpoint), endpoint.ResponseSchema},
+		} {
+			typ := bodyStructType(body.schema)
+			if typ == nil {
+				continue
+			}
+			suffix := ""
+			if outer := reflect.TypeOf(body.schema); outer.Kind() == reflect.Slice || (outer.Kind() == reflect.Ptr && outer.Elem().Kind() == reflect.Slice) {
+				suffix = "[]"
+			}
+			if typ.Name() != "" {
+				imports = append(imports, typ)
+				operations.WriteString("\n" + jsDoc("", []string{body.description}) + "export type " + body.name + " = " + names.name(typ) + suffix + ";\n")
+				continue
+			}
+			imports = append(imports, tsReferences(typ)...)
+			operations.WriteString("\n" + tsInterface(body.name, body.description, typ, an.config.TimeFormat, names) + "\n")
+			if suffix != "" {
+				operations.WriteString("export type " + body.name + "List = " + body.name + "[];\n")
+			}
+		}
+	}
+	var ts strings.Builder
+	ts.WriteString(header)
+	writeTypeScriptImports(&ts, "./models/", imports, "", names)
+	ts.WriteString(operations.String())
+	files["operations.ts"] = []byte(ts.String())
+
+	var index strings.Builder
+	index.WriteString(header + "\n")
+	for _, name := range modelNames {
+		index.WriteString("export * from './models/" + name + "';\n")
+	}
+	index.WriteString("export * from './operations';\n")
+	files["index.ts"] = []byte(index.String())
+	return files
+}
+
+// ExportTypeScriptModules writes the TypeScript module tree of
+// TypeScriptModules to dir, e.g. src/api/types of a frontend. Files of
+// models that no longer exist are left in place.
+func (an *ApiNote) ExportTypeScriptModules(dir string) error {
+	for name, data := range an.TypeScriptModules() {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bodyStructType returns the struct type of a request or response body,
+// unwrapping pointers and slices, or nil for other bodies
+func bodyStructType(body interface{}) reflect.Type {
+	typ := reflect.TypeOf(body)
+	if typ == nil {
+		return nil
+	}
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == timeType {
+		return nil
+	}
+	if _, ok := lookupStdType(typ); ok {
+		return nil
+	}
+	return typ
+}
+
+// tsReferences returns the named structs and SchemaEnum types the fields of a
+// struct refer to, looking into anonymous structs, which are inlined
+func tsReferences(typ reflect.Type) []reflect.Type {
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	var refs []reflect.Type
+	for i := 0; i < typ.NumField(); i++ {
+		fieldType := typ.Field(i).Type
+		for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice {
+			fieldType = fieldType.Elem()
+		}
+		if valueType, ok := validation.NullableValueType(fieldType); ok {
+			fieldType = valueType
+		}
+		if validation.TypeEnumValues(fieldType) != nil {
+			if typ.Field(i).Tag.Get("enum") == "" {
+				refs = append(refs, fieldType)
+			}
+			continue
+		}
+		if fieldType.Kind() != reflect.Struct || fieldType == timeType {
+			continue
+		}
+		if _, ok := lookupStdType(fieldType); ok {
+			continue
+		}
+		if fieldType.Name() == "" {
+			refs = append(refs, tsReferences(fieldType)...)
+			continue
+		}
+		refs = append(refs, fieldType)
+	}
+	return refs
+}
+
+// writeTypeScriptImports writes one sorted type-only import per referenced
+// model, skipping self, the model being declared
+func writeTypeScriptImports(ts *strings.Builder, dir string, refs []reflect.Type, self string, names *schemaNames) {
+	seen := map[string]bool{self: true}
+	var imports []string
+	for _, ref := range refs {
+		if name := names.name(ref); !seen[name] {
+			seen[name] = true
+			imports = append(imports, name)
+		}
+	}
+	if len(imports) == 0 {
+		return
+	}
+	sort.Strings(imports)
+	ts.WriteString("\n")
+	for _, name := range imports {
+		ts.WriteString("import type { " + name + " } from '" + dir + name + "';\n")
+	}
+}
@@ -0,0 +1,110 @@
+package notelink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type tsModuleAddress struct {
+	City string `json:"city"`
+}
+
+type tsModuleCustomer struct {
+	Name    string            `json:"name"`
+	Address tsModuleAddress   `json:"address"`
+	Parent  *tsModuleCustomer `json:"parent,omitempty"`
+}
+
+type tsModuleOrder struct {
+	ID       int              `json:"id"`
+	Customer tsModuleCustomer `json:"customer"`
+	Lines    []struct {
+		Shipping tsModuleAddress `json:"shipping"`
+	} `json:"lines"`
+}
+
+// TestExportTypeScriptModules tests writing one module per model with imports
+func TestExportTypeScriptModules(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Orders API", Host: "localhost:8080"}, "secret")
+	handler := func(c fiber.Ctx) error { return nil }
+	routes := []*DocumentedRouteInput{
+		{Method: "GET", Path: "/orders", Description: "List orders", Handler: handler, SchemasResponse: []tsModuleOrder{}},
+		{Method: "POST", Path: "/orders/notes", Description: "Add a note", Handler: handler,
+			SchemasRequest: struct {
+				Text   string           `json:"text"`
+				Author tsModuleCustomer `json:"author"`
+			}{}},
+	}
+	for _, route := range routes {
+		if err := api.DocumentedRoute(route); err != nil {
+			t.Fatalf("Failed to register route: %v", err)
+		}
+	}
+
+	dir := t.TempDir()
+	if err := api.ExportTypeScriptModules(dir); err != nil {
+		t.Fatalf("Failed to export modules: %v", err)
+	}
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("Expected %s to be written: %v", name, err)
+		}
+		return string(data)
+	}
+
+	tests := []struct {
+		file     string
+		contains []string
+		excludes []string
+	}{
+		{
+			file: "models/tsModuleOrder.ts",
+			contains: []string{
+				"import type { tsModuleAddress } from './tsModuleAddress';\nimport type { tsModuleCustomer } from './tsModuleCustomer';\n",
+				"export interface tsModuleOrder {",
+				"customer: tsModuleCustomer;",
+			},
+		},
+		{
+			file:     "models/tsModuleCustomer.ts",
+			contains: []string{"import type { tsModuleAddress } from './tsModuleAddress';", "parent?: tsModuleCustomer | null;"},
+			excludes: []string{"from './tsModuleCustomer'"},
+		},
+		{
+			file:     "models/tsModuleAddress.ts",
+			contains: []string{"export interface tsModuleAddress {\n  city: string;\n}"},
+			excludes: []string{"import"},
+		},
+		{
+			file: "operations.ts",
+			contains: []string{
+				"import type { tsModuleCustomer } from './models/tsModuleCustomer';",
+				"import type { tsModuleOrder } from './models/tsModuleOrder';",
+				"export type GetOrdersResponse = tsModuleOrder[];",
+				"export interface PostOrdersNotesRequest {",
+			},
+		},
+		{
+			file:     "index.ts",
+			contains: []string{"export * from './models/tsModuleAddress';", "export * from './operations';"},
+		},
+	}
+	for _, tt := range tests {
+		content := read(tt.file)
+		for _, fragment := range tt.contains {
+			if !strings.Contains(content, fragment) {
+				t.Errorf("Expected %s to contain %q, got:\n%s", tt.file, fragment, content)
+			}
+		}
+		for _, fragment := range tt.excludes {
+			if strings.Contains(content, fragment) {
+				t.Errorf("Expected %s not to contain %q, got:\n%s", tt.file, fragment, content)
+			}
+		}
+	}
+}
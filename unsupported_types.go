@@ -0,0 +1,132 @@
+package notelink
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// UnsupportedType is a field of a registered schema whose Go type has no JSON
+// representation: a channel, func, unsafe pointer or complex number
+type UnsupportedType struct {
+	Owner string // What registered the schema, e.g. "POST /users" or "channel orders"
+	Field string // Path of the field from the schema's type, e.g. "User.Events"
+	Type  string // Go type of the field, e.g. "chan main.Event"
+}
+
+func (u UnsupportedType) String() string {
+	return u.Owner + ": " + u.Field + " has unsupported type " + u.Type
+}
+
+// unsupportedKind reports whether values of a kind cannot be encoded as JSON
+func unsupportedKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+// unsupportedSchema is the placeholder schema of an unsupported type
+func unsupportedSchema(t reflect.Type) *JSONSchema {
+	return &JSONSchema{Description: "Unsupported Go type " + t.String() + ", not representable in JSON"}
+}
+
+// findUnsupportedTypes lists the fields of a schema's type with an unsupported
+// type, following pointers, slices, arrays, maps and nested structs
+func findUnsupportedTypes(schema interface{}) []UnsupportedType {
+	if schema == nil {
+		return nil
+	}
+	var found []UnsupportedType
+	seen := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type, path string)
+	walk = func(t reflect.Type, path string) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if unsupportedKind(t.Kind()) {
+			found = append(found, UnsupportedType{Field: path, Type: t.String()})
+			return
+		}
+		if t.Kind() != reflect.Struct || t == timeType || seen[t] {
+			return
+		}
+		if _, ok := lookupStdType(t); ok {
+			return
+		}
+		if _, ok := validation.NullableValueType(t); ok {
+			return
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || validation.JSONFieldName(&field) == "-" {
+				continue
+			}
+			walk(field.Type, path+"."+field.Name)
+		}
+	}
+	t := reflect.TypeOf(schema)
+	name := t.String()
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Name() != "" {
+		name = t.Name()
+	}
+	walk(reflect.TypeOf(schema), name)
+	return found
+}
+
+// checkSchemaTypes reports the fields of registered schemas with unsupported
+// types: as an error, or with Config.AllowUnsupportedTypes as a warning, the
+// fields being documented with a placeholder schema and listed by
+// UnsupportedTypes.
+func (an *ApiNote) checkSchemaTypes(owner string, schemas ...interface{}) error {
+	var found []UnsupportedType
+	for _, schema := range schemas {
+		found = append(found, findUnsupportedTypes(schema)...)
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	fields := make([]string, len(found))
+	for i := range found {
+		found[i].Owner = owner
+		fields[i] = found[i].Field + " (" + found[i].Type + ")"
+	}
+	if !an.config.AllowUnsupportedTypes {
+		return fmt.Errorf("%s: unsupported field types %s: channels, funcs, unsafe pointers and complex numbers have no JSON representation; tag them json:\"-\" or set Config.AllowUnsupportedTypes", owner, strings.Join(fields, ", "))
+	}
+	log.Warnf("notelink: %s: documenting unsupported field types %s with placeholder schemas", owner, strings.Join(fields, ", "))
+	an.unsupportedTypes = append(an.unsupportedTypes, found...)
+	return nil
+}
+
+// UnsupportedTypes returns the schema fields with unsupported types that
+// Config.AllowUnsupportedTypes let through, in registration order
+func (an *ApiNote) UnsupportedTypes() []UnsupportedType {
+	return append([]UnsupportedType(nil), an.unsupportedTypes...)
+}
+
+// mountSchemaReport logs the fields of UnsupportedTypes once the app
+// listens, after the routes are registered
+func (an *ApiNote) mountSchemaReport() {
+	an.app.Hooks().OnListen(func(fiber.ListenData) error {
+		if len(an.unsupportedTypes) == 0 {
+			return nil
+		}
+		var report strings.Builder
+		fmt.Fprintf(&report, "notelink: %d schema field%s documented with placeholder schemas:", len(an.unsupportedTypes), pluralize(len(an.unsupportedTypes)))
+		for _, unsupported := range an.unsupportedTypes {
+			report.WriteString("\n  " + unsupported.String())
+		}
+		log.Warn(report.String())
+		return nil
+	})
+}
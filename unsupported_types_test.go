@@ -0,0 +1,76 @@
+package notelink
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type exoticPayload struct {
+	Name     string         `json:"name"`
+	Events   chan string    `json:"events"`
+	Callback func() error   `json:"-"`
+	Hooks    []func()       `json:"hooks"`
+	Nested   *exoticNested  `json:"nested"`
+	Signal   complex128     `json:"signal"`
+	internal unsafe.Pointer //nolint:unused // Unexported fields are not documented
+}
+
+type exoticNested struct {
+	Raw    unsafe.Pointer `json:"raw"`
+	Parent *exoticNested  `json:"parent"`
+}
+
+// TestUnsupportedTypes tests registering schemas with types JSON cannot represent
+func TestUnsupportedTypes(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return nil }
+	expected := []string{
+		"exoticPayload.Events (chan string)",
+		"exoticPayload.Hooks (func())",
+		"exoticPayload.Nested.Raw (unsafe.Pointer)",
+		"exoticPayload.Signal (complex128)",
+	}
+
+	api := NewApiNote(&Config{Title: "Test API"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{Method: "POST", Path: "/events", Handler: handler, SchemasRequest: exoticPayload{}})
+	if err == nil || !strings.HasPrefix(err.Error(), "POST /events: unsupported field types "+strings.Join(expected, ", ")) {
+		t.Errorf("Expected the unsupported fields in the error, got %v", err)
+	}
+	if err := api.DocumentChannel("events", &exoticPayload{}, ChannelPublish); err == nil || !strings.HasPrefix(err.Error(), "channel events:") {
+		t.Errorf("Expected channel payloads to be checked, got %v", err)
+	}
+	if err := api.RegisterErrorCode("EVENTS_FAILED", 500, "Events failed", exoticNested{}); err == nil || !strings.Contains(err.Error(), "exoticNested.Raw (unsafe.Pointer)") {
+		t.Errorf("Expected error schemas to be checked, got %v", err)
+	}
+
+	api = NewApiNote(&Config{Title: "Test API", AllowUnsupportedTypes: true}, "secret")
+	if err := api.DocumentedRoute(&DocumentedRouteInput{Method: "POST", Path: "/events", Handler: handler, SchemasRequest: exoticPayload{}, SchemasResponse: exoticPayload{}, Responses: map[string]string{"200": "OK"}}); err != nil {
+		t.Fatalf("Expected a warning only, got %v", err)
+	}
+	unsupported := api.UnsupportedTypes()
+	if len(unsupported) != 2*len(expected) {
+		t.Fatalf("Expected the request and response fields listed, got %v", unsupported)
+	}
+	if got := unsupported[0].String(); got != "POST /events: exoticPayload.Events has unsupported type chan string" {
+		t.Errorf("Unexpected report line %q", got)
+	}
+
+	schema := api.GenerateOpenAPISpec().Components.Schemas["exoticPayload"]
+	if events := schema.Properties["events"]; events == nil || events.Description != "Unsupported Go type chan string, not representable in JSON" {
+		t.Errorf("Expected a placeholder schema for the channel, got %+v", events)
+	}
+	if hooks := schema.Properties["hooks"]; hooks == nil || hooks.Items == nil || !strings.HasPrefix(hooks.Items.Description, "Unsupported Go type func()") {
+		t.Errorf("Expected placeholder items for the func slice, got %+v", hooks)
+	}
+	if _, ok := schema.Properties["Callback"]; ok {
+		t.Error("Expected fields tagged json:\"-\" to stay undocumented")
+	}
+	if name := schema.Properties["name"]; name == nil || name.Type != "string" {
+		t.Errorf("Expected the supported fields documented as usual, got %+v", name)
+	}
+	if html := api.generateHTML(); !strings.Contains(html, `id="test-result-POST-events"`) {
+		t.Error("Expected the docs to render the route")
+	}
+}
@@ -1,339 +1,108 @@
 package notelink
 
 import (
-	"fmt"
-	"reflect"
-	"strconv"
-	"strings"
+	"bytes"
 
+	"github.com/canvas-tech-horizon/notelink/validation"
 	"github.com/gofiber/fiber/v3"
 )
 
 // ValidationError represents a single validation error
-type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-	Type    string `json:"type,omitempty"`
-}
+type ValidationError = validation.FieldError
 
 // ValidationErrorResponse represents the validation error response
-type ValidationErrorResponse struct {
-	ErrorMessage string            `json:"error"`
-	Errors       []ValidationError `json:"errors,omitempty"`
-}
-
-// Error implements the error interface
-func (v *ValidationErrorResponse) Error() string {
-	return v.ErrorMessage
-}
+type ValidationErrorResponse = validation.ErrorResponse
 
-// ValidateParameters validates path/query/header parameters
+// ValidateParameters validates path/query/header parameters. Routes validate
+// with a ParameterValidator, which parses the parameter types once.
 func ValidateParameters(c fiber.Ctx, params []Parameter) error {
-	var errors []ValidationError
-
-	for _, param := range params {
-		value, exists := getParameterValue(c, param)
-
-		// Check if required parameter is missing
-		if param.Required && (!exists || value == "") {
-			errors = append(errors, ValidationError{
-				Field:   param.Name,
-				Message: fmt.Sprintf("Required parameter '%s' is missing", param.Name),
-				Type:    "required",
-			})
-			continue
-		}
+	return validation.Parameters(c, params)
+}
 
-		// Skip validation if parameter is not provided and not required
-		if !exists || value == "" {
-			continue
-		}
+// ParameterValidator validates a route's parameters with their types parsed
+// once, allocating nothing for valid requests. Validate takes the fiber.Ctx.
+type ParameterValidator = validation.ParameterValidator
 
-		// Validate parameter type
-		if _, err := validateParameterType(value, param.Type); err != nil {
-			errors = append(errors, ValidationError{
-				Field:   param.Name,
-				Message: fmt.Sprintf("Parameter '%s' must be of type %s: %v", param.Name, param.Type, err),
-				Type:    "type_error",
-			})
-		}
-	}
+// NewParameterValidator prepares the validation of params
+func NewParameterValidator(params []Parameter) *ParameterValidator {
+	return validation.NewParameterValidator(params)
+}
 
-	if len(errors) > 0 {
-		return &ValidationErrorResponse{
-			ErrorMessage: "Parameter validation failed",
-			Errors:       errors,
-		}
-	}
+// ValidateRequestBody validates request body against schema. JSON bodies are
+// decoded with the app's JSONDecoder.
+func ValidateRequestBody(c fiber.Ctx, schema interface{}) error {
+	return validateRequestBody(c, schema, "", DefaultStreamValidationThreshold)
+}
 
-	return nil
+// ValidateJSON validates a raw JSON body against schema, decoding it with
+// decode, e.g. sonic.Unmarshal, or goccy/go-json when nil
+func ValidateJSON(data []byte, schema interface{}, decode func(data []byte, v interface{}) error) error {
+	return validation.JSON(data, schema, "", decode)
 }
 
-// ValidateRequestBody validates request body against schema
-func ValidateRequestBody(c fiber.Ctx, schema interface{}) error {
+// validateRequestBody validates the request body, checking time.Time fields
+// without a timeformat tag against timeFormat. JSON bodies of streamThreshold
+// bytes or more are validated while tokenizing them (0 never does).
+func validateRequestBody(c fiber.Ctx, schema interface{}, timeFormat string, streamThreshold int) error {
 	if schema == nil {
 		return nil
 	}
-
-	// Get request body
-	var body map[string]interface{}
-	if err := c.Bind().Body(&body); err != nil {
-		return &ValidationErrorResponse{
-			ErrorMessage: "Invalid JSON body",
-			Errors: []ValidationError{{
-				Field:   "body",
-				Message: err.Error(),
-				Type:    "parse_error",
-			}},
+	if isNDJSONContentType(c.Get(fiber.HeaderContentType)) {
+		// Streamed bodies are left to ValidateNDJSON in the handler, which
+		// validates them as they are read
+		if c.Request().IsBodyStream() {
+			return nil
 		}
+		return validateNDJSON(bytes.NewReader(c.Body()), schema, timeFormat, nil)
 	}
-
-	// Validate against schema using reflection
-	schemaType := reflect.TypeOf(schema)
-	if schemaType.Kind() == reflect.Ptr {
-		schemaType = schemaType.Elem()
-	}
-
-	// Handle array schemas
-	if schemaType.Kind() == reflect.Slice {
-		// For array schemas, we don't validate structure
-		// Just ensure body can be parsed
-		return nil
-	}
-
-	errors := validateStruct(body, schemaType)
-	if len(errors) > 0 {
-		return &ValidationErrorResponse{
-			ErrorMessage: "Request body validation failed",
-			Errors:       errors,
-		}
+	if isCSVContentType(c.Get(fiber.HeaderContentType)) {
+		return validateCSV(c.Body(), schema, timeFormat)
 	}
-
-	return nil
-}
-
-// getParameterValue extracts parameter value from request based on parameter location
-func getParameterValue(c fiber.Ctx, param Parameter) (string, bool) {
-	switch param.In {
-	case "path":
-		value := c.Params(param.Name)
-		return value, value != ""
-	case "query":
-		value := c.Query(param.Name)
-		return value, value != ""
-	case "header":
-		value := c.Get(param.Name)
-		return value, value != ""
-	default:
-		return "", false
+	if streamThreshold > 0 && len(c.Body()) >= streamThreshold && validation.IsJSONContentType(c.Get(fiber.HeaderContentType)) {
+		return validation.JSONStream(bytes.NewReader(c.Body()), schema, timeFormat)
 	}
-}
 
-// validateParameterType validates and converts parameter value to the expected type
-func validateParameterType(value, paramType string) (interface{}, error) {
-	switch strings.ToLower(paramType) {
-	case "string":
-		return value, nil
-	case "number", "float", "double":
-		return strconv.ParseFloat(value, 64)
-	case "integer", "int":
-		return strconv.Atoi(value)
-	case "boolean", "bool":
-		return strconv.ParseBool(value)
-	default:
-		// Unknown type, pass through as string
-		return value, nil
+	body, err := decodeRequestBody(c)
+	if err != nil {
+		return err
 	}
+	return validation.Body(body, schema, timeFormat)
 }
 
-// validateStruct validates a map against a struct type
-func validateStruct(data map[string]interface{}, schemaType reflect.Type) []ValidationError {
-	var errors []ValidationError
-
-	// Handle non-struct types
-	if schemaType.Kind() != reflect.Struct {
-		return errors
-	}
-
-	for i := 0; i < schemaType.NumField(); i++ {
-		field := schemaType.Field(i)
-
-		// Skip unexported fields
-		if !field.IsExported() {
-			continue
-		}
-
-		// Get JSON field name
-		jsonName := getJSONFieldName(&field)
-		if jsonName == "-" {
-			continue
-		}
-
-		// Check if field is required (not pointer, no omitempty)
-		jsonTag := field.Tag.Get("json")
-		isOmitEmpty := strings.Contains(jsonTag, "omitempty")
-		isPointer := field.Type.Kind() == reflect.Ptr
-		isRequired := !isOmitEmpty && !isPointer
-
-		value, exists := data[jsonName]
-
-		// Check required fields
-		if isRequired && (!exists || value == nil) {
-			errors = append(errors, ValidationError{
-				Field:   jsonName,
-				Message: fmt.Sprintf("Required field '%s' is missing", jsonName),
-				Type:    "required",
-			})
-			continue
-		}
-
-		// Validate field type if value exists
-		if exists && value != nil {
-			if err := validateFieldType(value, field.Type, jsonName); err != nil {
-				errors = append(errors, *err)
+// decodeRequestBody decodes a JSON or MessagePack request body into a
+// JSON-shaped map, failing with a *ValidationErrorResponse
+func decodeRequestBody(c fiber.Ctx) (map[string]interface{}, error) {
+	if isMsgPackContentType(c.Get(fiber.HeaderContentType)) {
+		body, err := decodeMsgPackMap(c)
+		if err != nil {
+			return nil, &ValidationErrorResponse{
+				ErrorMessage: "Invalid MessagePack body",
+				Errors: []ValidationError{{
+					Field:   "body",
+					Message: err.Error(),
+					Type:    "parse_error",
+					Code:    validation.CodeParse,
+				}},
 			}
 		}
+		return body, nil
 	}
-
-	return errors
-}
-
-// validateFieldType validates the type of a field value
-func validateFieldType(value interface{}, expectedType reflect.Type, fieldName string) *ValidationError {
-	// Handle pointers
-	if expectedType.Kind() == reflect.Ptr {
-		expectedType = expectedType.Elem()
+	// JSON bodies are decoded straight from the raw bytes, skipping the binder
+	if validation.IsJSONContentType(c.Get(fiber.HeaderContentType)) {
+		return validation.DecodeJSON(c.Body(), c.App().Config().JSONDecoder)
 	}
 
-	actualValue := reflect.ValueOf(value)
-	if !actualValue.IsValid() {
-		return nil // nil value is okay for optional fields
-	}
-
-	switch expectedType.Kind() {
-	case reflect.String:
-		if actualValue.Kind() != reflect.String {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be a string", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		// JSON unmarshals numbers as float64
-		if actualValue.Kind() != reflect.Float64 {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be a number", fieldName),
-				Type:    "type_error",
-			}
-		}
-		// Check if it's an integer value
-		floatVal := actualValue.Float()
-		if floatVal != float64(int64(floatVal)) {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be an integer", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		// JSON unmarshals numbers as float64
-		if actualValue.Kind() != reflect.Float64 {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be a number", fieldName),
-				Type:    "type_error",
-			}
-		}
-		// Check if it's a non-negative integer value
-		floatVal := actualValue.Float()
-		if floatVal < 0 || floatVal != float64(int64(floatVal)) {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be a non-negative integer", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-	case reflect.Float32, reflect.Float64:
-		if actualValue.Kind() != reflect.Float64 && actualValue.Kind() != reflect.Int && actualValue.Kind() != reflect.Int64 {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be a number", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-	case reflect.Bool:
-		if actualValue.Kind() != reflect.Bool {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be a boolean", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-	case reflect.Slice, reflect.Array:
-		if actualValue.Kind() != reflect.Slice && actualValue.Kind() != reflect.Array {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be an array", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-		// Validate array elements recursively
-		elemType := expectedType.Elem()
-		sliceValue := reflect.ValueOf(value)
-		for i := 0; i < sliceValue.Len(); i++ {
-			elem := sliceValue.Index(i).Interface()
-			elemFieldName := fmt.Sprintf("%s[%d]", fieldName, i)
-
-			if err := validateFieldType(elem, elemType, elemFieldName); err != nil {
-				return err
-			}
-		}
-
-	case reflect.Map:
-		if actualValue.Kind() != reflect.Map {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be an object", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-	case reflect.Struct:
-		// Nested struct should be a map in JSON
-		if actualValue.Kind() != reflect.Map {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be an object", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-		// Validate nested struct recursively
-		nestedMap, ok := value.(map[string]interface{})
-		if !ok {
-			return &ValidationError{
-				Field:   fieldName,
-				Message: fmt.Sprintf("Field '%s' must be a valid object", fieldName),
-				Type:    "type_error",
-			}
-		}
-
-		nestedErrors := validateStruct(nestedMap, expectedType)
-		if len(nestedErrors) > 0 {
-			// Return the first nested error with updated field path
-			firstErr := nestedErrors[0]
-			firstErr.Field = fmt.Sprintf("%s.%s", fieldName, firstErr.Field)
-			return &firstErr
+	var body map[string]interface{}
+	if err := c.Bind().Body(&body); err != nil {
+		return nil, &ValidationErrorResponse{
+			ErrorMessage: "Invalid JSON body",
+			Errors: []ValidationError{{
+				Field:   "body",
+				Message: err.Error(),
+				Type:    "parse_error",
+				Code:    validation.CodeParse,
+			}},
 		}
 	}
-
-	return nil
+	return body, nil
 }
@@ -0,0 +1,32 @@
+package validation
+
+// Machine-readable codes of FieldErrors, stable across releases so clients
+// can branch on them instead of parsing messages. A code never changes
+// meaning; new kinds of failures get new codes.
+const (
+	CodeRequired = "NL4001_REQUIRED" // A required field or parameter is missing
+	CodeType     = "NL4002_TYPE"     // A value has the wrong type
+	CodeFormat   = "NL4003_FORMAT"   // A string isn't in the format of its type, e.g. a time layout or URL
+	CodeEnum     = "NL4004_ENUM"     // A value isn't one of the allowed values
+	CodeParse    = "NL4005_PARSE"    // The body can't be parsed
+	CodeRule     = "NL4006_RULE"     // A validation rule rejected the request
+)
+
+// CodeInfo documents a FieldError code for error-code references
+type CodeInfo struct {
+	Code        string `json:"code"`
+	Type        string `json:"type"` // FieldError.Type of the code
+	Description string `json:"description"`
+}
+
+// Codes returns the FieldError codes in code order
+func Codes() []CodeInfo {
+	return []CodeInfo{
+		{CodeRequired, "required", "A required field or parameter is missing"},
+		{CodeType, "type_error", "A value has the wrong type, e.g. a string where a number is expected"},
+		{CodeFormat, "format_error", "A string isn't in the format of its type, e.g. a time layout, duration or URL"},
+		{CodeEnum, "enum_error", "A value isn't one of the allowed values"},
+		{CodeParse, "parse_error", "The body can't be parsed in its content type"},
+		{CodeRule, "rule_error", "A validation rule of the route rejected the request"},
+	}
+}
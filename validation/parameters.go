@@ -0,0 +1,239 @@
+package validation
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Parameter describes a path, query, or header parameter
+type Parameter struct {
+	Name        string
+	In          string // "query", "path", "header"
+	Type        string // e.g., "string", "number", "boolean", "array", "object"
+	Description string
+	Required    bool
+	Items       string // Item type of "array" parameters, e.g. "integer"
+	// Style and Explode set the OpenAPI serialization of the value, e.g.
+	// "pipeDelimited"; they default to "simple" in paths and headers and to
+	// exploded "form" in queries
+	Style   string
+	Explode *bool
+	// AllowReserved sends the reserved characters of query values unencoded
+	AllowReserved bool
+}
+
+// Values looks up the parameter values of a request by location. fiber.Ctx
+// implements it; HTTPValues adapts an *http.Request.
+type Values interface {
+	Params(key string, defaultValue ...string) string // Path parameters
+	Query(key string, defaultValue ...string) string  // Query parameters
+	Get(key string, defaultValue ...string) string    // Request headers
+}
+
+// httpValues looks up parameters in an *http.Request
+type httpValues struct {
+	r     *http.Request
+	query url.Values
+}
+
+// HTTPValues returns the parameter values of an *http.Request, reading path
+// parameters with r.PathValue as routed by http.ServeMux
+func HTTPValues(r *http.Request) Values {
+	return &httpValues{r: r, query: r.URL.Query()}
+}
+
+func (v *httpValues) Params(key string, defaultValue ...string) string {
+	return valueOr(v.r.PathValue(key), defaultValue)
+}
+
+func (v *httpValues) Query(key string, defaultValue ...string) string {
+	return valueOr(v.query.Get(key), defaultValue)
+}
+
+func (v *httpValues) Get(key string, defaultValue ...string) string {
+	return valueOr(v.r.Header.Get(key), defaultValue)
+}
+
+// valueOr returns value, or the default when it is empty
+func valueOr(value string, defaultValue []string) string {
+	if value == "" && len(defaultValue) > 0 {
+		return defaultValue[0]
+	}
+	return value
+}
+
+// Parameters validates path/query/header parameters. Repeated validations
+// should use a ParameterValidator, which parses the parameter types once.
+func Parameters(values Values, params []Parameter) error {
+	return validateParameters(values, params, nil)
+}
+
+// parameterKind is the type a parameter value is parsed as
+type parameterKind uint8
+
+const (
+	parameterString parameterKind = iota // Also unknown types, passed through
+	parameterNumber
+	parameterInteger
+	parameterBoolean
+)
+
+// parameterKindOf parses a Parameter.Type, case-insensitively and without allocating
+func parameterKindOf(paramType string) parameterKind {
+	switch {
+	case strings.EqualFold(paramType, "number"), strings.EqualFold(paramType, "float"), strings.EqualFold(paramType, "double"):
+		return parameterNumber
+	case strings.EqualFold(paramType, "integer"), strings.EqualFold(paramType, "int"):
+		return parameterInteger
+	case strings.EqualFold(paramType, "boolean"), strings.EqualFold(paramType, "bool"):
+		return parameterBoolean
+	}
+	return parameterString
+}
+
+// checkParameterValue parses a value as its kind, without keeping the result
+// so valid values don't allocate
+func checkParameterValue(value string, kind parameterKind) error {
+	var err error
+	switch kind {
+	case parameterNumber:
+		_, err = strconv.ParseFloat(value, 64)
+	case parameterInteger:
+		_, err = strconv.Atoi(value)
+	case parameterBoolean:
+		_, err = strconv.ParseBool(value)
+	}
+	return err
+}
+
+// ParameterValidator validates a route's parameters with their types parsed
+// once, allocating nothing for valid requests
+type ParameterValidator struct {
+	params []Parameter
+	kinds  []parameterKind
+}
+
+// NewParameterValidator prepares the validation of params
+func NewParameterValidator(params []Parameter) *ParameterValidator {
+	kinds := make([]parameterKind, len(params))
+	for i, param := range params {
+		kinds[i] = parameterKindOf(param.Type)
+	}
+	return &ParameterValidator{params: params, kinds: kinds}
+}
+
+// Validate validates the parameters of a request like Parameters
+func (v *ParameterValidator) Validate(values Values) error {
+	return validateParameters(values, v.params, v.kinds)
+}
+
+// validationErrorsPool recycles the slices collecting the errors of invalid
+// requests; the response gets a copy of the exact size
+var validationErrorsPool = sync.Pool{
+	New: func() interface{} {
+		errors := make([]FieldError, 0, 8)
+		return &errors
+	},
+}
+
+// appendValidationError appends to pooled errors, taking a slice from the
+// pool on the first error
+func appendValidationError(errors *[]FieldError, err FieldError) *[]FieldError {
+	if errors == nil {
+		errors = validationErrorsPool.Get().(*[]FieldError)
+	}
+	*errors = append(*errors, err)
+	return errors
+}
+
+// validateParameters validates params with their kinds, parsed from their
+// types when kinds is nil
+func validateParameters(values Values, params []Parameter, kinds []parameterKind) error {
+	var errors *[]FieldError
+	for i := range params {
+		param := &params[i]
+		value, exists := getParameterValue(values, param)
+
+		// Check if required parameter is missing
+		if param.Required && (!exists || value == "") {
+			errors = appendValidationError(errors, FieldError{
+				Field:   param.Name,
+				Message: fmt.Sprintf("Required parameter '%s' is missing", param.Name),
+				Type:    "required",
+				Code:    CodeRequired,
+			})
+			continue
+		}
+
+		// Skip validation if parameter is not provided and not required
+		if !exists || value == "" {
+			continue
+		}
+
+		// Validate parameter type
+		var kind parameterKind
+		if kinds != nil {
+			kind = kinds[i]
+		} else {
+			kind = parameterKindOf(param.Type)
+		}
+		if err := checkParameterValue(value, kind); err != nil {
+			errors = appendValidationError(errors, FieldError{
+				Field:   param.Name,
+				Message: fmt.Sprintf("Parameter '%s' must be of type %s: %v", param.Name, param.Type, err),
+				Type:    "type_error",
+				Code:    CodeType,
+			})
+		}
+	}
+
+	if errors == nil {
+		return nil
+	}
+	response := &ErrorResponse{
+		ErrorMessage: "Parameter validation failed",
+		Errors:       append([]FieldError(nil), *errors...),
+	}
+	clear(*errors)
+	*errors = (*errors)[:0]
+	validationErrorsPool.Put(errors)
+	return response
+}
+
+// getParameterValue extracts parameter value from request based on parameter location
+func getParameterValue(values Values, param *Parameter) (string, bool) {
+	switch param.In {
+	case "path":
+		value := values.Params(param.Name)
+		return value, value != ""
+	case "query":
+		value := values.Query(param.Name)
+		return value, value != ""
+	case "header":
+		value := values.Get(param.Name)
+		return value, value != ""
+	default:
+		return "", false
+	}
+}
+
+// validateParameterType validates and converts parameter value to the expected type
+func validateParameterType(value, paramType string) (interface{}, error) {
+	switch strings.ToLower(paramType) {
+	case "string":
+		return value, nil
+	case "number", "float", "double":
+		return strconv.ParseFloat(value, 64)
+	case "integer", "int":
+		return strconv.Atoi(value)
+	case "boolean", "bool":
+		return strconv.ParseBool(value)
+	default:
+		// Unknown type, pass through as string
+		return value, nil
+	}
+}
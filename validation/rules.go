@@ -0,0 +1,189 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Context carries the request-scoped data rules are checked against: the
+// authenticated caller and the route being validated
+type Context struct {
+	Subject string                       // Authenticated caller, e.g. the JWT "sub" claim; empty for anonymous requests
+	Claims  map[string]interface{}       // Claims of the caller's token or session, e.g. {"role": "admin"}
+	Scopes  []string                     // Scopes granted to the caller's API key
+	Method  string                       // Route method, e.g. "POST"
+	Path    string                       // Route path, e.g. "/users/:id"
+	Tags    []string                     // Route tags
+	Values  Values                       // Path, query and header values of the request
+	Locals  func(key string) interface{} // Request-scoped values set by middleware, when available
+}
+
+// HasClaim reports whether the caller's claim equals one of values. List
+// claims, e.g. {"roles": ["admin", "billing"]}, match when one item does.
+func (c *Context) HasClaim(name string, values ...string) bool {
+	if c == nil {
+		return false
+	}
+	claim, ok := c.Claims[name]
+	if !ok {
+		return false
+	}
+	items, ok := claim.([]interface{})
+	if !ok {
+		items = []interface{}{claim}
+	}
+	for _, item := range items {
+		for _, value := range values {
+			if fmt.Sprint(item) == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the caller's API key grants scope
+func (c *Context) HasScope(scope string) bool {
+	if c == nil {
+		return false
+	}
+	for _, granted := range c.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Condition is a predicate on the caller, described for the docs
+type Condition struct {
+	Description string // Who matches, e.g. "callers whose role claim is admin"
+	Match       func(ctx *Context) bool
+}
+
+// Authenticated matches callers identified by authentication
+func Authenticated() Condition {
+	return Condition{
+		Description: "authenticated callers",
+		Match:       func(ctx *Context) bool { return ctx != nil && ctx.Subject != "" },
+	}
+}
+
+// ClaimIn matches callers whose claim has one of values
+func ClaimIn(claim string, values ...string) Condition {
+	return Condition{
+		Description: fmt.Sprintf("callers whose %s claim is %s", claim, strings.Join(values, " or ")),
+		Match:       func(ctx *Context) bool { return ctx.HasClaim(claim, values...) },
+	}
+}
+
+// ScopeGranted matches callers whose API key grants scope
+func ScopeGranted(scope string) Condition {
+	return Condition{
+		Description: fmt.Sprintf("API keys with the %s scope", scope),
+		Match:       func(ctx *Context) bool { return ctx.HasScope(scope) },
+	}
+}
+
+// Rule is a custom or cross-field check of a request body, given the request
+// context. Rules run once the body is valid against its schema.
+type Rule struct {
+	// Field is the JSON field the rule is about, reported with violations;
+	// dots address nested fields, e.g. "address.country"
+	Field string
+	// Description documents the rule in the spec and the docs
+	Description string
+	// Check returns an error describing the violation, if any. body is nil
+	// for requests without a JSON object body.
+	Check func(ctx *Context, body map[string]interface{}) error
+}
+
+// RestrictValue only lets callers matching condition set field to value, e.g.
+//
+//	RestrictValue("role", "admin", ClaimIn("role", "admin"))
+func RestrictValue(field, value string, condition Condition) Rule {
+	return Rule{
+		Field:       field,
+		Description: fmt.Sprintf("Only %s may set %s to %s", condition.Description, field, strconv.Quote(value)),
+		Check: func(ctx *Context, body map[string]interface{}) error {
+			if actual, ok := Lookup(body, field); ok && actual != nil && fmt.Sprint(actual) == value && !condition.Match(ctx) {
+				return fmt.Errorf("Field '%s' may only be set to %s by %s", field, strconv.Quote(value), condition.Description)
+			}
+			return nil
+		},
+	}
+}
+
+// RestrictField only lets callers matching condition set field
+func RestrictField(field string, condition Condition) Rule {
+	return Rule{
+		Field:       field,
+		Description: fmt.Sprintf("Only %s may set %s", condition.Description, field),
+		Check: func(ctx *Context, body map[string]interface{}) error {
+			if actual, ok := Lookup(body, field); ok && actual != nil && !condition.Match(ctx) {
+				return fmt.Errorf("Field '%s' may only be set by %s", field, condition.Description)
+			}
+			return nil
+		},
+	}
+}
+
+// RequiredWhen requires field when the other field equals value, e.g. a
+// "reason" when "status" is "rejected"
+func RequiredWhen(field, other, value string) Rule {
+	return Rule{
+		Field:       field,
+		Description: fmt.Sprintf("%s is required when %s is %s", field, other, strconv.Quote(value)),
+		Check: func(ctx *Context, body map[string]interface{}) error {
+			actual, ok := Lookup(body, other)
+			if !ok || actual == nil || fmt.Sprint(actual) != value {
+				return nil
+			}
+			if present, ok := Lookup(body, field); !ok || present == nil {
+				return fmt.Errorf("Field '%s' is required when '%s' is %s", field, other, strconv.Quote(value))
+			}
+			return nil
+		},
+	}
+}
+
+// Lookup returns the value of a dotted field path in a decoded JSON object
+func Lookup(body map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = body
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[name]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// Rules checks body against rules in order, returning an *ErrorResponse
+// listing every violation
+func Rules(ctx *Context, body map[string]interface{}, rules []Rule) error {
+	var errs []FieldError
+	for _, rule := range rules {
+		if rule.Check == nil {
+			continue
+		}
+		if err := rule.Check(ctx, body); err != nil {
+			field := rule.Field
+			if field == "" {
+				field = "body"
+			}
+			errs = append(errs, FieldError{Field: field, Message: err.Error(), Type: "rule_error", Code: CodeRule})
+		}
+	}
+	if len(errs) > 0 {
+		return &ErrorResponse{
+			ErrorMessage: "Request validation failed",
+			Errors:       errs,
+		}
+	}
+	return nil
+}
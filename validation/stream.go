@@ -0,0 +1,262 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// errNotObject is the parse error of a JSON body that isn't an object
+var errNotObject = errors.New("expected a JSON object")
+
+// JSONStream validates a JSON object read from r against schema while
+// tokenizing it, without materializing it as a map, so multi-MB bodies are
+// validated in little memory. It reports the same errors as JSON.
+func JSONStream(r io.Reader, schema interface{}, timeFormat string) error {
+	if schema == nil {
+		return nil
+	}
+	schemaType := reflect.TypeOf(schema)
+	if schemaType.Kind() == reflect.Ptr {
+		schemaType = schemaType.Elem()
+	}
+
+	walker := &jsonWalker{dec: json.NewDecoder(r), timeFormat: timeFormat}
+	validationErrors, err := walker.body(schemaType)
+	if err != nil {
+		return parseError("Invalid JSON body", err)
+	}
+	if len(validationErrors) > 0 {
+		return &ErrorResponse{
+			ErrorMessage: "Request body validation failed",
+			Errors:       validationErrors,
+		}
+	}
+	return nil
+}
+
+// jsonWalker validates JSON values token by token. Scalars are checked with
+// Value, objects and arrays are walked without being decoded.
+type jsonWalker struct {
+	dec        *json.Decoder
+	timeFormat string
+}
+
+// body validates the top-level object, which must be the only value
+func (w *jsonWalker) body(schemaType reflect.Type) ([]FieldError, error) {
+	token, err := w.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	var validationErrors []FieldError
+	switch token {
+	case nil: // null validates like an empty object
+		if schemaType.Kind() == reflect.Struct {
+			validationErrors = Struct(nil, schemaType, w.timeFormat)
+		}
+	case json.Delim('{'):
+		if schemaType.Kind() == reflect.Struct {
+			validationErrors, err = w.object(schemaType)
+		} else {
+			// Only struct schemas are validated, other bodies just have to parse
+			err = w.skipContainer()
+		}
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errNotObject
+	}
+
+	if _, err := w.dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON object")
+	}
+	return validationErrors, nil
+}
+
+// object validates the members of an object against a struct type, after its
+// opening brace, reporting errors in field order like Struct
+func (w *jsonWalker) object(structType reflect.Type) ([]FieldError, error) {
+	fields := Fields(structType)
+	seen := make([]bool, len(fields))
+	fieldErrors := make([]*FieldError, len(fields))
+
+	for w.dec.More() {
+		token, err := w.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+		index := -1
+		for i := range fields {
+			if fields[i].JSONName == key {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			if err := w.skipValue(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		field := &fields[index]
+		value, err := w.value(field.StructField.Type, field.JSONName, FieldTimeFormat(&field.StructField, w.timeFormat))
+		if err != nil {
+			return nil, err
+		}
+		// The last occurrence of a repeated member counts, as when decoding
+		seen[index] = value.present
+		fieldErrors[index] = value.err
+		if value.err == nil && value.scalar != nil {
+			fieldErrors[index] = EnumValue(value.scalar, &field.StructField, field.JSONName)
+		}
+	}
+	if _, err := w.dec.Token(); err != nil { // Closing brace
+		return nil, err
+	}
+
+	var validationErrors []FieldError
+	for i, field := range fields {
+		switch {
+		case field.Required && !seen[i]:
+			validationErrors = append(validationErrors, FieldError{
+				Field:   field.JSONName,
+				Message: fmt.Sprintf("Required field '%s' is missing", field.JSONName),
+				Type:    "required",
+				Code:    CodeRequired,
+			})
+		case fieldErrors[i] != nil:
+			validationErrors = append(validationErrors, *fieldErrors[i])
+		}
+	}
+	return validationErrors, nil
+}
+
+// walkedValue is the outcome of validating a value
+type walkedValue struct {
+	present bool        // Not null
+	scalar  interface{} // The value when it is a scalar, for the enum check
+	err     *FieldError // The first error of the value, as Value reports it
+}
+
+// value validates the next value against a field type
+func (w *jsonWalker) value(expectedType reflect.Type, fieldName, timeFormat string) (walkedValue, error) {
+	token, err := w.dec.Token()
+	if err != nil {
+		return walkedValue{}, err
+	}
+
+	switch token {
+	case nil:
+		return walkedValue{}, nil
+	case json.Delim('{'):
+		if structType, ok := walkableStruct(expectedType); ok {
+			nestedErrors, err := w.object(structType)
+			if err != nil || len(nestedErrors) == 0 {
+				return walkedValue{present: true}, err
+			}
+			// Report the first nested error with its path, like Value
+			first := nestedErrors[0]
+			first.Field = fieldName + "." + first.Field
+			return walkedValue{present: true, err: &first}, nil
+		}
+		if err := w.skipContainer(); err != nil {
+			return walkedValue{}, err
+		}
+		return walkedValue{present: true, err: Value(map[string]interface{}{}, expectedType, fieldName, timeFormat)}, nil
+	case json.Delim('['):
+		if elemType, ok := walkableSlice(expectedType); ok {
+			var first *FieldError
+			for i := 0; w.dec.More(); i++ {
+				if first != nil {
+					if err := w.skipValue(); err != nil {
+						return walkedValue{}, err
+					}
+					continue
+				}
+				elem, err := w.value(elemType, fmt.Sprintf("%s[%d]", fieldName, i), timeFormat)
+				if err != nil {
+					return walkedValue{}, err
+				}
+				first = elem.err
+			}
+			_, err := w.dec.Token() // Closing bracket
+			return walkedValue{present: true, err: first}, err
+		}
+		if err := w.skipContainer(); err != nil {
+			return walkedValue{}, err
+		}
+		return walkedValue{present: true, err: Value([]interface{}{}, expectedType, fieldName, timeFormat)}, nil
+	}
+	return walkedValue{present: true, scalar: token, err: Value(token, expectedType, fieldName, timeFormat)}, nil
+}
+
+// skipValue skips the next value
+func (w *jsonWalker) skipValue() error {
+	token, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == json.Delim('{') || token == json.Delim('[') {
+		return w.skipContainer()
+	}
+	return nil
+}
+
+// skipContainer skips the rest of an object or array, after its opening delimiter
+func (w *jsonWalker) skipContainer() error {
+	for depth := 1; depth > 0; {
+		token, err := w.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// resolveFieldType unwraps pointers and nullable wrappers like Value
+func resolveFieldType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if valueType, ok := NullableValueType(t); ok {
+		t = valueType
+	}
+	return t
+}
+
+// walkableStruct returns the struct type objects of a field are walked
+// against; time.Time and the standard types are validated as scalars
+func walkableStruct(t reflect.Type) (reflect.Type, bool) {
+	t = resolveFieldType(t)
+	if t.Kind() != reflect.Struct || t == timeType {
+		return nil, false
+	}
+	if isStdType(t) {
+		return nil, false
+	}
+	return t, true
+}
+
+// walkableSlice returns the element type arrays of a field are walked against
+func walkableSlice(t reflect.Type) (reflect.Type, bool) {
+	t = resolveFieldType(t)
+	if isStdType(t) {
+		return nil, false
+	}
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, false
+	}
+	return t.Elem(), true
+}
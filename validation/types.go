@@ -0,0 +1,243 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Time formats for time.Time fields, set per field with a `timeformat:"unix"`
+// struct tag or for a whole schema with the timeFormat arguments. Any other
+// value is treated as a Go time layout, e.g. "2006-01-02".
+const (
+	TimeFormatRFC3339   = "rfc3339"   // RFC 3339 string (encoding/json's default)
+	TimeFormatUnix      = "unix"      // Seconds since the Unix epoch
+	TimeFormatUnixMilli = "unixmilli" // Milliseconds since the Unix epoch
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Enum is implemented by named types with a fixed set of values. Fields of
+// the type must hold one of them, unless an `enum:"a,b"` tag overrides them.
+type Enum interface {
+	EnumValues() []string
+}
+
+// JSONFieldName returns the JSON name of a struct field: the name in its json
+// tag, "-" for skipped fields, or the field name in camelCase without a tag
+func JSONFieldName(field *reflect.StructField) string {
+	jsonTag := field.Tag.Get("json")
+	if jsonTag == "" {
+		// Convert to camelCase if no JSON tag
+		name := field.Name
+		return strings.ToLower(name[:1]) + name[1:]
+	}
+
+	// Parse JSON tag (e.g., "field_name,omitempty")
+	parts := strings.Split(jsonTag, ",")
+	return parts[0]
+}
+
+// NullableValueType returns the value type wrapped by a database/sql null type
+// such as sql.NullString, sql.NullInt64, sql.NullTime or sql.Null[T]. Wrappers
+// that embed one of them as their only field, like the guregu/null types, are
+// unwrapped as well. These types validate as their value type, or null.
+func NullableValueType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	// guregu/null style wrappers embed the sql type
+	if t.NumField() == 1 && t.Field(0).Anonymous {
+		return NullableValueType(t.Field(0).Type)
+	}
+
+	if t.PkgPath() != "database/sql" || !strings.HasPrefix(t.Name(), "Null") {
+		return nil, false
+	}
+	if valid, ok := t.FieldByName("Valid"); !ok || valid.Type.Kind() != reflect.Bool || t.NumField() != 2 {
+		return nil, false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Name != "Valid" {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
+
+// IsNullable reports whether a field type accepts null: pointers and sql null types
+func IsNullable(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		return true
+	}
+	_, ok := NullableValueType(t)
+	return ok
+}
+
+// TypeEnumValues returns the EnumValues of an Enum type, or nil
+func TypeEnumValues(t reflect.Type) []string {
+	if t.Kind() == reflect.Struct || t.Kind() == reflect.Interface || t.Kind() == reflect.Ptr {
+		return nil
+	}
+	if enum, ok := reflect.New(t).Interface().(Enum); ok {
+		return enum.EnumValues()
+	}
+	return nil
+}
+
+// EnumValues returns the allowed values declared with an `enum:"a,b"` struct
+// tag or, without one, by the field's Enum type
+func EnumValues(field *reflect.StructField) []string {
+	tag := field.Tag.Get("enum")
+	if tag == "" {
+		t := field.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		return TypeEnumValues(t)
+	}
+	return strings.Split(tag, ",")
+}
+
+// IsTimeType reports whether t is time.Time, or a pointer, slice, array or
+// sql null type of it
+func IsTimeType(t reflect.Type) bool {
+	for {
+		if t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			t = t.Elem()
+		} else if valueType, ok := NullableValueType(t); ok {
+			t = valueType
+		} else {
+			return t == timeType
+		}
+	}
+}
+
+// FieldTimeFormat returns the format to use for a struct field's type: its
+// timeformat tag, defaultFormat or RFC 3339 for time fields, and defaultFormat
+// for anything else so nested structs resolve their own fields
+func FieldTimeFormat(field *reflect.StructField, defaultFormat string) string {
+	if !IsTimeType(field.Type) {
+		return defaultFormat
+	}
+	if tag := field.Tag.Get("timeformat"); tag != "" {
+		return tag
+	}
+	if defaultFormat != "" {
+		return defaultFormat
+	}
+	return TimeFormatRFC3339
+}
+
+// IsUnixTimeFormat reports whether the format serializes times as numbers
+func IsUnixTimeFormat(format string) bool {
+	return strings.EqualFold(format, TimeFormatUnix) || strings.EqualFold(format, TimeFormatUnixMilli)
+}
+
+// TimeLayout returns the Go layout for string formats
+func TimeLayout(format string) string {
+	if format == "" || strings.EqualFold(format, TimeFormatRFC3339) {
+		return time.RFC3339
+	}
+	return format
+}
+
+// validateTime checks that a decoded JSON value is a time in the given format
+func validateTime(value interface{}, format, fieldName string) *FieldError {
+	if IsUnixTimeFormat(format) {
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be an integer Unix timestamp", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+		return nil
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return &FieldError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("Field '%s' must be a string", fieldName),
+			Type:    "type_error",
+			Code:    CodeType,
+		}
+	}
+	layout := TimeLayout(format)
+	if _, err := time.Parse(layout, str); err != nil {
+		return &FieldError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("Field '%s' must be a time in the format %s", fieldName, layout),
+			Type:    "format_error",
+			Code:    CodeFormat,
+		}
+	}
+	return nil
+}
+
+// iso8601Duration matches ISO 8601 durations such as P1DT2H or PT1H30M
+var iso8601Duration = regexp.MustCompile(`^P(?:\d+(?:\.\d+)?[YMWD])*(?:T(?:\d+(?:\.\d+)?[HMS])+)?$`)
+
+// stdTypes validates the string representations of standard library types.
+// time.Time is handled separately since its format is configurable.
+var stdTypes = map[reflect.Type]func(s string) error{
+	reflect.TypeOf(time.Duration(0)): func(s string) error {
+		if _, err := time.ParseDuration(s); err == nil {
+			return nil
+		}
+		if s != "P" && s != "PT" && iso8601Duration.MatchString(s) {
+			return nil
+		}
+		return fmt.Errorf("must be a duration such as 1h30m or PT1H30M")
+	},
+	reflect.TypeOf(net.IP{}): func(s string) error {
+		if net.ParseIP(s) == nil {
+			return fmt.Errorf("must be an IP address")
+		}
+		return nil
+	},
+	reflect.TypeOf(url.URL{}): func(s string) error {
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" {
+			return fmt.Errorf("must be an absolute URL")
+		}
+		return nil
+	},
+}
+
+// isStdType reports whether t is a standard library type validated as a
+// string, such as time.Duration, net.IP and url.URL
+func isStdType(t reflect.Type) bool {
+	_, ok := stdTypes[t]
+	return ok
+}
+
+// validateStdType checks that a decoded JSON value is a valid string representation of the type
+func validateStdType(value interface{}, validate func(s string) error, fieldName string) *FieldError {
+	str, ok := value.(string)
+	if !ok {
+		return &FieldError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("Field '%s' must be a string", fieldName),
+			Type:    "type_error",
+			Code:    CodeType,
+		}
+	}
+	if err := validate(str); err != nil {
+		return &FieldError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("Field '%s' %v", fieldName, err),
+			Type:    "format_error",
+			Code:    CodeFormat,
+		}
+	}
+	return nil
+}
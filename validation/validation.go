@@ -0,0 +1,430 @@
+// Package validation validates request parameters and bodies against Go
+// struct schemas the way notelink routes do, without depending on Fiber. It
+// works on decoded JSON values, raw bodies and *http.Request, so validation
+// can be unit-tested and reused outside the web layer.
+//
+// Struct fields are required unless they are pointers, sql null types or
+// tagged omitempty, and are checked against their type, their
+// `timeformat:"unix"` tag and their `enum:"a,b"` tag or Enum type. Rules add
+// custom and cross-field checks given a Context with the caller and the route.
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/goccy/go-json"
+)
+
+// FieldError represents a single validation error
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Type    string `json:"type,omitempty"`
+	// Code is the stable machine-readable code of the error, e.g. CodeRequired
+	Code string `json:"code,omitempty" enum:"NL4001_REQUIRED,NL4002_TYPE,NL4003_FORMAT,NL4004_ENUM,NL4005_PARSE,NL4006_RULE"`
+}
+
+// ErrorResponse represents the validation error response
+type ErrorResponse struct {
+	ErrorMessage string       `json:"error"`
+	Errors       []FieldError `json:"errors,omitempty"`
+}
+
+// Error implements the error interface
+func (v *ErrorResponse) Error() string {
+	return v.ErrorMessage
+}
+
+// parseError returns the *ErrorResponse of a body that can't be parsed
+func parseError(message string, err error) *ErrorResponse {
+	return &ErrorResponse{
+		ErrorMessage: message,
+		Errors: []FieldError{{
+			Field:   "body",
+			Message: err.Error(),
+			Type:    "parse_error",
+			Code:    CodeParse,
+		}},
+	}
+}
+
+// Request validates the JSON body of r against schema, checking time.Time
+// fields without a timeformat tag against timeFormat. The body is read and
+// replaced, so handlers can still decode it.
+func Request(r *http.Request, schema interface{}, timeFormat string) error {
+	if schema == nil {
+		return nil
+	}
+	var data []byte
+	if r.Body != nil {
+		var err error
+		data, err = io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return parseError("Invalid JSON body", err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "" && !IsJSONContentType(contentType) {
+		return parseError("Invalid JSON body", fmt.Errorf("unsupported Content-Type %s", contentType))
+	}
+	return JSON(data, schema, timeFormat, nil)
+}
+
+// JSON validates a raw JSON body against schema, decoding it with decode,
+// e.g. sonic.Unmarshal, or goccy/go-json when nil
+func JSON(data []byte, schema interface{}, timeFormat string, decode func(data []byte, v interface{}) error) error {
+	if schema == nil {
+		return nil
+	}
+	body, err := DecodeJSON(data, decode)
+	if err != nil {
+		return err
+	}
+	return Body(body, schema, timeFormat)
+}
+
+// IsJSONContentType reports whether a content type is JSON, including vendor
+// types such as application/vnd.api+json
+func IsJSONContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// DecodeJSON decodes a JSON body into a JSON-shaped map, with goccy/go-json
+// when decode is nil, failing with an *ErrorResponse
+func DecodeJSON(data []byte, decode func(data []byte, v interface{}) error) (map[string]interface{}, error) {
+	if decode == nil {
+		decode = json.Unmarshal
+	}
+	var body map[string]interface{}
+	if err := decode(data, &body); err != nil {
+		return nil, parseError("Invalid JSON body", err)
+	}
+	return body, nil
+}
+
+// Body validates a decoded body against the schema's struct type. Array
+// schemas aren't validated.
+func Body(body map[string]interface{}, schema interface{}, timeFormat string) error {
+	schemaType := reflect.TypeOf(schema)
+	if schemaType.Kind() == reflect.Ptr {
+		schemaType = schemaType.Elem()
+	}
+
+	// Handle array schemas
+	if schemaType.Kind() == reflect.Slice {
+		// For array schemas, we don't validate structure
+		// Just ensure body can be parsed
+		return nil
+	}
+
+	errors := Struct(body, schemaType, timeFormat)
+	if len(errors) > 0 {
+		return &ErrorResponse{
+			ErrorMessage: "Request body validation failed",
+			Errors:       errors,
+		}
+	}
+
+	return nil
+}
+
+// Struct validates a decoded JSON object against a struct type, returning an
+// error per invalid field in field order
+func Struct(data map[string]interface{}, schemaType reflect.Type, timeFormat string) []FieldError {
+	var errors []FieldError
+
+	// Handle non-struct types
+	if schemaType.Kind() != reflect.Struct {
+		return errors
+	}
+
+	for _, compiled := range Fields(schemaType) {
+		field, jsonName := compiled.StructField, compiled.JSONName
+		value, exists := data[jsonName]
+
+		// Check required fields
+		if compiled.Required && (!exists || value == nil) {
+			errors = append(errors, FieldError{
+				Field:   jsonName,
+				Message: fmt.Sprintf("Required field '%s' is missing", jsonName),
+				Type:    "required",
+				Code:    CodeRequired,
+			})
+			continue
+		}
+
+		// Validate field type if value exists
+		if exists && value != nil {
+			if err := Value(value, field.Type, jsonName, FieldTimeFormat(&field, timeFormat)); err != nil {
+				errors = append(errors, *err)
+			} else if err := EnumValue(value, &field, jsonName); err != nil {
+				errors = append(errors, *err)
+			}
+		}
+	}
+
+	return errors
+}
+
+// Field is a struct field checked by Struct
+type Field struct {
+	StructField reflect.StructField
+	JSONName    string
+	Required    bool // Not nullable and without omitempty
+}
+
+// compiledStruct holds the checked fields of a struct type, compiled once
+type compiledStruct struct {
+	once   sync.Once
+	fields []Field
+}
+
+// compiledStructs caches the compiled struct types validated so far
+var compiledStructs sync.Map // reflect.Type -> *compiledStruct
+
+// Fields returns the exported, JSON-encoded fields of a struct type. The
+// first validations of a type compile it once, the others wait for that
+// instead of compiling it again.
+func Fields(schemaType reflect.Type) []Field {
+	value, _ := compiledStructs.LoadOrStore(schemaType, &compiledStruct{})
+	compiled := value.(*compiledStruct)
+	compiled.once.Do(func() {
+		for i := 0; i < schemaType.NumField(); i++ {
+			field := schemaType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			jsonName := JSONFieldName(&field)
+			if jsonName == "-" {
+				continue
+			}
+			isOmitEmpty := strings.Contains(field.Tag.Get("json"), "omitempty")
+			compiled.fields = append(compiled.fields, Field{
+				StructField: field,
+				JSONName:    jsonName,
+				Required:    !isOmitEmpty && !IsNullable(field.Type),
+			})
+		}
+	})
+	return compiled.fields
+}
+
+// Precompile compiles the fields of a schema type and of the struct types
+// nested in it ahead of the first validation, e.g. while warming up
+func Precompile(t reflect.Type) {
+	precompile(t, make(map[reflect.Type]bool))
+}
+
+// precompile compiles t and its nested struct types not seen yet
+func precompile(t reflect.Type, seen map[reflect.Type]bool) {
+	t = resolveFieldType(t)
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = resolveFieldType(t.Elem())
+	}
+	structType, ok := walkableStruct(t)
+	if !ok || seen[structType] {
+		return
+	}
+	seen[structType] = true
+	for _, field := range Fields(structType) {
+		precompile(field.StructField.Type, seen)
+	}
+}
+
+// IsCompiled reports whether the fields of a struct type have been compiled
+func IsCompiled(t reflect.Type) bool {
+	_, ok := compiledStructs.Load(t)
+	return ok
+}
+
+// Value validates a decoded JSON value against a field type, returning the
+// first error, with nested fields and elements in its path
+func Value(value interface{}, expectedType reflect.Type, fieldName string, timeFormat string) *FieldError {
+	// Handle pointers
+	if expectedType.Kind() == reflect.Ptr {
+		expectedType = expectedType.Elem()
+	}
+
+	actualValue := reflect.ValueOf(value)
+	if !actualValue.IsValid() {
+		return nil // nil value is okay for optional fields
+	}
+
+	if valueType, ok := NullableValueType(expectedType); ok {
+		expectedType = valueType
+	}
+	if expectedType == timeType {
+		return validateTime(value, timeFormat, fieldName)
+	}
+	if validate, ok := stdTypes[expectedType]; ok {
+		return validateStdType(value, validate, fieldName)
+	}
+
+	switch expectedType.Kind() {
+	case reflect.String:
+		if actualValue.Kind() != reflect.String {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be a string", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// JSON unmarshals numbers as float64
+		if actualValue.Kind() != reflect.Float64 {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be a number", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+		// Check if it's an integer value
+		floatVal := actualValue.Float()
+		if floatVal != float64(int64(floatVal)) {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be an integer", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// JSON unmarshals numbers as float64
+		if actualValue.Kind() != reflect.Float64 {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be a number", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+		// Check if it's a non-negative integer value
+		floatVal := actualValue.Float()
+		if floatVal < 0 || floatVal != float64(int64(floatVal)) {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be a non-negative integer", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+	case reflect.Float32, reflect.Float64:
+		if actualValue.Kind() != reflect.Float64 && actualValue.Kind() != reflect.Int && actualValue.Kind() != reflect.Int64 {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be a number", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+	case reflect.Bool:
+		if actualValue.Kind() != reflect.Bool {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be a boolean", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		if actualValue.Kind() != reflect.Slice && actualValue.Kind() != reflect.Array {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be an array", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+		// Validate array elements recursively
+		elemType := expectedType.Elem()
+		sliceValue := reflect.ValueOf(value)
+		for i := 0; i < sliceValue.Len(); i++ {
+			elem := sliceValue.Index(i).Interface()
+			elemFieldName := fmt.Sprintf("%s[%d]", fieldName, i)
+
+			if err := Value(elem, elemType, elemFieldName, timeFormat); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if actualValue.Kind() != reflect.Map {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be an object", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+	case reflect.Struct:
+		// Nested struct should be a map in JSON
+		if actualValue.Kind() != reflect.Map {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be an object", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+		// Validate nested struct recursively
+		nestedMap, ok := value.(map[string]interface{})
+		if !ok {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("Field '%s' must be a valid object", fieldName),
+				Type:    "type_error",
+				Code:    CodeType,
+			}
+		}
+
+		nestedErrors := Struct(nestedMap, expectedType, timeFormat)
+		if len(nestedErrors) > 0 {
+			// Return the first nested error with updated field path
+			firstErr := nestedErrors[0]
+			firstErr.Field = fmt.Sprintf("%s.%s", fieldName, firstErr.Field)
+			return &firstErr
+		}
+	}
+
+	return nil
+}
+
+// EnumValue checks a string value against the field's enum tag or Enum type
+func EnumValue(value interface{}, field *reflect.StructField, fieldName string) *FieldError {
+	values := EnumValues(field)
+	str, ok := value.(string)
+	if len(values) == 0 || !ok {
+		return nil
+	}
+
+	for _, allowed := range values {
+		if str == allowed {
+			return nil
+		}
+	}
+	return &FieldError{
+		Field:   fieldName,
+		Message: fmt.Sprintf("Field '%s' must be one of: %s", fieldName, strings.Join(values, ", ")),
+		Type:    "enum_error",
+		Code:    CodeEnum,
+	}
+}
@@ -0,0 +1,292 @@
+package validation
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testOrder struct {
+	ID       string     `json:"id"`
+	Quantity int        `json:"quantity"`
+	Placed   time.Time  `json:"placed" timeformat:"unix"`
+	Status   testStatus `json:"status"`
+	Note     *string    `json:"note"`
+	Lines    []struct {
+		SKU string `json:"sku"`
+	} `json:"lines,omitempty"`
+}
+
+type testStatus string
+
+func (testStatus) EnumValues() []string { return []string{"open", "shipped"} }
+
+// TestRequest tests validating the JSON body of an *http.Request
+func TestRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		fields      []string // Fields of the expected errors, nil when valid
+	}{
+		{"valid", "application/json", `{"id":"o1","quantity":2,"placed":1700000000,"status":"open"}`, nil},
+		{"missing and mistyped", "application/json", `{"quantity":1.5,"placed":"2024-01-01","status":"open"}`, []string{"id", "quantity", "placed"}},
+		{"enum type", "", `{"id":"o1","quantity":2,"placed":1700000000,"status":"lost"}`, []string{"status"}},
+		{"nested", "application/json", `{"id":"o1","quantity":2,"placed":1,"status":"open","lines":[{"sku":3}]}`, []string{"lines[0].sku"}},
+		{"not JSON", "application/json", `{"id":`, []string{"body"}},
+		{"other media type", "text/plain", `{}`, []string{"body"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			err := Request(req, testOrder{}, "")
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Expected a valid body, got %v", err)
+				}
+			} else {
+				response, ok := err.(*ErrorResponse)
+				if !ok {
+					t.Fatalf("Expected an *ErrorResponse, got %v", err)
+				}
+				var fields []string
+				for _, fieldError := range response.Errors {
+					fields = append(fields, fieldError.Field)
+				}
+				if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+					t.Errorf("Expected errors for %v, got %+v", tt.fields, response.Errors)
+				}
+			}
+
+			// The body is left for the handler
+			var rest bytes.Buffer
+			rest.ReadFrom(req.Body) //nolint:errcheck // in-memory reader
+			if rest.String() != tt.body {
+				t.Errorf("Expected the body to be readable again, got %q", rest.String())
+			}
+		})
+	}
+}
+
+// TestJSONStream tests that streaming validation reports the errors of JSON
+func TestJSONStream(t *testing.T) {
+	body := `{"quantity":"two","placed":1,"status":"lost","lines":[{"sku":"a"},{}]}`
+	decoded := JSON([]byte(body), testOrder{}, "", nil)
+	streamed := JSONStream(strings.NewReader(body), testOrder{}, "")
+	if decoded == nil || streamed == nil {
+		t.Fatalf("Expected both to fail, got %v and %v", decoded, streamed)
+	}
+	if !reflect.DeepEqual(decoded.(*ErrorResponse).Errors, streamed.(*ErrorResponse).Errors) {
+		t.Errorf("Expected the same errors, got %+v and %+v", decoded.(*ErrorResponse).Errors, streamed.(*ErrorResponse).Errors)
+	}
+}
+
+// TestHTTPParameters tests validating the parameters of an *http.Request
+func TestHTTPParameters(t *testing.T) {
+	params := []Parameter{
+		{Name: "id", In: "path", Type: "integer", Required: true},
+		{Name: "limit", In: "query", Type: "integer"},
+		{Name: "X-Dry-Run", In: "header", Type: "boolean"},
+	}
+	validator := NewParameterValidator(params)
+
+	tests := []struct {
+		name   string
+		url    string
+		header string
+		fields []string
+	}{
+		{"valid", "/orders/42?limit=10", "true", nil},
+		{"invalid", "/orders/abc?limit=ten", "maybe", []string{"id", "limit", "X-Dry-Run"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+				err = validator.Validate(HTTPValues(r))
+				if other := Parameters(HTTPValues(r), params); (other == nil) != (err == nil) {
+					t.Errorf("Expected Parameters to agree with the validator, got %v", other)
+				}
+			})
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set("X-Dry-Run", tt.header)
+			mux.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Expected valid parameters, got %v", err)
+				}
+				return
+			}
+			response, ok := err.(*ErrorResponse)
+			if !ok || len(response.Errors) != len(tt.fields) {
+				t.Fatalf("Expected errors for %v, got %v", tt.fields, err)
+			}
+			for i, field := range tt.fields {
+				if response.Errors[i].Field != field {
+					t.Errorf("Expected an error for %s, got %+v", field, response.Errors[i])
+				}
+			}
+		})
+	}
+}
+
+// TestValidateParameterType tests parameter type validation
+func TestValidateParameterType(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		paramType   string
+		expectError bool
+	}{
+		{"String type", "test", "string", false},
+		{"Integer valid", "123", "integer", false},
+		{"Integer invalid", "abc", "integer", true},
+		{"Float valid", "123.45", "number", false},
+		{"Float invalid", "not-a-number", "number", true},
+		{"Boolean valid true", "true", "boolean", false},
+		{"Boolean valid false", "false", "boolean", false},
+		{"Boolean invalid", "maybe", "boolean", true},
+		{"Unknown type", "value", "unknown", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateParameterType(tt.value, tt.paramType)
+
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Errorf("Expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+// TestJSONFieldName tests JSON field name extraction
+func TestJSONFieldName(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldName string
+		jsonTag   string
+		expected  string
+	}{
+		{"With json tag", "UserID", `json:"user_id"`, "user_id"},
+		{"With omitempty", "Email", `json:"email,omitempty"`, "email"},
+		{"Skip field", "Internal", `json:"-"`, "-"},
+		{"No json tag", "UserName", "", "userName"},
+		{"Empty json tag", "Field", ``, "field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			type TestStruct struct {
+				Field string
+			}
+
+			structType := new(TestStruct)
+			field := reflect.TypeOf(structType).Elem().Field(0)
+
+			// Create a mock field with the desired tag
+			mockField := reflect.StructField{
+				Name: tt.fieldName,
+				Type: field.Type,
+				Tag:  reflect.StructTag(tt.jsonTag),
+			}
+
+			result := JSONFieldName(&mockField)
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestFields tests compiling struct types once for concurrent validations
+func TestFields(t *testing.T) {
+	type order struct {
+		ID     string  `json:"id"`
+		Note   *string `json:"note"`
+		Total  int     `json:"total,omitempty"`
+		Secret string  `json:"-"`
+		hidden string
+	}
+	typ := reflect.TypeOf(order{})
+
+	results := make([][]Field, 10)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = Fields(typ)
+		}(i)
+	}
+	wg.Wait()
+
+	fields := results[0]
+	if len(fields) != 3 || fields[0].JSONName != "id" || !fields[0].Required || fields[1].Required || fields[2].Required {
+		t.Fatalf("Expected id (required), note and total, got %+v", fields)
+	}
+	for _, result := range results[1:] {
+		if &result[0] != &fields[0] {
+			t.Fatal("Expected every validation to share the compiled fields")
+		}
+	}
+}
+
+// TestRules tests rules on decoded bodies, without a web framework
+func TestRules(t *testing.T) {
+	rules := []Rule{
+		RestrictField("billing.discount", ScopeGranted("billing:write")),
+		RestrictValue("visibility", "public", Authenticated()),
+	}
+	body := map[string]interface{}{
+		"billing":    map[string]interface{}{"discount": 10.0},
+		"visibility": "public",
+	}
+
+	tests := []struct {
+		name   string
+		ctx    *Context
+		fields []string
+	}{
+		{"anonymous", nil, []string{"billing.discount", "visibility"}},
+		{"authenticated", &Context{Subject: "u1"}, []string{"billing.discount"}},
+		{"billing key", &Context{Subject: "billing-service", Scopes: []string{"billing:write"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Rules(tt.ctx, body, rules)
+			var fields []string
+			if response, ok := err.(*ErrorResponse); ok {
+				for _, fieldError := range response.Errors {
+					if fieldError.Type != "rule_error" {
+						t.Errorf("Expected rule_error, got %s", fieldError.Type)
+					}
+					fields = append(fields, fieldError.Field)
+				}
+			} else if err != nil {
+				t.Fatalf("Expected an *ErrorResponse, got %v", err)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("Expected errors for %v, got %v", tt.fields, err)
+			}
+		})
+	}
+
+	if err := Rules(nil, nil, rules); err != nil {
+		t.Errorf("Expected rules to pass without a body, got %v", err)
+	}
+}
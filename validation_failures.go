@@ -0,0 +1,222 @@
+package notelink
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// ValidationFailureLog logs and counts the requests of documented routes that
+// fail validation (see Config.ValidationFailures), to see which clients send
+// malformed payloads. Counts are served at /api-docs/validation-failures and,
+// for Prometheus, at /api-docs/validation-failures/metrics.
+type ValidationFailureLog struct {
+	// SampleRate is the fraction of failures logged, e.g. 0.01 for one in a
+	// hundred; every failure is logged when 0. Counts include every failure.
+	SampleRate float64
+	// Log replaces the structured warning of sampled failures, e.g. to write
+	// them to a slog.Logger or emit metrics
+	Log func(failure *ValidationFailure)
+}
+
+// ValidationFailure is a request that failed validation
+type ValidationFailure struct {
+	Method    string            `json:"method"`
+	Path      string            `json:"path"`
+	Client    string            `json:"client"` // "sub:<JWT subject>", "key:<API key fingerprint>" or "ip:<address>"
+	Errors    []ValidationError `json:"errors"`
+	RequestID string            `json:"request_id,omitempty"` // See RequestID
+}
+
+// ValidationFailureCount counts the validation errors of a field of a route
+type ValidationFailureCount struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Field  string `json:"field"`
+	Type   string `json:"type,omitempty"`
+	Count  int64  `json:"count"`
+}
+
+// ValidationFailureClient counts the requests of a client failing validation
+type ValidationFailureClient struct {
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	Client   string    `json:"client"`
+	Count    int64     `json:"count"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// maxValidationFailureKeys caps the fields and the clients counted per route;
+// further ones are counted under an empty field or client
+const maxValidationFailureKeys = 1000
+
+// validationFailureStats counts validation failures per route, field and client
+type validationFailureStats struct {
+	mu         sync.Mutex
+	fields     map[ValidationFailureCount]int64 // Keyed without Count
+	clients    map[[3]string]*ValidationFailureClient
+	fieldKeys  map[[2]string]int // Fields counted per method and path
+	clientKeys map[[2]string]int // Clients counted per method and path
+}
+
+// record counts a failed request
+func (s *validationFailureStats) record(failure *ValidationFailure, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fields == nil {
+		s.fields = make(map[ValidationFailureCount]int64)
+		s.clients = make(map[[3]string]*ValidationFailureClient)
+		s.fieldKeys = make(map[[2]string]int)
+		s.clientKeys = make(map[[2]string]int)
+	}
+	route := [2]string{failure.Method, failure.Path}
+	for _, fieldError := range failure.Errors {
+		key := ValidationFailureCount{Method: failure.Method, Path: failure.Path, Field: fieldError.Field, Type: fieldError.Type}
+		if _, ok := s.fields[key]; !ok {
+			if s.fieldKeys[route] >= maxValidationFailureKeys {
+				key.Field, key.Type = "", ""
+			} else {
+				s.fieldKeys[route]++
+			}
+		}
+		s.fields[key]++
+	}
+
+	key := [3]string{failure.Method, failure.Path, failure.Client}
+	client, ok := s.clients[key]
+	if !ok && s.clientKeys[route] >= maxValidationFailureKeys {
+		key[2] = ""
+		client, ok = s.clients[key]
+	}
+	if !ok {
+		// The client may reference the request's buffers, reused after it
+		key[2] = strings.Clone(key[2])
+		client = &ValidationFailureClient{Method: failure.Method, Path: failure.Path, Client: key[2]}
+		s.clients[key] = client
+		s.clientKeys[route]++
+	}
+	client.Count++
+	client.LastSeen = now
+}
+
+// ValidationFailures returns how often each field of each route failed
+// validation, ordered by route and then by the most frequent fields
+func (an *ApiNote) ValidationFailures() []ValidationFailureCount {
+	an.validationFailures.mu.Lock()
+	defer an.validationFailures.mu.Unlock()
+	counts := make([]ValidationFailureCount, 0, len(an.validationFailures.fields))
+	for key, count := range an.validationFailures.fields {
+		key.Count = count
+		counts = append(counts, key)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Path != counts[j].Path {
+			return counts[i].Path < counts[j].Path
+		}
+		if counts[i].Method != counts[j].Method {
+			return counts[i].Method < counts[j].Method
+		}
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if counts[i].Field != counts[j].Field {
+			return counts[i].Field < counts[j].Field
+		}
+		return counts[i].Type < counts[j].Type
+	})
+	return counts
+}
+
+// ValidationFailureClients returns the clients whose requests failed
+// validation, the most frequent first
+func (an *ApiNote) ValidationFailureClients() []ValidationFailureClient {
+	an.validationFailures.mu.Lock()
+	defer an.validationFailures.mu.Unlock()
+	clients := make([]ValidationFailureClient, 0, len(an.validationFailures.clients))
+	for _, client := range an.validationFailures.clients {
+		clients = append(clients, *client)
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if clients[i].Count != clients[j].Count {
+			return clients[i].Count > clients[j].Count
+		}
+		if clients[i].Path != clients[j].Path {
+			return clients[i].Path < clients[j].Path
+		}
+		if clients[i].Method != clients[j].Method {
+			return clients[i].Method < clients[j].Method
+		}
+		return clients[i].Client < clients[j].Client
+	})
+	return clients
+}
+
+// recordValidationFailure counts a request failing validation with a
+// *ValidationErrorResponse and logs a sample of them
+func (an *ApiNote) recordValidationFailure(c fiber.Ctx, endpoint *Endpoint, err error) {
+	var response *ValidationErrorResponse
+	if !errors.As(err, &response) {
+		return
+	}
+	failure := &ValidationFailure{Method: endpoint.Method, Path: endpoint.Path, Client: an.validationClient(c), Errors: response.Errors, RequestID: RequestID(c)}
+	an.validationFailures.record(failure, time.Now())
+
+	config := an.config.ValidationFailures
+	if config.SampleRate > 0 && config.SampleRate < 1 && rand.Float64() >= config.SampleRate {
+		return
+	}
+	if config.Log != nil {
+		config.Log(failure)
+		return
+	}
+	fields := make([]string, len(failure.Errors))
+	for i, fieldError := range failure.Errors {
+		fields[i] = fieldError.Field
+	}
+	log.Warnw("notelink: request failed validation", "method", failure.Method, "path", failure.Path,
+		"client", failure.Client, "fields", strings.Join(fields, ","), "error", response.ErrorMessage, "request_id", failure.RequestID)
+}
+
+// validationClient identifies the client of a request failing validation by
+// its JWT subject, else a fingerprint of its API key, else its IP address
+func (an *ApiNote) validationClient(c fiber.Ctx) string {
+	if subject := an.callerSubject(c); subject != "" {
+		return "sub:" + subject
+	}
+	if key := c.Get(an.apiKeyHeader()); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:6])
+	}
+	return "ip:" + c.IP()
+}
+
+// validationFailuresHandler serves the validation failures by field and client
+func (an *ApiNote) validationFailuresHandler(c fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"fields":  an.ValidationFailures(),
+		"clients": an.ValidationFailureClients(),
+	})
+}
+
+// validationFailuresPrometheus renders the validation failure counts in the
+// Prometheus text format
+func (an *ApiNote) validationFailuresPrometheus() string {
+	var out strings.Builder
+	out.WriteString("# HELP notelink_validation_failures_total Validation errors per route and field\n")
+	out.WriteString("# TYPE notelink_validation_failures_total counter\n")
+	for _, count := range an.ValidationFailures() {
+		fmt.Fprintf(&out, "notelink_validation_failures_total{method=%q,path=%q,field=%q,type=%q} %s\n",
+			count.Method, count.Path, count.Field, count.Type, strconv.FormatInt(count.Count, 10))
+	}
+	return out.String()
+}
@@ -0,0 +1,127 @@
+package notelink
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type failureOrder struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// TestValidationFailures tests counting and sampling validation failures
+func TestValidationFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		sampleRate float64
+		logged     int
+	}{
+		{"every failure", 0, 4},
+		{"sampled", 0.0000001, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged []*ValidationFailure
+			api := NewApiNote(&Config{
+				Title:             "Test API",
+				Host:              "localhost:8080",
+				PublicDiagnostics: true,
+				ValidationFailures: &ValidationFailureLog{
+					SampleRate: tt.sampleRate,
+					Log:        func(failure *ValidationFailure) { logged = append(logged, failure) },
+				},
+			}, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{
+				Method:         "POST",
+				Path:           "/v1/orders",
+				SchemasRequest: failureOrder{},
+				Handler:        func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) },
+			})
+			if err != nil {
+				t.Fatalf("Failed to register route: %v", err)
+			}
+			tokens, err := api.IssueTokens("client-a", nil)
+			if err != nil {
+				t.Fatalf("Failed to issue token: %v", err)
+			}
+
+			requests := []struct {
+				body, auth, apiKey string
+			}{
+				{`{"sku":"a"}`, tokens.AccessToken, ""},
+				{`{"sku":1,"quantity":"2"}`, tokens.AccessToken, ""},
+				{`{"sku":"a","quantity":1}`, tokens.AccessToken, ""},
+				{`{}`, "", "key-b"},
+				{`{"quantity":1}`, "", ""},
+			}
+			for _, r := range requests {
+				req := httptest.NewRequest("POST", "/v1/orders", strings.NewReader(r.body))
+				req.Header.Set("Content-Type", "application/json")
+				if r.auth != "" {
+					req.Header.Set("Authorization", "Bearer "+r.auth)
+				}
+				if r.apiKey != "" {
+					req.Header.Set("X-API-Key", r.apiKey)
+				}
+				resp, err := api.Fiber().Test(req)
+				if err != nil {
+					t.Fatalf("Request failed: %v", err)
+				}
+				resp.Body.Close()
+			}
+
+			if len(logged) != tt.logged {
+				t.Fatalf("Expected %d logged failures, got %d", tt.logged, len(logged))
+			}
+			if tt.logged > 0 && (logged[0].Client != "sub:client-a" || logged[0].Errors[0].Field != "quantity") {
+				t.Errorf("Expected the client and errors of the failure, got %+v", logged[0])
+			}
+
+			counts := map[string]int64{}
+			for _, count := range api.ValidationFailures() {
+				counts[count.Field+"/"+count.Type] = count.Count
+			}
+			if counts["quantity/required"] != 2 || counts["sku/required"] != 2 || counts["sku/type_error"] != 1 || counts["quantity/type_error"] != 1 {
+				t.Errorf("Expected every failure counted by field, got %+v", api.ValidationFailures())
+			}
+			clients := api.ValidationFailureClients()
+			if len(clients) != 3 || clients[0].Client != "sub:client-a" || clients[0].Count != 2 {
+				t.Errorf("Expected failures by client, most frequent first, got %+v", clients)
+			}
+
+			resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/api-docs/validation-failures/metrics", nil))
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			metrics, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if !strings.Contains(string(metrics), `notelink_validation_failures_total{method="POST",path="/v1/orders",field="quantity",type="required"} 2`) {
+				t.Errorf("Expected Prometheus counters, got %s", metrics)
+			}
+		})
+	}
+}
+
+// TestValidationFailuresReportAccess tests keeping the failures by client off
+// the public docs
+func TestValidationFailuresReportAccess(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", ValidationFailures: &ValidationFailureLog{}}, "secret")
+	for _, path := range []string{"/api-docs/validation-failures", "/api-docs/validation-failures/metrics"} {
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != fiber.StatusNotFound {
+			t.Errorf("%s: expected 404 without DocsAuth or PublicDiagnostics, got %d", path, resp.StatusCode)
+		}
+	}
+	if strings.Contains(api.generateLandingHTML(), "/validation-failures") {
+		t.Error("Expected no link to the unserved report")
+	}
+}
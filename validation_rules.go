@@ -0,0 +1,97 @@
+package notelink
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+)
+
+// ValidationRule is a custom or cross-field check of a route's request body,
+// given a ValidationContext with the caller and the route, e.g.
+//
+//	validation.RestrictValue("role", "admin", validation.ClaimIn("role", "admin"))
+//
+// Rules are set with DocumentedRouteInput.ValidationRules and documented on
+// the docs card and as the x-validation-rules extension of the operation.
+type ValidationRule = validation.Rule
+
+// ValidationContext is the request-scoped data ValidationRules get
+type ValidationContext = validation.Context
+
+// NewValidationContext returns the ValidationContext of a request to the
+// endpoint. The caller is identified by the user_id, claims, session and
+// api_key locals of the JWT, session and API key middlewares.
+func NewValidationContext(c fiber.Ctx, endpoint *Endpoint) *ValidationContext {
+	ctx := &ValidationContext{
+		Method: endpoint.Method,
+		Path:   endpoint.RoutePath,
+		Tags:   endpoint.Tags,
+		Values: c,
+		Locals: func(key string) interface{} { return c.Locals(key) },
+	}
+	if subject := c.Locals("user_id"); subject != nil {
+		ctx.Subject = fmt.Sprint(subject)
+	}
+	if claims, ok := c.Locals("claims").(map[string]interface{}); ok {
+		ctx.Claims = claims
+	} else if session, ok := c.Locals("session").(*Session); ok {
+		ctx.Claims = session.Claims
+	}
+	if key, ok := c.Locals("api_key").(*APIKey); ok {
+		ctx.Scopes = key.Scopes
+		if ctx.Subject == "" {
+			ctx.Subject = key.Name
+		}
+	}
+	return ctx
+}
+
+// validateRules checks the endpoint's ValidationRules against the request
+// body, decoded as a JSON object when the request has one
+func validateRules(c fiber.Ctx, endpoint *Endpoint) error {
+	var body map[string]interface{}
+	contentType := c.Get(fiber.HeaderContentType)
+	if len(c.Body()) > 0 && !isNDJSONContentType(contentType) && !isCSVContentType(contentType) {
+		decoded, err := decodeRequestBody(c)
+		if err != nil {
+			return err
+		}
+		body = decoded
+	}
+	return validation.Rules(NewValidationContext(c, endpoint), body, endpoint.ValidationRules)
+}
+
+// ruleDescriptions lists the descriptions of the rules that have one
+func ruleDescriptions(rules []ValidationRule) []string {
+	var descriptions []string
+	for _, rule := range rules {
+		if rule.Description != "" {
+			descriptions = append(descriptions, rule.Description)
+		}
+	}
+	return descriptions
+}
+
+// renderValidationRules renders the validation rules section of an endpoint card
+func renderValidationRules(rules []ValidationRule) string {
+	descriptions := ruleDescriptions(rules)
+	if len(descriptions) == 0 {
+		return ""
+	}
+
+	var html strings.Builder
+	html.WriteString(`
+                    <div class="validation-rules">
+                        <h4>Validation Rules:</h4>
+                        <ul>`)
+	for _, description := range descriptions {
+		html.WriteString(`
+                            <li>` + escapeHTML(description) + `</li>`)
+	}
+	html.WriteString(`
+                        </ul>
+                    </div>`)
+	return html.String()
+}
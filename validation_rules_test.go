@@ -0,0 +1,116 @@
+package notelink
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+)
+
+type ruleUser struct {
+	Name   string `json:"name"`
+	Role   string `json:"role" enum:"member,admin"`
+	Status string `json:"status,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// TestValidationRules tests rules given the JWT caller and the route
+func TestValidationRules(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	api.UseJWT()
+	var route *ValidationContext
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:          "POST",
+		Path:            "/v1/users",
+		Tags:            []string{"users"},
+		SchemasRequest:  ruleUser{},
+		SchemasResponse: ruleUser{},
+		ValidationRules: []ValidationRule{
+			validation.RestrictValue("role", "admin", validation.ClaimIn("role", "admin")),
+			validation.RequiredWhen("reason", "status", "suspended"),
+			{
+				Field:       "name",
+				Description: "Callers can't create a user named after themselves",
+				Check: func(ctx *ValidationContext, body map[string]interface{}) error {
+					route = ctx
+					if body["name"] == ctx.Subject {
+						return errUnexpectedName
+					}
+					return nil
+				},
+			},
+		},
+		Handler: func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		body   string
+		status int
+		fields []string
+	}{
+		{"member sets member", map[string]interface{}{"role": "member"}, `{"name":"Ada","role":"member"}`, 201, nil},
+		{"admin sets admin", map[string]interface{}{"role": "admin"}, `{"name":"Ada","role":"admin"}`, 201, nil},
+		{"admin in a list claim", map[string]interface{}{"role": []string{"billing", "admin"}}, `{"name":"Ada","role":"admin"}`, 201, nil},
+		{"member sets admin", map[string]interface{}{"role": "member"}, `{"name":"Ada","role":"admin"}`, 400, []string{"role"}},
+		{"missing reason", nil, `{"name":"Ada","role":"member","status":"suspended"}`, 400, []string{"reason"}},
+		{"with reason", nil, `{"name":"Ada","role":"member","status":"suspended","reason":"spam"}`, 201, nil},
+		{"custom rule", nil, `{"name":"u1","role":"admin"}`, 400, []string{"role", "name"}},
+		{"invalid body skips rules", nil, `{"name":"u1"}`, 400, []string{"role"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := api.IssueTokens("u1", tt.claims)
+			if err != nil {
+				t.Fatalf("Failed to issue token: %v", err)
+			}
+			req := httptest.NewRequest("POST", "/v1/users", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if tt.fields == nil {
+				return
+			}
+			var body ValidationErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode errors: %v", err)
+			}
+			var fields []string
+			for _, fieldError := range body.Errors {
+				fields = append(fields, fieldError.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("Expected errors for %v, got %+v", tt.fields, body.Errors)
+			}
+		})
+	}
+
+	if route == nil || route.Subject != "u1" || route.Method != "POST" || route.Path != "/v1/users" || route.Tags[0] != "users" {
+		t.Errorf("Expected the caller and route in the context, got %+v", route)
+	}
+
+	operation := api.GenerateOpenAPISpec().Paths["/v1/users"].Post
+	if len(operation.Rules) != 3 || operation.Rules[0] != `Only callers whose role claim is admin may set role to "admin"` {
+		t.Errorf("Expected the rules in x-validation-rules, got %v", operation.Rules)
+	}
+	if html := api.generateHTML(); !strings.Contains(html, "<h4>Validation Rules:</h4>") ||
+		!strings.Contains(html, "reason is required when status is &quot;suspended&quot;") {
+		t.Error("Expected the rules on the docs card")
+	}
+}
+
+var errUnexpectedName = &ValidationErrorResponse{ErrorMessage: "Field 'name' can't be the caller's id"}
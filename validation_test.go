@@ -543,36 +543,68 @@ func TestValidationErrorResponse(t *testing.T) {
 	}
 }
 
-// TestValidateParameterType tests parameter type validation
-func TestValidateParameterType(t *testing.T) {
+// TestJSONDecoder tests validating JSON bodies with a configured decoder
+func TestJSONDecoder(t *testing.T) {
+	decoded := 0
+	decoder := func(data []byte, v interface{}) error {
+		decoded++
+		return json.Unmarshal(data, v)
+	}
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", JSONDecoder: decoder}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/users",
+		Description:    "Create a user",
+		SchemasRequest: TestUser{},
+		Handler:        func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
 	tests := []struct {
 		name        string
-		value       string
-		paramType   string
-		expectError bool
+		contentType string
+		body        string
+		status      int
 	}{
-		{"String type", "test", "string", false},
-		{"Integer valid", "123", "integer", false},
-		{"Integer invalid", "abc", "integer", true},
-		{"Float valid", "123.45", "number", false},
-		{"Float invalid", "not-a-number", "number", true},
-		{"Boolean valid true", "true", "boolean", false},
-		{"Boolean valid false", "false", "boolean", false},
-		{"Boolean invalid", "maybe", "boolean", true},
-		{"Unknown type", "value", "unknown", false},
+		{"valid", "application/json", `{"name":"Ada","email":"ada@example.com","age":36}`, 200},
+		{"vendor type", "application/vnd.api+json; charset=utf-8", `{"name":"Ada","email":"ada@example.com","age":36}`, 200},
+		{"missing field", "application/json", `{"name":"Ada","age":36}`, 400},
+		{"malformed", "application/json", `{"name":`, 400},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := validateParameterType(tt.value, tt.paramType)
-
-			if tt.expectError && err == nil {
-				t.Errorf("Expected error but got none")
+			before := decoded
+			req := httptest.NewRequest("POST", "/users", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
 			}
-
-			if !tt.expectError && err != nil {
-				t.Errorf("Expected no error but got: %v", err)
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+			if decoded != before+1 {
+				t.Errorf("Expected the configured decoder to decode the body once, got %d", decoded-before)
 			}
 		})
 	}
 }
+
+// TestValidateJSON tests validating raw JSON bytes
+func TestValidateJSON(t *testing.T) {
+	if err := ValidateJSON([]byte(`{"name":"Ada","email":"ada@example.com","age":36}`), TestUser{}, nil); err != nil {
+		t.Errorf("Expected a valid body, got %v", err)
+	}
+	err := ValidateJSON([]byte(`{"name":"Ada","age":"old"}`), TestUser{}, json.Unmarshal)
+	verr, ok := err.(*ValidationErrorResponse)
+	if !ok || len(verr.Errors) != 2 {
+		t.Fatalf("Expected a missing email and an age type error, got %v", err)
+	}
+	err = ValidateJSON([]byte(`[`), TestUser{}, nil)
+	if verr, ok := err.(*ValidationErrorResponse); !ok || verr.Errors[0].Type != "parse_error" {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package notelink
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// RouteVariant is an alternative handler of a route, e.g. the canary of an A/B
+// test or a release. Requests carrying Header: Value get the variant; of the
+// others, Percentage percent are routed to it at random and the rest get the
+// route's Handler. Variants are shown on the docs card and exported as the
+// x-variants extension of the operation.
+type RouteVariant struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Header      string        `json:"header,omitempty"`     // Header selecting the variant, e.g. "X-Canary"
+	Value       string        `json:"value,omitempty"`      // Header value selecting the variant; any value when empty
+	Percentage  int           `json:"percentage,omitempty"` // Share of the other requests, 0 to 100
+	Handler     fiber.Handler `json:"-"`
+}
+
+// selectedBy reports whether a request's headers select the variant
+func (v *RouteVariant) selectedBy(c fiber.Ctx) bool {
+	if v.Header == "" {
+		return false
+	}
+	value := c.Get(v.Header)
+	if v.Value == "" {
+		return value != ""
+	}
+	return value == v.Value
+}
+
+// rule describes how requests are routed to the variant
+func (v *RouteVariant) rule() string {
+	var rules []string
+	if v.Header != "" {
+		value := v.Value
+		if value == "" {
+			value = "any value"
+		}
+		rules = append(rules, v.Header+": "+value)
+	}
+	if v.Percentage > 0 {
+		rules = append(rules, strconv.Itoa(v.Percentage)+"% of other requests")
+	}
+	return strings.Join(rules, " or ")
+}
+
+// checkVariants validates a route's variants
+func checkVariants(variants []RouteVariant) error {
+	total := 0
+	names := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		if variant.Name == "" {
+			return fmt.Errorf("variant name is required")
+		}
+		if names[variant.Name] {
+			return fmt.Errorf("duplicate variant %q", variant.Name)
+		}
+		names[variant.Name] = true
+		if variant.Handler == nil {
+			return fmt.Errorf("variant %q: handler is required", variant.Name)
+		}
+		if variant.Percentage < 0 || variant.Percentage > 100 {
+			return fmt.Errorf("variant %q: percentage must be between 0 and 100", variant.Name)
+		}
+		total += variant.Percentage
+	}
+	if total > 100 {
+		return fmt.Errorf("variant percentages add up to %d, more than 100", total)
+	}
+	return nil
+}
+
+// VariantHandler routes requests to the first variant their headers select,
+// then by the variants' percentages, falling back to handler
+func VariantHandler(handler fiber.Handler, variants []RouteVariant) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		for i := range variants {
+			if variants[i].selectedBy(c) {
+				return variants[i].Handler(c)
+			}
+		}
+		roll := rand.IntN(100)
+		for i := range variants {
+			if roll < variants[i].Percentage {
+				return variants[i].Handler(c)
+			}
+			roll -= variants[i].Percentage
+		}
+		return handler(c)
+	}
+}
+
+// renderVariants renders the variants section of an endpoint card
+func renderVariants(variants []RouteVariant) string {
+	if len(variants) == 0 {
+		return ""
+	}
+
+	var html strings.Builder
+	html.WriteString(`
+                    <div class="variants">
+                        <h4>Variants:</h4>
+                        <ul>`)
+	for _, variant := range variants {
+		html.WriteString(`
+                            <li><strong>` + escapeHTML(variant.Name) + `</strong>`)
+		if rule := variant.rule(); rule != "" {
+			html.WriteString(` (` + escapeHTML(rule) + `)`)
+		}
+		if variant.Description != "" {
+			html.WriteString(`: ` + escapeHTML(variant.Description))
+		}
+		html.WriteString(`</li>`)
+	}
+	html.WriteString(`
+                        </ul>
+                    </div>`)
+	return html.String()
+}
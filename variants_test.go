@@ -0,0 +1,96 @@
+package notelink
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestRouteVariants tests routing requests to handler variants and documenting them
+func TestRouteVariants(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	serve := func(name string) fiber.Handler {
+		return func(c fiber.Ctx) error { return c.SendString(name) }
+	}
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:  "GET",
+		Path:    "/v1/search",
+		Handler: serve("stable"),
+		Variants: []RouteVariant{
+			{Name: "canary", Description: "New ranking", Header: "X-Canary", Value: "1", Handler: serve("canary")},
+			{Name: "beta", Header: "X-Beta", Handler: serve("beta")},
+			{Name: "rollout", Percentage: 100, Handler: serve("rollout")},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected string
+	}{
+		{"header value", map[string]string{"X-Canary": "1"}, "canary"},
+		{"any header value", map[string]string{"X-Beta": "yes"}, "beta"},
+		{"other header value", map[string]string{"X-Canary": "0"}, "rollout"},
+		{"percentage", nil, "rollout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/search", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			if body, _ := io.ReadAll(resp.Body); string(body) != tt.expected {
+				t.Errorf("Expected the %s variant, got %q", tt.expected, body)
+			}
+		})
+	}
+
+	data, err := json.Marshal(api.GenerateOpenAPISpec().Paths["/v1/search"].Get)
+	if err != nil {
+		t.Fatalf("Failed to marshal operation: %v", err)
+	}
+	if !strings.Contains(string(data), `"x-variants":[{"name":"canary","description":"New ranking","header":"X-Canary","value":"1"}`) {
+		t.Errorf("Expected the x-variants extension, got %s", data)
+	}
+	html := api.generateHTML()
+	for _, want := range []string{"<strong>canary</strong> (X-Canary: 1): New ranking", "<strong>beta</strong> (X-Beta: any value)", "<strong>rollout</strong> (100% of other requests)"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected the docs to contain %q", want)
+		}
+	}
+}
+
+// TestRouteVariantsValidation tests rejecting invalid variants at registration
+func TestRouteVariantsValidation(t *testing.T) {
+	handler := func(c fiber.Ctx) error { return nil }
+	tests := []struct {
+		name     string
+		variants []RouteVariant
+	}{
+		{"missing name", []RouteVariant{{Handler: handler}}},
+		{"duplicate name", []RouteVariant{{Name: "a", Handler: handler}, {Name: "a", Handler: handler}}},
+		{"missing handler", []RouteVariant{{Name: "a"}}},
+		{"percentages over 100", []RouteVariant{{Name: "a", Percentage: 60, Handler: handler}, {Name: "b", Percentage: 50, Handler: handler}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+			err := api.DocumentedRoute(&DocumentedRouteInput{Method: "GET", Path: "/v1/search", Handler: handler, Variants: tt.variants})
+			if err == nil {
+				t.Error("Expected a registration error")
+			}
+		})
+	}
+}
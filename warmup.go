@@ -0,0 +1,85 @@
+package notelink
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/log"
+)
+
+// DefaultReadinessPath is where readiness is reported with Config.WarmUp
+const DefaultReadinessPath = "/readyz"
+
+// readinessPath returns Config.ReadinessPath, or its default
+func (an *ApiNote) readinessPath() string {
+	if an.config.ReadinessPath == "" {
+		return DefaultReadinessPath
+	}
+	return an.config.ReadinessPath
+}
+
+// mountWarmUp serves the readiness endpoint and warms up in the background
+// once the app listens, after the routes are registered
+func (an *ApiNote) mountWarmUp() {
+	an.app.Get(an.readinessPath(), func(c fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		if !an.Ready() {
+			return c.Status(fiber.StatusServiceUnavailable).SendString("warming up")
+		}
+		return c.SendString("ready")
+	})
+	an.app.Hooks().OnListen(func(fiber.ListenData) error {
+		go func() {
+			if err := an.WarmUp(); err != nil {
+				log.Errorf("notelink: warming up the docs: %v", err)
+			}
+		}()
+		return nil
+	})
+}
+
+// WarmUp renders the docs page, print view, specs and TypeScript types in
+// every locale and compiles the validators of the registered routes, so the
+// first requests after a deploy don't pay for them. Pages and specs are
+// rendered for Config.Host, over http and https. With Config.WarmUp it runs
+// once the app listens and the readiness endpoint reports ready afterwards;
+// call it directly when serving the app otherwise, after registering the routes.
+func (an *ApiNote) WarmUp() error {
+	start := time.Now()
+	for _, endpoint := range an.endpoints {
+		for _, schema := range []interface{}{endpoint.RequestSchema, endpoint.ResponseSchema} {
+			if schema != nil {
+				validation.Precompile(reflect.TypeOf(schema))
+			}
+		}
+	}
+
+	an.cachedTypeScriptTypes()
+	if an.config.Host != "" {
+		for _, scheme := range []string{"http", "https"} {
+			baseURL := scheme + "://" + an.config.Host
+			for _, locale := range an.locales() {
+				an.cachedDocsHTML(false, baseURL, locale)
+				an.cachedDocsHTML(true, baseURL, locale)
+				if _, err := an.cachedOpenAPISpec(baseURL, locale, false); err != nil {
+					return fmt.Errorf("rendering the OpenAPI spec: %w", err)
+				}
+				if _, err := an.cachedOpenAPISpec(baseURL, locale, true); err != nil {
+					return fmt.Errorf("rendering the OpenAPI spec: %w", err)
+				}
+			}
+		}
+	}
+
+	an.ready.Store(true)
+	log.Infof("notelink: docs warmed up in %s", time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// Ready reports whether WarmUp has completed
+func (an *ApiNote) Ready() bool {
+	return an.ready.Load()
+}
@@ -0,0 +1,90 @@
+package notelink
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/canvas-tech-horizon/notelink/validation"
+	"github.com/gofiber/fiber/v3"
+)
+
+type warmUpItem struct {
+	SKU string `json:"sku"`
+}
+
+type warmUpOrder struct {
+	Items []warmUpItem `json:"items"`
+}
+
+// TestWarmUp tests pre-rendering the docs and gating readiness on it
+func TestWarmUp(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", WarmUp: true}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:         "POST",
+		Path:           "/orders",
+		Description:    "Create an order",
+		SchemasRequest: warmUpOrder{},
+		Handler:        func(c fiber.Ctx) error { return c.SendString("ok") },
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+	specs := 0
+	api.OnSpecGenerated(func(spec *OpenAPISpec) { specs++ })
+
+	status := func(path string) int {
+		resp, err := api.Fiber().Test(httptest.NewRequest("GET", path, nil))
+		if err != nil {
+			t.Fatalf("Failed to send test request: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got := status("/readyz"); got != 503 {
+		t.Errorf("Expected 503 before warming up, got %d", got)
+	}
+
+	if err := api.WarmUp(); err != nil {
+		t.Fatalf("Failed to warm up: %v", err)
+	}
+	if got := status("/readyz"); got != 200 {
+		t.Errorf("Expected 200 once warmed up, got %d", got)
+	}
+	// A JSON and YAML spec over http and https
+	if specs != 4 {
+		t.Errorf("Expected 4 specs rendered while warming up, got %d", specs)
+	}
+	status("/api-docs/openapi.json")
+	status("/api-docs/openapi.yaml")
+	if specs != 4 {
+		t.Errorf("Expected the specs served from the warm-up, got %d renders", specs)
+	}
+	if !validation.IsCompiled(reflect.TypeOf(warmUpItem{})) {
+		t.Error("Expected the nested request struct compiled")
+	}
+}
+
+// TestWarmUpOnListen tests warming up once the app listens
+func TestWarmUpOnListen(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080", WarmUp: true, ReadinessPath: "/health/ready"}, "secret")
+	go api.ListenUnix(filepath.Join(t.TempDir(), "notelink.sock")) //nolint:errcheck // stopped by Shutdown
+	defer api.Fiber().Shutdown()                                   //nolint:errcheck // best-effort cleanup
+
+	for i := 0; i < 100 && !api.Ready(); i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !api.Ready() {
+		t.Fatal("Expected the app to be ready after listening")
+	}
+	resp, err := api.Fiber().Test(httptest.NewRequest("GET", "/health/ready", nil))
+	if err != nil {
+		t.Fatalf("Failed to send test request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Errorf("Expected ready at the configured path, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,179 @@
+package notelink
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec // SHA-1 HMAC is still used by several webhook providers
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// WebhookSignatureConfig configures HMAC signature verification for inbound webhook endpoints.
+//
+// The signature is computed over the request body using the configured algorithm
+// and compared in constant time against the value of Header (after stripping Prefix).
+// A body sent with a Content-Encoding is verified decompressed, as providers sign
+// the payload before compressing it.
+type WebhookSignatureConfig struct {
+	// SecretLookup resolves the signing secret per request (e.g., by tenant or provider).
+	// When nil, Secret is used.
+	SecretLookup func(c fiber.Ctx) (string, error)
+	Header       string // Header carrying the signature (default: "X-Signature")
+	Algorithm    string // "sha256" (default), "sha1" or "sha512"
+	Encoding     string // "hex" (default) or "base64"
+	Prefix       string // Optional prefix before the digest, e.g. "sha256="
+	Secret       string // Static signing secret
+}
+
+// headerName returns the configured signature header or the default
+func (w *WebhookSignatureConfig) headerName() string {
+	if w.Header == "" {
+		return "X-Signature"
+	}
+	return w.Header
+}
+
+// algorithm returns the normalized HMAC algorithm name
+func (w *WebhookSignatureConfig) algorithm() string {
+	if w.Algorithm == "" {
+		return "sha256"
+	}
+	return strings.ToLower(w.Algorithm)
+}
+
+// encoding returns the normalized digest encoding
+func (w *WebhookSignatureConfig) encoding() string {
+	if w.Encoding == "" {
+		return "hex"
+	}
+	return strings.ToLower(w.Encoding)
+}
+
+// hashFunc returns the hash constructor for the configured algorithm
+func (w *WebhookSignatureConfig) hashFunc() (func() hash.Hash, error) {
+	switch w.algorithm() {
+	case "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	case "sha1":
+		return sha1.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported webhook signature algorithm: %s", w.Algorithm)
+	}
+}
+
+// Describe returns a human-readable description of the signing scheme,
+// used for the generated endpoint documentation.
+func (w *WebhookSignatureConfig) Describe() string {
+	desc := fmt.Sprintf("HMAC-%s signature of the uncompressed request body, %s-encoded",
+		strings.ToUpper(w.algorithm()), w.encoding())
+	if w.Prefix != "" {
+		desc += fmt.Sprintf(", prefixed with %q", w.Prefix)
+	}
+	return desc
+}
+
+// Sign computes the header value for the given body and secret.
+// It is useful for tests and for senders that share this package.
+func (w *WebhookSignatureConfig) Sign(body []byte, secret string) (string, error) {
+	newHash, err := w.hashFunc()
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	sum := mac.Sum(nil)
+
+	switch w.encoding() {
+	case "hex":
+		return w.Prefix + hex.EncodeToString(sum), nil
+	case "base64":
+		return w.Prefix + base64.StdEncoding.EncodeToString(sum), nil
+	default:
+		return "", fmt.Errorf("unsupported webhook signature encoding: %s", w.Encoding)
+	}
+}
+
+// WebhookSignatureMiddleware returns a Fiber middleware that verifies the HMAC
+// signature of inbound webhook requests. On documented routes it runs after
+// request decompression, over the decompressed body.
+//
+// Requests with a missing or invalid signature are rejected with 401
+// Unauthorized, returned as a *fiber.Error rendered by the app's ErrorHandler.
+//
+// Example usage:
+//
+//	api.Use(notelink.WebhookSignatureMiddleware(notelink.WebhookSignatureConfig{
+//	    Header: "X-Hub-Signature-256",
+//	    Prefix: "sha256=",
+//	    Secret: os.Getenv("WEBHOOK_SECRET"),
+//	}))
+func WebhookSignatureMiddleware(cfg WebhookSignatureConfig) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		received := c.Get(cfg.headerName())
+		if received == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "Webhook signature header required")
+		}
+
+		secret := cfg.Secret
+		if cfg.SecretLookup != nil {
+			var err error
+			secret, err = cfg.SecretLookup(c)
+			if err != nil {
+				return fiber.NewError(fiber.StatusUnauthorized, "Unknown webhook signing secret")
+			}
+		}
+		if secret == "" {
+			return fiber.NewError(fiber.StatusInternalServerError, "Webhook signing secret not configured")
+		}
+
+		expected, err := cfg.Sign(c.Body(), secret)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+
+		if !hmac.Equal([]byte(received), []byte(expected)) {
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid webhook signature")
+		}
+
+		return c.Next()
+	}
+}
+
+// documentWebhookSignature adds the signature header parameter and the 401
+// response to an endpoint so the verification requirements appear in the docs.
+func documentWebhookSignature(endpoint *Endpoint, cfg *WebhookSignatureConfig) {
+	header := cfg.headerName()
+
+	declared := false
+	for _, param := range endpoint.Parameters {
+		if param.In == "header" && strings.EqualFold(param.Name, header) {
+			declared = true
+			break
+		}
+	}
+	if !declared {
+		endpoint.Parameters = append(endpoint.Parameters, Parameter{
+			Name:        header,
+			In:          "header",
+			Type:        "string",
+			Description: cfg.Describe(),
+			Required:    true,
+		})
+	}
+
+	if endpoint.Responses == nil {
+		endpoint.Responses = make(map[string]string)
+	}
+	if _, ok := endpoint.Responses["401"]; !ok {
+		endpoint.Responses["401"] = "Missing or invalid webhook signature"
+	}
+}
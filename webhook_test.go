@@ -0,0 +1,159 @@
+package notelink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// TestWebhookSignatureMiddleware tests HMAC verification of webhook requests
+func TestWebhookSignatureMiddleware(t *testing.T) {
+	cfg := WebhookSignatureConfig{
+		Header: "X-Hub-Signature-256",
+		Prefix: "sha256=",
+		Secret: "webhook-secret",
+	}
+	body := []byte(`{"event":"push"}`)
+	valid, err := cfg.Sign(body, cfg.Secret)
+	if err != nil {
+		t.Fatalf("Failed to sign body: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		signature      string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "Valid signature", signature: valid, expectedStatus: 200},
+		{name: "Missing signature", signature: "", expectedStatus: 401, expectedError: "Webhook signature header required"},
+		{name: "Invalid signature", signature: "sha256=deadbeef", expectedStatus: 401, expectedError: "Invalid webhook signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Rejections are rendered by the app's ErrorHandler
+			app := fiber.New(fiber.Config{ErrorHandler: DefaultErrorHandler})
+			app.Post("/webhook", WebhookSignatureMiddleware(cfg), func(c fiber.Ctx) error {
+				return c.SendString("OK")
+			})
+
+			req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+			if tt.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tt.signature)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, resp.StatusCode)
+			}
+			if tt.expectedError != "" {
+				var envelope ErrorResponse
+				if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil || envelope.Error != tt.expectedError {
+					t.Errorf("Expected the error envelope %q, got %+v (%v)", tt.expectedError, envelope, err)
+				}
+			}
+		})
+	}
+}
+
+// TestWebhookSignatureDocumentation tests that the signature requirements are documented
+func TestWebhookSignatureDocumentation(t *testing.T) {
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:      "POST",
+		Path:        "/webhooks/github",
+		Description: "Receive GitHub events",
+		Responses:   map[string]string{"204": "Accepted"},
+		Handler:     func(c fiber.Ctx) error { return c.SendStatus(204) },
+		WebhookSignature: &WebhookSignatureConfig{
+			Header:    "X-Hub-Signature-256",
+			Algorithm: "sha256",
+			Secret:    "webhook-secret",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	endpoint := api.endpoints["POST /webhooks/github"]
+	if len(endpoint.Parameters) != 1 || endpoint.Parameters[0].Name != "X-Hub-Signature-256" {
+		t.Fatalf("Expected signature header parameter, got %+v", endpoint.Parameters)
+	}
+	if endpoint.Parameters[0].Description != "HMAC-SHA256 signature of the uncompressed request body, hex-encoded" {
+		t.Errorf("Unexpected signature description: %s", endpoint.Parameters[0].Description)
+	}
+	if _, ok := endpoint.Responses["401"]; !ok {
+		t.Error("Expected 401 response to be documented")
+	}
+}
+
+// TestWebhookSignatureCompressed tests verifying the signature of a gzip-encoded
+// webhook over its decompressed body
+func TestWebhookSignatureCompressed(t *testing.T) {
+	cfg := &WebhookSignatureConfig{Secret: "webhook-secret"}
+	decompress := true
+	api := NewApiNote(&Config{Title: "Test API", Host: "localhost:8080"}, "secret")
+	err := api.DocumentedRoute(&DocumentedRouteInput{
+		Method:            "POST",
+		Path:              "/webhooks/events",
+		Handler:           func(c fiber.Ctx) error { return c.Send(c.Body()) },
+		WebhookSignature:  cfg,
+		DecompressRequest: &decompress,
+	})
+	if err != nil {
+		t.Fatalf("Failed to register route: %v", err)
+	}
+
+	body := []byte(`{"event":"push"}`)
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	writer.Write(body) //nolint:errcheck // in-memory buffer
+	writer.Close()
+	signed := func(payload []byte) string {
+		signature, err := cfg.Sign(payload, cfg.Secret)
+		if err != nil {
+			t.Fatalf("Failed to sign body: %v", err)
+		}
+		return signature
+	}
+
+	tests := []struct {
+		name      string
+		signature string
+		status    int
+	}{
+		{name: "signed uncompressed", signature: signed(body), status: 200},
+		{name: "signed compressed", signature: signed(compressed.Bytes()), status: 401},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/webhooks/events", bytes.NewReader(compressed.Bytes()))
+			req.Header.Set("Content-Encoding", "gzip")
+			req.Header.Set("X-Signature", tt.signature)
+			resp, err := api.Fiber().Test(req)
+			if err != nil {
+				t.Fatalf("Failed to send test request: %v", err)
+			}
+			defer resp.Body.Close()
+			received, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, resp.StatusCode, received)
+			}
+			if tt.status == 200 && !bytes.Equal(received, body) {
+				t.Errorf("Expected the handler to read the decompressed body, got %q", received)
+			}
+		})
+	}
+}